@@ -15,27 +15,46 @@ import (
 	"github.com/biezax/wg-portal/internal/adapters"
 	"github.com/biezax/wg-portal/internal/app"
 	"github.com/biezax/wg-portal/internal/app/api/core"
+	"github.com/biezax/wg-portal/internal/app/api/rpc"
 	backendV0 "github.com/biezax/wg-portal/internal/app/api/v0/backend"
 	handlersV0 "github.com/biezax/wg-portal/internal/app/api/v0/handlers"
 	backendV1 "github.com/biezax/wg-portal/internal/app/api/v1/backend"
 	handlersV1 "github.com/biezax/wg-portal/internal/app/api/v1/handlers"
 	"github.com/biezax/wg-portal/internal/app/audit"
 	"github.com/biezax/wg-portal/internal/app/auth"
+	"github.com/biezax/wg-portal/internal/app/backup"
+	"github.com/biezax/wg-portal/internal/app/bulk"
+	"github.com/biezax/wg-portal/internal/app/configcheck"
 	"github.com/biezax/wg-portal/internal/app/configfile"
+	"github.com/biezax/wg-portal/internal/app/eventstream"
+	"github.com/biezax/wg-portal/internal/app/exporters"
 	"github.com/biezax/wg-portal/internal/app/mail"
+	"github.com/biezax/wg-portal/internal/app/migration"
+	"github.com/biezax/wg-portal/internal/app/provisioning"
+	"github.com/biezax/wg-portal/internal/app/reload"
 	"github.com/biezax/wg-portal/internal/app/route"
 	"github.com/biezax/wg-portal/internal/app/users"
 	"github.com/biezax/wg-portal/internal/app/webhooks"
 	"github.com/biezax/wg-portal/internal/app/wireguard"
 	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
 )
 
 // main entry point for WireGuard Portal
 func main() {
-	ctx := internal.SignalAwareContext(context.Background(), syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	// SIGHUP reloads the configuration, see reloadManager
+	ctx := internal.SignalAwareContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 
 	slog.Info("Starting WireGuard Portal V2...", "version", internal.Version)
 
+	if app.IsConfigCommand(os.Args[1:]) {
+		if err := app.RunConfigCommand(ctx, os.Stdout, configcheck.NewChecker(), os.Args[2:]); err != nil {
+			slog.Error("Config command failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	cfg, err := config.GetConfig()
 	internal.AssertNoError(err)
 	internal.SetupLogging(cfg.Advanced.LogLevel, cfg.Advanced.LogPretty, cfg.Advanced.LogJson)
@@ -60,7 +79,7 @@ func main() {
 	cfgFileSystem, err := adapters.NewFileSystemRepository(cfg.Advanced.ConfigStoragePath)
 	internal.AssertNoError(err)
 
-	shouldExit, err := app.HandleProgramArgs(rawDb)
+	shouldExit, programArgs, err := app.HandleProgramArgs(rawDb)
 	switch {
 	case shouldExit && err == nil:
 		return
@@ -72,50 +91,153 @@ func main() {
 	}
 
 	queueSize := 100
-	eventBus := evbus.New(queueSize)
+	eventBus := app.NewTrackingEventBus(evbus.New(queueSize))
 
-	auditManager := audit.NewManager(database)
+	auditManager := audit.NewManager(cfg, database)
 
 	auditRecorder, err := audit.NewAuditRecorder(cfg, eventBus, database)
 	internal.AssertNoError(err)
-	auditRecorder.StartBackgroundJobs(ctx)
 
-	userManager, err := users.NewUserManager(cfg, eventBus, database, database)
+	admissionController := webhooks.NewAdmissionController(cfg)
+
+	userManager, err := users.NewUserManager(cfg, eventBus, database, database, admissionController)
 	internal.AssertNoError(err)
-	userManager.StartBackgroundJobs(ctx)
 
 	authenticator, err := auth.NewAuthenticator(&cfg.Auth, cfg.Web.ExternalUrl, eventBus, userManager)
 	internal.AssertNoError(err)
-	authenticator.StartBackgroundJobs(ctx)
+
+	jwtAuthenticator := auth.NewJwtAuthenticator(&cfg.Auth, userManager)
 
 	webAuthn, err := auth.NewWebAuthnAuthenticator(cfg, eventBus, userManager)
 	internal.AssertNoError(err)
 
-	wireGuardManager, err := wireguard.NewWireGuardManager(cfg, eventBus, wireGuard, database)
+	wireGuardManager, err := wireguard.NewWireGuardManager(cfg, eventBus, wireGuard, database, admissionController)
 	internal.AssertNoError(err)
-	wireGuardManager.StartBackgroundJobs(ctx)
 
 	statisticsCollector, err := wireguard.NewStatisticsCollector(cfg, eventBus, database, wireGuard, metricsServer)
 	internal.AssertNoError(err)
-	statisticsCollector.StartBackgroundJobs(ctx)
 
 	cfgFileManager, err := configfile.NewConfigFileManager(cfg, eventBus, database, database, cfgFileSystem)
 	internal.AssertNoError(err)
 
-	mailManager, err := mail.NewMailManager(cfg, mailer, cfgFileManager, database, database)
+	mailManager, err := mail.NewMailManager(cfg, eventBus, mailer, cfgFileManager, database, database)
 	internal.AssertNoError(err)
 
 	routeManager, err := route.NewRouteManager(cfg, eventBus, database, wireGuard)
 	internal.AssertNoError(err)
-	routeManager.StartBackgroundJobs(ctx)
 
-	webhookManager, err := webhooks.NewManager(cfg, eventBus)
+	webhookManager, err := webhooks.NewManager(cfg, eventBus, database)
+	internal.AssertNoError(err)
+
+	eventStreamManager, err := eventstream.NewManager(eventBus)
 	internal.AssertNoError(err)
+
+	exporterManager, err := exporters.NewManager(cfg, eventBus)
+	internal.AssertNoError(err)
+
+	migrationManager := migration.NewManager(cfg, userManager, wireGuardManager)
+
+	bulkManager := bulk.NewManager(cfg, userManager, wireGuardManager)
+
+	backupManager := backup.NewManager(cfg, database, wireGuardManager, auditRecorder)
+
+	provisioningManager := provisioning.NewManager(cfg, userManager, wireGuardManager)
+
+	// one-shot commands run before any background job is started, their follow-up work is done before exiting
+	if programArgs.Import != nil {
+		err := app.RunImport(ctx, os.Stdout, migrationManager, programArgs.Import)
+		drainEventBus(ctx, eventBus)
+		if err != nil {
+			slog.Error("Failed to import data", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if programArgs.Backup != nil {
+		err := app.RunBackup(ctx, os.Stdin, os.Stdout, backupManager, programArgs.Backup)
+		drainEventBus(ctx, eventBus)
+		if err != nil {
+			slog.Error("Failed to run backup command", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if programArgs.Admin != nil {
+		err := app.RunAdminCommand(ctx, os.Stdout, app.AdminManagers{
+			Users:       userManager,
+			WireGuard:   wireGuardManager,
+			ConfigFiles: cfgFileManager,
+		}, programArgs.Admin)
+		drainEventBus(ctx, eventBus)
+		if err != nil {
+			slog.Error("Failed to run command", "command", programArgs.Admin.Resource+" "+programArgs.Admin.Action,
+				"error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if programArgs.Bulk != nil {
+		err := app.RunBulk(ctx, os.Stdin, os.Stdout, bulkManager, programArgs.Bulk)
+		drainEventBus(ctx, eventBus)
+		if err != nil {
+			slog.Error("Failed to run bulk operation", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if programArgs.Audit != nil {
+		if err := app.RunAuditCommand(ctx, os.Stdout, auditManager, programArgs.Audit); err != nil {
+			slog.Error("Failed to verify audit log", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if programArgs.Provision != nil {
+		err := app.RunProvisioning(ctx, os.Stdout, provisioningManager, programArgs.Provision)
+		drainEventBus(ctx, eventBus)
+		if err != nil {
+			slog.Error("Failed to run provisioning", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	auditRecorder.StartBackgroundJobs(ctx)
+	userManager.StartBackgroundJobs(ctx)
+	authenticator.StartBackgroundJobs(ctx)
+	wireGuardManager.StartBackgroundJobs(ctx)
+	statisticsCollector.StartBackgroundJobs(ctx)
+	routeManager.StartBackgroundJobs(ctx)
 	webhookManager.StartBackgroundJobs(ctx)
+	exporterManager.StartBackgroundJobs(ctx)
 
 	err = app.Initialize(cfg, wireGuardManager, userManager)
 	internal.AssertNoError(err)
 
+	_, err = provisioningManager.Apply(domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo()))
+	internal.AssertNoError(err)
+
+	reloadManager := reload.NewManager(cfg, eventBus, config.GetConfig)
+	reloadManager.Register("logging", reload.ReloadFunc(func(_ context.Context, cfg *config.Config) error {
+		internal.SetupLogging(cfg.Advanced.LogLevel, cfg.Advanced.LogPretty, cfg.Advanced.LogJson)
+		return nil
+	}), "advanced.log_level", "advanced.log_pretty", "advanced.log_json")
+	reloadManager.Register("authentication", authenticator, "auth.oidc", "auth.oauth", "auth.ldap")
+	reloadManager.Register("jwt authentication", jwtAuthenticator, "auth.oidc")
+	reloadManager.Register("ldap synchronization", userManager, "auth.ldap")
+	reloadManager.Register("backends", wireGuard, "backend.mikrotik", "backend.pfsense")
+	reloadManager.Register("webhook", webhookManager, "webhook")
+	reloadManager.Register("admission webhooks", admissionController, "webhook.admission")
+	reloadManager.Register("mail server", mailer, "mail.host", "mail.port", "mail.encryption",
+		"mail.cert_validation", "mail.username", "mail.password", "mail.password_file",
+		"mail.auth_type", "mail.from")
+	reloadManager.Register("mail", mailManager, "mail.allow_peer_email")
+	reloadManager.Register("provisioning", provisioningManager, "provisioning.users", "provisioning.peers",
+		"provisioning.prune")
+	reloadManager.Register("statistics", statisticsCollector,
+		"statistics.data_collection_interval", "statistics.ping_check_interval")
+	reloadManager.StartBackgroundJobs(ctx)
+
 	validatorManager := validator.New()
 
 	// region API v0 (SPA frontend)
@@ -150,12 +272,20 @@ func main() {
 
 	// region API v1 (User REST API)
 
-	apiV1Auth := handlersV1.NewAuthenticationHandler(userManager)
+	apiV1Auth := handlersV1.NewAuthenticationHandler(userManager, jwtAuthenticator, cfg.Web.TrustedProxies)
 	apiV1BackendUsers := backendV1.NewUserService(cfg, userManager)
 	apiV1BackendPeers := backendV1.NewPeerService(cfg, wireGuardManager, userManager)
 	apiV1BackendInterfaces := backendV1.NewInterfaceService(cfg, wireGuardManager)
 	apiV1BackendProvisioning := backendV1.NewProvisioningService(cfg, userManager, wireGuardManager, cfgFileManager)
 	apiV1BackendMetrics := backendV1.NewMetricsService(cfg, database, userManager, wireGuardManager)
+	apiV1BackendMigration := backendV1.NewMigrationService(cfg, migrationManager)
+	apiV1BackendBulk := backendV1.NewBulkService(cfg, bulkManager)
+	apiV1BackendBackup := backendV1.NewBackupService(cfg, backupManager)
+	apiV1BackendConfig := backendV1.NewConfigService(cfg, reloadManager)
+	apiV1BackendApiTokens := backendV1.NewApiTokenService(cfg, userManager)
+	apiV1BackendEvents := backendV1.NewEventService(cfg, eventStreamManager)
+	apiV1BackendWebhooks := backendV1.NewWebhookService(cfg, webhookManager)
+	apiV1BackendAudit := backendV1.NewAuditService(cfg, auditManager)
 
 	apiV1EndpointUsers := handlersV1.NewUserEndpoint(apiV1Auth, validatorManager, apiV1BackendUsers)
 	apiV1EndpointPeers := handlersV1.NewPeerEndpoint(apiV1Auth, validatorManager, apiV1BackendPeers)
@@ -163,6 +293,14 @@ func main() {
 	apiV1EndpointProvisioning := handlersV1.NewProvisioningEndpoint(apiV1Auth, validatorManager,
 		apiV1BackendProvisioning)
 	apiV1EndpointMetrics := handlersV1.NewMetricsEndpoint(apiV1Auth, validatorManager, apiV1BackendMetrics)
+	apiV1EndpointMigration := handlersV1.NewMigrationEndpoint(apiV1Auth, validatorManager, apiV1BackendMigration)
+	apiV1EndpointBulk := handlersV1.NewBulkEndpoint(apiV1Auth, validatorManager, apiV1BackendBulk)
+	apiV1EndpointBackup := handlersV1.NewBackupEndpoint(apiV1Auth, validatorManager, apiV1BackendBackup)
+	apiV1EndpointConfig := handlersV1.NewConfigEndpoint(apiV1Auth, validatorManager, apiV1BackendConfig)
+	apiV1EndpointApiTokens := handlersV1.NewApiTokenEndpoint(apiV1Auth, validatorManager, apiV1BackendApiTokens)
+	apiV1EndpointEvents := handlersV1.NewEventEndpoint(apiV1Auth, validatorManager, apiV1BackendEvents)
+	apiV1EndpointWebhooks := handlersV1.NewWebhookEndpoint(apiV1Auth, validatorManager, apiV1BackendWebhooks)
+	apiV1EndpointAudit := handlersV1.NewAuditEndpoint(apiV1Auth, validatorManager, apiV1BackendAudit)
 
 	apiV1 := handlersV1.NewRestApi(
 		apiV1EndpointUsers,
@@ -170,10 +308,35 @@ func main() {
 		apiV1EndpointInterfaces,
 		apiV1EndpointProvisioning,
 		apiV1EndpointMetrics,
+		apiV1EndpointMigration,
+		apiV1EndpointBulk,
+		apiV1EndpointBackup,
+		apiV1EndpointConfig,
+		apiV1EndpointApiTokens,
+		apiV1EndpointEvents,
+		apiV1EndpointWebhooks,
+		apiV1EndpointAudit,
 	)
 
 	// endregion API v1 (User REST API)
 
+	// region gRPC API
+
+	if cfg.Web.GrpcListeningAddress != "" {
+		grpcSrv, err := rpc.NewServer(cfg, apiV1Auth,
+			rpc.NewUserServer(validatorManager, apiV1BackendUsers),
+			rpc.NewPeerServer(validatorManager, apiV1BackendPeers, apiV1BackendEvents),
+			rpc.NewInterfaceServer(validatorManager, apiV1BackendInterfaces),
+			rpc.NewProvisioningServer(validatorManager, apiV1BackendProvisioning),
+			rpc.NewMetricsServer(apiV1BackendMetrics),
+		)
+		internal.AssertNoError(err)
+
+		go grpcSrv.Run(ctx, cfg.Web.GrpcListeningAddress)
+	}
+
+	// endregion gRPC API
+
 	webSrv, err := core.NewServer(cfg, apiFrontend, apiV1)
 	internal.AssertNoError(err)
 
@@ -191,3 +354,14 @@ func main() {
 
 	slog.Info("Stopped WireGuard Portal")
 }
+
+// drainEventBus waits until the events published by a one-shot command have been handled, for example the audit
+// entries of the changes or the peers that have to be disabled for a disabled user.
+func drainEventBus(ctx context.Context, bus *app.TrackingEventBus) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := bus.Wait(ctx); err != nil {
+		slog.Warn("Not all events have been handled before exiting", "error", err)
+	}
+}
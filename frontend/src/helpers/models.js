@@ -25,6 +25,7 @@ export function freshInterface() {
     PostDown: "",
 
     SaveConfig: false,
+    PeerExtensionDays: 0,
 
     // Peer defaults
 
@@ -225,11 +225,16 @@ func (r *SqlRepo) migrate() error {
 	slog.Debug("running migration: user", "result", r.db.AutoMigrate(&domain.User{}))
 	slog.Debug("running migration: user webauthn credentials", "result",
 		r.db.AutoMigrate(&domain.UserWebauthnCredential{}))
+	slog.Debug("running migration: user api tokens", "result", r.db.AutoMigrate(&domain.ApiToken{}))
 	slog.Debug("running migration: interface", "result", r.db.AutoMigrate(&domain.Interface{}))
 	slog.Debug("running migration: peer", "result", r.db.AutoMigrate(&domain.Peer{}))
 	slog.Debug("running migration: peer status", "result", r.db.AutoMigrate(&domain.PeerStatus{}))
 	slog.Debug("running migration: interface status", "result", r.db.AutoMigrate(&domain.InterfaceStatus{}))
 	slog.Debug("running migration: audit data", "result", r.db.AutoMigrate(&domain.AuditEntry{}))
+	slog.Debug("running migration: audit checkpoints", "result", r.db.AutoMigrate(&domain.AuditCheckpoint{}))
+	slog.Debug("running migration: webhook deliveries", "result", r.db.AutoMigrate(&domain.WebhookDelivery{}))
+	slog.Debug("running migration: webhook subscriptions", "result",
+		r.db.AutoMigrate(&domain.WebhookSubscription{}))
 
 	existingSysStat := SysStat{}
 	r.db.Where("schema_version = ?", SchemaVersion).First(&existingSysStat)
@@ -247,6 +252,105 @@ func (r *SqlRepo) migrate() error {
 	return nil
 }
 
+// region list queries
+
+// Database columns of the sortable fields of list queries, see domain.ListOptions.
+var (
+	peerSortColumns = map[string]string{
+		"Identifier":          "identifier",
+		"DisplayName":         "display_name",
+		"UserIdentifier":      "user_identifier",
+		"InterfaceIdentifier": "interface_identifier",
+		"CreatedAt":           "created_at",
+		"UpdatedAt":           "updated_at",
+		"ExpiresAt":           "expires_at",
+		"Disabled":            "disabled",
+	}
+	userSortColumns = map[string]string{
+		"Identifier": "identifier",
+		"Email":      "email",
+		"Firstname":  "firstname",
+		"Lastname":   "lastname",
+		"CreatedAt":  "created_at",
+		"UpdatedAt":  "updated_at",
+		"Disabled":   "disabled",
+	}
+	interfaceSortColumns = map[string]string{
+		"Identifier":  "identifier",
+		"DisplayName": "display_name",
+		"Mode":        "type",
+		"CreatedAt":   "created_at",
+		"UpdatedAt":   "updated_at",
+		"Disabled":    "disabled",
+	}
+	webhookDeliverySortColumns = map[string]string{
+		"Identifier": "identifier",
+		"CreatedAt":  "created_at",
+		"UpdatedAt":  "updated_at",
+		"Status":     "status",
+		"Attempts":   "attempts",
+	}
+)
+
+// applyListOptions adds the sort order and pagination to a query. The identifier is always used as last sort
+// column, so that pages are stable.
+func applyListOptions(tx *gorm.DB, opts domain.ListOptions, sortColumns map[string]string) *gorm.DB {
+	if column, ok := sortColumns[opts.SortBy]; ok && column != "identifier" {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: opts.SortDesc})
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: "identifier"}})
+	} else {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: "identifier"}, Desc: opts.SortDesc})
+	}
+	if opts.Offset > 0 {
+		tx = tx.Offset(opts.Offset)
+	}
+	if opts.Limit > 0 {
+		tx = tx.Limit(opts.Limit)
+	}
+	return tx
+}
+
+// filterDisabled restricts a query to disabled or enabled records, if disabled is set.
+func filterDisabled(tx *gorm.DB, disabled *bool) *gorm.DB {
+	switch {
+	case disabled == nil:
+		return tx
+	case *disabled:
+		return tx.Where("disabled IS NOT NULL")
+	default:
+		return tx.Where("disabled IS NULL")
+	}
+}
+
+// endregion list queries
+
+// region preconditions
+
+// checkIfMatchPrecondition verifies the If-Match precondition of the request for the record with the given identifier.
+// It must be called inside the transaction that changes the record. On databases that support it, the record is
+// locked until the transaction ends, so that no concurrent change slips in between the check and the update.
+func (r *SqlRepo) checkIfMatchPrecondition(ctx context.Context, tx *gorm.DB, model, id any) error {
+	if !domain.HasIfMatchPrecondition(ctx, id) {
+		return nil
+	}
+
+	query := tx.Model(model).Select("created_at", "updated_at").Where("identifier = ?", id).Limit(1)
+	switch tx.Dialector.Name() {
+	case "mysql", "postgres":
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var current domain.BaseModel
+	result := query.Scan(&current)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return domain.CheckIfMatchPrecondition(ctx, id, result.RowsAffected > 0, current)
+}
+
+// endregion preconditions
+
 // region interfaces
 
 // GetInterface returns the interface with the given id.
@@ -366,6 +470,10 @@ func (r *SqlRepo) SaveInterface(
 ) error {
 	userInfo := domain.GetUserInfo(ctx)
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.checkIfMatchPrecondition(ctx, tx, &domain.Interface{}, id); err != nil {
+			return err
+		}
+
 		in, err := r.getOrCreateInterface(userInfo, tx, id)
 		if err != nil {
 			return err // return any error will roll back
@@ -437,6 +545,10 @@ func (r *SqlRepo) upsertInterface(ui *domain.ContextUserInfo, tx *gorm.DB, in *d
 // DeleteInterface deletes the interface with the given id.
 func (r *SqlRepo) DeleteInterface(ctx context.Context, id domain.InterfaceIdentifier) error {
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.checkIfMatchPrecondition(ctx, tx, &domain.Interface{}, id); err != nil {
+			return err
+		}
+
 		err := tx.Where("interface_identifier = ?", id).Delete(&domain.Peer{}).Error
 		if err != nil {
 			return err
@@ -483,6 +595,64 @@ func (r *SqlRepo) GetInterfaceIps(ctx context.Context) (map[domain.InterfaceIden
 	return result, nil
 }
 
+// QueryInterfaces returns one page of the interfaces that match the given filter and the total number of matching
+// interfaces.
+func (r *SqlRepo) QueryInterfaces(
+	ctx context.Context,
+	filter domain.InterfaceFilter,
+	opts domain.ListOptions,
+) ([]domain.Interface, int, error) {
+	query := func() *gorm.DB {
+		tx := r.db.WithContext(ctx).Model(&domain.Interface{})
+		tx = filterDisabled(tx, filter.Disabled)
+		if filter.CreatedAfter != nil {
+			tx = tx.Where("created_at > ?", *filter.CreatedAfter)
+		}
+		return tx
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var interfaces []domain.Interface
+	err := applyListOptions(query(), opts, interfaceSortColumns).Preload("Addresses").Find(&interfaces).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return interfaces, int(total), nil
+}
+
+// GetInterfacePeerCounts returns the number of all and enabled peers per interface.
+func (r *SqlRepo) GetInterfacePeerCounts(ctx context.Context) (
+	map[domain.InterfaceIdentifier]domain.PeerCounts,
+	error,
+) {
+	var rows []struct {
+		InterfaceIdentifier domain.InterfaceIdentifier
+		Total               int
+		Enabled             int
+	}
+
+	err := r.db.WithContext(ctx).Model(&domain.Peer{}).
+		Select("interface_identifier, COUNT(*) AS total, " +
+			"SUM(CASE WHEN disabled IS NULL THEN 1 ELSE 0 END) AS enabled").
+		Group("interface_identifier").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.InterfaceIdentifier]domain.PeerCounts, len(rows))
+	for _, row := range rows {
+		counts[row.InterfaceIdentifier] = domain.PeerCounts{Total: row.Total, Enabled: row.Enabled}
+	}
+
+	return counts, nil
+}
+
 // endregion interfaces
 
 // region peers
@@ -576,6 +746,10 @@ func (r *SqlRepo) SavePeer(
 ) error {
 	userInfo := domain.GetUserInfo(ctx)
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.checkIfMatchPrecondition(ctx, tx, &domain.Peer{}, id); err != nil {
+			return err
+		}
+
 		peer, err := r.getOrCreatePeer(userInfo, tx, id)
 		if err != nil {
 			return err // return any error will roll back
@@ -646,6 +820,10 @@ func (r *SqlRepo) upsertPeer(ui *domain.ContextUserInfo, tx *gorm.DB, peer *doma
 // DeletePeer deletes the peer with the given id.
 func (r *SqlRepo) DeletePeer(ctx context.Context, id domain.PeerIdentifier) error {
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.checkIfMatchPrecondition(ctx, tx, &domain.Peer{}, id); err != nil {
+			return err
+		}
+
 		err := tx.Delete(&domain.PeerStatus{PeerId: id}).Error
 		if err != nil {
 			return err
@@ -742,6 +920,57 @@ func (r *SqlRepo) GetUsedIpsPerSubnet(ctx context.Context, subnets []domain.Cidr
 	return result, nil
 }
 
+// QueryPeers returns one page of the peers that match the given filter and the total number of matching peers.
+func (r *SqlRepo) QueryPeers(
+	ctx context.Context,
+	filter domain.PeerFilter,
+	opts domain.ListOptions,
+) ([]domain.Peer, int, error) {
+	query := func() *gorm.DB {
+		tx := r.db.WithContext(ctx).Model(&domain.Peer{})
+		if filter.InterfaceIdentifier != "" {
+			tx = tx.Where("interface_identifier = ?", filter.InterfaceIdentifier)
+		}
+		if filter.UserIdentifier != "" {
+			tx = tx.Where("user_identifier = ?", filter.UserIdentifier)
+		}
+		tx = filterDisabled(tx, filter.Disabled)
+		if filter.Expired != nil {
+			now := time.Now()
+			if *filter.Expired {
+				tx = tx.Where("expires_at IS NOT NULL AND expires_at < ?", now)
+			} else {
+				tx = tx.Where("expires_at IS NULL OR expires_at >= ?", now)
+			}
+		}
+		if filter.Connected != nil {
+			connected := r.db.Model(&domain.PeerStatus{}).Select("identifier").Where("connected = ?", true)
+			if *filter.Connected {
+				tx = tx.Where("identifier IN (?)", connected)
+			} else {
+				tx = tx.Where("identifier NOT IN (?)", connected)
+			}
+		}
+		if filter.CreatedAfter != nil {
+			tx = tx.Where("created_at > ?", *filter.CreatedAfter)
+		}
+		return tx
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var peers []domain.Peer
+	err := applyListOptions(query(), opts, peerSortColumns).Preload("Addresses").Find(&peers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return peers, int(total), nil
+}
+
 // endregion peers
 
 // region users
@@ -847,6 +1076,10 @@ func (r *SqlRepo) SaveUser(
 	userInfo := domain.GetUserInfo(ctx)
 
 	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.checkIfMatchPrecondition(ctx, tx, &domain.User{}, id); err != nil {
+			return err
+		}
+
 		user, err := r.getOrCreateUser(userInfo, tx, id)
 		if err != nil {
 			return err // return any error will roll back
@@ -874,7 +1107,17 @@ func (r *SqlRepo) SaveUser(
 
 // DeleteUser deletes the user with the given id.
 func (r *SqlRepo) DeleteUser(ctx context.Context, id domain.UserIdentifier) error {
-	err := r.db.WithContext(ctx).Unscoped().Select(clause.Associations).Delete(&domain.User{Identifier: id}).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.checkIfMatchPrecondition(ctx, tx, &domain.User{}, id); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_identifier = ?", id).Delete(&domain.ApiToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Select(clause.Associations).Delete(&domain.User{Identifier: id}).Error
+	})
 	if err != nil {
 		return err
 	}
@@ -926,6 +1169,150 @@ func (r *SqlRepo) upsertUser(ui *domain.ContextUserInfo, tx *gorm.DB, user *doma
 	return nil
 }
 
+// QueryUsers returns one page of the users that match the given filter and the total number of matching users.
+func (r *SqlRepo) QueryUsers(
+	ctx context.Context,
+	filter domain.UserFilter,
+	opts domain.ListOptions,
+) ([]domain.User, int, error) {
+	query := func() *gorm.DB {
+		tx := r.db.WithContext(ctx).Model(&domain.User{})
+		tx = filterDisabled(tx, filter.Disabled)
+		if filter.Admin != nil {
+			tx = tx.Where("is_admin = ?", *filter.Admin)
+		}
+		if filter.CreatedAfter != nil {
+			tx = tx.Where("created_at > ?", *filter.CreatedAfter)
+		}
+		return tx
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	err := applyListOptions(query(), opts, userSortColumns).Preload("WebAuthnCredentialList").Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, int(total), nil
+}
+
+// GetUserPeerCounts returns the number of peers of the given users. Users without peers are not part of the result.
+func (r *SqlRepo) GetUserPeerCounts(ctx context.Context, ids ...domain.UserIdentifier) (
+	map[domain.UserIdentifier]int,
+	error,
+) {
+	var rows []struct {
+		UserIdentifier domain.UserIdentifier
+		Total          int
+	}
+
+	err := r.db.WithContext(ctx).Model(&domain.Peer{}).
+		Select("user_identifier, COUNT(*) AS total").
+		Where("user_identifier IN ?", ids).
+		Group("user_identifier").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.UserIdentifier]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserIdentifier] = row.Total
+	}
+
+	return counts, nil
+}
+
+// GetUserApiTokens returns the named API tokens of the given user, ordered by their creation time.
+func (r *SqlRepo) GetUserApiTokens(ctx context.Context, id domain.UserIdentifier) ([]domain.ApiToken, error) {
+	var tokens []domain.ApiToken
+
+	err := r.db.WithContext(ctx).Where("user_identifier = ?", id).Order("created_at, identifier").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetAllApiTokens returns the named API tokens of all users.
+func (r *SqlRepo) GetAllApiTokens(ctx context.Context) ([]domain.ApiToken, error) {
+	var tokens []domain.ApiToken
+
+	err := r.db.WithContext(ctx).Order("user_identifier, created_at, identifier").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetApiToken returns the named API token with the given identifier.
+func (r *SqlRepo) GetApiToken(ctx context.Context, id domain.ApiTokenIdentifier) (*domain.ApiToken, error) {
+	var token domain.ApiToken
+
+	err := r.db.WithContext(ctx).Where("identifier = ?", id).First(&token).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// GetApiTokenByHash returns the named API token with the given secret hash.
+func (r *SqlRepo) GetApiTokenByHash(ctx context.Context, hash string) (*domain.ApiToken, error) {
+	var token domain.ApiToken
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// SaveApiToken creates or updates the given named API token.
+func (r *SqlRepo) SaveApiToken(ctx context.Context, token *domain.ApiToken) error {
+	err := r.db.WithContext(ctx).Save(token).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateApiTokenLastUsed sets the last usage time of the named API token with the given identifier.
+func (r *SqlRepo) UpdateApiTokenLastUsed(ctx context.Context, id domain.ApiTokenIdentifier, lastUsed time.Time) error {
+	err := r.db.WithContext(ctx).Model(&domain.ApiToken{}).Where("identifier = ?", id).
+		Update("last_used_at", lastUsed).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteApiToken deletes the named API token with the given identifier.
+func (r *SqlRepo) DeleteApiToken(ctx context.Context, id domain.ApiTokenIdentifier) error {
+	err := r.db.WithContext(ctx).Delete(&domain.ApiToken{Identifier: id}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // endregion users
 
 // region statistics
@@ -1087,4 +1474,392 @@ func (r *SqlRepo) GetAllAuditEntries(ctx context.Context) ([]domain.AuditEntry,
 	return entries, nil
 }
 
+// QueryAuditEntries returns at most limit audit entries that match the filter, newest first. The query continues
+// after the given cursor, a zero cursor starts at the newest entry.
+func (r *SqlRepo) QueryAuditEntries(
+	ctx context.Context,
+	filter domain.AuditEntryFilter,
+	cursor domain.AuditEntryCursor,
+	limit int,
+) ([]domain.AuditEntry, error) {
+	tx := r.db.WithContext(ctx).Model(&domain.AuditEntry{})
+	if cursor > 0 {
+		tx = tx.Where("id < ?", uint64(cursor))
+	}
+	if filter.From != nil {
+		tx = tx.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		tx = tx.Where("created_at < ?", *filter.To)
+	}
+	if filter.Severity != "" {
+		tx = tx.Where("severity = ?", filter.Severity)
+	}
+	if filter.ContextUser != "" {
+		tx = tx.Where("context_user = ?", filter.ContextUser)
+	}
+	if filter.Origin != "" {
+		tx = tx.Where("origin LIKE ? ESCAPE '!'", escapeLikePattern(filter.Origin)+"%")
+	}
+	if filter.Search != "" {
+		searchValue := "%" + escapeLikePattern(strings.ToLower(filter.Search)) + "%"
+		tx = tx.Where(r.db.
+			Where("LOWER(message) LIKE ? ESCAPE '!'", searchValue).
+			Or("LOWER(origin) LIKE ? ESCAPE '!'", searchValue).
+			Or("LOWER(entity_id) LIKE ? ESCAPE '!'", searchValue))
+	}
+
+	var entries []domain.AuditEntry
+	// identifiers are assigned in creation order, in contrast to timestamps they are unique
+	err := tx.Order("id desc").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// DeleteAuditEntriesBefore deletes the audit entries that have been created before the given time. It returns the
+// number of deleted entries.
+func (r *SqlRepo) DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&domain.AuditEntry{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// DeleteAuditEntriesExceeding deletes the oldest audit entries, so that at most keep entries remain. It returns the
+// number of deleted entries.
+func (r *SqlRepo) DeleteAuditEntriesExceeding(ctx context.Context, keep int) (int, error) {
+	var oldestKept []uint64
+	err := r.db.WithContext(ctx).Model(&domain.AuditEntry{}).
+		Order("id desc").Offset(keep-1).Limit(1).Pluck("id", &oldestKept).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(oldestKept) == 0 {
+		return 0, nil // not more than keep entries
+	}
+
+	result := r.db.WithContext(ctx).Where("id < ?", oldestKept[0]).Delete(&domain.AuditEntry{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// GetLastAuditEntry returns the newest audit entry. If there are no entries, domain.ErrNotFound is returned.
+func (r *SqlRepo) GetLastAuditEntry(ctx context.Context) (*domain.AuditEntry, error) {
+	var entry domain.AuditEntry
+
+	err := r.db.WithContext(ctx).Order("id desc").First(&entry).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetAuditEntriesAfter returns at most limit audit entries with an identifier greater than the given one, oldest
+// first.
+func (r *SqlRepo) GetAuditEntriesAfter(ctx context.Context, afterId uint64, limit int) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+
+	err := r.db.WithContext(ctx).Where("id > ?", afterId).Order("id").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// SaveAuditCheckpoint saves the given audit checkpoint.
+func (r *SqlRepo) SaveAuditCheckpoint(ctx context.Context, checkpoint *domain.AuditCheckpoint) error {
+	return r.db.WithContext(ctx).Save(checkpoint).Error
+}
+
+// GetAuditCheckpoints returns all audit checkpoints, oldest first.
+func (r *SqlRepo) GetAuditCheckpoints(ctx context.Context) ([]domain.AuditCheckpoint, error) {
+	var checkpoints []domain.AuditCheckpoint
+
+	err := r.db.WithContext(ctx).Order("id").Find(&checkpoints).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoints, nil
+}
+
+// escapeLikePattern escapes the wildcards of a LIKE pattern, ! is used as escape character.
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
+
 // endregion audit
+
+// region webhooks
+
+// GetWebhookSubscriptions returns the webhook subscriptions that have been created through the API.
+func (r *SqlRepo) GetWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+
+	err := r.db.WithContext(ctx).Order("name, identifier").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Source = domain.WebhookSubscriptionSourceDatabase
+	}
+
+	return subscriptions, nil
+}
+
+// SaveWebhookSubscription creates or updates the given webhook subscription.
+func (r *SqlRepo) SaveWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	err := r.db.WithContext(ctx).Save(subscription).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhookSubscription deletes the webhook subscription with the given identifier.
+func (r *SqlRepo) DeleteWebhookSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) error {
+	result := r.db.WithContext(ctx).Where("identifier = ?", id).Delete(&domain.WebhookSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// SaveWebhookDelivery creates or updates the given webhook delivery.
+func (r *SqlRepo) SaveWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	err := r.db.WithContext(ctx).Save(delivery).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetWebhookDelivery returns the webhook delivery with the given identifier.
+func (r *SqlRepo) GetWebhookDelivery(ctx context.Context, id domain.WebhookDeliveryIdentifier) (
+	*domain.WebhookDelivery,
+	error,
+) {
+	var delivery domain.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Where("identifier = ?", id).First(&delivery).Error
+	if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// GetDueWebhookDeliveries returns at most limit pending webhook deliveries whose next attempt is due at the given
+// time. The oldest deliveries are returned first.
+func (r *SqlRepo) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) (
+	[]domain.WebhookDelivery,
+	error,
+) {
+	var deliveries []domain.WebhookDelivery
+
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryStatusPending, now).
+		Order("created_at, identifier").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// QueryWebhookDeliveries returns a page of the webhook deliveries that match the filter, and the total number of
+// matching deliveries.
+func (r *SqlRepo) QueryWebhookDeliveries(
+	ctx context.Context,
+	filter domain.WebhookDeliveryFilter,
+	opts domain.ListOptions,
+) ([]domain.WebhookDelivery, int, error) {
+	query := func() *gorm.DB {
+		tx := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{})
+		if filter.Subscription != "" {
+			tx = tx.Where("subscription = ?", filter.Subscription)
+		}
+		if filter.Status != "" {
+			tx = tx.Where("status = ?", filter.Status)
+		}
+		return tx
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []domain.WebhookDelivery
+	err := applyListOptions(query(), opts, webhookDeliverySortColumns).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, int(total), nil
+}
+
+// DeleteWebhookDeliveriesBefore deletes the delivered and failed webhook deliveries that have been created before
+// the given time. Pending deliveries are kept. It returns the number of deleted deliveries.
+func (r *SqlRepo) DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int, error) {
+	result := r.db.WithContext(ctx).
+		Where("status <> ? AND created_at < ?", domain.WebhookDeliveryStatusPending, before).
+		Delete(&domain.WebhookDelivery{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// endregion webhooks
+
+// region backup
+
+// restoreBatchSize limits the number of records per insert statement, some databases limit the number of parameters.
+const restoreBatchSize = 50
+
+// GetBackupData returns all entities of the database.
+func (r *SqlRepo) GetBackupData(ctx context.Context) (*domain.BackupData, error) {
+	data := &domain.BackupData{}
+	db := r.db.WithContext(ctx)
+
+	if err := db.Order("identifier").Find(&data.Users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+	if err := db.Order("user_identifier, credential_identifier").Find(&data.WebAuthnCredentials).Error; err != nil {
+		return nil, fmt.Errorf("failed to load webauthn credentials: %w", err)
+	}
+	if err := db.Order("user_identifier, identifier").Find(&data.ApiTokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to load api tokens: %w", err)
+	}
+	if err := db.Order("identifier").Find(&data.WebhookSubscriptions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+	if err := db.Preload("Addresses").Order("identifier").Find(&data.Interfaces).Error; err != nil {
+		return nil, fmt.Errorf("failed to load interfaces: %w", err)
+	}
+	if err := db.Preload("Addresses").Order("interface_identifier, identifier").Find(&data.Peers).Error; err != nil {
+		return nil, fmt.Errorf("failed to load peers: %w", err)
+	}
+	for i := range data.Peers {
+		data.Peers[i].User = nil // the user is loaded by the AfterFind hook, it is part of the users
+	}
+	if err := db.Order("identifier").Find(&data.InterfaceStatuses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load interface statuses: %w", err)
+	}
+	if err := db.Order("identifier").Find(&data.PeerStatuses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load peer statuses: %w", err)
+	}
+	if err := db.Order("created_at, id").Find(&data.AuditEntries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load audit entries: %w", err)
+	}
+	if err := db.Order("created_at, id").Find(&data.AuditCheckpoints).Error; err != nil {
+		return nil, fmt.Errorf("failed to load audit checkpoints: %w", err)
+	}
+
+	return data, nil
+}
+
+// RestoreBackupData replaces all entities of the database with the given data. All changes are made in a single
+// transaction, so the database is unchanged if the restore fails. Audit entries and checkpoints get new
+// identifiers, as auto-increment counters cannot be set in a database independent way. The identifiers are not part
+// of the entry hashes and checkpoint signatures, so the restored hash chain stays valid.
+func (r *SqlRepo) RestoreBackupData(ctx context.Context, data *domain.BackupData) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// join tables first, the address records are shared by interfaces and peers
+		for _, table := range []string{"peer_addresses", "interface_addresses"} {
+			if err := tx.Exec("DELETE FROM " + table).Error; err != nil {
+				return fmt.Errorf("failed to clear %s: %w", table, err)
+			}
+		}
+		for _, model := range []any{&domain.Peer{}, &domain.PeerStatus{}, &domain.Interface{},
+			&domain.InterfaceStatus{}, &domain.Cidr{}, &domain.UserWebauthnCredential{}, &domain.ApiToken{},
+			&domain.User{}, &domain.AuditEntry{}, &domain.AuditCheckpoint{}, &domain.WebhookSubscription{}} {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to clear %T: %w", model, err)
+			}
+		}
+
+		users := make([]domain.User, len(data.Users))
+		for i, user := range data.Users {
+			users[i] = user
+			users[i].WebAuthnCredentialList = nil
+		}
+		if err := createInBatches(tx.Omit(clause.Associations), users); err != nil {
+			return fmt.Errorf("failed to restore users: %w", err)
+		}
+		if err := createInBatches(tx, data.WebAuthnCredentials); err != nil {
+			return fmt.Errorf("failed to restore webauthn credentials: %w", err)
+		}
+		if err := createInBatches(tx, data.ApiTokens); err != nil {
+			return fmt.Errorf("failed to restore api tokens: %w", err)
+		}
+		if err := createInBatches(tx, data.WebhookSubscriptions); err != nil {
+			return fmt.Errorf("failed to restore webhook subscriptions: %w", err)
+		}
+		if err := createInBatches(tx, data.Interfaces); err != nil {
+			return fmt.Errorf("failed to restore interfaces: %w", err)
+		}
+		if err := createInBatches(tx, data.Peers); err != nil {
+			return fmt.Errorf("failed to restore peers: %w", err)
+		}
+		if err := createInBatches(tx, data.InterfaceStatuses); err != nil {
+			return fmt.Errorf("failed to restore interface statuses: %w", err)
+		}
+		if err := createInBatches(tx, data.PeerStatuses); err != nil {
+			return fmt.Errorf("failed to restore peer statuses: %w", err)
+		}
+		entries := make([]domain.AuditEntry, len(data.AuditEntries))
+		for i, entry := range data.AuditEntries {
+			entries[i] = entry
+			entries[i].UniqueId = 0
+		}
+		if err := createInBatches(tx, entries); err != nil {
+			return fmt.Errorf("failed to restore audit entries: %w", err)
+		}
+		checkpoints := make([]domain.AuditCheckpoint, len(data.AuditCheckpoints))
+		for i, checkpoint := range data.AuditCheckpoints {
+			checkpoints[i] = checkpoint
+			checkpoints[i].Id = 0
+		}
+		if err := createInBatches(tx, checkpoints); err != nil {
+			return fmt.Errorf("failed to restore audit checkpoints: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func createInBatches[T any](tx *gorm.DB, records []T) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(records, restoreBatchSize).Error
+}
+
+// endregion backup
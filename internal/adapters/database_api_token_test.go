@@ -0,0 +1,39 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestSqlRepo_ApiTokens(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+
+	require.NoError(t, repo.SaveUser(ctx, "alice", func(u *domain.User) (*domain.User, error) {
+		return u, nil
+	}))
+	token, secret, err := domain.NewApiToken("alice", "ci", []domain.ApiTokenScope{domain.ApiTokenScopeReadOnly})
+	require.NoError(t, err)
+	token.SourceCidrs = []string{"10.0.0.0/8"}
+	require.NoError(t, repo.SaveApiToken(ctx, token))
+
+	found, err := repo.GetApiTokenByHash(ctx, domain.HashApiTokenSecret(secret))
+	require.NoError(t, err)
+	assert.Equal(t, token.Identifier, found.Identifier)
+	assert.Equal(t, []domain.ApiTokenScope{domain.ApiTokenScopeReadOnly}, found.Scopes)
+	assert.Equal(t, []string{"10.0.0.0/8"}, found.SourceCidrs)
+
+	_, err = repo.GetApiTokenByHash(ctx, domain.HashApiTokenSecret("wgp_unknown"))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// tokens are removed together with their user
+	require.NoError(t, repo.DeleteUser(ctx, "alice"))
+	tokens, err := repo.GetUserApiTokens(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
@@ -0,0 +1,132 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestSqlRepo_AuditEntries(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour)
+	from, to := start.Add(time.Minute), start.Add(2*time.Minute)
+
+	for i, entry := range []domain.AuditEntry{
+		{ContextUser: "admin", Severity: domain.AuditSeverityLevelLow, Origin: "auth: login", Message: "admin logged in"},
+		{ContextUser: "admin", Severity: domain.AuditSeverityLevelHigh, Origin: "user: update",
+			EntityId: "alice", Message: "user alice updated"},
+		{ContextUser: "alice", Severity: domain.AuditSeverityLevelLow, Origin: "peer: save",
+			Message: "peer 50%_off saved"},
+		{ContextUser: "admin", Severity: domain.AuditSeverityLevelLow, Origin: "peer: delete",
+			Message: "peer deleted"},
+	} {
+		entry.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.SaveAuditEntry(ctx, &entry))
+	}
+
+	// pages are returned newest first
+	page, err := repo.QueryAuditEntries(ctx, domain.AuditEntryFilter{}, 0, 3)
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	assert.Equal(t, "peer: delete", page[0].Origin)
+	page, err = repo.QueryAuditEntries(ctx, domain.AuditEntryFilter{}, domain.AuditEntryCursor(page[2].UniqueId), 3)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "auth: login", page[0].Origin)
+
+	tests := []struct {
+		name   string
+		filter domain.AuditEntryFilter
+		want   []string
+	}{
+		{"severity", domain.AuditEntryFilter{Severity: domain.AuditSeverityLevelHigh}, []string{"user: update"}},
+		{"user", domain.AuditEntryFilter{ContextUser: "alice"}, []string{"peer: save"}},
+		{"origin", domain.AuditEntryFilter{Origin: "peer"}, []string{"peer: delete", "peer: save"}},
+		{"search entity", domain.AuditEntryFilter{Search: "ALICE"}, []string{"user: update"}},
+		{"search wildcards", domain.AuditEntryFilter{Search: "%_"}, []string{"peer: save"}},
+		{"time range", domain.AuditEntryFilter{From: &from, To: &to}, []string{"user: update"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := repo.QueryAuditEntries(ctx, tt.filter, 0, 10)
+			require.NoError(t, err)
+			origins := make([]string, len(entries))
+			for i, entry := range entries {
+				origins[i] = entry.Origin
+			}
+			assert.Equal(t, tt.want, origins)
+		})
+	}
+
+	deleted, err := repo.DeleteAuditEntriesBefore(ctx, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = repo.DeleteAuditEntriesExceeding(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	deleted, err = repo.DeleteAuditEntriesExceeding(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	entries, err := repo.GetAllAuditEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "peer: delete", entries[0].Origin)
+}
+
+func TestSqlRepo_AuditChain(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.GetLastAuditEntry(ctx)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+
+	var prevHash string
+	for _, changes := range [][]domain.AuditChange{
+		nil,
+		{},
+		domain.AuditChanges(&domain.User{Firstname: "Alice"}, &domain.User{Firstname: "Bob", IsAdmin: true}),
+	} {
+		entry := &domain.AuditEntry{
+			CreatedAt: time.Now().Truncate(time.Millisecond),
+			Severity:  domain.AuditSeverityLevelLow,
+			Message:   "changed",
+			Changes:   changes,
+			PrevHash:  prevHash,
+		}
+		entry.Hash = entry.ComputeHash()
+		require.NoError(t, repo.SaveAuditEntry(ctx, entry))
+		prevHash = entry.Hash
+	}
+
+	// the hashes still match after the entries have been loaded from the database
+	entries, err := repo.GetAuditEntriesAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, entry.Hash, entry.ComputeHash())
+	}
+	first := entries[0]
+	entries, err = repo.GetAuditEntriesAfter(ctx, first.UniqueId, 1)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, first.Hash, entries[0].PrevHash)
+
+	last, err := repo.GetLastAuditEntry(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, prevHash, last.Hash)
+
+	checkpoint := &domain.AuditCheckpoint{CreatedAt: time.Now(), EntryHash: last.Hash, Signature: "sig"}
+	require.NoError(t, repo.SaveAuditCheckpoint(ctx, checkpoint))
+	checkpoints, err := repo.GetAuditCheckpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	assert.Equal(t, last.Hash, checkpoints[0].EntryHash)
+}
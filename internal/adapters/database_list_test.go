@@ -0,0 +1,97 @@
+package adapters_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+
+	"github.com/biezax/wg-portal/internal/adapters"
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func init() {
+	schema.RegisterSerializer("encstr", app.NewGormEncryptedStringSerializer("database-encryption-key"))
+}
+
+func newListTestRepo(t *testing.T) *adapters.SqlRepo {
+	db, err := adapters.NewDatabase(config.DatabaseConfig{
+		Type: config.DatabaseSQLite,
+		DSN:  filepath.Join(t.TempDir(), "list.db"),
+	})
+	require.NoError(t, err)
+	repo, err := adapters.NewSqlRepository(db)
+	require.NoError(t, err)
+	return repo
+}
+
+func TestSqlRepo_QueryPeers(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+
+	past := time.Now().Add(-time.Hour)
+	peers := []domain.Peer{
+		{Identifier: "peer-a", InterfaceIdentifier: "wg0", UserIdentifier: "alice", DisplayName: "c"},
+		{Identifier: "peer-b", InterfaceIdentifier: "wg0", UserIdentifier: "bob", DisplayName: "b", Disabled: &past},
+		{Identifier: "peer-c", InterfaceIdentifier: "wg0", UserIdentifier: "alice", DisplayName: "a", ExpiresAt: &past},
+		{Identifier: "peer-d", InterfaceIdentifier: "wg1", UserIdentifier: "alice", DisplayName: "d"},
+	}
+	for _, peer := range peers {
+		require.NoError(t, repo.SavePeer(ctx, peer.Identifier, func(_ *domain.Peer) (*domain.Peer, error) {
+			return &peer, nil
+		}))
+	}
+	require.NoError(t, repo.UpdatePeerStatus(ctx, "peer-a", func(in *domain.PeerStatus) (*domain.PeerStatus, error) {
+		in.IsConnected = true
+		return in, nil
+	}))
+
+	result, total, err := repo.QueryPeers(ctx, domain.PeerFilter{InterfaceIdentifier: "wg0"},
+		domain.ListOptions{Limit: 2, SortBy: "DisplayName"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, result, 2)
+	assert.Equal(t, domain.PeerIdentifier("peer-c"), result[0].Identifier)
+	assert.Equal(t, domain.PeerIdentifier("peer-b"), result[1].Identifier)
+
+	result, total, err = repo.QueryPeers(ctx, domain.PeerFilter{InterfaceIdentifier: "wg0"},
+		domain.ListOptions{Offset: 2, Limit: 2, SortBy: "DisplayName"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, result, 1)
+	assert.Equal(t, domain.PeerIdentifier("peer-a"), result[0].Identifier)
+
+	enabled, expired, connected := false, true, true
+	_, total, err = repo.QueryPeers(ctx, domain.PeerFilter{Disabled: &enabled}, domain.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	result, _, err = repo.QueryPeers(ctx, domain.PeerFilter{Expired: &expired}, domain.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, domain.PeerIdentifier("peer-c"), result[0].Identifier)
+	notExpired := false
+	_, total, err = repo.QueryPeers(ctx, domain.PeerFilter{InterfaceIdentifier: "wg0", Expired: &notExpired},
+		domain.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	result, _, err = repo.QueryPeers(ctx, domain.PeerFilter{UserIdentifier: "alice", Connected: &connected},
+		domain.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, domain.PeerIdentifier("peer-a"), result[0].Identifier)
+
+	counts, err := repo.GetInterfacePeerCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PeerCounts{Total: 3, Enabled: 2}, counts["wg0"])
+	assert.Equal(t, domain.PeerCounts{Total: 1, Enabled: 1}, counts["wg1"])
+
+	userCounts, err := repo.GetUserPeerCounts(ctx, "alice", "bob", "carol")
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.UserIdentifier]int{"alice": 3, "bob": 1}, userCounts)
+}
@@ -0,0 +1,48 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestSqlRepo_SavePeer_IfMatch(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+	rename := func(name string) func(in *domain.Peer) (*domain.Peer, error) {
+		return func(in *domain.Peer) (*domain.Peer, error) {
+			in.InterfaceIdentifier = "wg0"
+			in.DisplayName = name
+			return in, nil
+		}
+	}
+
+	require.NoError(t, repo.SavePeer(ctx, "peer-a", rename("first")))
+	peer, err := repo.GetPeer(ctx, "peer-a")
+	require.NoError(t, err)
+	etag := peer.ETag()
+
+	require.NoError(t, repo.SavePeer(domain.SetIfMatchPrecondition(ctx, peer.Identifier, etag), "peer-a",
+		rename("second")))
+
+	// the entity tag changed with the previous update
+	err = repo.SavePeer(domain.SetIfMatchPrecondition(ctx, peer.Identifier, etag), "peer-a", rename("third"))
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	err = repo.DeletePeer(domain.SetIfMatchPrecondition(ctx, peer.Identifier, etag), "peer-a")
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+
+	peer, err = repo.GetPeer(ctx, "peer-a")
+	require.NoError(t, err)
+	assert.Equal(t, "second", peer.DisplayName)
+	assert.NotEqual(t, etag, peer.ETag())
+
+	err = repo.SavePeer(domain.SetIfMatchPrecondition(ctx, domain.PeerIdentifier("peer-b"), "*"), "peer-b",
+		rename("new"))
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed, "If-Match must not create missing records")
+
+	require.NoError(t, repo.DeletePeer(domain.SetIfMatchPrecondition(ctx, peer.Identifier, peer.ETag()), "peer-a"))
+}
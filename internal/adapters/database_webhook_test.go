@@ -0,0 +1,77 @@
+package adapters_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestSqlRepo_WebhookDeliveries(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := context.Background()
+	newDelivery := func(event string) *domain.WebhookDelivery {
+		return domain.NewWebhookDelivery("default", event, "user", "alice", "http://localhost", []byte(`{}`),
+			"application/json")
+	}
+
+	due := newDelivery("create")
+	require.NoError(t, repo.SaveWebhookDelivery(ctx, due))
+
+	later := newDelivery("update")
+	nextAttempt := time.Now().Add(time.Hour)
+	later.NextAttemptAt = &nextAttempt
+	require.NoError(t, repo.SaveWebhookDelivery(ctx, later))
+
+	delivered := newDelivery("delete")
+	delivered.Status = domain.WebhookDeliveryStatusDelivered
+	require.NoError(t, repo.SaveWebhookDelivery(ctx, delivered))
+
+	deliveries, err := repo.GetDueWebhookDeliveries(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, due.Identifier, deliveries[0].Identifier)
+	assert.Equal(t, []byte(`{}`), deliveries[0].Payload)
+
+	deliveries, total, err := repo.QueryWebhookDeliveries(ctx,
+		domain.WebhookDeliveryFilter{Status: domain.WebhookDeliveryStatusPending},
+		domain.ListOptions{SortBy: "CreatedAt", SortDesc: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, later.Identifier, deliveries[0].Identifier)
+
+	// only completed deliveries are removed
+	deleted, err := repo.DeleteWebhookDeliveriesBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	_, err = repo.GetWebhookDelivery(ctx, delivered.Identifier)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestSqlRepo_WebhookSubscriptions(t *testing.T) {
+	repo := newListTestRepo(t)
+	ctx := context.Background()
+
+	subscription := &domain.WebhookSubscription{
+		Identifier: domain.NewWebhookSubscriptionIdentifier(),
+		Name:       "noc",
+		Url:        "http://localhost",
+		Events:     []string{"connect", "disconnect"},
+		Interfaces: []domain.InterfaceIdentifier{"wg0"},
+	}
+	require.NoError(t, repo.SaveWebhookSubscription(ctx, subscription))
+
+	subscriptions, err := repo.GetWebhookSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, domain.WebhookSubscriptionSourceDatabase, subscriptions[0].Source)
+	assert.Equal(t, []string{"connect", "disconnect"}, subscriptions[0].Events)
+	assert.Equal(t, []domain.InterfaceIdentifier{"wg0"}, subscriptions[0].Interfaces)
+
+	require.NoError(t, repo.DeleteWebhookSubscription(ctx, subscription.Identifier))
+	assert.ErrorIs(t, repo.DeleteWebhookSubscription(ctx, subscription.Identifier), domain.ErrNotFound)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	mail "github.com/xhit/go-simple-mail/v2"
@@ -16,20 +17,41 @@ import (
 )
 
 type MailRepo struct {
+	mu  sync.RWMutex // protects cfg, it is replaced on configuration reloads
 	cfg *config.MailConfig
 }
 
 // NewSmtpMailRepo creates a new MailRepo instance.
-func NewSmtpMailRepo(cfg config.MailConfig) MailRepo {
-	return MailRepo{cfg: &cfg}
+func NewSmtpMailRepo(cfg config.MailConfig) *MailRepo {
+	return &MailRepo{cfg: &cfg}
+}
+
+// ReloadConfig applies the SMTP settings of the given configuration to all mails that are sent afterward.
+func (r *MailRepo) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	mailCfg := cfg.Mail
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cfg = &mailCfg
+
+	return nil
+}
+
+func (r *MailRepo) getConfig() *config.MailConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cfg
 }
 
 // Send sends a mail using SMTP.
-func (r MailRepo) Send(_ context.Context, subject, body string, to []string, options *domain.MailOptions) error {
+func (r *MailRepo) Send(_ context.Context, subject, body string, to []string, options *domain.MailOptions) error {
 	if options == nil {
 		options = &domain.MailOptions{}
 	}
-	r.setDefaultOptions(r.cfg.From, options)
+	cfg := r.getConfig()
+	r.setDefaultOptions(cfg.From, options)
 
 	if len(to) == 0 {
 		return errors.New("missing email recipient")
@@ -37,7 +59,7 @@ func (r MailRepo) Send(_ context.Context, subject, body string, to []string, opt
 
 	uniqueTo := internal.UniqueStringSlice(to)
 	email := mail.NewMSG()
-	email.SetFrom(r.cfg.From).
+	email.SetFrom(cfg.From).
 		AddTo(uniqueTo...).
 		SetReplyTo(options.ReplyTo).
 		SetSubject(subject).
@@ -75,7 +97,7 @@ func (r MailRepo) Send(_ context.Context, subject, body string, to []string, opt
 	}
 
 	// Call Send and pass the client
-	srv := r.getMailServer()
+	srv := r.getMailServer(cfg)
 	client, err := srv.Connect()
 	if err != nil {
 		return fmt.Errorf("failed to connect to SMTP server: %w", err)
@@ -89,23 +111,33 @@ func (r MailRepo) Send(_ context.Context, subject, body string, to []string, opt
 	return nil
 }
 
-func (r MailRepo) setDefaultOptions(sender string, options *domain.MailOptions) {
+// Ping connects and authenticates to the SMTP server without sending a mail.
+func (r *MailRepo) Ping(_ context.Context) error {
+	client, err := r.getMailServer(r.getConfig()).Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	return client.Close()
+}
+
+func (r *MailRepo) setDefaultOptions(sender string, options *domain.MailOptions) {
 	if options.ReplyTo == "" {
 		options.ReplyTo = sender
 	}
 }
 
-func (r MailRepo) getMailServer() *mail.SMTPServer {
+func (r *MailRepo) getMailServer(cfg *config.MailConfig) *mail.SMTPServer {
 	srv := mail.NewSMTPClient()
 
 	srv.ConnectTimeout = 30 * time.Second
 	srv.SendTimeout = 30 * time.Second
-	srv.Host = r.cfg.Host
-	srv.Port = r.cfg.Port
-	srv.Username = r.cfg.Username
-	srv.Password = r.cfg.Password
+	srv.Host = cfg.Host
+	srv.Port = cfg.Port
+	srv.Username = cfg.Username
+	srv.Password = cfg.Password
 
-	switch r.cfg.Encryption {
+	switch cfg.Encryption {
 	case config.MailEncryptionTLS:
 		srv.Encryption = mail.EncryptionSSLTLS
 	case config.MailEncryptionStartTLS:
@@ -113,8 +145,8 @@ func (r MailRepo) getMailServer() *mail.SMTPServer {
 	default: // MailEncryptionNone
 		srv.Encryption = mail.EncryptionNone
 	}
-	srv.TLSConfig = &tls.Config{ServerName: srv.Host, InsecureSkipVerify: !r.cfg.CertValidation}
-	switch r.cfg.AuthType {
+	srv.TLSConfig = &tls.Config{ServerName: srv.Host, InsecureSkipVerify: !cfg.CertValidation}
+	switch cfg.AuthType {
 	case config.MailAuthPlain:
 		srv.Authentication = mail.AuthPlain
 	case config.MailAuthLogin:
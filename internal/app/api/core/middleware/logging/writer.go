@@ -38,6 +38,12 @@ func (w *writerWrapper) Write(data []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap returns the wrapped ResponseWriter, so that http.ResponseController can access its optional
+// interfaces, for example http.Flusher.
+func (w *writerWrapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // newWriterWrapper returns a new writerWrapper that wraps the given http.ResponseWriter.
 // It initializes the StatusCode to http.StatusOK.
 func newWriterWrapper(w http.ResponseWriter) *writerWrapper {
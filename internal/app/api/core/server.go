@@ -17,8 +17,10 @@ import (
 	"github.com/biezax/wg-portal/internal/app/api/core/middleware/logging"
 	"github.com/biezax/wg-portal/internal/app/api/core/middleware/recovery"
 	"github.com/biezax/wg-portal/internal/app/api/core/middleware/tracing"
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
 	"github.com/biezax/wg-portal/internal/app/api/core/respond"
 	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
 )
 
 const (
@@ -61,6 +63,13 @@ func NewServer(cfg *config.Config, endpoints ...ApiEndpointSetupFunc) (*Server,
 		tracing.WithContextIdentifier(RequestIDKey),
 		tracing.WithHeaderIdentifier(RequestIDKey),
 	).Handler)
+	s.server.Use(func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the client IP is recorded in the audit log
+			ctx := domain.SetSourceIp(r.Context(), request.ClientIp(r, cfg.Web.TrustedProxies...))
+			handler.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
 	if cfg.Web.ExposeHostInfo {
 		s.server.Use(func(handler http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
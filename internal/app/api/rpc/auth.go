@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// reflectionServicePrefix is the prefix of the reflection service methods, they are available without credentials.
+const reflectionServicePrefix = "/grpc.reflection."
+
+type Authenticator interface {
+	// Authenticate returns the user of the given Authorization header value. If a named API token or a JWT has been
+	// used, its scopes are returned as well.
+	Authenticate(ctx context.Context, authorization, clientIp string) (*domain.User, *domain.ApiToken, error)
+}
+
+// methodAccess describes the permissions that are required to call a method.
+type methodAccess struct {
+	resource string // the resource of the matching v1 REST endpoint, it is checked against the scopes of API tokens
+	write    bool   // true for methods that modify data
+	admin    bool   // true for methods that are restricted to admins
+}
+
+// methodAccessRules contains the permissions of all methods, they match the permissions of the v1 REST endpoints.
+// Methods that are not listed are denied.
+var methodAccessRules = map[string]methodAccess{
+	pb.UserService_ListUsers_FullMethodName:  {resource: "user", admin: true},
+	pb.UserService_GetUser_FullMethodName:    {resource: "user"},
+	pb.UserService_CreateUser_FullMethodName: {resource: "user", write: true, admin: true},
+	pb.UserService_UpdateUser_FullMethodName: {resource: "user", write: true, admin: true},
+	pb.UserService_DeleteUser_FullMethodName: {resource: "user", write: true, admin: true},
+
+	pb.PeerService_ListInterfacePeers_FullMethodName: {resource: "peer", admin: true},
+	pb.PeerService_ListUserPeers_FullMethodName:      {resource: "peer"},
+	pb.PeerService_GetPeer_FullMethodName:            {resource: "peer"},
+	pb.PeerService_PreparePeer_FullMethodName:        {resource: "peer", admin: true},
+	pb.PeerService_CreatePeer_FullMethodName:         {resource: "peer", write: true, admin: true},
+	pb.PeerService_UpdatePeer_FullMethodName:         {resource: "peer", write: true, admin: true},
+	pb.PeerService_DeletePeer_FullMethodName:         {resource: "peer", write: true, admin: true},
+	pb.PeerService_WatchPeerStatus_FullMethodName:    {resource: "event"},
+
+	pb.InterfaceService_ListInterfaces_FullMethodName:   {resource: "interface", admin: true},
+	pb.InterfaceService_GetInterface_FullMethodName:     {resource: "interface", admin: true},
+	pb.InterfaceService_PrepareInterface_FullMethodName: {resource: "interface", admin: true},
+	pb.InterfaceService_CreateInterface_FullMethodName:  {resource: "interface", write: true, admin: true},
+	pb.InterfaceService_UpdateInterface_FullMethodName:  {resource: "interface", write: true, admin: true},
+	pb.InterfaceService_DeleteInterface_FullMethodName:  {resource: "interface", write: true, admin: true},
+	pb.InterfaceService_ImportWgQuick_FullMethodName:    {resource: "interface", write: true, admin: true},
+
+	pb.ProvisioningService_GetUserInformation_FullMethodName: {resource: "provisioning"},
+	pb.ProvisioningService_GetPeerConfig_FullMethodName:      {resource: "provisioning"},
+	pb.ProvisioningService_GetPeerQrCode_FullMethodName:      {resource: "provisioning"},
+	pb.ProvisioningService_CreatePeer_FullMethodName:         {resource: "provisioning", write: true, admin: true},
+
+	pb.MetricsService_GetInterfaceMetrics_FullMethodName: {resource: "metrics", admin: true},
+	pb.MetricsService_GetUserMetrics_FullMethodName:      {resource: "metrics"},
+	pb.MetricsService_GetPeerMetrics_FullMethodName:      {resource: "metrics"},
+	pb.MetricsService_WatchUserMetrics_FullMethodName:    {resource: "metrics"},
+}
+
+// authInterceptor authenticates all calls and stores the user information in the call context, just like the
+// LoggedIn middleware of the v1 REST API.
+type authInterceptor struct {
+	authenticator Authenticator
+}
+
+func newAuthInterceptor(authenticator Authenticator) authInterceptor {
+	return authInterceptor{authenticator: authenticator}
+}
+
+func (a authInterceptor) unary(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a authInterceptor) stream(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, authorizedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize authenticates the caller and checks the permissions for the given method. The returned context contains
+// the user information of the caller.
+func (a authInterceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if strings.HasPrefix(fullMethod, reflectionServicePrefix) {
+		return ctx, nil
+	}
+
+	access, ok := methodAccessRules[fullMethod]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "not enough permissions")
+	}
+
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	ctx = domain.SetSourceIp(ctx, clientIp(ctx))
+	user, token, err := a.authenticator.Authenticate(ctx, authorization, domain.GetSourceIp(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if (access.admin && !user.IsAdmin) || (token != nil && !token.Allows(access.resource, access.write)) {
+		return nil, status.Error(codes.PermissionDenied, "not enough permissions")
+	}
+
+	return domain.SetUserInfo(ctx, &domain.ContextUserInfo{
+		Id:      user.Identifier,
+		IsAdmin: user.IsAdmin,
+	}), nil
+}
+
+// clientIp returns the IP address of the caller, it is checked against the source networks of named API tokens.
+func clientIp(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// authorizedStream is a server stream with the context of the authenticated caller.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authorizedStream) Context() context.Context {
+	return s.ctx
+}
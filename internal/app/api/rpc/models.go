@@ -0,0 +1,451 @@
+package rpc
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// The protocol buffer messages mirror the models of the v1 REST API. Domain objects are converted with the v1 model
+// functions first, so both APIs expose the same data.
+
+// region common
+
+func newListOptions(src *pb.ListOptions) domain.ListOptions {
+	sortBy := src.GetSort()
+
+	return domain.ListOptions{
+		Offset:   int(src.GetOffset()),
+		Limit:    int(src.GetLimit()),
+		SortBy:   strings.TrimPrefix(sortBy, "-"),
+		SortDesc: strings.HasPrefix(sortBy, "-"),
+	}
+}
+
+func newTimestamp(src *time.Time) *timestamppb.Timestamp {
+	if src == nil || src.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*src)
+}
+
+func newTimeFilter(src *timestamppb.Timestamp) *time.Time {
+	if src == nil {
+		return nil
+	}
+	t := src.AsTime()
+	return &t
+}
+
+func newStringOption(src models.ConfigOption[string]) *pb.StringOption {
+	return &pb.StringOption{Value: src.Value, Overridable: src.Overridable}
+}
+
+func newModelStringOption(src *pb.StringOption) models.ConfigOption[string] {
+	return models.ConfigOption[string]{Value: src.GetValue(), Overridable: src.GetOverridable()}
+}
+
+func newStringListOption(src models.ConfigOption[[]string]) *pb.StringListOption {
+	return &pb.StringListOption{Value: src.Value, Overridable: src.Overridable}
+}
+
+func newModelStringListOption(src *pb.StringListOption) models.ConfigOption[[]string] {
+	return models.ConfigOption[[]string]{Value: src.GetValue(), Overridable: src.GetOverridable()}
+}
+
+func newInt32Option(src models.ConfigOption[int]) *pb.Int32Option {
+	return &pb.Int32Option{Value: int32(src.Value), Overridable: src.Overridable}
+}
+
+func newModelIntOption(src *pb.Int32Option) models.ConfigOption[int] {
+	return models.ConfigOption[int]{Value: int(src.GetValue()), Overridable: src.GetOverridable()}
+}
+
+func newUint32Option(src models.ConfigOption[uint32]) *pb.Uint32Option {
+	return &pb.Uint32Option{Value: src.Value, Overridable: src.Overridable}
+}
+
+func newModelUint32Option(src *pb.Uint32Option) models.ConfigOption[uint32] {
+	return models.ConfigOption[uint32]{Value: src.GetValue(), Overridable: src.GetOverridable()}
+}
+
+// endregion common
+
+// region users
+
+func newUser(src *domain.User, exposeCredentials bool) *pb.User {
+	u := models.NewUser(src, exposeCredentials)
+
+	return &pb.User{
+		Identifier:     u.Identifier,
+		Email:          u.Email,
+		Source:         u.Source,
+		ProviderName:   u.ProviderName,
+		IsAdmin:        u.IsAdmin,
+		Firstname:      u.Firstname,
+		Lastname:       u.Lastname,
+		Phone:          u.Phone,
+		Department:     u.Department,
+		Notes:          u.Notes,
+		Disabled:       u.Disabled,
+		DisabledReason: u.DisabledReason,
+		Locked:         u.Locked,
+		LockedReason:   u.LockedReason,
+		ApiToken:       u.ApiToken,
+		ApiEnabled:     u.ApiEnabled,
+		PeerCount:      int32(u.PeerCount),
+		Etag:           src.ETag(),
+	}
+}
+
+func newUsers(src []domain.User) []*pb.User {
+	results := make([]*pb.User, len(src))
+	for i := range src {
+		results[i] = newUser(&src[i], false)
+	}
+
+	return results
+}
+
+func newModelUser(src *pb.User) *models.User {
+	return &models.User{
+		Identifier:     src.GetIdentifier(),
+		Email:          src.GetEmail(),
+		Source:         src.GetSource(),
+		ProviderName:   src.GetProviderName(),
+		IsAdmin:        src.GetIsAdmin(),
+		Firstname:      src.GetFirstname(),
+		Lastname:       src.GetLastname(),
+		Phone:          src.GetPhone(),
+		Department:     src.GetDepartment(),
+		Notes:          src.GetNotes(),
+		Password:       src.GetPassword(),
+		Disabled:       src.GetDisabled(),
+		DisabledReason: src.GetDisabledReason(),
+		Locked:         src.GetLocked(),
+		LockedReason:   src.GetLockedReason(),
+		ApiToken:       src.GetApiToken(),
+	}
+}
+
+// endregion users
+
+// region peers
+
+func newPeer(src *domain.Peer) *pb.Peer {
+	p := models.NewPeer(src)
+
+	return &pb.Peer{
+		Identifier:          p.Identifier,
+		DisplayName:         p.DisplayName,
+		UserIdentifier:      p.UserIdentifier,
+		InterfaceIdentifier: p.InterfaceIdentifier,
+		Disabled:            p.Disabled,
+		DisabledReason:      p.DisabledReason,
+		ExpiresAt:           p.ExpiresAt,
+		Notes:               p.Notes,
+		Endpoint:            newStringOption(p.Endpoint),
+		EndpointPublicKey:   newStringOption(p.EndpointPublicKey),
+		AllowedIps:          newStringListOption(p.AllowedIPs),
+		ExtraAllowedIps:     p.ExtraAllowedIPs,
+		PresharedKey:        p.PresharedKey,
+		PersistentKeepalive: newInt32Option(p.PersistentKeepalive),
+		PrivateKey:          p.PrivateKey,
+		PublicKey:           p.PublicKey,
+		Mode:                p.Mode,
+		Addresses:           p.Addresses,
+		CheckAliveAddress:   p.CheckAliveAddress,
+		Dns:                 newStringListOption(p.Dns),
+		DnsSearch:           newStringListOption(p.DnsSearch),
+		Mtu:                 newInt32Option(p.Mtu),
+		FirewallMark:        newUint32Option(p.FirewallMark),
+		RoutingTable:        newStringOption(p.RoutingTable),
+		PreUp:               newStringOption(p.PreUp),
+		PostUp:              newStringOption(p.PostUp),
+		PreDown:             newStringOption(p.PreDown),
+		PostDown:            newStringOption(p.PostDown),
+		Filename:            p.Filename,
+		Etag:                src.ETag(),
+	}
+}
+
+func newPeers(src []domain.Peer) []*pb.Peer {
+	results := make([]*pb.Peer, len(src))
+	for i := range src {
+		results[i] = newPeer(&src[i])
+	}
+
+	return results
+}
+
+func newModelPeer(src *pb.Peer) *models.Peer {
+	return &models.Peer{
+		Identifier:          src.GetIdentifier(),
+		DisplayName:         src.GetDisplayName(),
+		UserIdentifier:      src.GetUserIdentifier(),
+		InterfaceIdentifier: src.GetInterfaceIdentifier(),
+		Disabled:            src.GetDisabled(),
+		DisabledReason:      src.GetDisabledReason(),
+		ExpiresAt:           src.GetExpiresAt(),
+		Notes:               src.GetNotes(),
+		Endpoint:            newModelStringOption(src.GetEndpoint()),
+		EndpointPublicKey:   newModelStringOption(src.GetEndpointPublicKey()),
+		AllowedIPs:          newModelStringListOption(src.GetAllowedIps()),
+		ExtraAllowedIPs:     src.GetExtraAllowedIps(),
+		PresharedKey:        src.GetPresharedKey(),
+		PersistentKeepalive: newModelIntOption(src.GetPersistentKeepalive()),
+		PrivateKey:          src.GetPrivateKey(),
+		PublicKey:           src.GetPublicKey(),
+		Mode:                src.GetMode(),
+		Addresses:           src.GetAddresses(),
+		CheckAliveAddress:   src.GetCheckAliveAddress(),
+		Dns:                 newModelStringListOption(src.GetDns()),
+		DnsSearch:           newModelStringListOption(src.GetDnsSearch()),
+		Mtu:                 newModelIntOption(src.GetMtu()),
+		FirewallMark:        newModelUint32Option(src.GetFirewallMark()),
+		RoutingTable:        newModelStringOption(src.GetRoutingTable()),
+		PreUp:               newModelStringOption(src.GetPreUp()),
+		PostUp:              newModelStringOption(src.GetPostUp()),
+		PreDown:             newModelStringOption(src.GetPreDown()),
+		PostDown:            newModelStringOption(src.GetPostDown()),
+	}
+}
+
+func newPeerFilter(src *pb.PeerFilter) domain.PeerFilter {
+	if src == nil {
+		return domain.PeerFilter{}
+	}
+
+	return domain.PeerFilter{
+		Disabled:     src.Disabled,
+		Expired:      src.Expired,
+		Connected:    src.Connected,
+		CreatedAfter: newTimeFilter(src.GetCreatedAfter()),
+	}
+}
+
+// endregion peers
+
+// region interfaces
+
+func newInterface(src *domain.Interface, peers []domain.Peer) *pb.Interface {
+	return newInterfaceFromModel(models.NewInterface(src, peers), src.ETag())
+}
+
+func newInterfaceFromModel(i *models.Interface, etag string) *pb.Interface {
+	return &pb.Interface{
+		Identifier:                 i.Identifier,
+		DisplayName:                i.DisplayName,
+		Mode:                       i.Mode,
+		PrivateKey:                 i.PrivateKey,
+		PublicKey:                  i.PublicKey,
+		Disabled:                   i.Disabled,
+		DisabledReason:             i.DisabledReason,
+		SaveConfig:                 i.SaveConfig,
+		ListenPort:                 int32(i.ListenPort),
+		Addresses:                  i.Addresses,
+		Dns:                        i.Dns,
+		DnsSearch:                  i.DnsSearch,
+		Mtu:                        int32(i.Mtu),
+		FirewallMark:               i.FirewallMark,
+		RoutingTable:               i.RoutingTable,
+		PreUp:                      i.PreUp,
+		PostUp:                     i.PostUp,
+		PreDown:                    i.PreDown,
+		PostDown:                   i.PostDown,
+		PeerDefNetwork:             i.PeerDefNetwork,
+		PeerDefDns:                 i.PeerDefDns,
+		PeerDefDnsSearch:           i.PeerDefDnsSearch,
+		PeerDefEndpoint:            i.PeerDefEndpoint,
+		PeerDefAllowedIps:          i.PeerDefAllowedIPs,
+		PeerDefMtu:                 int32(i.PeerDefMtu),
+		PeerDefPersistentKeepalive: int32(i.PeerDefPersistentKeepalive),
+		PeerDefFirewallMark:        i.PeerDefFirewallMark,
+		PeerDefRoutingTable:        i.PeerDefRoutingTable,
+		PeerDefPreUp:               i.PeerDefPreUp,
+		PeerDefPostUp:              i.PeerDefPostUp,
+		PeerDefPreDown:             i.PeerDefPreDown,
+		PeerDefPostDown:            i.PeerDefPostDown,
+		EnabledPeers:               int32(i.EnabledPeers),
+		TotalPeers:                 int32(i.TotalPeers),
+		Filename:                   i.Filename,
+		Etag:                       etag,
+	}
+}
+
+func newInterfacesWithPeerCounts(
+	src []domain.Interface,
+	counts map[domain.InterfaceIdentifier]domain.PeerCounts,
+) []*pb.Interface {
+	ifaces := models.NewInterfacesWithPeerCounts(src, counts)
+	results := make([]*pb.Interface, len(ifaces))
+	for i := range ifaces {
+		results[i] = newInterfaceFromModel(&ifaces[i], src[i].ETag())
+	}
+
+	return results
+}
+
+func newModelInterface(src *pb.Interface) *models.Interface {
+	return &models.Interface{
+		Identifier:                 src.GetIdentifier(),
+		DisplayName:                src.GetDisplayName(),
+		Mode:                       src.GetMode(),
+		PrivateKey:                 src.GetPrivateKey(),
+		PublicKey:                  src.GetPublicKey(),
+		Disabled:                   src.GetDisabled(),
+		DisabledReason:             src.GetDisabledReason(),
+		SaveConfig:                 src.GetSaveConfig(),
+		ListenPort:                 int(src.GetListenPort()),
+		Addresses:                  src.GetAddresses(),
+		Dns:                        src.GetDns(),
+		DnsSearch:                  src.GetDnsSearch(),
+		Mtu:                        int(src.GetMtu()),
+		FirewallMark:               src.GetFirewallMark(),
+		RoutingTable:               src.GetRoutingTable(),
+		PreUp:                      src.GetPreUp(),
+		PostUp:                     src.GetPostUp(),
+		PreDown:                    src.GetPreDown(),
+		PostDown:                   src.GetPostDown(),
+		PeerDefNetwork:             src.GetPeerDefNetwork(),
+		PeerDefDns:                 src.GetPeerDefDns(),
+		PeerDefDnsSearch:           src.GetPeerDefDnsSearch(),
+		PeerDefEndpoint:            src.GetPeerDefEndpoint(),
+		PeerDefAllowedIPs:          src.GetPeerDefAllowedIps(),
+		PeerDefMtu:                 int(src.GetPeerDefMtu()),
+		PeerDefPersistentKeepalive: int(src.GetPeerDefPersistentKeepalive()),
+		PeerDefFirewallMark:        src.GetPeerDefFirewallMark(),
+		PeerDefRoutingTable:        src.GetPeerDefRoutingTable(),
+		PeerDefPreUp:               src.GetPeerDefPreUp(),
+		PeerDefPostUp:              src.GetPeerDefPostUp(),
+		PeerDefPreDown:             src.GetPeerDefPreDown(),
+		PeerDefPostDown:            src.GetPeerDefPostDown(),
+	}
+}
+
+func newModelWgQuickImportRequest(src *pb.ImportWgQuickRequest) *models.WgQuickImportRequest {
+	req := &models.WgQuickImportRequest{
+		Identifier:       src.GetIdentifier(),
+		Backend:          src.GetBackend(),
+		ServerConfigPath: src.GetServerConfigPath(),
+		ClientConfigDir:  src.GetClientConfigDir(),
+		DryRun:           src.GetDryRun(),
+	}
+	if src.GetServerConfig() != nil {
+		req.ServerConfig = &models.WgQuickFile{
+			Name:    src.GetServerConfig().GetName(),
+			Content: src.GetServerConfig().GetContent(),
+		}
+	}
+	for _, client := range src.GetClientConfigs() {
+		req.ClientConfigs = append(req.ClientConfigs, models.WgQuickFile{
+			Name:    client.GetName(),
+			Content: client.GetContent(),
+		})
+	}
+
+	return req
+}
+
+func newImportWgQuickResponse(src *domain.WgQuickImportPlan) *pb.ImportWgQuickResponse {
+	return &pb.ImportWgQuickResponse{
+		Interface: newInterface(src.Interface, src.Peers),
+		Peers:     newPeers(src.Peers),
+		Conflicts: src.Conflicts,
+		Warnings:  src.Warnings,
+		Committed: src.Committed,
+	}
+}
+
+// endregion interfaces
+
+// region provisioning
+
+func newUserInformation(user *domain.User, peers []domain.Peer) *pb.UserInformation {
+	ui := models.NewUserInformation(user, peers)
+
+	result := &pb.UserInformation{
+		UserIdentifier: ui.UserIdentifier,
+		PeerCount:      int32(ui.PeerCount),
+		Peers:          make([]*pb.UserInformationPeer, len(ui.Peers)),
+	}
+	for i, peer := range ui.Peers {
+		result.Peers[i] = &pb.UserInformationPeer{
+			Identifier:          peer.Identifier,
+			DisplayName:         peer.DisplayName,
+			IpAddresses:         peer.IpAddresses,
+			IsDisabled:          peer.IsDisabled,
+			InterfaceIdentifier: peer.InterfaceIdentifier,
+		}
+	}
+
+	return result
+}
+
+func newModelProvisioningRequest(src *pb.ProvisioningRequest) *models.ProvisioningRequest {
+	return &models.ProvisioningRequest{
+		InterfaceIdentifier: src.GetInterfaceIdentifier(),
+		UserIdentifier:      src.GetUserIdentifier(),
+		DisplayName:         src.GetDisplayName(),
+		PublicKey:           src.GetPublicKey(),
+		PresharedKey:        src.GetPresharedKey(),
+	}
+}
+
+// endregion provisioning
+
+// region metrics
+
+func newPeerMetrics(src *domain.PeerStatus) *pb.PeerMetrics {
+	return newPeerMetricsFromModel(models.NewPeerMetrics(src))
+}
+
+func newPeerMetricsFromModel(m *models.PeerMetrics) *pb.PeerMetrics {
+	return &pb.PeerMetrics{
+		PeerIdentifier:   m.PeerIdentifier,
+		IsPingable:       m.IsPingable,
+		LastPing:         newTimestamp(m.LastPing),
+		BytesReceived:    m.BytesReceived,
+		BytesTransmitted: m.BytesTransmitted,
+		LastHandshake:    newTimestamp(m.LastHandshake),
+		Endpoint:         m.Endpoint,
+		LastSessionStart: newTimestamp(m.LastSessionStart),
+	}
+}
+
+func newInterfaceMetrics(src *domain.InterfaceStatus) *pb.InterfaceMetrics {
+	m := models.NewInterfaceMetrics(src)
+
+	return &pb.InterfaceMetrics{
+		InterfaceIdentifier: m.InterfaceIdentifier,
+		BytesReceived:       m.BytesReceived,
+		BytesTransmitted:    m.BytesTransmitted,
+	}
+}
+
+func newUserMetrics(user *domain.User, peers []domain.PeerStatus) *pb.UserMetrics {
+	m := models.NewUserMetrics(user, peers)
+	if m == nil {
+		return &pb.UserMetrics{}
+	}
+
+	result := &pb.UserMetrics{
+		UserIdentifier:   m.UserIdentifier,
+		PeerCount:        int32(m.PeerCount),
+		BytesReceived:    m.BytesReceived,
+		BytesTransmitted: m.BytesTransmitted,
+		PeerMetrics:      make([]*pb.PeerMetrics, len(m.PeerMetrics)),
+	}
+	for i := range m.PeerMetrics {
+		result.PeerMetrics[i] = newPeerMetricsFromModel(&m.PeerMetrics[i])
+	}
+
+	return result
+}
+
+// endregion metrics
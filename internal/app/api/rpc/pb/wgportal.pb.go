@@ -0,0 +1,3933 @@
+// The gRPC management API of WireGuard Portal. It mirrors the v1 REST API: the services provide the same
+// operations, use the same authentication and enforce the same permissions.
+//
+// Regenerate the Go code with "make codegen-grpc" after changing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: wgportal/v1/wgportal.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ListOptions contains the pagination and sort settings of list requests.
+type ListOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The number of records to skip.
+	Offset int32 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// The maximum number of records, 0 returns all records.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The field to sort by, prefixed with - for descending order, for example -CreatedAt.
+	Sort          string `protobuf:"bytes,3,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOptions) Reset() {
+	*x = ListOptions{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOptions) ProtoMessage() {}
+
+func (x *ListOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOptions.ProtoReflect.Descriptor instead.
+func (*ListOptions) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListOptions) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListOptions) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOptions) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type StringOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Overridable   bool                   `protobuf:"varint,2,opt,name=overridable,proto3" json:"overridable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringOption) Reset() {
+	*x = StringOption{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringOption) ProtoMessage() {}
+
+func (x *StringOption) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringOption.ProtoReflect.Descriptor instead.
+func (*StringOption) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StringOption) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *StringOption) GetOverridable() bool {
+	if x != nil {
+		return x.Overridable
+	}
+	return false
+}
+
+type StringListOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         []string               `protobuf:"bytes,1,rep,name=value,proto3" json:"value,omitempty"`
+	Overridable   bool                   `protobuf:"varint,2,opt,name=overridable,proto3" json:"overridable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringListOption) Reset() {
+	*x = StringListOption{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringListOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringListOption) ProtoMessage() {}
+
+func (x *StringListOption) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringListOption.ProtoReflect.Descriptor instead.
+func (*StringListOption) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StringListOption) GetValue() []string {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *StringListOption) GetOverridable() bool {
+	if x != nil {
+		return x.Overridable
+	}
+	return false
+}
+
+type Int32Option struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int32                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Overridable   bool                   `protobuf:"varint,2,opt,name=overridable,proto3" json:"overridable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Int32Option) Reset() {
+	*x = Int32Option{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Int32Option) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Int32Option) ProtoMessage() {}
+
+func (x *Int32Option) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Int32Option.ProtoReflect.Descriptor instead.
+func (*Int32Option) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Int32Option) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Int32Option) GetOverridable() bool {
+	if x != nil {
+		return x.Overridable
+	}
+	return false
+}
+
+type Uint32Option struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         uint32                 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Overridable   bool                   `protobuf:"varint,2,opt,name=overridable,proto3" json:"overridable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Uint32Option) Reset() {
+	*x = Uint32Option{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Uint32Option) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Uint32Option) ProtoMessage() {}
+
+func (x *Uint32Option) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Uint32Option.ProtoReflect.Descriptor instead.
+func (*Uint32Option) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Uint32Option) GetValue() uint32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Uint32Option) GetOverridable() bool {
+	if x != nil {
+		return x.Overridable
+	}
+	return false
+}
+
+type User struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Identifier string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Email      string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	// The source of the user: db, ldap or oauth.
+	Source       string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	ProviderName string `protobuf:"bytes,4,opt,name=provider_name,json=providerName,proto3" json:"provider_name,omitempty"`
+	IsAdmin      bool   `protobuf:"varint,5,opt,name=is_admin,json=isAdmin,proto3" json:"is_admin,omitempty"`
+	Firstname    string `protobuf:"bytes,6,opt,name=firstname,proto3" json:"firstname,omitempty"`
+	Lastname     string `protobuf:"bytes,7,opt,name=lastname,proto3" json:"lastname,omitempty"`
+	Phone        string `protobuf:"bytes,8,opt,name=phone,proto3" json:"phone,omitempty"`
+	Department   string `protobuf:"bytes,9,opt,name=department,proto3" json:"department,omitempty"`
+	Notes        string `protobuf:"bytes,10,opt,name=notes,proto3" json:"notes,omitempty"`
+	// The password of the user. It is never populated on read operations.
+	Password       string `protobuf:"bytes,11,opt,name=password,proto3" json:"password,omitempty"`
+	Disabled       bool   `protobuf:"varint,12,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	DisabledReason string `protobuf:"bytes,13,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	Locked         bool   `protobuf:"varint,14,opt,name=locked,proto3" json:"locked,omitempty"`
+	LockedReason   string `protobuf:"bytes,15,opt,name=locked_reason,json=lockedReason,proto3" json:"locked_reason,omitempty"`
+	// The API token of the user. It is only populated when a single user is read.
+	ApiToken   string `protobuf:"bytes,16,opt,name=api_token,json=apiToken,proto3" json:"api_token,omitempty"`
+	ApiEnabled bool   `protobuf:"varint,17,opt,name=api_enabled,json=apiEnabled,proto3" json:"api_enabled,omitempty"`
+	PeerCount  int32  `protobuf:"varint,18,opt,name=peer_count,json=peerCount,proto3" json:"peer_count,omitempty"`
+	// The entity tag of the record, pass it as if_match to detect concurrent modifications.
+	Etag          string `protobuf:"bytes,19,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *User) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *User) GetProviderName() string {
+	if x != nil {
+		return x.ProviderName
+	}
+	return ""
+}
+
+func (x *User) GetIsAdmin() bool {
+	if x != nil {
+		return x.IsAdmin
+	}
+	return false
+}
+
+func (x *User) GetFirstname() string {
+	if x != nil {
+		return x.Firstname
+	}
+	return ""
+}
+
+func (x *User) GetLastname() string {
+	if x != nil {
+		return x.Lastname
+	}
+	return ""
+}
+
+func (x *User) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *User) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+func (x *User) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *User) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *User) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *User) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+func (x *User) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *User) GetLockedReason() string {
+	if x != nil {
+		return x.LockedReason
+	}
+	return ""
+}
+
+func (x *User) GetApiToken() string {
+	if x != nil {
+		return x.ApiToken
+	}
+	return ""
+}
+
+func (x *User) GetApiEnabled() bool {
+	if x != nil {
+		return x.ApiEnabled
+	}
+	return false
+}
+
+func (x *User) GetPeerCount() int32 {
+	if x != nil {
+		return x.PeerCount
+	}
+	return 0
+}
+
+func (x *User) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *ListOptions           `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Disabled      *bool                  `protobuf:"varint,2,opt,name=disabled,proto3,oneof" json:"disabled,omitempty"`
+	Admin         *bool                  `protobuf:"varint,3,opt,name=admin,proto3,oneof" json:"admin,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListUsersRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ListUsersRequest) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetAdmin() bool {
+	if x != nil && x.Admin != nil {
+		return *x.Admin
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+type ListUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// The total number of users that match the filters.
+	TotalCount    int32 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetUserRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type UpdateUserRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Identifier string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	User       *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	// If set, the update fails with FAILED_PRECONDITION if the record has been changed since it has been read.
+	IfMatch       string `protobuf:"bytes,3,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateUserRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *UpdateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UpdateUserRequest) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	IfMatch       string                 `protobuf:"bytes,2,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteUserRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *DeleteUserRequest) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+type Peer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The identifier of the peer, it is always equal to the public key of the peer.
+	Identifier          string `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	DisplayName         string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	UserIdentifier      string `protobuf:"bytes,3,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	InterfaceIdentifier string `protobuf:"bytes,4,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	Disabled            bool   `protobuf:"varint,5,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	DisabledReason      string `protobuf:"bytes,6,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	// The expiry date of the peer in YYYY-MM-DD format.
+	ExpiresAt           string            `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Notes               string            `protobuf:"bytes,8,opt,name=notes,proto3" json:"notes,omitempty"`
+	Endpoint            *StringOption     `protobuf:"bytes,9,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	EndpointPublicKey   *StringOption     `protobuf:"bytes,10,opt,name=endpoint_public_key,json=endpointPublicKey,proto3" json:"endpoint_public_key,omitempty"`
+	AllowedIps          *StringListOption `protobuf:"bytes,11,opt,name=allowed_ips,json=allowedIps,proto3" json:"allowed_ips,omitempty"`
+	ExtraAllowedIps     []string          `protobuf:"bytes,12,rep,name=extra_allowed_ips,json=extraAllowedIps,proto3" json:"extra_allowed_ips,omitempty"`
+	PresharedKey        string            `protobuf:"bytes,13,opt,name=preshared_key,json=presharedKey,proto3" json:"preshared_key,omitempty"`
+	PersistentKeepalive *Int32Option      `protobuf:"bytes,14,opt,name=persistent_keepalive,json=persistentKeepalive,proto3" json:"persistent_keepalive,omitempty"`
+	PrivateKey          string            `protobuf:"bytes,15,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	PublicKey           string            `protobuf:"bytes,16,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// The peer interface type: server, client or any.
+	Mode              string            `protobuf:"bytes,17,opt,name=mode,proto3" json:"mode,omitempty"`
+	Addresses         []string          `protobuf:"bytes,18,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	CheckAliveAddress string            `protobuf:"bytes,19,opt,name=check_alive_address,json=checkAliveAddress,proto3" json:"check_alive_address,omitempty"`
+	Dns               *StringListOption `protobuf:"bytes,20,opt,name=dns,proto3" json:"dns,omitempty"`
+	DnsSearch         *StringListOption `protobuf:"bytes,21,opt,name=dns_search,json=dnsSearch,proto3" json:"dns_search,omitempty"`
+	Mtu               *Int32Option      `protobuf:"bytes,22,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	FirewallMark      *Uint32Option     `protobuf:"bytes,23,opt,name=firewall_mark,json=firewallMark,proto3" json:"firewall_mark,omitempty"`
+	RoutingTable      *StringOption     `protobuf:"bytes,24,opt,name=routing_table,json=routingTable,proto3" json:"routing_table,omitempty"`
+	PreUp             *StringOption     `protobuf:"bytes,25,opt,name=pre_up,json=preUp,proto3" json:"pre_up,omitempty"`
+	PostUp            *StringOption     `protobuf:"bytes,26,opt,name=post_up,json=postUp,proto3" json:"post_up,omitempty"`
+	PreDown           *StringOption     `protobuf:"bytes,27,opt,name=pre_down,json=preDown,proto3" json:"pre_down,omitempty"`
+	PostDown          *StringOption     `protobuf:"bytes,28,opt,name=post_down,json=postDown,proto3" json:"post_down,omitempty"`
+	Filename          string            `protobuf:"bytes,29,opt,name=filename,proto3" json:"filename,omitempty"`
+	Etag              string            `protobuf:"bytes,30,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Peer) Reset() {
+	*x = Peer{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Peer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Peer) ProtoMessage() {}
+
+func (x *Peer) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Peer.ProtoReflect.Descriptor instead.
+func (*Peer) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Peer) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *Peer) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Peer) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *Peer) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+func (x *Peer) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *Peer) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+func (x *Peer) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Peer) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Peer) GetEndpoint() *StringOption {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+func (x *Peer) GetEndpointPublicKey() *StringOption {
+	if x != nil {
+		return x.EndpointPublicKey
+	}
+	return nil
+}
+
+func (x *Peer) GetAllowedIps() *StringListOption {
+	if x != nil {
+		return x.AllowedIps
+	}
+	return nil
+}
+
+func (x *Peer) GetExtraAllowedIps() []string {
+	if x != nil {
+		return x.ExtraAllowedIps
+	}
+	return nil
+}
+
+func (x *Peer) GetPresharedKey() string {
+	if x != nil {
+		return x.PresharedKey
+	}
+	return ""
+}
+
+func (x *Peer) GetPersistentKeepalive() *Int32Option {
+	if x != nil {
+		return x.PersistentKeepalive
+	}
+	return nil
+}
+
+func (x *Peer) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+func (x *Peer) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *Peer) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Peer) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Peer) GetCheckAliveAddress() string {
+	if x != nil {
+		return x.CheckAliveAddress
+	}
+	return ""
+}
+
+func (x *Peer) GetDns() *StringListOption {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *Peer) GetDnsSearch() *StringListOption {
+	if x != nil {
+		return x.DnsSearch
+	}
+	return nil
+}
+
+func (x *Peer) GetMtu() *Int32Option {
+	if x != nil {
+		return x.Mtu
+	}
+	return nil
+}
+
+func (x *Peer) GetFirewallMark() *Uint32Option {
+	if x != nil {
+		return x.FirewallMark
+	}
+	return nil
+}
+
+func (x *Peer) GetRoutingTable() *StringOption {
+	if x != nil {
+		return x.RoutingTable
+	}
+	return nil
+}
+
+func (x *Peer) GetPreUp() *StringOption {
+	if x != nil {
+		return x.PreUp
+	}
+	return nil
+}
+
+func (x *Peer) GetPostUp() *StringOption {
+	if x != nil {
+		return x.PostUp
+	}
+	return nil
+}
+
+func (x *Peer) GetPreDown() *StringOption {
+	if x != nil {
+		return x.PreDown
+	}
+	return nil
+}
+
+func (x *Peer) GetPostDown() *StringOption {
+	if x != nil {
+		return x.PostDown
+	}
+	return nil
+}
+
+func (x *Peer) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Peer) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type PeerFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Disabled      *bool                  `protobuf:"varint,1,opt,name=disabled,proto3,oneof" json:"disabled,omitempty"`
+	Expired       *bool                  `protobuf:"varint,2,opt,name=expired,proto3,oneof" json:"expired,omitempty"`
+	Connected     *bool                  `protobuf:"varint,3,opt,name=connected,proto3,oneof" json:"connected,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerFilter) Reset() {
+	*x = PeerFilter{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerFilter) ProtoMessage() {}
+
+func (x *PeerFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerFilter.ProtoReflect.Descriptor instead.
+func (*PeerFilter) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PeerFilter) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *PeerFilter) GetExpired() bool {
+	if x != nil && x.Expired != nil {
+		return *x.Expired
+	}
+	return false
+}
+
+func (x *PeerFilter) GetConnected() bool {
+	if x != nil && x.Connected != nil {
+		return *x.Connected
+	}
+	return false
+}
+
+func (x *PeerFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+type ListInterfacePeersRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InterfaceIdentifier string                 `protobuf:"bytes,1,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	Options             *ListOptions           `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Filter              *PeerFilter            `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ListInterfacePeersRequest) Reset() {
+	*x = ListInterfacePeersRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInterfacePeersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInterfacePeersRequest) ProtoMessage() {}
+
+func (x *ListInterfacePeersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInterfacePeersRequest.ProtoReflect.Descriptor instead.
+func (*ListInterfacePeersRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListInterfacePeersRequest) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+func (x *ListInterfacePeersRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ListInterfacePeersRequest) GetFilter() *PeerFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListUserPeersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserIdentifier string                 `protobuf:"bytes,1,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	Options        *ListOptions           `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Filter         *PeerFilter            `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListUserPeersRequest) Reset() {
+	*x = ListUserPeersRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserPeersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserPeersRequest) ProtoMessage() {}
+
+func (x *ListUserPeersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserPeersRequest.ProtoReflect.Descriptor instead.
+func (*ListUserPeersRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListUserPeersRequest) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *ListUserPeersRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ListUserPeersRequest) GetFilter() *PeerFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type ListPeersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Peers         []*Peer                `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPeersResponse) Reset() {
+	*x = ListPeersResponse{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPeersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeersResponse) ProtoMessage() {}
+
+func (x *ListPeersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeersResponse.ProtoReflect.Descriptor instead.
+func (*ListPeersResponse) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListPeersResponse) GetPeers() []*Peer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *ListPeersResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetPeerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPeerRequest) Reset() {
+	*x = GetPeerRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerRequest) ProtoMessage() {}
+
+func (x *GetPeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerRequest.ProtoReflect.Descriptor instead.
+func (*GetPeerRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetPeerRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+type PreparePeerRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InterfaceIdentifier string                 `protobuf:"bytes,1,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *PreparePeerRequest) Reset() {
+	*x = PreparePeerRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreparePeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreparePeerRequest) ProtoMessage() {}
+
+func (x *PreparePeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreparePeerRequest.ProtoReflect.Descriptor instead.
+func (*PreparePeerRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PreparePeerRequest) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+type CreatePeerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Peer          *Peer                  `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePeerRequest) Reset() {
+	*x = CreatePeerRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePeerRequest) ProtoMessage() {}
+
+func (x *CreatePeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePeerRequest.ProtoReflect.Descriptor instead.
+func (*CreatePeerRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreatePeerRequest) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+type UpdatePeerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Peer          *Peer                  `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	IfMatch       string                 `protobuf:"bytes,3,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePeerRequest) Reset() {
+	*x = UpdatePeerRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePeerRequest) ProtoMessage() {}
+
+func (x *UpdatePeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePeerRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePeerRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdatePeerRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *UpdatePeerRequest) GetPeer() *Peer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+func (x *UpdatePeerRequest) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+type DeletePeerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	IfMatch       string                 `protobuf:"bytes,2,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePeerRequest) Reset() {
+	*x = DeletePeerRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePeerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePeerRequest) ProtoMessage() {}
+
+func (x *DeletePeerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePeerRequest.ProtoReflect.Descriptor instead.
+func (*DeletePeerRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeletePeerRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *DeletePeerRequest) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+type WatchPeerStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// If set, only the events of the given peers are sent.
+	PeerIdentifiers []string `protobuf:"bytes,1,rep,name=peer_identifiers,json=peerIdentifiers,proto3" json:"peer_identifiers,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchPeerStatusRequest) Reset() {
+	*x = WatchPeerStatusRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPeerStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPeerStatusRequest) ProtoMessage() {}
+
+func (x *WatchPeerStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPeerStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchPeerStatusRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchPeerStatusRequest) GetPeerIdentifiers() []string {
+	if x != nil {
+		return x.PeerIdentifiers
+	}
+	return nil
+}
+
+type PeerStatusEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The increasing identifier of the event.
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Connected     bool                   `protobuf:"varint,3,opt,name=connected,proto3" json:"connected,omitempty"`
+	Metrics       *PeerMetrics           `protobuf:"bytes,4,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerStatusEvent) Reset() {
+	*x = PeerStatusEvent{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerStatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerStatusEvent) ProtoMessage() {}
+
+func (x *PeerStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerStatusEvent.ProtoReflect.Descriptor instead.
+func (*PeerStatusEvent) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PeerStatusEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PeerStatusEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *PeerStatusEvent) GetConnected() bool {
+	if x != nil {
+		return x.Connected
+	}
+	return false
+}
+
+func (x *PeerStatusEvent) GetMetrics() *PeerMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type Interface struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The identifier of the interface, it is always equal to the device name.
+	Identifier  string `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// The interface type: server, client or any.
+	Mode                       string   `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	PrivateKey                 string   `protobuf:"bytes,4,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	PublicKey                  string   `protobuf:"bytes,5,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Disabled                   bool     `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	DisabledReason             string   `protobuf:"bytes,7,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	SaveConfig                 bool     `protobuf:"varint,8,opt,name=save_config,json=saveConfig,proto3" json:"save_config,omitempty"`
+	ListenPort                 int32    `protobuf:"varint,9,opt,name=listen_port,json=listenPort,proto3" json:"listen_port,omitempty"`
+	Addresses                  []string `protobuf:"bytes,10,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Dns                        []string `protobuf:"bytes,11,rep,name=dns,proto3" json:"dns,omitempty"`
+	DnsSearch                  []string `protobuf:"bytes,12,rep,name=dns_search,json=dnsSearch,proto3" json:"dns_search,omitempty"`
+	Mtu                        int32    `protobuf:"varint,13,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	FirewallMark               uint32   `protobuf:"varint,14,opt,name=firewall_mark,json=firewallMark,proto3" json:"firewall_mark,omitempty"`
+	RoutingTable               string   `protobuf:"bytes,15,opt,name=routing_table,json=routingTable,proto3" json:"routing_table,omitempty"`
+	PreUp                      string   `protobuf:"bytes,16,opt,name=pre_up,json=preUp,proto3" json:"pre_up,omitempty"`
+	PostUp                     string   `protobuf:"bytes,17,opt,name=post_up,json=postUp,proto3" json:"post_up,omitempty"`
+	PreDown                    string   `protobuf:"bytes,18,opt,name=pre_down,json=preDown,proto3" json:"pre_down,omitempty"`
+	PostDown                   string   `protobuf:"bytes,19,opt,name=post_down,json=postDown,proto3" json:"post_down,omitempty"`
+	PeerDefNetwork             []string `protobuf:"bytes,20,rep,name=peer_def_network,json=peerDefNetwork,proto3" json:"peer_def_network,omitempty"`
+	PeerDefDns                 []string `protobuf:"bytes,21,rep,name=peer_def_dns,json=peerDefDns,proto3" json:"peer_def_dns,omitempty"`
+	PeerDefDnsSearch           []string `protobuf:"bytes,22,rep,name=peer_def_dns_search,json=peerDefDnsSearch,proto3" json:"peer_def_dns_search,omitempty"`
+	PeerDefEndpoint            string   `protobuf:"bytes,23,opt,name=peer_def_endpoint,json=peerDefEndpoint,proto3" json:"peer_def_endpoint,omitempty"`
+	PeerDefAllowedIps          []string `protobuf:"bytes,24,rep,name=peer_def_allowed_ips,json=peerDefAllowedIps,proto3" json:"peer_def_allowed_ips,omitempty"`
+	PeerDefMtu                 int32    `protobuf:"varint,25,opt,name=peer_def_mtu,json=peerDefMtu,proto3" json:"peer_def_mtu,omitempty"`
+	PeerDefPersistentKeepalive int32    `protobuf:"varint,26,opt,name=peer_def_persistent_keepalive,json=peerDefPersistentKeepalive,proto3" json:"peer_def_persistent_keepalive,omitempty"`
+	PeerDefFirewallMark        uint32   `protobuf:"varint,27,opt,name=peer_def_firewall_mark,json=peerDefFirewallMark,proto3" json:"peer_def_firewall_mark,omitempty"`
+	PeerDefRoutingTable        string   `protobuf:"bytes,28,opt,name=peer_def_routing_table,json=peerDefRoutingTable,proto3" json:"peer_def_routing_table,omitempty"`
+	PeerDefPreUp               string   `protobuf:"bytes,29,opt,name=peer_def_pre_up,json=peerDefPreUp,proto3" json:"peer_def_pre_up,omitempty"`
+	PeerDefPostUp              string   `protobuf:"bytes,30,opt,name=peer_def_post_up,json=peerDefPostUp,proto3" json:"peer_def_post_up,omitempty"`
+	PeerDefPreDown             string   `protobuf:"bytes,31,opt,name=peer_def_pre_down,json=peerDefPreDown,proto3" json:"peer_def_pre_down,omitempty"`
+	PeerDefPostDown            string   `protobuf:"bytes,32,opt,name=peer_def_post_down,json=peerDefPostDown,proto3" json:"peer_def_post_down,omitempty"`
+	EnabledPeers               int32    `protobuf:"varint,33,opt,name=enabled_peers,json=enabledPeers,proto3" json:"enabled_peers,omitempty"`
+	TotalPeers                 int32    `protobuf:"varint,34,opt,name=total_peers,json=totalPeers,proto3" json:"total_peers,omitempty"`
+	Filename                   string   `protobuf:"bytes,35,opt,name=filename,proto3" json:"filename,omitempty"`
+	Etag                       string   `protobuf:"bytes,36,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *Interface) Reset() {
+	*x = Interface{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Interface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Interface) ProtoMessage() {}
+
+func (x *Interface) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Interface.ProtoReflect.Descriptor instead.
+func (*Interface) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Interface) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *Interface) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Interface) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *Interface) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+func (x *Interface) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *Interface) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *Interface) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+func (x *Interface) GetSaveConfig() bool {
+	if x != nil {
+		return x.SaveConfig
+	}
+	return false
+}
+
+func (x *Interface) GetListenPort() int32 {
+	if x != nil {
+		return x.ListenPort
+	}
+	return 0
+}
+
+func (x *Interface) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *Interface) GetDns() []string {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *Interface) GetDnsSearch() []string {
+	if x != nil {
+		return x.DnsSearch
+	}
+	return nil
+}
+
+func (x *Interface) GetMtu() int32 {
+	if x != nil {
+		return x.Mtu
+	}
+	return 0
+}
+
+func (x *Interface) GetFirewallMark() uint32 {
+	if x != nil {
+		return x.FirewallMark
+	}
+	return 0
+}
+
+func (x *Interface) GetRoutingTable() string {
+	if x != nil {
+		return x.RoutingTable
+	}
+	return ""
+}
+
+func (x *Interface) GetPreUp() string {
+	if x != nil {
+		return x.PreUp
+	}
+	return ""
+}
+
+func (x *Interface) GetPostUp() string {
+	if x != nil {
+		return x.PostUp
+	}
+	return ""
+}
+
+func (x *Interface) GetPreDown() string {
+	if x != nil {
+		return x.PreDown
+	}
+	return ""
+}
+
+func (x *Interface) GetPostDown() string {
+	if x != nil {
+		return x.PostDown
+	}
+	return ""
+}
+
+func (x *Interface) GetPeerDefNetwork() []string {
+	if x != nil {
+		return x.PeerDefNetwork
+	}
+	return nil
+}
+
+func (x *Interface) GetPeerDefDns() []string {
+	if x != nil {
+		return x.PeerDefDns
+	}
+	return nil
+}
+
+func (x *Interface) GetPeerDefDnsSearch() []string {
+	if x != nil {
+		return x.PeerDefDnsSearch
+	}
+	return nil
+}
+
+func (x *Interface) GetPeerDefEndpoint() string {
+	if x != nil {
+		return x.PeerDefEndpoint
+	}
+	return ""
+}
+
+func (x *Interface) GetPeerDefAllowedIps() []string {
+	if x != nil {
+		return x.PeerDefAllowedIps
+	}
+	return nil
+}
+
+func (x *Interface) GetPeerDefMtu() int32 {
+	if x != nil {
+		return x.PeerDefMtu
+	}
+	return 0
+}
+
+func (x *Interface) GetPeerDefPersistentKeepalive() int32 {
+	if x != nil {
+		return x.PeerDefPersistentKeepalive
+	}
+	return 0
+}
+
+func (x *Interface) GetPeerDefFirewallMark() uint32 {
+	if x != nil {
+		return x.PeerDefFirewallMark
+	}
+	return 0
+}
+
+func (x *Interface) GetPeerDefRoutingTable() string {
+	if x != nil {
+		return x.PeerDefRoutingTable
+	}
+	return ""
+}
+
+func (x *Interface) GetPeerDefPreUp() string {
+	if x != nil {
+		return x.PeerDefPreUp
+	}
+	return ""
+}
+
+func (x *Interface) GetPeerDefPostUp() string {
+	if x != nil {
+		return x.PeerDefPostUp
+	}
+	return ""
+}
+
+func (x *Interface) GetPeerDefPreDown() string {
+	if x != nil {
+		return x.PeerDefPreDown
+	}
+	return ""
+}
+
+func (x *Interface) GetPeerDefPostDown() string {
+	if x != nil {
+		return x.PeerDefPostDown
+	}
+	return ""
+}
+
+func (x *Interface) GetEnabledPeers() int32 {
+	if x != nil {
+		return x.EnabledPeers
+	}
+	return 0
+}
+
+func (x *Interface) GetTotalPeers() int32 {
+	if x != nil {
+		return x.TotalPeers
+	}
+	return 0
+}
+
+func (x *Interface) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Interface) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+type ListInterfacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *ListOptions           `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Disabled      *bool                  `protobuf:"varint,2,opt,name=disabled,proto3,oneof" json:"disabled,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInterfacesRequest) Reset() {
+	*x = ListInterfacesRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInterfacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInterfacesRequest) ProtoMessage() {}
+
+func (x *ListInterfacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInterfacesRequest.ProtoReflect.Descriptor instead.
+func (*ListInterfacesRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListInterfacesRequest) GetOptions() *ListOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ListInterfacesRequest) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *ListInterfacesRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+type ListInterfacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interfaces    []*Interface           `protobuf:"bytes,1,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInterfacesResponse) Reset() {
+	*x = ListInterfacesResponse{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInterfacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInterfacesResponse) ProtoMessage() {}
+
+func (x *ListInterfacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInterfacesResponse.ProtoReflect.Descriptor instead.
+func (*ListInterfacesResponse) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListInterfacesResponse) GetInterfaces() []*Interface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *ListInterfacesResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetInterfaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInterfaceRequest) Reset() {
+	*x = GetInterfaceRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInterfaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInterfaceRequest) ProtoMessage() {}
+
+func (x *GetInterfaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*GetInterfaceRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetInterfaceRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+type CreateInterfaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interface     *Interface             `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateInterfaceRequest) Reset() {
+	*x = CreateInterfaceRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateInterfaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateInterfaceRequest) ProtoMessage() {}
+
+func (x *CreateInterfaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateInterfaceRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CreateInterfaceRequest) GetInterface() *Interface {
+	if x != nil {
+		return x.Interface
+	}
+	return nil
+}
+
+type UpdateInterfaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Interface     *Interface             `protobuf:"bytes,2,opt,name=interface,proto3" json:"interface,omitempty"`
+	IfMatch       string                 `protobuf:"bytes,3,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateInterfaceRequest) Reset() {
+	*x = UpdateInterfaceRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateInterfaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateInterfaceRequest) ProtoMessage() {}
+
+func (x *UpdateInterfaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateInterfaceRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UpdateInterfaceRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *UpdateInterfaceRequest) GetInterface() *Interface {
+	if x != nil {
+		return x.Interface
+	}
+	return nil
+}
+
+func (x *UpdateInterfaceRequest) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+type DeleteInterfaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Identifier    string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	IfMatch       string                 `protobuf:"bytes,2,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteInterfaceRequest) Reset() {
+	*x = DeleteInterfaceRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteInterfaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteInterfaceRequest) ProtoMessage() {}
+
+func (x *DeleteInterfaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteInterfaceRequest.ProtoReflect.Descriptor instead.
+func (*DeleteInterfaceRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DeleteInterfaceRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *DeleteInterfaceRequest) GetIfMatch() string {
+	if x != nil {
+		return x.IfMatch
+	}
+	return ""
+}
+
+type WgQuickFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WgQuickFile) Reset() {
+	*x = WgQuickFile{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WgQuickFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WgQuickFile) ProtoMessage() {}
+
+func (x *WgQuickFile) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WgQuickFile.ProtoReflect.Descriptor instead.
+func (*WgQuickFile) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *WgQuickFile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WgQuickFile) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ImportWgQuickRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Identifier       string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Backend          string                 `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	ServerConfig     *WgQuickFile           `protobuf:"bytes,3,opt,name=server_config,json=serverConfig,proto3" json:"server_config,omitempty"`
+	ServerConfigPath string                 `protobuf:"bytes,4,opt,name=server_config_path,json=serverConfigPath,proto3" json:"server_config_path,omitempty"`
+	ClientConfigs    []*WgQuickFile         `protobuf:"bytes,5,rep,name=client_configs,json=clientConfigs,proto3" json:"client_configs,omitempty"`
+	ClientConfigDir  string                 `protobuf:"bytes,6,opt,name=client_config_dir,json=clientConfigDir,proto3" json:"client_config_dir,omitempty"`
+	DryRun           bool                   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ImportWgQuickRequest) Reset() {
+	*x = ImportWgQuickRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWgQuickRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWgQuickRequest) ProtoMessage() {}
+
+func (x *ImportWgQuickRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWgQuickRequest.ProtoReflect.Descriptor instead.
+func (*ImportWgQuickRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ImportWgQuickRequest) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *ImportWgQuickRequest) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *ImportWgQuickRequest) GetServerConfig() *WgQuickFile {
+	if x != nil {
+		return x.ServerConfig
+	}
+	return nil
+}
+
+func (x *ImportWgQuickRequest) GetServerConfigPath() string {
+	if x != nil {
+		return x.ServerConfigPath
+	}
+	return ""
+}
+
+func (x *ImportWgQuickRequest) GetClientConfigs() []*WgQuickFile {
+	if x != nil {
+		return x.ClientConfigs
+	}
+	return nil
+}
+
+func (x *ImportWgQuickRequest) GetClientConfigDir() string {
+	if x != nil {
+		return x.ClientConfigDir
+	}
+	return ""
+}
+
+func (x *ImportWgQuickRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// ImportWgQuickResponse is the result of a wg-quick import. If conflicts are detected, nothing is stored.
+type ImportWgQuickResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interface     *Interface             `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	Peers         []*Peer                `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	Conflicts     []string               `protobuf:"bytes,3,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Warnings      []string               `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Committed     bool                   `protobuf:"varint,5,opt,name=committed,proto3" json:"committed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportWgQuickResponse) Reset() {
+	*x = ImportWgQuickResponse{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportWgQuickResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportWgQuickResponse) ProtoMessage() {}
+
+func (x *ImportWgQuickResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportWgQuickResponse.ProtoReflect.Descriptor instead.
+func (*ImportWgQuickResponse) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ImportWgQuickResponse) GetInterface() *Interface {
+	if x != nil {
+		return x.Interface
+	}
+	return nil
+}
+
+func (x *ImportWgQuickResponse) GetPeers() []*Peer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *ImportWgQuickResponse) GetConflicts() []string {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *ImportWgQuickResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ImportWgQuickResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+type GetUserInformationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The user identifier, the authenticated user is used if neither user_identifier nor email are set.
+	UserIdentifier string `protobuf:"bytes,1,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	Email          string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetUserInformationRequest) Reset() {
+	*x = GetUserInformationRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserInformationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserInformationRequest) ProtoMessage() {}
+
+func (x *GetUserInformationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserInformationRequest.ProtoReflect.Descriptor instead.
+func (*GetUserInformationRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetUserInformationRequest) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *GetUserInformationRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type UserInformation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserIdentifier string                 `protobuf:"bytes,1,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	PeerCount      int32                  `protobuf:"varint,2,opt,name=peer_count,json=peerCount,proto3" json:"peer_count,omitempty"`
+	Peers          []*UserInformationPeer `protobuf:"bytes,3,rep,name=peers,proto3" json:"peers,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UserInformation) Reset() {
+	*x = UserInformation{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInformation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInformation) ProtoMessage() {}
+
+func (x *UserInformation) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInformation.ProtoReflect.Descriptor instead.
+func (*UserInformation) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UserInformation) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *UserInformation) GetPeerCount() int32 {
+	if x != nil {
+		return x.PeerCount
+	}
+	return 0
+}
+
+func (x *UserInformation) GetPeers() []*UserInformationPeer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type UserInformationPeer struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Identifier          string                 `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	DisplayName         string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	IpAddresses         []string               `protobuf:"bytes,3,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+	IsDisabled          bool                   `protobuf:"varint,4,opt,name=is_disabled,json=isDisabled,proto3" json:"is_disabled,omitempty"`
+	InterfaceIdentifier string                 `protobuf:"bytes,5,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UserInformationPeer) Reset() {
+	*x = UserInformationPeer{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInformationPeer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInformationPeer) ProtoMessage() {}
+
+func (x *UserInformationPeer) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInformationPeer.ProtoReflect.Descriptor instead.
+func (*UserInformationPeer) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UserInformationPeer) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *UserInformationPeer) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UserInformationPeer) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+func (x *UserInformationPeer) GetIsDisabled() bool {
+	if x != nil {
+		return x.IsDisabled
+	}
+	return false
+}
+
+func (x *UserInformationPeer) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+type GetPeerConfigRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PeerIdentifier string                 `protobuf:"bytes,1,opt,name=peer_identifier,json=peerIdentifier,proto3" json:"peer_identifier,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPeerConfigRequest) Reset() {
+	*x = GetPeerConfigRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPeerConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerConfigRequest) ProtoMessage() {}
+
+func (x *GetPeerConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetPeerConfigRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetPeerConfigRequest) GetPeerIdentifier() string {
+	if x != nil {
+		return x.PeerIdentifier
+	}
+	return ""
+}
+
+type PeerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The configuration file in wg-quick format.
+	Config        string `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerConfig) Reset() {
+	*x = PeerConfig{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerConfig) ProtoMessage() {}
+
+func (x *PeerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerConfig.ProtoReflect.Descriptor instead.
+func (*PeerConfig) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PeerConfig) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type PeerQrCode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The QR code of the configuration file as PNG image.
+	Png           []byte `protobuf:"bytes,1,opt,name=png,proto3" json:"png,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PeerQrCode) Reset() {
+	*x = PeerQrCode{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerQrCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerQrCode) ProtoMessage() {}
+
+func (x *PeerQrCode) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerQrCode.ProtoReflect.Descriptor instead.
+func (*PeerQrCode) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PeerQrCode) GetPng() []byte {
+	if x != nil {
+		return x.Png
+	}
+	return nil
+}
+
+type ProvisioningRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InterfaceIdentifier string                 `protobuf:"bytes,1,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	UserIdentifier      string                 `protobuf:"bytes,2,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	DisplayName         string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	PublicKey           string                 `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	PresharedKey        string                 `protobuf:"bytes,5,opt,name=preshared_key,json=presharedKey,proto3" json:"preshared_key,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ProvisioningRequest) Reset() {
+	*x = ProvisioningRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProvisioningRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProvisioningRequest) ProtoMessage() {}
+
+func (x *ProvisioningRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProvisioningRequest.ProtoReflect.Descriptor instead.
+func (*ProvisioningRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ProvisioningRequest) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+func (x *ProvisioningRequest) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *ProvisioningRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ProvisioningRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *ProvisioningRequest) GetPresharedKey() string {
+	if x != nil {
+		return x.PresharedKey
+	}
+	return ""
+}
+
+type PeerMetrics struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PeerIdentifier   string                 `protobuf:"bytes,1,opt,name=peer_identifier,json=peerIdentifier,proto3" json:"peer_identifier,omitempty"`
+	IsPingable       bool                   `protobuf:"varint,2,opt,name=is_pingable,json=isPingable,proto3" json:"is_pingable,omitempty"`
+	LastPing         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_ping,json=lastPing,proto3" json:"last_ping,omitempty"`
+	BytesReceived    uint64                 `protobuf:"varint,4,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	BytesTransmitted uint64                 `protobuf:"varint,5,opt,name=bytes_transmitted,json=bytesTransmitted,proto3" json:"bytes_transmitted,omitempty"`
+	LastHandshake    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_handshake,json=lastHandshake,proto3" json:"last_handshake,omitempty"`
+	Endpoint         string                 `protobuf:"bytes,7,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	LastSessionStart *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_session_start,json=lastSessionStart,proto3" json:"last_session_start,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PeerMetrics) Reset() {
+	*x = PeerMetrics{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeerMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerMetrics) ProtoMessage() {}
+
+func (x *PeerMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerMetrics.ProtoReflect.Descriptor instead.
+func (*PeerMetrics) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PeerMetrics) GetPeerIdentifier() string {
+	if x != nil {
+		return x.PeerIdentifier
+	}
+	return ""
+}
+
+func (x *PeerMetrics) GetIsPingable() bool {
+	if x != nil {
+		return x.IsPingable
+	}
+	return false
+}
+
+func (x *PeerMetrics) GetLastPing() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastPing
+	}
+	return nil
+}
+
+func (x *PeerMetrics) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *PeerMetrics) GetBytesTransmitted() uint64 {
+	if x != nil {
+		return x.BytesTransmitted
+	}
+	return 0
+}
+
+func (x *PeerMetrics) GetLastHandshake() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastHandshake
+	}
+	return nil
+}
+
+func (x *PeerMetrics) GetEndpoint() string {
+	if x != nil {
+		return x.Endpoint
+	}
+	return ""
+}
+
+func (x *PeerMetrics) GetLastSessionStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSessionStart
+	}
+	return nil
+}
+
+type InterfaceMetrics struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InterfaceIdentifier string                 `protobuf:"bytes,1,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	BytesReceived       uint64                 `protobuf:"varint,2,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	BytesTransmitted    uint64                 `protobuf:"varint,3,opt,name=bytes_transmitted,json=bytesTransmitted,proto3" json:"bytes_transmitted,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *InterfaceMetrics) Reset() {
+	*x = InterfaceMetrics{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InterfaceMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterfaceMetrics) ProtoMessage() {}
+
+func (x *InterfaceMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterfaceMetrics.ProtoReflect.Descriptor instead.
+func (*InterfaceMetrics) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *InterfaceMetrics) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+func (x *InterfaceMetrics) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *InterfaceMetrics) GetBytesTransmitted() uint64 {
+	if x != nil {
+		return x.BytesTransmitted
+	}
+	return 0
+}
+
+type UserMetrics struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	UserIdentifier   string                 `protobuf:"bytes,1,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	PeerCount        int32                  `protobuf:"varint,2,opt,name=peer_count,json=peerCount,proto3" json:"peer_count,omitempty"`
+	BytesReceived    uint64                 `protobuf:"varint,3,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	BytesTransmitted uint64                 `protobuf:"varint,4,opt,name=bytes_transmitted,json=bytesTransmitted,proto3" json:"bytes_transmitted,omitempty"`
+	PeerMetrics      []*PeerMetrics         `protobuf:"bytes,5,rep,name=peer_metrics,json=peerMetrics,proto3" json:"peer_metrics,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UserMetrics) Reset() {
+	*x = UserMetrics{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserMetrics) ProtoMessage() {}
+
+func (x *UserMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserMetrics.ProtoReflect.Descriptor instead.
+func (*UserMetrics) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *UserMetrics) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *UserMetrics) GetPeerCount() int32 {
+	if x != nil {
+		return x.PeerCount
+	}
+	return 0
+}
+
+func (x *UserMetrics) GetBytesReceived() uint64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *UserMetrics) GetBytesTransmitted() uint64 {
+	if x != nil {
+		return x.BytesTransmitted
+	}
+	return 0
+}
+
+func (x *UserMetrics) GetPeerMetrics() []*PeerMetrics {
+	if x != nil {
+		return x.PeerMetrics
+	}
+	return nil
+}
+
+type GetInterfaceMetricsRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InterfaceIdentifier string                 `protobuf:"bytes,1,opt,name=interface_identifier,json=interfaceIdentifier,proto3" json:"interface_identifier,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetInterfaceMetricsRequest) Reset() {
+	*x = GetInterfaceMetricsRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInterfaceMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInterfaceMetricsRequest) ProtoMessage() {}
+
+func (x *GetInterfaceMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInterfaceMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetInterfaceMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetInterfaceMetricsRequest) GetInterfaceIdentifier() string {
+	if x != nil {
+		return x.InterfaceIdentifier
+	}
+	return ""
+}
+
+type GetUserMetricsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserIdentifier string                 `protobuf:"bytes,1,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetUserMetricsRequest) Reset() {
+	*x = GetUserMetricsRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserMetricsRequest) ProtoMessage() {}
+
+func (x *GetUserMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetUserMetricsRequest) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+type GetPeerMetricsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PeerIdentifier string                 `protobuf:"bytes,1,opt,name=peer_identifier,json=peerIdentifier,proto3" json:"peer_identifier,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetPeerMetricsRequest) Reset() {
+	*x = GetPeerMetricsRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPeerMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerMetricsRequest) ProtoMessage() {}
+
+func (x *GetPeerMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetPeerMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetPeerMetricsRequest) GetPeerIdentifier() string {
+	if x != nil {
+		return x.PeerIdentifier
+	}
+	return ""
+}
+
+type WatchUserMetricsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserIdentifier string                 `protobuf:"bytes,1,opt,name=user_identifier,json=userIdentifier,proto3" json:"user_identifier,omitempty"`
+	// The update interval in seconds, defaults to 10 seconds.
+	IntervalSeconds uint32 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WatchUserMetricsRequest) Reset() {
+	*x = WatchUserMetricsRequest{}
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUserMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUserMetricsRequest) ProtoMessage() {}
+
+func (x *WatchUserMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wgportal_v1_wgportal_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUserMetricsRequest.ProtoReflect.Descriptor instead.
+func (*WatchUserMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_wgportal_v1_wgportal_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *WatchUserMetricsRequest) GetUserIdentifier() string {
+	if x != nil {
+		return x.UserIdentifier
+	}
+	return ""
+}
+
+func (x *WatchUserMetricsRequest) GetIntervalSeconds() uint32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+var File_wgportal_v1_wgportal_proto protoreflect.FileDescriptor
+
+const file_wgportal_v1_wgportal_proto_rawDesc = "" +
+	"\n" +
+	"\x1awgportal/v1/wgportal.proto\x12\vwgportal.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"O\n" +
+	"\vListOptions\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x05R\x06offset\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\"F\n" +
+	"\fStringOption\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12 \n" +
+	"\voverridable\x18\x02 \x01(\bR\voverridable\"J\n" +
+	"\x10StringListOption\x12\x14\n" +
+	"\x05value\x18\x01 \x03(\tR\x05value\x12 \n" +
+	"\voverridable\x18\x02 \x01(\bR\voverridable\"E\n" +
+	"\vInt32Option\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x05R\x05value\x12 \n" +
+	"\voverridable\x18\x02 \x01(\bR\voverridable\"F\n" +
+	"\fUint32Option\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\rR\x05value\x12 \n" +
+	"\voverridable\x18\x02 \x01(\bR\voverridable\"\xa9\x04\n" +
+	"\x04User\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12#\n" +
+	"\rprovider_name\x18\x04 \x01(\tR\fproviderName\x12\x19\n" +
+	"\bis_admin\x18\x05 \x01(\bR\aisAdmin\x12\x1c\n" +
+	"\tfirstname\x18\x06 \x01(\tR\tfirstname\x12\x1a\n" +
+	"\blastname\x18\a \x01(\tR\blastname\x12\x14\n" +
+	"\x05phone\x18\b \x01(\tR\x05phone\x12\x1e\n" +
+	"\n" +
+	"department\x18\t \x01(\tR\n" +
+	"department\x12\x14\n" +
+	"\x05notes\x18\n" +
+	" \x01(\tR\x05notes\x12\x1a\n" +
+	"\bpassword\x18\v \x01(\tR\bpassword\x12\x1a\n" +
+	"\bdisabled\x18\f \x01(\bR\bdisabled\x12'\n" +
+	"\x0fdisabled_reason\x18\r \x01(\tR\x0edisabledReason\x12\x16\n" +
+	"\x06locked\x18\x0e \x01(\bR\x06locked\x12#\n" +
+	"\rlocked_reason\x18\x0f \x01(\tR\flockedReason\x12\x1b\n" +
+	"\tapi_token\x18\x10 \x01(\tR\bapiToken\x12\x1f\n" +
+	"\vapi_enabled\x18\x11 \x01(\bR\n" +
+	"apiEnabled\x12\x1d\n" +
+	"\n" +
+	"peer_count\x18\x12 \x01(\x05R\tpeerCount\x12\x12\n" +
+	"\x04etag\x18\x13 \x01(\tR\x04etag\"\xda\x01\n" +
+	"\x10ListUsersRequest\x122\n" +
+	"\aoptions\x18\x01 \x01(\v2\x18.wgportal.v1.ListOptionsR\aoptions\x12\x1f\n" +
+	"\bdisabled\x18\x02 \x01(\bH\x00R\bdisabled\x88\x01\x01\x12\x19\n" +
+	"\x05admin\x18\x03 \x01(\bH\x01R\x05admin\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfterB\v\n" +
+	"\t_disabledB\b\n" +
+	"\x06_admin\"]\n" +
+	"\x11ListUsersResponse\x12'\n" +
+	"\x05users\x18\x01 \x03(\v2\x11.wgportal.v1.UserR\x05users\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\"0\n" +
+	"\x0eGetUserRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\":\n" +
+	"\x11CreateUserRequest\x12%\n" +
+	"\x04user\x18\x01 \x01(\v2\x11.wgportal.v1.UserR\x04user\"u\n" +
+	"\x11UpdateUserRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12%\n" +
+	"\x04user\x18\x02 \x01(\v2\x11.wgportal.v1.UserR\x04user\x12\x19\n" +
+	"\bif_match\x18\x03 \x01(\tR\aifMatch\"N\n" +
+	"\x11DeleteUserRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x19\n" +
+	"\bif_match\x18\x02 \x01(\tR\aifMatch\"\xc0\n" +
+	"\n" +
+	"\x04Peer\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12'\n" +
+	"\x0fuser_identifier\x18\x03 \x01(\tR\x0euserIdentifier\x121\n" +
+	"\x14interface_identifier\x18\x04 \x01(\tR\x13interfaceIdentifier\x12\x1a\n" +
+	"\bdisabled\x18\x05 \x01(\bR\bdisabled\x12'\n" +
+	"\x0fdisabled_reason\x18\x06 \x01(\tR\x0edisabledReason\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\tR\texpiresAt\x12\x14\n" +
+	"\x05notes\x18\b \x01(\tR\x05notes\x125\n" +
+	"\bendpoint\x18\t \x01(\v2\x19.wgportal.v1.StringOptionR\bendpoint\x12I\n" +
+	"\x13endpoint_public_key\x18\n" +
+	" \x01(\v2\x19.wgportal.v1.StringOptionR\x11endpointPublicKey\x12>\n" +
+	"\vallowed_ips\x18\v \x01(\v2\x1d.wgportal.v1.StringListOptionR\n" +
+	"allowedIps\x12*\n" +
+	"\x11extra_allowed_ips\x18\f \x03(\tR\x0fextraAllowedIps\x12#\n" +
+	"\rpreshared_key\x18\r \x01(\tR\fpresharedKey\x12K\n" +
+	"\x14persistent_keepalive\x18\x0e \x01(\v2\x18.wgportal.v1.Int32OptionR\x13persistentKeepalive\x12\x1f\n" +
+	"\vprivate_key\x18\x0f \x01(\tR\n" +
+	"privateKey\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x10 \x01(\tR\tpublicKey\x12\x12\n" +
+	"\x04mode\x18\x11 \x01(\tR\x04mode\x12\x1c\n" +
+	"\taddresses\x18\x12 \x03(\tR\taddresses\x12.\n" +
+	"\x13check_alive_address\x18\x13 \x01(\tR\x11checkAliveAddress\x12/\n" +
+	"\x03dns\x18\x14 \x01(\v2\x1d.wgportal.v1.StringListOptionR\x03dns\x12<\n" +
+	"\n" +
+	"dns_search\x18\x15 \x01(\v2\x1d.wgportal.v1.StringListOptionR\tdnsSearch\x12*\n" +
+	"\x03mtu\x18\x16 \x01(\v2\x18.wgportal.v1.Int32OptionR\x03mtu\x12>\n" +
+	"\rfirewall_mark\x18\x17 \x01(\v2\x19.wgportal.v1.Uint32OptionR\ffirewallMark\x12>\n" +
+	"\rrouting_table\x18\x18 \x01(\v2\x19.wgportal.v1.StringOptionR\froutingTable\x120\n" +
+	"\x06pre_up\x18\x19 \x01(\v2\x19.wgportal.v1.StringOptionR\x05preUp\x122\n" +
+	"\apost_up\x18\x1a \x01(\v2\x19.wgportal.v1.StringOptionR\x06postUp\x124\n" +
+	"\bpre_down\x18\x1b \x01(\v2\x19.wgportal.v1.StringOptionR\apreDown\x126\n" +
+	"\tpost_down\x18\x1c \x01(\v2\x19.wgportal.v1.StringOptionR\bpostDown\x12\x1a\n" +
+	"\bfilename\x18\x1d \x01(\tR\bfilename\x12\x12\n" +
+	"\x04etag\x18\x1e \x01(\tR\x04etag\"\xd7\x01\n" +
+	"\n" +
+	"PeerFilter\x12\x1f\n" +
+	"\bdisabled\x18\x01 \x01(\bH\x00R\bdisabled\x88\x01\x01\x12\x1d\n" +
+	"\aexpired\x18\x02 \x01(\bH\x01R\aexpired\x88\x01\x01\x12!\n" +
+	"\tconnected\x18\x03 \x01(\bH\x02R\tconnected\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfterB\v\n" +
+	"\t_disabledB\n" +
+	"\n" +
+	"\b_expiredB\f\n" +
+	"\n" +
+	"_connected\"\xb3\x01\n" +
+	"\x19ListInterfacePeersRequest\x121\n" +
+	"\x14interface_identifier\x18\x01 \x01(\tR\x13interfaceIdentifier\x122\n" +
+	"\aoptions\x18\x02 \x01(\v2\x18.wgportal.v1.ListOptionsR\aoptions\x12/\n" +
+	"\x06filter\x18\x03 \x01(\v2\x17.wgportal.v1.PeerFilterR\x06filter\"\xa4\x01\n" +
+	"\x14ListUserPeersRequest\x12'\n" +
+	"\x0fuser_identifier\x18\x01 \x01(\tR\x0euserIdentifier\x122\n" +
+	"\aoptions\x18\x02 \x01(\v2\x18.wgportal.v1.ListOptionsR\aoptions\x12/\n" +
+	"\x06filter\x18\x03 \x01(\v2\x17.wgportal.v1.PeerFilterR\x06filter\"]\n" +
+	"\x11ListPeersResponse\x12'\n" +
+	"\x05peers\x18\x01 \x03(\v2\x11.wgportal.v1.PeerR\x05peers\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\"0\n" +
+	"\x0eGetPeerRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\"G\n" +
+	"\x12PreparePeerRequest\x121\n" +
+	"\x14interface_identifier\x18\x01 \x01(\tR\x13interfaceIdentifier\":\n" +
+	"\x11CreatePeerRequest\x12%\n" +
+	"\x04peer\x18\x01 \x01(\v2\x11.wgportal.v1.PeerR\x04peer\"u\n" +
+	"\x11UpdatePeerRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12%\n" +
+	"\x04peer\x18\x02 \x01(\v2\x11.wgportal.v1.PeerR\x04peer\x12\x19\n" +
+	"\bif_match\x18\x03 \x01(\tR\aifMatch\"N\n" +
+	"\x11DeletePeerRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x19\n" +
+	"\bif_match\x18\x02 \x01(\tR\aifMatch\"C\n" +
+	"\x16WatchPeerStatusRequest\x12)\n" +
+	"\x10peer_identifiers\x18\x01 \x03(\tR\x0fpeerIdentifiers\"\xa3\x01\n" +
+	"\x0fPeerStatusEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1c\n" +
+	"\tconnected\x18\x03 \x01(\bR\tconnected\x122\n" +
+	"\ametrics\x18\x04 \x01(\v2\x18.wgportal.v1.PeerMetricsR\ametrics\"\x81\n" +
+	"\n" +
+	"\tInterface\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x1f\n" +
+	"\vprivate_key\x18\x04 \x01(\tR\n" +
+	"privateKey\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x05 \x01(\tR\tpublicKey\x12\x1a\n" +
+	"\bdisabled\x18\x06 \x01(\bR\bdisabled\x12'\n" +
+	"\x0fdisabled_reason\x18\a \x01(\tR\x0edisabledReason\x12\x1f\n" +
+	"\vsave_config\x18\b \x01(\bR\n" +
+	"saveConfig\x12\x1f\n" +
+	"\vlisten_port\x18\t \x01(\x05R\n" +
+	"listenPort\x12\x1c\n" +
+	"\taddresses\x18\n" +
+	" \x03(\tR\taddresses\x12\x10\n" +
+	"\x03dns\x18\v \x03(\tR\x03dns\x12\x1d\n" +
+	"\n" +
+	"dns_search\x18\f \x03(\tR\tdnsSearch\x12\x10\n" +
+	"\x03mtu\x18\r \x01(\x05R\x03mtu\x12#\n" +
+	"\rfirewall_mark\x18\x0e \x01(\rR\ffirewallMark\x12#\n" +
+	"\rrouting_table\x18\x0f \x01(\tR\froutingTable\x12\x15\n" +
+	"\x06pre_up\x18\x10 \x01(\tR\x05preUp\x12\x17\n" +
+	"\apost_up\x18\x11 \x01(\tR\x06postUp\x12\x19\n" +
+	"\bpre_down\x18\x12 \x01(\tR\apreDown\x12\x1b\n" +
+	"\tpost_down\x18\x13 \x01(\tR\bpostDown\x12(\n" +
+	"\x10peer_def_network\x18\x14 \x03(\tR\x0epeerDefNetwork\x12 \n" +
+	"\fpeer_def_dns\x18\x15 \x03(\tR\n" +
+	"peerDefDns\x12-\n" +
+	"\x13peer_def_dns_search\x18\x16 \x03(\tR\x10peerDefDnsSearch\x12*\n" +
+	"\x11peer_def_endpoint\x18\x17 \x01(\tR\x0fpeerDefEndpoint\x12/\n" +
+	"\x14peer_def_allowed_ips\x18\x18 \x03(\tR\x11peerDefAllowedIps\x12 \n" +
+	"\fpeer_def_mtu\x18\x19 \x01(\x05R\n" +
+	"peerDefMtu\x12A\n" +
+	"\x1dpeer_def_persistent_keepalive\x18\x1a \x01(\x05R\x1apeerDefPersistentKeepalive\x123\n" +
+	"\x16peer_def_firewall_mark\x18\x1b \x01(\rR\x13peerDefFirewallMark\x123\n" +
+	"\x16peer_def_routing_table\x18\x1c \x01(\tR\x13peerDefRoutingTable\x12%\n" +
+	"\x0fpeer_def_pre_up\x18\x1d \x01(\tR\fpeerDefPreUp\x12'\n" +
+	"\x10peer_def_post_up\x18\x1e \x01(\tR\rpeerDefPostUp\x12)\n" +
+	"\x11peer_def_pre_down\x18\x1f \x01(\tR\x0epeerDefPreDown\x12+\n" +
+	"\x12peer_def_post_down\x18  \x01(\tR\x0fpeerDefPostDown\x12#\n" +
+	"\renabled_peers\x18! \x01(\x05R\fenabledPeers\x12\x1f\n" +
+	"\vtotal_peers\x18\" \x01(\x05R\n" +
+	"totalPeers\x12\x1a\n" +
+	"\bfilename\x18# \x01(\tR\bfilename\x12\x12\n" +
+	"\x04etag\x18$ \x01(\tR\x04etag\"\xba\x01\n" +
+	"\x15ListInterfacesRequest\x122\n" +
+	"\aoptions\x18\x01 \x01(\v2\x18.wgportal.v1.ListOptionsR\aoptions\x12\x1f\n" +
+	"\bdisabled\x18\x02 \x01(\bH\x00R\bdisabled\x88\x01\x01\x12?\n" +
+	"\rcreated_after\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfterB\v\n" +
+	"\t_disabled\"q\n" +
+	"\x16ListInterfacesResponse\x126\n" +
+	"\n" +
+	"interfaces\x18\x01 \x03(\v2\x16.wgportal.v1.InterfaceR\n" +
+	"interfaces\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\"5\n" +
+	"\x13GetInterfaceRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\"N\n" +
+	"\x16CreateInterfaceRequest\x124\n" +
+	"\tinterface\x18\x01 \x01(\v2\x16.wgportal.v1.InterfaceR\tinterface\"\x89\x01\n" +
+	"\x16UpdateInterfaceRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x124\n" +
+	"\tinterface\x18\x02 \x01(\v2\x16.wgportal.v1.InterfaceR\tinterface\x12\x19\n" +
+	"\bif_match\x18\x03 \x01(\tR\aifMatch\"S\n" +
+	"\x16DeleteInterfaceRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x19\n" +
+	"\bif_match\x18\x02 \x01(\tR\aifMatch\";\n" +
+	"\vWgQuickFile\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"\xc3\x02\n" +
+	"\x14ImportWgQuickRequest\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12\x18\n" +
+	"\abackend\x18\x02 \x01(\tR\abackend\x12=\n" +
+	"\rserver_config\x18\x03 \x01(\v2\x18.wgportal.v1.WgQuickFileR\fserverConfig\x12,\n" +
+	"\x12server_config_path\x18\x04 \x01(\tR\x10serverConfigPath\x12?\n" +
+	"\x0eclient_configs\x18\x05 \x03(\v2\x18.wgportal.v1.WgQuickFileR\rclientConfigs\x12*\n" +
+	"\x11client_config_dir\x18\x06 \x01(\tR\x0fclientConfigDir\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\"\xce\x01\n" +
+	"\x15ImportWgQuickResponse\x124\n" +
+	"\tinterface\x18\x01 \x01(\v2\x16.wgportal.v1.InterfaceR\tinterface\x12'\n" +
+	"\x05peers\x18\x02 \x03(\v2\x11.wgportal.v1.PeerR\x05peers\x12\x1c\n" +
+	"\tconflicts\x18\x03 \x03(\tR\tconflicts\x12\x1a\n" +
+	"\bwarnings\x18\x04 \x03(\tR\bwarnings\x12\x1c\n" +
+	"\tcommitted\x18\x05 \x01(\bR\tcommitted\"Z\n" +
+	"\x19GetUserInformationRequest\x12'\n" +
+	"\x0fuser_identifier\x18\x01 \x01(\tR\x0euserIdentifier\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\"\x91\x01\n" +
+	"\x0fUserInformation\x12'\n" +
+	"\x0fuser_identifier\x18\x01 \x01(\tR\x0euserIdentifier\x12\x1d\n" +
+	"\n" +
+	"peer_count\x18\x02 \x01(\x05R\tpeerCount\x126\n" +
+	"\x05peers\x18\x03 \x03(\v2 .wgportal.v1.UserInformationPeerR\x05peers\"\xcf\x01\n" +
+	"\x13UserInformationPeer\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x01 \x01(\tR\n" +
+	"identifier\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12!\n" +
+	"\fip_addresses\x18\x03 \x03(\tR\vipAddresses\x12\x1f\n" +
+	"\vis_disabled\x18\x04 \x01(\bR\n" +
+	"isDisabled\x121\n" +
+	"\x14interface_identifier\x18\x05 \x01(\tR\x13interfaceIdentifier\"?\n" +
+	"\x14GetPeerConfigRequest\x12'\n" +
+	"\x0fpeer_identifier\x18\x01 \x01(\tR\x0epeerIdentifier\"$\n" +
+	"\n" +
+	"PeerConfig\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\"\x1e\n" +
+	"\n" +
+	"PeerQrCode\x12\x10\n" +
+	"\x03png\x18\x01 \x01(\fR\x03png\"\xd8\x01\n" +
+	"\x13ProvisioningRequest\x121\n" +
+	"\x14interface_identifier\x18\x01 \x01(\tR\x13interfaceIdentifier\x12'\n" +
+	"\x0fuser_identifier\x18\x02 \x01(\tR\x0euserIdentifier\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x12#\n" +
+	"\rpreshared_key\x18\x05 \x01(\tR\fpresharedKey\"\x8d\x03\n" +
+	"\vPeerMetrics\x12'\n" +
+	"\x0fpeer_identifier\x18\x01 \x01(\tR\x0epeerIdentifier\x12\x1f\n" +
+	"\vis_pingable\x18\x02 \x01(\bR\n" +
+	"isPingable\x127\n" +
+	"\tlast_ping\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\blastPing\x12%\n" +
+	"\x0ebytes_received\x18\x04 \x01(\x04R\rbytesReceived\x12+\n" +
+	"\x11bytes_transmitted\x18\x05 \x01(\x04R\x10bytesTransmitted\x12A\n" +
+	"\x0elast_handshake\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rlastHandshake\x12\x1a\n" +
+	"\bendpoint\x18\a \x01(\tR\bendpoint\x12H\n" +
+	"\x12last_session_start\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x10lastSessionStart\"\x99\x01\n" +
+	"\x10InterfaceMetrics\x121\n" +
+	"\x14interface_identifier\x18\x01 \x01(\tR\x13interfaceIdentifier\x12%\n" +
+	"\x0ebytes_received\x18\x02 \x01(\x04R\rbytesReceived\x12+\n" +
+	"\x11bytes_transmitted\x18\x03 \x01(\x04R\x10bytesTransmitted\"\xe6\x01\n" +
+	"\vUserMetrics\x12'\n" +
+	"\x0fuser_identifier\x18\x01 \x01(\tR\x0euserIdentifier\x12\x1d\n" +
+	"\n" +
+	"peer_count\x18\x02 \x01(\x05R\tpeerCount\x12%\n" +
+	"\x0ebytes_received\x18\x03 \x01(\x04R\rbytesReceived\x12+\n" +
+	"\x11bytes_transmitted\x18\x04 \x01(\x04R\x10bytesTransmitted\x12;\n" +
+	"\fpeer_metrics\x18\x05 \x03(\v2\x18.wgportal.v1.PeerMetricsR\vpeerMetrics\"O\n" +
+	"\x1aGetInterfaceMetricsRequest\x121\n" +
+	"\x14interface_identifier\x18\x01 \x01(\tR\x13interfaceIdentifier\"@\n" +
+	"\x15GetUserMetricsRequest\x12'\n" +
+	"\x0fuser_identifier\x18\x01 \x01(\tR\x0euserIdentifier\"@\n" +
+	"\x15GetPeerMetricsRequest\x12'\n" +
+	"\x0fpeer_identifier\x18\x01 \x01(\tR\x0epeerIdentifier\"m\n" +
+	"\x17WatchUserMetricsRequest\x12'\n" +
+	"\x0fuser_identifier\x18\x01 \x01(\tR\x0euserIdentifier\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\rR\x0fintervalSeconds2\xdc\x02\n" +
+	"\vUserService\x12J\n" +
+	"\tListUsers\x12\x1d.wgportal.v1.ListUsersRequest\x1a\x1e.wgportal.v1.ListUsersResponse\x129\n" +
+	"\aGetUser\x12\x1b.wgportal.v1.GetUserRequest\x1a\x11.wgportal.v1.User\x12?\n" +
+	"\n" +
+	"CreateUser\x12\x1e.wgportal.v1.CreateUserRequest\x1a\x11.wgportal.v1.User\x12?\n" +
+	"\n" +
+	"UpdateUser\x12\x1e.wgportal.v1.UpdateUserRequest\x1a\x11.wgportal.v1.User\x12D\n" +
+	"\n" +
+	"DeleteUser\x12\x1e.wgportal.v1.DeleteUserRequest\x1a\x16.google.protobuf.Empty2\xdd\x04\n" +
+	"\vPeerService\x12\\\n" +
+	"\x12ListInterfacePeers\x12&.wgportal.v1.ListInterfacePeersRequest\x1a\x1e.wgportal.v1.ListPeersResponse\x12R\n" +
+	"\rListUserPeers\x12!.wgportal.v1.ListUserPeersRequest\x1a\x1e.wgportal.v1.ListPeersResponse\x129\n" +
+	"\aGetPeer\x12\x1b.wgportal.v1.GetPeerRequest\x1a\x11.wgportal.v1.Peer\x12A\n" +
+	"\vPreparePeer\x12\x1f.wgportal.v1.PreparePeerRequest\x1a\x11.wgportal.v1.Peer\x12?\n" +
+	"\n" +
+	"CreatePeer\x12\x1e.wgportal.v1.CreatePeerRequest\x1a\x11.wgportal.v1.Peer\x12?\n" +
+	"\n" +
+	"UpdatePeer\x12\x1e.wgportal.v1.UpdatePeerRequest\x1a\x11.wgportal.v1.Peer\x12D\n" +
+	"\n" +
+	"DeletePeer\x12\x1e.wgportal.v1.DeletePeerRequest\x1a\x16.google.protobuf.Empty\x12V\n" +
+	"\x0fWatchPeerStatus\x12#.wgportal.v1.WatchPeerStatusRequest\x1a\x1c.wgportal.v1.PeerStatusEvent0\x012\xc3\x04\n" +
+	"\x10InterfaceService\x12Y\n" +
+	"\x0eListInterfaces\x12\".wgportal.v1.ListInterfacesRequest\x1a#.wgportal.v1.ListInterfacesResponse\x12H\n" +
+	"\fGetInterface\x12 .wgportal.v1.GetInterfaceRequest\x1a\x16.wgportal.v1.Interface\x12B\n" +
+	"\x10PrepareInterface\x12\x16.google.protobuf.Empty\x1a\x16.wgportal.v1.Interface\x12N\n" +
+	"\x0fCreateInterface\x12#.wgportal.v1.CreateInterfaceRequest\x1a\x16.wgportal.v1.Interface\x12N\n" +
+	"\x0fUpdateInterface\x12#.wgportal.v1.UpdateInterfaceRequest\x1a\x16.wgportal.v1.Interface\x12N\n" +
+	"\x0fDeleteInterface\x12#.wgportal.v1.DeleteInterfaceRequest\x1a\x16.google.protobuf.Empty\x12V\n" +
+	"\rImportWgQuick\x12!.wgportal.v1.ImportWgQuickRequest\x1a\".wgportal.v1.ImportWgQuickResponse2\xce\x02\n" +
+	"\x13ProvisioningService\x12Z\n" +
+	"\x12GetUserInformation\x12&.wgportal.v1.GetUserInformationRequest\x1a\x1c.wgportal.v1.UserInformation\x12K\n" +
+	"\rGetPeerConfig\x12!.wgportal.v1.GetPeerConfigRequest\x1a\x17.wgportal.v1.PeerConfig\x12K\n" +
+	"\rGetPeerQrCode\x12!.wgportal.v1.GetPeerConfigRequest\x1a\x17.wgportal.v1.PeerQrCode\x12A\n" +
+	"\n" +
+	"CreatePeer\x12 .wgportal.v1.ProvisioningRequest\x1a\x11.wgportal.v1.Peer2\xe5\x02\n" +
+	"\x0eMetricsService\x12]\n" +
+	"\x13GetInterfaceMetrics\x12'.wgportal.v1.GetInterfaceMetricsRequest\x1a\x1d.wgportal.v1.InterfaceMetrics\x12N\n" +
+	"\x0eGetUserMetrics\x12\".wgportal.v1.GetUserMetricsRequest\x1a\x18.wgportal.v1.UserMetrics\x12N\n" +
+	"\x0eGetPeerMetrics\x12\".wgportal.v1.GetPeerMetricsRequest\x1a\x18.wgportal.v1.PeerMetrics\x12T\n" +
+	"\x10WatchUserMetrics\x12$.wgportal.v1.WatchUserMetricsRequest\x1a\x18.wgportal.v1.UserMetrics0\x01B5Z3github.com/biezax/wg-portal/internal/app/api/rpc/pbb\x06proto3"
+
+var (
+	file_wgportal_v1_wgportal_proto_rawDescOnce sync.Once
+	file_wgportal_v1_wgportal_proto_rawDescData []byte
+)
+
+func file_wgportal_v1_wgportal_proto_rawDescGZIP() []byte {
+	file_wgportal_v1_wgportal_proto_rawDescOnce.Do(func() {
+		file_wgportal_v1_wgportal_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_wgportal_v1_wgportal_proto_rawDesc), len(file_wgportal_v1_wgportal_proto_rawDesc)))
+	})
+	return file_wgportal_v1_wgportal_proto_rawDescData
+}
+
+var file_wgportal_v1_wgportal_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
+var file_wgportal_v1_wgportal_proto_goTypes = []any{
+	(*ListOptions)(nil),                // 0: wgportal.v1.ListOptions
+	(*StringOption)(nil),               // 1: wgportal.v1.StringOption
+	(*StringListOption)(nil),           // 2: wgportal.v1.StringListOption
+	(*Int32Option)(nil),                // 3: wgportal.v1.Int32Option
+	(*Uint32Option)(nil),               // 4: wgportal.v1.Uint32Option
+	(*User)(nil),                       // 5: wgportal.v1.User
+	(*ListUsersRequest)(nil),           // 6: wgportal.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),          // 7: wgportal.v1.ListUsersResponse
+	(*GetUserRequest)(nil),             // 8: wgportal.v1.GetUserRequest
+	(*CreateUserRequest)(nil),          // 9: wgportal.v1.CreateUserRequest
+	(*UpdateUserRequest)(nil),          // 10: wgportal.v1.UpdateUserRequest
+	(*DeleteUserRequest)(nil),          // 11: wgportal.v1.DeleteUserRequest
+	(*Peer)(nil),                       // 12: wgportal.v1.Peer
+	(*PeerFilter)(nil),                 // 13: wgportal.v1.PeerFilter
+	(*ListInterfacePeersRequest)(nil),  // 14: wgportal.v1.ListInterfacePeersRequest
+	(*ListUserPeersRequest)(nil),       // 15: wgportal.v1.ListUserPeersRequest
+	(*ListPeersResponse)(nil),          // 16: wgportal.v1.ListPeersResponse
+	(*GetPeerRequest)(nil),             // 17: wgportal.v1.GetPeerRequest
+	(*PreparePeerRequest)(nil),         // 18: wgportal.v1.PreparePeerRequest
+	(*CreatePeerRequest)(nil),          // 19: wgportal.v1.CreatePeerRequest
+	(*UpdatePeerRequest)(nil),          // 20: wgportal.v1.UpdatePeerRequest
+	(*DeletePeerRequest)(nil),          // 21: wgportal.v1.DeletePeerRequest
+	(*WatchPeerStatusRequest)(nil),     // 22: wgportal.v1.WatchPeerStatusRequest
+	(*PeerStatusEvent)(nil),            // 23: wgportal.v1.PeerStatusEvent
+	(*Interface)(nil),                  // 24: wgportal.v1.Interface
+	(*ListInterfacesRequest)(nil),      // 25: wgportal.v1.ListInterfacesRequest
+	(*ListInterfacesResponse)(nil),     // 26: wgportal.v1.ListInterfacesResponse
+	(*GetInterfaceRequest)(nil),        // 27: wgportal.v1.GetInterfaceRequest
+	(*CreateInterfaceRequest)(nil),     // 28: wgportal.v1.CreateInterfaceRequest
+	(*UpdateInterfaceRequest)(nil),     // 29: wgportal.v1.UpdateInterfaceRequest
+	(*DeleteInterfaceRequest)(nil),     // 30: wgportal.v1.DeleteInterfaceRequest
+	(*WgQuickFile)(nil),                // 31: wgportal.v1.WgQuickFile
+	(*ImportWgQuickRequest)(nil),       // 32: wgportal.v1.ImportWgQuickRequest
+	(*ImportWgQuickResponse)(nil),      // 33: wgportal.v1.ImportWgQuickResponse
+	(*GetUserInformationRequest)(nil),  // 34: wgportal.v1.GetUserInformationRequest
+	(*UserInformation)(nil),            // 35: wgportal.v1.UserInformation
+	(*UserInformationPeer)(nil),        // 36: wgportal.v1.UserInformationPeer
+	(*GetPeerConfigRequest)(nil),       // 37: wgportal.v1.GetPeerConfigRequest
+	(*PeerConfig)(nil),                 // 38: wgportal.v1.PeerConfig
+	(*PeerQrCode)(nil),                 // 39: wgportal.v1.PeerQrCode
+	(*ProvisioningRequest)(nil),        // 40: wgportal.v1.ProvisioningRequest
+	(*PeerMetrics)(nil),                // 41: wgportal.v1.PeerMetrics
+	(*InterfaceMetrics)(nil),           // 42: wgportal.v1.InterfaceMetrics
+	(*UserMetrics)(nil),                // 43: wgportal.v1.UserMetrics
+	(*GetInterfaceMetricsRequest)(nil), // 44: wgportal.v1.GetInterfaceMetricsRequest
+	(*GetUserMetricsRequest)(nil),      // 45: wgportal.v1.GetUserMetricsRequest
+	(*GetPeerMetricsRequest)(nil),      // 46: wgportal.v1.GetPeerMetricsRequest
+	(*WatchUserMetricsRequest)(nil),    // 47: wgportal.v1.WatchUserMetricsRequest
+	(*timestamppb.Timestamp)(nil),      // 48: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 49: google.protobuf.Empty
+}
+var file_wgportal_v1_wgportal_proto_depIdxs = []int32{
+	0,  // 0: wgportal.v1.ListUsersRequest.options:type_name -> wgportal.v1.ListOptions
+	48, // 1: wgportal.v1.ListUsersRequest.created_after:type_name -> google.protobuf.Timestamp
+	5,  // 2: wgportal.v1.ListUsersResponse.users:type_name -> wgportal.v1.User
+	5,  // 3: wgportal.v1.CreateUserRequest.user:type_name -> wgportal.v1.User
+	5,  // 4: wgportal.v1.UpdateUserRequest.user:type_name -> wgportal.v1.User
+	1,  // 5: wgportal.v1.Peer.endpoint:type_name -> wgportal.v1.StringOption
+	1,  // 6: wgportal.v1.Peer.endpoint_public_key:type_name -> wgportal.v1.StringOption
+	2,  // 7: wgportal.v1.Peer.allowed_ips:type_name -> wgportal.v1.StringListOption
+	3,  // 8: wgportal.v1.Peer.persistent_keepalive:type_name -> wgportal.v1.Int32Option
+	2,  // 9: wgportal.v1.Peer.dns:type_name -> wgportal.v1.StringListOption
+	2,  // 10: wgportal.v1.Peer.dns_search:type_name -> wgportal.v1.StringListOption
+	3,  // 11: wgportal.v1.Peer.mtu:type_name -> wgportal.v1.Int32Option
+	4,  // 12: wgportal.v1.Peer.firewall_mark:type_name -> wgportal.v1.Uint32Option
+	1,  // 13: wgportal.v1.Peer.routing_table:type_name -> wgportal.v1.StringOption
+	1,  // 14: wgportal.v1.Peer.pre_up:type_name -> wgportal.v1.StringOption
+	1,  // 15: wgportal.v1.Peer.post_up:type_name -> wgportal.v1.StringOption
+	1,  // 16: wgportal.v1.Peer.pre_down:type_name -> wgportal.v1.StringOption
+	1,  // 17: wgportal.v1.Peer.post_down:type_name -> wgportal.v1.StringOption
+	48, // 18: wgportal.v1.PeerFilter.created_after:type_name -> google.protobuf.Timestamp
+	0,  // 19: wgportal.v1.ListInterfacePeersRequest.options:type_name -> wgportal.v1.ListOptions
+	13, // 20: wgportal.v1.ListInterfacePeersRequest.filter:type_name -> wgportal.v1.PeerFilter
+	0,  // 21: wgportal.v1.ListUserPeersRequest.options:type_name -> wgportal.v1.ListOptions
+	13, // 22: wgportal.v1.ListUserPeersRequest.filter:type_name -> wgportal.v1.PeerFilter
+	12, // 23: wgportal.v1.ListPeersResponse.peers:type_name -> wgportal.v1.Peer
+	12, // 24: wgportal.v1.CreatePeerRequest.peer:type_name -> wgportal.v1.Peer
+	12, // 25: wgportal.v1.UpdatePeerRequest.peer:type_name -> wgportal.v1.Peer
+	48, // 26: wgportal.v1.PeerStatusEvent.time:type_name -> google.protobuf.Timestamp
+	41, // 27: wgportal.v1.PeerStatusEvent.metrics:type_name -> wgportal.v1.PeerMetrics
+	0,  // 28: wgportal.v1.ListInterfacesRequest.options:type_name -> wgportal.v1.ListOptions
+	48, // 29: wgportal.v1.ListInterfacesRequest.created_after:type_name -> google.protobuf.Timestamp
+	24, // 30: wgportal.v1.ListInterfacesResponse.interfaces:type_name -> wgportal.v1.Interface
+	24, // 31: wgportal.v1.CreateInterfaceRequest.interface:type_name -> wgportal.v1.Interface
+	24, // 32: wgportal.v1.UpdateInterfaceRequest.interface:type_name -> wgportal.v1.Interface
+	31, // 33: wgportal.v1.ImportWgQuickRequest.server_config:type_name -> wgportal.v1.WgQuickFile
+	31, // 34: wgportal.v1.ImportWgQuickRequest.client_configs:type_name -> wgportal.v1.WgQuickFile
+	24, // 35: wgportal.v1.ImportWgQuickResponse.interface:type_name -> wgportal.v1.Interface
+	12, // 36: wgportal.v1.ImportWgQuickResponse.peers:type_name -> wgportal.v1.Peer
+	36, // 37: wgportal.v1.UserInformation.peers:type_name -> wgportal.v1.UserInformationPeer
+	48, // 38: wgportal.v1.PeerMetrics.last_ping:type_name -> google.protobuf.Timestamp
+	48, // 39: wgportal.v1.PeerMetrics.last_handshake:type_name -> google.protobuf.Timestamp
+	48, // 40: wgportal.v1.PeerMetrics.last_session_start:type_name -> google.protobuf.Timestamp
+	41, // 41: wgportal.v1.UserMetrics.peer_metrics:type_name -> wgportal.v1.PeerMetrics
+	6,  // 42: wgportal.v1.UserService.ListUsers:input_type -> wgportal.v1.ListUsersRequest
+	8,  // 43: wgportal.v1.UserService.GetUser:input_type -> wgportal.v1.GetUserRequest
+	9,  // 44: wgportal.v1.UserService.CreateUser:input_type -> wgportal.v1.CreateUserRequest
+	10, // 45: wgportal.v1.UserService.UpdateUser:input_type -> wgportal.v1.UpdateUserRequest
+	11, // 46: wgportal.v1.UserService.DeleteUser:input_type -> wgportal.v1.DeleteUserRequest
+	14, // 47: wgportal.v1.PeerService.ListInterfacePeers:input_type -> wgportal.v1.ListInterfacePeersRequest
+	15, // 48: wgportal.v1.PeerService.ListUserPeers:input_type -> wgportal.v1.ListUserPeersRequest
+	17, // 49: wgportal.v1.PeerService.GetPeer:input_type -> wgportal.v1.GetPeerRequest
+	18, // 50: wgportal.v1.PeerService.PreparePeer:input_type -> wgportal.v1.PreparePeerRequest
+	19, // 51: wgportal.v1.PeerService.CreatePeer:input_type -> wgportal.v1.CreatePeerRequest
+	20, // 52: wgportal.v1.PeerService.UpdatePeer:input_type -> wgportal.v1.UpdatePeerRequest
+	21, // 53: wgportal.v1.PeerService.DeletePeer:input_type -> wgportal.v1.DeletePeerRequest
+	22, // 54: wgportal.v1.PeerService.WatchPeerStatus:input_type -> wgportal.v1.WatchPeerStatusRequest
+	25, // 55: wgportal.v1.InterfaceService.ListInterfaces:input_type -> wgportal.v1.ListInterfacesRequest
+	27, // 56: wgportal.v1.InterfaceService.GetInterface:input_type -> wgportal.v1.GetInterfaceRequest
+	49, // 57: wgportal.v1.InterfaceService.PrepareInterface:input_type -> google.protobuf.Empty
+	28, // 58: wgportal.v1.InterfaceService.CreateInterface:input_type -> wgportal.v1.CreateInterfaceRequest
+	29, // 59: wgportal.v1.InterfaceService.UpdateInterface:input_type -> wgportal.v1.UpdateInterfaceRequest
+	30, // 60: wgportal.v1.InterfaceService.DeleteInterface:input_type -> wgportal.v1.DeleteInterfaceRequest
+	32, // 61: wgportal.v1.InterfaceService.ImportWgQuick:input_type -> wgportal.v1.ImportWgQuickRequest
+	34, // 62: wgportal.v1.ProvisioningService.GetUserInformation:input_type -> wgportal.v1.GetUserInformationRequest
+	37, // 63: wgportal.v1.ProvisioningService.GetPeerConfig:input_type -> wgportal.v1.GetPeerConfigRequest
+	37, // 64: wgportal.v1.ProvisioningService.GetPeerQrCode:input_type -> wgportal.v1.GetPeerConfigRequest
+	40, // 65: wgportal.v1.ProvisioningService.CreatePeer:input_type -> wgportal.v1.ProvisioningRequest
+	44, // 66: wgportal.v1.MetricsService.GetInterfaceMetrics:input_type -> wgportal.v1.GetInterfaceMetricsRequest
+	45, // 67: wgportal.v1.MetricsService.GetUserMetrics:input_type -> wgportal.v1.GetUserMetricsRequest
+	46, // 68: wgportal.v1.MetricsService.GetPeerMetrics:input_type -> wgportal.v1.GetPeerMetricsRequest
+	47, // 69: wgportal.v1.MetricsService.WatchUserMetrics:input_type -> wgportal.v1.WatchUserMetricsRequest
+	7,  // 70: wgportal.v1.UserService.ListUsers:output_type -> wgportal.v1.ListUsersResponse
+	5,  // 71: wgportal.v1.UserService.GetUser:output_type -> wgportal.v1.User
+	5,  // 72: wgportal.v1.UserService.CreateUser:output_type -> wgportal.v1.User
+	5,  // 73: wgportal.v1.UserService.UpdateUser:output_type -> wgportal.v1.User
+	49, // 74: wgportal.v1.UserService.DeleteUser:output_type -> google.protobuf.Empty
+	16, // 75: wgportal.v1.PeerService.ListInterfacePeers:output_type -> wgportal.v1.ListPeersResponse
+	16, // 76: wgportal.v1.PeerService.ListUserPeers:output_type -> wgportal.v1.ListPeersResponse
+	12, // 77: wgportal.v1.PeerService.GetPeer:output_type -> wgportal.v1.Peer
+	12, // 78: wgportal.v1.PeerService.PreparePeer:output_type -> wgportal.v1.Peer
+	12, // 79: wgportal.v1.PeerService.CreatePeer:output_type -> wgportal.v1.Peer
+	12, // 80: wgportal.v1.PeerService.UpdatePeer:output_type -> wgportal.v1.Peer
+	49, // 81: wgportal.v1.PeerService.DeletePeer:output_type -> google.protobuf.Empty
+	23, // 82: wgportal.v1.PeerService.WatchPeerStatus:output_type -> wgportal.v1.PeerStatusEvent
+	26, // 83: wgportal.v1.InterfaceService.ListInterfaces:output_type -> wgportal.v1.ListInterfacesResponse
+	24, // 84: wgportal.v1.InterfaceService.GetInterface:output_type -> wgportal.v1.Interface
+	24, // 85: wgportal.v1.InterfaceService.PrepareInterface:output_type -> wgportal.v1.Interface
+	24, // 86: wgportal.v1.InterfaceService.CreateInterface:output_type -> wgportal.v1.Interface
+	24, // 87: wgportal.v1.InterfaceService.UpdateInterface:output_type -> wgportal.v1.Interface
+	49, // 88: wgportal.v1.InterfaceService.DeleteInterface:output_type -> google.protobuf.Empty
+	33, // 89: wgportal.v1.InterfaceService.ImportWgQuick:output_type -> wgportal.v1.ImportWgQuickResponse
+	35, // 90: wgportal.v1.ProvisioningService.GetUserInformation:output_type -> wgportal.v1.UserInformation
+	38, // 91: wgportal.v1.ProvisioningService.GetPeerConfig:output_type -> wgportal.v1.PeerConfig
+	39, // 92: wgportal.v1.ProvisioningService.GetPeerQrCode:output_type -> wgportal.v1.PeerQrCode
+	12, // 93: wgportal.v1.ProvisioningService.CreatePeer:output_type -> wgportal.v1.Peer
+	42, // 94: wgportal.v1.MetricsService.GetInterfaceMetrics:output_type -> wgportal.v1.InterfaceMetrics
+	43, // 95: wgportal.v1.MetricsService.GetUserMetrics:output_type -> wgportal.v1.UserMetrics
+	41, // 96: wgportal.v1.MetricsService.GetPeerMetrics:output_type -> wgportal.v1.PeerMetrics
+	43, // 97: wgportal.v1.MetricsService.WatchUserMetrics:output_type -> wgportal.v1.UserMetrics
+	70, // [70:98] is the sub-list for method output_type
+	42, // [42:70] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
+}
+
+func init() { file_wgportal_v1_wgportal_proto_init() }
+func file_wgportal_v1_wgportal_proto_init() {
+	if File_wgportal_v1_wgportal_proto != nil {
+		return
+	}
+	file_wgportal_v1_wgportal_proto_msgTypes[6].OneofWrappers = []any{}
+	file_wgportal_v1_wgportal_proto_msgTypes[13].OneofWrappers = []any{}
+	file_wgportal_v1_wgportal_proto_msgTypes[25].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wgportal_v1_wgportal_proto_rawDesc), len(file_wgportal_v1_wgportal_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   48,
+			NumExtensions: 0,
+			NumServices:   5,
+		},
+		GoTypes:           file_wgportal_v1_wgportal_proto_goTypes,
+		DependencyIndexes: file_wgportal_v1_wgportal_proto_depIdxs,
+		MessageInfos:      file_wgportal_v1_wgportal_proto_msgTypes,
+	}.Build()
+	File_wgportal_v1_wgportal_proto = out.File
+	file_wgportal_v1_wgportal_proto_goTypes = nil
+	file_wgportal_v1_wgportal_proto_depIdxs = nil
+}
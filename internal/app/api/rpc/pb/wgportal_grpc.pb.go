@@ -0,0 +1,1449 @@
+// The gRPC management API of WireGuard Portal. It mirrors the v1 REST API: the services provide the same
+// operations, use the same authentication and enforce the same permissions.
+//
+// Regenerate the Go code with "make codegen-grpc" after changing this file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: wgportal/v1/wgportal.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserService_ListUsers_FullMethodName  = "/wgportal.v1.UserService/ListUsers"
+	UserService_GetUser_FullMethodName    = "/wgportal.v1.UserService/GetUser"
+	UserService_CreateUser_FullMethodName = "/wgportal.v1.UserService/CreateUser"
+	UserService_UpdateUser_FullMethodName = "/wgportal.v1.UserService/UpdateUser"
+	UserService_DeleteUser_FullMethodName = "/wgportal.v1.UserService/DeleteUser"
+)
+
+// UserServiceClient is the client API for UserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserService manages users. Normal users can only read their own record.
+type UserServiceClient interface {
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type userServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserServiceClient(cc grpc.ClientConnInterface) UserServiceClient {
+	return &userServiceClient{cc}
+}
+
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, UserService_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UserService_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, UserService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserServiceServer is the server API for UserService service.
+// All implementations must embed UnimplementedUserServiceServer
+// for forward compatibility.
+//
+// UserService manages users. Normal users can only read their own record.
+type UserServiceServer interface {
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
+	DeleteUser(context.Context, *DeleteUserRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+// UnimplementedUserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedUserServiceServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUserServiceServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedUserServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserServiceServer will
+// result in compilation errors.
+type UnsafeUserServiceServer interface {
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+func RegisterUserServiceServer(s grpc.ServiceRegistrar, srv UserServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserService_ServiceDesc, srv)
+}
+
+func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wgportal.v1.UserService",
+	HandlerType: (*UserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListUsers",
+			Handler:    _UserService_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UserService_GetUser_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _UserService_CreateUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _UserService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _UserService_DeleteUser_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "wgportal/v1/wgportal.proto",
+}
+
+const (
+	PeerService_ListInterfacePeers_FullMethodName = "/wgportal.v1.PeerService/ListInterfacePeers"
+	PeerService_ListUserPeers_FullMethodName      = "/wgportal.v1.PeerService/ListUserPeers"
+	PeerService_GetPeer_FullMethodName            = "/wgportal.v1.PeerService/GetPeer"
+	PeerService_PreparePeer_FullMethodName        = "/wgportal.v1.PeerService/PreparePeer"
+	PeerService_CreatePeer_FullMethodName         = "/wgportal.v1.PeerService/CreatePeer"
+	PeerService_UpdatePeer_FullMethodName         = "/wgportal.v1.PeerService/UpdatePeer"
+	PeerService_DeletePeer_FullMethodName         = "/wgportal.v1.PeerService/DeletePeer"
+	PeerService_WatchPeerStatus_FullMethodName    = "/wgportal.v1.PeerService/WatchPeerStatus"
+)
+
+// PeerServiceClient is the client API for PeerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PeerService manages peers. Normal users can only read their own peers.
+type PeerServiceClient interface {
+	ListInterfacePeers(ctx context.Context, in *ListInterfacePeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	ListUserPeers(ctx context.Context, in *ListUserPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	GetPeer(ctx context.Context, in *GetPeerRequest, opts ...grpc.CallOption) (*Peer, error)
+	// PreparePeer returns a new peer with default values of the interface. The peer is not stored.
+	PreparePeer(ctx context.Context, in *PreparePeerRequest, opts ...grpc.CallOption) (*Peer, error)
+	CreatePeer(ctx context.Context, in *CreatePeerRequest, opts ...grpc.CallOption) (*Peer, error)
+	UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*Peer, error)
+	DeletePeer(ctx context.Context, in *DeletePeerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// WatchPeerStatus streams connects and disconnects of peers. Admins receive the events of all peers, normal users
+	// only the events of their own peers.
+	WatchPeerStatus(ctx context.Context, in *WatchPeerStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PeerStatusEvent], error)
+}
+
+type peerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeerServiceClient(cc grpc.ClientConnInterface) PeerServiceClient {
+	return &peerServiceClient{cc}
+}
+
+func (c *peerServiceClient) ListInterfacePeers(ctx context.Context, in *ListInterfacePeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPeersResponse)
+	err := c.cc.Invoke(ctx, PeerService_ListInterfacePeers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) ListUserPeers(ctx context.Context, in *ListUserPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPeersResponse)
+	err := c.cc.Invoke(ctx, PeerService_ListUserPeers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) GetPeer(ctx context.Context, in *GetPeerRequest, opts ...grpc.CallOption) (*Peer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Peer)
+	err := c.cc.Invoke(ctx, PeerService_GetPeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) PreparePeer(ctx context.Context, in *PreparePeerRequest, opts ...grpc.CallOption) (*Peer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Peer)
+	err := c.cc.Invoke(ctx, PeerService_PreparePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) CreatePeer(ctx context.Context, in *CreatePeerRequest, opts ...grpc.CallOption) (*Peer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Peer)
+	err := c.cc.Invoke(ctx, PeerService_CreatePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*Peer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Peer)
+	err := c.cc.Invoke(ctx, PeerService_UpdatePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) DeletePeer(ctx context.Context, in *DeletePeerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PeerService_DeletePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerServiceClient) WatchPeerStatus(ctx context.Context, in *WatchPeerStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PeerStatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PeerService_ServiceDesc.Streams[0], PeerService_WatchPeerStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchPeerStatusRequest, PeerStatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_WatchPeerStatusClient = grpc.ServerStreamingClient[PeerStatusEvent]
+
+// PeerServiceServer is the server API for PeerService service.
+// All implementations must embed UnimplementedPeerServiceServer
+// for forward compatibility.
+//
+// PeerService manages peers. Normal users can only read their own peers.
+type PeerServiceServer interface {
+	ListInterfacePeers(context.Context, *ListInterfacePeersRequest) (*ListPeersResponse, error)
+	ListUserPeers(context.Context, *ListUserPeersRequest) (*ListPeersResponse, error)
+	GetPeer(context.Context, *GetPeerRequest) (*Peer, error)
+	// PreparePeer returns a new peer with default values of the interface. The peer is not stored.
+	PreparePeer(context.Context, *PreparePeerRequest) (*Peer, error)
+	CreatePeer(context.Context, *CreatePeerRequest) (*Peer, error)
+	UpdatePeer(context.Context, *UpdatePeerRequest) (*Peer, error)
+	DeletePeer(context.Context, *DeletePeerRequest) (*emptypb.Empty, error)
+	// WatchPeerStatus streams connects and disconnects of peers. Admins receive the events of all peers, normal users
+	// only the events of their own peers.
+	WatchPeerStatus(*WatchPeerStatusRequest, grpc.ServerStreamingServer[PeerStatusEvent]) error
+	mustEmbedUnimplementedPeerServiceServer()
+}
+
+// UnimplementedPeerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPeerServiceServer struct{}
+
+func (UnimplementedPeerServiceServer) ListInterfacePeers(context.Context, *ListInterfacePeersRequest) (*ListPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInterfacePeers not implemented")
+}
+func (UnimplementedPeerServiceServer) ListUserPeers(context.Context, *ListUserPeersRequest) (*ListPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserPeers not implemented")
+}
+func (UnimplementedPeerServiceServer) GetPeer(context.Context, *GetPeerRequest) (*Peer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeer not implemented")
+}
+func (UnimplementedPeerServiceServer) PreparePeer(context.Context, *PreparePeerRequest) (*Peer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreparePeer not implemented")
+}
+func (UnimplementedPeerServiceServer) CreatePeer(context.Context, *CreatePeerRequest) (*Peer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePeer not implemented")
+}
+func (UnimplementedPeerServiceServer) UpdatePeer(context.Context, *UpdatePeerRequest) (*Peer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePeer not implemented")
+}
+func (UnimplementedPeerServiceServer) DeletePeer(context.Context, *DeletePeerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePeer not implemented")
+}
+func (UnimplementedPeerServiceServer) WatchPeerStatus(*WatchPeerStatusRequest, grpc.ServerStreamingServer[PeerStatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPeerStatus not implemented")
+}
+func (UnimplementedPeerServiceServer) mustEmbedUnimplementedPeerServiceServer() {}
+func (UnimplementedPeerServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafePeerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeerServiceServer will
+// result in compilation errors.
+type UnsafePeerServiceServer interface {
+	mustEmbedUnimplementedPeerServiceServer()
+}
+
+func RegisterPeerServiceServer(s grpc.ServiceRegistrar, srv PeerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPeerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PeerService_ServiceDesc, srv)
+}
+
+func _PeerService_ListInterfacePeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInterfacePeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).ListInterfacePeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_ListInterfacePeers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).ListInterfacePeers(ctx, req.(*ListInterfacePeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_ListUserPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).ListUserPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_ListUserPeers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).ListUserPeers(ctx, req.(*ListUserPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_GetPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).GetPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_GetPeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).GetPeer(ctx, req.(*GetPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_PreparePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreparePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).PreparePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_PreparePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).PreparePeer(ctx, req.(*PreparePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_CreatePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).CreatePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_CreatePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).CreatePeer(ctx, req.(*CreatePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_UpdatePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).UpdatePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_UpdatePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).UpdatePeer(ctx, req.(*UpdatePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_DeletePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).DeletePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_DeletePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).DeletePeer(ctx, req.(*DeletePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeerService_WatchPeerStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPeerStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerServiceServer).WatchPeerStatus(m, &grpc.GenericServerStream[WatchPeerStatusRequest, PeerStatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PeerService_WatchPeerStatusServer = grpc.ServerStreamingServer[PeerStatusEvent]
+
+// PeerService_ServiceDesc is the grpc.ServiceDesc for PeerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wgportal.v1.PeerService",
+	HandlerType: (*PeerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListInterfacePeers",
+			Handler:    _PeerService_ListInterfacePeers_Handler,
+		},
+		{
+			MethodName: "ListUserPeers",
+			Handler:    _PeerService_ListUserPeers_Handler,
+		},
+		{
+			MethodName: "GetPeer",
+			Handler:    _PeerService_GetPeer_Handler,
+		},
+		{
+			MethodName: "PreparePeer",
+			Handler:    _PeerService_PreparePeer_Handler,
+		},
+		{
+			MethodName: "CreatePeer",
+			Handler:    _PeerService_CreatePeer_Handler,
+		},
+		{
+			MethodName: "UpdatePeer",
+			Handler:    _PeerService_UpdatePeer_Handler,
+		},
+		{
+			MethodName: "DeletePeer",
+			Handler:    _PeerService_DeletePeer_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPeerStatus",
+			Handler:       _PeerService_WatchPeerStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "wgportal/v1/wgportal.proto",
+}
+
+const (
+	InterfaceService_ListInterfaces_FullMethodName   = "/wgportal.v1.InterfaceService/ListInterfaces"
+	InterfaceService_GetInterface_FullMethodName     = "/wgportal.v1.InterfaceService/GetInterface"
+	InterfaceService_PrepareInterface_FullMethodName = "/wgportal.v1.InterfaceService/PrepareInterface"
+	InterfaceService_CreateInterface_FullMethodName  = "/wgportal.v1.InterfaceService/CreateInterface"
+	InterfaceService_UpdateInterface_FullMethodName  = "/wgportal.v1.InterfaceService/UpdateInterface"
+	InterfaceService_DeleteInterface_FullMethodName  = "/wgportal.v1.InterfaceService/DeleteInterface"
+	InterfaceService_ImportWgQuick_FullMethodName    = "/wgportal.v1.InterfaceService/ImportWgQuick"
+)
+
+// InterfaceServiceClient is the client API for InterfaceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// InterfaceService manages interfaces. It is only available to admins.
+type InterfaceServiceClient interface {
+	ListInterfaces(ctx context.Context, in *ListInterfacesRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error)
+	GetInterface(ctx context.Context, in *GetInterfaceRequest, opts ...grpc.CallOption) (*Interface, error)
+	// PrepareInterface returns a new interface with default values. The interface is not stored.
+	PrepareInterface(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Interface, error)
+	CreateInterface(ctx context.Context, in *CreateInterfaceRequest, opts ...grpc.CallOption) (*Interface, error)
+	UpdateInterface(ctx context.Context, in *UpdateInterfaceRequest, opts ...grpc.CallOption) (*Interface, error)
+	DeleteInterface(ctx context.Context, in *DeleteInterfaceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ImportWgQuick(ctx context.Context, in *ImportWgQuickRequest, opts ...grpc.CallOption) (*ImportWgQuickResponse, error)
+}
+
+type interfaceServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInterfaceServiceClient(cc grpc.ClientConnInterface) InterfaceServiceClient {
+	return &interfaceServiceClient{cc}
+}
+
+func (c *interfaceServiceClient) ListInterfaces(ctx context.Context, in *ListInterfacesRequest, opts ...grpc.CallOption) (*ListInterfacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInterfacesResponse)
+	err := c.cc.Invoke(ctx, InterfaceService_ListInterfaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interfaceServiceClient) GetInterface(ctx context.Context, in *GetInterfaceRequest, opts ...grpc.CallOption) (*Interface, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Interface)
+	err := c.cc.Invoke(ctx, InterfaceService_GetInterface_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interfaceServiceClient) PrepareInterface(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Interface, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Interface)
+	err := c.cc.Invoke(ctx, InterfaceService_PrepareInterface_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interfaceServiceClient) CreateInterface(ctx context.Context, in *CreateInterfaceRequest, opts ...grpc.CallOption) (*Interface, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Interface)
+	err := c.cc.Invoke(ctx, InterfaceService_CreateInterface_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interfaceServiceClient) UpdateInterface(ctx context.Context, in *UpdateInterfaceRequest, opts ...grpc.CallOption) (*Interface, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Interface)
+	err := c.cc.Invoke(ctx, InterfaceService_UpdateInterface_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interfaceServiceClient) DeleteInterface(ctx context.Context, in *DeleteInterfaceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, InterfaceService_DeleteInterface_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *interfaceServiceClient) ImportWgQuick(ctx context.Context, in *ImportWgQuickRequest, opts ...grpc.CallOption) (*ImportWgQuickResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportWgQuickResponse)
+	err := c.cc.Invoke(ctx, InterfaceService_ImportWgQuick_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InterfaceServiceServer is the server API for InterfaceService service.
+// All implementations must embed UnimplementedInterfaceServiceServer
+// for forward compatibility.
+//
+// InterfaceService manages interfaces. It is only available to admins.
+type InterfaceServiceServer interface {
+	ListInterfaces(context.Context, *ListInterfacesRequest) (*ListInterfacesResponse, error)
+	GetInterface(context.Context, *GetInterfaceRequest) (*Interface, error)
+	// PrepareInterface returns a new interface with default values. The interface is not stored.
+	PrepareInterface(context.Context, *emptypb.Empty) (*Interface, error)
+	CreateInterface(context.Context, *CreateInterfaceRequest) (*Interface, error)
+	UpdateInterface(context.Context, *UpdateInterfaceRequest) (*Interface, error)
+	DeleteInterface(context.Context, *DeleteInterfaceRequest) (*emptypb.Empty, error)
+	ImportWgQuick(context.Context, *ImportWgQuickRequest) (*ImportWgQuickResponse, error)
+	mustEmbedUnimplementedInterfaceServiceServer()
+}
+
+// UnimplementedInterfaceServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInterfaceServiceServer struct{}
+
+func (UnimplementedInterfaceServiceServer) ListInterfaces(context.Context, *ListInterfacesRequest) (*ListInterfacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInterfaces not implemented")
+}
+func (UnimplementedInterfaceServiceServer) GetInterface(context.Context, *GetInterfaceRequest) (*Interface, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInterface not implemented")
+}
+func (UnimplementedInterfaceServiceServer) PrepareInterface(context.Context, *emptypb.Empty) (*Interface, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareInterface not implemented")
+}
+func (UnimplementedInterfaceServiceServer) CreateInterface(context.Context, *CreateInterfaceRequest) (*Interface, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInterface not implemented")
+}
+func (UnimplementedInterfaceServiceServer) UpdateInterface(context.Context, *UpdateInterfaceRequest) (*Interface, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateInterface not implemented")
+}
+func (UnimplementedInterfaceServiceServer) DeleteInterface(context.Context, *DeleteInterfaceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInterface not implemented")
+}
+func (UnimplementedInterfaceServiceServer) ImportWgQuick(context.Context, *ImportWgQuickRequest) (*ImportWgQuickResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportWgQuick not implemented")
+}
+func (UnimplementedInterfaceServiceServer) mustEmbedUnimplementedInterfaceServiceServer() {}
+func (UnimplementedInterfaceServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeInterfaceServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InterfaceServiceServer will
+// result in compilation errors.
+type UnsafeInterfaceServiceServer interface {
+	mustEmbedUnimplementedInterfaceServiceServer()
+}
+
+func RegisterInterfaceServiceServer(s grpc.ServiceRegistrar, srv InterfaceServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInterfaceServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InterfaceService_ServiceDesc, srv)
+}
+
+func _InterfaceService_ListInterfaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInterfacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).ListInterfaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_ListInterfaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).ListInterfaces(ctx, req.(*ListInterfacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InterfaceService_GetInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInterfaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).GetInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_GetInterface_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).GetInterface(ctx, req.(*GetInterfaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InterfaceService_PrepareInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).PrepareInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_PrepareInterface_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).PrepareInterface(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InterfaceService_CreateInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInterfaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).CreateInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_CreateInterface_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).CreateInterface(ctx, req.(*CreateInterfaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InterfaceService_UpdateInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateInterfaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).UpdateInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_UpdateInterface_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).UpdateInterface(ctx, req.(*UpdateInterfaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InterfaceService_DeleteInterface_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInterfaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).DeleteInterface(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_DeleteInterface_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).DeleteInterface(ctx, req.(*DeleteInterfaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InterfaceService_ImportWgQuick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportWgQuickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InterfaceServiceServer).ImportWgQuick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InterfaceService_ImportWgQuick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InterfaceServiceServer).ImportWgQuick(ctx, req.(*ImportWgQuickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InterfaceService_ServiceDesc is the grpc.ServiceDesc for InterfaceService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InterfaceService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wgportal.v1.InterfaceService",
+	HandlerType: (*InterfaceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListInterfaces",
+			Handler:    _InterfaceService_ListInterfaces_Handler,
+		},
+		{
+			MethodName: "GetInterface",
+			Handler:    _InterfaceService_GetInterface_Handler,
+		},
+		{
+			MethodName: "PrepareInterface",
+			Handler:    _InterfaceService_PrepareInterface_Handler,
+		},
+		{
+			MethodName: "CreateInterface",
+			Handler:    _InterfaceService_CreateInterface_Handler,
+		},
+		{
+			MethodName: "UpdateInterface",
+			Handler:    _InterfaceService_UpdateInterface_Handler,
+		},
+		{
+			MethodName: "DeleteInterface",
+			Handler:    _InterfaceService_DeleteInterface_Handler,
+		},
+		{
+			MethodName: "ImportWgQuick",
+			Handler:    _InterfaceService_ImportWgQuick_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "wgportal/v1/wgportal.proto",
+}
+
+const (
+	ProvisioningService_GetUserInformation_FullMethodName = "/wgportal.v1.ProvisioningService/GetUserInformation"
+	ProvisioningService_GetPeerConfig_FullMethodName      = "/wgportal.v1.ProvisioningService/GetPeerConfig"
+	ProvisioningService_GetPeerQrCode_FullMethodName      = "/wgportal.v1.ProvisioningService/GetPeerQrCode"
+	ProvisioningService_CreatePeer_FullMethodName         = "/wgportal.v1.ProvisioningService/CreatePeer"
+)
+
+// ProvisioningServiceClient is the client API for ProvisioningService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ProvisioningService provides the peer provisioning operations.
+type ProvisioningServiceClient interface {
+	GetUserInformation(ctx context.Context, in *GetUserInformationRequest, opts ...grpc.CallOption) (*UserInformation, error)
+	GetPeerConfig(ctx context.Context, in *GetPeerConfigRequest, opts ...grpc.CallOption) (*PeerConfig, error)
+	GetPeerQrCode(ctx context.Context, in *GetPeerConfigRequest, opts ...grpc.CallOption) (*PeerQrCode, error)
+	// CreatePeer creates a new peer with default values of the interface. It is only available to admins.
+	CreatePeer(ctx context.Context, in *ProvisioningRequest, opts ...grpc.CallOption) (*Peer, error)
+}
+
+type provisioningServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProvisioningServiceClient(cc grpc.ClientConnInterface) ProvisioningServiceClient {
+	return &provisioningServiceClient{cc}
+}
+
+func (c *provisioningServiceClient) GetUserInformation(ctx context.Context, in *GetUserInformationRequest, opts ...grpc.CallOption) (*UserInformation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserInformation)
+	err := c.cc.Invoke(ctx, ProvisioningService_GetUserInformation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisioningServiceClient) GetPeerConfig(ctx context.Context, in *GetPeerConfigRequest, opts ...grpc.CallOption) (*PeerConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeerConfig)
+	err := c.cc.Invoke(ctx, ProvisioningService_GetPeerConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisioningServiceClient) GetPeerQrCode(ctx context.Context, in *GetPeerConfigRequest, opts ...grpc.CallOption) (*PeerQrCode, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeerQrCode)
+	err := c.cc.Invoke(ctx, ProvisioningService_GetPeerQrCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *provisioningServiceClient) CreatePeer(ctx context.Context, in *ProvisioningRequest, opts ...grpc.CallOption) (*Peer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Peer)
+	err := c.cc.Invoke(ctx, ProvisioningService_CreatePeer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProvisioningServiceServer is the server API for ProvisioningService service.
+// All implementations must embed UnimplementedProvisioningServiceServer
+// for forward compatibility.
+//
+// ProvisioningService provides the peer provisioning operations.
+type ProvisioningServiceServer interface {
+	GetUserInformation(context.Context, *GetUserInformationRequest) (*UserInformation, error)
+	GetPeerConfig(context.Context, *GetPeerConfigRequest) (*PeerConfig, error)
+	GetPeerQrCode(context.Context, *GetPeerConfigRequest) (*PeerQrCode, error)
+	// CreatePeer creates a new peer with default values of the interface. It is only available to admins.
+	CreatePeer(context.Context, *ProvisioningRequest) (*Peer, error)
+	mustEmbedUnimplementedProvisioningServiceServer()
+}
+
+// UnimplementedProvisioningServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProvisioningServiceServer struct{}
+
+func (UnimplementedProvisioningServiceServer) GetUserInformation(context.Context, *GetUserInformationRequest) (*UserInformation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserInformation not implemented")
+}
+func (UnimplementedProvisioningServiceServer) GetPeerConfig(context.Context, *GetPeerConfigRequest) (*PeerConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerConfig not implemented")
+}
+func (UnimplementedProvisioningServiceServer) GetPeerQrCode(context.Context, *GetPeerConfigRequest) (*PeerQrCode, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerQrCode not implemented")
+}
+func (UnimplementedProvisioningServiceServer) CreatePeer(context.Context, *ProvisioningRequest) (*Peer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePeer not implemented")
+}
+func (UnimplementedProvisioningServiceServer) mustEmbedUnimplementedProvisioningServiceServer() {}
+func (UnimplementedProvisioningServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeProvisioningServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProvisioningServiceServer will
+// result in compilation errors.
+type UnsafeProvisioningServiceServer interface {
+	mustEmbedUnimplementedProvisioningServiceServer()
+}
+
+func RegisterProvisioningServiceServer(s grpc.ServiceRegistrar, srv ProvisioningServiceServer) {
+	// If the following call pancis, it indicates UnimplementedProvisioningServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProvisioningService_ServiceDesc, srv)
+}
+
+func _ProvisioningService_GetUserInformation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserInformationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisioningServiceServer).GetUserInformation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProvisioningService_GetUserInformation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisioningServiceServer).GetUserInformation(ctx, req.(*GetUserInformationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisioningService_GetPeerConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisioningServiceServer).GetPeerConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProvisioningService_GetPeerConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisioningServiceServer).GetPeerConfig(ctx, req.(*GetPeerConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisioningService_GetPeerQrCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisioningServiceServer).GetPeerQrCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProvisioningService_GetPeerQrCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisioningServiceServer).GetPeerQrCode(ctx, req.(*GetPeerConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProvisioningService_CreatePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProvisioningRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProvisioningServiceServer).CreatePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProvisioningService_CreatePeer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProvisioningServiceServer).CreatePeer(ctx, req.(*ProvisioningRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProvisioningService_ServiceDesc is the grpc.ServiceDesc for ProvisioningService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProvisioningService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wgportal.v1.ProvisioningService",
+	HandlerType: (*ProvisioningServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserInformation",
+			Handler:    _ProvisioningService_GetUserInformation_Handler,
+		},
+		{
+			MethodName: "GetPeerConfig",
+			Handler:    _ProvisioningService_GetPeerConfig_Handler,
+		},
+		{
+			MethodName: "GetPeerQrCode",
+			Handler:    _ProvisioningService_GetPeerQrCode_Handler,
+		},
+		{
+			MethodName: "CreatePeer",
+			Handler:    _ProvisioningService_CreatePeer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "wgportal/v1/wgportal.proto",
+}
+
+const (
+	MetricsService_GetInterfaceMetrics_FullMethodName = "/wgportal.v1.MetricsService/GetInterfaceMetrics"
+	MetricsService_GetUserMetrics_FullMethodName      = "/wgportal.v1.MetricsService/GetUserMetrics"
+	MetricsService_GetPeerMetrics_FullMethodName      = "/wgportal.v1.MetricsService/GetPeerMetrics"
+	MetricsService_WatchUserMetrics_FullMethodName    = "/wgportal.v1.MetricsService/WatchUserMetrics"
+)
+
+// MetricsServiceClient is the client API for MetricsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MetricsService provides the traffic and connection metrics.
+type MetricsServiceClient interface {
+	GetInterfaceMetrics(ctx context.Context, in *GetInterfaceMetricsRequest, opts ...grpc.CallOption) (*InterfaceMetrics, error)
+	GetUserMetrics(ctx context.Context, in *GetUserMetricsRequest, opts ...grpc.CallOption) (*UserMetrics, error)
+	GetPeerMetrics(ctx context.Context, in *GetPeerMetricsRequest, opts ...grpc.CallOption) (*PeerMetrics, error)
+	// WatchUserMetrics streams the metrics of a user and its peers in the given interval.
+	WatchUserMetrics(ctx context.Context, in *WatchUserMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserMetrics], error)
+}
+
+type metricsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMetricsServiceClient(cc grpc.ClientConnInterface) MetricsServiceClient {
+	return &metricsServiceClient{cc}
+}
+
+func (c *metricsServiceClient) GetInterfaceMetrics(ctx context.Context, in *GetInterfaceMetricsRequest, opts ...grpc.CallOption) (*InterfaceMetrics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InterfaceMetrics)
+	err := c.cc.Invoke(ctx, MetricsService_GetInterfaceMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) GetUserMetrics(ctx context.Context, in *GetUserMetricsRequest, opts ...grpc.CallOption) (*UserMetrics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserMetrics)
+	err := c.cc.Invoke(ctx, MetricsService_GetUserMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) GetPeerMetrics(ctx context.Context, in *GetPeerMetricsRequest, opts ...grpc.CallOption) (*PeerMetrics, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PeerMetrics)
+	err := c.cc.Invoke(ctx, MetricsService_GetPeerMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricsServiceClient) WatchUserMetrics(ctx context.Context, in *WatchUserMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UserMetrics], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MetricsService_ServiceDesc.Streams[0], MetricsService_WatchUserMetrics_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUserMetricsRequest, UserMetrics]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MetricsService_WatchUserMetricsClient = grpc.ServerStreamingClient[UserMetrics]
+
+// MetricsServiceServer is the server API for MetricsService service.
+// All implementations must embed UnimplementedMetricsServiceServer
+// for forward compatibility.
+//
+// MetricsService provides the traffic and connection metrics.
+type MetricsServiceServer interface {
+	GetInterfaceMetrics(context.Context, *GetInterfaceMetricsRequest) (*InterfaceMetrics, error)
+	GetUserMetrics(context.Context, *GetUserMetricsRequest) (*UserMetrics, error)
+	GetPeerMetrics(context.Context, *GetPeerMetricsRequest) (*PeerMetrics, error)
+	// WatchUserMetrics streams the metrics of a user and its peers in the given interval.
+	WatchUserMetrics(*WatchUserMetricsRequest, grpc.ServerStreamingServer[UserMetrics]) error
+	mustEmbedUnimplementedMetricsServiceServer()
+}
+
+// UnimplementedMetricsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMetricsServiceServer struct{}
+
+func (UnimplementedMetricsServiceServer) GetInterfaceMetrics(context.Context, *GetInterfaceMetricsRequest) (*InterfaceMetrics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInterfaceMetrics not implemented")
+}
+func (UnimplementedMetricsServiceServer) GetUserMetrics(context.Context, *GetUserMetricsRequest) (*UserMetrics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserMetrics not implemented")
+}
+func (UnimplementedMetricsServiceServer) GetPeerMetrics(context.Context, *GetPeerMetricsRequest) (*PeerMetrics, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerMetrics not implemented")
+}
+func (UnimplementedMetricsServiceServer) WatchUserMetrics(*WatchUserMetricsRequest, grpc.ServerStreamingServer[UserMetrics]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUserMetrics not implemented")
+}
+func (UnimplementedMetricsServiceServer) mustEmbedUnimplementedMetricsServiceServer() {}
+func (UnimplementedMetricsServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeMetricsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MetricsServiceServer will
+// result in compilation errors.
+type UnsafeMetricsServiceServer interface {
+	mustEmbedUnimplementedMetricsServiceServer()
+}
+
+func RegisterMetricsServiceServer(s grpc.ServiceRegistrar, srv MetricsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMetricsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MetricsService_ServiceDesc, srv)
+}
+
+func _MetricsService_GetInterfaceMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInterfaceMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).GetInterfaceMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_GetInterfaceMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).GetInterfaceMetrics(ctx, req.(*GetInterfaceMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_GetUserMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).GetUserMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_GetUserMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).GetUserMetrics(ctx, req.(*GetUserMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_GetPeerMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricsServiceServer).GetPeerMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetricsService_GetPeerMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricsServiceServer).GetPeerMetrics(ctx, req.(*GetPeerMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricsService_WatchUserMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUserMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetricsServiceServer).WatchUserMetrics(m, &grpc.GenericServerStream[WatchUserMetricsRequest, UserMetrics]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MetricsService_WatchUserMetricsServer = grpc.ServerStreamingServer[UserMetrics]
+
+// MetricsService_ServiceDesc is the grpc.ServiceDesc for MetricsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MetricsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wgportal.v1.MetricsService",
+	HandlerType: (*MetricsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetInterfaceMetrics",
+			Handler:    _MetricsService_GetInterfaceMetrics_Handler,
+		},
+		{
+			MethodName: "GetUserMetrics",
+			Handler:    _MetricsService_GetUserMetrics_Handler,
+		},
+		{
+			MethodName: "GetPeerMetrics",
+			Handler:    _MetricsService_GetPeerMetrics_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUserMetrics",
+			Handler:       _MetricsService_WatchUserMetrics_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "wgportal/v1/wgportal.proto",
+}
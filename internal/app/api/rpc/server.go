@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// To regenerate the Go code of the protocol buffer definitions in the proto directory use the
+// make codegen-grpc
+// command.
+
+type Service interface {
+	// GetName returns the name of the service.
+	GetName() string
+	// Register registers the service implementation at the given gRPC server.
+	Register(s grpc.ServiceRegistrar)
+}
+
+// Server is the gRPC management API server. It exposes the same operations as the v1 REST API.
+type Server struct {
+	cfg    *config.Config
+	server *grpc.Server
+}
+
+// NewServer creates a new gRPC server with reflection enabled. The authenticator verifies the credentials of all
+// calls, it accepts the same credentials as the v1 REST API. The TLS certificate of the web server is used if
+// configured.
+func NewServer(cfg *config.Config, authenticator Authenticator, services ...Service) (*Server, error) {
+	auth := newAuthInterceptor(authenticator)
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(auth.unary),
+		grpc.ChainStreamInterceptor(auth.stream),
+	}
+
+	if cfg.Web.CertFile != "" && cfg.Web.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.Web.CertFile, cfg.Web.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		server: grpc.NewServer(opts...),
+	}
+
+	for _, service := range services {
+		service.Register(s.server)
+		slog.Debug("registered grpc service", "service", service.GetName())
+	}
+	reflection.Register(s.server)
+
+	return s, nil
+}
+
+// Run starts the gRPC server and blocks until the given context is cancelled.
+func (s *Server) Run(ctx context.Context, listenAddress string) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		slog.Error("failed to start grpc service", "address", listenAddress, "error", err)
+		return
+	}
+
+	srvContext, cancelFn := context.WithCancel(ctx)
+	go func() {
+		if err := s.server.Serve(listener); err != nil {
+			slog.Info("grpc service exited", "address", listenAddress, "error", err)
+		}
+		cancelFn()
+	}()
+	slog.Info("started grpc service", "address", listenAddress)
+
+	// Wait for the main context to end
+	<-srvContext.Done()
+
+	slog.Debug("grpc service shutting down, grace period: 5 seconds")
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		s.server.Stop() // streaming calls do not end on their own
+	}
+
+	slog.Debug("grpc service shut down")
+}
+
+// serviceError converts an error of the backend services to a gRPC status error, it is the gRPC counterpart of the
+// ParseServiceError function of the v1 REST API.
+func serviceError(err error) error {
+	if err == nil {
+		return status.Error(codes.Internal, "unknown server error")
+	}
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, domain.ErrNoPermission):
+		code = codes.PermissionDenied
+	case errors.Is(err, domain.ErrDuplicateEntry):
+		code = codes.AlreadyExists
+	case errors.Is(err, domain.ErrInvalidData):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		code = codes.FailedPrecondition
+	}
+
+	return status.Error(code, err.Error())
+}
+
+// invalidArgument returns an InvalidArgument status error, it is used for request validation errors.
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// region service-interfaces
+
+type Validator interface {
+	// Struct validates the given struct.
+	Struct(s interface{}) error
+}
+
+// endregion service-interfaces
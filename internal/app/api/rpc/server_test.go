@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockAuthenticator struct{}
+
+func (m mockAuthenticator) Authenticate(_ context.Context, authorization, _ string) (
+	*domain.User,
+	*domain.ApiToken,
+	error,
+) {
+	switch authorization {
+	case "admin":
+		return &domain.User{Identifier: "admin", IsAdmin: true}, nil, nil
+	case "alice":
+		return &domain.User{Identifier: "alice"}, nil, nil
+	case "read-only":
+		return &domain.User{Identifier: "admin", IsAdmin: true},
+			&domain.ApiToken{Scopes: []domain.ApiTokenScope{domain.ApiTokenScopeReadOnly}}, nil
+	default:
+		return nil, nil, errors.New("invalid credentials")
+	}
+}
+
+type mockUserService struct {
+	UserServerUserService
+}
+
+func (m mockUserService) GetAll(ctx context.Context, _ domain.UserFilter, _ domain.ListOptions) (
+	[]domain.User,
+	int,
+	error,
+) {
+	return []domain.User{{Identifier: domain.GetUserInfo(ctx).Id}}, 1, nil
+}
+
+func (m mockUserService) GetById(_ context.Context, id domain.UserIdentifier) (*domain.User, error) {
+	return nil, errors.Join(errors.New("user "+string(id)+" not found"), domain.ErrNotFound)
+}
+
+type mockValidator struct{}
+
+func (m mockValidator) Struct(interface{}) error { return nil }
+
+func newTestClient(t *testing.T) *grpc.ClientConn {
+	srv, err := NewServer(&config.Config{}, mockAuthenticator{},
+		NewUserServer(mockValidator{}, mockUserService{}))
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = srv.server.Serve(listener) }()
+	t.Cleanup(srv.server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func withAuthorization(authorization string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", authorization)
+}
+
+func TestServer_Authorization(t *testing.T) {
+	client := pb.NewUserServiceClient(newTestClient(t))
+
+	_, err := client.ListUsers(context.Background(), &pb.ListUsersRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "missing credentials")
+
+	_, err = client.ListUsers(withAuthorization("alice"), &pb.ListUsersRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "admin method")
+
+	resp, err := client.ListUsers(withAuthorization("admin"), &pb.ListUsersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resp.TotalCount)
+	assert.Equal(t, "admin", resp.Users[0].Identifier, "user info is stored in the context")
+
+	_, err = client.ListUsers(withAuthorization("read-only"), &pb.ListUsersRequest{})
+	assert.NoError(t, err)
+	_, err = client.DeleteUser(withAuthorization("read-only"), &pb.DeleteUserRequest{Identifier: "alice"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "token scopes are checked")
+}
+
+func TestServer_ServiceErrors(t *testing.T) {
+	client := pb.NewUserServiceClient(newTestClient(t))
+
+	_, err := client.GetUser(withAuthorization("alice"), &pb.GetUserRequest{Identifier: "bob"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetUser(withAuthorization("alice"), &pb.GetUserRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_Reflection(t *testing.T) {
+	client := reflectionpb.NewServerReflectionClient(newTestClient(t))
+
+	stream, err := client.ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err, "reflection is available without credentials")
+
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	assert.Contains(t, services, "wgportal.v1.UserService")
+}
@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type InterfaceServerInterfaceService interface {
+	GetAll(context.Context, domain.InterfaceFilter, domain.ListOptions) (
+		[]domain.Interface, map[domain.InterfaceIdentifier]domain.PeerCounts, int, error)
+	GetById(context.Context, domain.InterfaceIdentifier) (*domain.Interface, []domain.Peer, error)
+	Prepare(context.Context) (*domain.Interface, error)
+	Create(context.Context, *domain.Interface) (*domain.Interface, error)
+	Update(context.Context, domain.InterfaceIdentifier, *domain.Interface) (*domain.Interface, []domain.Peer, error)
+	Delete(context.Context, domain.InterfaceIdentifier) error
+	ImportWgQuick(
+		ctx context.Context,
+		req *domain.WgQuickImportRequest,
+		serverConfigPath, clientConfigDir string,
+		dryRun bool,
+	) (*domain.WgQuickImportPlan, error)
+}
+
+// InterfaceServer implements the InterfaceService of the gRPC API, it mirrors the interface endpoints of the v1
+// REST API.
+type InterfaceServer struct {
+	pb.UnimplementedInterfaceServiceServer
+
+	interfaces InterfaceServerInterfaceService
+	validator  Validator
+}
+
+func NewInterfaceServer(validator Validator, interfaceService InterfaceServerInterfaceService) *InterfaceServer {
+	return &InterfaceServer{
+		validator:  validator,
+		interfaces: interfaceService,
+	}
+}
+
+func (s *InterfaceServer) GetName() string {
+	return "InterfaceService"
+}
+
+func (s *InterfaceServer) Register(r grpc.ServiceRegistrar) {
+	pb.RegisterInterfaceServiceServer(r, s)
+}
+
+func (s *InterfaceServer) ListInterfaces(ctx context.Context, req *pb.ListInterfacesRequest) (
+	*pb.ListInterfacesResponse,
+	error,
+) {
+	filter := domain.InterfaceFilter{
+		Disabled:     req.Disabled,
+		CreatedAfter: newTimeFilter(req.GetCreatedAfter()),
+	}
+
+	interfaces, peerCounts, total, err := s.interfaces.GetAll(ctx, filter, newListOptions(req.GetOptions()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.ListInterfacesResponse{
+		Interfaces: newInterfacesWithPeerCounts(interfaces, peerCounts),
+		TotalCount: int32(total),
+	}, nil
+}
+
+func (s *InterfaceServer) GetInterface(ctx context.Context, req *pb.GetInterfaceRequest) (*pb.Interface, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing interface id"))
+	}
+
+	iface, peers, err := s.interfaces.GetById(ctx, domain.InterfaceIdentifier(req.GetIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newInterface(iface, peers), nil
+}
+
+func (s *InterfaceServer) PrepareInterface(ctx context.Context, _ *emptypb.Empty) (*pb.Interface, error) {
+	iface, err := s.interfaces.Prepare(ctx)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newInterface(iface, nil), nil
+}
+
+func (s *InterfaceServer) CreateInterface(ctx context.Context, req *pb.CreateInterfaceRequest) (*pb.Interface, error) {
+	iface, err := s.validInterface(req.GetInterface())
+	if err != nil {
+		return nil, err
+	}
+
+	newIface, err := s.interfaces.Create(ctx, models.NewDomainInterface(iface))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newInterface(newIface, nil), nil
+}
+
+func (s *InterfaceServer) UpdateInterface(ctx context.Context, req *pb.UpdateInterfaceRequest) (*pb.Interface, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing interface id"))
+	}
+	iface, err := s.validInterface(req.GetInterface())
+	if err != nil {
+		return nil, err
+	}
+
+	id := domain.InterfaceIdentifier(req.GetIdentifier())
+	ctx = domain.SetIfMatchPrecondition(ctx, id, req.GetIfMatch())
+	updatedIface, peers, err := s.interfaces.Update(ctx, id, models.NewDomainInterface(iface))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newInterface(updatedIface, peers), nil
+}
+
+func (s *InterfaceServer) DeleteInterface(ctx context.Context, req *pb.DeleteInterfaceRequest) (*emptypb.Empty, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing interface id"))
+	}
+
+	id := domain.InterfaceIdentifier(req.GetIdentifier())
+	if err := s.interfaces.Delete(domain.SetIfMatchPrecondition(ctx, id, req.GetIfMatch()), id); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ImportWgQuick imports an interface and its peers from wg-quick configuration files. Unlike the REST endpoint, an
+// import with conflicts is not an error, the conflicts are listed in the response and nothing is committed.
+func (s *InterfaceServer) ImportWgQuick(ctx context.Context, req *pb.ImportWgQuickRequest) (
+	*pb.ImportWgQuickResponse,
+	error,
+) {
+	importReq := newModelWgQuickImportRequest(req)
+	if err := s.validator.Struct(*importReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	plan, err := s.interfaces.ImportWgQuick(ctx, models.NewDomainWgQuickImportRequest(importReq),
+		importReq.ServerConfigPath, importReq.ClientConfigDir, importReq.DryRun)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newImportWgQuickResponse(plan), nil
+}
+
+// validInterface converts and validates the interface of a create or update request.
+func (s *InterfaceServer) validInterface(src *pb.Interface) (*models.Interface, error) {
+	if src == nil {
+		return nil, invalidArgument(errors.New("missing interface"))
+	}
+
+	iface := newModelInterface(src)
+	if err := s.validator.Struct(*iface); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	return iface, nil
+}
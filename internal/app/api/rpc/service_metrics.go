@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// defaultMetricsWatchInterval is the update interval of WatchUserMetrics if the client does not request one.
+const defaultMetricsWatchInterval = 10 * time.Second
+
+type MetricsServerStatisticsService interface {
+	GetForInterface(ctx context.Context, id domain.InterfaceIdentifier) (*domain.InterfaceStatus, error)
+	GetForUser(ctx context.Context, id domain.UserIdentifier) (*domain.User, []domain.PeerStatus, error)
+	GetForPeer(ctx context.Context, id domain.PeerIdentifier) (*domain.PeerStatus, error)
+}
+
+// MetricsServer implements the MetricsService of the gRPC API, it mirrors the metrics endpoints of the v1 REST API.
+type MetricsServer struct {
+	pb.UnimplementedMetricsServiceServer
+
+	metrics MetricsServerStatisticsService
+}
+
+func NewMetricsServer(metricsService MetricsServerStatisticsService) *MetricsServer {
+	return &MetricsServer{
+		metrics: metricsService,
+	}
+}
+
+func (s *MetricsServer) GetName() string {
+	return "MetricsService"
+}
+
+func (s *MetricsServer) Register(r grpc.ServiceRegistrar) {
+	pb.RegisterMetricsServiceServer(r, s)
+}
+
+func (s *MetricsServer) GetInterfaceMetrics(ctx context.Context, req *pb.GetInterfaceMetricsRequest) (
+	*pb.InterfaceMetrics,
+	error,
+) {
+	if req.GetInterfaceIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing interface id"))
+	}
+
+	interfaceMetrics, err := s.metrics.GetForInterface(ctx, domain.InterfaceIdentifier(req.GetInterfaceIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newInterfaceMetrics(interfaceMetrics), nil
+}
+
+func (s *MetricsServer) GetUserMetrics(ctx context.Context, req *pb.GetUserMetricsRequest) (*pb.UserMetrics, error) {
+	if req.GetUserIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing user id"))
+	}
+
+	user, peerMetrics, err := s.metrics.GetForUser(ctx, domain.UserIdentifier(req.GetUserIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newUserMetrics(user, peerMetrics), nil
+}
+
+func (s *MetricsServer) GetPeerMetrics(ctx context.Context, req *pb.GetPeerMetricsRequest) (*pb.PeerMetrics, error) {
+	if req.GetPeerIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing peer id"))
+	}
+
+	peerMetrics, err := s.metrics.GetForPeer(ctx, domain.PeerIdentifier(req.GetPeerIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPeerMetrics(peerMetrics), nil
+}
+
+// WatchUserMetrics sends the metrics of a user and its peers in the requested interval until the client cancels the
+// call. The first metrics are sent immediately.
+func (s *MetricsServer) WatchUserMetrics(
+	req *pb.WatchUserMetricsRequest,
+	stream grpc.ServerStreamingServer[pb.UserMetrics],
+) error {
+	if req.GetUserIdentifier() == "" {
+		return invalidArgument(errors.New("missing user id"))
+	}
+
+	interval := time.Duration(req.GetIntervalSeconds()) * time.Second
+	if interval == 0 {
+		interval = defaultMetricsWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		user, peerMetrics, err := s.metrics.GetForUser(stream.Context(), domain.UserIdentifier(req.GetUserIdentifier()))
+		if err != nil {
+			return serviceError(err)
+		}
+		if err := stream.Send(newUserMetrics(user, peerMetrics)); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
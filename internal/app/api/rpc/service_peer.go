@@ -0,0 +1,233 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type PeerServerPeerService interface {
+	GetForInterface(context.Context, domain.InterfaceIdentifier, domain.PeerFilter, domain.ListOptions) (
+		[]domain.Peer, int, error)
+	GetForUser(context.Context, domain.UserIdentifier, domain.PeerFilter, domain.ListOptions) (
+		[]domain.Peer, int, error)
+	GetById(context.Context, domain.PeerIdentifier) (*domain.Peer, error)
+	Prepare(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Peer, error)
+	Create(context.Context, *domain.Peer) (*domain.Peer, error)
+	Update(context.Context, domain.PeerIdentifier, *domain.Peer) (*domain.Peer, error)
+	Delete(context.Context, domain.PeerIdentifier) error
+}
+
+type PeerServerEventService interface {
+	Subscribe(ctx context.Context, filter domain.StreamEventFilter, resumeAfter *uint64) (
+		[]domain.StreamEvent,
+		<-chan domain.StreamEvent,
+		func(),
+		error,
+	)
+}
+
+// PeerServer implements the PeerService of the gRPC API, it mirrors the peer endpoints of the v1 REST API.
+type PeerServer struct {
+	pb.UnimplementedPeerServiceServer
+
+	peers     PeerServerPeerService
+	events    PeerServerEventService
+	validator Validator
+}
+
+func NewPeerServer(
+	validator Validator,
+	peerService PeerServerPeerService,
+	eventService PeerServerEventService,
+) *PeerServer {
+	return &PeerServer{
+		validator: validator,
+		peers:     peerService,
+		events:    eventService,
+	}
+}
+
+func (s *PeerServer) GetName() string {
+	return "PeerService"
+}
+
+func (s *PeerServer) Register(r grpc.ServiceRegistrar) {
+	pb.RegisterPeerServiceServer(r, s)
+}
+
+func (s *PeerServer) ListInterfacePeers(ctx context.Context, req *pb.ListInterfacePeersRequest) (
+	*pb.ListPeersResponse,
+	error,
+) {
+	if req.GetInterfaceIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing interface id"))
+	}
+
+	peers, total, err := s.peers.GetForInterface(ctx, domain.InterfaceIdentifier(req.GetInterfaceIdentifier()),
+		newPeerFilter(req.GetFilter()), newListOptions(req.GetOptions()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.ListPeersResponse{Peers: newPeers(peers), TotalCount: int32(total)}, nil
+}
+
+func (s *PeerServer) ListUserPeers(ctx context.Context, req *pb.ListUserPeersRequest) (*pb.ListPeersResponse, error) {
+	if req.GetUserIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing user id"))
+	}
+
+	peers, total, err := s.peers.GetForUser(ctx, domain.UserIdentifier(req.GetUserIdentifier()),
+		newPeerFilter(req.GetFilter()), newListOptions(req.GetOptions()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.ListPeersResponse{Peers: newPeers(peers), TotalCount: int32(total)}, nil
+}
+
+func (s *PeerServer) GetPeer(ctx context.Context, req *pb.GetPeerRequest) (*pb.Peer, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing peer id"))
+	}
+
+	peer, err := s.peers.GetById(ctx, domain.PeerIdentifier(req.GetIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPeer(peer), nil
+}
+
+func (s *PeerServer) PreparePeer(ctx context.Context, req *pb.PreparePeerRequest) (*pb.Peer, error) {
+	if req.GetInterfaceIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing interface id"))
+	}
+
+	peer, err := s.peers.Prepare(ctx, domain.InterfaceIdentifier(req.GetInterfaceIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPeer(peer), nil
+}
+
+func (s *PeerServer) CreatePeer(ctx context.Context, req *pb.CreatePeerRequest) (*pb.Peer, error) {
+	peer, err := s.validPeer(req.GetPeer())
+	if err != nil {
+		return nil, err
+	}
+
+	newPeerRecord, err := s.peers.Create(ctx, models.NewDomainPeer(peer))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPeer(newPeerRecord), nil
+}
+
+func (s *PeerServer) UpdatePeer(ctx context.Context, req *pb.UpdatePeerRequest) (*pb.Peer, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing peer id"))
+	}
+	peer, err := s.validPeer(req.GetPeer())
+	if err != nil {
+		return nil, err
+	}
+
+	id := domain.PeerIdentifier(req.GetIdentifier())
+	ctx = domain.SetIfMatchPrecondition(ctx, id, req.GetIfMatch())
+	updatedPeer, err := s.peers.Update(ctx, id, models.NewDomainPeer(peer))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPeer(updatedPeer), nil
+}
+
+func (s *PeerServer) DeletePeer(ctx context.Context, req *pb.DeletePeerRequest) (*emptypb.Empty, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing peer id"))
+	}
+
+	id := domain.PeerIdentifier(req.GetIdentifier())
+	if err := s.peers.Delete(domain.SetIfMatchPrecondition(ctx, id, req.GetIfMatch()), id); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// WatchPeerStatus sends the connects and disconnects of peers until the client cancels the call. Admins receive the
+// events of all peers, normal users only the events of their own peers.
+func (s *PeerServer) WatchPeerStatus(
+	req *pb.WatchPeerStatusRequest,
+	stream grpc.ServerStreamingServer[pb.PeerStatusEvent],
+) error {
+	filter := domain.StreamEventFilter{Types: []string{
+		string(domain.StreamEventPeerConnected),
+		string(domain.StreamEventPeerDisconnected),
+	}}
+
+	_, events, unsubscribe, err := s.events.Subscribe(stream.Context(), filter, nil)
+	if err != nil {
+		return serviceError(err)
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				// the subscription has been closed because the client did not keep up, it can call the method again
+				return status.Error(codes.Unavailable, "peer status subscription closed")
+			}
+
+			peerStatus, ok := event.Payload.(domain.PeerStatus)
+			if !ok {
+				continue
+			}
+			if len(req.GetPeerIdentifiers()) > 0 &&
+				!slices.Contains(req.GetPeerIdentifiers(), string(peerStatus.PeerId)) {
+				continue
+			}
+
+			err := stream.Send(&pb.PeerStatusEvent{
+				Id:        event.Id,
+				Time:      timestamppb.New(event.CreatedAt),
+				Connected: event.Type == domain.StreamEventPeerConnected,
+				Metrics:   newPeerMetrics(&peerStatus),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// validPeer converts and validates the peer of a create or update request.
+func (s *PeerServer) validPeer(src *pb.Peer) (*models.Peer, error) {
+	if src == nil {
+		return nil, invalidArgument(errors.New("missing peer"))
+	}
+
+	peer := newModelPeer(src)
+	if err := s.validator.Struct(*peer); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	return peer, nil
+}
@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type ProvisioningServerProvisioningService interface {
+	GetUserAndPeers(ctx context.Context, userId domain.UserIdentifier, email string) (
+		*domain.User,
+		[]domain.Peer,
+		error,
+	)
+	GetPeerConfig(ctx context.Context, peerId domain.PeerIdentifier) ([]byte, error)
+	GetPeerQrPng(ctx context.Context, peerId domain.PeerIdentifier) ([]byte, error)
+	NewPeer(ctx context.Context, req models.ProvisioningRequest) (*domain.Peer, error)
+}
+
+// ProvisioningServer implements the ProvisioningService of the gRPC API, it mirrors the provisioning endpoints of
+// the v1 REST API.
+type ProvisioningServer struct {
+	pb.UnimplementedProvisioningServiceServer
+
+	provisioning ProvisioningServerProvisioningService
+	validator    Validator
+}
+
+func NewProvisioningServer(
+	validator Validator,
+	provisioningService ProvisioningServerProvisioningService,
+) *ProvisioningServer {
+	return &ProvisioningServer{
+		validator:    validator,
+		provisioning: provisioningService,
+	}
+}
+
+func (s *ProvisioningServer) GetName() string {
+	return "ProvisioningService"
+}
+
+func (s *ProvisioningServer) Register(r grpc.ServiceRegistrar) {
+	pb.RegisterProvisioningServiceServer(r, s)
+}
+
+func (s *ProvisioningServer) GetUserInformation(ctx context.Context, req *pb.GetUserInformationRequest) (
+	*pb.UserInformation,
+	error,
+) {
+	id := strings.TrimSpace(req.GetUserIdentifier())
+	email := strings.TrimSpace(req.GetEmail())
+
+	if id == "" && email == "" {
+		id = string(domain.GetUserInfo(ctx).Id)
+	}
+
+	user, peers, err := s.provisioning.GetUserAndPeers(ctx, domain.UserIdentifier(id), email)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newUserInformation(user, peers), nil
+}
+
+func (s *ProvisioningServer) GetPeerConfig(ctx context.Context, req *pb.GetPeerConfigRequest) (
+	*pb.PeerConfig,
+	error,
+) {
+	id := strings.TrimSpace(req.GetPeerIdentifier())
+	if id == "" {
+		return nil, invalidArgument(errors.New("missing peer id"))
+	}
+
+	peerConfig, err := s.provisioning.GetPeerConfig(ctx, domain.PeerIdentifier(id))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.PeerConfig{Config: string(peerConfig)}, nil
+}
+
+func (s *ProvisioningServer) GetPeerQrCode(ctx context.Context, req *pb.GetPeerConfigRequest) (
+	*pb.PeerQrCode,
+	error,
+) {
+	id := strings.TrimSpace(req.GetPeerIdentifier())
+	if id == "" {
+		return nil, invalidArgument(errors.New("missing peer id"))
+	}
+
+	peerConfigQrCode, err := s.provisioning.GetPeerQrPng(ctx, domain.PeerIdentifier(id))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.PeerQrCode{Png: peerConfigQrCode}, nil
+}
+
+func (s *ProvisioningServer) CreatePeer(ctx context.Context, req *pb.ProvisioningRequest) (*pb.Peer, error) {
+	provisioningReq := newModelProvisioningRequest(req)
+	if err := s.validator.Struct(*provisioningReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	peer, err := s.provisioning.NewPeer(ctx, *provisioningReq)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newPeer(peer), nil
+}
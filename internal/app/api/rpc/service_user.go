@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/biezax/wg-portal/internal/app/api/rpc/pb"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type UserServerUserService interface {
+	GetAll(ctx context.Context, filter domain.UserFilter, opts domain.ListOptions) ([]domain.User, int, error)
+	GetById(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) (*domain.User, error)
+	Update(ctx context.Context, id domain.UserIdentifier, user *domain.User) (*domain.User, error)
+	Delete(ctx context.Context, id domain.UserIdentifier) error
+}
+
+// UserServer implements the UserService of the gRPC API, it mirrors the user endpoints of the v1 REST API.
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+
+	users     UserServerUserService
+	validator Validator
+}
+
+func NewUserServer(validator Validator, userService UserServerUserService) *UserServer {
+	return &UserServer{
+		validator: validator,
+		users:     userService,
+	}
+}
+
+func (s *UserServer) GetName() string {
+	return "UserService"
+}
+
+func (s *UserServer) Register(r grpc.ServiceRegistrar) {
+	pb.RegisterUserServiceServer(r, s)
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	filter := domain.UserFilter{
+		Disabled:     req.Disabled,
+		Admin:        req.Admin,
+		CreatedAfter: newTimeFilter(req.GetCreatedAfter()),
+	}
+
+	users, total, err := s.users.GetAll(ctx, filter, newListOptions(req.GetOptions()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &pb.ListUsersResponse{Users: newUsers(users), TotalCount: int32(total)}, nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing user id"))
+	}
+
+	user, err := s.users.GetById(ctx, domain.UserIdentifier(req.GetIdentifier()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newUser(user, true), nil
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	user, err := s.validUser(req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+
+	newUserRecord, err := s.users.Create(ctx, models.NewDomainUser(user))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newUser(newUserRecord, true), nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing user id"))
+	}
+	user, err := s.validUser(req.GetUser())
+	if err != nil {
+		return nil, err
+	}
+
+	id := domain.UserIdentifier(req.GetIdentifier())
+	ctx = domain.SetIfMatchPrecondition(ctx, id, req.GetIfMatch())
+	updatedUser, err := s.users.Update(ctx, id, models.NewDomainUser(user))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return newUser(updatedUser, true), nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if req.GetIdentifier() == "" {
+		return nil, invalidArgument(errors.New("missing user id"))
+	}
+
+	id := domain.UserIdentifier(req.GetIdentifier())
+	if err := s.users.Delete(domain.SetIfMatchPrecondition(ctx, id, req.GetIfMatch()), id); err != nil {
+		return nil, serviceError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// validUser converts and validates the user of a create or update request.
+func (s *UserServer) validUser(src *pb.User) (*models.User, error) {
+	if src == nil {
+		return nil, invalidArgument(errors.New("missing user"))
+	}
+
+	user := newModelUser(src)
+	if err := s.validator.Struct(*user); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	return user, nil
+}
@@ -3,6 +3,7 @@ package backend
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/biezax/wg-portal/internal/config"
 	"github.com/biezax/wg-portal/internal/domain"
@@ -24,6 +25,8 @@ type PeerServicePeerManager interface {
 		r *domain.PeerCreationRequest,
 	) ([]domain.Peer, error)
 	GetPeerStats(ctx context.Context, id domain.InterfaceIdentifier) ([]domain.PeerStatus, error)
+	GetPeerExtension(ctx context.Context, token string) (*domain.Peer, time.Time, error)
+	ExtendPeer(ctx context.Context, token string) (*domain.Peer, error)
 }
 
 type PeerServiceConfigFileManager interface {
@@ -118,3 +121,11 @@ func (p PeerService) SendPeerEmail(
 func (p PeerService) GetPeerStats(ctx context.Context, id domain.InterfaceIdentifier) ([]domain.PeerStatus, error) {
 	return p.peers.GetPeerStats(ctx, id)
 }
+
+func (p PeerService) GetPeerExtension(ctx context.Context, token string) (*domain.Peer, time.Time, error) {
+	return p.peers.GetPeerExtension(ctx, token)
+}
+
+func (p PeerService) ExtendPeer(ctx context.Context, token string) (*domain.Peer, error) {
+	return p.peers.ExtendPeer(ctx, token)
+}
@@ -2,8 +2,14 @@ package handlers
 
 import (
 	"context"
+	"embed"
+	"errors"
+	"html/template"
 	"io"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-pkgz/routegroup"
 
@@ -14,6 +20,9 @@ import (
 	"github.com/biezax/wg-portal/internal/domain"
 )
 
+//go:embed peer_extension.gohtml
+var peerExtensionHtml embed.FS
+
 type PeerService interface {
 	// GetInterfaceAndPeers returns the interface with the given id and all peers associated with it.
 	GetInterfaceAndPeers(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Interface, []domain.Peer, error)
@@ -41,6 +50,10 @@ type PeerService interface {
 	SendPeerEmail(ctx context.Context, linkOnly bool, style string, peers ...domain.PeerIdentifier) error
 	// GetPeerStats returns the peer stats for the given interface.
 	GetPeerStats(ctx context.Context, id domain.InterfaceIdentifier) ([]domain.PeerStatus, error)
+	// GetPeerExtension returns the peer of the extension token and its expiry date after the extension.
+	GetPeerExtension(ctx context.Context, token string) (*domain.Peer, time.Time, error)
+	// ExtendPeer extends the expiry date of the peer of the extension token.
+	ExtendPeer(ctx context.Context, token string) (*domain.Peer, error)
 }
 
 type PeerEndpoint struct {
@@ -48,6 +61,8 @@ type PeerEndpoint struct {
 	peerService   PeerService
 	authenticator Authenticator
 	validator     Validator
+
+	tpl *respond.TemplateRenderer
 }
 
 func NewPeerEndpoint(
@@ -61,6 +76,8 @@ func NewPeerEndpoint(
 		peerService:   peerService,
 		authenticator: authenticator,
 		validator:     validator,
+		tpl: respond.NewTemplateRenderer(template.Must(template.ParseFS(peerExtensionHtml,
+			"peer_extension.gohtml"))),
 	}
 }
 
@@ -69,6 +86,10 @@ func (e PeerEndpoint) GetName() string {
 }
 
 func (e PeerEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	// the extension link of expiry reminders authorizes the request, no login is required
+	g.HandleFunc("GET /peer/extend", e.handleExtendGet())
+	g.HandleFunc("POST /peer/extend", e.handleExtendPost())
+
 	apiGroup := g.Mount("/peer")
 	apiGroup.Use(e.authenticator.LoggedIn())
 
@@ -521,3 +542,81 @@ func (e PeerEndpoint) getConfigStyle(r *http.Request) string {
 	}
 	return configStyle
 }
+
+// handleExtendGet returns a gorm Handler function.
+//
+// @ID peers_handleExtendGet
+// @Tags Peer
+// @Summary Show the confirmation page of a self-service peer extension.
+// @Produce html
+// @Param token query string true "The extension token of the expiry reminder"
+// @Success 200 string html "The confirmation page"
+// @Failure 400 string html "The error page"
+// @Failure 500 string html "The error page"
+// @Router /peer/extend [get]
+func (e PeerEndpoint) handleExtendGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := request.Query(r, "token")
+
+		peer, expiresAt, err := e.peerService.GetPeerExtension(r.Context(), token)
+		if err != nil {
+			e.renderExtensionError(w, err)
+			return
+		}
+
+		e.renderExtension(w, http.StatusOK, respond.TplData{
+			"PeerName":     peer.DisplayName,
+			"ExpiresAt":    peer.ExpiresAt.Format(time.DateOnly),
+			"NewExpiresAt": expiresAt.Format(time.DateOnly),
+			"Token":        token,
+		})
+	}
+}
+
+// handleExtendPost returns a gorm Handler function.
+//
+// @ID peers_handleExtendPost
+// @Tags Peer
+// @Summary Extend a peer with the extension token of an expiry reminder.
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param token formData string true "The extension token of the expiry reminder"
+// @Success 200 string html "The result page"
+// @Failure 400 string html "The error page"
+// @Failure 500 string html "The error page"
+// @Router /peer/extend [post]
+func (e PeerEndpoint) handleExtendPost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		peer, err := e.peerService.ExtendPeer(r.Context(), r.FormValue("token"))
+		if err != nil {
+			e.renderExtensionError(w, err)
+			return
+		}
+
+		e.renderExtension(w, http.StatusOK, respond.TplData{
+			"PeerName":     peer.DisplayName,
+			"NewExpiresAt": peer.ExpiresAt.Format(time.DateOnly),
+			"Extended":     true,
+		})
+	}
+}
+
+func (e PeerEndpoint) renderExtension(w http.ResponseWriter, code int, data respond.TplData) {
+	data["SiteTitle"] = e.cfg.Web.SiteTitle
+	data["PortalUrl"] = e.cfg.Web.ExternalUrl
+	e.tpl.HTML(w, code, "peer_extension.gohtml", data)
+}
+
+// renderExtensionError renders the error page, details of internal errors are not shown to the user.
+func (e PeerEndpoint) renderExtensionError(w http.ResponseWriter, err error) {
+	if !errors.Is(err, domain.ErrInvalidData) {
+		slog.Error("peer extension failed", "error", err)
+		e.renderExtension(w, http.StatusInternalServerError, respond.TplData{
+			"Error": "The peer could not be extended, please contact your administrator.",
+		})
+		return
+	}
+
+	message, _, _ := strings.Cut(err.Error(), "\n") // the first line describes the cause
+	e.renderExtension(w, http.StatusBadRequest, respond.TplData{"Error": message})
+}
@@ -12,6 +12,18 @@ type AuditEntry struct {
 	Severity    string `json:"Severity"`
 	Origin      string `json:"Origin"` // origin: for example user auth, stats, ...
 	Message     string `message:"Message"`
+
+	EntityType string        `json:"EntityType"`
+	EntityId   string        `json:"EntityId"`
+	Action     string        `json:"Action"`
+	SourceIp   string        `json:"SourceIp"`
+	Changes    []AuditChange `json:"Changes"`
+}
+
+type AuditChange struct {
+	Field string `json:"Field"`
+	Old   any    `json:"Old,omitempty"`
+	New   any    `json:"New,omitempty"`
 }
 
 // NewAuditEntry creates a REST API AuditEntry from a domain AuditEntry.
@@ -23,9 +35,23 @@ func NewAuditEntry(src domain.AuditEntry) AuditEntry {
 		Severity:    string(src.Severity),
 		Origin:      src.Origin,
 		Message:     src.Message,
+		EntityType:  src.EntityType,
+		EntityId:    src.EntityId,
+		Action:      src.Action,
+		SourceIp:    src.SourceIp,
+		Changes:     NewAuditChanges(src.Changes),
 	}
 }
 
+// NewAuditChanges creates a slice of REST API AuditChange from a slice of domain AuditChange.
+func NewAuditChanges(src []domain.AuditChange) []AuditChange {
+	dst := make([]AuditChange, 0, len(src))
+	for _, change := range src {
+		dst = append(dst, AuditChange{Field: change.Field, Old: change.Old, New: change.New})
+	}
+	return dst
+}
+
 // NewAuditEntries creates a slice of REST API AuditEntry from a slice of domain AuditEntry.
 func NewAuditEntries(src []domain.AuditEntry) []AuditEntry {
 	dst := make([]AuditEntry, 0, len(src))
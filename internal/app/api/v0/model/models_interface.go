@@ -21,6 +21,8 @@ type Interface struct {
 	DisabledReason string `json:"DisabledReason"`                // the reason why the interface has been disabled
 	SaveConfig     bool   `json:"SaveConfig"`                    // automatically persist config changes to the wgX.conf file
 
+	PeerExtensionDays int `json:"PeerExtensionDays"` // the number of days users can extend their expiring peers by, 0 disables it
+
 	ListenPort   int      `json:"ListenPort"`   // the listening port, for example: 51820
 	Addresses    []string `json:"Addresses"`    // the interface ip addresses
 	Dns          []string `json:"Dns"`          // the dns server that should be set if the interface is up, comma separated
@@ -70,6 +72,7 @@ func NewInterface(src *domain.Interface, peers []domain.Peer) *Interface {
 		Disabled:                   src.IsDisabled(),
 		DisabledReason:             src.DisabledReason,
 		SaveConfig:                 src.SaveConfig,
+		PeerExtensionDays:          src.PeerExtensionDays,
 		ListenPort:                 src.ListenPort,
 		Addresses:                  domain.CidrsToStringSlice(src.Addresses),
 		Dns:                        internal.SliceString(src.DnsStr),
@@ -176,6 +179,7 @@ func NewDomainInterface(src *Interface) *domain.Interface {
 		DriverType:                 "",  // currently unused
 		Disabled:                   nil, // set below
 		DisabledReason:             src.DisabledReason,
+		PeerExtensionDays:          src.PeerExtensionDays,
 		PeerDefNetworkStr:          internal.SliceToString(src.PeerDefNetwork),
 		PeerDefDnsStr:              internal.SliceToString(src.PeerDefDns),
 		PeerDefDnsSearchStr:        internal.SliceToString(src.PeerDefDnsSearch),
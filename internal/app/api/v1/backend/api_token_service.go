@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type ApiTokenServiceUserManagerRepo interface {
+	GetApiTokens(ctx context.Context, id domain.UserIdentifier) ([]domain.ApiToken, error)
+	GetAllApiTokens(ctx context.Context) ([]domain.ApiToken, error)
+	CreateApiToken(ctx context.Context, token *domain.ApiToken) (*domain.ApiToken, string, error)
+	DeleteApiToken(ctx context.Context, id domain.ApiTokenIdentifier) error
+}
+
+type ApiTokenService struct {
+	cfg *config.Config
+
+	users ApiTokenServiceUserManagerRepo
+}
+
+func NewApiTokenService(cfg *config.Config, users ApiTokenServiceUserManagerRepo) *ApiTokenService {
+	return &ApiTokenService{
+		cfg:   cfg,
+		users: users,
+	}
+}
+
+func (s ApiTokenService) GetAll(ctx context.Context) ([]domain.ApiToken, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.users.GetAllApiTokens(ctx)
+}
+
+func (s ApiTokenService) GetForUser(ctx context.Context, id domain.UserIdentifier) ([]domain.ApiToken, error) {
+	if err := s.validateUserAccess(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.users.GetApiTokens(ctx, id)
+}
+
+func (s ApiTokenService) Create(ctx context.Context, token *domain.ApiToken) (*domain.ApiToken, string, error) {
+	if err := s.validateUserAccess(ctx, token.UserIdentifier); err != nil {
+		return nil, "", err
+	}
+
+	return s.users.CreateApiToken(ctx, token)
+}
+
+func (s ApiTokenService) Delete(ctx context.Context, id domain.ApiTokenIdentifier) error {
+	if s.cfg.Advanced.ApiAdminOnly && !domain.GetUserInfo(ctx).IsAdmin {
+		return errors.Join(errors.New("only admins can access this endpoint"), domain.ErrNoPermission)
+	}
+
+	return s.users.DeleteApiToken(ctx, id)
+}
+
+func (s ApiTokenService) validateUserAccess(ctx context.Context, id domain.UserIdentifier) error {
+	if err := domain.ValidateUserAccessRights(ctx, id); err != nil {
+		return err
+	}
+
+	if s.cfg.Advanced.ApiAdminOnly && !domain.GetUserInfo(ctx).IsAdmin {
+		return errors.Join(errors.New("only admins can access this endpoint"), domain.ErrNoPermission)
+	}
+
+	return nil
+}
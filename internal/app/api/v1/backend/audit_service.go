@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"context"
+	"io"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type AuditServiceAuditManager interface {
+	GetEntries(
+		ctx context.Context,
+		filter domain.AuditEntryFilter,
+		cursor domain.AuditEntryCursor,
+		limit int,
+	) ([]domain.AuditEntry, domain.AuditEntryCursor, error)
+	ExportEntries(
+		ctx context.Context,
+		w io.Writer,
+		filter domain.AuditEntryFilter,
+		format domain.AuditExportFormat,
+	) error
+	VerifyChain(ctx context.Context) (*domain.AuditChainReport, error)
+}
+
+type AuditService struct {
+	cfg *config.Config
+
+	audit AuditServiceAuditManager
+}
+
+func NewAuditService(cfg *config.Config, audit AuditServiceAuditManager) *AuditService {
+	return &AuditService{
+		cfg:   cfg,
+		audit: audit,
+	}
+}
+
+func (s AuditService) GetEntries(
+	ctx context.Context,
+	filter domain.AuditEntryFilter,
+	cursor domain.AuditEntryCursor,
+	limit int,
+) ([]domain.AuditEntry, domain.AuditEntryCursor, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return s.audit.GetEntries(ctx, filter, cursor, limit)
+}
+
+func (s AuditService) ExportEntries(
+	ctx context.Context,
+	w io.Writer,
+	filter domain.AuditEntryFilter,
+	format domain.AuditExportFormat,
+) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+
+	return s.audit.ExportEntries(ctx, w, filter, format)
+}
+
+func (s AuditService) VerifyChain(ctx context.Context) (*domain.AuditChainReport, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.audit.VerifyChain(ctx)
+}
@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"context"
+	"io"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type BackupServiceBackupManagerRepo interface {
+	Backup(ctx context.Context, w io.Writer, passphrase string) (*domain.BackupInfo, error)
+	Restore(ctx context.Context, r io.Reader, passphrase string, dryRun bool) (*domain.BackupInfo, error)
+}
+
+type BackupService struct {
+	cfg *config.Config
+
+	backups BackupServiceBackupManagerRepo
+}
+
+func NewBackupService(cfg *config.Config, backups BackupServiceBackupManagerRepo) *BackupService {
+	return &BackupService{
+		cfg:     cfg,
+		backups: backups,
+	}
+}
+
+func (s BackupService) Backup(ctx context.Context, w io.Writer, passphrase string) (*domain.BackupInfo, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.backups.Backup(ctx, w, passphrase)
+}
+
+func (s BackupService) Restore(
+	ctx context.Context,
+	r io.Reader,
+	passphrase string,
+	dryRun bool,
+) (*domain.BackupInfo, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.backups.Restore(ctx, r, passphrase, dryRun)
+}
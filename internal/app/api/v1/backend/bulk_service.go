@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"context"
+	"io"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type BulkServiceBulkManagerRepo interface {
+	ExportUsers(ctx context.Context, w io.Writer, format domain.BulkFormat) error
+	ExportPeers(ctx context.Context, w io.Writer, format domain.BulkFormat) error
+	ImportUsers(
+		ctx context.Context,
+		r io.Reader,
+		format domain.BulkFormat,
+		mode domain.BulkImportMode,
+		dryRun bool,
+	) (*domain.BulkImportReport, error)
+	ImportPeers(
+		ctx context.Context,
+		r io.Reader,
+		format domain.BulkFormat,
+		mode domain.BulkImportMode,
+		dryRun bool,
+	) (*domain.BulkImportReport, error)
+}
+
+type BulkService struct {
+	cfg *config.Config
+
+	bulk BulkServiceBulkManagerRepo
+}
+
+func NewBulkService(cfg *config.Config, bulk BulkServiceBulkManagerRepo) *BulkService {
+	return &BulkService{
+		cfg:  cfg,
+		bulk: bulk,
+	}
+}
+
+func (s BulkService) ExportUsers(ctx context.Context, w io.Writer, format domain.BulkFormat) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+
+	return s.bulk.ExportUsers(ctx, w, format)
+}
+
+func (s BulkService) ExportPeers(ctx context.Context, w io.Writer, format domain.BulkFormat) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+
+	return s.bulk.ExportPeers(ctx, w, format)
+}
+
+func (s BulkService) ImportUsers(
+	ctx context.Context,
+	r io.Reader,
+	format domain.BulkFormat,
+	mode domain.BulkImportMode,
+	dryRun bool,
+) (*domain.BulkImportReport, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.bulk.ImportUsers(ctx, r, format, mode, dryRun)
+}
+
+func (s BulkService) ImportPeers(
+	ctx context.Context,
+	r io.Reader,
+	format domain.BulkFormat,
+	mode domain.BulkImportMode,
+	dryRun bool,
+) (*domain.BulkImportReport, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.bulk.ImportPeers(ctx, r, format, mode, dryRun)
+}
@@ -0,0 +1,33 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type ConfigServiceReloadManagerRepo interface {
+	Reload(ctx context.Context) (*domain.ConfigReloadReport, error)
+}
+
+type ConfigService struct {
+	cfg *config.Config
+
+	reloads ConfigServiceReloadManagerRepo
+}
+
+func NewConfigService(cfg *config.Config, reloads ConfigServiceReloadManagerRepo) *ConfigService {
+	return &ConfigService{
+		cfg:     cfg,
+		reloads: reloads,
+	}
+}
+
+func (s ConfigService) Reload(ctx context.Context) (*domain.ConfigReloadReport, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.reloads.Reload(ctx)
+}
@@ -0,0 +1,46 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type EventServiceEventStreamRepo interface {
+	Subscribe(ctx context.Context, filter domain.StreamEventFilter, resumeAfter *uint64) (
+		[]domain.StreamEvent,
+		<-chan domain.StreamEvent,
+		func(),
+	)
+}
+
+type EventService struct {
+	cfg *config.Config
+
+	stream EventServiceEventStreamRepo
+}
+
+func NewEventService(cfg *config.Config, stream EventServiceEventStreamRepo) *EventService {
+	return &EventService{
+		cfg:    cfg,
+		stream: stream,
+	}
+}
+
+// Subscribe subscribes to the event stream. Admins receive all events, other users only the events of their own peers.
+func (s EventService) Subscribe(ctx context.Context, filter domain.StreamEventFilter, resumeAfter *uint64) (
+	[]domain.StreamEvent,
+	<-chan domain.StreamEvent,
+	func(),
+	error,
+) {
+	if s.cfg.Advanced.ApiAdminOnly && !domain.GetUserInfo(ctx).IsAdmin {
+		return nil, nil, nil, errors.Join(errors.New("only admins can access this endpoint"), domain.ErrNoPermission)
+	}
+
+	replay, events, unsubscribe := s.stream.Subscribe(ctx, filter, resumeAfter)
+
+	return replay, events, unsubscribe, nil
+}
@@ -3,18 +3,27 @@ package backend
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/biezax/wg-portal/internal/config"
 	"github.com/biezax/wg-portal/internal/domain"
 )
 
 type InterfaceServiceInterfaceManagerRepo interface {
-	GetAllInterfacesAndPeers(ctx context.Context) ([]domain.Interface, [][]domain.Peer, error)
+	QueryInterfaces(ctx context.Context, filter domain.InterfaceFilter, opts domain.ListOptions) (
+		[]domain.Interface, map[domain.InterfaceIdentifier]domain.PeerCounts, int, error)
 	GetInterfaceAndPeers(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Interface, []domain.Peer, error)
 	PrepareInterface(ctx context.Context) (*domain.Interface, error)
 	CreateInterface(ctx context.Context, in *domain.Interface) (*domain.Interface, error)
 	UpdateInterface(ctx context.Context, in *domain.Interface) (*domain.Interface, []domain.Peer, error)
 	DeleteInterface(ctx context.Context, id domain.InterfaceIdentifier) error
+	ImportWgQuickInterface(
+		ctx context.Context,
+		req *domain.WgQuickImportRequest,
+		dryRun bool,
+	) (*domain.WgQuickImportPlan, error)
 }
 
 type InterfaceService struct {
@@ -31,17 +40,22 @@ func NewInterfaceService(cfg *config.Config, interfaces InterfaceServiceInterfac
 	}
 }
 
-func (s InterfaceService) GetAll(ctx context.Context) ([]domain.Interface, [][]domain.Peer, error) {
+func (s InterfaceService) GetAll(ctx context.Context, filter domain.InterfaceFilter, opts domain.ListOptions) (
+	[]domain.Interface,
+	map[domain.InterfaceIdentifier]domain.PeerCounts,
+	int,
+	error,
+) {
 	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
-	interfaces, interfacePeers, err := s.interfaces.GetAllInterfacesAndPeers(ctx)
+	interfaces, peerCounts, total, err := s.interfaces.QueryInterfaces(ctx, filter, opts)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
-	return interfaces, interfacePeers, nil
+	return interfaces, peerCounts, total, nil
 }
 
 func (s InterfaceService) GetById(ctx context.Context, id domain.InterfaceIdentifier) (
@@ -101,12 +115,13 @@ func (s InterfaceService) Update(ctx context.Context, id domain.InterfaceIdentif
 			iface.Identifier, id, domain.ErrInvalidData)
 	}
 
-	updatedInterface, updatedPeers, err := s.interfaces.UpdateInterface(ctx, iface)
+	updatedInterface, _, err := s.interfaces.UpdateInterface(ctx, iface)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return updatedInterface, updatedPeers, nil
+	// reload the interface, so that the returned entity tag matches the stored record
+	return s.interfaces.GetInterfaceAndPeers(ctx, updatedInterface.Identifier)
 }
 
 func (s InterfaceService) Delete(ctx context.Context, id domain.InterfaceIdentifier) error {
@@ -114,6 +129,17 @@ func (s InterfaceService) Delete(ctx context.Context, id domain.InterfaceIdentif
 		return err
 	}
 
+	if domain.HasIfMatchPrecondition(ctx, id) {
+		iface, _, err := s.interfaces.GetInterfaceAndPeers(ctx, id)
+		if err != nil {
+			return err
+		}
+		// fail before the interface is removed from the WireGuard backend
+		if err := domain.PeekIfMatchPrecondition(ctx, id, true, iface.BaseModel); err != nil {
+			return err
+		}
+	}
+
 	err := s.interfaces.DeleteInterface(ctx, id)
 	if err != nil {
 		return err
@@ -121,3 +147,107 @@ func (s InterfaceService) Delete(ctx context.Context, id domain.InterfaceIdentif
 
 	return nil
 }
+
+// ImportWgQuick imports an interface and its peers from wg-quick configuration files. In addition to the files
+// contained in the request, the server configuration and client configurations can be loaded from the local
+// file system. Local files must be located within the configured import directory.
+func (s InterfaceService) ImportWgQuick(
+	ctx context.Context,
+	req *domain.WgQuickImportRequest,
+	serverConfigPath, clientConfigDir string,
+	dryRun bool,
+) (*domain.WgQuickImportPlan, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	if serverConfigPath != "" {
+		path, err := s.resolveImportPath(serverConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		serverConfig, err := readWgQuickFile(path)
+		if err != nil {
+			return nil, err
+		}
+		req.Server = serverConfig
+		serverConfigPath = path
+	}
+	if len(req.Server.Content) == 0 {
+		return nil, fmt.Errorf("missing server configuration: %w", domain.ErrInvalidData)
+	}
+
+	if clientConfigDir != "" {
+		dir, err := s.resolveImportPath(clientConfigDir)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client config directory %s: %w: %w", dir, err, domain.ErrInvalidData)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".conf") {
+				continue
+			}
+			path, err := s.resolveImportPath(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if path == serverConfigPath {
+				continue // server and client configs may share a directory
+			}
+			clientConfig, err := readWgQuickFile(path)
+			if err != nil {
+				return nil, err
+			}
+			req.Clients = append(req.Clients, clientConfig)
+		}
+	}
+
+	return s.interfaces.ImportWgQuickInterface(ctx, req, dryRun)
+}
+
+// resolveImportPath returns the absolute path of the given file or directory. Relative paths are resolved against the
+// configured import directory. Paths outside of the import directory are rejected, symbolic links are followed.
+func (s InterfaceService) resolveImportPath(path string) (string, error) {
+	if s.cfg.Advanced.ImportConfigPath == "" {
+		return "", fmt.Errorf("importing local configuration files is disabled: %w", domain.ErrNoPermission)
+	}
+
+	baseDir, err := filepath.Abs(s.cfg.Advanced.ImportConfigPath)
+	if err == nil {
+		baseDir, err = filepath.EvalSymlinks(baseDir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import directory: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w: %w", path, err, domain.ErrInvalidData)
+	}
+
+	rel, err := filepath.Rel(baseDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside of the import directory: %w", path, domain.ErrNoPermission)
+	}
+
+	return resolved, nil
+}
+
+func readWgQuickFile(path string) (domain.WgQuickFile, error) {
+	if !strings.HasSuffix(path, ".conf") {
+		return domain.WgQuickFile{}, fmt.Errorf("%s is not a wg-quick configuration file: %w", path, domain.ErrInvalidData)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return domain.WgQuickFile{}, fmt.Errorf("failed to read %s: %w: %w", path, err, domain.ErrInvalidData)
+	}
+
+	return domain.WgQuickFile{Name: filepath.Base(path), Content: content}, nil
+}
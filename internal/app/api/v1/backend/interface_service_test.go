@@ -0,0 +1,54 @@
+package backend
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestInterfaceService_resolveImportPath(t *testing.T) {
+	baseDir := t.TempDir()
+	outsideDir := t.TempDir()
+	for _, file := range []string{filepath.Join(baseDir, "wg0.conf"), filepath.Join(outsideDir, "secret.conf")} {
+		if err := os.WriteFile(file, []byte("[Interface]"), 0600); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(outsideDir, "secret.conf"), filepath.Join(baseDir, "link.conf")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Advanced.ImportConfigPath = baseDir
+	s := InterfaceService{cfg: cfg}
+
+	if _, err := s.resolveImportPath("wg0.conf"); err != nil {
+		t.Fatalf("expected relative path within import directory to be allowed: %v", err)
+	}
+	if _, err := s.resolveImportPath(filepath.Join(baseDir, "wg0.conf")); err != nil {
+		t.Fatalf("expected absolute path within import directory to be allowed: %v", err)
+	}
+	if _, err := s.resolveImportPath(filepath.Join(outsideDir, "secret.conf")); !errors.Is(err, domain.ErrNoPermission) {
+		t.Fatalf("expected path outside of import directory to be rejected, got %v", err)
+	}
+	escapingPath := "../" + filepath.Base(outsideDir) + "/secret.conf"
+	if _, err := s.resolveImportPath(escapingPath); !errors.Is(err, domain.ErrNoPermission) {
+		t.Fatalf("expected relative path escaping the import directory to be rejected, got %v", err)
+	}
+	if _, err := s.resolveImportPath("link.conf"); !errors.Is(err, domain.ErrNoPermission) {
+		t.Fatalf("expected symlink escaping the import directory to be rejected, got %v", err)
+	}
+	_, err := s.resolveImportPath("missing.conf")
+	if !errors.Is(err, domain.ErrInvalidData) || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing file to report the underlying error, got %v", err)
+	}
+
+	cfg.Advanced.ImportConfigPath = ""
+	if _, err := s.resolveImportPath(filepath.Join(baseDir, "wg0.conf")); !errors.Is(err, domain.ErrNoPermission) {
+		t.Fatalf("expected local import to be disabled without import directory, got %v", err)
+	}
+}
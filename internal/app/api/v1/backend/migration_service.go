@@ -0,0 +1,37 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type MigrationServiceMigrationManagerRepo interface {
+	Import(ctx context.Context, req *domain.MigrationRequest, dryRun bool) (*domain.MigrationPlan, error)
+}
+
+type MigrationService struct {
+	cfg *config.Config
+
+	migrations MigrationServiceMigrationManagerRepo
+}
+
+func NewMigrationService(cfg *config.Config, migrations MigrationServiceMigrationManagerRepo) *MigrationService {
+	return &MigrationService{
+		cfg:        cfg,
+		migrations: migrations,
+	}
+}
+
+func (s MigrationService) Import(
+	ctx context.Context,
+	req *domain.MigrationRequest,
+	dryRun bool,
+) (*domain.MigrationPlan, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.migrations.Import(ctx, req, dryRun)
+}
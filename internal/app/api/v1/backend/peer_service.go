@@ -11,8 +11,8 @@ import (
 
 type PeerServicePeerManagerRepo interface {
 	GetPeer(ctx context.Context, id domain.PeerIdentifier) (*domain.Peer, error)
-	GetUserPeers(ctx context.Context, id domain.UserIdentifier) ([]domain.Peer, error)
-	GetInterfaceAndPeers(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Interface, []domain.Peer, error)
+	GetInterface(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Interface, error)
+	QueryPeers(ctx context.Context, filter domain.PeerFilter, opts domain.ListOptions) ([]domain.Peer, int, error)
 	PreparePeer(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Peer, error)
 	CreatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
 	UpdatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
@@ -42,39 +42,55 @@ func NewPeerService(
 	}
 }
 
-func (s PeerService) GetForInterface(ctx context.Context, id domain.InterfaceIdentifier) ([]domain.Peer, error) {
+func (s PeerService) GetForInterface(
+	ctx context.Context,
+	id domain.InterfaceIdentifier,
+	filter domain.PeerFilter,
+	opts domain.ListOptions,
+) ([]domain.Peer, int, error) {
 	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	if _, err := s.peers.GetInterface(ctx, id); err != nil {
+		return nil, 0, err
 	}
 
-	_, interfacePeers, err := s.peers.GetInterfaceAndPeers(ctx, id)
+	filter.InterfaceIdentifier = id
+	interfacePeers, total, err := s.peers.QueryPeers(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return interfacePeers, nil
+	return interfacePeers, total, nil
 }
 
-func (s PeerService) GetForUser(ctx context.Context, id domain.UserIdentifier) ([]domain.Peer, error) {
+func (s PeerService) GetForUser(
+	ctx context.Context,
+	id domain.UserIdentifier,
+	filter domain.PeerFilter,
+	opts domain.ListOptions,
+) ([]domain.Peer, int, error) {
 	if err := domain.ValidateUserAccessRights(ctx, id); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if s.cfg.Advanced.ApiAdminOnly && !domain.GetUserInfo(ctx).IsAdmin {
-		return nil, errors.Join(errors.New("only admins can access this endpoint"), domain.ErrNoPermission)
+		return nil, 0, errors.Join(errors.New("only admins can access this endpoint"), domain.ErrNoPermission)
 	}
 
 	user, err := s.users.GetUser(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	userPeers, err := s.peers.GetUserPeers(ctx, user.Identifier)
+	filter.UserIdentifier = user.Identifier
+	userPeers, total, err := s.peers.QueryPeers(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return userPeers, nil
+	return userPeers, total, nil
 }
 
 func (s PeerService) GetById(ctx context.Context, id domain.PeerIdentifier) (*domain.Peer, error) {
@@ -127,7 +143,7 @@ func (s PeerService) Create(ctx context.Context, peer *domain.Peer) (*domain.Pee
 	return createdPeer, nil
 }
 
-func (s PeerService) Update(ctx context.Context, _ domain.PeerIdentifier, peer *domain.Peer) (
+func (s PeerService) Update(ctx context.Context, id domain.PeerIdentifier, peer *domain.Peer) (
 	*domain.Peer,
 	error,
 ) {
@@ -135,12 +151,17 @@ func (s PeerService) Update(ctx context.Context, _ domain.PeerIdentifier, peer *
 		return nil, err
 	}
 
+	if peer.Identifier != id {
+		return nil, fmt.Errorf("peer id mismatch: %s != %s: %w", peer.Identifier, id, domain.ErrInvalidData)
+	}
+
 	updatedPeer, err := s.peers.UpdatePeer(ctx, peer)
 	if err != nil {
 		return nil, err
 	}
 
-	return updatedPeer, nil
+	// reload the peer, so that the returned entity tag matches the stored record
+	return s.peers.GetPeer(ctx, updatedPeer.Identifier)
 }
 
 func (s PeerService) Delete(ctx context.Context, id domain.PeerIdentifier) error {
@@ -148,6 +169,17 @@ func (s PeerService) Delete(ctx context.Context, id domain.PeerIdentifier) error
 		return err
 	}
 
+	if domain.HasIfMatchPrecondition(ctx, id) {
+		peer, err := s.peers.GetPeer(ctx, id)
+		if err != nil {
+			return err
+		}
+		// fail before the peer is removed from the WireGuard interface
+		if err := domain.PeekIfMatchPrecondition(ctx, id, true, peer.BaseModel); err != nil {
+			return err
+		}
+	}
+
 	err := s.peers.DeletePeer(ctx, id)
 	if err != nil {
 		return err
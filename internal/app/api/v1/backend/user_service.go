@@ -11,7 +11,7 @@ import (
 
 type UserManagerRepo interface {
 	GetUser(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
-	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	QueryUsers(ctx context.Context, filter domain.UserFilter, opts domain.ListOptions) ([]domain.User, int, error)
 	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	DeleteUser(ctx context.Context, id domain.UserIdentifier) error
@@ -30,17 +30,21 @@ func NewUserService(cfg *config.Config, users UserManagerRepo) *UserService {
 	}
 }
 
-func (s UserService) GetAll(ctx context.Context) ([]domain.User, error) {
+func (s UserService) GetAll(ctx context.Context, filter domain.UserFilter, opts domain.ListOptions) (
+	[]domain.User,
+	int,
+	error,
+) {
 	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	allUsers, err := s.users.GetAllUsers(ctx)
+	users, total, err := s.users.QueryUsers(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return allUsers, nil
+	return users, total, nil
 }
 
 func (s UserService) GetById(ctx context.Context, id domain.UserIdentifier) (*domain.User, error) {
@@ -90,7 +94,8 @@ func (s UserService) Update(ctx context.Context, id domain.UserIdentifier, user
 		return nil, err
 	}
 
-	return updatedUser, nil
+	// reload the user, so that the returned entity tag matches the stored record
+	return s.users.GetUser(ctx, updatedUser.Identifier)
 }
 
 func (s UserService) Delete(ctx context.Context, id domain.UserIdentifier) error {
@@ -98,6 +103,17 @@ func (s UserService) Delete(ctx context.Context, id domain.UserIdentifier) error
 		return err
 	}
 
+	if domain.HasIfMatchPrecondition(ctx, id) {
+		user, err := s.users.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		// fail before the peers of the user are removed
+		if err := domain.PeekIfMatchPrecondition(ctx, id, true, user.BaseModel); err != nil {
+			return err
+		}
+	}
+
 	err := s.users.DeleteUser(ctx, id)
 	if err != nil {
 		return err
@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type WebhookServiceWebhookManager interface {
+	GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) (*domain.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (
+		*domain.WebhookSubscription,
+		error,
+	)
+	UpdateSubscription(
+		ctx context.Context,
+		id domain.WebhookSubscriptionIdentifier,
+		subscription *domain.WebhookSubscription,
+	) (*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) error
+	GetDeliveries(
+		ctx context.Context,
+		filter domain.WebhookDeliveryFilter,
+		opts domain.ListOptions,
+	) ([]domain.WebhookDelivery, int, error)
+	GetDelivery(ctx context.Context, id domain.WebhookDeliveryIdentifier) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id domain.WebhookDeliveryIdentifier) (*domain.WebhookDelivery, error)
+}
+
+type WebhookService struct {
+	cfg *config.Config
+
+	webhooks WebhookServiceWebhookManager
+}
+
+func NewWebhookService(cfg *config.Config, webhooks WebhookServiceWebhookManager) *WebhookService {
+	return &WebhookService{
+		cfg:      cfg,
+		webhooks: webhooks,
+	}
+}
+
+func (s WebhookService) GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.webhooks.GetSubscriptions(ctx)
+}
+
+func (s WebhookService) GetSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) (
+	*domain.WebhookSubscription,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.webhooks.GetSubscription(ctx, id)
+}
+
+func (s WebhookService) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (
+	*domain.WebhookSubscription,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.webhooks.CreateSubscription(ctx, subscription)
+}
+
+func (s WebhookService) UpdateSubscription(
+	ctx context.Context,
+	id domain.WebhookSubscriptionIdentifier,
+	subscription *domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.webhooks.UpdateSubscription(ctx, id, subscription)
+}
+
+func (s WebhookService) DeleteSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+
+	return s.webhooks.DeleteSubscription(ctx, id)
+}
+
+func (s WebhookService) GetDeliveries(
+	ctx context.Context,
+	filter domain.WebhookDeliveryFilter,
+	opts domain.ListOptions,
+) ([]domain.WebhookDelivery, int, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	return s.webhooks.GetDeliveries(ctx, filter, opts)
+}
+
+func (s WebhookService) GetDelivery(ctx context.Context, id domain.WebhookDeliveryIdentifier) (
+	*domain.WebhookDelivery,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.webhooks.GetDelivery(ctx, id)
+}
+
+func (s WebhookService) Redeliver(ctx context.Context, id domain.WebhookDeliveryIdentifier) (
+	*domain.WebhookDelivery,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.webhooks.Redeliver(ctx, id)
+}
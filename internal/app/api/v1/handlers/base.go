@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-pkgz/routegroup"
 
 	"github.com/biezax/wg-portal/internal/app/api/core"
 	"github.com/biezax/wg-portal/internal/app/api/core/middleware/cors"
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
 	"github.com/biezax/wg-portal/internal/app/api/v1/models"
 	"github.com/biezax/wg-portal/internal/domain"
 )
@@ -37,13 +43,18 @@ type Handler interface {
 
 // @securityDefinitions.basic BasicAuth
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description A named API token or a JWT of an OpenID Connect provider, prefixed with "Bearer ".
+
 // @BasePath /api/v1
 // @query.collection.format multi
 
 func NewRestApi(handlers ...Handler) core.ApiEndpointSetupFunc {
 	return func() (core.ApiVersion, core.GroupSetupFn) {
 		return "v1", func(group *routegroup.Bundle) {
-			group.Use(cors.New().Handler)
+			group.Use(cors.New(cors.WithExposedHeaders(TotalCountHeader, ETagHeader)).Handler)
 
 			// Handler functions
 			for _, h := range handlers {
@@ -71,6 +82,8 @@ func ParseServiceError(err error) (int, models.Error) {
 		code = http.StatusConflict
 	case errors.Is(err, domain.ErrInvalidData):
 		code = http.StatusBadRequest
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		code = http.StatusPreconditionFailed
 	}
 
 	return code, models.Error{
@@ -79,6 +92,71 @@ func ParseServiceError(err error) (int, models.Error) {
 	}
 }
 
+// TotalCountHeader contains the total number of records of a paginated list response.
+const TotalCountHeader = "X-Total-Count"
+
+// ETagHeader contains the entity tag of a single record. Clients pass it in the If-Match header of PUT and DELETE
+// requests to detect concurrent modifications.
+const ETagHeader = "ETag"
+
+// ifMatchContext returns the request context with the If-Match precondition for the record with the given identifier.
+// The precondition is verified by the database repository, a mismatch results in a 412 response.
+func ifMatchContext(r *http.Request, id any) context.Context {
+	return domain.SetIfMatchPrecondition(r.Context(), id, request.Header(r, "If-Match"))
+}
+
+// parseListOptions parses the Offset, Limit and Sort query parameters of list endpoints.
+// Sort is the name of a field, prefixed with - for descending order, for example -CreatedAt.
+func parseListOptions(r *http.Request) (domain.ListOptions, error) {
+	var opts domain.ListOptions
+	var err error
+
+	if offset := request.Query(r, "Offset"); offset != "" {
+		if opts.Offset, err = strconv.Atoi(offset); err != nil {
+			return opts, errors.Join(fmt.Errorf("invalid offset: %w", err), domain.ErrInvalidData)
+		}
+	}
+	if limit := request.Query(r, "Limit"); limit != "" {
+		if opts.Limit, err = strconv.Atoi(limit); err != nil {
+			return opts, errors.Join(fmt.Errorf("invalid limit: %w", err), domain.ErrInvalidData)
+		}
+	}
+	sortBy := request.Query(r, "Sort")
+	opts.SortBy, opts.SortDesc = strings.TrimPrefix(sortBy, "-"), strings.HasPrefix(sortBy, "-")
+
+	return opts, nil
+}
+
+// parseBoolFilter parses an optional boolean query parameter, nil is returned if it is not set.
+func parseBoolFilter(r *http.Request, name string) (*bool, error) {
+	value := request.Query(r, name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("invalid %s filter: %w", name, err), domain.ErrInvalidData)
+	}
+	return &b, nil
+}
+
+// parseTimeFilter parses an optional RFC 3339 timestamp or date (2006-01-02) query parameter, nil is returned if it
+// is not set.
+func parseTimeFilter(r *http.Request, name string) (*time.Time, error) {
+	value := request.Query(r, name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, value); err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid %s filter, expected RFC 3339 or YYYY-MM-DD", name),
+				domain.ErrInvalidData)
+		}
+	}
+	return &t, nil
+}
+
 // region handler-interfaces
 
 type Authenticator interface {
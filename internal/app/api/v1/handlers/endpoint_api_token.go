@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type ApiTokenEndpointApiTokenService interface {
+	GetAll(ctx context.Context) ([]domain.ApiToken, error)
+	GetForUser(ctx context.Context, id domain.UserIdentifier) ([]domain.ApiToken, error)
+	Create(ctx context.Context, token *domain.ApiToken) (*domain.ApiToken, string, error)
+	Delete(ctx context.Context, id domain.ApiTokenIdentifier) error
+}
+
+type ApiTokenEndpoint struct {
+	tokens        ApiTokenEndpointApiTokenService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewApiTokenEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	tokenService ApiTokenEndpointApiTokenService,
+) *ApiTokenEndpoint {
+	return &ApiTokenEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		tokens:        tokenService,
+	}
+}
+
+func (e ApiTokenEndpoint) GetName() string {
+	return "ApiTokenEndpoint"
+}
+
+func (e ApiTokenEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/token")
+	apiGroup.Use(e.authenticator.LoggedIn())
+
+	apiGroup.With(e.authenticator.LoggedIn(ScopeAdmin)).HandleFunc("GET /all", e.handleAllGet())
+	apiGroup.HandleFunc("GET /by-user/{id...}", e.handleAllForUserGet())
+	apiGroup.HandleFunc("POST /new", e.handleCreatePost())
+	apiGroup.HandleFunc("DELETE /by-id/{id...}", e.handleDelete())
+}
+
+// handleAllGet returns a gorm Handler function.
+//
+// @ID tokens_handleAllGet
+// @Tags API Tokens
+// @Summary Get the named API tokens of all users.
+// @Description Only admins can access this endpoint. Token secrets are never returned.
+// @Produce json
+// @Success 200 {object} []models.ApiToken
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /token/all [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e ApiTokenEndpoint) handleAllGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokens, err := e.tokens.GetAll(r.Context())
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewApiTokens(tokens))
+	}
+}
+
+// handleAllForUserGet returns a gorm Handler function.
+//
+// @ID tokens_handleAllForUserGet
+// @Tags API Tokens
+// @Summary Get the named API tokens of a user.
+// @Description Normal users can only access their own tokens. Admins can access the tokens of all users.
+// @Param id path string true "The user identifier."
+// @Produce json
+// @Success 200 {object} []models.ApiToken
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /token/by-user/{id} [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e ApiTokenEndpoint) handleAllForUserGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := request.Path(r, "id")
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing user id"})
+			return
+		}
+
+		tokens, err := e.tokens.GetForUser(r.Context(), domain.UserIdentifier(id))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewApiTokens(tokens))
+	}
+}
+
+// handleCreatePost returns a gorm handler function.
+//
+// @ID tokens_handleCreatePost
+// @Tags API Tokens
+// @Summary Create a new named API token.
+// @Description Normal users can only create tokens for themselves. Admins can create tokens for all users.
+// @Description The token secret is only contained in this response, it cannot be retrieved later.
+// @Param request body models.ApiTokenCreateRequest true "The token settings."
+// @Produce json
+// @Success 200 {object} models.ApiTokenCreated
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /token/new [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e ApiTokenEndpoint) handleCreatePost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.ApiTokenCreateRequest
+		if err := request.BodyJson(r, &req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		if err := e.validator.Struct(req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		token, secret, err := e.tokens.Create(r.Context(), models.NewDomainApiToken(&req))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.ApiTokenCreated{ApiToken: *models.NewApiToken(token), Token: secret})
+	}
+}
+
+// handleDelete returns a gorm handler function.
+//
+// @ID tokens_handleDelete
+// @Tags API Tokens
+// @Summary Revoke a named API token.
+// @Description Normal users can only revoke their own tokens. Admins can revoke the tokens of all users.
+// @Param id path string true "The token identifier."
+// @Produce json
+// @Success 204 "No content if the token has been revoked."
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /token/by-id/{id} [delete]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e ApiTokenEndpoint) handleDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := request.Path(r, "id")
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing token id"})
+			return
+		}
+
+		err := e.tokens.Delete(r.Context(), domain.ApiTokenIdentifier(id))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.Status(w, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// NextCursorHeader contains the cursor of the next page of a cursor paginated list response. It is missing on the
+// last page.
+const NextCursorHeader = "X-Next-Cursor"
+
+// defaultAuditPageSize is the number of audit entries returned if no limit is given.
+const defaultAuditPageSize = 100
+
+type AuditEndpointAuditService interface {
+	GetEntries(
+		ctx context.Context,
+		filter domain.AuditEntryFilter,
+		cursor domain.AuditEntryCursor,
+		limit int,
+	) ([]domain.AuditEntry, domain.AuditEntryCursor, error)
+	ExportEntries(
+		ctx context.Context,
+		w io.Writer,
+		filter domain.AuditEntryFilter,
+		format domain.AuditExportFormat,
+	) error
+	VerifyChain(ctx context.Context) (*domain.AuditChainReport, error)
+}
+
+type AuditEndpoint struct {
+	audit         AuditEndpointAuditService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewAuditEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	auditService AuditEndpointAuditService,
+) *AuditEndpoint {
+	return &AuditEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		audit:         auditService,
+	}
+}
+
+func (e AuditEndpoint) GetName() string {
+	return "AuditEndpoint"
+}
+
+func (e AuditEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/audit")
+	apiGroup.Use(e.authenticator.LoggedIn(ScopeAdmin))
+
+	apiGroup.HandleFunc("GET /entries", e.handleEntriesGet())
+	apiGroup.HandleFunc("GET /export", e.handleExportGet())
+	apiGroup.HandleFunc("GET /verify", e.handleVerifyGet())
+}
+
+// handleEntriesGet returns a gorm Handler function.
+//
+// @ID audit_handleEntriesGet
+// @Tags Audit
+// @Summary Get a page of the audit log.
+// @Description Only admins can access this endpoint. The newest entries are returned first.
+// @Description If there are more entries, the X-Next-Cursor header contains the Cursor of the next page.
+// @Param Cursor query string false "The cursor of the page, taken from the X-Next-Cursor header of the previous page."
+// @Param Limit query int false "The maximum number of entries, at most 1000." default(100)
+// @Param From query string false "Only return entries created at or after this time (RFC 3339 or YYYY-MM-DD)."
+// @Param To query string false "Only return entries created before this time (RFC 3339 or YYYY-MM-DD)."
+// @Param Severity query string false "Only return entries with the given severity." Enums(low, high)
+// @Param User query string false "Only return entries caused by the given user."
+// @Param Origin query string false "Only return entries whose origin starts with the given value, for example peer."
+// @Param Search query string false "Only return entries whose message, origin or entity contains the given text."
+// @Produce json
+// @Success 200 {object} []models.AuditEntry
+// @Header 200 {string} X-Next-Cursor "The cursor of the next page, missing on the last page."
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /audit/entries [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e AuditEndpoint) handleEntriesGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, cursor, limit, err := parseAuditEntryListQuery(r)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		entries, next, err := e.audit.GetEntries(r.Context(), filter, cursor, limit)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		if next > 0 {
+			w.Header().Set(NextCursorHeader, strconv.FormatUint(uint64(next), 10))
+		}
+		respond.JSON(w, http.StatusOK, models.NewAuditEntries(entries))
+	}
+}
+
+// handleExportGet returns a gorm Handler function.
+//
+// @ID audit_handleExportGet
+// @Tags Audit
+// @Summary Export the audit log as CSV or JSON lines file.
+// @Description Only admins can access this endpoint. All entries that match the filter are exported, newest first.
+// @Description The changes of an entry are exported as JSON array in the CSV file.
+// @Param Format query string false "The file format, either csv or jsonl." Enums(csv, jsonl) default(csv)
+// @Param From query string false "Only export entries created at or after this time (RFC 3339 or YYYY-MM-DD)."
+// @Param To query string false "Only export entries created before this time (RFC 3339 or YYYY-MM-DD)."
+// @Param Severity query string false "Only export entries with the given severity." Enums(low, high)
+// @Param User query string false "Only export entries caused by the given user."
+// @Param Origin query string false "Only export entries whose origin starts with the given value, for example peer."
+// @Param Search query string false "Only export entries whose message, origin or entity contains the given text."
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Success 200 {file} binary "The exported audit entries"
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /audit/export [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e AuditEndpoint) handleExportGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := domain.AuditExportFormat(request.QueryDefault(r, "Format", string(domain.AuditExportFormatCsv)))
+		filter, err := parseAuditEntryFilter(r)
+		if err == nil {
+			err = format.Validate()
+		}
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		contentType := "text/csv"
+		if format == domain.AuditExportFormatJsonLines {
+			contentType = "application/x-ndjson"
+		}
+		attachment := &attachmentWriter{w: w, filename: "audit." + string(format), contentType: contentType}
+
+		err = e.audit.ExportEntries(r.Context(), attachment, filter, format)
+		switch {
+		case err != nil && !attachment.started:
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+		case err != nil:
+			// the response has already been started, the client receives an incomplete file
+			slog.Error("failed to export audit entries", "error", err)
+		}
+	}
+}
+
+// handleVerifyGet returns a gorm Handler function.
+//
+// @ID audit_handleVerifyGet
+// @Tags Audit
+// @Summary Verify the hash chain of the audit log.
+// @Description Only admins can access this endpoint. The hash of each entry and the link to its predecessor are
+// @Description verified, as well as the signed checkpoints if a signing key is configured. The first broken link is
+// @Description reported. Entries recorded before the hash chain has been enabled are skipped.
+// @Produce json
+// @Success 200 {object} models.AuditChainReport
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /audit/verify [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e AuditEndpoint) handleVerifyGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, err := e.audit.VerifyChain(r.Context())
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewAuditChainReport(report))
+	}
+}
+
+// parseAuditEntryListQuery parses the filter, the cursor and the limit of the audit entry list endpoint.
+func parseAuditEntryListQuery(r *http.Request) (domain.AuditEntryFilter, domain.AuditEntryCursor, int, error) {
+	filter, err := parseAuditEntryFilter(r)
+	if err != nil {
+		return filter, 0, 0, err
+	}
+
+	var cursor uint64
+	if value := request.Query(r, "Cursor"); value != "" {
+		if cursor, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return filter, 0, 0, errors.Join(fmt.Errorf("invalid cursor: %w", err), domain.ErrInvalidData)
+		}
+	}
+	limit, err := strconv.Atoi(request.QueryDefault(r, "Limit", strconv.Itoa(defaultAuditPageSize)))
+	if err != nil {
+		return filter, 0, 0, errors.Join(fmt.Errorf("invalid limit: %w", err), domain.ErrInvalidData)
+	}
+
+	return filter, domain.AuditEntryCursor(cursor), limit, nil
+}
+
+// parseAuditEntryFilter parses the filter query parameters of the audit endpoints.
+func parseAuditEntryFilter(r *http.Request) (domain.AuditEntryFilter, error) {
+	var filter domain.AuditEntryFilter
+	var err error
+
+	if filter.From, err = parseTimeFilter(r, "From"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeFilter(r, "To"); err != nil {
+		return filter, err
+	}
+	filter.Severity = domain.AuditSeverityLevel(request.Query(r, "Severity"))
+	filter.ContextUser = request.Query(r, "User")
+	filter.Origin = request.Query(r, "Origin")
+	filter.Search = request.Query(r, "Search")
+
+	return filter, filter.Validate()
+}
+
+// attachmentWriter starts the attachment response with the first write. Errors that occur before any data has been
+// written can still be returned as error response.
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	filename    string
+	contentType string
+	started     bool
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	if !a.started {
+		a.w.Header().Set("Content-Disposition", "attachment; filename="+a.filename)
+		a.w.Header().Set("Content-Type", a.contentType)
+		a.w.WriteHeader(http.StatusOK)
+		a.started = true
+	}
+	return a.w.Write(p)
+}
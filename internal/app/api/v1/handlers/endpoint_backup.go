@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// backupPassphraseHeader is the request header that contains the passphrase of a backup archive.
+const backupPassphraseHeader = "X-Backup-Passphrase"
+
+type BackupEndpointBackupService interface {
+	Backup(ctx context.Context, w io.Writer, passphrase string) (*domain.BackupInfo, error)
+	Restore(ctx context.Context, r io.Reader, passphrase string, dryRun bool) (*domain.BackupInfo, error)
+}
+
+type BackupEndpoint struct {
+	backups       BackupEndpointBackupService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewBackupEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	backupService BackupEndpointBackupService,
+) *BackupEndpoint {
+	return &BackupEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		backups:       backupService,
+	}
+}
+
+func (e BackupEndpoint) GetName() string {
+	return "BackupEndpoint"
+}
+
+func (e BackupEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/backup")
+	apiGroup.Use(e.authenticator.LoggedIn(ScopeAdmin))
+
+	apiGroup.HandleFunc("POST /create", e.handleBackupPost())
+	apiGroup.HandleFunc("POST /restore", e.handleRestorePost())
+}
+
+// handleBackupPost returns a gorm handler function.
+//
+// @ID backup_handleBackupPost
+// @Tags Backup
+// @Summary Create an encrypted backup of all users, interfaces, peers, statuses and audit entries.
+// @Description The backup archive is database independent, it can be restored into any supported database type.
+// @Description The archive is encrypted with the given passphrase, it cannot be restored without it.
+// @Param request body models.BackupRequest true "The backup options."
+// @Produce octet-stream
+// @Produce json
+// @Success 200 {file} binary "The encrypted backup archive"
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /backup/create [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e BackupEndpoint) handleBackupPost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.BackupRequest
+		if err := request.BodyJson(r, &req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		if err := e.validator.Struct(req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		var buf bytes.Buffer
+		info, err := e.backups.Backup(r.Context(), &buf, req.Passphrase)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		filename := "wg-portal-backup-" + info.CreatedAt.Format("20060102-150405") + ".bin"
+		respond.Attachment(w, http.StatusOK, filename, "application/octet-stream", buf.Bytes())
+	}
+}
+
+// handleRestorePost returns a gorm handler function.
+//
+// @ID backup_handleRestorePost
+// @Tags Backup
+// @Summary Restore an encrypted backup, replacing all existing data.
+// @Description The request body is the backup archive, the passphrase is passed in the X-Backup-Passphrase header.
+// @Description Backups with a different database schema version are rejected. If the archive is invalid, no data is changed.
+// @Description After the restore, the restored interfaces and peers are applied. Sessions of deleted users stay valid until they expire.
+// @Param X-Backup-Passphrase header string true "The passphrase of the backup archive."
+// @Param DryRun query bool false "Only validate the archive, nothing is restored."
+// @Param request body string true "The backup archive."
+// @Accept octet-stream
+// @Produce json
+// @Success 200 {object} models.BackupInfo
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /backup/restore [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e BackupEndpoint) handleRestorePost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		passphrase := r.Header.Get(backupPassphraseHeader)
+		if passphrase == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing " + backupPassphraseHeader + " header"})
+			return
+		}
+		dryRun, err := strconv.ParseBool(request.QueryDefault(r, "DryRun", "false"))
+		if err != nil {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "invalid DryRun value"})
+			return
+		}
+
+		info, err := e.backups.Restore(r.Context(), r.Body, passphrase, dryRun)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewBackupInfo(info))
+	}
+}
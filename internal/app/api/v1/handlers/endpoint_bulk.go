@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type BulkEndpointBulkService interface {
+	ExportUsers(ctx context.Context, w io.Writer, format domain.BulkFormat) error
+	ExportPeers(ctx context.Context, w io.Writer, format domain.BulkFormat) error
+	ImportUsers(
+		ctx context.Context,
+		r io.Reader,
+		format domain.BulkFormat,
+		mode domain.BulkImportMode,
+		dryRun bool,
+	) (*domain.BulkImportReport, error)
+	ImportPeers(
+		ctx context.Context,
+		r io.Reader,
+		format domain.BulkFormat,
+		mode domain.BulkImportMode,
+		dryRun bool,
+	) (*domain.BulkImportReport, error)
+}
+
+type BulkEndpoint struct {
+	bulk          BulkEndpointBulkService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewBulkEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	bulkService BulkEndpointBulkService,
+) *BulkEndpoint {
+	return &BulkEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		bulk:          bulkService,
+	}
+}
+
+func (e BulkEndpoint) GetName() string {
+	return "BulkEndpoint"
+}
+
+func (e BulkEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/bulk")
+	apiGroup.Use(e.authenticator.LoggedIn(ScopeAdmin))
+
+	apiGroup.HandleFunc("GET /users/export", e.handleUsersExportGet())
+	apiGroup.HandleFunc("GET /peers/export", e.handlePeersExportGet())
+	apiGroup.HandleFunc("POST /users/import", e.handleUsersImportPost())
+	apiGroup.HandleFunc("POST /peers/import", e.handlePeersImportPost())
+}
+
+// handleUsersExportGet returns a gorm handler function.
+//
+// @ID bulk_handleUsersExportGet
+// @Tags Bulk
+// @Summary Export all users as CSV or JSON file.
+// @Description Passwords are never exported. The Interfaces column lists the interfaces the user has peers on.
+// @Param Format query string false "The file format, either csv or json." Enums(csv, json) default(csv)
+// @Produce text/csv
+// @Produce json
+// @Success 200 {file} binary "The exported users"
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /bulk/users/export [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e BulkEndpoint) handleUsersExportGet() http.HandlerFunc {
+	return e.exportHandler("users", e.bulk.ExportUsers)
+}
+
+// handlePeersExportGet returns a gorm handler function.
+//
+// @ID bulk_handlePeersExportGet
+// @Tags Bulk
+// @Summary Export all peers as CSV or JSON file.
+// @Description Keys are never exported, the addresses are exported for reference only.
+// @Param Format query string false "The file format, either csv or json." Enums(csv, json) default(csv)
+// @Produce text/csv
+// @Produce json
+// @Success 200 {file} binary "The exported peers"
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /bulk/peers/export [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e BulkEndpoint) handlePeersExportGet() http.HandlerFunc {
+	return e.exportHandler("peers", e.bulk.ExportPeers)
+}
+
+// exportHandler returns a handler function that responds with the export file as attachment.
+func (e BulkEndpoint) exportHandler(
+	name string,
+	export func(ctx context.Context, w io.Writer, format domain.BulkFormat) error,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := domain.BulkFormat(request.QueryDefault(r, "Format", string(domain.BulkFormatCsv)))
+
+		var buf bytes.Buffer
+		if err := export(r.Context(), &buf, format); err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		contentType := "text/csv"
+		if format == domain.BulkFormatJson {
+			contentType = "application/json"
+		}
+		respond.Attachment(w, http.StatusOK, name+"."+string(format), contentType, buf.Bytes())
+	}
+}
+
+// handleUsersImportPost returns a gorm handler function.
+//
+// @ID bulk_handleUsersImportPost
+// @Tags Bulk
+// @Summary Create or update users from a CSV or JSON file.
+// @Description The request body is the file content, using the columns of the export. New database users need a password.
+// @Description For each listed interface, a new peer is created if the user has no peer on that interface yet.
+// @Description In atomic mode, nothing is stored if any row is invalid or fails, and the report is returned with status 400.
+// @Description In best-effort mode, valid rows are stored and the failed rows are reported.
+// @Param Format query string false "The file format, either csv or json." Enums(csv, json) default(csv)
+// @Param Mode query string false "The import mode." Enums(atomic, best-effort) default(atomic)
+// @Param DryRun query bool false "Only validate the file, nothing is stored."
+// @Param request body string true "The CSV or JSON file content."
+// @Accept plain
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.BulkImportReport
+// @Failure 400 {object} models.BulkImportReport
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /bulk/users/import [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e BulkEndpoint) handleUsersImportPost() http.HandlerFunc {
+	return e.importHandler(e.bulk.ImportUsers)
+}
+
+// handlePeersImportPost returns a gorm handler function.
+//
+// @ID bulk_handlePeersImportPost
+// @Tags Bulk
+// @Summary Create or update peers from a CSV or JSON file.
+// @Description The request body is the file content, using the columns of the export. Existing peers are identified by their public key.
+// @Description Rows without identifier create a new peer for the given user on the given interface, keys and addresses are generated.
+// @Description In atomic mode, nothing is stored if any row is invalid or fails, and the report is returned with status 400.
+// @Description In best-effort mode, valid rows are stored and the failed rows are reported.
+// @Param Format query string false "The file format, either csv or json." Enums(csv, json) default(csv)
+// @Param Mode query string false "The import mode." Enums(atomic, best-effort) default(atomic)
+// @Param DryRun query bool false "Only validate the file, nothing is stored."
+// @Param request body string true "The CSV or JSON file content."
+// @Accept plain
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.BulkImportReport
+// @Failure 400 {object} models.BulkImportReport
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /bulk/peers/import [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e BulkEndpoint) handlePeersImportPost() http.HandlerFunc {
+	return e.importHandler(e.bulk.ImportPeers)
+}
+
+// importHandler returns a handler function that imports the request body and responds with the import report.
+func (e BulkEndpoint) importHandler(
+	doImport func(
+		ctx context.Context,
+		r io.Reader,
+		format domain.BulkFormat,
+		mode domain.BulkImportMode,
+		dryRun bool,
+	) (*domain.BulkImportReport, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := domain.BulkFormat(request.QueryDefault(r, "Format", string(domain.BulkFormatCsv)))
+		mode := domain.BulkImportMode(request.QueryDefault(r, "Mode", string(domain.BulkImportModeAtomic)))
+		dryRun, err := strconv.ParseBool(request.QueryDefault(r, "DryRun", "false"))
+		if err != nil {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "invalid DryRun value"})
+			return
+		}
+
+		report, err := doImport(r.Context(), r.Body, format, mode, dryRun)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		if report.Mode == domain.BulkImportModeAtomic && report.HasErrors() {
+			respond.JSON(w, http.StatusBadRequest, models.NewBulkImportReport(report))
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewBulkImportReport(report))
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type ConfigEndpointConfigService interface {
+	Reload(ctx context.Context) (*domain.ConfigReloadReport, error)
+}
+
+type ConfigEndpoint struct {
+	configs       ConfigEndpointConfigService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewConfigEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	configService ConfigEndpointConfigService,
+) *ConfigEndpoint {
+	return &ConfigEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		configs:       configService,
+	}
+}
+
+func (e ConfigEndpoint) GetName() string {
+	return "ConfigEndpoint"
+}
+
+func (e ConfigEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/config")
+	apiGroup.Use(e.authenticator.LoggedIn(ScopeAdmin))
+
+	apiGroup.HandleFunc("POST /reload", e.handleReloadPost())
+}
+
+// handleReloadPost returns a gorm handler function.
+//
+// @ID config_handleReloadPost
+// @Tags Configuration
+// @Summary Reload the configuration file without a restart.
+// @Description The configuration is read and validated again, the same happens if the process receives SIGHUP.
+// @Description If the configuration is invalid, nothing is changed. Otherwise, the changed reloadable settings are applied:
+// @Description log settings, OIDC, OAuth and LDAP providers, Mikrotik and pfSense backends, webhook and mail settings and statistics intervals.
+// @Description All other changed settings are listed in RestartRequired, they take effect after the next restart.
+// @Produce json
+// @Success 200 {object} models.ConfigReloadReport
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /config/reload [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e ConfigEndpoint) handleReloadPost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, err := e.configs.Reload(r.Context())
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewConfigReloadReport(report))
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// eventStreamKeepAliveInterval is the interval of the keep-alive comments, they prevent proxies from closing idle
+// event streams.
+const eventStreamKeepAliveInterval = 30 * time.Second
+
+type EventEndpointEventService interface {
+	Subscribe(ctx context.Context, filter domain.StreamEventFilter, resumeAfter *uint64) (
+		[]domain.StreamEvent,
+		<-chan domain.StreamEvent,
+		func(),
+		error,
+	)
+}
+
+type EventEndpoint struct {
+	events        EventEndpointEventService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewEventEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	eventService EventEndpointEventService,
+) *EventEndpoint {
+	return &EventEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		events:        eventService,
+	}
+}
+
+func (e EventEndpoint) GetName() string {
+	return "EventEndpoint"
+}
+
+func (e EventEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/event")
+	apiGroup.Use(e.authenticator.LoggedIn())
+
+	apiGroup.HandleFunc("GET /stream", e.handleStreamGet())
+}
+
+// handleStreamGet returns a gorm Handler function.
+//
+// @ID event_handleStreamGet
+// @Tags Events
+// @Summary Stream peer, interface and audit events in real time.
+// @Description The events are sent as Server-Sent Events (text/event-stream). Admins receive all events, normal users
+// @Description only receive the peer events of their own peers.
+// @Description Event types: peer:created, peer:updated, peer:deleted, peer:connected, peer:disconnected,
+// @Description interface:created, interface:updated, interface:deleted and audit:entry.
+// @Description Interrupted streams can be resumed with the Last-Event-ID header. If the missed events are no longer
+// @Description available, the stream starts with a stream:reset event and the client should reload its data.
+// @Param type query []string false "Only send events of the given types or type prefixes, for example peer: or audit:entry." collectionFormat(multi)
+// @Param Last-Event-ID header string false "Resume the stream after the event with this id."
+// @Produce event-stream
+// @Success 200 {object} models.Event
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /event/stream [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e EventEndpoint) handleStreamGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var resumeAfter *uint64
+		if lastEventId := request.Header(r, "Last-Event-ID"); lastEventId != "" {
+			id, err := strconv.ParseUint(lastEventId, 10, 64)
+			if err != nil {
+				id = 0 // unknown ids start the stream with a reset event
+			}
+			resumeAfter = &id
+		}
+		filter := domain.StreamEventFilter{Types: request.QuerySlice(r, "type")}
+
+		replay, events, unsubscribe, err := e.events.Subscribe(r.Context(), filter, resumeAfter)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+		defer unsubscribe()
+
+		// the stream is long-lived, so the write deadline of the server must not apply
+		rc := http.NewResponseController(w)
+		_ = rc.SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no") // disable response buffering of nginx
+		w.WriteHeader(http.StatusOK)
+
+		for _, event := range replay {
+			if err := writeStreamEvent(w, &event); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			slog.Debug("event stream does not support flushing", "error", err)
+			return
+		}
+
+		keepAlive := time.NewTicker(eventStreamKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			case event, ok := <-events:
+				if !ok {
+					return // the subscription has been closed, the client can resume the stream
+				}
+				if err := writeStreamEvent(w, &event); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeStreamEvent writes an event in the Server-Sent Events format.
+func writeStreamEvent(w http.ResponseWriter, event *domain.StreamEvent) error {
+	data, err := json.Marshal(models.NewEvent(event))
+	if err != nil {
+		slog.Error("failed to serialize stream event", "id", event.Id, "type", event.Type, "error", err)
+		return nil // skip the event, the stream itself is still intact
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data)
+	return err
+}
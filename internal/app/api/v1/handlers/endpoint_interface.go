@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/go-pkgz/routegroup"
 
@@ -13,12 +14,19 @@ import (
 )
 
 type InterfaceEndpointInterfaceService interface {
-	GetAll(context.Context) ([]domain.Interface, [][]domain.Peer, error)
+	GetAll(context.Context, domain.InterfaceFilter, domain.ListOptions) (
+		[]domain.Interface, map[domain.InterfaceIdentifier]domain.PeerCounts, int, error)
 	GetById(context.Context, domain.InterfaceIdentifier) (*domain.Interface, []domain.Peer, error)
 	Prepare(context.Context) (*domain.Interface, error)
 	Create(context.Context, *domain.Interface) (*domain.Interface, error)
 	Update(context.Context, domain.InterfaceIdentifier, *domain.Interface) (*domain.Interface, []domain.Peer, error)
 	Delete(context.Context, domain.InterfaceIdentifier) error
+	ImportWgQuick(
+		ctx context.Context,
+		req *domain.WgQuickImportRequest,
+		serverConfigPath, clientConfigDir string,
+		dryRun bool,
+	) (*domain.WgQuickImportPlan, error)
 }
 
 type InterfaceEndpoint struct {
@@ -54,6 +62,8 @@ func (e InterfaceEndpoint) RegisterRoutes(g *routegroup.Bundle) {
 	apiGroup.HandleFunc("POST /new", e.handleCreatePost())
 	apiGroup.HandleFunc("PUT /by-id/{id...}", e.handleUpdatePut())
 	apiGroup.HandleFunc("DELETE /by-id/{id...}", e.handleDelete())
+
+	apiGroup.HandleFunc("POST /import/wg-quick", e.handleImportWgQuickPost())
 }
 
 // handleAllGet returns a gorm Handler function.
@@ -61,23 +71,59 @@ func (e InterfaceEndpoint) RegisterRoutes(g *routegroup.Bundle) {
 // @ID interface_handleAllGet
 // @Tags Interfaces
 // @Summary Get all interface records.
+// @Description The total number of matching records is returned in the X-Total-Count header.
+// @Param Offset query int false "The number of records to skip."
+// @Param Limit query int false "The maximum number of records, at most 1000. All records are returned by default."
+// @Param Sort query string false "The sort field (Identifier, DisplayName, Mode, CreatedAt, UpdatedAt or Disabled), prefix with - for descending order."
+// @Param Disabled query bool false "Only return disabled (true) or enabled (false) interfaces."
+// @Param CreatedAfter query string false "Only return interfaces created after the given time (RFC 3339 or YYYY-MM-DD)."
 // @Produce json
 // @Success 200 {object} []models.Interface
+// @Header 200 {integer} X-Total-Count "The total number of matching records."
+// @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /interface/all [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e InterfaceEndpoint) handleAllGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		allInterfaces, allPeersPerInterface, err := e.interfaces.GetAll(r.Context())
+		filter, opts, err := parseInterfaceListQuery(r)
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
 			return
 		}
 
-		respond.JSON(w, http.StatusOK, models.NewInterfaces(allInterfaces, allPeersPerInterface))
+		allInterfaces, peerCounts, total, err := e.interfaces.GetAll(r.Context(), filter, opts)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		w.Header().Set(TotalCountHeader, strconv.Itoa(total))
+		respond.JSON(w, http.StatusOK, models.NewInterfacesWithPeerCounts(allInterfaces, peerCounts))
+	}
+}
+
+// parseInterfaceListQuery parses the filter and list options of the interface list endpoint.
+func parseInterfaceListQuery(r *http.Request) (domain.InterfaceFilter, domain.ListOptions, error) {
+	var filter domain.InterfaceFilter
+	var err error
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		return filter, opts, err
+	}
+	if filter.Disabled, err = parseBoolFilter(r, "Disabled"); err != nil {
+		return filter, opts, err
+	}
+	if filter.CreatedAfter, err = parseTimeFilter(r, "CreatedAfter"); err != nil {
+		return filter, opts, err
 	}
+
+	return filter, opts, nil
 }
 
 // handleByIdGet returns a gorm Handler function.
@@ -88,12 +134,14 @@ func (e InterfaceEndpoint) handleAllGet() http.HandlerFunc {
 // @Param id path string true "The interface identifier."
 // @Produce json
 // @Success 200 {object} models.Interface
+// @Header 200 {string} ETag "The entity tag of the record, pass it in the If-Match header of updates."
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /interface/by-id/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e InterfaceEndpoint) handleByIdGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -110,6 +158,7 @@ func (e InterfaceEndpoint) handleByIdGet() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set(ETagHeader, iface.ETag())
 		respond.JSON(w, http.StatusOK, models.NewInterface(iface, interfacePeers))
 	}
 }
@@ -127,6 +176,7 @@ func (e InterfaceEndpoint) handleByIdGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /interface/prepare [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e InterfaceEndpoint) handlePrepareGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		iface, err := e.interfaces.Prepare(r.Context())
@@ -156,6 +206,7 @@ func (e InterfaceEndpoint) handlePrepareGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /interface/new [post]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e InterfaceEndpoint) handleCreatePost() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var iface models.Interface
@@ -186,16 +237,20 @@ func (e InterfaceEndpoint) handleCreatePost() http.HandlerFunc {
 // @Summary Update an interface record.
 // @Description This endpoint updates an existing interface with the provided data. All required fields must be filled (e.g. name, private key, public key, ...).
 // @Param id path string true "The interface identifier."
+// @Param If-Match header string false "Only apply the change if the record still has the given entity tag (ETag)."
 // @Param request body models.Interface true "The interface data."
 // @Produce json
 // @Success 200 {object} models.Interface
+// @Header 200 {string} ETag "The entity tag of the record, pass it in the If-Match header of updates."
 // @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 412 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /interface/by-id/{id} [put]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e InterfaceEndpoint) handleUpdatePut() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -222,7 +277,7 @@ func (e InterfaceEndpoint) handleUpdatePut() http.HandlerFunc {
 		}
 
 		updatedInterface, updatedInterfacePeers, err := e.interfaces.Update(
-			r.Context(),
+			ifMatchContext(r, domain.InterfaceIdentifier(id)),
 			domain.InterfaceIdentifier(id),
 			models.NewDomainInterface(&iface),
 		)
@@ -232,6 +287,7 @@ func (e InterfaceEndpoint) handleUpdatePut() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set(ETagHeader, updatedInterface.ETag())
 		respond.JSON(w, http.StatusOK, models.NewInterface(updatedInterface, updatedInterfacePeers))
 	}
 }
@@ -242,15 +298,18 @@ func (e InterfaceEndpoint) handleUpdatePut() http.HandlerFunc {
 // @Tags Interfaces
 // @Summary Delete the interface record.
 // @Param id path string true "The interface identifier."
+// @Param If-Match header string false "Only apply the change if the record still has the given entity tag (ETag)."
 // @Produce json
 // @Success 204 "No content if deletion was successful."
 // @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 412 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /interface/by-id/{id} [delete]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e InterfaceEndpoint) handleDelete() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -260,7 +319,7 @@ func (e InterfaceEndpoint) handleDelete() http.HandlerFunc {
 			return
 		}
 
-		err := e.interfaces.Delete(r.Context(), domain.InterfaceIdentifier(id))
+		err := e.interfaces.Delete(ifMatchContext(r, domain.InterfaceIdentifier(id)), domain.InterfaceIdentifier(id))
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
@@ -270,3 +329,52 @@ func (e InterfaceEndpoint) handleDelete() http.HandlerFunc {
 		respond.Status(w, http.StatusNoContent)
 	}
 }
+
+// handleImportWgQuickPost returns a gorm handler function.
+//
+// @ID interfaces_handleImportWgQuickPost
+// @Tags Interfaces
+// @Summary Import an interface and its peers from wg-quick configuration files.
+// @Description This endpoint creates a new interface from a wg-quick server configuration (for example /etc/wireguard/wg0.conf).
+// @Description Client configurations are matched to the [Peer] sections by their public key, so that imported peers keep their private keys.
+// @Description Configuration files can be uploaded or loaded from the local file system. Use DryRun to review the planned changes first.
+// @Description If conflicts are detected, nothing is stored and the import report is returned with status 409.
+// @Param request body models.WgQuickImportRequest true "The wg-quick configuration files."
+// @Produce json
+// @Success 200 {object} models.WgQuickImportResult
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 409 {object} models.WgQuickImportResult
+// @Failure 500 {object} models.Error
+// @Router /interface/import/wg-quick [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e InterfaceEndpoint) handleImportWgQuickPost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.WgQuickImportRequest
+		if err := request.BodyJson(r, &req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		if err := e.validator.Struct(req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		plan, err := e.interfaces.ImportWgQuick(r.Context(), models.NewDomainWgQuickImportRequest(&req),
+			req.ServerConfigPath, req.ClientConfigDir, req.DryRun)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		if plan.HasConflicts() {
+			respond.JSON(w, http.StatusConflict, models.NewWgQuickImportResult(plan))
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewWgQuickImportResult(plan))
+	}
+}
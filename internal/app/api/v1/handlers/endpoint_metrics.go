@@ -64,6 +64,7 @@ func (e MetricsEndpoint) RegisterRoutes(g *routegroup.Bundle) {
 // @Failure 500 {object} models.Error
 // @Router /metrics/by-interface/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e MetricsEndpoint) handleMetricsForInterfaceGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -98,6 +99,7 @@ func (e MetricsEndpoint) handleMetricsForInterfaceGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /metrics/by-user/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e MetricsEndpoint) handleMetricsForUserGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -132,6 +134,7 @@ func (e MetricsEndpoint) handleMetricsForUserGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /metrics/by-peer/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e MetricsEndpoint) handleMetricsForPeerGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type MigrationEndpointMigrationService interface {
+	Import(ctx context.Context, req *domain.MigrationRequest, dryRun bool) (*domain.MigrationPlan, error)
+}
+
+type MigrationEndpoint struct {
+	migrations    MigrationEndpointMigrationService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewMigrationEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	migrationService MigrationEndpointMigrationService,
+) *MigrationEndpoint {
+	return &MigrationEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		migrations:    migrationService,
+	}
+}
+
+func (e MigrationEndpoint) GetName() string {
+	return "MigrationEndpoint"
+}
+
+func (e MigrationEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/migration")
+	apiGroup.Use(e.authenticator.LoggedIn(ScopeAdmin))
+
+	apiGroup.HandleFunc("POST /import", e.handleImportPost())
+}
+
+// handleImportPost returns a gorm handler function.
+//
+// @ID migration_handleImportPost
+// @Tags Migration
+// @Summary Import users, an interface and its peers from wg-easy or Firezone.
+// @Description wg-easy clients are imported from the content of the wg0.json file, Firezone users and devices are read from its Postgres database.
+// @Description Keys, addresses, the enabled state and expiry dates are carried over. Use DryRun to review the planned changes first.
+// @Description If conflicts are detected, nothing is stored and the import report is returned with status 409.
+// @Param request body models.MigrationRequest true "The migration source and options."
+// @Produce json
+// @Success 200 {object} models.MigrationResult
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 409 {object} models.MigrationResult
+// @Failure 500 {object} models.Error
+// @Router /migration/import [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e MigrationEndpoint) handleImportPost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.MigrationRequest
+		if err := request.BodyJson(r, &req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		if err := e.validator.Struct(req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		migrationRequest, err := models.NewDomainMigrationRequest(&req)
+		if err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		plan, err := e.migrations.Import(r.Context(), migrationRequest, req.DryRun)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		if plan.HasConflicts() {
+			respond.JSON(w, http.StatusConflict, models.NewMigrationResult(plan))
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewMigrationResult(plan))
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/go-pkgz/routegroup"
 
@@ -13,8 +14,10 @@ import (
 )
 
 type PeerService interface {
-	GetForInterface(context.Context, domain.InterfaceIdentifier) ([]domain.Peer, error)
-	GetForUser(context.Context, domain.UserIdentifier) ([]domain.Peer, error)
+	GetForInterface(context.Context, domain.InterfaceIdentifier, domain.PeerFilter, domain.ListOptions) (
+		[]domain.Peer, int, error)
+	GetForUser(context.Context, domain.UserIdentifier, domain.PeerFilter, domain.ListOptions) (
+		[]domain.Peer, int, error)
 	GetById(context.Context, domain.PeerIdentifier) (*domain.Peer, error)
 	Prepare(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Peer, error)
 	Create(context.Context, *domain.Peer) (*domain.Peer, error)
@@ -63,13 +66,26 @@ func (e PeerEndpoint) RegisterRoutes(g *routegroup.Bundle) {
 // @ID peers_handleAllForInterfaceGet
 // @Tags Peers
 // @Summary Get all peer records for a given WireGuard interface.
+// @Description The total number of matching records is returned in the X-Total-Count header.
 // @Param id path string true "The WireGuard interface identifier."
+// @Param Offset query int false "The number of records to skip."
+// @Param Limit query int false "The maximum number of records, at most 1000. All records are returned by default."
+// @Param Sort query string false "The sort field (Identifier, DisplayName, UserIdentifier, InterfaceIdentifier, CreatedAt, UpdatedAt, ExpiresAt or Disabled), prefix with - for descending order."
+// @Param User query string false "Only return peers of the given user."
+// @Param Disabled query bool false "Only return disabled (true) or enabled (false) peers."
+// @Param Expired query bool false "Only return expired (true) or not expired (false) peers."
+// @Param Connected query bool false "Only return connected (true) or disconnected (false) peers."
+// @Param CreatedAfter query string false "Only return peers created after the given time (RFC 3339 or YYYY-MM-DD)."
 // @Produce json
 // @Success 200 {object} []models.Peer
+// @Header 200 {integer} X-Total-Count "The total number of matching records."
+// @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
+// @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /peer/by-interface/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handleAllForInterfaceGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -79,13 +95,23 @@ func (e PeerEndpoint) handleAllForInterfaceGet() http.HandlerFunc {
 			return
 		}
 
-		interfacePeers, err := e.peers.GetForInterface(r.Context(), domain.InterfaceIdentifier(id))
+		filter, opts, err := parsePeerListQuery(r)
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
 			return
 		}
+		filter.UserIdentifier = domain.UserIdentifier(request.Query(r, "User"))
 
+		interfacePeers, total, err := e.peers.GetForInterface(r.Context(), domain.InterfaceIdentifier(id), filter,
+			opts)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		w.Header().Set(TotalCountHeader, strconv.Itoa(total))
 		respond.JSON(w, http.StatusOK, models.NewPeers(interfacePeers))
 	}
 }
@@ -96,13 +122,24 @@ func (e PeerEndpoint) handleAllForInterfaceGet() http.HandlerFunc {
 // @Tags Peers
 // @Summary Get all peer records for a given user.
 // @Description Normal users can only access their own records. Admins can access all records.
+// @Description The total number of matching records is returned in the X-Total-Count header.
 // @Param id path string true "The user identifier."
+// @Param Offset query int false "The number of records to skip."
+// @Param Limit query int false "The maximum number of records, at most 1000. All records are returned by default."
+// @Param Sort query string false "The sort field (Identifier, DisplayName, UserIdentifier, InterfaceIdentifier, CreatedAt, UpdatedAt, ExpiresAt or Disabled), prefix with - for descending order."
+// @Param Disabled query bool false "Only return disabled (true) or enabled (false) peers."
+// @Param Expired query bool false "Only return expired (true) or not expired (false) peers."
+// @Param Connected query bool false "Only return connected (true) or disconnected (false) peers."
+// @Param CreatedAfter query string false "Only return peers created after the given time (RFC 3339 or YYYY-MM-DD)."
 // @Produce json
 // @Success 200 {object} []models.Peer
+// @Header 200 {integer} X-Total-Count "The total number of matching records."
+// @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /peer/by-user/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handleAllForUserGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -112,15 +149,48 @@ func (e PeerEndpoint) handleAllForUserGet() http.HandlerFunc {
 			return
 		}
 
-		interfacePeers, err := e.peers.GetForUser(r.Context(), domain.UserIdentifier(id))
+		filter, opts, err := parsePeerListQuery(r)
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
 			return
 		}
 
-		respond.JSON(w, http.StatusOK, models.NewPeers(interfacePeers))
+		userPeers, total, err := e.peers.GetForUser(r.Context(), domain.UserIdentifier(id), filter, opts)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		w.Header().Set(TotalCountHeader, strconv.Itoa(total))
+		respond.JSON(w, http.StatusOK, models.NewPeers(userPeers))
+	}
+}
+
+// parsePeerListQuery parses the filter and list options of the peer list endpoints.
+func parsePeerListQuery(r *http.Request) (domain.PeerFilter, domain.ListOptions, error) {
+	var filter domain.PeerFilter
+	var err error
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		return filter, opts, err
+	}
+	if filter.Disabled, err = parseBoolFilter(r, "Disabled"); err != nil {
+		return filter, opts, err
+	}
+	if filter.Expired, err = parseBoolFilter(r, "Expired"); err != nil {
+		return filter, opts, err
 	}
+	if filter.Connected, err = parseBoolFilter(r, "Connected"); err != nil {
+		return filter, opts, err
+	}
+	if filter.CreatedAfter, err = parseTimeFilter(r, "CreatedAfter"); err != nil {
+		return filter, opts, err
+	}
+
+	return filter, opts, nil
 }
 
 // handleByIdGet returns a gorm Handler function.
@@ -132,12 +202,14 @@ func (e PeerEndpoint) handleAllForUserGet() http.HandlerFunc {
 // @Param id path string true "The peer identifier (public key)."
 // @Produce json
 // @Success 200 {object} models.Peer
+// @Header 200 {string} ETag "The entity tag of the record, pass it in the If-Match header of updates."
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /peer/by-id/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handleByIdGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -154,6 +226,7 @@ func (e PeerEndpoint) handleByIdGet() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set(ETagHeader, peer.ETag())
 		respond.JSON(w, http.StatusOK, models.NewPeer(peer))
 	}
 }
@@ -174,6 +247,7 @@ func (e PeerEndpoint) handleByIdGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /peer/prepare/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handlePrepareGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -210,6 +284,7 @@ func (e PeerEndpoint) handlePrepareGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /peer/new [post]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handleCreatePost() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var peer models.Peer
@@ -240,16 +315,20 @@ func (e PeerEndpoint) handleCreatePost() http.HandlerFunc {
 // @Summary Update a peer record.
 // @Description Only admins can update existing records. The peer record must contain all required fields (e.g., public key, allowed IPs).
 // @Param id path string true "The peer identifier."
+// @Param If-Match header string false "Only apply the change if the record still has the given entity tag (ETag)."
 // @Param request body models.Peer true "The peer data."
 // @Produce json
 // @Success 200 {object} models.Peer
+// @Header 200 {string} ETag "The entity tag of the record, pass it in the If-Match header of updates."
 // @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 412 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /peer/by-id/{id} [put]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handleUpdatePut() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -269,13 +348,15 @@ func (e PeerEndpoint) handleUpdatePut() http.HandlerFunc {
 			return
 		}
 
-		updatedPeer, err := e.peers.Update(r.Context(), domain.PeerIdentifier(id), models.NewDomainPeer(&peer))
+		ctx := ifMatchContext(r, domain.PeerIdentifier(id))
+		updatedPeer, err := e.peers.Update(ctx, domain.PeerIdentifier(id), models.NewDomainPeer(&peer))
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
 			return
 		}
 
+		w.Header().Set(ETagHeader, updatedPeer.ETag())
 		respond.JSON(w, http.StatusOK, models.NewPeer(updatedPeer))
 	}
 }
@@ -286,15 +367,18 @@ func (e PeerEndpoint) handleUpdatePut() http.HandlerFunc {
 // @Tags Peers
 // @Summary Delete the peer record.
 // @Param id path string true "The peer identifier."
+// @Param If-Match header string false "Only apply the change if the record still has the given entity tag (ETag)."
 // @Produce json
 // @Success 204 "No content if deletion was successful."
 // @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 412 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /peer/by-id/{id} [delete]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e PeerEndpoint) handleDelete() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -304,7 +388,7 @@ func (e PeerEndpoint) handleDelete() http.HandlerFunc {
 			return
 		}
 
-		err := e.peers.Delete(r.Context(), domain.PeerIdentifier(id))
+		err := e.peers.Delete(ifMatchContext(r, domain.PeerIdentifier(id)), domain.PeerIdentifier(id))
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
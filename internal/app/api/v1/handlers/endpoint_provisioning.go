@@ -74,6 +74,7 @@ func (e ProvisioningEndpoint) RegisterRoutes(g *routegroup.Bundle) {
 // @Failure 500 {object} models.Error
 // @Router /provisioning/data/user-info [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e ProvisioningEndpoint) handleUserInfoGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := strings.TrimSpace(request.Query(r, "UserId"))
@@ -111,6 +112,7 @@ func (e ProvisioningEndpoint) handleUserInfoGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /provisioning/data/peer-config [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e ProvisioningEndpoint) handlePeerConfigGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := strings.TrimSpace(request.Query(r, "PeerId"))
@@ -148,6 +150,7 @@ func (e ProvisioningEndpoint) handlePeerConfigGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /provisioning/data/peer-qr [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e ProvisioningEndpoint) handlePeerQrGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := strings.TrimSpace(request.Query(r, "PeerId"))
@@ -184,6 +187,7 @@ func (e ProvisioningEndpoint) handlePeerQrGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /provisioning/new-peer [post]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e ProvisioningEndpoint) handleNewPeerPost() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req models.ProvisioningRequest
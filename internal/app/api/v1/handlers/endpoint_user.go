@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/go-pkgz/routegroup"
 
@@ -13,7 +14,7 @@ import (
 )
 
 type UserService interface {
-	GetAll(ctx context.Context) ([]domain.User, error)
+	GetAll(ctx context.Context, filter domain.UserFilter, opts domain.ListOptions) ([]domain.User, int, error)
 	GetById(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
 	Create(ctx context.Context, user *domain.User) (*domain.User, error)
 	Update(ctx context.Context, id domain.UserIdentifier, user *domain.User) (*domain.User, error)
@@ -58,25 +59,65 @@ func (e UserEndpoint) RegisterRoutes(g *routegroup.Bundle) {
 // @ID users_handleAllGet
 // @Tags Users
 // @Summary Get all user records.
+// @Description The total number of matching records is returned in the X-Total-Count header.
+// @Param Offset query int false "The number of records to skip."
+// @Param Limit query int false "The maximum number of records, at most 1000. All records are returned by default."
+// @Param Sort query string false "The sort field (Identifier, Email, Firstname, Lastname, CreatedAt, UpdatedAt or Disabled), prefix with - for descending order."
+// @Param Disabled query bool false "Only return disabled (true) or enabled (false) users."
+// @Param Admin query bool false "Only return admins (true) or normal users (false)."
+// @Param CreatedAfter query string false "Only return users created after the given time (RFC 3339 or YYYY-MM-DD)."
 // @Produce json
 // @Success 200 {object} []models.User
+// @Header 200 {integer} X-Total-Count "The total number of matching records."
+// @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/all [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e UserEndpoint) handleAllGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		users, err := e.users.GetAll(r.Context())
+		filter, opts, err := parseUserListQuery(r)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		users, total, err := e.users.GetAll(r.Context(), filter, opts)
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
 			return
 		}
 
+		w.Header().Set(TotalCountHeader, strconv.Itoa(total))
 		respond.JSON(w, http.StatusOK, models.NewUsers(users))
 	}
 }
 
+// parseUserListQuery parses the filter and list options of the user list endpoint.
+func parseUserListQuery(r *http.Request) (domain.UserFilter, domain.ListOptions, error) {
+	var filter domain.UserFilter
+	var err error
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		return filter, opts, err
+	}
+	if filter.Disabled, err = parseBoolFilter(r, "Disabled"); err != nil {
+		return filter, opts, err
+	}
+	if filter.Admin, err = parseBoolFilter(r, "Admin"); err != nil {
+		return filter, opts, err
+	}
+	if filter.CreatedAfter, err = parseTimeFilter(r, "CreatedAfter"); err != nil {
+		return filter, opts, err
+	}
+
+	return filter, opts, nil
+}
+
 // handleByIdGet returns a gorm Handler function.
 //
 // @ID users_handleByIdGet
@@ -86,12 +127,14 @@ func (e UserEndpoint) handleAllGet() http.HandlerFunc {
 // @Param id path string true "The user identifier."
 // @Produce json
 // @Success 200 {object} models.User
+// @Header 200 {string} ETag "The entity tag of the record, pass it in the If-Match header of updates."
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/by-id/{id} [get]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e UserEndpoint) handleByIdGet() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -108,6 +151,7 @@ func (e UserEndpoint) handleByIdGet() http.HandlerFunc {
 			return
 		}
 
+		w.Header().Set(ETagHeader, user.ETag())
 		respond.JSON(w, http.StatusOK, models.NewUser(user, true))
 	}
 }
@@ -128,6 +172,7 @@ func (e UserEndpoint) handleByIdGet() http.HandlerFunc {
 // @Failure 500 {object} models.Error
 // @Router /user/new [post]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e UserEndpoint) handleCreatePost() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var user models.User
@@ -158,16 +203,20 @@ func (e UserEndpoint) handleCreatePost() http.HandlerFunc {
 // @Summary Update a user record.
 // @Description Only admins can update existing records.
 // @Param id path string true "The user identifier."
+// @Param If-Match header string false "Only apply the change if the record still has the given entity tag (ETag)."
 // @Param request body models.User true "The user data."
 // @Produce json
 // @Success 200 {object} models.User
+// @Header 200 {string} ETag "The entity tag of the record, pass it in the If-Match header of updates."
 // @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 412 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/by-id/{id} [put]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e UserEndpoint) handleUpdatePut() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -187,13 +236,15 @@ func (e UserEndpoint) handleUpdatePut() http.HandlerFunc {
 			return
 		}
 
-		updateUser, err := e.users.Update(r.Context(), domain.UserIdentifier(id), models.NewDomainUser(&user))
+		ctx := ifMatchContext(r, domain.UserIdentifier(id))
+		updateUser, err := e.users.Update(ctx, domain.UserIdentifier(id), models.NewDomainUser(&user))
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
 			return
 		}
 
+		w.Header().Set(ETagHeader, updateUser.ETag())
 		respond.JSON(w, http.StatusOK, models.NewUser(updateUser, true))
 	}
 }
@@ -204,15 +255,18 @@ func (e UserEndpoint) handleUpdatePut() http.HandlerFunc {
 // @Tags Users
 // @Summary Delete the user record.
 // @Param id path string true "The user identifier."
+// @Param If-Match header string false "Only apply the change if the record still has the given entity tag (ETag)."
 // @Produce json
 // @Success 204 "No content if deletion was successful."
 // @Failure 400 {object} models.Error
 // @Failure 401 {object} models.Error
 // @Failure 403 {object} models.Error
 // @Failure 404 {object} models.Error
+// @Failure 412 {object} models.Error
 // @Failure 500 {object} models.Error
 // @Router /user/by-id/{id} [delete]
 // @Security BasicAuth
+// @Security BearerAuth
 func (e UserEndpoint) handleDelete() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := request.Path(r, "id")
@@ -222,7 +276,7 @@ func (e UserEndpoint) handleDelete() http.HandlerFunc {
 			return
 		}
 
-		err := e.users.Delete(r.Context(), domain.UserIdentifier(id))
+		err := e.users.Delete(ifMatchContext(r, domain.UserIdentifier(id)), domain.UserIdentifier(id))
 		if err != nil {
 			status, model := ParseServiceError(err)
 			respond.JSON(w, status, model)
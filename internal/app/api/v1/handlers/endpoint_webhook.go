@@ -0,0 +1,416 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-pkgz/routegroup"
+
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
+	"github.com/biezax/wg-portal/internal/app/api/core/respond"
+	"github.com/biezax/wg-portal/internal/app/api/v1/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type WebhookEndpointWebhookService interface {
+	GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) (*domain.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (
+		*domain.WebhookSubscription,
+		error,
+	)
+	UpdateSubscription(
+		ctx context.Context,
+		id domain.WebhookSubscriptionIdentifier,
+		subscription *domain.WebhookSubscription,
+	) (*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) error
+	GetDeliveries(
+		ctx context.Context,
+		filter domain.WebhookDeliveryFilter,
+		opts domain.ListOptions,
+	) ([]domain.WebhookDelivery, int, error)
+	GetDelivery(ctx context.Context, id domain.WebhookDeliveryIdentifier) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id domain.WebhookDeliveryIdentifier) (*domain.WebhookDelivery, error)
+}
+
+type WebhookEndpoint struct {
+	webhooks      WebhookEndpointWebhookService
+	authenticator Authenticator
+	validator     Validator
+}
+
+func NewWebhookEndpoint(
+	authenticator Authenticator,
+	validator Validator,
+	webhookService WebhookEndpointWebhookService,
+) *WebhookEndpoint {
+	return &WebhookEndpoint{
+		authenticator: authenticator,
+		validator:     validator,
+		webhooks:      webhookService,
+	}
+}
+
+func (e WebhookEndpoint) GetName() string {
+	return "WebhookEndpoint"
+}
+
+func (e WebhookEndpoint) RegisterRoutes(g *routegroup.Bundle) {
+	apiGroup := g.Mount("/webhook")
+	apiGroup.Use(e.authenticator.LoggedIn(ScopeAdmin))
+
+	apiGroup.HandleFunc("GET /subscriptions", e.handleSubscriptionsGet())
+	apiGroup.HandleFunc("GET /subscriptions/{id}", e.handleSubscriptionGet())
+	apiGroup.HandleFunc("POST /subscriptions", e.handleSubscriptionCreatePost())
+	apiGroup.HandleFunc("PUT /subscriptions/{id}", e.handleSubscriptionUpdatePut())
+	apiGroup.HandleFunc("DELETE /subscriptions/{id}", e.handleSubscriptionDelete())
+
+	apiGroup.HandleFunc("GET /deliveries", e.handleDeliveriesGet())
+	apiGroup.HandleFunc("GET /deliveries/{id}", e.handleDeliveryGet())
+	apiGroup.HandleFunc("POST /deliveries/{id}/redeliver", e.handleRedeliverPost())
+}
+
+// handleSubscriptionsGet returns a gorm Handler function.
+//
+// @ID webhooks_handleSubscriptionsGet
+// @Tags Webhooks
+// @Summary Get all webhook subscriptions.
+// @Description Only admins can access this endpoint. The subscriptions of the configuration file are returned first.
+// @Produce json
+// @Success 200 {object} []models.WebhookSubscription
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/subscriptions [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleSubscriptionsGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		subscriptions, err := e.webhooks.GetSubscriptions(r.Context())
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewWebhookSubscriptions(subscriptions))
+	}
+}
+
+// handleSubscriptionGet returns a gorm Handler function.
+//
+// @ID webhooks_handleSubscriptionGet
+// @Tags Webhooks
+// @Summary Get a specific webhook subscription.
+// @Description Only admins can access this endpoint.
+// @Param id path string true "The subscription identifier."
+// @Produce json
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/subscriptions/{id} [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleSubscriptionGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := request.Path(r, "id")
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing subscription id"})
+			return
+		}
+
+		subscription, err := e.webhooks.GetSubscription(r.Context(), domain.WebhookSubscriptionIdentifier(id))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewWebhookSubscription(subscription))
+	}
+}
+
+// handleSubscriptionCreatePost returns a gorm Handler function.
+//
+// @ID webhooks_handleSubscriptionCreatePost
+// @Tags Webhooks
+// @Summary Create a new webhook subscription.
+// @Description Only admins can access this endpoint. The subscription receives the matching events immediately.
+// @Param request body models.WebhookSubscriptionRequest true "The subscription settings."
+// @Produce json
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/subscriptions [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleSubscriptionCreatePost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.WebhookSubscriptionRequest
+		if err := request.BodyJson(r, &req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		if err := e.validator.Struct(req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		subscription, err := e.webhooks.CreateSubscription(r.Context(),
+			models.NewDomainWebhookSubscription(&req, nil))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewWebhookSubscription(subscription))
+	}
+}
+
+// handleSubscriptionUpdatePut returns a gorm Handler function.
+//
+// @ID webhooks_handleSubscriptionUpdatePut
+// @Tags Webhooks
+// @Summary Update a webhook subscription.
+// @Description Only admins can access this endpoint. Subscriptions of the configuration file cannot be changed.
+// @Description The authentication header and the secret are kept if they are omitted in the request.
+// @Param id path string true "The subscription identifier."
+// @Param request body models.WebhookSubscriptionRequest true "The subscription settings."
+// @Produce json
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/subscriptions/{id} [put]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleSubscriptionUpdatePut() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := domain.WebhookSubscriptionIdentifier(request.Path(r, "id"))
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing subscription id"})
+			return
+		}
+
+		var req models.WebhookSubscriptionRequest
+		if err := request.BodyJson(r, &req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+		if err := e.validator.Struct(req); err != nil {
+			respond.JSON(w, http.StatusBadRequest, models.Error{Code: http.StatusBadRequest, Message: err.Error()})
+			return
+		}
+
+		current, err := e.webhooks.GetSubscription(r.Context(), id)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		subscription, err := e.webhooks.UpdateSubscription(r.Context(), id,
+			models.NewDomainWebhookSubscription(&req, current))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewWebhookSubscription(subscription))
+	}
+}
+
+// handleSubscriptionDelete returns a gorm Handler function.
+//
+// @ID webhooks_handleSubscriptionDelete
+// @Tags Webhooks
+// @Summary Delete a webhook subscription.
+// @Description Only admins can access this endpoint. Subscriptions of the configuration file cannot be deleted.
+// @Description Pending deliveries of the subscription are not sent anymore.
+// @Param id path string true "The subscription identifier."
+// @Produce json
+// @Success 204 "No content if the subscription has been deleted."
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/subscriptions/{id} [delete]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleSubscriptionDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := request.Path(r, "id")
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing subscription id"})
+			return
+		}
+
+		err := e.webhooks.DeleteSubscription(r.Context(), domain.WebhookSubscriptionIdentifier(id))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.Status(w, http.StatusNoContent)
+	}
+}
+
+// handleDeliveriesGet returns a gorm Handler function.
+//
+// @ID webhooks_handleDeliveriesGet
+// @Tags Webhooks
+// @Summary Get the webhook delivery log.
+// @Description Only admins can access this endpoint. The most recent deliveries are returned first by default.
+// @Description The total number of matching records is returned in the X-Total-Count header.
+// @Param Offset query int false "The number of records to skip."
+// @Param Limit query int false "The maximum number of records, at most 1000. All records are returned by default."
+// @Param Sort query string false "The sort field (Identifier, CreatedAt, UpdatedAt, Status or Attempts), prefix with - for descending order. Defaults to -CreatedAt."
+// @Param Subscription query string false "Only return deliveries of the given subscription."
+// @Param Status query string false "Only return deliveries with the given status (pending, delivered or failed)."
+// @Produce json
+// @Success 200 {object} []models.WebhookDelivery
+// @Header 200 {integer} X-Total-Count "The total number of matching records."
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/deliveries [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleDeliveriesGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, opts, err := parseWebhookDeliveryListQuery(r)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		deliveries, total, err := e.webhooks.GetDeliveries(r.Context(), filter, opts)
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		w.Header().Set(TotalCountHeader, strconv.Itoa(total))
+		respond.JSON(w, http.StatusOK, models.NewWebhookDeliveries(deliveries))
+	}
+}
+
+// parseWebhookDeliveryListQuery parses the filter and list options of the webhook delivery list endpoint.
+func parseWebhookDeliveryListQuery(r *http.Request) (domain.WebhookDeliveryFilter, domain.ListOptions, error) {
+	var filter domain.WebhookDeliveryFilter
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		return filter, opts, err
+	}
+	if opts.SortBy == "" {
+		opts.SortBy, opts.SortDesc = "CreatedAt", true
+	}
+
+	filter.Subscription = domain.WebhookSubscriptionIdentifier(request.Query(r, "Subscription"))
+	switch status := domain.WebhookDeliveryStatus(request.Query(r, "Status")); status {
+	case "", domain.WebhookDeliveryStatusPending, domain.WebhookDeliveryStatusDelivered,
+		domain.WebhookDeliveryStatusFailed:
+		filter.Status = status
+	default:
+		return filter, opts, errors.Join(fmt.Errorf("invalid status: %s", status), domain.ErrInvalidData)
+	}
+
+	return filter, opts, nil
+}
+
+// handleDeliveryGet returns a gorm Handler function.
+//
+// @ID webhooks_handleDeliveryGet
+// @Tags Webhooks
+// @Summary Get a specific webhook delivery, including the response of the last attempt.
+// @Description Only admins can access this endpoint.
+// @Param id path string true "The delivery identifier."
+// @Produce json
+// @Success 200 {object} models.WebhookDelivery
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/deliveries/{id} [get]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleDeliveryGet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := request.Path(r, "id")
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing delivery id"})
+			return
+		}
+
+		delivery, err := e.webhooks.GetDelivery(r.Context(), domain.WebhookDeliveryIdentifier(id))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, models.NewWebhookDelivery(delivery))
+	}
+}
+
+// handleRedeliverPost returns a gorm Handler function.
+//
+// @ID webhooks_handleRedeliverPost
+// @Tags Webhooks
+// @Summary Send a webhook delivery again.
+// @Description Only admins can access this endpoint. The delivery is queued with the full number of attempts and
+// @Description keeps its identifier, so receivers can detect the duplicate.
+// @Param id path string true "The delivery identifier."
+// @Produce json
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 400 {object} models.Error
+// @Failure 401 {object} models.Error
+// @Failure 403 {object} models.Error
+// @Failure 404 {object} models.Error
+// @Failure 500 {object} models.Error
+// @Router /webhook/deliveries/{id}/redeliver [post]
+// @Security BasicAuth
+// @Security BearerAuth
+func (e WebhookEndpoint) handleRedeliverPost() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := request.Path(r, "id")
+		if id == "" {
+			respond.JSON(w, http.StatusBadRequest,
+				models.Error{Code: http.StatusBadRequest, Message: "missing delivery id"})
+			return
+		}
+
+		delivery, err := e.webhooks.Redeliver(r.Context(), domain.WebhookDeliveryIdentifier(id))
+		if err != nil {
+			status, model := ParseServiceError(err)
+			respond.JSON(w, status, model)
+			return
+		}
+
+		respond.JSON(w, http.StatusAccepted, models.NewWebhookDelivery(delivery))
+	}
+}
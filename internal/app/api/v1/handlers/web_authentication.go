@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/biezax/wg-portal/internal/app/api/core/request"
 	"github.com/biezax/wg-portal/internal/app/api/core/respond"
 	"github.com/biezax/wg-portal/internal/app/api/v0/model"
 	"github.com/biezax/wg-portal/internal/domain"
@@ -17,57 +22,57 @@ const (
 
 type UserAuthenticator interface {
 	GetUser(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
+	AuthenticateApiToken(ctx context.Context, secret, clientIp string) (*domain.User, *domain.ApiToken, error)
+}
+
+type BearerTokenAuthenticator interface {
+	AuthenticateBearerToken(ctx context.Context, token string) (*domain.User, *domain.ApiToken, error)
 }
 
 type AuthenticationHandler struct {
-	authenticator UserAuthenticator
+	authenticator    UserAuthenticator
+	jwtAuthenticator BearerTokenAuthenticator
+	trustedProxies   []string
 }
 
-func NewAuthenticationHandler(authenticator UserAuthenticator) AuthenticationHandler {
+// NewAuthenticationHandler creates a new authentication handler. The trusted proxies are used to determine the
+// client IP address, which is checked against the source networks of named API tokens.
+// The JWT authenticator verifies bearer tokens that are not named API tokens, it may be nil.
+func NewAuthenticationHandler(
+	authenticator UserAuthenticator,
+	jwtAuthenticator BearerTokenAuthenticator,
+	trustedProxies []string,
+) AuthenticationHandler {
 	return AuthenticationHandler{
-		authenticator: authenticator,
+		authenticator:    authenticator,
+		jwtAuthenticator: jwtAuthenticator,
+		trustedProxies:   trustedProxies,
 	}
 }
 
 // LoggedIn checks if a user is logged in. If scopes are given, they are validated as well.
+// Users authenticate with Basic auth (user identifier and API token), with a named API token as Bearer token or
+// with a JWT of an OpenID Connect provider as Bearer token. Named API tokens and JWTs are additionally restricted
+// to their token scopes.
 func (h AuthenticationHandler) LoggedIn(scopes ...Scope) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			username, password, ok := r.BasicAuth()
-			if !ok || username == "" || password == "" {
-				// Abort the request with the appropriate error code
-				respond.JSON(w, http.StatusUnauthorized,
-					model.Error{Code: http.StatusUnauthorized, Message: "missing credentials"})
-				return
-			}
-
-			// check if user exists in DB
-
-			ctx := domain.SetUserInfo(r.Context(), domain.SystemAdminContextUserInfo())
-			user, err := h.authenticator.GetUser(ctx, domain.UserIdentifier(username))
+			user, token, err := h.authenticate(r)
 			if err != nil {
 				// Abort the request with the appropriate error code
 				respond.JSON(w, http.StatusUnauthorized,
-					model.Error{Code: http.StatusUnauthorized, Message: "invalid credentials"})
-				return
-			}
-
-			// validate API token
-			if err := user.CheckApiToken(password); err != nil {
-				// Abort the request with the appropriate error code
-				respond.JSON(w, http.StatusUnauthorized,
-					model.Error{Code: http.StatusUnauthorized, Message: "invalid credentials"})
+					model.Error{Code: http.StatusUnauthorized, Message: err.Error()})
 				return
 			}
 
-			if !UserHasScopes(user, scopes...) {
+			if !UserHasScopes(user, scopes...) || (token != nil && !tokenAllowsRequest(token, r)) {
 				// Abort the request with the appropriate error code
 				respond.JSON(w, http.StatusForbidden,
 					model.Error{Code: http.StatusForbidden, Message: "not enough permissions"})
 				return
 			}
 
-			ctx = context.WithValue(r.Context(), domain.CtxUserInfo, &domain.ContextUserInfo{
+			ctx := context.WithValue(r.Context(), domain.CtxUserInfo, &domain.ContextUserInfo{
 				Id:      user.Identifier,
 				IsAdmin: user.IsAdmin,
 			})
@@ -79,6 +84,116 @@ func (h AuthenticationHandler) LoggedIn(scopes ...Scope) func(next http.Handler)
 	}
 }
 
+// authenticate returns the user of the request credentials. If a named API token or a JWT has been used, its scopes
+// are returned as well. The returned errors are safe to be shown to the client.
+func (h AuthenticationHandler) authenticate(r *http.Request) (*domain.User, *domain.ApiToken, error) {
+	return h.Authenticate(r.Context(), r.Header.Get("Authorization"), request.ClientIp(r, h.trustedProxies...))
+}
+
+// Authenticate returns the user of the given Authorization header value, it supports the same credentials as
+// LoggedIn. The client IP is checked against the source networks of named API tokens. If a named API token or a JWT
+// has been used, its scopes are returned as well. The returned errors are safe to be shown to the client.
+func (h AuthenticationHandler) Authenticate(ctx context.Context, authorization, clientIp string) (
+	*domain.User,
+	*domain.ApiToken,
+	error,
+) {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+
+	if bearer, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		bearer = strings.TrimSpace(bearer)
+		if h.jwtAuthenticator != nil && bearer != "" && !domain.IsApiTokenSecret(bearer) {
+			return h.authenticateJwt(ctx, bearer)
+		}
+		return h.authenticateApiToken(ctx, bearer, clientIp)
+	}
+
+	username, password, ok := parseBasicAuth(authorization)
+	if !ok || username == "" || password == "" {
+		return nil, nil, errors.New("missing credentials")
+	}
+
+	if domain.IsApiTokenSecret(password) {
+		user, token, err := h.authenticateApiToken(ctx, password, clientIp)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user.Identifier != domain.UserIdentifier(username) {
+			return nil, nil, errors.New("invalid credentials")
+		}
+		return user, token, nil
+	}
+
+	// check if user exists in DB
+	user, err := h.authenticator.GetUser(ctx, domain.UserIdentifier(username))
+	if err != nil {
+		return nil, nil, errors.New("invalid credentials")
+	}
+
+	// validate API token
+	if err := user.CheckApiToken(password); err != nil {
+		return nil, nil, errors.New("invalid credentials")
+	}
+
+	return user, nil, nil
+}
+
+// parseBasicAuth parses the value of a Basic Authorization header, it behaves like http.Request.BasicAuth.
+func parseBasicAuth(authorization string) (username, password string, ok bool) {
+	const prefix = "Basic "
+	if len(authorization) < len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(authorization[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+
+	return strings.Cut(string(decoded), ":")
+}
+
+func (h AuthenticationHandler) authenticateApiToken(ctx context.Context, secret, clientIp string) (
+	*domain.User,
+	*domain.ApiToken,
+	error,
+) {
+	if secret == "" {
+		return nil, nil, errors.New("missing credentials")
+	}
+
+	user, token, err := h.authenticator.AuthenticateApiToken(ctx, secret, clientIp)
+	if err != nil {
+		slog.Debug("api token authentication failed", "error", err)
+		return nil, nil, errors.New("invalid credentials")
+	}
+
+	return user, token, nil
+}
+
+func (h AuthenticationHandler) authenticateJwt(ctx context.Context, rawToken string) (
+	*domain.User,
+	*domain.ApiToken,
+	error,
+) {
+	user, token, err := h.jwtAuthenticator.AuthenticateBearerToken(ctx, rawToken)
+	if err != nil {
+		slog.Debug("jwt authentication failed", "error", err)
+		return nil, nil, errors.New("invalid credentials")
+	}
+
+	return user, token, nil
+}
+
+// tokenAllowsRequest checks the request against the scopes of a named API token. The resource of a request is the
+// first path segment after the API prefix, for example "peer" for /api/v1/peer/by-id/{id}.
+func tokenAllowsRequest(token *domain.ApiToken, r *http.Request) bool {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/")
+	resource, _, _ := strings.Cut(path, "/")
+	write := r.Method != http.MethodGet && r.Method != http.MethodHead
+
+	return token.Allows(resource, write)
+}
+
 func UserHasScopes(user *domain.User, scopes ...Scope) bool {
 	// No scopes give, so the check should succeed
 	if len(scopes) == 0 {
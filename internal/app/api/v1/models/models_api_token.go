@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// ApiToken is a named token for the REST API. The token secret is only returned when the token is created.
+type ApiToken struct {
+	// The unique identifier of the token.
+	Identifier string `json:"Identifier" readonly:"true" example:"0b6c0c9e-7a0c-4c4e-8d3c-0d7c2a9d6c1e"`
+	// The user that owns the token.
+	UserIdentifier string `json:"UserIdentifier" example:"uid-1234567"`
+	// The name of the token.
+	Name string `json:"Name" example:"terraform"`
+	// The scopes of the token: full, read-only, peers:write or provisioning.
+	Scopes []string `json:"Scopes" example:"read-only,peers:write"`
+	// The networks the token can be used from. If empty, the token can be used from everywhere.
+	SourceCidrs []string `json:"SourceCidrs" example:"10.0.0.0/24"`
+	// The time the token expires. If empty, the token does not expire.
+	ExpiresAt *time.Time `json:"ExpiresAt,omitempty" example:"2026-01-01T00:00:00Z"`
+	// The time the token has last been used. This field is read-only.
+	LastUsedAt *time.Time `json:"LastUsedAt,omitempty" readonly:"true" example:"2025-06-01T12:00:00Z"`
+	// The time the token has been created. This field is read-only.
+	CreatedAt time.Time `json:"CreatedAt" readonly:"true" example:"2025-01-01T00:00:00Z"`
+	// The user that created the token. This field is read-only.
+	CreatedBy string `json:"CreatedBy" readonly:"true" example:"admin@wgportal.local"`
+}
+
+func NewApiToken(src *domain.ApiToken) *ApiToken {
+	scopes := make([]string, len(src.Scopes))
+	for i, scope := range src.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &ApiToken{
+		Identifier:     string(src.Identifier),
+		UserIdentifier: string(src.UserIdentifier),
+		Name:           src.Name,
+		Scopes:         scopes,
+		SourceCidrs:    append([]string{}, src.SourceCidrs...),
+		ExpiresAt:      src.ExpiresAt,
+		LastUsedAt:     src.LastUsedAt,
+		CreatedAt:      src.CreatedAt,
+		CreatedBy:      src.CreatedBy,
+	}
+}
+
+func NewApiTokens(src []domain.ApiToken) []ApiToken {
+	results := make([]ApiToken, len(src))
+	for i := range src {
+		results[i] = *NewApiToken(&src[i])
+	}
+
+	return results
+}
+
+// ApiTokenCreateRequest contains the settings of a new named API token.
+type ApiTokenCreateRequest struct {
+	// The user that owns the token. Normal users can only create tokens for themselves.
+	UserIdentifier string `json:"UserIdentifier" binding:"required,max=64" example:"uid-1234567"`
+	// The name of the token.
+	Name string `json:"Name" binding:"required,max=64" example:"terraform"`
+	// The scopes of the token: full, read-only, peers:write or provisioning.
+	Scopes []string `json:"Scopes" binding:"required,min=1" example:"read-only,peers:write"`
+	// The networks the token can be used from. If empty, the token can be used from everywhere.
+	SourceCidrs []string `json:"SourceCidrs" binding:"omitempty,dive,cidr" example:"10.0.0.0/24"`
+	// The time the token expires. If empty, the token does not expire.
+	ExpiresAt *time.Time `json:"ExpiresAt,omitempty" example:"2026-01-01T00:00:00Z"`
+}
+
+func NewDomainApiToken(src *ApiTokenCreateRequest) *domain.ApiToken {
+	scopes := make([]domain.ApiTokenScope, len(src.Scopes))
+	for i, scope := range src.Scopes {
+		scopes[i] = domain.ApiTokenScope(scope)
+	}
+
+	return &domain.ApiToken{
+		UserIdentifier: domain.UserIdentifier(src.UserIdentifier),
+		Name:           src.Name,
+		Scopes:         scopes,
+		SourceCidrs:    src.SourceCidrs,
+		ExpiresAt:      src.ExpiresAt,
+	}
+}
+
+// ApiTokenCreated is a newly created named API token, including its secret.
+type ApiTokenCreated struct {
+	ApiToken
+	// The token secret. Use it as Bearer token, it is only shown once.
+	Token string `json:"Token" example:"wgp_4Qm0tYx4y2cJ1m0u6l7QpB8x3Yb9Z0aV1c2D3e4F5g6"`
+}
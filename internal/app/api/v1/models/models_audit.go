@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// AuditEntry is an entry of the audit log.
+type AuditEntry struct {
+	// The unique identifier of the entry.
+	Id uint64 `json:"Id" example:"42"`
+	// The time the entry has been created.
+	CreatedAt time.Time `json:"CreatedAt" example:"2025-01-01T00:00:00Z"`
+	// The user that caused the entry.
+	ContextUser string `json:"ContextUser" example:"admin@wgportal.local"`
+	// The severity of the entry, either low or high.
+	Severity string `json:"Severity" example:"low"`
+	// The origin of the entry, for example "peer: save".
+	Origin string `json:"Origin" example:"peer: save"`
+	// The message of the entry.
+	Message string `json:"Message" example:"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg= updated"`
+	// The type of the changed entity, for example user, api_token, webauthn_credential, interface, peer or config.
+	EntityType string `json:"EntityType,omitempty" example:"user"`
+	// The identifier of the changed entity.
+	EntityId string `json:"EntityId,omitempty" example:"alice"`
+	// The action that has been performed, for example create, update or delete.
+	Action string `json:"Action,omitempty" example:"update"`
+	// The IP address the change has been requested from.
+	SourceIp string `json:"SourceIp,omitempty" example:"192.168.1.10"`
+	// The changed fields. Secret values are redacted.
+	Changes []AuditChange `json:"Changes,omitempty"`
+	// The hash of the previous entry, if the hash chain is enabled.
+	PrevHash string `json:"PrevHash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// The hash of this entry, if the hash chain is enabled.
+	Hash string `json:"Hash,omitempty" example:"60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"`
+}
+
+// AuditChange is a changed field of an audit entry.
+type AuditChange struct {
+	// The name of the field, nested fields are separated by dots.
+	Field string `json:"Field" example:"IsAdmin"`
+	// The value before the change, empty if the field has been set.
+	Old any `json:"Old,omitempty"`
+	// The value after the change, empty if the field has been cleared.
+	New any `json:"New,omitempty"`
+}
+
+func NewAuditEntry(src *domain.AuditEntry) *AuditEntry {
+	return &AuditEntry{
+		Id:          src.UniqueId,
+		CreatedAt:   src.CreatedAt,
+		ContextUser: src.ContextUser,
+		Severity:    string(src.Severity),
+		Origin:      src.Origin,
+		Message:     src.Message,
+		EntityType:  src.EntityType,
+		EntityId:    src.EntityId,
+		Action:      src.Action,
+		SourceIp:    src.SourceIp,
+		Changes:     NewAuditChanges(src.Changes),
+		PrevHash:    src.PrevHash,
+		Hash:        src.Hash,
+	}
+}
+
+func NewAuditEntries(src []domain.AuditEntry) []AuditEntry {
+	dst := make([]AuditEntry, len(src))
+	for i := range src {
+		dst[i] = *NewAuditEntry(&src[i])
+	}
+	return dst
+}
+
+func NewAuditChanges(src []domain.AuditChange) []AuditChange {
+	if len(src) == 0 {
+		return nil
+	}
+	dst := make([]AuditChange, len(src))
+	for i, change := range src {
+		dst[i] = AuditChange{Field: change.Field, Old: change.Old, New: change.New}
+	}
+	return dst
+}
+
+// AuditChainReport is the result of the verification of the audit hash chain.
+type AuditChainReport struct {
+	// True if no broken link has been found.
+	Valid bool `json:"Valid" example:"true"`
+	// The number of verified entries.
+	Entries int `json:"Entries" example:"1000"`
+	// The number of entries that have been recorded before the hash chain has been enabled. They are not verified.
+	UnchainedEntries int `json:"UnchainedEntries" example:"0"`
+	// The number of verified signed checkpoints.
+	Checkpoints int `json:"Checkpoints" example:"7"`
+	// The first broken link, missing if the chain is valid.
+	FirstBroken *AuditChainBreak `json:"FirstBroken,omitempty"`
+}
+
+// AuditChainBreak describes the first broken link of the audit hash chain.
+type AuditChainBreak struct {
+	// The identifier of the entry at which the chain is broken, missing if a checkpoint revealed the break.
+	EntryId uint64 `json:"EntryId,omitempty" example:"42"`
+	// The identifier of the checkpoint that revealed the break, missing if the break has been found in the entries.
+	CheckpointId uint64 `json:"CheckpointId,omitempty" example:"3"`
+	// The creation time of the entry or checkpoint.
+	CreatedAt time.Time `json:"CreatedAt" example:"2025-01-01T00:00:00Z"`
+	// The reason of the break, for example "the entry has been altered".
+	Reason string `json:"Reason" example:"the entry has been altered"`
+}
+
+func NewAuditChainReport(src *domain.AuditChainReport) *AuditChainReport {
+	report := &AuditChainReport{
+		Valid:            src.Valid,
+		Entries:          src.Entries,
+		UnchainedEntries: src.UnchainedEntries,
+		Checkpoints:      src.Checkpoints,
+	}
+	if src.FirstBroken != nil {
+		report.FirstBroken = &AuditChainBreak{
+			EntryId:      src.FirstBroken.EntryId,
+			CheckpointId: src.FirstBroken.CheckpointId,
+			CreatedAt:    src.FirstBroken.CreatedAt,
+			Reason:       src.FirstBroken.Reason,
+		}
+	}
+
+	return report
+}
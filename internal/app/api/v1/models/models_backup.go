@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// BackupRequest contains the options of a new backup.
+type BackupRequest struct {
+	// Passphrase is used to encrypt the backup archive. It is required to restore the backup.
+	Passphrase string `json:"Passphrase" binding:"required" example:"a long and secret passphrase"`
+}
+
+// BackupInfo describes a backup archive.
+type BackupInfo struct {
+	// FormatVersion is the version of the archive format.
+	FormatVersion int `json:"FormatVersion" example:"1"`
+	// SchemaVersion is the database schema version of the backed up portal.
+	SchemaVersion uint64 `json:"SchemaVersion" example:"1"`
+	// AppVersion is the portal version that created the backup.
+	AppVersion string `json:"AppVersion" example:"v2.0.0"`
+	// DatabaseType is the database type of the backed up portal.
+	DatabaseType string `json:"DatabaseType" example:"sqlite"`
+	// CreatedAt is the time the backup has been created.
+	CreatedAt time.Time `json:"CreatedAt" example:"2025-01-01T00:00:00Z"`
+	// CreatedBy is the user that created the backup.
+	CreatedBy string `json:"CreatedBy" example:"admin@wgportal.local"`
+
+	Users                int `json:"Users" example:"10"`
+	WebAuthnCredentials  int `json:"WebAuthnCredentials" example:"2"`
+	ApiTokens            int `json:"ApiTokens" example:"3"`
+	WebhookSubscriptions int `json:"WebhookSubscriptions" example:"2"`
+	Interfaces           int `json:"Interfaces" example:"1"`
+	Peers                int `json:"Peers" example:"25"`
+	InterfaceStatuses    int `json:"InterfaceStatuses" example:"1"`
+	PeerStatuses         int `json:"PeerStatuses" example:"25"`
+	AuditEntries         int `json:"AuditEntries" example:"1000"`
+}
+
+func NewBackupInfo(src *domain.BackupInfo) *BackupInfo {
+	return &BackupInfo{
+		FormatVersion:        src.FormatVersion,
+		SchemaVersion:        src.SchemaVersion,
+		AppVersion:           src.AppVersion,
+		DatabaseType:         src.DatabaseType,
+		CreatedAt:            src.CreatedAt,
+		CreatedBy:            src.CreatedBy,
+		Users:                src.Users,
+		WebAuthnCredentials:  src.WebAuthnCredentials,
+		ApiTokens:            src.ApiTokens,
+		WebhookSubscriptions: src.WebhookSubscriptions,
+		Interfaces:           src.Interfaces,
+		Peers:                src.Peers,
+		InterfaceStatuses:    src.InterfaceStatuses,
+		PeerStatuses:         src.PeerStatuses,
+		AuditEntries:         src.AuditEntries,
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// BulkImportReport is the per-row report of a bulk import of users or peers.
+type BulkImportReport struct {
+	// Mode is the import mode, either atomic or best-effort.
+	Mode string `json:"Mode" example:"atomic"`
+	// DryRun is true if the file has only been validated.
+	DryRun bool `json:"DryRun" example:"false"`
+	// Committed is true if at least one row has been stored.
+	Committed bool `json:"Committed" example:"true"`
+	// Rows contains the result of each row of the import file.
+	Rows []BulkImportRow `json:"Rows"`
+}
+
+// BulkImportRow is the result of a single row of a bulk import.
+type BulkImportRow struct {
+	// Row is the row number, starting at 1 for the first record.
+	Row int `json:"Row" example:"1"`
+	// Identifier is the identifier of the user or peer.
+	Identifier string `json:"Identifier" example:"uid-1234567"`
+	// Status is the result of the row: valid, invalid, created, updated, failed, skipped or rolled back.
+	Status string `json:"Status" example:"created"`
+	// Errors contains the validation or processing errors of the row.
+	Errors []string `json:"Errors,omitempty" example:"missing password"`
+	// Peers are the identifiers of the peers that have been created for the row.
+	Peers []string `json:"Peers,omitempty" example:"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="`
+}
+
+func NewBulkImportReport(src *domain.BulkImportReport) *BulkImportReport {
+	report := &BulkImportReport{
+		Mode:      string(src.Mode),
+		DryRun:    src.DryRun,
+		Committed: src.Committed,
+		Rows:      make([]BulkImportRow, len(src.Rows)),
+	}
+	for i, row := range src.Rows {
+		report.Rows[i] = BulkImportRow{
+			Row:        row.Row,
+			Identifier: row.Identifier,
+			Status:     string(row.Status),
+			Errors:     row.Errors,
+		}
+		for _, peer := range row.Peers {
+			report.Rows[i].Peers = append(report.Rows[i].Peers, string(peer))
+		}
+	}
+	return report
+}
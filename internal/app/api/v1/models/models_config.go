@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// ConfigReloadReport describes the result of a configuration reload.
+type ConfigReloadReport struct {
+	// ReloadedAt is the time of the reload.
+	ReloadedAt time.Time `json:"ReloadedAt" example:"2025-01-01T00:00:00Z"`
+	// Applied lists the changed settings that have been applied.
+	Applied []string `json:"Applied" example:"auth.ldap,webhook.url"`
+	// Failed lists the components that could not apply the changed settings, they keep their previous settings.
+	Failed []ConfigReloadError `json:"Failed"`
+	// RestartRequired lists the changed settings that only take effect after a restart.
+	RestartRequired []string `json:"RestartRequired" example:"web.listening_address"`
+}
+
+// ConfigReloadError describes a component that could not apply the changed settings.
+type ConfigReloadError struct {
+	// Component is the name of the component.
+	Component string `json:"Component" example:"backends"`
+	// Settings are the changed settings that could not be applied.
+	Settings []string `json:"Settings" example:"backend.mikrotik"`
+	// Error is the error message.
+	Error string `json:"Error" example:"failed to create Mikrotik controller for backend mt1"`
+}
+
+func NewConfigReloadReport(src *domain.ConfigReloadReport) *ConfigReloadReport {
+	failed := make([]ConfigReloadError, len(src.Failed))
+	for i, f := range src.Failed {
+		failed[i] = ConfigReloadError{
+			Component: f.Component,
+			Settings:  f.Settings,
+			Error:     f.Error,
+		}
+	}
+
+	return &ConfigReloadReport{
+		ReloadedAt:      src.ReloadedAt,
+		Applied:         src.Applied,
+		Failed:          failed,
+		RestartRequired: src.RestartRequired,
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// Event is an event of the real-time event stream. It is sent as data of a Server-Sent Event, the event id and
+// type are also set as SSE id and event fields.
+type Event struct {
+	// The unique, increasing identifier of the event. It can be used to resume the stream with the Last-Event-ID header.
+	Id uint64 `json:"Id" example:"1735689600000001"`
+	// The event type, for example peer:created, peer:connected, interface:updated, audit:entry or stream:reset.
+	Type string `json:"Type" example:"peer:updated"`
+	// The time the event occurred.
+	CreatedAt time.Time `json:"CreatedAt" example:"2025-01-01T00:00:00Z"`
+	// The payload of the event: a Peer for peer changes, PeerMetrics for connects and disconnects, an Interface for
+	// interface changes and an AuditEntry for audit entries. Reset events have no payload.
+	Payload any `json:"Payload,omitempty"`
+}
+
+func NewEvent(src *domain.StreamEvent) *Event {
+	event := &Event{
+		Id:        src.Id,
+		Type:      string(src.Type),
+		CreatedAt: src.CreatedAt,
+	}
+
+	switch payload := src.Payload.(type) {
+	case domain.Peer:
+		event.Payload = NewPeer(&payload)
+	case domain.PeerStatus:
+		event.Payload = NewPeerMetrics(&payload)
+	case domain.Interface:
+		event.Payload = NewInterface(&payload, nil)
+	case domain.AuditEntry:
+		event.Payload = NewAuditEntry(&payload)
+	}
+
+	return event
+}
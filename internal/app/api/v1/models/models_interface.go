@@ -25,6 +25,8 @@ type Interface struct {
 	DisabledReason string `json:"DisabledReason" binding:"required_if=Disabled true" example:"This is a reason why the interface has been disabled."`
 	// SaveConfig is a flag that specifies if the configuration should be saved to the configuration file (wgX.conf in wg-quick format).
 	SaveConfig bool `json:"SaveConfig" example:"false"`
+	// PeerExtensionDays is the number of days users can extend their expiring peers by with the link of the expiry reminder. 0 disables self-service extensions.
+	PeerExtensionDays int `json:"PeerExtensionDays" binding:"omitempty,min=0" example:"30"`
 
 	// ListenPort is the listening port, for example: 51820. The listening port is only required for server interfaces.
 	ListenPort int `json:"ListenPort" binding:"omitempty,min=1,max=65535" example:"51820"`
@@ -99,6 +101,7 @@ func NewInterface(src *domain.Interface, peers []domain.Peer) *Interface {
 		Disabled:                   src.IsDisabled(),
 		DisabledReason:             src.DisabledReason,
 		SaveConfig:                 src.SaveConfig,
+		PeerExtensionDays:          src.PeerExtensionDays,
 		ListenPort:                 src.ListenPort,
 		Addresses:                  domain.CidrsToStringSlice(src.Addresses),
 		Dns:                        internal.SliceString(src.DnsStr),
@@ -153,6 +156,21 @@ func NewInterfaces(src []domain.Interface, srcPeers [][]domain.Peer) []Interface
 	return results
 }
 
+// NewInterfacesWithPeerCounts converts the given interfaces, the peer counters are taken from the given counts.
+func NewInterfacesWithPeerCounts(
+	src []domain.Interface,
+	counts map[domain.InterfaceIdentifier]domain.PeerCounts,
+) []Interface {
+	results := make([]Interface, len(src))
+	for i := range src {
+		results[i] = *NewInterface(&src[i], nil)
+		results[i].TotalPeers = counts[src[i].Identifier].Total
+		results[i].EnabledPeers = counts[src[i].Identifier].Enabled
+	}
+
+	return results
+}
+
 func NewDomainInterface(src *Interface) *domain.Interface {
 	now := time.Now()
 
@@ -182,6 +200,7 @@ func NewDomainInterface(src *Interface) *domain.Interface {
 		DriverType:                 "",  // currently unused
 		Disabled:                   nil, // set below
 		DisabledReason:             src.DisabledReason,
+		PeerExtensionDays:          src.PeerExtensionDays,
 		PeerDefNetworkStr:          internal.SliceToString(src.PeerDefNetwork),
 		PeerDefDnsStr:              internal.SliceToString(src.PeerDefDns),
 		PeerDefDnsSearchStr:        internal.SliceToString(src.PeerDefDnsSearch),
@@ -203,3 +222,72 @@ func NewDomainInterface(src *Interface) *domain.Interface {
 
 	return res
 }
+
+// WgQuickFile is a wg-quick configuration file.
+type WgQuickFile struct {
+	// Name is the file name, for example alice.conf. It is used as fallback display name for the matching peer.
+	Name string `json:"Name" example:"alice.conf"`
+	// Content is the content of the wg-quick configuration file.
+	Content string `json:"Content" example:"[Interface]\nPrivateKey = ..."`
+}
+
+// WgQuickImportRequest contains the wg-quick configuration files that should be imported.
+type WgQuickImportRequest struct {
+	// Identifier is the identifier of the new interface. If empty, the name of the server configuration file is used.
+	Identifier string `json:"Identifier" example:"wg0"`
+	// Backend is the backend that should manage the interface. If empty, the default backend is used.
+	Backend string `json:"Backend" example:"local"`
+
+	// ServerConfig is the server configuration file. Either ServerConfig or ServerConfigPath must be set.
+	ServerConfig *WgQuickFile `json:"ServerConfig,omitempty"`
+	// ServerConfigPath is the path to a server configuration file on the WireGuard Portal host.
+	ServerConfigPath string `json:"ServerConfigPath,omitempty" example:"/etc/wireguard/wg0.conf"`
+
+	// ClientConfigs is a list of client configuration files.
+	ClientConfigs []WgQuickFile `json:"ClientConfigs,omitempty"`
+	// ClientConfigDir is a directory on the WireGuard Portal host that contains client configuration files (*.conf).
+	ClientConfigDir string `json:"ClientConfigDir,omitempty" example:"/home/pivpn/configs"`
+
+	// DryRun only reports the planned changes and conflicts, nothing is stored.
+	DryRun bool `json:"DryRun" example:"true"`
+}
+
+// WgQuickImportResult is the result of a wg-quick import.
+type WgQuickImportResult struct {
+	// Interface is the interface that will be, or has been, created.
+	Interface *Interface `json:"Interface"`
+	// Peers are the peers that will be, or have been, created.
+	Peers []Peer `json:"Peers"`
+	// Conflicts lists problems that prevent the import. If conflicts exist, nothing is stored.
+	Conflicts []string `json:"Conflicts"`
+	// Warnings lists problems that do not prevent the import, for example peers without a client configuration.
+	Warnings []string `json:"Warnings"`
+	// Committed is true if the interface and peers have been stored.
+	Committed bool `json:"Committed" example:"false"`
+}
+
+func NewDomainWgQuickImportRequest(src *WgQuickImportRequest) *domain.WgQuickImportRequest {
+	req := &domain.WgQuickImportRequest{
+		Identifier: domain.InterfaceIdentifier(src.Identifier),
+		Backend:    domain.InterfaceBackend(src.Backend),
+		Clients:    make([]domain.WgQuickFile, len(src.ClientConfigs)),
+	}
+	if src.ServerConfig != nil {
+		req.Server = domain.WgQuickFile{Name: src.ServerConfig.Name, Content: []byte(src.ServerConfig.Content)}
+	}
+	for i, client := range src.ClientConfigs {
+		req.Clients[i] = domain.WgQuickFile{Name: client.Name, Content: []byte(client.Content)}
+	}
+
+	return req
+}
+
+func NewWgQuickImportResult(src *domain.WgQuickImportPlan) *WgQuickImportResult {
+	return &WgQuickImportResult{
+		Interface: NewInterface(src.Interface, src.Peers),
+		Peers:     NewPeers(src.Peers),
+		Conflicts: src.Conflicts,
+		Warnings:  src.Warnings,
+		Committed: src.Committed,
+	}
+}
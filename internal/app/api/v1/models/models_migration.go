@@ -0,0 +1,93 @@
+package models
+
+import (
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// MigrationRequest describes an import from another WireGuard management tool.
+type MigrationRequest struct {
+	// Source is the WireGuard management tool to import data from.
+	Source string `json:"Source" example:"wg-easy" binding:"required,oneof=wg-easy firezone"`
+	// Data is the content of the wg0.json file of wg-easy. Only used for the wg-easy source.
+	Data string `json:"Data,omitempty"`
+	// Dsn is the DSN of the Firezone Postgres database. Only used for the Firezone source.
+	Dsn string `json:"Dsn,omitempty" example:"host=localhost user=postgres password=secret dbname=firezone"`
+
+	// Identifier is the identifier of the new interface. Defaults to wg0 (wg-easy) or wg-firezone (Firezone).
+	Identifier string `json:"Identifier,omitempty" example:"wg0"`
+	// Backend is the backend that should manage the interface. If empty, the default backend is used.
+	Backend string `json:"Backend,omitempty" example:"local"`
+	// PrivateKey is the private key of the interface. Firezone stores the key outside the database
+	// (/var/firezone/private_key), if it is not set, a new key pair is generated.
+	PrivateKey string `json:"PrivateKey,omitempty" example:"yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk="`
+	// Addresses are the addresses of the interface. Defaults to the default network of the source.
+	Addresses []string `json:"Addresses,omitempty" example:"10.8.0.1/24"`
+	// ListenPort is the listen port of the interface. Defaults to 51820.
+	ListenPort int `json:"ListenPort,omitempty" example:"51820"`
+	// Endpoint is the public endpoint of the interface (host[:port]), for wg-easy the value of WG_HOST.
+	Endpoint string `json:"Endpoint,omitempty" example:"vpn.example.com:51820"`
+	// UserIdentifier is the existing user that owns the imported wg-easy peers. wg-easy has no user accounts.
+	UserIdentifier string `json:"UserIdentifier,omitempty" example:"uid-1234567"`
+
+	// DryRun only reports the planned changes and conflicts, nothing is stored.
+	DryRun bool `json:"DryRun" example:"true"`
+}
+
+// MigrationResult is the result of an import from another WireGuard management tool.
+type MigrationResult struct {
+	// Source is the WireGuard management tool the data has been imported from.
+	Source string `json:"Source" example:"wg-easy"`
+	// Interface is the interface that will be, or has been, created.
+	Interface *Interface `json:"Interface"`
+	// Users are the new users that will be, or have been, created. Existing users are not modified.
+	Users []User `json:"Users"`
+	// Peers are the peers that will be, or have been, created.
+	Peers []Peer `json:"Peers"`
+	// Conflicts lists problems that prevent the import. If conflicts exist, nothing is stored.
+	Conflicts []string `json:"Conflicts"`
+	// Warnings lists problems that do not prevent the import, for example unknown private keys.
+	Warnings []string `json:"Warnings"`
+	// Committed is true if the users, interface and peers have been stored.
+	Committed bool `json:"Committed" example:"false"`
+}
+
+func NewDomainMigrationRequest(src *MigrationRequest) (*domain.MigrationRequest, error) {
+	req := &domain.MigrationRequest{
+		Source:         domain.MigrationSource(src.Source),
+		Data:           []byte(src.Data),
+		Dsn:            src.Dsn,
+		Identifier:     domain.InterfaceIdentifier(src.Identifier),
+		Backend:        domain.InterfaceBackend(src.Backend),
+		PrivateKey:     src.PrivateKey,
+		ListenPort:     src.ListenPort,
+		Endpoint:       src.Endpoint,
+		UserIdentifier: domain.UserIdentifier(src.UserIdentifier),
+	}
+
+	if len(src.Addresses) > 0 {
+		addresses, err := domain.CidrsFromArray(src.Addresses)
+		if err != nil {
+			return nil, err
+		}
+		req.Addresses = addresses
+	}
+
+	return req, nil
+}
+
+func NewMigrationResult(src *domain.MigrationPlan) *MigrationResult {
+	users := make([]User, len(src.Users))
+	for i := range src.Users {
+		users[i] = *NewUser(&src.Users[i], false)
+	}
+
+	return &MigrationResult{
+		Source:    string(src.Source),
+		Interface: NewInterface(src.Interface, src.Peers),
+		Users:     users,
+		Peers:     NewPeers(src.Peers),
+		Conflicts: src.Conflicts,
+		Warnings:  src.Warnings,
+		Committed: src.Committed,
+	}
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// WebhookSubscription is a webhook receiver. The authentication header and the secret are never returned.
+type WebhookSubscription struct {
+	// The unique identifier of the subscription. Subscriptions of the configuration file use their name.
+	Identifier string `json:"Identifier" readonly:"true" example:"0b6c0c9e-7a0c-4c4e-8d3c-0d7c2a9d6c1e"`
+	// The origin of the subscription: config or database. Subscriptions of the configuration file are read-only.
+	Source string `json:"Source" readonly:"true" example:"database"`
+	// The name of the subscription.
+	Name string `json:"Name" example:"noc"`
+	// The URL the webhooks are sent to.
+	Url string `json:"Url" example:"https://noc.example.com/wg-portal-hook"`
+	// Whether the subscription has an authentication header.
+	HasAuthentication bool `json:"HasAuthentication" readonly:"true" example:"true"`
+	// Whether the requests of the subscription are signed.
+	HasSecret bool `json:"HasSecret" readonly:"true" example:"true"`
+	// Disabled subscriptions do not receive webhooks.
+	Disabled bool `json:"Disabled" example:"false"`
+	// A built-in payload template: slack, teams, discord or mattermost. Empty for the event data JSON.
+	Preset string `json:"Preset" example:"slack"`
+	// A custom text/template of the request body. It cannot be combined with a preset.
+	Template string `json:"Template" example:""`
+	// The content type of the custom template, application/json if empty.
+	ContentType string `json:"ContentType" example:""`
+	// The entities the subscription receives: user, peer, peer_metric or interface. Empty for all entities.
+	Entities []string `json:"Entities" example:"peer_metric"`
+	// The events the subscription receives: create, update, delete, connect, disconnect or expiring. Empty for all events.
+	Events []string `json:"Events" example:"connect,disconnect"`
+	// Only receive events of peers and interfaces of the given interfaces. Empty for all interfaces.
+	Interfaces []string `json:"Interfaces" example:"wg0"`
+	// Only receive events of the given users and their peers. Empty for all users.
+	Users []string `json:"Users" example:""`
+
+	// The time the subscription has been created. This field is read-only.
+	CreatedAt time.Time `json:"CreatedAt" readonly:"true" example:"2025-01-01T00:00:00Z"`
+	// The user that created the subscription. This field is read-only.
+	CreatedBy string `json:"CreatedBy" readonly:"true" example:"admin@wgportal.local"`
+	// The time the subscription has been updated last. This field is read-only.
+	UpdatedAt time.Time `json:"UpdatedAt" readonly:"true" example:"2025-01-01T00:00:00Z"`
+	// The user that updated the subscription last. This field is read-only.
+	UpdatedBy string `json:"UpdatedBy" readonly:"true" example:"admin@wgportal.local"`
+}
+
+func NewWebhookSubscription(src *domain.WebhookSubscription) *WebhookSubscription {
+	subscription := &WebhookSubscription{
+		Identifier:        string(src.Identifier),
+		Source:            string(src.Source),
+		Name:              src.Name,
+		Url:               src.Url,
+		HasAuthentication: src.Authentication != "",
+		HasSecret:         src.Secret != "",
+		Disabled:          src.Disabled,
+		Preset:            src.Preset,
+		Template:          src.Template,
+		ContentType:       src.ContentType,
+		Entities:          append([]string{}, src.Entities...),
+		Events:            append([]string{}, src.Events...),
+		Interfaces:        make([]string, len(src.Interfaces)),
+		Users:             make([]string, len(src.Users)),
+		CreatedAt:         src.CreatedAt,
+		CreatedBy:         src.CreatedBy,
+		UpdatedAt:         src.UpdatedAt,
+		UpdatedBy:         src.UpdatedBy,
+	}
+	for i, iface := range src.Interfaces {
+		subscription.Interfaces[i] = string(iface)
+	}
+	for i, user := range src.Users {
+		subscription.Users[i] = string(user)
+	}
+
+	return subscription
+}
+
+func NewWebhookSubscriptions(src []domain.WebhookSubscription) []WebhookSubscription {
+	results := make([]WebhookSubscription, len(src))
+	for i := range src {
+		results[i] = *NewWebhookSubscription(&src[i])
+	}
+
+	return results
+}
+
+// WebhookSubscriptionRequest contains the settings of a webhook subscription.
+type WebhookSubscriptionRequest struct {
+	// The name of the subscription.
+	Name string `json:"Name" binding:"required,max=64" example:"noc"`
+	// The URL the webhooks are sent to.
+	Url string `json:"Url" binding:"required,url" example:"https://noc.example.com/wg-portal-hook"`
+	// The Authorization header of the requests. If omitted on updates, the current value is kept.
+	Authentication *string `json:"Authentication,omitempty" example:"Bearer my-token"`
+	// The key of the HMAC-SHA256 request signature. If omitted on updates, the current value is kept.
+	Secret *string `json:"Secret,omitempty" example:"a-long-random-string"`
+	// Disabled subscriptions do not receive webhooks.
+	Disabled bool `json:"Disabled" example:"false"`
+	// A built-in payload template: slack, teams, discord or mattermost. Empty for the event data JSON.
+	Preset string `json:"Preset" binding:"omitempty,oneof=slack teams discord mattermost" example:"slack"`
+	// A custom text/template of the request body. It cannot be combined with a preset.
+	Template string `json:"Template" example:""`
+	// The content type of the custom template, application/json if empty.
+	ContentType string `json:"ContentType" example:""`
+	// The entities the subscription receives: user, peer, peer_metric or interface. Empty for all entities.
+	Entities []string `json:"Entities" example:"peer_metric"`
+	// The events the subscription receives: create, update, delete, connect, disconnect or expiring. Empty for all events.
+	Events []string `json:"Events" example:"connect,disconnect"`
+	// Only receive events of peers and interfaces of the given interfaces. Empty for all interfaces.
+	Interfaces []string `json:"Interfaces" example:"wg0"`
+	// Only receive events of the given users and their peers. Empty for all users.
+	Users []string `json:"Users" example:""`
+}
+
+// NewDomainWebhookSubscription creates a domain subscription from the request. The authentication header and the
+// secret of the current subscription are kept if they are omitted in the request, current may be nil.
+func NewDomainWebhookSubscription(
+	src *WebhookSubscriptionRequest,
+	current *domain.WebhookSubscription,
+) *domain.WebhookSubscription {
+	subscription := &domain.WebhookSubscription{
+		Name:        src.Name,
+		Url:         src.Url,
+		Disabled:    src.Disabled,
+		Preset:      src.Preset,
+		Template:    src.Template,
+		ContentType: src.ContentType,
+		Entities:    src.Entities,
+		Events:      src.Events,
+	}
+	if current != nil {
+		subscription.Authentication = current.Authentication
+		subscription.Secret = current.Secret
+	}
+	if src.Authentication != nil {
+		subscription.Authentication = *src.Authentication
+	}
+	if src.Secret != nil {
+		subscription.Secret = *src.Secret
+	}
+	for _, iface := range src.Interfaces {
+		subscription.Interfaces = append(subscription.Interfaces, domain.InterfaceIdentifier(iface))
+	}
+	for _, user := range src.Users {
+		subscription.Users = append(subscription.Users, domain.UserIdentifier(user))
+	}
+
+	return subscription
+}
+
+// WebhookDelivery is a webhook request of the delivery log, including the response of the last attempt.
+type WebhookDelivery struct {
+	// The unique identifier of the delivery, it is sent in the X-Wg-Portal-Delivery header.
+	Identifier string `json:"Identifier" example:"01964f3a-6c1e-7b9e-8f3c-0d7c2a9d6c1e"`
+	// The time the event occurred.
+	CreatedAt time.Time `json:"CreatedAt" example:"2025-01-01T00:00:00Z"`
+	// The time the delivery has been updated last.
+	UpdatedAt time.Time `json:"UpdatedAt" example:"2025-01-01T00:00:05Z"`
+
+	// The identifier of the subscription the delivery belongs to.
+	Subscription string `json:"Subscription" example:"default"`
+	// The event type: create, update, delete, connect, disconnect or expiring.
+	Event string `json:"Event" example:"create"`
+	// The entity type: user, peer, peer_metric or interface.
+	Entity string `json:"Entity" example:"peer"`
+	// The identifier of the entity.
+	EntityIdentifier string `json:"EntityIdentifier" example:"xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="`
+	// The URL the webhook is sent to.
+	Url string `json:"Url" example:"https://example.com/wg-portal-hook"`
+	// The request body.
+	Payload string `json:"Payload" example:"{\"event\":\"create\",\"entity\":\"peer\"}"`
+	// The content type of the request body.
+	ContentType string `json:"ContentType" example:"application/json"`
+
+	// The delivery status: pending, delivered or failed.
+	Status string `json:"Status" example:"delivered"`
+	// The number of attempts.
+	Attempts int `json:"Attempts" example:"1"`
+	// The time of the next attempt, only set for pending deliveries.
+	NextAttemptAt *time.Time `json:"NextAttemptAt,omitempty" example:"2025-01-01T00:00:30Z"`
+	// The time of the last attempt.
+	LastAttemptAt *time.Time `json:"LastAttemptAt,omitempty" example:"2025-01-01T00:00:05Z"`
+
+	// The HTTP status code of the last response, 0 if no response has been received.
+	ResponseStatus int `json:"ResponseStatus" example:"200"`
+	// The body of the last response, truncated to 4 KiB.
+	ResponseBody string `json:"ResponseBody" example:"ok"`
+	// The error of the last attempt, empty if the last attempt succeeded.
+	LastError string `json:"LastError,omitempty" example:"unexpected response status: 502 Bad Gateway"`
+}
+
+func NewWebhookDelivery(src *domain.WebhookDelivery) *WebhookDelivery {
+	return &WebhookDelivery{
+		Identifier:       string(src.Identifier),
+		CreatedAt:        src.CreatedAt,
+		UpdatedAt:        src.UpdatedAt,
+		Subscription:     string(src.Subscription),
+		Event:            src.Event,
+		Entity:           src.Entity,
+		EntityIdentifier: src.EntityIdentifier,
+		Url:              src.Url,
+		Payload:          string(src.Payload),
+		ContentType:      src.ContentType,
+		Status:           string(src.Status),
+		Attempts:         src.Attempts,
+		NextAttemptAt:    src.NextAttemptAt,
+		LastAttemptAt:    src.LastAttemptAt,
+		ResponseStatus:   src.ResponseStatus,
+		ResponseBody:     src.ResponseBody,
+		LastError:        src.LastError,
+	}
+}
+
+func NewWebhookDeliveries(src []domain.WebhookDelivery) []WebhookDelivery {
+	results := make([]WebhookDelivery, len(src))
+	for i := range src {
+		results[i] = *NewWebhookDelivery(&src[i])
+	}
+
+	return results
+}
@@ -2,23 +2,43 @@ package audit
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
+	"io"
 
+	"github.com/biezax/wg-portal/internal/config"
 	"github.com/biezax/wg-portal/internal/domain"
 )
 
+// exportBatchSize is the number of audit entries that are loaded at once during an export.
+const exportBatchSize = domain.MaxListLimit
+
 type ManagerDatabaseRepo interface {
 	// GetAllAuditEntries retrieves all audit entries from the database.
 	// The entries are ordered by timestamp, with the newest entries first.
 	GetAllAuditEntries(ctx context.Context) ([]domain.AuditEntry, error)
+	// QueryAuditEntries returns at most limit audit entries that match the filter, newest first, starting after
+	// the given cursor.
+	QueryAuditEntries(
+		ctx context.Context,
+		filter domain.AuditEntryFilter,
+		cursor domain.AuditEntryCursor,
+		limit int,
+	) ([]domain.AuditEntry, error)
+	// GetAuditEntriesAfter returns at most limit audit entries with an identifier greater than the given one,
+	// oldest first.
+	GetAuditEntriesAfter(ctx context.Context, afterId uint64, limit int) ([]domain.AuditEntry, error)
+	// GetAuditCheckpoints returns all audit checkpoints, oldest first.
+	GetAuditCheckpoints(ctx context.Context) ([]domain.AuditCheckpoint, error)
 }
 
 type Manager struct {
-	db ManagerDatabaseRepo
+	cfg *config.Config
+	db  ManagerDatabaseRepo
 }
 
-func NewManager(db ManagerDatabaseRepo) *Manager {
-	return &Manager{db: db}
+func NewManager(cfg *config.Config, db ManagerDatabaseRepo) *Manager {
+	return &Manager{cfg: cfg, db: db}
 }
 
 func (m *Manager) GetAll(ctx context.Context) ([]domain.AuditEntry, error) {
@@ -35,3 +55,97 @@ func (m *Manager) GetAll(ctx context.Context) ([]domain.AuditEntry, error) {
 
 	return entries, nil
 }
+
+// GetEntries returns at most limit audit entries that match the filter, newest first, starting after the given
+// cursor. If there are more entries, the returned cursor continues the query, otherwise it is zero.
+func (m *Manager) GetEntries(
+	ctx context.Context,
+	filter domain.AuditEntryFilter,
+	cursor domain.AuditEntryCursor,
+	limit int,
+) ([]domain.AuditEntry, domain.AuditEntryCursor, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || limit > domain.MaxListLimit {
+		return nil, 0, fmt.Errorf("limit must be between 1 and %d: %w", domain.MaxListLimit, domain.ErrInvalidData)
+	}
+
+	// one additional entry is loaded to find out whether there is a next page
+	entries, err := m.db.QueryAuditEntries(ctx, filter, cursor, limit+1)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	if len(entries) <= limit {
+		return entries, 0, nil
+	}
+
+	entries = entries[:limit]
+	return entries, domain.AuditEntryCursor(entries[limit-1].UniqueId), nil
+}
+
+// ExportEntries writes all audit entries that match the filter to the given writer, newest first. The entries are
+// loaded in batches, so that large audit logs can be exported without loading them into memory at once.
+func (m *Manager) ExportEntries(
+	ctx context.Context,
+	w io.Writer,
+	filter domain.AuditEntryFilter,
+	format domain.AuditExportFormat,
+) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	writer := newExportWriter(w, format)
+	var cursor domain.AuditEntryCursor
+	for {
+		entries, err := m.db.QueryAuditEntries(ctx, filter, cursor, exportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to query audit entries: %w", err)
+		}
+		for _, entry := range entries {
+			if err := writer.Write(entry); err != nil {
+				return fmt.Errorf("failed to write audit entry %d: %w", entry.UniqueId, err)
+			}
+		}
+		if len(entries) < exportBatchSize {
+			break
+		}
+		cursor = domain.AuditEntryCursor(entries[len(entries)-1].UniqueId)
+	}
+
+	return writer.Flush()
+}
+
+// VerifyChain verifies the hash chain of the audit log and the signatures of the checkpoints. The report contains
+// the first broken link, if any. Checkpoints are only verified if a signing key is configured.
+func (m *Manager) VerifyChain(ctx context.Context) (*domain.AuditChainReport, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	var publicKey ed25519.PublicKey
+	if m.cfg.Statistics.AuditSigningKey != "" {
+		key, err := loadSigningKey(m.cfg.Statistics.AuditSigningKey)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = key.Public().(ed25519.PublicKey)
+	}
+
+	checkpoints, err := m.db.GetAuditCheckpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit checkpoints: %w", err)
+	}
+
+	return verifyChain(ctx, m.db, checkpoints, publicKey)
+}
@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockRepo struct {
+	entries     []domain.AuditEntry // newest first
+	checkpoints []domain.AuditCheckpoint
+}
+
+func (r *mockRepo) SaveAuditEntry(_ context.Context, entry *domain.AuditEntry) error {
+	entry.UniqueId = uint64(len(r.entries) + 1)
+	r.entries = append([]domain.AuditEntry{*entry}, r.entries...)
+	return nil
+}
+
+func (r *mockRepo) GetLastAuditEntry(context.Context) (*domain.AuditEntry, error) {
+	if len(r.entries) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &r.entries[0], nil
+}
+
+func (r *mockRepo) GetAuditEntriesAfter(_ context.Context, afterId uint64, limit int) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+	for i := len(r.entries) - 1; i >= 0 && len(entries) < limit; i-- {
+		if r.entries[i].UniqueId > afterId {
+			entries = append(entries, r.entries[i])
+		}
+	}
+	return entries, nil
+}
+
+func (r *mockRepo) DeleteAuditEntriesBefore(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (r *mockRepo) DeleteAuditEntriesExceeding(context.Context, int) (int, error) {
+	return 0, nil
+}
+
+func (r *mockRepo) SaveAuditCheckpoint(_ context.Context, checkpoint *domain.AuditCheckpoint) error {
+	checkpoint.Id = uint64(len(r.checkpoints) + 1)
+	r.checkpoints = append(r.checkpoints, *checkpoint)
+	return nil
+}
+
+func (r *mockRepo) GetAuditCheckpoints(context.Context) ([]domain.AuditCheckpoint, error) {
+	return r.checkpoints, nil
+}
+
+func (r *mockRepo) GetAllAuditEntries(context.Context) ([]domain.AuditEntry, error) {
+	return r.entries, nil
+}
+
+func (r *mockRepo) QueryAuditEntries(
+	_ context.Context,
+	_ domain.AuditEntryFilter,
+	cursor domain.AuditEntryCursor,
+	limit int,
+) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+	for _, entry := range r.entries {
+		if cursor > 0 && entry.UniqueId >= uint64(cursor) {
+			continue
+		}
+		if len(entries) == limit {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newMockRepo(count int) *mockRepo {
+	repo := &mockRepo{}
+	for id := count; id > 0; id-- {
+		repo.entries = append(repo.entries, domain.AuditEntry{UniqueId: uint64(id), Severity: "low",
+			Origin: "peer: save", Message: "peer saved"})
+	}
+	return repo
+}
+
+func adminContext() context.Context {
+	return domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+}
+
+func TestManager_GetEntries(t *testing.T) {
+	m := NewManager(&config.Config{}, newMockRepo(5))
+
+	entries, cursor, err := m.GetEntries(adminContext(), domain.AuditEntryFilter{}, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, domain.AuditEntryCursor(4), cursor)
+
+	entries, cursor, err = m.GetEntries(adminContext(), domain.AuditEntryFilter{}, cursor, 3)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, uint64(1), entries[2].UniqueId)
+	assert.Zero(t, cursor, "there are no more entries")
+
+	_, _, err = m.GetEntries(adminContext(), domain.AuditEntryFilter{Severity: "medium"}, 0, 2)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+
+	userCtx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "user"})
+	_, _, err = m.GetEntries(userCtx, domain.AuditEntryFilter{}, 0, 2)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+}
+
+func TestManager_ExportEntries(t *testing.T) {
+	count := exportBatchSize + 2
+	repo := newMockRepo(count)
+	repo.entries[0].Changes = []domain.AuditChange{{Field: "IsAdmin", Old: false, New: true}}
+	m := NewManager(&config.Config{}, repo)
+
+	var buf bytes.Buffer
+	require.NoError(t, m.ExportEntries(adminContext(), &buf, domain.AuditEntryFilter{}, domain.AuditExportFormatCsv))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, count+1, "all batches and the header are exported")
+	assert.Equal(t, exportColumns, rows[0])
+	assert.Equal(t, `[{"field":"IsAdmin","old":false,"new":true}]`, rows[1][10])
+
+	buf.Reset()
+	require.NoError(t, m.ExportEntries(adminContext(), &buf, domain.AuditEntryFilter{},
+		domain.AuditExportFormatJsonLines))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, count)
+	var last exportEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[count-1]), &last))
+	assert.Equal(t, uint64(1), last.Id)
+
+	err = m.ExportEntries(adminContext(), &buf, domain.AuditEntryFilter{}, "xml")
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+}
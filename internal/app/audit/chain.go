@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// verifyBatchSize is the number of audit entries that are loaded at once during the verification of the chain.
+const verifyBatchSize = domain.MaxListLimit
+
+type chainRepo interface {
+	// GetAuditEntriesAfter returns at most limit audit entries with an identifier greater than the given one,
+	// oldest first.
+	GetAuditEntriesAfter(ctx context.Context, afterId uint64, limit int) ([]domain.AuditEntry, error)
+}
+
+// loadSigningKey reads the PEM encoded Ed25519 private key that signs the audit checkpoints, as created by
+// "openssl genpkey -algorithm ed25519".
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("audit signing key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse audit signing key: %w", err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("audit signing key is a %T, expected an Ed25519 key", key)
+	}
+
+	return privateKey, nil
+}
+
+// newCheckpoint returns a signed checkpoint for the given entry.
+func newCheckpoint(key ed25519.PrivateKey, entryHash string, entryCreatedAt time.Time) *domain.AuditCheckpoint {
+	checkpoint := &domain.AuditCheckpoint{
+		CreatedAt:      time.Now(),
+		EntryHash:      entryHash,
+		EntryCreatedAt: entryCreatedAt,
+	}
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, checkpoint.SigningPayload()))
+
+	return checkpoint
+}
+
+// verifyChain verifies the hash chain of all audit entries and the given checkpoints, oldest first. The first
+// chained entry is trusted, as older entries may have been deleted by the retention policy. Checkpoints are only
+// verified if a public key is given.
+func verifyChain(
+	ctx context.Context,
+	db chainRepo,
+	checkpoints []domain.AuditCheckpoint,
+	publicKey ed25519.PublicKey,
+) (*domain.AuditChainReport, error) {
+	report := &domain.AuditChainReport{}
+
+	pending := make(map[string][]*domain.AuditCheckpoint) // checkpoints by entry hash
+	if publicKey != nil {
+		for i := range checkpoints {
+			checkpoint := &checkpoints[i]
+			signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+			if err != nil || !ed25519.Verify(publicKey, checkpoint.SigningPayload(), signature) {
+				return brokenChain(report, nil, checkpoint, "the checkpoint signature is invalid"), nil
+			}
+			pending[checkpoint.EntryHash] = append(pending[checkpoint.EntryHash], checkpoint)
+		}
+	}
+
+	var first *domain.AuditEntry
+	var prevHash string
+	var afterId uint64
+	for {
+		entries, err := db.GetAuditEntriesAfter(ctx, afterId, verifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audit entries: %w", err)
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			afterId = entry.UniqueId
+
+			switch {
+			case !entry.IsChained() && first == nil:
+				report.UnchainedEntries++
+				continue
+			case !entry.IsChained():
+				return brokenChain(report, entry, nil, "the entry is not part of the hash chain"), nil
+			case first == nil:
+				first = entry
+			case entry.PrevHash != prevHash:
+				return brokenChain(report, entry, nil, "the previous entry has been deleted or altered"), nil
+			}
+			if entry.ComputeHash() != entry.Hash {
+				return brokenChain(report, entry, nil, "the entry has been altered"), nil
+			}
+
+			prevHash = entry.Hash
+			report.Entries++
+			report.Checkpoints += len(pending[entry.Hash])
+			delete(pending, entry.Hash)
+		}
+
+		if len(entries) < verifyBatchSize {
+			break
+		}
+	}
+
+	// the entries of the remaining checkpoints are missing, which is only expected if they have been deleted by the
+	// retention policy, together with all older entries
+	for i := range checkpoints {
+		checkpoint := &checkpoints[i]
+		if _, ok := pending[checkpoint.EntryHash]; !ok {
+			continue
+		}
+		if first != nil && !checkpoint.EntryCreatedAt.After(first.CreatedAt) {
+			continue
+		}
+		return brokenChain(report, nil, checkpoint,
+			"the entry of the checkpoint is missing, entries have been deleted"), nil
+	}
+
+	report.Valid = true
+	return report, nil
+}
+
+// brokenChain marks the report as invalid at the given entry or checkpoint.
+func brokenChain(
+	report *domain.AuditChainReport,
+	entry *domain.AuditEntry,
+	checkpoint *domain.AuditCheckpoint,
+	reason string,
+) *domain.AuditChainReport {
+	report.FirstBroken = &domain.AuditChainBreak{Reason: reason}
+	if entry != nil {
+		report.FirstBroken.EntryId = entry.UniqueId
+		report.FirstBroken.CreatedAt = entry.CreatedAt
+	}
+	if checkpoint != nil {
+		report.FirstBroken.CheckpointId = checkpoint.Id
+		report.FirstBroken.CreatedAt = checkpoint.CreatedAt
+	}
+
+	return report
+}
@@ -0,0 +1,165 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type noopBus struct{}
+
+func (noopBus) Publish(string, ...any) {}
+
+func (noopBus) Subscribe(string, interface{}) error { return nil }
+
+func writeSigningKey(t *testing.T) string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	raw, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "audit.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw}), 0600))
+	return path
+}
+
+// newChainedLog records count entries with the hash chain enabled and creates a checkpoint after every entry.
+func newChainedLog(t *testing.T, repo *mockRepo, count int) *config.Config {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Statistics.AuditHashChain = true
+	cfg.Statistics.AuditSigningKey = writeSigningKey(t)
+
+	r, err := NewAuditRecorder(cfg, noopBus{}, repo)
+	require.NoError(t, err)
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < count; i++ {
+		entry := newAuditEntry(nil, domain.AuditEntityUser, "alice", "update")
+		entry.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		entry.Message = "user alice updated"
+		entry.Changes = []domain.AuditChange{{Field: "Firstname", Old: "Alice", New: float64(i)}}
+		r.saveAuditEntry(&entry, "user")
+		r.createCheckpoint(adminContext())
+	}
+	return cfg
+}
+
+func TestManager_VerifyChain(t *testing.T) {
+	repo := &mockRepo{}
+	// entries recorded before the chain has been enabled are skipped
+	require.NoError(t, repo.SaveAuditEntry(adminContext(), &domain.AuditEntry{Message: "legacy"}))
+	cfg := newChainedLog(t, repo, 4)
+
+	report, err := NewManager(cfg, repo).VerifyChain(adminContext())
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 4, report.Entries)
+	assert.Equal(t, 1, report.UnchainedEntries)
+	assert.Equal(t, 4, report.Checkpoints)
+	assert.Nil(t, report.FirstBroken)
+
+	// a restarted recorder continues the chain
+	r, err := NewAuditRecorder(cfg, noopBus{}, repo)
+	require.NoError(t, err)
+	entry := newAuditEntry(nil, domain.AuditEntityConfig, "", "reload")
+	r.saveAuditEntry(&entry, "config")
+	report, err = NewManager(cfg, repo).VerifyChain(adminContext())
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 5, report.Entries)
+}
+
+func TestManager_VerifyChain_Tampering(t *testing.T) {
+	tests := []struct {
+		name         string
+		tamper       func(repo *mockRepo) // entries are ordered newest first
+		entryId      uint64
+		checkpointId uint64
+	}{
+		{
+			name:    "altered entry",
+			tamper:  func(repo *mockRepo) { repo.entries[2].Message = "nothing happened" },
+			entryId: 2,
+		},
+		{
+			name:    "altered changes",
+			tamper:  func(repo *mockRepo) { repo.entries[1].Changes[0].New = "Bob" },
+			entryId: 3,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(repo *mockRepo) {
+				repo.entries = append(repo.entries[:2], repo.entries[3:]...)
+			},
+			entryId: 3,
+		},
+		{
+			name:         "deleted newest entries",
+			tamper:       func(repo *mockRepo) { repo.entries = repo.entries[2:] },
+			checkpointId: 3,
+		},
+		{
+			name:         "forged checkpoint",
+			tamper:       func(repo *mockRepo) { repo.checkpoints[1].EntryCreatedAt = time.Now() },
+			checkpointId: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepo{}
+			cfg := newChainedLog(t, repo, 4)
+			tt.tamper(repo)
+
+			report, err := NewManager(cfg, repo).VerifyChain(adminContext())
+			require.NoError(t, err)
+			assert.False(t, report.Valid)
+			require.NotNil(t, report.FirstBroken)
+			assert.Equal(t, tt.entryId, report.FirstBroken.EntryId)
+			assert.Equal(t, tt.checkpointId, report.FirstBroken.CheckpointId)
+		})
+	}
+}
+
+func TestRecorder_ReloadChain(t *testing.T) {
+	repo := &mockRepo{}
+	cfg := newChainedLog(t, repo, 4)
+	r, err := NewAuditRecorder(cfg, noopBus{}, repo)
+	require.NoError(t, err)
+
+	// restore an older state of the log, like a backup restore does
+	repo.entries = repo.entries[2:]
+	repo.checkpoints = repo.checkpoints[:2]
+	require.NoError(t, r.ReloadChain(adminContext()))
+
+	entry := newAuditEntry(nil, domain.AuditEntityConfig, "", "restore")
+	r.saveAuditEntry(&entry, "config")
+	report, err := NewManager(cfg, repo).VerifyChain(adminContext())
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 3, report.Entries)
+}
+
+func TestManager_VerifyChain_Retention(t *testing.T) {
+	repo := &mockRepo{}
+	cfg := newChainedLog(t, repo, 4)
+	// the retention policy deletes the oldest entries
+	repo.entries = repo.entries[:2]
+
+	report, err := NewManager(cfg, repo).VerifyChain(adminContext())
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 2, report.Entries)
+	assert.Equal(t, 2, report.Checkpoints)
+}
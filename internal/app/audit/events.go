@@ -9,10 +9,35 @@ type AuthEvent struct {
 
 type InterfaceEvent struct {
 	Interface domain.Interface
+	Previous  *domain.Interface // the stored interface before the change, nil for new interfaces
 	Action    string
 }
 
 type PeerEvent struct {
-	Peer   domain.Peer
-	Action string
+	Peer     domain.Peer
+	Previous *domain.Peer // the stored peer before the change, nil for new peers
+	Action   string
+}
+
+type UserEvent struct {
+	User     domain.User
+	Previous *domain.User // the stored user before the change, nil for new users
+	Action   string       // create, update, delete, api-enable, api-disable, ldap-sync or ldap-disable
+}
+
+type ApiTokenEvent struct {
+	Token  domain.ApiToken
+	Action string // create or revoke
+}
+
+type WebAuthnCredentialEvent struct {
+	Credential domain.UserWebauthnCredential
+	Previous   *domain.UserWebauthnCredential // the credential before the change, nil for new credentials
+	Action     string                         // create, update or delete
+}
+
+type ConfigEvent struct {
+	Settings []string // the changed settings, for example auth.ldap
+	Failed   []string // the components that failed to apply the changes
+	Action   string   // reload
 }
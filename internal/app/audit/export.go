@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// exportColumns are the columns of a CSV export, the changes are exported as JSON array.
+var exportColumns = []string{"id", "created_at", "severity", "context_user", "source_ip", "origin", "entity_type",
+	"entity_id", "action", "message", "changes", "prev_hash", "hash"}
+
+// exportEntry is an audit entry of a JSON lines export, the field names match the REST API.
+type exportEntry struct {
+	Id          uint64               `json:"Id"`
+	CreatedAt   time.Time            `json:"CreatedAt"`
+	Severity    string               `json:"Severity"`
+	ContextUser string               `json:"ContextUser"`
+	SourceIp    string               `json:"SourceIp,omitempty"`
+	Origin      string               `json:"Origin"`
+	EntityType  string               `json:"EntityType,omitempty"`
+	EntityId    string               `json:"EntityId,omitempty"`
+	Action      string               `json:"Action,omitempty"`
+	Message     string               `json:"Message"`
+	Changes     []domain.AuditChange `json:"Changes,omitempty"`
+	PrevHash    string               `json:"PrevHash,omitempty"`
+	Hash        string               `json:"Hash,omitempty"`
+}
+
+func newExportEntry(entry domain.AuditEntry) exportEntry {
+	return exportEntry{
+		Id:          entry.UniqueId,
+		CreatedAt:   entry.CreatedAt,
+		Severity:    string(entry.Severity),
+		ContextUser: entry.ContextUser,
+		SourceIp:    entry.SourceIp,
+		Origin:      entry.Origin,
+		EntityType:  entry.EntityType,
+		EntityId:    entry.EntityId,
+		Action:      entry.Action,
+		Message:     entry.Message,
+		Changes:     entry.Changes,
+		PrevHash:    entry.PrevHash,
+		Hash:        entry.Hash,
+	}
+}
+
+// exportWriter writes audit entries in the format of an export.
+type exportWriter interface {
+	Write(entry domain.AuditEntry) error
+	Flush() error
+}
+
+func newExportWriter(w io.Writer, format domain.AuditExportFormat) exportWriter {
+	if format == domain.AuditExportFormatJsonLines {
+		return &jsonLinesWriter{encoder: json.NewEncoder(w)}
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+type jsonLinesWriter struct {
+	encoder *json.Encoder
+}
+
+func (j *jsonLinesWriter) Write(entry domain.AuditEntry) error {
+	return j.encoder.Encode(newExportEntry(entry))
+}
+
+func (j *jsonLinesWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) Write(entry domain.AuditEntry) error {
+	if !c.headerWritten {
+		if err := c.writer.Write(exportColumns); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+
+	changes := ""
+	if len(entry.Changes) > 0 {
+		raw, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return err
+		}
+		changes = string(raw)
+	}
+
+	return c.writer.Write([]string{strconv.FormatUint(entry.UniqueId, 10), entry.CreatedAt.Format(time.RFC3339Nano),
+		string(entry.Severity), entry.ContextUser, entry.SourceIp, entry.Origin, entry.EntityType, entry.EntityId,
+		entry.Action, entry.Message, changes, entry.PrevHash, entry.Hash})
+}
+
+func (c *csvWriter) Flush() error {
+	if !c.headerWritten {
+		// an empty export still contains the header
+		if err := c.writer.Write(exportColumns); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
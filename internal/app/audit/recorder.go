@@ -2,8 +2,12 @@ package audit
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/biezax/wg-portal/internal/app"
@@ -16,9 +20,21 @@ import (
 type DatabaseRepo interface {
 	// SaveAuditEntry saves an audit entry to the database
 	SaveAuditEntry(ctx context.Context, entry *domain.AuditEntry) error
+	// DeleteAuditEntriesBefore deletes the audit entries created before the given time.
+	DeleteAuditEntriesBefore(ctx context.Context, before time.Time) (int, error)
+	// DeleteAuditEntriesExceeding deletes the oldest audit entries, so that at most keep entries remain.
+	DeleteAuditEntriesExceeding(ctx context.Context, keep int) (int, error)
+	// GetLastAuditEntry returns the newest audit entry, or domain.ErrNotFound if there are no entries.
+	GetLastAuditEntry(ctx context.Context) (*domain.AuditEntry, error)
+	// SaveAuditCheckpoint saves a signed audit checkpoint.
+	SaveAuditCheckpoint(ctx context.Context, checkpoint *domain.AuditCheckpoint) error
+	// GetAuditCheckpoints returns all audit checkpoints, oldest first.
+	GetAuditCheckpoints(ctx context.Context) ([]domain.AuditCheckpoint, error)
 }
 
 type EventBus interface {
+	// Publish sends a message to the message bus.
+	Publish(topic string, args ...any)
 	// Subscribe subscribes to a topic
 	Subscribe(topic string, fn interface{}) error
 }
@@ -31,6 +47,14 @@ type Recorder struct {
 	bus EventBus
 
 	db DatabaseRepo
+
+	// chainMux serializes the appends to the hash chain, so that each entry links to its predecessor.
+	chainMux           sync.Mutex
+	lastEntry          *domain.AuditEntry // the newest chained entry
+	lastCheckpointHash string
+	signingKey         ed25519.PrivateKey
+
+	sinks []*sinkWorker // forward all entries to syslog servers and files
 }
 
 // NewAuditRecorder creates a new audit recorder instance.
@@ -42,6 +66,18 @@ func NewAuditRecorder(cfg *config.Config, bus EventBus, db DatabaseRepo) (*Recor
 		db: db,
 	}
 
+	if err := r.initChain(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to setup audit hash chain: %w", err)
+	}
+
+	for _, sinkCfg := range cfg.Statistics.AuditSinks {
+		worker, err := newSinkWorker(sinkCfg)
+		if err != nil {
+			return nil, err
+		}
+		r.sinks = append(r.sinks, worker)
+	}
+
 	err := r.connectToMessageBus()
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup message bus: %w", err)
@@ -50,14 +86,69 @@ func NewAuditRecorder(cfg *config.Config, bus EventBus, db DatabaseRepo) (*Recor
 	return r, nil
 }
 
-// StartBackgroundJobs starts background jobs for the audit recorder.
+// ReloadChain reloads the newest entry of the hash chain and the last checkpoint. It has to be called after the audit
+// entries have been replaced, for example by restoring a backup.
+func (r *Recorder) ReloadChain(ctx context.Context) error {
+	r.chainMux.Lock()
+	defer r.chainMux.Unlock()
+
+	return r.initChain(ctx)
+}
+
+// initChain loads the newest entry of the hash chain and the signing key of the checkpoints.
+func (r *Recorder) initChain(ctx context.Context) error {
+	if !r.cfg.Statistics.AuditHashChain {
+		return nil // noting to do
+	}
+
+	r.lastEntry = nil
+	r.lastCheckpointHash = ""
+
+	lastEntry, err := r.db.GetLastAuditEntry(ctx)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+	case err != nil:
+		return fmt.Errorf("failed to load last audit entry: %w", err)
+	case lastEntry.IsChained():
+		r.lastEntry = lastEntry
+	}
+
+	if r.cfg.Statistics.AuditSigningKey == "" {
+		return nil
+	}
+	if r.signingKey, err = loadSigningKey(r.cfg.Statistics.AuditSigningKey); err != nil {
+		return err
+	}
+	checkpoints, err := r.db.GetAuditCheckpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load audit checkpoints: %w", err)
+	}
+	if len(checkpoints) > 0 {
+		r.lastCheckpointHash = checkpoints[len(checkpoints)-1].EntryHash
+	}
+
+	return nil
+}
+
+// StartBackgroundJobs starts background jobs for the audit recorder. The retention policy is enforced at startup
+// and then hourly, also if no new audit data is collected. Signed checkpoints of the hash chain are created in the
+// configured interval. Each audit sink forwards the entries in its own goroutine.
 // This method is non-blocking and returns immediately.
 func (r *Recorder) StartBackgroundJobs(ctx context.Context) {
-	if !r.cfg.Statistics.CollectAuditData {
+	for _, worker := range r.sinks {
+		go worker.run(ctx)
+	}
+	if r.signingKey != nil {
+		go r.runCheckpointJob(ctx)
+	}
+
+	if r.cfg.Statistics.AuditRetention <= 0 && r.cfg.Statistics.AuditMaxEntries <= 0 {
 		return // noting to do
 	}
 
 	go func() {
+		r.enforceRetention(ctx)
+
 		running := true
 		for running {
 			select {
@@ -68,11 +159,67 @@ func (r *Recorder) StartBackgroundJobs(ctx context.Context) {
 				// select blocks until one of the cases evaluate to true
 			}
 
-			slog.Debug("audit status", "registered_messages", 0) // TODO: implement
+			r.enforceRetention(ctx)
 		}
 	}()
 }
 
+func (r *Recorder) runCheckpointJob(ctx context.Context) {
+	running := true
+	for running {
+		select {
+		case <-ctx.Done():
+			running = false
+			continue
+		case <-time.After(r.cfg.Statistics.AuditCheckpointInterval):
+			// select blocks until one of the cases evaluate to true
+		}
+
+		r.createCheckpoint(ctx)
+	}
+}
+
+// createCheckpoint signs the newest entry of the hash chain, if it has changed since the last checkpoint.
+func (r *Recorder) createCheckpoint(ctx context.Context) {
+	r.chainMux.Lock()
+	defer r.chainMux.Unlock()
+
+	if r.lastEntry == nil || r.lastEntry.Hash == r.lastCheckpointHash {
+		return // no new entries
+	}
+
+	checkpoint := newCheckpoint(r.signingKey, r.lastEntry.Hash, r.lastEntry.CreatedAt)
+	if err := r.db.SaveAuditCheckpoint(ctx, checkpoint); err != nil {
+		slog.Error("failed to create audit checkpoint", "error", err)
+		return
+	}
+	r.lastCheckpointHash = checkpoint.EntryHash
+
+	slog.Debug("created audit checkpoint", "entry_hash", checkpoint.EntryHash)
+}
+
+// enforceRetention deletes the audit entries that are older than the retention time or exceed the maximum number
+// of entries.
+func (r *Recorder) enforceRetention(ctx context.Context) {
+	if retention := r.cfg.Statistics.AuditRetention; retention > 0 {
+		deleted, err := r.db.DeleteAuditEntriesBefore(ctx, time.Now().Add(-retention))
+		if err != nil {
+			slog.Error("failed to delete expired audit entries", "error", err)
+		} else if deleted > 0 {
+			slog.Debug("deleted expired audit entries", "count", deleted)
+		}
+	}
+
+	if maxEntries := r.cfg.Statistics.AuditMaxEntries; maxEntries > 0 {
+		deleted, err := r.db.DeleteAuditEntriesExceeding(ctx, maxEntries)
+		if err != nil {
+			slog.Error("failed to delete excess audit entries", "error", err)
+		} else if deleted > 0 {
+			slog.Debug("deleted excess audit entries", "count", deleted)
+		}
+	}
+}
+
 func (r *Recorder) connectToMessageBus() error {
 	if !r.cfg.Statistics.CollectAuditData {
 		return nil // noting to do
@@ -90,46 +237,110 @@ func (r *Recorder) connectToMessageBus() error {
 	if err := r.bus.Subscribe(app.TopicAuditPeerChanged, r.handlePeerEvent); err != nil {
 		return fmt.Errorf("failed to subscribe to %s: %w", app.TopicAuditPeerChanged, err)
 	}
+	if err := r.bus.Subscribe(app.TopicAuditUserChanged, r.handleUserEvent); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", app.TopicAuditUserChanged, err)
+	}
+	if err := r.bus.Subscribe(app.TopicAuditApiTokenChanged, r.handleApiTokenEvent); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", app.TopicAuditApiTokenChanged, err)
+	}
+	if err := r.bus.Subscribe(app.TopicAuditWebAuthnChanged, r.handleWebAuthnEvent); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", app.TopicAuditWebAuthnChanged, err)
+	}
+	if err := r.bus.Subscribe(app.TopicAuditConfigChanged, r.handleConfigEvent); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", app.TopicAuditConfigChanged, err)
+	}
 
 	return nil
 }
 
 func (r *Recorder) handleAuthEvent(event domain.AuditEventWrapper[AuthEvent]) {
-	err := r.db.SaveAuditEntry(context.Background(), r.authEventToAuditEntry(event))
-	if err != nil {
-		slog.Error("failed to create audit entry for auth event", "error", err)
-		return
-	}
+	r.saveAuditEntry(r.authEventToAuditEntry(event), "auth")
 }
 
 func (r *Recorder) handleInterfaceEvent(event domain.AuditEventWrapper[InterfaceEvent]) {
-	err := r.db.SaveAuditEntry(context.Background(), r.interfaceEventToAuditEntry(event))
-	if err != nil {
-		slog.Error("failed to create audit entry for interface event", "error", err)
-		return
-	}
+	r.saveAuditEntry(r.interfaceEventToAuditEntry(event), "interface")
 }
 
 func (r *Recorder) handlePeerEvent(event domain.AuditEventWrapper[PeerEvent]) {
-	err := r.db.SaveAuditEntry(context.Background(), r.peerEventToAuditEntry(event))
+	r.saveAuditEntry(r.peerEventToAuditEntry(event), "peer")
+}
+
+func (r *Recorder) handleUserEvent(event domain.AuditEventWrapper[UserEvent]) {
+	entry := r.userEventToAuditEntry(event)
+	if entry.Action == "update" && len(entry.Changes) == 0 {
+		return // for example, webauthn credential changes are recorded separately
+	}
+	r.saveAuditEntry(entry, "user")
+}
+
+func (r *Recorder) handleApiTokenEvent(event domain.AuditEventWrapper[ApiTokenEvent]) {
+	r.saveAuditEntry(r.apiTokenEventToAuditEntry(event), "api_token")
+}
+
+func (r *Recorder) handleWebAuthnEvent(event domain.AuditEventWrapper[WebAuthnCredentialEvent]) {
+	r.saveAuditEntry(r.webAuthnEventToAuditEntry(event), "webauthn")
+}
+
+func (r *Recorder) handleConfigEvent(event domain.AuditEventWrapper[ConfigEvent]) {
+	r.saveAuditEntry(r.configEventToAuditEntry(event), "config")
+}
+
+// saveAuditEntry stores the audit entry and publishes it for other consumers, like the event stream. If the hash
+// chain is enabled, the entry is linked to the previous entry. The entry is forwarded to the audit sinks even if it
+// could not be stored.
+func (r *Recorder) saveAuditEntry(entry *domain.AuditEntry, kind string) {
+	if r.cfg.Statistics.AuditHashChain {
+		r.chainMux.Lock()
+		defer r.chainMux.Unlock()
+
+		// some databases round sub-millisecond timestamps, the stored value must match the hashed value
+		entry.CreatedAt = entry.CreatedAt.Truncate(time.Millisecond)
+		if r.lastEntry != nil {
+			entry.PrevHash = r.lastEntry.Hash
+		}
+		entry.Hash = entry.ComputeHash()
+	}
+
+	err := r.db.SaveAuditEntry(context.Background(), entry)
+	for _, worker := range r.sinks {
+		worker.enqueue(*entry)
+	}
 	if err != nil {
-		slog.Error("failed to create audit entry for peer event", "error", err)
+		slog.Error("failed to create audit entry", "event", kind, "error", err)
 		return
 	}
+	if r.cfg.Statistics.AuditHashChain {
+		r.lastEntry = entry
+	}
+
+	r.bus.Publish(app.TopicAuditEntryCreated, *entry)
 }
 
-func (r *Recorder) authEventToAuditEntry(event domain.AuditEventWrapper[AuthEvent]) *domain.AuditEntry {
-	contextUser := domain.GetUserInfo(event.Ctx)
-	e := domain.AuditEntry{
+// newAuditEntry returns an audit entry for the actor and the source IP of the given context.
+func newAuditEntry(ctx context.Context, entityType, entityId, action string) domain.AuditEntry {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return domain.AuditEntry{
 		CreatedAt:   time.Now(),
 		Severity:    domain.AuditSeverityLevelLow,
-		ContextUser: contextUser.UserId(),
-		Origin:      fmt.Sprintf("auth: %s", event.Source),
-		Message:     fmt.Sprintf("%s logged in", event.Event.Username),
+		ContextUser: domain.GetUserInfo(ctx).UserId(),
+		SourceIp:    domain.GetSourceIp(ctx),
+		EntityType:  entityType,
+		EntityId:    entityId,
+		Action:      action,
 	}
+}
+
+func (r *Recorder) authEventToAuditEntry(event domain.AuditEventWrapper[AuthEvent]) *domain.AuditEntry {
+	e := newAuditEntry(event.Ctx, domain.AuditEntityAuth, event.Event.Username, "login")
+	e.Origin = fmt.Sprintf("auth: %s", event.Source)
+	e.Message = fmt.Sprintf("%s logged in", event.Event.Username)
 
 	if event.Event.Error != "" {
 		e.Severity = domain.AuditSeverityLevelHigh
+		e.Action = "login-failed"
 		e.Message = fmt.Sprintf("%s failed to login: %s", event.Event.Username, event.Event.Error)
 	}
 
@@ -137,38 +348,139 @@ func (r *Recorder) authEventToAuditEntry(event domain.AuditEventWrapper[AuthEven
 }
 
 func (r *Recorder) interfaceEventToAuditEntry(event domain.AuditEventWrapper[InterfaceEvent]) *domain.AuditEntry {
-	contextUser := domain.GetUserInfo(event.Ctx)
-	e := domain.AuditEntry{
-		CreatedAt:   time.Now(),
-		Severity:    domain.AuditSeverityLevelLow,
-		ContextUser: contextUser.UserId(),
-		Origin:      fmt.Sprintf("interface: %s", event.Event.Action),
-	}
+	iface := event.Event.Interface
+	e := newAuditEntry(event.Ctx, domain.AuditEntityInterface, string(iface.Identifier), event.Event.Action)
+	e.Origin = fmt.Sprintf("interface: %s", event.Event.Action)
 
 	switch event.Event.Action {
 	case "save":
-		e.Message = fmt.Sprintf("%s updated", event.Event.Interface.Identifier)
+		e.Message = fmt.Sprintf("%s updated", iface.Identifier)
+		e.Changes = domain.AuditChanges(event.Event.Previous, iface)
+	case "delete":
+		e.Severity = domain.AuditSeverityLevelHigh
+		e.Message = fmt.Sprintf("%s deleted", iface.Identifier)
+		e.Changes = domain.AuditChanges(iface, nil)
 	default:
-		e.Message = fmt.Sprintf("%s: unknown action", event.Event.Interface.Identifier)
+		e.Message = fmt.Sprintf("%s: unknown action", iface.Identifier)
 	}
 
 	return &e
 }
 
 func (r *Recorder) peerEventToAuditEntry(event domain.AuditEventWrapper[PeerEvent]) *domain.AuditEntry {
-	contextUser := domain.GetUserInfo(event.Ctx)
-	e := domain.AuditEntry{
-		CreatedAt:   time.Now(),
-		Severity:    domain.AuditSeverityLevelLow,
-		ContextUser: contextUser.UserId(),
-		Origin:      fmt.Sprintf("peer: %s", event.Event.Action),
-	}
+	peer := event.Event.Peer
+	e := newAuditEntry(event.Ctx, domain.AuditEntityPeer, string(peer.Identifier), event.Event.Action)
+	e.Origin = fmt.Sprintf("peer: %s", event.Event.Action)
 
 	switch event.Event.Action {
 	case "save":
-		e.Message = fmt.Sprintf("%s updated", event.Event.Peer.Identifier)
+		e.Message = fmt.Sprintf("%s updated", peer.Identifier)
+		e.Changes = domain.AuditChanges(event.Event.Previous, peer)
+	case "delete":
+		e.Message = fmt.Sprintf("%s deleted", peer.Identifier)
+		e.Changes = domain.AuditChanges(peer, nil)
+	default:
+		e.Message = fmt.Sprintf("%s: unknown action", peer.Identifier)
+	}
+
+	return &e
+}
+
+func (r *Recorder) userEventToAuditEntry(event domain.AuditEventWrapper[UserEvent]) *domain.AuditEntry {
+	user := event.Event.User
+	e := newAuditEntry(event.Ctx, domain.AuditEntityUser, string(user.Identifier), event.Event.Action)
+	e.Origin = fmt.Sprintf("user: %s", event.Event.Action)
+
+	switch event.Event.Action {
+	case "create":
+		e.Message = fmt.Sprintf("%s created", user.Identifier)
+		e.Changes = domain.AuditChanges(nil, user)
+	case "delete":
+		e.Severity = domain.AuditSeverityLevelHigh
+		e.Message = fmt.Sprintf("%s deleted", user.Identifier)
+		e.Changes = domain.AuditChanges(user, nil)
+	case "api-enable":
+		e.Severity = domain.AuditSeverityLevelHigh
+		e.Message = fmt.Sprintf("API access of %s enabled", user.Identifier)
+		e.Changes = domain.AuditChanges(event.Event.Previous, user)
+	case "api-disable":
+		e.Message = fmt.Sprintf("API access of %s disabled", user.Identifier)
+		e.Changes = domain.AuditChanges(event.Event.Previous, user)
+	case "update", "ldap-sync", "ldap-disable":
+		e.Message = fmt.Sprintf("%s updated", user.Identifier)
+		e.Changes = domain.AuditChanges(event.Event.Previous, user)
+	default:
+		e.Message = fmt.Sprintf("%s: unknown action", user.Identifier)
+	}
+
+	// granting or revoking admin rights is always of high severity
+	if event.Event.Previous != nil && event.Event.Previous.IsAdmin != user.IsAdmin ||
+		event.Event.Previous == nil && user.IsAdmin {
+		e.Severity = domain.AuditSeverityLevelHigh
+	}
+
+	return &e
+}
+
+func (r *Recorder) apiTokenEventToAuditEntry(event domain.AuditEventWrapper[ApiTokenEvent]) *domain.AuditEntry {
+	token := event.Event.Token
+	e := newAuditEntry(event.Ctx, domain.AuditEntityApiToken, string(token.Identifier), event.Event.Action)
+	e.Origin = fmt.Sprintf("api_token: %s", event.Event.Action)
+
+	switch event.Event.Action {
+	case "create":
+		e.Severity = domain.AuditSeverityLevelHigh
+		e.Message = fmt.Sprintf("API token %s of %s created", token.Name, token.UserIdentifier)
+		e.Changes = domain.AuditChanges(nil, token)
+	case "revoke":
+		e.Message = fmt.Sprintf("API token %s of %s revoked", token.Name, token.UserIdentifier)
+		e.Changes = domain.AuditChanges(token, nil)
 	default:
-		e.Message = fmt.Sprintf("%s: unknown action", event.Event.Peer.Identifier)
+		e.Message = fmt.Sprintf("API token %s: unknown action", token.Identifier)
+	}
+
+	return &e
+}
+
+func (r *Recorder) webAuthnEventToAuditEntry(
+	event domain.AuditEventWrapper[WebAuthnCredentialEvent],
+) *domain.AuditEntry {
+	credential := event.Event.Credential
+	e := newAuditEntry(event.Ctx, domain.AuditEntityWebAuthnCredential, credential.CredentialIdentifier,
+		event.Event.Action)
+	e.Origin = fmt.Sprintf("webauthn: %s", event.Event.Action)
+
+	switch event.Event.Action {
+	case "create":
+		e.Severity = domain.AuditSeverityLevelHigh
+		e.Message = fmt.Sprintf("passkey %s of %s registered", credential.DisplayName, credential.UserIdentifier)
+		e.Changes = domain.AuditChanges(nil, credential)
+	case "update":
+		e.Message = fmt.Sprintf("passkey %s of %s updated", credential.DisplayName, credential.UserIdentifier)
+		e.Changes = domain.AuditChanges(event.Event.Previous, credential)
+	case "delete":
+		e.Message = fmt.Sprintf("passkey %s of %s removed", credential.DisplayName, credential.UserIdentifier)
+		e.Changes = domain.AuditChanges(credential, nil)
+	default:
+		e.Message = fmt.Sprintf("passkey %s: unknown action", credential.CredentialIdentifier)
+	}
+
+	return &e
+}
+
+func (r *Recorder) configEventToAuditEntry(event domain.AuditEventWrapper[ConfigEvent]) *domain.AuditEntry {
+	e := newAuditEntry(event.Ctx, domain.AuditEntityConfig, "", event.Event.Action)
+	e.Severity = domain.AuditSeverityLevelHigh
+	e.Origin = fmt.Sprintf("config: %s", event.Event.Action)
+	e.Message = fmt.Sprintf("configuration reloaded, %d settings changed", len(event.Event.Settings))
+	if len(event.Event.Failed) > 0 {
+		e.Message += fmt.Sprintf(", failed components: %s", strings.Join(event.Event.Failed, ", "))
+	}
+
+	// only the names of the settings are recorded, the configuration contains secrets
+	e.Changes = make([]domain.AuditChange, 0, len(event.Event.Settings))
+	for _, setting := range event.Event.Settings {
+		e.Changes = append(e.Changes, domain.AuditChange{Field: setting})
 	}
 
 	return &e
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// defaultSinkBufferSize is the number of queued entries of a sink, if no buffer size is configured.
+const defaultSinkBufferSize = 1000
+
+// sink writes audit entries to an external system.
+type sink interface {
+	Write(entry domain.AuditEntry) error
+	Close() error
+}
+
+// sinkWorker forwards the audit entries to a sink in the background. Entries are dropped while its queue is full,
+// so that a slow sink never blocks the recorder.
+type sinkWorker struct {
+	name    string
+	sink    sink
+	queue   chan domain.AuditEntry
+	dropped atomic.Uint64
+}
+
+func newSinkWorker(cfg config.AuditSink) (*sinkWorker, error) {
+	var s sink
+	var err error
+	switch cfg.Type {
+	case config.AuditSinkSyslog:
+		s, err = newSyslogSink(cfg)
+	case config.AuditSinkFile:
+		s, err = newFileSink(cfg)
+	default:
+		err = fmt.Errorf("unknown audit sink type %s", cfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup audit sink %s: %w", cfg.Name, err)
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultSinkBufferSize
+	}
+
+	return &sinkWorker{
+		name:  cfg.Name,
+		sink:  s,
+		queue: make(chan domain.AuditEntry, bufferSize),
+	}, nil
+}
+
+// enqueue queues the entry for the sink, it never blocks.
+func (w *sinkWorker) enqueue(entry domain.AuditEntry) {
+	select {
+	case w.queue <- entry:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// run writes the queued entries to the sink until the context is cancelled. The remaining entries are written
+// before the sink is closed.
+func (w *sinkWorker) run(ctx context.Context) {
+	defer func() {
+		if err := w.sink.Close(); err != nil {
+			slog.Warn("failed to close audit sink", "sink", w.name, "error", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-w.queue:
+					w.write(entry)
+				default:
+					return
+				}
+			}
+		case entry := <-w.queue:
+			w.write(entry)
+		}
+	}
+}
+
+func (w *sinkWorker) write(entry domain.AuditEntry) {
+	if dropped := w.dropped.Swap(0); dropped > 0 {
+		slog.Warn("audit sink queue was full, entries have been dropped", "sink", w.name, "count", dropped)
+	}
+
+	if err := w.sink.Write(entry); err != nil {
+		slog.Warn("failed to forward audit entry", "sink", w.name, "entry", entry.UniqueId, "error", err)
+	}
+}
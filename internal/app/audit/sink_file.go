@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// fileSink appends audit entries to a JSON lines file, in the format of the JSON lines export. The file is rotated
+// once it exceeds the maximum size: the current file becomes path.1, older files are shifted to path.2 and so on.
+type fileSink struct {
+	path       string
+	maxSize    int64 // 0 disables the rotation
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+func newFileSink(cfg config.AuditSink) (*fileSink, error) {
+	s := &fileSink{
+		path:       cfg.Path,
+		maxSize:    int64(cfg.MaxSize) * 1024 * 1024,
+		maxBackups: max(cfg.MaxBackups, 1),
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileSink) Write(entry domain.AuditEntry) error {
+	line, err := json.Marshal(newExportEntry(entry))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// rotate moves the current file to path.1 and removes the oldest rotated file.
+func (s *fileSink) rotate() error {
+	if err := s.Close(); err != nil {
+		return fmt.Errorf("failed to close audit file: %w", err)
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+	for i := s.maxBackups - 1; i > 0; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+	}
+	if err := os.Rename(s.path, s.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate audit file: %w", err)
+	}
+
+	return s.open()
+}
+
+func (s *fileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+const (
+	syslogDialTimeout  = 10 * time.Second
+	syslogWriteTimeout = 10 * time.Second
+
+	// syslogSdId is the id of the structured data element, 32473 is the enterprise number reserved for
+	// documentation by RFC 5612.
+	syslogSdId = "wgportal@32473"
+
+	syslogSeverityWarning = 4
+	syslogSeverityNotice  = 5
+)
+
+// syslogSink sends audit entries as RFC 5424 messages to a syslog server. TCP and TLS messages are framed by
+// octet counting (RFC 6587, RFC 5425).
+type syslogSink struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	facility  int
+	appName   string
+	hostname  string
+	procId    string
+
+	conn net.Conn
+}
+
+func newSyslogSink(cfg config.AuditSink) (*syslogSink, error) {
+	s := &syslogSink{
+		network:  cfg.Network,
+		address:  cfg.Address,
+		facility: cfg.SyslogFacility(),
+		appName:  cfg.AppName,
+		hostname: "-",
+		procId:   strconv.Itoa(os.Getpid()),
+	}
+	if s.appName == "" {
+		s.appName = "wg-portal"
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		s.hostname = hostname
+	}
+
+	if cfg.Network == "tls" {
+		host, _, err := net.SplitHostPort(cfg.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address: %w", err)
+		}
+		s.tlsConfig = &tls.Config{ServerName: host, InsecureSkipVerify: cfg.TlsSkipVerify}
+		if cfg.TlsCaFile != "" {
+			caCert, err := os.ReadFile(cfg.TlsCaFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+			}
+			s.tlsConfig.RootCAs = x509.NewCertPool()
+			if !s.tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+				return nil, errors.New("CA certificate file contains no PEM certificate")
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// Write sends the entry to the syslog server. The connection is established on demand, a broken connection is
+// re-established once.
+func (s *syslogSink) Write(entry domain.AuditEntry) error {
+	message := s.format(entry)
+	if s.network != "udp" {
+		message = strconv.Itoa(len(message)) + " " + message
+	}
+
+	reconnected := false
+	for {
+		if s.conn == nil {
+			if err := s.connect(); err != nil {
+				return err
+			}
+			reconnected = true
+		}
+
+		_ = s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+		_, err := s.conn.Write([]byte(message))
+		if err == nil {
+			return nil
+		}
+
+		_ = s.conn.Close()
+		s.conn = nil
+		if reconnected {
+			return fmt.Errorf("failed to send syslog message: %w", err)
+		}
+	}
+}
+
+func (s *syslogSink) connect() error {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+
+	var err error
+	if s.network == "tls" {
+		s.conn, err = tls.DialWithDialer(dialer, "tcp", s.address, s.tlsConfig)
+	} else {
+		s.conn, err = dialer.Dial(s.network, s.address)
+	}
+	if err != nil {
+		s.conn = nil
+		return fmt.Errorf("failed to connect to syslog server: %w", err)
+	}
+
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format returns the RFC 5424 message of the entry. The entry details are sent as structured data.
+func (s *syslogSink) format(entry domain.AuditEntry) string {
+	severity := syslogSeverityNotice
+	if entry.Severity == domain.AuditSeverityLevelHigh {
+		severity = syslogSeverityWarning
+	}
+
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSdId)
+	param := func(name, value string) {
+		if value != "" {
+			sd.WriteString(" " + name + `="` + syslogParamEscaper.Replace(value) + `"`)
+		}
+	}
+	if entry.UniqueId != 0 {
+		param("id", strconv.FormatUint(entry.UniqueId, 10))
+	}
+	param("severity", string(entry.Severity))
+	param("user", entry.ContextUser)
+	param("sourceIp", entry.SourceIp)
+	param("origin", entry.Origin)
+	param("entityType", entry.EntityType)
+	param("entityId", entry.EntityId)
+	param("action", entry.Action)
+	if len(entry.Changes) > 0 {
+		if changes, err := json.Marshal(entry.Changes); err == nil {
+			param("changes", string(changes))
+		}
+	}
+	param("hash", entry.Hash)
+	sd.WriteString("]")
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA BOM MSG
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s %s \ufeff%s",
+		s.facility*8+severity,
+		entry.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(s.hostname, 255),
+		syslogHeaderField(s.appName, 48),
+		syslogHeaderField(s.procId, 128),
+		syslogHeaderField(entry.Action, 32),
+		sd.String(),
+		entry.Message)
+}
+
+// syslogParamEscaper escapes the characters that must be escaped in structured data parameter values.
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogHeaderField returns the value as printable US-ASCII header field of at most maxLength characters, or the
+// nil value "-" if it is empty.
+func syslogHeaderField(value string, maxLength int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(field) > maxLength {
+		field = field[:maxLength]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
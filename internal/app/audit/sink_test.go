@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func newSinkTestEntry() domain.AuditEntry {
+	return domain.AuditEntry{
+		UniqueId:    7,
+		CreatedAt:   time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC),
+		Severity:    domain.AuditSeverityLevelHigh,
+		ContextUser: "alice",
+		SourceIp:    "192.0.2.1",
+		Origin:      "auth: password",
+		EntityType:  domain.AuditEntityAuth,
+		EntityId:    "bob",
+		Action:      "login-failed",
+		Message:     `bob failed to login: "wrong] password"`,
+	}
+}
+
+func TestSyslogSink_Tcp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	s, err := newSyslogSink(config.AuditSink{Type: config.AuditSinkSyslog, Network: "tcp",
+		Address: listener.Addr().String(), Facility: "local0"})
+	require.NoError(t, err)
+	defer s.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		length, _ := reader.ReadString(' ')
+		size, _ := strconv.Atoi(strings.TrimSpace(length))
+		message := make([]byte, size)
+		_, _ = io.ReadFull(reader, message)
+		received <- string(message)
+	}()
+
+	require.NoError(t, s.Write(newSinkTestEntry()))
+
+	var message string
+	select {
+	case message = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no syslog message received")
+	}
+	assert.True(t, strings.HasPrefix(message, "<132>1 2025-03-01T12:30:00.000000Z "), message) // local0.warning
+	assert.Contains(t, message, " wg-portal "+strconv.Itoa(os.Getpid())+" login-failed [wgportal@32473 id=\"7\"")
+	assert.Contains(t, message, `user="alice" sourceIp="192.0.2.1"`)
+	assert.True(t, strings.HasSuffix(message, "] \ufeffbob failed to login: \"wrong] password\""), message)
+}
+
+func TestSyslogSink_Udp(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	s, err := newSyslogSink(config.AuditSink{Type: config.AuditSinkSyslog, Network: "udp",
+		Address: conn.LocalAddr().String(), AppName: "vpn portal"})
+	require.NoError(t, err)
+	defer s.Close()
+
+	entry := newSinkTestEntry()
+	entry.Severity = domain.AuditSeverityLevelLow
+	entry.Message = "bob updated"
+	entry.Changes = []domain.AuditChange{{Field: "Notes", Old: "a\"b", New: "c]d"}}
+	require.NoError(t, s.Write(entry))
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	message := string(buf[:n])
+	assert.True(t, strings.HasPrefix(message, "<37>1 "), message) // auth.notice, no octet counting
+	assert.Contains(t, message, " vpn_portal ")
+	assert.Contains(t, message, `changes="[{\"field\":\"Notes\",\"old\":\"a\\\"b\",\"new\":\"c\]d\"}\]"`)
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	s, err := newFileSink(config.AuditSink{Type: config.AuditSinkFile, Path: path, MaxBackups: 2})
+	require.NoError(t, err)
+	s.maxSize = 300 // a few entries per file
+
+	for i := 1; i <= 10; i++ {
+		entry := newSinkTestEntry()
+		entry.UniqueId = uint64(i)
+		require.NoError(t, s.Write(entry))
+	}
+	require.NoError(t, s.Close())
+
+	for _, file := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(300))
+	}
+	assert.NoFileExists(t, path+".3")
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	var last exportEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+	assert.Equal(t, uint64(10), last.Id)
+	assert.Equal(t, "login-failed", last.Action)
+}
+
+type blockingSink struct {
+	written []domain.AuditEntry
+}
+
+func (b *blockingSink) Write(entry domain.AuditEntry) error {
+	b.written = append(b.written, entry)
+	return nil
+}
+
+func (b *blockingSink) Close() error {
+	return nil
+}
+
+func TestSinkWorker_DropsEntriesWhenFull(t *testing.T) {
+	s := &blockingSink{}
+	worker := &sinkWorker{name: "test", sink: s, queue: make(chan domain.AuditEntry, 2)}
+
+	// the worker is not running, so the queue is never drained and enqueue must not block
+	for i := 1; i <= 5; i++ {
+		worker.enqueue(domain.AuditEntry{UniqueId: uint64(i)})
+	}
+	assert.Equal(t, uint64(3), worker.dropped.Load())
+
+	// a stopped worker writes the queued entries before it returns
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker.run(ctx)
+	require.Len(t, s.written, 2)
+	assert.Equal(t, uint64(1), s.written[0].UniqueId)
+	assert.Zero(t, worker.dropped.Load())
+}
+
+func TestRecorder_ForwardsToSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := &config.Config{}
+	cfg.Statistics.AuditSinks = []config.AuditSink{{Name: "file", Type: config.AuditSinkFile, Path: path}}
+
+	r, err := NewAuditRecorder(cfg, noopBus{}, &mockRepo{})
+	require.NoError(t, err)
+	r.handleAuthEvent(domain.AuditEventWrapper[AuthEvent]{Source: "password",
+		Event: AuthEvent{Username: "bob", Error: "wrong password"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.sinks[0].run(ctx)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entry exportEntry
+	require.NoError(t, json.Unmarshal(raw, &entry))
+	assert.Equal(t, uint64(1), entry.Id)
+	assert.Equal(t, "login-failed", entry.Action)
+	assert.Equal(t, "high", entry.Severity)
+}
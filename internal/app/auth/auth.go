@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/url"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -84,12 +86,18 @@ type AuthenticatorLdap interface {
 // Authenticator is the main entry point for all authentication related tasks.
 // This includes password authentication and external authentication providers (OIDC, OAuth, LDAP).
 type Authenticator struct {
-	cfg *config.Auth
 	bus EventBus
 
+	// mu protects the configuration and the registered providers, they are replaced on configuration reloads.
+	// The provider maps are never modified after they have been published, so they can be used without the lock.
+	mu                  sync.RWMutex
+	cfg                 *config.Auth
 	oauthAuthenticators map[string]AuthenticatorOauth
 	ldapAuthenticators  map[string]AuthenticatorLdap
 
+	jobCtx            context.Context    // context of the background jobs, set by StartBackgroundJobs
+	stopProviderSetup context.CancelFunc // stops the provider setup of the previous configuration
+
 	// URL prefix for the callback endpoints, this is a combination of the external URL and the API prefix
 	callbackUrlPrefix string
 
@@ -124,16 +132,54 @@ func NewAuthenticator(cfg *config.Auth, extUrl string, bus EventBus, users UserM
 // StartBackgroundJobs starts the background jobs for the authenticator.
 // It sets up the external authentication providers (OIDC, OAuth, LDAP) and retries in case of errors.
 func (a *Authenticator) StartBackgroundJobs(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.jobCtx = ctx
+	a.startProviderSetup()
+}
+
+// ReloadConfig replaces the external authentication providers with the ones of the given configuration.
+// The providers of the previous configuration stay active until the new providers have been set up.
+func (a *Authenticator) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	auth := *a.cfg
+	auth.OpenIDConnect = cfg.Auth.OpenIDConnect
+	auth.OAuth = cfg.Auth.OAuth
+	auth.Ldap = cfg.Auth.Ldap
+	a.cfg = &auth
+
+	if a.jobCtx == nil {
+		return nil // background jobs not started yet, the providers are set up on start
+	}
+	a.stopProviderSetup()
+	a.startProviderSetup()
+
+	return nil
+}
+
+// startProviderSetup sets up the external authentication providers of the current configuration in the background.
+// The caller must hold the lock.
+func (a *Authenticator) startProviderSetup() {
+	ctx, cancel := context.WithCancel(a.jobCtx)
+	a.stopProviderSetup = cancel
+
+	// Initialize local copies of authentication providers to allow retry in case of errors
+	oidcQueue := a.cfg.OpenIDConnect
+	oauthQueue := a.cfg.OAuth
+	ldapQueue := a.cfg.Ldap
+	oauthAuthenticators := make(map[string]AuthenticatorOauth, len(oidcQueue)+len(oauthQueue))
+	ldapAuthenticators := make(map[string]AuthenticatorLdap, len(ldapQueue))
+
 	go func() {
 		slog.Debug("setting up external auth providers...")
 
-		// Initialize local copies of authentication providers to allow retry in case of errors
-		oidcQueue := a.cfg.OpenIDConnect
-		oauthQueue := a.cfg.OAuth
-		ldapQueue := a.cfg.Ldap
-
 		// Immediate attempt
-		failedOidc, failedOauth, failedLdap := a.setupExternalAuthProviders(oidcQueue, oauthQueue, ldapQueue)
+		failedOidc, failedOauth, failedLdap := a.setupExternalAuthProviders(oauthAuthenticators, ldapAuthenticators,
+			oidcQueue, oauthQueue, ldapQueue)
+		a.publishProviders(ctx, oauthAuthenticators, ldapAuthenticators)
 		if len(failedOidc) == 0 && len(failedOauth) == 0 && len(failedLdap) == 0 {
 			slog.Info("successfully setup all external auth providers")
 			return
@@ -152,7 +198,9 @@ func (a *Authenticator) StartBackgroundJobs(ctx context.Context) {
 		for {
 			select {
 			case <-ticker.C:
-				failedOidc, failedOauth, failedLdap := a.setupExternalAuthProviders(oidcQueue, oauthQueue, ldapQueue)
+				failedOidc, failedOauth, failedLdap := a.setupExternalAuthProviders(oauthAuthenticators,
+					ldapAuthenticators, oidcQueue, oauthQueue, ldapQueue)
+				a.publishProviders(ctx, oauthAuthenticators, ldapAuthenticators)
 				if len(failedOidc) > 0 || len(failedOauth) > 0 || len(failedLdap) > 0 {
 					slog.Warn("failed to setup some external auth providers, retrying in 30 seconds",
 						"failedOidc", len(failedOidc), "failedOauth", len(failedOauth), "failedLdap", len(failedLdap))
@@ -172,7 +220,34 @@ func (a *Authenticator) StartBackgroundJobs(ctx context.Context) {
 	}()
 }
 
+// publishProviders makes the given providers available for logins, unless the setup has been stopped because a
+// newer configuration has been loaded.
+func (a *Authenticator) publishProviders(
+	ctx context.Context,
+	oauthAuthenticators map[string]AuthenticatorOauth,
+	ldapAuthenticators map[string]AuthenticatorLdap,
+) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	a.oauthAuthenticators = maps.Clone(oauthAuthenticators)
+	a.ldapAuthenticators = maps.Clone(ldapAuthenticators)
+}
+
+// getProviders returns the current configuration and the registered providers.
+func (a *Authenticator) getProviders() (*config.Auth, map[string]AuthenticatorOauth, map[string]AuthenticatorLdap) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.cfg, a.oauthAuthenticators, a.ldapAuthenticators
+}
+
 func (a *Authenticator) setupExternalAuthProviders(
+	oauthAuthenticators map[string]AuthenticatorOauth,
+	ldapAuthenticators map[string]AuthenticatorLdap,
 	oidc []config.OpenIDConnectProvider,
 	oauth []config.OAuthProvider,
 	ldap []config.LdapProvider,
@@ -192,7 +267,7 @@ func (a *Authenticator) setupExternalAuthProviders(
 		providerCfg := &oidc[i]
 		providerId := strings.ToLower(providerCfg.ProviderName)
 
-		if _, exists := a.oauthAuthenticators[providerId]; exists {
+		if _, exists := oauthAuthenticators[providerId]; exists {
 			// this is an unrecoverable error, we cannot register the same provider twice
 			slog.Error("OIDC auth provider is already registered", "name", providerId)
 			continue // skip this provider
@@ -207,13 +282,13 @@ func (a *Authenticator) setupExternalAuthProviders(
 			slog.Error("failed to setup oidc authentication provider", "name", providerId, "error", err)
 			continue
 		}
-		a.oauthAuthenticators[providerId] = provider
+		oauthAuthenticators[providerId] = provider
 	}
 	for i := range oauth { // PLAIN OAUTH
 		providerCfg := &oauth[i]
 		providerId := strings.ToLower(providerCfg.ProviderName)
 
-		if _, exists := a.oauthAuthenticators[providerId]; exists {
+		if _, exists := oauthAuthenticators[providerId]; exists {
 			// this is an unrecoverable error, we cannot register the same provider twice
 			slog.Error("OAUTH auth provider is already registered", "name", providerId)
 			continue // skip this provider
@@ -228,13 +303,13 @@ func (a *Authenticator) setupExternalAuthProviders(
 			slog.Error("failed to setup oauth authentication provider", "name", providerId, "error", err)
 			continue
 		}
-		a.oauthAuthenticators[providerId] = provider
+		oauthAuthenticators[providerId] = provider
 	}
 	for i := range ldap { // LDAP
 		providerCfg := &ldap[i]
 		providerId := strings.ToLower(providerCfg.URL)
 
-		if _, exists := a.ldapAuthenticators[providerId]; exists {
+		if _, exists := ldapAuthenticators[providerId]; exists {
 			// this is an unrecoverable error, we cannot register the same provider twice
 			slog.Error("LDAP auth provider is already registered", "name", providerId)
 			continue // skip this provider
@@ -246,7 +321,7 @@ func (a *Authenticator) setupExternalAuthProviders(
 			slog.Error("failed to setup ldap authentication provider", "name", providerId, "error", err)
 			continue
 		}
-		a.ldapAuthenticators[providerId] = provider
+		ldapAuthenticators[providerId] = provider
 	}
 
 	return failedOidc, failedOauth, failedLdap
@@ -254,9 +329,10 @@ func (a *Authenticator) setupExternalAuthProviders(
 
 // GetExternalLoginProviders returns a list of all available external login providers.
 func (a *Authenticator) GetExternalLoginProviders(_ context.Context) []domain.LoginProviderInfo {
-	authProviders := make([]domain.LoginProviderInfo, 0, len(a.cfg.OAuth)+len(a.cfg.OpenIDConnect))
+	cfg, _, _ := a.getProviders()
+	authProviders := make([]domain.LoginProviderInfo, 0, len(cfg.OAuth)+len(cfg.OpenIDConnect))
 
-	for _, provider := range a.cfg.OpenIDConnect {
+	for _, provider := range cfg.OpenIDConnect {
 		providerId := strings.ToLower(provider.ProviderName)
 		providerName := provider.DisplayName
 		if providerName == "" {
@@ -270,7 +346,7 @@ func (a *Authenticator) GetExternalLoginProviders(_ context.Context) []domain.Lo
 		})
 	}
 
-	for _, provider := range a.cfg.OAuth {
+	for _, provider := range cfg.OAuth {
 		providerId := strings.ToLower(provider.ProviderName)
 		providerName := provider.DisplayName
 		if providerName == "" {
@@ -364,9 +440,10 @@ func (a *Authenticator) passwordAuthentication(
 		return nil, errors.New("user is locked")
 	}
 
+	_, _, ldapAuthenticators := a.getProviders()
 	if !userInDatabase || userSource == domain.UserSourceLdap {
 		// search user in ldap if registration is enabled
-		for _, ldapAuth := range a.ldapAuthenticators {
+		for _, ldapAuth := range ldapAuthenticators {
 			if !userInDatabase && !ldapAuth.RegistrationEnabled() {
 				continue
 			}
@@ -396,13 +473,13 @@ func (a *Authenticator) passwordAuthentication(
 
 	if userSource == "" {
 		slog.Warn("no user source found for user",
-			"identifier", identifier, "ldapProviderCount", len(a.ldapAuthenticators), "inDb", userInDatabase)
+			"identifier", identifier, "ldapProviderCount", len(ldapAuthenticators), "inDb", userInDatabase)
 		return nil, errors.New("user not found")
 	}
 
 	if userSource == domain.UserSourceLdap && ldapProvider == nil {
 		slog.Warn("no ldap provider found for user",
-			"identifier", identifier, "ldapProviderCount", len(a.ldapAuthenticators), "inDb", userInDatabase)
+			"identifier", identifier, "ldapProviderCount", len(ldapAuthenticators), "inDb", userInDatabase)
 		return nil, errors.New("ldap provider not found")
 	}
 
@@ -439,7 +516,8 @@ func (a *Authenticator) OauthLoginStep1(_ context.Context, providerId string) (
 	authCodeUrl, state, nonce string,
 	err error,
 ) {
-	oauthProvider, ok := a.oauthAuthenticators[providerId]
+	_, oauthAuthenticators, _ := a.getProviders()
+	oauthProvider, ok := oauthAuthenticators[providerId]
 	if !ok {
 		return "", "", "", fmt.Errorf("missing oauth provider %s", providerId)
 	}
@@ -493,7 +571,8 @@ func isDomainAllowed(email string, allowedDomains []string) bool {
 // OauthLoginStep2 finishes the oauth authentication flow by exchanging the code for an access token and
 // fetching the user information.
 func (a *Authenticator) OauthLoginStep2(ctx context.Context, providerId, nonce, code string) (*domain.User, error) {
-	oauthProvider, ok := a.oauthAuthenticators[providerId]
+	_, oauthAuthenticators, _ := a.getProviders()
+	oauthProvider, ok := oauthAuthenticators[providerId]
 	if !ok {
 		return nil, fmt.Errorf("missing oauth provider %s", providerId)
 	}
@@ -617,15 +696,16 @@ func (a *Authenticator) registerNewUser(
 }
 
 func (a *Authenticator) getAuthenticatorConfig(id string) (any, error) {
-	for i := range a.cfg.OpenIDConnect {
-		if a.cfg.OpenIDConnect[i].ProviderName == id {
-			return a.cfg.OpenIDConnect[i], nil
+	cfg, _, _ := a.getProviders()
+	for i := range cfg.OpenIDConnect {
+		if cfg.OpenIDConnect[i].ProviderName == id {
+			return cfg.OpenIDConnect[i], nil
 		}
 	}
 
-	for i := range a.cfg.OAuth {
-		if a.cfg.OAuth[i].ProviderName == id {
-			return a.cfg.OAuth[i], nil
+	for i := range cfg.OAuth {
+		if cfg.OAuth[i].ProviderName == id {
+			return cfg.OAuth[i], nil
 		}
 	}
 
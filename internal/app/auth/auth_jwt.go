@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// jwtDiscoveryRetryInterval limits the discovery attempts of an unreachable issuer.
+const jwtDiscoveryRetryInterval = 30 * time.Second
+
+// JwtUserManager is the user dependency of the JwtAuthenticator.
+type JwtUserManager interface {
+	// GetUser returns a user by its identifier.
+	GetUser(context.Context, domain.UserIdentifier) (*domain.User, error)
+}
+
+// jwtIssuer is an OpenID Connect provider that issues JWT bearer tokens for the REST API.
+// The provider metadata and the signing keys are discovered on first use.
+type jwtIssuer struct {
+	cfg config.OpenIDConnectProvider
+
+	mu            sync.Mutex
+	verifier      *oidc.IDTokenVerifier
+	lastDiscovery time.Time
+}
+
+// JwtAuthenticator authenticates REST API requests with JWT bearer tokens of OpenID Connect providers,
+// for example access tokens of the OAuth2 client credentials flow.
+type JwtAuthenticator struct {
+	users JwtUserManager
+
+	mu      sync.RWMutex
+	issuers map[string]*jwtIssuer // keyed by the issuer URL without trailing slash
+}
+
+// NewJwtAuthenticator creates a new JwtAuthenticator for all OpenID Connect providers with enabled API access.
+func NewJwtAuthenticator(cfg *config.Auth, users JwtUserManager) *JwtAuthenticator {
+	a := &JwtAuthenticator{users: users}
+	a.issuers = newJwtIssuers(cfg.OpenIDConnect)
+
+	return a
+}
+
+func newJwtIssuers(providers []config.OpenIDConnectProvider) map[string]*jwtIssuer {
+	issuers := make(map[string]*jwtIssuer)
+	for _, provider := range providers {
+		if !provider.ApiAccess.Enabled {
+			continue
+		}
+		issuers[strings.TrimSuffix(provider.BaseUrl, "/")] = &jwtIssuer{cfg: provider}
+	}
+
+	return issuers
+}
+
+// ReloadConfig replaces the token issuers with the ones of the given configuration.
+func (a *JwtAuthenticator) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	issuers := newJwtIssuers(cfg.Auth.OpenIDConnect)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.issuers = issuers
+
+	return nil
+}
+
+// AuthenticateBearerToken verifies a JWT bearer token and returns the user it has been issued for. The token scopes
+// are returned as unnamed API token, so that they restrict the request like the scopes of a named API token.
+func (a *JwtAuthenticator) AuthenticateBearerToken(ctx context.Context, rawToken string) (
+	*domain.User,
+	*domain.ApiToken,
+	error,
+) {
+	issuerUrl, err := unverifiedJwtIssuer(rawToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a.mu.RLock()
+	issuer, ok := a.issuers[strings.TrimSuffix(issuerUrl, "/")]
+	a.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown token issuer %s", issuerUrl)
+	}
+
+	verifier, err := issuer.getVerifier()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid token of issuer %s: %w", issuerUrl, err)
+	}
+	var claims map[string]any
+	if err := token.Claims(&claims); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+
+	access := issuer.cfg.ApiAccess
+	user, err := a.getTokenUser(ctx, access, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scopes := mapJwtScopes(access.ScopeMapping, claims[access.GetScopeClaim()])
+	if len(scopes) == 0 {
+		return nil, nil, fmt.Errorf("token of %s grants no api scope", user.Identifier)
+	}
+
+	expiry := token.Expiry
+	apiToken := &domain.ApiToken{
+		Name:           issuer.cfg.ProviderName,
+		UserIdentifier: user.Identifier,
+		Scopes:         scopes,
+		ExpiresAt:      &expiry,
+		CreatedAt:      token.IssuedAt,
+	}
+
+	return user, apiToken, nil
+}
+
+// getTokenUser returns the service identity or the wg-portal user of the user claim.
+func (a *JwtAuthenticator) getTokenUser(ctx context.Context, access config.OidcApiAccess, claims map[string]any) (
+	*domain.User,
+	error,
+) {
+	subject, _ := claims[access.GetUserClaim()].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("token has no %s claim", access.GetUserClaim())
+	}
+
+	for _, identity := range access.ServiceIdentities {
+		if identity.Subject == subject {
+			return &domain.User{
+				Identifier: domain.UserIdentifier(identity.Name),
+				IsAdmin:    identity.Admin,
+			}, nil
+		}
+	}
+
+	user, err := a.users.GetUser(ctx, domain.UserIdentifier(subject))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find user %s: %w", subject, err)
+	}
+	if user.IsDisabled() || user.IsLocked() {
+		return nil, fmt.Errorf("user %s is disabled or locked", user.Identifier)
+	}
+
+	return user, nil
+}
+
+// getVerifier returns the token verifier of the issuer. The provider discovery is retried on later calls if it fails.
+func (i *jwtIssuer) getVerifier() (*oidc.IDTokenVerifier, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.verifier != nil {
+		return i.verifier, nil
+	}
+	if time.Since(i.lastDiscovery) < jwtDiscoveryRetryInterval {
+		return nil, fmt.Errorf("discovery of token issuer %s failed recently", i.cfg.ProviderName)
+	}
+	i.lastDiscovery = time.Now()
+
+	// use new context here, see https://github.com/coreos/go-oidc/issues/339
+	provider, err := oidc.NewProvider(context.Background(), i.cfg.BaseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover token issuer %s: %w", i.cfg.ProviderName, err)
+	}
+
+	audience := i.cfg.ApiAccess.Audience
+	if audience == "" {
+		audience = i.cfg.ClientID
+	}
+	i.verifier = provider.Verifier(&oidc.Config{ClientID: audience})
+
+	return i.verifier, nil
+}
+
+// unverifiedJwtIssuer returns the iss claim of a JWT without verifying the signature. It is only used to select
+// the issuer that verifies the token.
+func unverifiedJwtIssuer(rawToken string) (string, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return "", errors.New("malformed jwt")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed jwt payload: %w", err)
+	}
+
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("malformed jwt payload: %w", err)
+	}
+	if claims.Issuer == "" {
+		return "", errors.New("jwt has no issuer")
+	}
+
+	return claims.Issuer, nil
+}
+
+// mapJwtScopes converts the scope claim, a space separated string or a list, to API token scopes.
+// Without a mapping, the provider scopes are used if they are valid API token scopes.
+func mapJwtScopes(mapping map[string]string, claim any) []domain.ApiTokenScope {
+	var providerScopes []string
+	switch value := claim.(type) {
+	case string:
+		providerScopes = strings.Fields(value)
+	case []any:
+		for _, item := range value {
+			if scope, ok := item.(string); ok {
+				providerScopes = append(providerScopes, scope)
+			}
+		}
+	}
+
+	var scopes []domain.ApiTokenScope
+	for _, providerScope := range providerScopes {
+		scope := domain.ApiTokenScope(providerScope)
+		if len(mapping) > 0 {
+			mapped, ok := mapping[providerScope]
+			if !ok {
+				continue
+			}
+			scope = domain.ApiTokenScope(mapped)
+		}
+		if slices.Contains(domain.ApiTokenScopes, scope) && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
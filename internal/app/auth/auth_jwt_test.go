@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type testJwtIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+// newTestJwtIssuer starts a local OpenID Connect discovery and JWKS endpoint.
+func newTestJwtIssuer(t *testing.T) *testJwtIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &testJwtIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 issuer.server.URL,
+			"jwks_uri":               issuer.server.URL + "/jwks",
+			"authorization_endpoint": issuer.server.URL + "/auth",
+			"token_endpoint":         issuer.server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testJwtIssuer) sign(t *testing.T, claims map[string]any) string {
+	header, err := json.Marshal(map[string]any{"alg": "RS256", "typ": "JWT", "kid": "test"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (i *testJwtIssuer) claims(subject string, scope any) map[string]any {
+	return map[string]any{
+		"iss":   i.server.URL,
+		"aud":   "wg-portal-api",
+		"sub":   subject,
+		"scope": scope,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+type jwtTestUsers map[domain.UserIdentifier]*domain.User
+
+func (u jwtTestUsers) GetUser(_ context.Context, id domain.UserIdentifier) (*domain.User, error) {
+	if user, ok := u[id]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestJwtAuthenticator_AuthenticateBearerToken(t *testing.T) {
+	issuer := newTestJwtIssuer(t)
+	disabled := time.Now()
+	users := jwtTestUsers{
+		"alice": {Identifier: "alice"},
+		"bob":   {Identifier: "bob", Disabled: &disabled},
+	}
+	a := NewJwtAuthenticator(&config.Auth{OpenIDConnect: []config.OpenIDConnectProvider{{
+		ProviderName: "idp",
+		BaseUrl:      issuer.server.URL,
+		ClientID:     "wg-portal",
+		ApiAccess: config.OidcApiAccess{
+			Enabled:  true,
+			Audience: "wg-portal-api",
+			ScopeMapping: map[string]string{
+				"wgportal.read":  "read-only",
+				"wgportal.peers": "peers:write",
+			},
+			ServiceIdentities: []config.OidcServiceIdentity{
+				{Subject: "ci-client", Name: "ci", Admin: true},
+			},
+		},
+	}}}, users)
+	ctx := context.Background()
+
+	user, token, err := a.AuthenticateBearerToken(ctx,
+		issuer.sign(t, issuer.claims("ci-client", "openid wgportal.peers")))
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserIdentifier("ci"), user.Identifier)
+	assert.True(t, user.IsAdmin)
+	assert.Equal(t, "idp", token.Name)
+	assert.Equal(t, []domain.ApiTokenScope{domain.ApiTokenScopePeersWrite}, token.Scopes)
+
+	user, token, err = a.AuthenticateBearerToken(ctx,
+		issuer.sign(t, issuer.claims("alice", []any{"wgportal.read", "wgportal.read"})))
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserIdentifier("alice"), user.Identifier)
+	assert.False(t, user.IsAdmin)
+	assert.Equal(t, []domain.ApiTokenScope{domain.ApiTokenScopeReadOnly}, token.Scopes)
+
+	_, _, err = a.AuthenticateBearerToken(ctx, issuer.sign(t, issuer.claims("alice", "openid")))
+	assert.Error(t, err, "no mapped scope")
+	_, _, err = a.AuthenticateBearerToken(ctx, issuer.sign(t, issuer.claims("bob", "wgportal.read")))
+	assert.Error(t, err, "disabled user")
+	_, _, err = a.AuthenticateBearerToken(ctx, issuer.sign(t, issuer.claims("carol", "wgportal.read")))
+	assert.True(t, errors.Is(err, domain.ErrNotFound), "unknown user")
+
+	claims := issuer.claims("alice", "wgportal.read")
+	claims["aud"] = "other-api"
+	_, _, err = a.AuthenticateBearerToken(ctx, issuer.sign(t, claims))
+	assert.Error(t, err, "wrong audience")
+
+	claims = issuer.claims("alice", "wgportal.read")
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, _, err = a.AuthenticateBearerToken(ctx, issuer.sign(t, claims))
+	assert.Error(t, err, "expired token")
+
+	other := newTestJwtIssuer(t)
+	claims = issuer.claims("alice", "wgportal.read")
+	_, _, err = a.AuthenticateBearerToken(ctx, other.sign(t, claims))
+	assert.Error(t, err, "foreign signing key")
+	_, _, err = a.AuthenticateBearerToken(ctx, other.sign(t, other.claims("alice", "wgportal.read")))
+	assert.Error(t, err, "unknown issuer")
+
+	require.NoError(t, a.ReloadConfig(ctx, &config.Config{}))
+	_, _, err = a.AuthenticateBearerToken(ctx, issuer.sign(t, issuer.claims("ci-client", "wgportal.peers")))
+	assert.Error(t, err, "api access disabled by reload")
+}
+
+func TestMapJwtScopes(t *testing.T) {
+	assert.Equal(t, []domain.ApiTokenScope{domain.ApiTokenScopeFull, domain.ApiTokenScopeProvisioning},
+		mapJwtScopes(nil, "openid full provisioning unknown"))
+	assert.Empty(t, mapJwtScopes(map[string]string{"a": "full"}, "full"))
+	assert.Empty(t, mapJwtScopes(nil, 42))
+}
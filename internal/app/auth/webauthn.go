@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -149,12 +150,15 @@ func (a *WebAuthnAuthenticator) FinishWebAuthnRegistration(
 	if err != nil {
 		return nil, err
 	}
+	added := user.WebAuthnCredentialList[len(user.WebAuthnCredentialList)-1]
 
 	user, err = a.users.UpdateUser(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
+	a.publishCredentialAuditEvent(ctx, "create", added, nil)
+
 	return user.WebAuthnCredentialList, nil
 }
 
@@ -180,12 +184,26 @@ func (a *WebAuthnAuthenticator) RemoveCredential(
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	user.RemoveCredential(credentialIdBase64)
+	idx := slices.IndexFunc(user.WebAuthnCredentialList, func(c domain.UserWebauthnCredential) bool {
+		return c.CredentialIdentifier == credentialIdBase64
+	})
+
+	var removed *domain.UserWebauthnCredential
+	if idx >= 0 {
+		credential := user.WebAuthnCredentialList[idx] // copy, the list is modified in place
+		removed = &credential
+		user.RemoveCredential(credentialIdBase64)
+	}
+
 	user, err = a.users.UpdateUser(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
+	if removed != nil {
+		a.publishCredentialAuditEvent(ctx, "delete", *removed, nil)
+	}
+
 	return user.WebAuthnCredentialList, nil
 }
 
@@ -200,6 +218,13 @@ func (a *WebAuthnAuthenticator) UpdateCredential(
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
+	var previous domain.UserWebauthnCredential
+	for _, c := range user.WebAuthnCredentialList {
+		if c.CredentialIdentifier == credentialIdBase64 {
+			previous = c
+		}
+	}
+
 	err = user.UpdateCredential(credentialIdBase64, name)
 	if err != nil {
 		return nil, err
@@ -210,9 +235,30 @@ func (a *WebAuthnAuthenticator) UpdateCredential(
 		return nil, err
 	}
 
+	updated := previous
+	updated.DisplayName = name
+	a.publishCredentialAuditEvent(ctx, "update", updated, &previous)
+
 	return user.WebAuthnCredentialList, nil
 }
 
+// publishCredentialAuditEvent publishes the change of a passkey for the audit log, previous is only set for updates.
+func (a *WebAuthnAuthenticator) publishCredentialAuditEvent(
+	ctx context.Context,
+	action string,
+	credential domain.UserWebauthnCredential,
+	previous *domain.UserWebauthnCredential,
+) {
+	a.bus.Publish(app.TopicAuditWebAuthnChanged, domain.AuditEventWrapper[audit.WebAuthnCredentialEvent]{
+		Ctx: ctx,
+		Event: audit.WebAuthnCredentialEvent{
+			Credential: credential,
+			Previous:   previous,
+			Action:     action,
+		},
+	})
+}
+
 func (a *WebAuthnAuthenticator) StartWebAuthnLogin(_ context.Context) (
 	optionsAsJSON []byte,
 	sessionDataAsJSON []byte,
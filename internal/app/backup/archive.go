@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// FormatVersion is the version of the archive format. It must be incremented if the archive content changes in an
+// incompatible way.
+const FormatVersion = 1
+
+// The archive starts with a header of the magic bytes, the format version, the scrypt salt and the AES-GCM nonce.
+// It is followed by the encrypted, gzip compressed JSON document. The header is authenticated as additional data.
+var archiveMagic = []byte("WGPBACKUP")
+
+const (
+	saltLength = 16
+	keyLength  = 32 // AES-256
+	scryptN    = 1 << 15
+	scryptR    = 8
+	scryptP    = 1
+)
+
+// document is the JSON content of an archive. Passwords and the peer status timestamps are hidden in the JSON
+// representation of the domain models, so they are stored in separate fields.
+type document struct {
+	Info domain.BackupInfo `json:"Info"`
+
+	Users                []archiveUser                   `json:"Users"`
+	WebAuthnCredentials  []domain.UserWebauthnCredential `json:"WebAuthnCredentials"`
+	ApiTokens            []domain.ApiToken               `json:"ApiTokens"`
+	WebhookSubscriptions []domain.WebhookSubscription    `json:"WebhookSubscriptions"`
+	Interfaces           []domain.Interface              `json:"Interfaces"`
+	Peers                []domain.Peer                   `json:"Peers"`
+	InterfaceStatuses    []domain.InterfaceStatus        `json:"InterfaceStatuses"`
+	PeerStatuses         []archivePeerStatus             `json:"PeerStatuses"`
+	AuditEntries         []domain.AuditEntry             `json:"AuditEntries"`
+	AuditCheckpoints     []domain.AuditCheckpoint        `json:"AuditCheckpoints"`
+}
+
+type archiveUser struct {
+	domain.User
+	Password string `json:"Password"` // the password hash
+}
+
+type archivePeerStatus struct {
+	domain.PeerStatus
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+func newDocument(info domain.BackupInfo, data *domain.BackupData) *document {
+	doc := &document{
+		Info:                 info,
+		Users:                make([]archiveUser, len(data.Users)),
+		WebAuthnCredentials:  data.WebAuthnCredentials,
+		ApiTokens:            data.ApiTokens,
+		WebhookSubscriptions: data.WebhookSubscriptions,
+		Interfaces:           data.Interfaces,
+		Peers:                data.Peers,
+		InterfaceStatuses:    data.InterfaceStatuses,
+		PeerStatuses:         make([]archivePeerStatus, len(data.PeerStatuses)),
+		AuditEntries:         data.AuditEntries,
+		AuditCheckpoints:     data.AuditCheckpoints,
+	}
+	for i, user := range data.Users {
+		user.WebAuthnCredentialList = nil
+		doc.Users[i] = archiveUser{User: user, Password: string(user.Password)}
+	}
+	for i, status := range data.PeerStatuses {
+		doc.PeerStatuses[i] = archivePeerStatus{PeerStatus: status, UpdatedAt: status.UpdatedAt}
+	}
+	return doc
+}
+
+func (d *document) data() *domain.BackupData {
+	data := &domain.BackupData{
+		Users:                make([]domain.User, len(d.Users)),
+		WebAuthnCredentials:  d.WebAuthnCredentials,
+		ApiTokens:            d.ApiTokens,
+		WebhookSubscriptions: d.WebhookSubscriptions,
+		Interfaces:           d.Interfaces,
+		Peers:                d.Peers,
+		InterfaceStatuses:    d.InterfaceStatuses,
+		PeerStatuses:         make([]domain.PeerStatus, len(d.PeerStatuses)),
+		AuditEntries:         d.AuditEntries,
+		AuditCheckpoints:     d.AuditCheckpoints,
+	}
+	for i, user := range d.Users {
+		data.Users[i] = user.User
+		data.Users[i].Password = domain.PrivateString(user.Password)
+	}
+	for i, status := range d.PeerStatuses {
+		data.PeerStatuses[i] = status.PeerStatus
+		data.PeerStatuses[i].UpdatedAt = status.UpdatedAt
+	}
+	return data
+}
+
+// writeArchive encrypts the document with a key derived from the passphrase and writes the archive.
+func writeArchive(w io.Writer, doc *document, passphrase string) error {
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode backup: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress backup: %w", err)
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := newCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	header := append(append(append(append([]byte{}, archiveMagic...), FormatVersion), salt...), nonce...)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Write(gcm.Seal(nil, nonce, plain.Bytes(), header))
+	return err
+}
+
+// readArchive decrypts and decodes an archive.
+func readArchive(r io.Reader, passphrase string) (*document, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	if len(raw) < len(archiveMagic)+1 || !bytes.Equal(raw[:len(archiveMagic)], archiveMagic) {
+		return nil, fmt.Errorf("not a WireGuard Portal backup: %w", domain.ErrInvalidData)
+	}
+	if version := int(raw[len(archiveMagic)]); version != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d, expected %d: %w", version, FormatVersion,
+			domain.ErrInvalidData)
+	}
+
+	saltStart := len(archiveMagic) + 1
+	if len(raw) < saltStart+saltLength {
+		return nil, fmt.Errorf("truncated backup: %w", domain.ErrInvalidData)
+	}
+	gcm, err := newCipher(passphrase, raw[saltStart:saltStart+saltLength])
+	if err != nil {
+		return nil, err
+	}
+	headerLength := saltStart + saltLength + gcm.NonceSize()
+	if len(raw) < headerLength {
+		return nil, fmt.Errorf("truncated backup: %w", domain.ErrInvalidData)
+	}
+
+	header, ciphertext := raw[:headerLength], raw[headerLength:]
+	plain, err := gcm.Open(nil, header[saltStart+saltLength:], ciphertext, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt backup, wrong passphrase or corrupted file: %w",
+			domain.ErrInvalidData)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress backup: %w: %w", err, domain.ErrInvalidData)
+	}
+	var doc document
+	if err := json.NewDecoder(zr).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode backup: %w: %w", err, domain.ErrInvalidData)
+	}
+
+	return &doc, nil
+}
+
+func newCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.Join(errors.New("missing backup passphrase"), domain.ErrInvalidData)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/biezax/wg-portal/internal"
+	"github.com/biezax/wg-portal/internal/adapters"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// region dependencies
+
+type DatabaseRepo interface {
+	// GetBackupData returns all entities of the database.
+	GetBackupData(ctx context.Context) (*domain.BackupData, error)
+	// RestoreBackupData replaces all entities of the database with the given data.
+	RestoreBackupData(ctx context.Context, data *domain.BackupData) error
+}
+
+type WireGuardManager interface {
+	// RestoreInterfaceState restores the state of all physical interfaces and their peers.
+	RestoreInterfaceState(ctx context.Context, updateDbOnError bool, filter ...domain.InterfaceIdentifier) error
+}
+
+type AuditRecorder interface {
+	// ReloadChain reloads the head of the audit hash chain.
+	ReloadChain(ctx context.Context) error
+}
+
+// endregion dependencies
+
+// Manager creates and restores encrypted backups of all portal data. Backups are database independent, so they can
+// be used to move the portal to another database type.
+type Manager struct {
+	cfg *config.Config
+
+	db    DatabaseRepo
+	wg    WireGuardManager
+	audit AuditRecorder
+}
+
+// NewManager creates a new backup manager instance.
+func NewManager(cfg *config.Config, db DatabaseRepo, wg WireGuardManager, audit AuditRecorder) *Manager {
+	return &Manager{
+		cfg:   cfg,
+		db:    db,
+		wg:    wg,
+		audit: audit,
+	}
+}
+
+// Backup writes an archive of all entities to w, encrypted with the given passphrase.
+func (m Manager) Backup(ctx context.Context, w io.Writer, passphrase string) (*domain.BackupInfo, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := m.db.GetBackupData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backup data: %w", err)
+	}
+
+	info := domain.BackupInfo{
+		FormatVersion: FormatVersion,
+		SchemaVersion: adapters.SchemaVersion,
+		AppVersion:    internal.Version,
+		DatabaseType:  string(m.cfg.Database.Type),
+		CreatedAt:     time.Now().UTC(),
+		CreatedBy:     string(domain.GetUserInfo(ctx).Id),
+	}
+	info.SetCounts(data)
+
+	if err := writeArchive(w, newDocument(info, data), passphrase); err != nil {
+		return nil, err
+	}
+
+	slog.Info("backup created", "user", info.CreatedBy, "users", info.Users, "interfaces", info.Interfaces,
+		"peers", info.Peers)
+
+	return &info, nil
+}
+
+// Restore replaces all entities with the content of the given archive. The archive is validated first, if it is
+// invalid, or if dryRun is set, the database is not changed. After a restore, the interfaces and peers are applied
+// to the WireGuard backends.
+func (m Manager) Restore(
+	ctx context.Context,
+	r io.Reader,
+	passphrase string,
+	dryRun bool,
+) (*domain.BackupInfo, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	doc, err := readArchive(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	info := doc.Info
+	data := doc.data()
+	info.SetCounts(data)
+
+	if err := validate(&info, data); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return &info, nil
+	}
+
+	if err := m.db.RestoreBackupData(ctx, data); err != nil {
+		return nil, fmt.Errorf("failed to restore backup: %w", err)
+	}
+	// new audit entries have to be linked to the restored hash chain
+	if err := m.audit.ReloadChain(ctx); err != nil {
+		return nil, fmt.Errorf("failed to reload audit hash chain: %w", err)
+	}
+
+	slog.Info("backup restored", "user", domain.GetUserInfo(ctx).Id, "created", info.CreatedAt,
+		"source_database", info.DatabaseType, "users", info.Users, "interfaces", info.Interfaces, "peers", info.Peers)
+
+	if err := m.wg.RestoreInterfaceState(ctx, true); err != nil {
+		slog.Warn("failed to apply restored interfaces", "error", err)
+	}
+
+	return &info, nil
+}
+
+// validate checks the schema version and the references between the entities of a backup.
+func validate(info *domain.BackupInfo, data *domain.BackupData) error {
+	switch {
+	case info.SchemaVersion > adapters.SchemaVersion:
+		return fmt.Errorf("backup has been created by a newer version with schema version %d, "+
+			"this version supports schema version %d: %w", info.SchemaVersion, adapters.SchemaVersion,
+			domain.ErrInvalidData)
+	case info.SchemaVersion < adapters.SchemaVersion:
+		return fmt.Errorf("backup schema version %d is outdated, this version requires schema version %d: %w",
+			info.SchemaVersion, adapters.SchemaVersion, domain.ErrInvalidData)
+	}
+
+	users := make(map[domain.UserIdentifier]struct{}, len(data.Users))
+	for _, user := range data.Users {
+		if user.Identifier == "" {
+			return fmt.Errorf("backup contains a user without identifier: %w", domain.ErrInvalidData)
+		}
+		users[user.Identifier] = struct{}{}
+	}
+	for _, credential := range data.WebAuthnCredentials {
+		if _, ok := users[domain.UserIdentifier(credential.UserIdentifier)]; !ok {
+			return fmt.Errorf("webauthn credential of unknown user %s: %w", credential.UserIdentifier,
+				domain.ErrInvalidData)
+		}
+	}
+	for _, token := range data.ApiTokens {
+		if _, ok := users[token.UserIdentifier]; !ok {
+			return fmt.Errorf("api token of unknown user %s: %w", token.UserIdentifier, domain.ErrInvalidData)
+		}
+	}
+
+	interfaces := make(map[domain.InterfaceIdentifier]struct{}, len(data.Interfaces))
+	for _, iface := range data.Interfaces {
+		if iface.Identifier == "" {
+			return fmt.Errorf("backup contains an interface without identifier: %w", domain.ErrInvalidData)
+		}
+		interfaces[iface.Identifier] = struct{}{}
+	}
+	for _, peer := range data.Peers {
+		if _, ok := interfaces[peer.InterfaceIdentifier]; !ok {
+			return fmt.Errorf("peer %s belongs to unknown interface %s: %w", peer.Identifier,
+				peer.InterfaceIdentifier, domain.ErrInvalidData)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+
+	"github.com/biezax/wg-portal/internal/adapters"
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func init() {
+	schema.RegisterSerializer("encstr", app.NewGormEncryptedStringSerializer("database-encryption-key"))
+}
+
+type mockWireGuard struct {
+	restored int
+}
+
+func (f *mockWireGuard) RestoreInterfaceState(_ context.Context, _ bool, _ ...domain.InterfaceIdentifier) error {
+	f.restored++
+	return nil
+}
+
+type mockAudit struct {
+	reloaded int
+}
+
+func (f *mockAudit) ReloadChain(_ context.Context) error {
+	f.reloaded++
+	return nil
+}
+
+func newTestRepo(t *testing.T, name string) *adapters.SqlRepo {
+	db, err := adapters.NewDatabase(config.DatabaseConfig{
+		Type: config.DatabaseSQLite,
+		DSN:  filepath.Join(t.TempDir(), name+".db"),
+	})
+	require.NoError(t, err)
+	repo, err := adapters.NewSqlRepository(db)
+	require.NoError(t, err)
+	return repo
+}
+
+func adminContext() context.Context {
+	return domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+}
+
+func saveUser(t *testing.T, repo *adapters.SqlRepo, user domain.User) {
+	err := repo.SaveUser(adminContext(), user.Identifier, func(_ *domain.User) (*domain.User, error) {
+		return &user, nil
+	})
+	require.NoError(t, err)
+}
+
+func seedSourceRepo(t *testing.T, repo *adapters.SqlRepo) {
+	ctx := adminContext()
+
+	saveUser(t, repo, domain.User{
+		Identifier: "alice",
+		Email:      "alice@example.com",
+		Source:     domain.UserSourceDatabase,
+		IsAdmin:    true,
+		Password:   "$2a$10$abcdefghijklmnopqrstuuJ1r1uQeC3hV0zEj7P0m4yXSKz5sGxHW",
+		WebAuthnCredentialList: []domain.UserWebauthnCredential{
+			{UserIdentifier: "alice", CredentialIdentifier: "cred-1", DisplayName: "key",
+				SerializedCredential: "e30=", CreatedAt: time.Now().UTC()},
+		},
+	})
+
+	addr, err := domain.CidrFromString("10.11.12.1/24")
+	require.NoError(t, err)
+	err = repo.SaveInterface(ctx, "wg0", func(in *domain.Interface) (*domain.Interface, error) {
+		in.KeyPair = domain.KeyPair{PrivateKey: "interface-private", PublicKey: "interface-public"}
+		in.Addresses = []domain.Cidr{addr}
+		in.ListenPort = 51820
+		in.Type = domain.InterfaceTypeServer
+		return in, nil
+	})
+	require.NoError(t, err)
+
+	peerAddr, err := domain.CidrFromString("10.11.12.2/32")
+	require.NoError(t, err)
+	err = repo.SavePeer(ctx, "peer-public", func(p *domain.Peer) (*domain.Peer, error) {
+		p.InterfaceIdentifier = "wg0"
+		p.UserIdentifier = "alice"
+		p.DisplayName = "alice laptop"
+		p.PresharedKey = "preshared"
+		p.Interface.KeyPair = domain.KeyPair{PrivateKey: "peer-private", PublicKey: "peer-public"}
+		p.Interface.Addresses = []domain.Cidr{peerAddr}
+		return p, nil
+	})
+	require.NoError(t, err)
+
+	err = repo.UpdatePeerStatus(ctx, "peer-public", func(s *domain.PeerStatus) (*domain.PeerStatus, error) {
+		s.BytesReceived = 42
+		return s, nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveAuditEntry(ctx, &domain.AuditEntry{CreatedAt: time.Now(), Message: "first"}))
+	require.NoError(t, repo.SaveAuditEntry(ctx, &domain.AuditEntry{CreatedAt: time.Now(), Message: "second"}))
+	require.NoError(t, repo.SaveAuditCheckpoint(ctx, &domain.AuditCheckpoint{CreatedAt: time.Now(),
+		EntryHash: "source-hash"}))
+}
+
+func TestManager_BackupAndRestore(t *testing.T) {
+	ctx := adminContext()
+	source := newTestRepo(t, "source")
+	seedSourceRepo(t, source)
+
+	var archive bytes.Buffer
+	info, err := NewManager(&config.Config{}, source, &mockWireGuard{}, &mockAudit{}).Backup(ctx, &archive,
+		"secret passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.Users)
+	assert.Equal(t, 1, info.WebAuthnCredentials)
+	assert.Equal(t, 1, info.Peers)
+	assert.Equal(t, 2, info.AuditEntries)
+	assert.Equal(t, 1, info.AuditCheckpoints)
+	assert.NotContains(t, archive.String(), "peer-private", "the archive is encrypted")
+
+	target := newTestRepo(t, "target")
+	saveUser(t, target, domain.User{Identifier: "bob", Source: domain.UserSourceDatabase})
+	require.NoError(t, target.SaveAuditEntry(ctx, &domain.AuditEntry{CreatedAt: time.Now(), Message: "existing"}))
+	require.NoError(t, target.SaveAuditCheckpoint(ctx, &domain.AuditCheckpoint{CreatedAt: time.Now(),
+		EntryHash: "existing"}))
+
+	wg := &mockWireGuard{}
+	audit := &mockAudit{}
+	restored, err := NewManager(&config.Config{}, target, wg, audit).Restore(ctx, bytes.NewReader(archive.Bytes()),
+		"secret passphrase", false)
+	require.NoError(t, err)
+	assert.Equal(t, adapters.SchemaVersion, restored.SchemaVersion)
+	assert.Equal(t, 1, wg.restored)
+	assert.Equal(t, 1, audit.reloaded)
+
+	users, err := target.GetAllUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, domain.UserIdentifier("alice"), users[0].Identifier)
+	assert.Equal(t, domain.PrivateString("$2a$10$abcdefghijklmnopqrstuuJ1r1uQeC3hV0zEj7P0m4yXSKz5sGxHW"),
+		users[0].Password)
+	require.Len(t, users[0].WebAuthnCredentialList, 1)
+
+	iface, peers, err := target.GetInterfaceAndPeers(ctx, "wg0")
+	require.NoError(t, err)
+	assert.Equal(t, "interface-private", iface.PrivateKey)
+	assert.Equal(t, "10.11.12.1/24", domain.CidrsToString(iface.Addresses))
+	require.Len(t, peers, 1)
+	assert.Equal(t, "peer-private", peers[0].Interface.PrivateKey)
+	assert.Equal(t, domain.PreSharedKey("preshared"), peers[0].PresharedKey)
+	assert.Equal(t, "10.11.12.2/32", domain.CidrsToString(peers[0].Interface.Addresses))
+
+	stats, err := target.GetPeersStats(ctx, "peer-public")
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, uint64(42), stats[0].BytesReceived)
+
+	entries, err := target.GetAllAuditEntries(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "second", entries[0].Message)
+
+	checkpoints, err := target.GetAuditCheckpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, checkpoints, 1)
+	assert.Equal(t, "source-hash", checkpoints[0].EntryHash)
+
+	// new audit entries get fresh identifiers
+	require.NoError(t, target.SaveAuditEntry(ctx, &domain.AuditEntry{CreatedAt: time.Now(), Message: "third"}))
+}
+
+func TestManager_RestoreValidation(t *testing.T) {
+	ctx := adminContext()
+	source := newTestRepo(t, "source")
+	seedSourceRepo(t, source)
+
+	var archive bytes.Buffer
+	_, err := NewManager(&config.Config{}, source, &mockWireGuard{}, &mockAudit{}).Backup(ctx, &archive,
+		"secret passphrase")
+	require.NoError(t, err)
+
+	target := newTestRepo(t, "target")
+	saveUser(t, target, domain.User{Identifier: "bob", Source: domain.UserSourceDatabase})
+	m := NewManager(&config.Config{}, target, &mockWireGuard{}, &mockAudit{})
+
+	_, err = m.Restore(ctx, bytes.NewReader(archive.Bytes()), "wrong passphrase", false)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+
+	_, err = m.Restore(ctx, bytes.NewReader([]byte("not a backup")), "secret passphrase", false)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+
+	info, err := m.Restore(ctx, bytes.NewReader(archive.Bytes()), "secret passphrase", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.Interfaces)
+
+	users, err := target.GetAllUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, domain.UserIdentifier("bob"), users[0].Identifier, "failed and dry-run restores change nothing")
+}
+
+func TestManager_RestoreSchemaVersion(t *testing.T) {
+	var archive bytes.Buffer
+	info := domain.BackupInfo{FormatVersion: FormatVersion, SchemaVersion: adapters.SchemaVersion + 1}
+	require.NoError(t, writeArchive(&archive, newDocument(info, &domain.BackupData{}), "passphrase"))
+
+	m := NewManager(&config.Config{}, nil, &mockWireGuard{}, &mockAudit{})
+	_, err := m.Restore(adminContext(), &archive, "passphrase", false)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+	assert.ErrorContains(t, err, "newer version")
+}
+
+func TestManager_RequiresAdmin(t *testing.T) {
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "alice"})
+	m := NewManager(&config.Config{}, nil, &mockWireGuard{}, &mockAudit{})
+
+	_, err := m.Backup(ctx, &bytes.Buffer{}, "passphrase")
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+	_, err = m.Restore(ctx, &bytes.Buffer{}, "passphrase", true)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+}
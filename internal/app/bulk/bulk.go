@@ -0,0 +1,623 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// region dependencies
+
+type UserManager interface {
+	// GetAllUsers returns all users.
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	// CreateUser creates a new user.
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	// UpdateUser updates the user with the given identifier.
+	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	// DeleteUser deletes the user with the given identifier.
+	DeleteUser(ctx context.Context, id domain.UserIdentifier) error
+}
+
+type WireGuardManager interface {
+	// GetAllInterfacesAndPeers returns all interfaces and their peers.
+	GetAllInterfacesAndPeers(ctx context.Context) ([]domain.Interface, [][]domain.Peer, error)
+	// GetUserPeers returns all peers linked to the given user.
+	GetUserPeers(ctx context.Context, id domain.UserIdentifier) ([]domain.Peer, error)
+	// GetPeer returns the peer with the given identifier.
+	GetPeer(ctx context.Context, id domain.PeerIdentifier) (*domain.Peer, error)
+	// CreateUserPeerOnInterface creates a new peer for the given user on the given interface.
+	CreateUserPeerOnInterface(
+		ctx context.Context,
+		userId domain.UserIdentifier,
+		interfaceId domain.InterfaceIdentifier,
+	) (*domain.Peer, error)
+	// UpdatePeer updates the given peer.
+	UpdatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
+	// DeletePeer deletes the peer with the given identifier.
+	DeletePeer(ctx context.Context, id domain.PeerIdentifier) error
+}
+
+// endregion dependencies
+
+// Manager imports and exports users and peers in bulk, as CSV or JSON files.
+type Manager struct {
+	cfg *config.Config
+
+	users UserManager
+	wg    WireGuardManager
+}
+
+// NewManager creates a new bulk import and export manager instance.
+func NewManager(cfg *config.Config, users UserManager, wg WireGuardManager) *Manager {
+	return &Manager{
+		cfg:   cfg,
+		users: users,
+		wg:    wg,
+	}
+}
+
+// ExportUsers writes all users to the given writer. Passwords are never exported.
+func (m Manager) ExportUsers(ctx context.Context, w io.Writer, format domain.BulkFormat) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	s, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	records := make([]domain.BulkUser, len(s.userList))
+	for i, user := range s.userList {
+		records[i] = domain.BulkUser{
+			Identifier: user.Identifier,
+			Email:      user.Email,
+			Source:     user.Source,
+			Firstname:  user.Firstname,
+			Lastname:   user.Lastname,
+			Phone:      user.Phone,
+			Department: user.Department,
+			Notes:      user.Notes,
+			IsAdmin:    user.IsAdmin,
+			Disabled:   user.IsDisabled(),
+			Interfaces: s.userInterfaces(user.Identifier),
+		}
+	}
+
+	return writeUsers(w, format, records)
+}
+
+// ExportPeers writes all peers of all interfaces to the given writer. Keys are never exported.
+func (m Manager) ExportPeers(ctx context.Context, w io.Writer, format domain.BulkFormat) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	s, err := m.loadState(ctx)
+	if err != nil {
+		return err
+	}
+
+	records := make([]domain.BulkPeer, len(s.peerList))
+	for i, peer := range s.peerList {
+		records[i] = domain.BulkPeer{
+			Identifier:          peer.Identifier,
+			InterfaceIdentifier: peer.InterfaceIdentifier,
+			UserIdentifier:      peer.UserIdentifier,
+			DisplayName:         peer.DisplayName,
+			Notes:               peer.Notes,
+			Addresses:           domain.CidrsToString(peer.Interface.Addresses),
+			Disabled:            peer.IsDisabled(),
+			ExpiresAt:           peer.ExpiresAt,
+		}
+	}
+
+	return writePeers(w, format, records)
+}
+
+// ImportUsers creates or updates the users read from the given reader. For each interface listed for a user, a new
+// peer is created if the user has no peer on that interface yet. The returned report contains the result of each row.
+func (m Manager) ImportUsers(
+	ctx context.Context,
+	r io.Reader,
+	format domain.BulkFormat,
+	mode domain.BulkImportMode,
+	dryRun bool,
+) (*domain.BulkImportReport, error) {
+	if err := m.validateImport(ctx, format, mode); err != nil {
+		return nil, err
+	}
+
+	records, err := readUsers(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := m.loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*importRow, len(records))
+	seen := make(map[domain.UserIdentifier]struct{}, len(records))
+	for i, rec := range records {
+		rows[i] = m.prepareUser(s, rec, seen)
+	}
+
+	return m.runImport(ctx, mode, dryRun, rows), nil
+}
+
+// ImportPeers updates the peers read from the given reader. Rows without an identifier create a new peer for the
+// given user on the given interface. The returned report contains the result of each row.
+func (m Manager) ImportPeers(
+	ctx context.Context,
+	r io.Reader,
+	format domain.BulkFormat,
+	mode domain.BulkImportMode,
+	dryRun bool,
+) (*domain.BulkImportReport, error) {
+	if err := m.validateImport(ctx, format, mode); err != nil {
+		return nil, err
+	}
+
+	records, err := readPeers(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := m.loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*importRow, len(records))
+	seen := make(map[domain.PeerIdentifier]struct{}, len(records))
+	for i, rec := range records {
+		rows[i] = m.preparePeer(s, rec, seen)
+	}
+
+	return m.runImport(ctx, mode, dryRun, rows), nil
+}
+
+func (m Manager) validateImport(ctx context.Context, format domain.BulkFormat, mode domain.BulkImportMode) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+	if err := format.Validate(); err != nil {
+		return err
+	}
+	return mode.Validate()
+}
+
+// region state
+
+// state is a snapshot of the existing users, interfaces and peers, used to validate the import rows.
+type state struct {
+	userList   []domain.User
+	users      map[domain.UserIdentifier]*domain.User
+	interfaces map[domain.InterfaceIdentifier]*domain.Interface
+	peerList   []domain.Peer
+	peers      map[domain.PeerIdentifier]*domain.Peer
+}
+
+func (m Manager) loadState(ctx context.Context) (*state, error) {
+	users, err := m.users.GetAllUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load users: %w", err)
+	}
+	interfaces, interfacePeers, err := m.wg.GetAllInterfacesAndPeers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load interfaces: %w", err)
+	}
+
+	s := &state{
+		userList:   users,
+		users:      make(map[domain.UserIdentifier]*domain.User, len(users)),
+		interfaces: make(map[domain.InterfaceIdentifier]*domain.Interface, len(interfaces)),
+		peers:      make(map[domain.PeerIdentifier]*domain.Peer),
+	}
+	for i := range s.userList {
+		s.users[s.userList[i].Identifier] = &s.userList[i]
+	}
+	for i := range interfaces {
+		s.interfaces[interfaces[i].Identifier] = &interfaces[i]
+		s.peerList = append(s.peerList, interfacePeers[i]...)
+	}
+	for i := range s.peerList {
+		s.peers[s.peerList[i].Identifier] = &s.peerList[i]
+	}
+
+	return s, nil
+}
+
+// userInterfaces returns the interfaces the given user has peers on, in interface order.
+func (s *state) userInterfaces(id domain.UserIdentifier) []domain.InterfaceIdentifier {
+	var interfaces []domain.InterfaceIdentifier
+	for _, peer := range s.peerList {
+		if peer.UserIdentifier == id && !slices.Contains(interfaces, peer.InterfaceIdentifier) {
+			interfaces = append(interfaces, peer.InterfaceIdentifier)
+		}
+	}
+	return interfaces
+}
+
+// checkUserInterface returns an error if no user peers can be created on the given interface.
+func (s *state) checkUserInterface(id domain.InterfaceIdentifier) error {
+	iface, ok := s.interfaces[id]
+	switch {
+	case !ok:
+		return fmt.Errorf("interface %s not found", id)
+	case iface.IsDisabled():
+		return fmt.Errorf("interface %s is disabled", id)
+	case iface.Type != domain.InterfaceTypeServer && iface.Type != domain.InterfaceTypeAny:
+		return fmt.Errorf("interface %s is not eligible for user peers", id)
+	}
+	return nil
+}
+
+// endregion state
+
+// region users
+
+func (m Manager) prepareUser(
+	s *state,
+	rec record[domain.BulkUser],
+	seen map[domain.UserIdentifier]struct{},
+) *importRow {
+	in := rec.Value
+	row := &importRow{report: domain.BulkImportRow{Row: rec.Row, Identifier: string(in.Identifier)}}
+	if rec.Err != nil {
+		return row.invalid(rec.Err.Error())
+	}
+	if in.Identifier == "" {
+		return row.invalid("missing identifier")
+	}
+	if _, ok := seen[in.Identifier]; ok {
+		return row.invalid(fmt.Sprintf("duplicate identifier %s", in.Identifier))
+	}
+	seen[in.Identifier] = struct{}{}
+
+	if in.Source == "" {
+		in.Source = domain.UserSourceDatabase
+	}
+	switch in.Source {
+	case domain.UserSourceDatabase, domain.UserSourceLdap, domain.UserSourceOauth:
+	default:
+		row.invalid(fmt.Sprintf("invalid source %s", in.Source))
+	}
+	if in.Password != "" && in.Source != domain.UserSourceDatabase {
+		row.invalid(fmt.Sprintf("passwords are only supported for the %s source", domain.UserSourceDatabase))
+	}
+	if in.Password != "" && len(in.Password) < m.cfg.Auth.MinPasswordLength {
+		row.invalid(fmt.Sprintf("password is too short, minimum length is %d", m.cfg.Auth.MinPasswordLength))
+	}
+
+	existing, exists := s.users[in.Identifier]
+	user := &domain.User{}
+	if exists {
+		*user = *existing
+	}
+	user.Identifier = in.Identifier
+	user.Email = in.Email
+	user.Source = in.Source
+	user.Firstname = in.Firstname
+	user.Lastname = in.Lastname
+	user.Phone = in.Phone
+	user.Department = in.Department
+	user.Notes = in.Notes
+	user.IsAdmin = in.IsAdmin
+	user.Password = domain.PrivateString(in.Password)
+	switch {
+	case in.Disabled && !user.IsDisabled():
+		now := time.Now()
+		user.Disabled = &now
+		user.DisabledReason = domain.DisabledReasonAdmin
+	case !in.Disabled:
+		user.Disabled = nil
+		user.DisabledReason = ""
+	}
+
+	if exists {
+		if existing.Source != user.Source {
+			row.invalid(fmt.Sprintf("cannot change the source of user %s from %s to %s",
+				user.Identifier, existing.Source, user.Source))
+		}
+		if err := existing.EditAllowed(user); err != nil {
+			row.invalid(err.Error())
+		}
+	} else if user.Source == domain.UserSourceDatabase && user.Password == "" {
+		row.invalid("missing password")
+	}
+
+	var interfaces []domain.InterfaceIdentifier
+	for _, id := range in.Interfaces {
+		if err := s.checkUserInterface(id); err != nil {
+			row.invalid(err.Error())
+			continue
+		}
+		if !slices.Contains(interfaces, id) {
+			interfaces = append(interfaces, id)
+		}
+	}
+
+	row.apply = func(ctx context.Context, undo *undoStack) (domain.BulkRowStatus, error) {
+		status := domain.BulkRowCreated
+		if exists {
+			status = domain.BulkRowUpdated
+			previous := *existing
+			if _, err := m.users.UpdateUser(ctx, user); err != nil {
+				return "", fmt.Errorf("failed to update user %s: %w", user.Identifier, err)
+			}
+			undo.push(func(ctx context.Context) error {
+				_, err := m.users.UpdateUser(ctx, &previous)
+				return err
+			})
+		} else {
+			if _, err := m.users.CreateUser(ctx, user); err != nil {
+				return "", fmt.Errorf("failed to create user %s: %w", user.Identifier, err)
+			}
+			undo.push(func(ctx context.Context) error {
+				return m.users.DeleteUser(ctx, user.Identifier)
+			})
+		}
+
+		if len(interfaces) == 0 {
+			return status, nil
+		}
+
+		userPeers, err := m.wg.GetUserPeers(ctx, user.Identifier)
+		if err != nil {
+			return "", fmt.Errorf("failed to load peers of user %s: %w", user.Identifier, err)
+		}
+		for _, id := range interfaces {
+			if slices.ContainsFunc(userPeers, func(p domain.Peer) bool { return p.InterfaceIdentifier == id }) {
+				continue // the user already has a peer on this interface
+			}
+			peer, err := m.wg.CreateUserPeerOnInterface(ctx, user.Identifier, id)
+			if err != nil {
+				return "", fmt.Errorf("failed to create peer on interface %s: %w", id, err)
+			}
+			row.report.Peers = append(row.report.Peers, peer.Identifier)
+			undo.push(func(ctx context.Context) error {
+				return m.wg.DeletePeer(ctx, peer.Identifier)
+			})
+		}
+
+		return status, nil
+	}
+
+	return row
+}
+
+// endregion users
+
+// region peers
+
+func (m Manager) preparePeer(
+	s *state,
+	rec record[domain.BulkPeer],
+	seen map[domain.PeerIdentifier]struct{},
+) *importRow {
+	in := rec.Value
+	row := &importRow{report: domain.BulkImportRow{Row: rec.Row, Identifier: string(in.Identifier)}}
+	if rec.Err != nil {
+		return row.invalid(rec.Err.Error())
+	}
+
+	if in.UserIdentifier != "" {
+		if _, ok := s.users[in.UserIdentifier]; !ok {
+			row.invalid(fmt.Sprintf("user %s not found", in.UserIdentifier))
+		}
+	}
+
+	if in.Identifier == "" {
+		if in.UserIdentifier == "" {
+			row.invalid("missing user, new peers are created for a user")
+		}
+		if in.InterfaceIdentifier == "" {
+			row.invalid("missing interface")
+		} else if err := s.checkUserInterface(in.InterfaceIdentifier); err != nil {
+			row.invalid(err.Error())
+		}
+
+		row.apply = func(ctx context.Context, undo *undoStack) (domain.BulkRowStatus, error) {
+			peer, err := m.wg.CreateUserPeerOnInterface(ctx, in.UserIdentifier, in.InterfaceIdentifier)
+			if err != nil {
+				return "", fmt.Errorf("failed to create peer on interface %s: %w", in.InterfaceIdentifier, err)
+			}
+			row.report.Identifier = string(peer.Identifier)
+			row.report.Peers = append(row.report.Peers, peer.Identifier)
+			undo.push(func(ctx context.Context) error {
+				return m.wg.DeletePeer(ctx, peer.Identifier)
+			})
+
+			applyBulkPeer(peer, in)
+			if _, err := m.wg.UpdatePeer(ctx, peer); err != nil {
+				return "", fmt.Errorf("failed to update peer %s: %w", peer.Identifier, err)
+			}
+			return domain.BulkRowCreated, nil
+		}
+		return row
+	}
+
+	if _, ok := seen[in.Identifier]; ok {
+		return row.invalid(fmt.Sprintf("duplicate identifier %s", in.Identifier))
+	}
+	seen[in.Identifier] = struct{}{}
+
+	existing, ok := s.peers[in.Identifier]
+	if !ok {
+		return row.invalid(fmt.Sprintf("peer %s not found", in.Identifier))
+	}
+	if in.InterfaceIdentifier != "" && in.InterfaceIdentifier != existing.InterfaceIdentifier {
+		row.invalid(fmt.Sprintf("cannot move peer from interface %s to %s",
+			existing.InterfaceIdentifier, in.InterfaceIdentifier))
+	}
+
+	row.apply = func(ctx context.Context, undo *undoStack) (domain.BulkRowStatus, error) {
+		peer, err := m.wg.GetPeer(ctx, in.Identifier)
+		if err != nil {
+			return "", fmt.Errorf("failed to load peer %s: %w", in.Identifier, err)
+		}
+		previous := *peer
+
+		applyBulkPeer(peer, in)
+		if in.UserIdentifier != "" {
+			peer.UserIdentifier = in.UserIdentifier
+		}
+		if _, err := m.wg.UpdatePeer(ctx, peer); err != nil {
+			return "", fmt.Errorf("failed to update peer %s: %w", peer.Identifier, err)
+		}
+		undo.push(func(ctx context.Context) error {
+			_, err := m.wg.UpdatePeer(ctx, &previous)
+			return err
+		})
+		return domain.BulkRowUpdated, nil
+	}
+
+	return row
+}
+
+// applyBulkPeer copies the editable fields of an import record to the peer. An empty display name keeps the
+// current name.
+func applyBulkPeer(peer *domain.Peer, in domain.BulkPeer) {
+	if in.DisplayName != "" {
+		peer.DisplayName = in.DisplayName
+	}
+	peer.Notes = in.Notes
+	peer.ExpiresAt = in.ExpiresAt
+	switch {
+	case in.Disabled && !peer.IsDisabled():
+		now := time.Now()
+		peer.Disabled = &now
+		peer.DisabledReason = domain.DisabledReasonAdmin
+	case !in.Disabled:
+		peer.Disabled = nil
+		peer.DisabledReason = ""
+	}
+}
+
+// endregion peers
+
+// region import
+
+// importRow is a validated row of an import file. Rows without errors are applied by calling apply.
+type importRow struct {
+	report domain.BulkImportRow
+	apply  func(ctx context.Context, undo *undoStack) (domain.BulkRowStatus, error)
+	undo   undoStack
+}
+
+func (r *importRow) invalid(msg string) *importRow {
+	r.report.Status = domain.BulkRowInvalid
+	r.report.Errors = append(r.report.Errors, msg)
+	return r
+}
+
+// undoStack collects the functions that revert the changes of a row.
+type undoStack []func(ctx context.Context) error
+
+func (u *undoStack) push(fn func(ctx context.Context) error) {
+	*u = append(*u, fn)
+}
+
+// run reverts all changes in reverse order and returns the errors of all failed steps.
+func (u *undoStack) run(ctx context.Context) error {
+	var errs []error
+	for i := len(*u) - 1; i >= 0; i-- {
+		if err := (*u)[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	*u = nil
+	return errors.Join(errs...)
+}
+
+// runImport applies the valid rows. In atomic mode, no row is applied if any row is invalid, and all applied rows
+// are reverted as soon as a row fails. In best-effort mode, only the changes of the failed row are reverted.
+func (m Manager) runImport(
+	ctx context.Context,
+	mode domain.BulkImportMode,
+	dryRun bool,
+	rows []*importRow,
+) *domain.BulkImportReport {
+	report := &domain.BulkImportReport{
+		Mode:   mode,
+		DryRun: dryRun,
+		Rows:   make([]domain.BulkImportRow, len(rows)),
+	}
+
+	hasInvalid := slices.ContainsFunc(rows, func(r *importRow) bool {
+		return r.report.Status == domain.BulkRowInvalid
+	})
+	skipAll := dryRun || (mode == domain.BulkImportModeAtomic && hasInvalid)
+
+	var applied []*importRow
+	failed := false
+	for _, row := range rows {
+		switch {
+		case row.report.Status == domain.BulkRowInvalid:
+			continue
+		case dryRun:
+			row.report.Status = domain.BulkRowValid
+			continue
+		case skipAll || failed:
+			row.report.Status = domain.BulkRowSkipped
+			continue
+		}
+
+		status, err := row.apply(ctx, &row.undo)
+		if err == nil {
+			row.report.Status = status
+			applied = append(applied, row)
+			continue
+		}
+
+		row.report.Status = domain.BulkRowFailed
+		row.report.Errors = append(row.report.Errors, err.Error())
+		row.report.Peers = nil
+		m.revert(ctx, row)
+
+		if mode == domain.BulkImportModeAtomic {
+			failed = true
+			for i := len(applied) - 1; i >= 0; i-- {
+				applied[i].report.Status = domain.BulkRowRolledBack
+				applied[i].report.Peers = nil
+				m.revert(ctx, applied[i])
+			}
+			applied = nil
+		}
+	}
+
+	for i, row := range rows {
+		report.Rows[i] = row.report
+	}
+	report.Committed = len(applied) > 0
+
+	return report
+}
+
+// revert undoes the changes of a row, errors are logged and added to the row report.
+func (m Manager) revert(ctx context.Context, row *importRow) {
+	if err := row.undo.run(ctx); err != nil {
+		slog.Error("failed to revert bulk import row", "row", row.report.Row, "identifier", row.report.Identifier,
+			"error", err)
+		row.report.Errors = append(row.report.Errors, fmt.Sprintf("failed to revert changes: %v", err))
+	}
+}
+
+// endregion import
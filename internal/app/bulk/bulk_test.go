@@ -0,0 +1,328 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// mockBackend implements the user and WireGuard manager on top of in-memory maps.
+type mockBackend struct {
+	users      map[domain.UserIdentifier]*domain.User
+	interfaces []domain.Interface
+	peers      map[domain.PeerIdentifier]*domain.Peer
+	peerCount  int
+
+	failPeerOn domain.InterfaceIdentifier // creating a peer on this interface fails
+}
+
+func newMockBackend() *mockBackend {
+	return &mockBackend{
+		users: map[domain.UserIdentifier]*domain.User{
+			"alice": {Identifier: "alice", Email: "alice@example.com", Source: domain.UserSourceDatabase,
+				Password: "hashed"},
+			"ldap-user": {Identifier: "ldap-user", Email: "ldap@example.com", Source: domain.UserSourceLdap},
+		},
+		interfaces: []domain.Interface{
+			{Identifier: "wg0", Type: domain.InterfaceTypeServer},
+			{Identifier: "wg1", Type: domain.InterfaceTypeServer},
+			{Identifier: "wg-client", Type: domain.InterfaceTypeClient},
+		},
+		peers: map[domain.PeerIdentifier]*domain.Peer{
+			"alice-key": {Identifier: "alice-key", InterfaceIdentifier: "wg0", UserIdentifier: "alice",
+				DisplayName: "alice laptop"},
+		},
+	}
+}
+
+func (f *mockBackend) GetAllUsers(_ context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, len(f.users))
+	for _, user := range f.users {
+		users = append(users, *user)
+	}
+	return users, nil
+}
+func (f *mockBackend) CreateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	if _, ok := f.users[user.Identifier]; ok {
+		return nil, domain.ErrDuplicateEntry
+	}
+	stored := *user
+	f.users[user.Identifier] = &stored
+	return user, nil
+}
+func (f *mockBackend) UpdateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	existing, ok := f.users[user.Identifier]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	stored := *user
+	if stored.Password == "" {
+		stored.Password = existing.Password
+	}
+	f.users[user.Identifier] = &stored
+	return user, nil
+}
+func (f *mockBackend) DeleteUser(_ context.Context, id domain.UserIdentifier) error {
+	delete(f.users, id)
+	return nil
+}
+func (f *mockBackend) GetAllInterfacesAndPeers(_ context.Context) ([]domain.Interface, [][]domain.Peer, error) {
+	peers := make([][]domain.Peer, len(f.interfaces))
+	for i, iface := range f.interfaces {
+		for _, peer := range f.peers {
+			if peer.InterfaceIdentifier == iface.Identifier {
+				peers[i] = append(peers[i], *peer)
+			}
+		}
+	}
+	return f.interfaces, peers, nil
+}
+func (f *mockBackend) GetUserPeers(_ context.Context, id domain.UserIdentifier) ([]domain.Peer, error) {
+	var peers []domain.Peer
+	for _, peer := range f.peers {
+		if peer.UserIdentifier == id {
+			peers = append(peers, *peer)
+		}
+	}
+	return peers, nil
+}
+func (f *mockBackend) GetPeer(_ context.Context, id domain.PeerIdentifier) (*domain.Peer, error) {
+	if peer, ok := f.peers[id]; ok {
+		copied := *peer
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+func (f *mockBackend) CreateUserPeerOnInterface(
+	_ context.Context,
+	userId domain.UserIdentifier,
+	interfaceId domain.InterfaceIdentifier,
+) (*domain.Peer, error) {
+	if interfaceId == f.failPeerOn {
+		return nil, errors.New("no free ip address")
+	}
+	f.peerCount++
+	peer := &domain.Peer{
+		Identifier:          domain.PeerIdentifier(fmt.Sprintf("new-key-%d", f.peerCount)),
+		InterfaceIdentifier: interfaceId,
+		UserIdentifier:      userId,
+		DisplayName:         "generated",
+	}
+	stored := *peer
+	f.peers[peer.Identifier] = &stored
+	return peer, nil
+}
+func (f *mockBackend) UpdatePeer(_ context.Context, peer *domain.Peer) (*domain.Peer, error) {
+	if _, ok := f.peers[peer.Identifier]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	stored := *peer
+	f.peers[peer.Identifier] = &stored
+	return peer, nil
+}
+func (f *mockBackend) DeletePeer(_ context.Context, id domain.PeerIdentifier) error {
+	delete(f.peers, id)
+	return nil
+}
+
+func newTestManager(backend *mockBackend) *Manager {
+	cfg := &config.Config{}
+	cfg.Auth.MinPasswordLength = 8
+	return NewManager(cfg, backend, backend)
+}
+
+func adminContext() context.Context {
+	return domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+}
+
+func rowStatuses(report *domain.BulkImportReport) []domain.BulkRowStatus {
+	statuses := make([]domain.BulkRowStatus, len(report.Rows))
+	for i, row := range report.Rows {
+		statuses[i] = row.Status
+	}
+	return statuses
+}
+
+func TestManager_ExportUsers(t *testing.T) {
+	m := newTestManager(newMockBackend())
+
+	var buf bytes.Buffer
+	require.NoError(t, m.ExportUsers(adminContext(), &buf, domain.BulkFormatCsv))
+
+	records, err := readUsers(&buf, domain.BulkFormatCsv)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for _, rec := range records {
+		if rec.Value.Identifier == "alice" {
+			assert.Equal(t, []domain.InterfaceIdentifier{"wg0"}, rec.Value.Interfaces)
+			assert.Empty(t, rec.Value.Password)
+		}
+	}
+}
+
+func TestManager_ImportUsers(t *testing.T) {
+	backend := newMockBackend()
+	m := newTestManager(backend)
+
+	input := "identifier,email,firstname,password,interfaces\n" +
+		"alice,alice@example.org,Alice,,\"wg0,wg1\"\n" +
+		"bob,bob@example.com,Bob,long-password,wg0\n"
+
+	report, err := m.ImportUsers(adminContext(), strings.NewReader(input), domain.BulkFormatCsv,
+		domain.BulkImportModeAtomic, false)
+	require.NoError(t, err)
+	assert.False(t, report.HasErrors())
+	assert.True(t, report.Committed)
+	assert.Equal(t, []domain.BulkRowStatus{domain.BulkRowUpdated, domain.BulkRowCreated}, rowStatuses(report))
+
+	assert.Equal(t, "alice@example.org", backend.users["alice"].Email)
+	assert.Equal(t, domain.PrivateString("hashed"), backend.users["alice"].Password, "password is kept")
+	assert.Equal(t, domain.UserSourceDatabase, backend.users["bob"].Source)
+	// alice already has a peer on wg0, only the wg1 peer is created
+	require.Len(t, report.Rows[0].Peers, 1)
+	assert.Equal(t, domain.InterfaceIdentifier("wg1"), backend.peers[report.Rows[0].Peers[0]].InterfaceIdentifier)
+	require.Len(t, report.Rows[1].Peers, 1)
+}
+
+func TestManager_ImportUsersValidation(t *testing.T) {
+	backend := newMockBackend()
+	m := newTestManager(backend)
+
+	input := `[
+		{"Identifier": "new-user"},
+		{"Identifier": "short", "Password": "short"},
+		{"Identifier": "ldap-user", "Email": "changed@example.com", "Source": "ldap"},
+		{"Identifier": "alice", "Source": "oauth"},
+		{"Identifier": "carol", "Password": "long-password", "Interfaces": ["wg-client", "missing"]},
+		{"Identifier": "carol", "Password": "long-password"},
+		{"Identifier": "dave", "Password": "long-password"}
+	]`
+
+	report, err := m.ImportUsers(adminContext(), strings.NewReader(input), domain.BulkFormatJson,
+		domain.BulkImportModeAtomic, false)
+	require.NoError(t, err)
+	assert.True(t, report.HasErrors())
+	assert.False(t, report.Committed)
+	assert.Equal(t, []domain.BulkRowStatus{
+		domain.BulkRowInvalid, domain.BulkRowInvalid, domain.BulkRowInvalid, domain.BulkRowInvalid,
+		domain.BulkRowInvalid, domain.BulkRowInvalid, domain.BulkRowSkipped,
+	}, rowStatuses(report))
+	assert.Contains(t, report.Rows[0].Errors, "missing password")
+	assert.Len(t, report.Rows[4].Errors, 2)
+	assert.NotContains(t, backend.users, domain.UserIdentifier("dave"), "atomic imports apply nothing")
+}
+
+func TestManager_ImportUsersDryRun(t *testing.T) {
+	backend := newMockBackend()
+	m := newTestManager(backend)
+
+	input := "identifier,password\nbob,long-password\n,\n"
+
+	report, err := m.ImportUsers(adminContext(), strings.NewReader(input), domain.BulkFormatCsv,
+		domain.BulkImportModeBestEffort, true)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkRowStatus{domain.BulkRowValid, domain.BulkRowInvalid}, rowStatuses(report))
+	assert.False(t, report.Committed)
+	assert.NotContains(t, backend.users, domain.UserIdentifier("bob"))
+}
+
+func TestManager_ImportUsersAtomicRollback(t *testing.T) {
+	backend := newMockBackend()
+	backend.failPeerOn = "wg1"
+	m := newTestManager(backend)
+
+	input := "identifier,email,password,interfaces\n" +
+		"alice,alice@example.org,,\n" +
+		"bob,,long-password,wg0\n" +
+		"carol,,long-password,wg1\n" +
+		"dave,,long-password,\n"
+
+	report, err := m.ImportUsers(adminContext(), strings.NewReader(input), domain.BulkFormatCsv,
+		domain.BulkImportModeAtomic, false)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkRowStatus{
+		domain.BulkRowRolledBack, domain.BulkRowRolledBack, domain.BulkRowFailed, domain.BulkRowSkipped,
+	}, rowStatuses(report))
+	assert.False(t, report.Committed)
+
+	assert.Equal(t, "alice@example.com", backend.users["alice"].Email)
+	assert.Equal(t, domain.PrivateString("hashed"), backend.users["alice"].Password)
+	assert.NotContains(t, backend.users, domain.UserIdentifier("bob"))
+	assert.NotContains(t, backend.users, domain.UserIdentifier("carol"))
+	assert.Len(t, backend.peers, 1)
+}
+
+func TestManager_ImportUsersBestEffort(t *testing.T) {
+	backend := newMockBackend()
+	backend.failPeerOn = "wg1"
+	m := newTestManager(backend)
+
+	input := "identifier,password,interfaces\n" +
+		"bob,long-password,wg0\n" +
+		"carol,long-password,wg1\n" +
+		"dave,short,\n" +
+		"erin,long-password,\n"
+
+	report, err := m.ImportUsers(adminContext(), strings.NewReader(input), domain.BulkFormatCsv,
+		domain.BulkImportModeBestEffort, false)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkRowStatus{
+		domain.BulkRowCreated, domain.BulkRowFailed, domain.BulkRowInvalid, domain.BulkRowCreated,
+	}, rowStatuses(report))
+	assert.True(t, report.Committed)
+
+	assert.Contains(t, backend.users, domain.UserIdentifier("bob"))
+	assert.NotContains(t, backend.users, domain.UserIdentifier("carol"))
+	assert.Contains(t, backend.users, domain.UserIdentifier("erin"))
+}
+
+func TestManager_ImportPeers(t *testing.T) {
+	backend := newMockBackend()
+	m := newTestManager(backend)
+
+	input := "identifier,interface,user,display_name,notes,disabled,expires_at\n" +
+		"alice-key,wg0,,renamed,some notes,true,2030-01-01\n" +
+		",wg1,alice,phone,,,\n" +
+		",wg0,,orphan,,,\n" +
+		"unknown-key,,,,,,\n" +
+		"alice-key,wg1,,,,,\n"
+
+	report, err := m.ImportPeers(adminContext(), strings.NewReader(input), domain.BulkFormatCsv,
+		domain.BulkImportModeBestEffort, false)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BulkRowStatus{
+		domain.BulkRowUpdated, domain.BulkRowCreated, domain.BulkRowInvalid, domain.BulkRowInvalid,
+		domain.BulkRowInvalid,
+	}, rowStatuses(report))
+
+	updated := backend.peers["alice-key"]
+	assert.Equal(t, "renamed", updated.DisplayName)
+	assert.Equal(t, "some notes", updated.Notes)
+	assert.True(t, updated.IsDisabled())
+	require.NotNil(t, updated.ExpiresAt)
+
+	require.Len(t, report.Rows[1].Peers, 1)
+	created := backend.peers[report.Rows[1].Peers[0]]
+	assert.Equal(t, "phone", created.DisplayName)
+	assert.Equal(t, string(created.Identifier), report.Rows[1].Identifier)
+}
+
+func TestManager_ImportRequiresAdmin(t *testing.T) {
+	m := newTestManager(newMockBackend())
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "alice"})
+
+	_, err := m.ImportUsers(ctx, strings.NewReader("[]"), domain.BulkFormatJson, domain.BulkImportModeAtomic, true)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+
+	_, err = m.ImportPeers(adminContext(), strings.NewReader("[]"), "xml", domain.BulkImportModeAtomic, true)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+}
@@ -0,0 +1,225 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// csv column names, the order is used for exports
+var (
+	userColumns = []string{"identifier", "email", "source", "firstname", "lastname", "phone", "department", "notes",
+		"is_admin", "disabled", "password", "interfaces"}
+	peerColumns = []string{"identifier", "interface", "user", "display_name", "notes", "addresses", "disabled",
+		"expires_at"}
+)
+
+// record is a decoded row of an import file. Rows that cannot be decoded are reported as invalid, the remaining rows
+// are still processed.
+type record[T any] struct {
+	Row   int // the row number, starting at 1 for the first record
+	Value T
+	Err   error
+}
+
+func writeUsers(w io.Writer, format domain.BulkFormat, users []domain.BulkUser) error {
+	if format == domain.BulkFormatJson {
+		return writeJson(w, users)
+	}
+
+	columns := slices.DeleteFunc(slices.Clone(userColumns), func(c string) bool { return c == "password" })
+	records := make([][]string, len(users))
+	for i, user := range users {
+		interfaces := make([]string, len(user.Interfaces))
+		for j, iface := range user.Interfaces {
+			interfaces[j] = string(iface)
+		}
+		records[i] = []string{string(user.Identifier), user.Email, string(user.Source), user.Firstname,
+			user.Lastname, user.Phone, user.Department, user.Notes, strconv.FormatBool(user.IsAdmin),
+			strconv.FormatBool(user.Disabled), strings.Join(interfaces, ",")}
+	}
+	return writeCsv(w, columns, records)
+}
+
+func writePeers(w io.Writer, format domain.BulkFormat, peers []domain.BulkPeer) error {
+	if format == domain.BulkFormatJson {
+		return writeJson(w, peers)
+	}
+
+	records := make([][]string, len(peers))
+	for i, peer := range peers {
+		expiresAt := ""
+		if peer.ExpiresAt != nil {
+			expiresAt = peer.ExpiresAt.Format(time.RFC3339)
+		}
+		records[i] = []string{string(peer.Identifier), string(peer.InterfaceIdentifier), string(peer.UserIdentifier),
+			peer.DisplayName, peer.Notes, peer.Addresses, strconv.FormatBool(peer.Disabled), expiresAt}
+	}
+	return writeCsv(w, peerColumns, records)
+}
+
+func readUsers(r io.Reader, format domain.BulkFormat) ([]record[domain.BulkUser], error) {
+	if format == domain.BulkFormatJson {
+		return readJson[domain.BulkUser](r)
+	}
+
+	return readCsv(r, userColumns, func(values map[string]string) (domain.BulkUser, error) {
+		user := domain.BulkUser{
+			Identifier: domain.UserIdentifier(values["identifier"]),
+			Email:      values["email"],
+			Source:     domain.UserSource(values["source"]),
+			Firstname:  values["firstname"],
+			Lastname:   values["lastname"],
+			Phone:      values["phone"],
+			Department: values["department"],
+			Notes:      values["notes"],
+			Password:   values["password"],
+		}
+		var err error
+		if user.IsAdmin, err = parseBool(values["is_admin"]); err != nil {
+			return user, fmt.Errorf("invalid is_admin value: %w", err)
+		}
+		if user.Disabled, err = parseBool(values["disabled"]); err != nil {
+			return user, fmt.Errorf("invalid disabled value: %w", err)
+		}
+		for _, iface := range strings.Split(values["interfaces"], ",") {
+			if iface = strings.TrimSpace(iface); iface != "" {
+				user.Interfaces = append(user.Interfaces, domain.InterfaceIdentifier(iface))
+			}
+		}
+		return user, nil
+	})
+}
+
+func readPeers(r io.Reader, format domain.BulkFormat) ([]record[domain.BulkPeer], error) {
+	if format == domain.BulkFormatJson {
+		return readJson[domain.BulkPeer](r)
+	}
+
+	return readCsv(r, peerColumns, func(values map[string]string) (domain.BulkPeer, error) {
+		peer := domain.BulkPeer{
+			Identifier:          domain.PeerIdentifier(values["identifier"]),
+			InterfaceIdentifier: domain.InterfaceIdentifier(values["interface"]),
+			UserIdentifier:      domain.UserIdentifier(values["user"]),
+			DisplayName:         values["display_name"],
+			Notes:               values["notes"],
+			Addresses:           values["addresses"],
+		}
+		var err error
+		if peer.Disabled, err = parseBool(values["disabled"]); err != nil {
+			return peer, fmt.Errorf("invalid disabled value: %w", err)
+		}
+		if peer.ExpiresAt, err = parseTime(values["expires_at"]); err != nil {
+			return peer, fmt.Errorf("invalid expires_at value: %w", err)
+		}
+		return peer, nil
+	})
+}
+
+// readJson reads a json array. Each element is a row.
+func readJson[T any](r io.Reader) ([]record[T], error) {
+	var values []T
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return nil, fmt.Errorf("invalid json: %w: %w", err, domain.ErrInvalidData)
+	}
+
+	records := make([]record[T], len(values))
+	for i, value := range values {
+		records[i] = record[T]{Row: i + 1, Value: value}
+	}
+	return records, nil
+}
+
+func writeJson(w io.Writer, records any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func writeCsv(w io.Writer, columns []string, records [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// readCsv reads a csv file with a header row. Columns may be given in any order, and missing columns are empty.
+// Rows that cannot be parsed are returned with an error, so that the remaining rows can still be processed.
+func readCsv[T any](
+	r io.Reader,
+	knownColumns []string,
+	parseRow func(values map[string]string) (T, error),
+) ([]record[T], error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // the number of fields is checked per row
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w: %w", err, domain.ErrInvalidData)
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\uFEFF")))
+		if !slices.Contains(knownColumns, header[i]) {
+			return nil, fmt.Errorf("unknown csv column %q: %w", column, domain.ErrInvalidData)
+		}
+	}
+	if !slices.Contains(header, "identifier") {
+		return nil, fmt.Errorf("missing csv column identifier: %w", domain.ErrInvalidData)
+	}
+
+	var records []record[T]
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv data in row %d: %w: %w", row, err, domain.ErrInvalidData)
+		}
+		if len(fields) != len(header) {
+			records = append(records, record[T]{Row: row,
+				Err: fmt.Errorf("expected %d fields, got %d", len(header), len(fields))})
+			continue
+		}
+
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[column] = strings.TrimSpace(fields[i])
+		}
+		value, err := parseRow(values)
+		records = append(records, record[T]{Row: row, Value: value, Err: err})
+	}
+
+	return records, nil
+}
+
+func parseBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is neither a RFC 3339 timestamp nor a date", value)
+}
@@ -0,0 +1,88 @@
+package bulk
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestUsersCsvRoundTrip(t *testing.T) {
+	users := []domain.BulkUser{
+		{Identifier: "alice", Email: "alice@example.com", Source: domain.UserSourceDatabase, Firstname: "Alice",
+			Notes: "first, with comma", IsAdmin: true, Interfaces: []domain.InterfaceIdentifier{"wg0", "wg1"}},
+		{Identifier: "bob", Source: domain.UserSourceLdap, Disabled: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeUsers(&buf, domain.BulkFormatCsv, users))
+	assert.NotContains(t, buf.String(), "password")
+
+	records, err := readUsers(&buf, domain.BulkFormatCsv)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	for i, rec := range records {
+		require.NoError(t, rec.Err)
+		assert.Equal(t, i+1, rec.Row)
+		assert.Equal(t, users[i], rec.Value)
+	}
+}
+
+func TestReadUsersCsvReportsRowErrors(t *testing.T) {
+	input := "\uFEFFIdentifier, is_admin, password\n" +
+		"alice,yes,secret\n" +
+		"bob,true\n" +
+		"carol,,\n"
+
+	records, err := readUsers(strings.NewReader(input), domain.BulkFormatCsv)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.ErrorContains(t, records[0].Err, "invalid is_admin value")
+	assert.ErrorContains(t, records[1].Err, "expected 3 fields, got 2")
+	require.NoError(t, records[2].Err)
+	assert.Equal(t, 3, records[2].Row)
+	assert.Equal(t, domain.UserIdentifier("carol"), records[2].Value.Identifier)
+}
+
+func TestReadCsvInvalidHeader(t *testing.T) {
+	_, err := readUsers(strings.NewReader("identifier,unknown\nalice,x\n"), domain.BulkFormatCsv)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+
+	_, err = readPeers(strings.NewReader("user,interface\nalice,wg0\n"), domain.BulkFormatCsv)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+}
+
+func TestPeersJsonRoundTrip(t *testing.T) {
+	expires := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	peers := []domain.BulkPeer{
+		{Identifier: "key1", InterfaceIdentifier: "wg0", UserIdentifier: "alice", DisplayName: "laptop",
+			Addresses: "10.0.0.2/32", ExpiresAt: &expires},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writePeers(&buf, domain.BulkFormatJson, peers))
+
+	records, err := readPeers(&buf, domain.BulkFormatJson)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, 1, records[0].Row)
+	assert.Equal(t, peers[0], records[0].Value)
+}
+
+func TestReadPeersCsvDates(t *testing.T) {
+	input := "identifier,expires_at\nkey1,2030-01-02\nkey2,2030-01-02T10:00:00Z\nkey3,tomorrow\n"
+
+	records, err := readPeers(strings.NewReader(input), domain.BulkFormatCsv)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.NotNil(t, records[0].Value.ExpiresAt)
+	assert.Equal(t, time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC), *records[0].Value.ExpiresAt)
+	require.NotNil(t, records[1].Value.ExpiresAt)
+	assert.ErrorContains(t, records[2].Err, "invalid expires_at value")
+}
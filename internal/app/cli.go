@@ -1,24 +1,492 @@
 package app
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
 	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
 )
 
+// ImportArgs contains the program arguments of an import from another WireGuard management tool.
+type ImportArgs struct {
+	Request domain.MigrationRequest
+	Apply   bool // if false, only the dry-run summary is printed
+}
+
+// Importer imports users, interfaces and peers from other WireGuard management tools.
+type Importer interface {
+	Import(ctx context.Context, req *domain.MigrationRequest, dryRun bool) (*domain.MigrationPlan, error)
+}
+
+// BulkArgs contains the program arguments of a bulk import or export of users or peers.
+type BulkArgs struct {
+	Peers  bool // if false, users are imported or exported
+	Export bool // if false, the file is imported
+	Path   string
+	Format domain.BulkFormat
+	Mode   domain.BulkImportMode
+	DryRun bool
+}
+
+// BulkManager imports and exports users and peers as CSV or JSON files.
+type BulkManager interface {
+	ExportUsers(ctx context.Context, w io.Writer, format domain.BulkFormat) error
+	ExportPeers(ctx context.Context, w io.Writer, format domain.BulkFormat) error
+	ImportUsers(ctx context.Context, r io.Reader, format domain.BulkFormat, mode domain.BulkImportMode, dryRun bool) (
+		*domain.BulkImportReport, error)
+	ImportPeers(ctx context.Context, r io.Reader, format domain.BulkFormat, mode domain.BulkImportMode, dryRun bool) (
+		*domain.BulkImportReport, error)
+}
+
+// BackupArgs contains the arguments of the backup and restore subcommands.
+type BackupArgs struct {
+	Restore    bool // if false, a backup is created
+	Path       string
+	Passphrase string
+	DryRun     bool // only used for restores
+}
+
+// BackupManager creates and restores encrypted backups of all portal data.
+type BackupManager interface {
+	Backup(ctx context.Context, w io.Writer, passphrase string) (*domain.BackupInfo, error)
+	Restore(ctx context.Context, r io.Reader, passphrase string, dryRun bool) (*domain.BackupInfo, error)
+}
+
+// ProvisionArgs contains the arguments of the provision subcommand.
+type ProvisionArgs struct {
+	Apply bool // if false, only the plan is printed
+}
+
+// Provisioner reconciles the provisioned users and peers with the configuration.
+type Provisioner interface {
+	Plan(ctx context.Context) (*domain.ProvisioningPlan, error)
+	Apply(ctx context.Context) (*domain.ProvisioningPlan, error)
+}
+
+// ProgramArgs contains the arguments of commands that need the fully initialized application.
+type ProgramArgs struct {
+	Import    *ImportArgs    // passed to RunImport
+	Bulk      *BulkArgs      // passed to RunBulk
+	Backup    *BackupArgs    // passed to RunBackup
+	Admin     *AdminArgs     // passed to RunAdminCommand
+	Provision *ProvisionArgs // passed to RunProvisioning
+	Audit     *AuditArgs     // passed to RunAuditCommand
+}
+
+// backupPassphraseEnv is the environment variable that contains the passphrase of backup archives.
+const backupPassphraseEnv = "WG_PORTAL_BACKUP_PASSPHRASE"
+
 // HandleProgramArgs handles program arguments and returns true if the program should exit.
-func HandleProgramArgs(db *gorm.DB) (exit bool, err error) {
+// Imports, bulk operations, backups and administrative commands need the fully initialized application, their
+// arguments are returned.
+func HandleProgramArgs(db *gorm.DB) (exit bool, args ProgramArgs, err error) {
 	migrationSource := flag.String("migrateFrom", "", "path to v1 database file or DSN")
 	migrationDbType := flag.String("migrateFromType", string(config.DatabaseSQLite),
 		"old database type, either mysql, mssql, postgres or sqlite")
+	importSource := flag.String("importFrom", "", "path to the wg-easy wg0.json file or DSN of the Firezone database")
+	importType := flag.String("importType", string(domain.MigrationSourceWgEasy),
+		"import source type, either wg-easy or firezone")
+	importInterface := flag.String("importInterface", "",
+		"identifier of the imported interface, defaults to wg0 (wg-easy) or wg-firezone (Firezone)")
+	importEndpoint := flag.String("importEndpoint", "", "public endpoint of the imported interface, host[:port]")
+	importKeyFile := flag.String("importPrivateKeyFile", "",
+		"file containing the private key of the imported interface, for example /var/firezone/private_key")
+	importUser := flag.String("importUser", "", "user that owns the imported wg-easy peers")
+	importApply := flag.Bool("importApply", false, "store the imported data, otherwise only a summary is printed")
+	exportUsers := flag.String("exportUsers", "", "export all users to the given file, - writes to stdout")
+	exportPeers := flag.String("exportPeers", "", "export all peers to the given file, - writes to stdout")
+	importUsers := flag.String("importUsers", "", "import users from the given file, - reads from stdin")
+	importPeers := flag.String("importPeers", "", "import peers from the given file, - reads from stdin")
+	bulkFormat := flag.String("bulkFormat", "",
+		"file format of bulk imports and exports, either csv or json, defaults to the file extension or csv")
+	bulkMode := flag.String("bulkMode", string(domain.BulkImportModeAtomic),
+		"bulk import mode, either atomic (all rows or none) or best-effort")
+	bulkDryRun := flag.Bool("bulkDryRun", false, "only validate the bulk import file, nothing is stored")
 	flag.Parse()
 
+	switch flag.Arg(0) {
+	case "backup", "restore":
+		args.Backup, err = parseBackupArgs(flag.Arg(0), flag.Args()[1:])
+		return err != nil, args, err
+	case "user", "peer", "interface":
+		args.Admin, err = parseAdminArgs(flag.Args())
+		return err != nil, args, err
+	case "provision":
+		args.Provision, err = parseProvisionArgs(flag.Args()[1:])
+		return err != nil, args, err
+	case "audit":
+		args.Audit, err = parseAuditArgs(flag.Args()[1:])
+		return err != nil, args, err
+	case "config":
+		return true, args, fmt.Errorf("the config command must be the first argument")
+	case "":
+	default:
+		return true, args, fmt.Errorf("unknown command %q, supported commands are config, backup, restore, user, "+
+			"peer, interface, provision and audit", flag.Arg(0))
+	}
+
 	if *migrationSource != "" {
 		err = migrateFromV1(db, *migrationSource, *migrationDbType)
 		exit = true
+		return
+	}
+
+	if *importSource != "" {
+		importArgs := &ImportArgs{
+			Request: domain.MigrationRequest{
+				Source:         domain.MigrationSource(*importType),
+				Identifier:     domain.InterfaceIdentifier(*importInterface),
+				Endpoint:       *importEndpoint,
+				UserIdentifier: domain.UserIdentifier(*importUser),
+			},
+			Apply: *importApply,
+		}
+		if err = importArgs.Request.Source.Validate(); err != nil {
+			return true, args, err
+		}
+
+		if importArgs.Request.Source == domain.MigrationSourceFirezone {
+			importArgs.Request.Dsn = *importSource
+		} else if importArgs.Request.Data, err = os.ReadFile(*importSource); err != nil {
+			return true, args, fmt.Errorf("failed to read import source: %w", err)
+		}
+
+		if *importKeyFile != "" {
+			key, err := os.ReadFile(*importKeyFile)
+			if err != nil {
+				return true, args, fmt.Errorf("failed to read private key file: %w", err)
+			}
+			importArgs.Request.PrivateKey = strings.TrimSpace(string(key))
+		}
+		args.Import = importArgs
+		return
+	}
+
+	bulkArgs := &BulkArgs{
+		Mode:   domain.BulkImportMode(*bulkMode),
+		DryRun: *bulkDryRun,
+	}
+	switch {
+	case *exportUsers != "":
+		bulkArgs.Export, bulkArgs.Path = true, *exportUsers
+	case *exportPeers != "":
+		bulkArgs.Export, bulkArgs.Peers, bulkArgs.Path = true, true, *exportPeers
+	case *importUsers != "":
+		bulkArgs.Path = *importUsers
+	case *importPeers != "":
+		bulkArgs.Peers, bulkArgs.Path = true, *importPeers
+	default:
+		return
 	}
 
+	bulkArgs.Format = domain.BulkFormat(*bulkFormat)
+	if bulkArgs.Format == "" {
+		bulkArgs.Format = domain.BulkFormatCsv
+		if strings.EqualFold(filepath.Ext(bulkArgs.Path), ".json") {
+			bulkArgs.Format = domain.BulkFormatJson
+		}
+	}
+	if err = bulkArgs.Format.Validate(); err != nil {
+		return true, args, err
+	}
+	if err = bulkArgs.Mode.Validate(); err != nil {
+		return true, args, err
+	}
+	args.Bulk = bulkArgs
+
 	return
 }
+
+// parseBackupArgs parses the arguments of the backup and restore subcommands. The passphrase is read from a file or
+// from the WG_PORTAL_BACKUP_PASSPHRASE environment variable, so that it does not show up in the process list.
+func parseBackupArgs(command string, arguments []string) (*BackupArgs, error) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	path := flags.String("file", "", "path of the backup archive, - for stdout (backup) or stdin (restore)")
+	passphraseFile := flags.String("passphraseFile", "",
+		"file containing the backup passphrase, defaults to the "+backupPassphraseEnv+" environment variable")
+	var dryRun *bool
+	if command == "restore" {
+		dryRun = flags.Bool("dryRun", false, "only validate the backup archive, nothing is restored")
+	}
+	if err := flags.Parse(arguments); err != nil {
+		return nil, err
+	}
+
+	args := &BackupArgs{
+		Restore:    command == "restore",
+		Path:       *path,
+		Passphrase: os.Getenv(backupPassphraseEnv),
+	}
+	if dryRun != nil {
+		args.DryRun = *dryRun
+	}
+	if args.Path == "" {
+		return nil, fmt.Errorf("missing backup file, use -file")
+	}
+	if *passphraseFile != "" {
+		passphrase, err := os.ReadFile(*passphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		args.Passphrase = strings.TrimSpace(string(passphrase))
+	}
+	if args.Passphrase == "" {
+		return nil, fmt.Errorf("missing backup passphrase, use -passphraseFile or %s", backupPassphraseEnv)
+	}
+
+	return args, nil
+}
+
+// RunBackup creates or restores a backup as described by the program arguments and prints a summary to w.
+func RunBackup(ctx context.Context, stdin io.Reader, w io.Writer, backups BackupManager, args *BackupArgs) error {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+
+	if !args.Restore {
+		out, summary := w, w
+		if args.Path == "-" {
+			summary = io.Discard // stdout contains the archive
+		} else {
+			file, err := os.OpenFile(args.Path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to create backup file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		info, err := backups.Backup(ctx, out, args.Passphrase)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(summary, "Backup created.")
+		printBackupInfo(summary, info)
+		return nil
+	}
+
+	in := stdin
+	if args.Path != "-" {
+		file, err := os.Open(args.Path)
+		if err != nil {
+			return fmt.Errorf("failed to open backup file: %w", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	info, err := backups.Restore(ctx, in, args.Passphrase, args.DryRun)
+	if err != nil {
+		return err
+	}
+	if args.DryRun {
+		_, _ = fmt.Fprintln(w, "Backup is valid, dry run only, nothing has been restored.")
+	} else {
+		_, _ = fmt.Fprintln(w, "Backup restored.")
+	}
+	printBackupInfo(w, info)
+	return nil
+}
+
+func printBackupInfo(w io.Writer, info *domain.BackupInfo) {
+	_, _ = fmt.Fprintf(w, "Created at %s by %s, version %s, %s database, schema version %d\n",
+		info.CreatedAt.Format(time.RFC3339), info.CreatedBy, info.AppVersion, info.DatabaseType, info.SchemaVersion)
+	_, _ = fmt.Fprintf(w, "Users: %d, WebAuthn credentials: %d, API tokens: %d, webhook subscriptions: %d, "+
+		"interfaces: %d, peers: %d, audit entries: %d\n", info.Users, info.WebAuthnCredentials, info.ApiTokens,
+		info.WebhookSubscriptions, info.Interfaces, info.Peers, info.AuditEntries)
+}
+
+// parseProvisionArgs parses the arguments of the provision subcommand, either plan or apply.
+func parseProvisionArgs(arguments []string) (*ProvisionArgs, error) {
+	if len(arguments) != 1 || (arguments[0] != "plan" && arguments[0] != "apply") {
+		return nil, fmt.Errorf("usage: wg-portal provision plan|apply")
+	}
+	return &ProvisionArgs{Apply: arguments[0] == "apply"}, nil
+}
+
+// RunProvisioning prints the provisioning plan and applies it if requested. An error is returned if the
+// provisioning configuration is invalid.
+func RunProvisioning(ctx context.Context, w io.Writer, provisioner Provisioner, args *ProvisionArgs) error {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+
+	var plan *domain.ProvisioningPlan
+	var err error
+	if args.Apply {
+		plan, err = provisioner.Apply(ctx)
+	} else {
+		plan, err = provisioner.Plan(ctx)
+	}
+	if plan != nil {
+		printProvisioningPlan(w, plan)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case plan.HasErrors():
+		return fmt.Errorf("provisioning configuration has %d errors", len(plan.Errors))
+	case len(plan.Changes) == 0:
+		_, _ = fmt.Fprintln(w, "No changes, the provisioned users and peers are up to date.")
+	case plan.Applied:
+		_, _ = fmt.Fprintln(w, "Provisioning applied.")
+	default:
+		_, _ = fmt.Fprintln(w, "Plan only, use \"provision apply\" to apply the changes.")
+	}
+	return nil
+}
+
+func printProvisioningPlan(w io.Writer, plan *domain.ProvisioningPlan) {
+	_, _ = fmt.Fprintln(w, "Provisioning plan")
+	for _, change := range plan.Changes {
+		_, _ = fmt.Fprintf(w, "  %s %s %s", change.Action, change.Kind, change.Identifier)
+		if len(change.Fields) > 0 {
+			_, _ = fmt.Fprintf(w, " (%s)", strings.Join(change.Fields, ", "))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+	for _, msg := range plan.Errors {
+		_, _ = fmt.Fprintf(w, "  error: %s\n", msg)
+	}
+}
+
+// RunImport runs the import described by the program arguments and prints its summary.
+func RunImport(ctx context.Context, w io.Writer, importer Importer, args *ImportArgs) error {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+
+	plan, err := importer.Import(ctx, &args.Request, !args.Apply)
+	if err != nil {
+		return err
+	}
+
+	printMigrationPlan(w, plan)
+
+	switch {
+	case plan.HasConflicts():
+		return fmt.Errorf("import from %s has %d conflicts", plan.Source, len(plan.Conflicts))
+	case !plan.Committed:
+		_, _ = fmt.Fprintln(w, "Dry run only, use -importApply to store the imported data.")
+	}
+
+	return nil
+}
+
+// RunBulk runs the bulk import or export described by the program arguments. For imports, the per-row report is
+// printed to w, and an error is returned if any row is invalid or failed.
+func RunBulk(ctx context.Context, stdin io.Reader, w io.Writer, bulk BulkManager, args *BulkArgs) error {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+
+	if args.Export {
+		out := w
+		if args.Path != "-" {
+			file, err := os.Create(args.Path)
+			if err != nil {
+				return fmt.Errorf("failed to create export file: %w", err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if args.Peers {
+			return bulk.ExportPeers(ctx, out, args.Format)
+		}
+		return bulk.ExportUsers(ctx, out, args.Format)
+	}
+
+	in := stdin
+	if args.Path != "-" {
+		file, err := os.Open(args.Path)
+		if err != nil {
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var report *domain.BulkImportReport
+	var err error
+	if args.Peers {
+		report, err = bulk.ImportPeers(ctx, in, args.Format, args.Mode, args.DryRun)
+	} else {
+		report, err = bulk.ImportUsers(ctx, in, args.Format, args.Mode, args.DryRun)
+	}
+	if err != nil {
+		return err
+	}
+
+	printBulkImportReport(w, report)
+
+	if report.HasErrors() {
+		return fmt.Errorf("bulk import has invalid or failed rows")
+	}
+	return nil
+}
+
+func printBulkImportReport(w io.Writer, report *domain.BulkImportReport) {
+	_, _ = fmt.Fprintf(w, "Bulk import (%s mode)\n", report.Mode)
+	for _, row := range report.Rows {
+		_, _ = fmt.Fprintf(w, "  row %d %s: %s\n", row.Row, row.Identifier, row.Status)
+		for _, peer := range row.Peers {
+			_, _ = fmt.Fprintf(w, "    created peer %s\n", peer)
+		}
+		for _, msg := range row.Errors {
+			_, _ = fmt.Fprintf(w, "    error: %s\n", msg)
+		}
+	}
+	switch {
+	case report.DryRun:
+		_, _ = fmt.Fprintln(w, "Dry run only, nothing has been stored.")
+	case report.Committed:
+		_, _ = fmt.Fprintln(w, "Bulk import completed.")
+	default:
+		_, _ = fmt.Fprintln(w, "Nothing has been stored.")
+	}
+}
+
+func printMigrationPlan(w io.Writer, plan *domain.MigrationPlan) {
+	_, _ = fmt.Fprintf(w, "Import from %s\n", plan.Source)
+	_, _ = fmt.Fprintf(w, "Interface: %s (%s, port %d, public key %s)\n", plan.Interface.Identifier,
+		domain.CidrsToString(plan.Interface.Addresses), plan.Interface.ListenPort, plan.Interface.PublicKey)
+
+	_, _ = fmt.Fprintf(w, "Users: %d\n", len(plan.Users))
+	for _, user := range plan.Users {
+		state := "enabled"
+		if user.IsDisabled() {
+			state = "disabled"
+		}
+		_, _ = fmt.Fprintf(w, "  %s (admin: %t, %s)\n", user.Identifier, user.IsAdmin, state)
+	}
+
+	_, _ = fmt.Fprintf(w, "Peers: %d\n", len(plan.Peers))
+	for _, peer := range plan.Peers {
+		state := "enabled"
+		if peer.IsDisabled() {
+			state = "disabled"
+		}
+		if peer.ExpiresAt != nil {
+			state += ", expires " + peer.ExpiresAt.Format("2006-01-02")
+		}
+		owner := string(peer.UserIdentifier)
+		if owner == "" {
+			owner = "-"
+		}
+		_, _ = fmt.Fprintf(w, "  %s [%s] %s, user %s (%s)\n", peer.DisplayName, peer.Identifier,
+			domain.CidrsToString(peer.Interface.Addresses), owner, state)
+	}
+
+	for _, warning := range plan.Warnings {
+		_, _ = fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	for _, conflict := range plan.Conflicts {
+		_, _ = fmt.Fprintf(w, "Conflict: %s\n", conflict)
+	}
+	if plan.Committed {
+		_, _ = fmt.Fprintln(w, "Import completed successfully.")
+	}
+}
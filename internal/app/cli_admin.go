@@ -0,0 +1,643 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// adminCommands lists the actions of the administrative subcommands per resource.
+var adminCommands = map[string][]string{
+	"user":      {"list", "create", "disable", "enable", "reset-password", "api-token"},
+	"peer":      {"list", "create", "disable", "enable", "config", "qr"},
+	"interface": {"list", "apply", "import"},
+}
+
+// AdminArgs contains the arguments of the user, peer and interface subcommands.
+type AdminArgs struct {
+	Resource string   // user, peer or interface
+	Action   string   // for example list or create
+	Args     []string // the remaining arguments, they are parsed by RunAdminCommand
+}
+
+type AdminUserManager interface {
+	GetUser(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	ActivateApi(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
+	DeactivateApi(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
+}
+
+type AdminWireGuardManager interface {
+	GetAllInterfacesAndPeers(ctx context.Context) ([]domain.Interface, [][]domain.Peer, error)
+	GetPeer(ctx context.Context, id domain.PeerIdentifier) (*domain.Peer, error)
+	PreparePeer(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Peer, error)
+	CreatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
+	CreateUserPeerOnInterface(
+		ctx context.Context,
+		userId domain.UserIdentifier,
+		interfaceId domain.InterfaceIdentifier,
+	) (*domain.Peer, error)
+	UpdatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
+	RestoreInterfaceState(ctx context.Context, updateDbOnError bool, filter ...domain.InterfaceIdentifier) error
+	ImportNewInterfaces(ctx context.Context, filter ...domain.InterfaceIdentifier) (int, error)
+}
+
+type AdminConfigFileManager interface {
+	GetPeerConfig(ctx context.Context, id domain.PeerIdentifier, style string) (io.Reader, error)
+	GetPeerConfigQrCode(ctx context.Context, id domain.PeerIdentifier, style string) (io.Reader, error)
+}
+
+// AdminManagers are the managers used by the administrative subcommands.
+type AdminManagers struct {
+	Users       AdminUserManager
+	WireGuard   AdminWireGuardManager
+	ConfigFiles AdminConfigFileManager
+}
+
+// parseAdminArgs validates the resource and action of an administrative subcommand.
+func parseAdminArgs(arguments []string) (*AdminArgs, error) {
+	resource := arguments[0]
+	if len(arguments) < 2 {
+		return nil, fmt.Errorf("missing %s command, supported commands are %s", resource,
+			strings.Join(adminCommands[resource], ", "))
+	}
+	if !slices.Contains(adminCommands[resource], arguments[1]) {
+		return nil, fmt.Errorf("unknown %s command %q, supported commands are %s", resource, arguments[1],
+			strings.Join(adminCommands[resource], ", "))
+	}
+
+	return &AdminArgs{Resource: resource, Action: arguments[1], Args: arguments[2:]}, nil
+}
+
+// adminCommand is a parsed administrative subcommand. Identifiers are passed as positional argument after the flags.
+type adminCommand struct {
+	flags      *flag.FlagSet
+	jsonOutput *bool
+	w          io.Writer
+}
+
+func newAdminCommand(args *AdminArgs, w io.Writer) *adminCommand {
+	flags := flag.NewFlagSet(args.Resource+" "+args.Action, flag.ContinueOnError)
+	return &adminCommand{
+		flags:      flags,
+		jsonOutput: flags.Bool("json", false, "print the result as JSON"),
+		w:          w,
+	}
+}
+
+// parse parses the flags and returns the positional identifier, if required is set, it must be present.
+func (c *adminCommand) parse(arguments []string, required bool) (string, error) {
+	if err := c.flags.Parse(arguments); err != nil {
+		return "", err
+	}
+	switch {
+	case c.flags.NArg() > 1:
+		return "", fmt.Errorf("unexpected arguments %s, flags must be given before the identifier",
+			strings.Join(c.flags.Args()[1:], " "))
+	case c.flags.NArg() == 0 && required:
+		return "", fmt.Errorf("missing identifier, usage: wg-portal %s [flags] <identifier>", c.flags.Name())
+	}
+	return c.flags.Arg(0), nil
+}
+
+// print writes the value as JSON, or calls text to write a human-readable representation.
+func (c *adminCommand) print(value any, text func(w io.Writer)) error {
+	if *c.jsonOutput {
+		encoder := json.NewEncoder(c.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(c.w, 0, 4, 2, ' ', 0)
+	text(tw)
+	return tw.Flush()
+}
+
+// RunAdminCommand runs an administrative subcommand with system admin rights and prints the result to w.
+func RunAdminCommand(ctx context.Context, w io.Writer, managers AdminManagers, args *AdminArgs) error {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+	cmd := newAdminCommand(args, w)
+
+	switch args.Resource {
+	case "user":
+		return runUserCommand(ctx, cmd, managers.Users, args)
+	case "peer":
+		return runPeerCommand(ctx, cmd, managers, args)
+	case "interface":
+		return runInterfaceCommand(ctx, cmd, managers.WireGuard, args)
+	default:
+		return fmt.Errorf("unknown command %s", args.Resource)
+	}
+}
+
+// region user-commands
+
+// cliUser is the output representation of a user, it never contains credentials.
+type cliUser struct {
+	Identifier     domain.UserIdentifier `json:"Identifier"`
+	Email          string                `json:"Email"`
+	Source         domain.UserSource     `json:"Source"`
+	Firstname      string                `json:"Firstname"`
+	Lastname       string                `json:"Lastname"`
+	IsAdmin        bool                  `json:"IsAdmin"`
+	Disabled       *time.Time            `json:"Disabled"`
+	DisabledReason string                `json:"DisabledReason"`
+	Locked         *time.Time            `json:"Locked"`
+	ApiEnabled     bool                  `json:"ApiEnabled"`
+
+	// Password is only set by reset-password if a password has been generated.
+	Password string `json:"Password,omitempty"`
+	// ApiToken is only set by api-token.
+	ApiToken string `json:"ApiToken,omitempty"`
+}
+
+func newCliUser(user *domain.User) cliUser {
+	return cliUser{
+		Identifier:     user.Identifier,
+		Email:          user.Email,
+		Source:         user.Source,
+		Firstname:      user.Firstname,
+		Lastname:       user.Lastname,
+		IsAdmin:        user.IsAdmin,
+		Disabled:       user.Disabled,
+		DisabledReason: user.DisabledReason,
+		Locked:         user.Locked,
+		ApiEnabled:     user.IsApiEnabled(),
+	}
+}
+
+func printUsers(w io.Writer, users []cliUser) {
+	_, _ = fmt.Fprintln(w, "IDENTIFIER\tEMAIL\tSOURCE\tADMIN\tSTATE")
+	for _, user := range users {
+		state := "enabled"
+		switch {
+		case user.Disabled != nil:
+			state = "disabled"
+		case user.Locked != nil:
+			state = "locked"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", user.Identifier, user.Email, user.Source, user.IsAdmin, state)
+	}
+}
+
+func runUserCommand(ctx context.Context, cmd *adminCommand, users AdminUserManager, args *AdminArgs) error {
+	switch args.Action {
+	case "list":
+		if _, err := cmd.parse(args.Args, false); err != nil {
+			return err
+		}
+		all, err := users.GetAllUsers(ctx)
+		if err != nil {
+			return err
+		}
+		slices.SortFunc(all, func(a, b domain.User) int {
+			return strings.Compare(string(a.Identifier), string(b.Identifier))
+		})
+		result := make([]cliUser, len(all))
+		for i := range all {
+			result[i] = newCliUser(&all[i])
+		}
+		return cmd.print(result, func(w io.Writer) { printUsers(w, result) })
+
+	case "create":
+		email := cmd.flags.String("email", "", "email address of the user")
+		firstname := cmd.flags.String("firstname", "", "first name of the user")
+		lastname := cmd.flags.String("lastname", "", "last name of the user")
+		source := cmd.flags.String("source", string(domain.UserSourceDatabase), "user source: db, ldap or oauth")
+		isAdmin := cmd.flags.Bool("admin", false, "grant admin rights")
+		passwordFile := cmd.flags.String("passwordFile", "",
+			"file containing the password, a password is generated and printed if omitted")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		password, generated, err := readOrGeneratePassword(*passwordFile, domain.UserSource(*source))
+		if err != nil {
+			return err
+		}
+		user, err := users.CreateUser(ctx, &domain.User{
+			Identifier: domain.UserIdentifier(id),
+			Email:      *email,
+			Source:     domain.UserSource(*source),
+			Firstname:  *firstname,
+			Lastname:   *lastname,
+			IsAdmin:    *isAdmin,
+			Password:   domain.PrivateString(password),
+		})
+		if err != nil {
+			return err
+		}
+		result := newCliUser(user)
+		if generated {
+			result.Password = password
+		}
+		return cmd.print(result, func(w io.Writer) {
+			printUsers(w, []cliUser{result})
+			if generated {
+				_, _ = fmt.Fprintf(w, "\nGenerated password: %s\n", password)
+			}
+		})
+
+	case "disable", "enable":
+		reason := cmd.flags.String("reason", domain.DisabledReasonAdmin, "reason shown for disabled users")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		user, err := users.GetUser(ctx, domain.UserIdentifier(id))
+		if err != nil {
+			return err
+		}
+		if args.Action == "disable" {
+			now := time.Now()
+			user.Disabled = &now
+			user.DisabledReason = *reason
+		} else {
+			user.Disabled = nil
+			user.DisabledReason = ""
+		}
+		if user, err = users.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+		result := newCliUser(user)
+		return cmd.print(result, func(w io.Writer) { printUsers(w, []cliUser{result}) })
+
+	case "reset-password":
+		passwordFile := cmd.flags.String("passwordFile", "",
+			"file containing the new password, a password is generated and printed if omitted")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		user, err := users.GetUser(ctx, domain.UserIdentifier(id))
+		if err != nil {
+			return err
+		}
+		if err := user.CanChangePassword(); err != nil {
+			return errors.Join(err, domain.ErrInvalidData)
+		}
+		password, generated, err := readOrGeneratePassword(*passwordFile, user.Source)
+		if err != nil {
+			return err
+		}
+		user.Password = domain.PrivateString(password)
+		if user, err = users.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+		result := newCliUser(user)
+		if generated {
+			result.Password = password
+		}
+		return cmd.print(result, func(w io.Writer) {
+			_, _ = fmt.Fprintf(w, "Password of %s has been reset.\n", user.Identifier)
+			if generated {
+				_, _ = fmt.Fprintf(w, "Generated password: %s\n", password)
+			}
+		})
+
+	case "api-token":
+		disable := cmd.flags.Bool("disable", false, "disable the API access instead of creating a new token")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		var user *domain.User
+		if *disable {
+			user, err = users.DeactivateApi(ctx, domain.UserIdentifier(id))
+		} else {
+			user, err = users.ActivateApi(ctx, domain.UserIdentifier(id))
+		}
+		if err != nil {
+			return err
+		}
+		result := newCliUser(user)
+		result.ApiToken = user.ApiToken
+		return cmd.print(result, func(w io.Writer) {
+			if *disable {
+				_, _ = fmt.Fprintf(w, "API access of %s has been disabled.\n", user.Identifier)
+				return
+			}
+			_, _ = fmt.Fprintf(w, "API token of %s: %s\n", user.Identifier, user.ApiToken)
+		})
+	}
+
+	return fmt.Errorf("unknown user command %s", args.Action)
+}
+
+// readOrGeneratePassword reads the password from the given file. If no file is given, a random password is generated
+// for database users.
+func readOrGeneratePassword(path string, source domain.UserSource) (password string, generated bool, err error) {
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read password file: %w", err)
+		}
+		return strings.TrimSpace(string(raw)), false, nil
+	}
+	if source != domain.UserSourceDatabase {
+		return "", false, nil
+	}
+
+	random := make([]byte, 18)
+	if _, err := rand.Read(random); err != nil {
+		return "", false, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(random), true, nil
+}
+
+// endregion user-commands
+
+// region peer-commands
+
+// cliPeer is the output representation of a peer, it never contains keys.
+type cliPeer struct {
+	Identifier          domain.PeerIdentifier      `json:"Identifier"`
+	DisplayName         string                     `json:"DisplayName"`
+	InterfaceIdentifier domain.InterfaceIdentifier `json:"InterfaceIdentifier"`
+	UserIdentifier      domain.UserIdentifier      `json:"UserIdentifier"`
+	Addresses           []string                   `json:"Addresses"`
+	Disabled            *time.Time                 `json:"Disabled"`
+	DisabledReason      string                     `json:"DisabledReason"`
+	ExpiresAt           *time.Time                 `json:"ExpiresAt"`
+}
+
+func newCliPeer(peer *domain.Peer) cliPeer {
+	addresses := make([]string, len(peer.Interface.Addresses))
+	for i, addr := range peer.Interface.Addresses {
+		addresses[i] = addr.String()
+	}
+	return cliPeer{
+		Identifier:          peer.Identifier,
+		DisplayName:         peer.DisplayName,
+		InterfaceIdentifier: peer.InterfaceIdentifier,
+		UserIdentifier:      peer.UserIdentifier,
+		Addresses:           addresses,
+		Disabled:            peer.Disabled,
+		DisabledReason:      peer.DisabledReason,
+		ExpiresAt:           peer.ExpiresAt,
+	}
+}
+
+func printPeers(w io.Writer, peers []cliPeer) {
+	_, _ = fmt.Fprintln(w, "IDENTIFIER\tNAME\tINTERFACE\tUSER\tADDRESSES\tSTATE")
+	for _, peer := range peers {
+		state := "enabled"
+		if peer.Disabled != nil {
+			state = "disabled"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", peer.Identifier, peer.DisplayName, peer.InterfaceIdentifier,
+			peer.UserIdentifier, strings.Join(peer.Addresses, ","), state)
+	}
+}
+
+func runPeerCommand(ctx context.Context, cmd *adminCommand, managers AdminManagers, args *AdminArgs) error {
+	wg := managers.WireGuard
+
+	switch args.Action {
+	case "list":
+		iface := cmd.flags.String("interface", "", "only list peers of the given interface")
+		user := cmd.flags.String("user", "", "only list peers of the given user")
+		if _, err := cmd.parse(args.Args, false); err != nil {
+			return err
+		}
+		_, interfacePeers, err := wg.GetAllInterfacesAndPeers(ctx)
+		if err != nil {
+			return err
+		}
+		result := make([]cliPeer, 0)
+		for _, peers := range interfacePeers {
+			for i := range peers {
+				if *iface != "" && string(peers[i].InterfaceIdentifier) != *iface {
+					continue
+				}
+				if *user != "" && string(peers[i].UserIdentifier) != *user {
+					continue
+				}
+				result = append(result, newCliPeer(&peers[i]))
+			}
+		}
+		return cmd.print(result, func(w io.Writer) { printPeers(w, result) })
+
+	case "create":
+		user := cmd.flags.String("user", "", "owner of the new peer, the interface defaults are used")
+		name := cmd.flags.String("name", "", "display name of the new peer")
+		ifaceId, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		var peer *domain.Peer
+		if *user != "" {
+			peer, err = wg.CreateUserPeerOnInterface(ctx, domain.UserIdentifier(*user),
+				domain.InterfaceIdentifier(ifaceId))
+		} else {
+			peer, err = wg.PreparePeer(ctx, domain.InterfaceIdentifier(ifaceId))
+			if err == nil {
+				peer, err = wg.CreatePeer(ctx, peer)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if *name != "" {
+			peer.DisplayName = *name
+			if peer, err = wg.UpdatePeer(ctx, peer); err != nil {
+				return err
+			}
+		}
+		result := newCliPeer(peer)
+		return cmd.print(result, func(w io.Writer) { printPeers(w, []cliPeer{result}) })
+
+	case "disable", "enable":
+		reason := cmd.flags.String("reason", domain.DisabledReasonAdmin, "reason shown for disabled peers")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		peer, err := wg.GetPeer(ctx, domain.PeerIdentifier(id))
+		if err != nil {
+			return err
+		}
+		if args.Action == "disable" {
+			now := time.Now()
+			peer.Disabled = &now
+			peer.DisabledReason = *reason
+		} else {
+			peer.Disabled = nil
+			peer.DisabledReason = ""
+		}
+		if peer, err = wg.UpdatePeer(ctx, peer); err != nil {
+			return err
+		}
+		result := newCliPeer(peer)
+		return cmd.print(result, func(w io.Writer) { printPeers(w, []cliPeer{result}) })
+
+	case "config":
+		style := cmd.flags.String("style", domain.ConfigStyleWgQuick, "configuration style, wgquick or raw")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		cfg, err := managers.ConfigFiles.GetPeerConfig(ctx, domain.PeerIdentifier(id), *style)
+		if err != nil {
+			return err
+		}
+		if !*cmd.jsonOutput {
+			_, err = io.Copy(cmd.w, cfg)
+			return err
+		}
+		raw, err := io.ReadAll(cfg)
+		if err != nil {
+			return err
+		}
+		return cmd.print(struct {
+			Identifier domain.PeerIdentifier `json:"Identifier"`
+			Config     string                `json:"Config"`
+		}{Identifier: domain.PeerIdentifier(id), Config: string(raw)}, nil)
+
+	case "qr":
+		style := cmd.flags.String("style", domain.ConfigStyleWgQuick, "configuration style, wgquick or raw")
+		file := cmd.flags.String("file", "", "path of the PNG file, - writes to stdout")
+		id, err := cmd.parse(args.Args, true)
+		if err != nil {
+			return err
+		}
+		if *file == "" {
+			return fmt.Errorf("missing output file, use -file")
+		}
+		png, err := managers.ConfigFiles.GetPeerConfigQrCode(ctx, domain.PeerIdentifier(id), *style)
+		if err != nil {
+			return err
+		}
+		if *file == "-" {
+			_, err = io.Copy(cmd.w, png)
+			return err
+		}
+		raw, err := io.ReadAll(png)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(*file, raw, 0600)
+	}
+
+	return fmt.Errorf("unknown peer command %s", args.Action)
+}
+
+// endregion peer-commands
+
+// region interface-commands
+
+// cliInterface is the output representation of an interface, it never contains keys.
+type cliInterface struct {
+	Identifier  domain.InterfaceIdentifier `json:"Identifier"`
+	DisplayName string                     `json:"DisplayName"`
+	Type        domain.InterfaceType       `json:"Type"`
+	Backend     domain.InterfaceBackend    `json:"Backend"`
+	PublicKey   string                     `json:"PublicKey"`
+	ListenPort  int                        `json:"ListenPort"`
+	Addresses   []string                   `json:"Addresses"`
+	Disabled    *time.Time                 `json:"Disabled"`
+	Peers       int                        `json:"Peers"`
+}
+
+func printInterfaces(w io.Writer, interfaces []cliInterface) {
+	_, _ = fmt.Fprintln(w, "IDENTIFIER\tTYPE\tBACKEND\tPORT\tADDRESSES\tPEERS\tSTATE")
+	for _, iface := range interfaces {
+		state := "enabled"
+		if iface.Disabled != nil {
+			state = "disabled"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%s\n", iface.Identifier, iface.Type, iface.Backend,
+			iface.ListenPort, strings.Join(iface.Addresses, ","), iface.Peers, state)
+	}
+}
+
+func runInterfaceCommand(ctx context.Context, cmd *adminCommand, wg AdminWireGuardManager, args *AdminArgs) error {
+	switch args.Action {
+	case "list":
+		if _, err := cmd.parse(args.Args, false); err != nil {
+			return err
+		}
+		interfaces, peers, err := wg.GetAllInterfacesAndPeers(ctx)
+		if err != nil {
+			return err
+		}
+		result := make([]cliInterface, len(interfaces))
+		for i, iface := range interfaces {
+			addresses := make([]string, len(iface.Addresses))
+			for j, addr := range iface.Addresses {
+				addresses[j] = addr.String()
+			}
+			result[i] = cliInterface{
+				Identifier:  iface.Identifier,
+				DisplayName: iface.DisplayName,
+				Type:        iface.Type,
+				Backend:     iface.Backend,
+				PublicKey:   iface.PublicKey,
+				ListenPort:  iface.ListenPort,
+				Addresses:   addresses,
+				Disabled:    iface.Disabled,
+				Peers:       len(peers[i]),
+			}
+		}
+		return cmd.print(result, func(w io.Writer) { printInterfaces(w, result) })
+
+	case "apply":
+		id, err := cmd.parse(args.Args, false)
+		if err != nil {
+			return err
+		}
+		var filter []domain.InterfaceIdentifier
+		if id != "" {
+			filter = append(filter, domain.InterfaceIdentifier(id))
+		}
+		if err := wg.RestoreInterfaceState(ctx, true, filter...); err != nil {
+			return err
+		}
+		return cmd.print(struct {
+			Applied []domain.InterfaceIdentifier `json:"Applied"`
+		}{Applied: filter}, func(w io.Writer) {
+			if id == "" {
+				_, _ = fmt.Fprintln(w, "All interfaces have been applied.")
+				return
+			}
+			_, _ = fmt.Fprintf(w, "Interface %s has been applied.\n", id)
+		})
+
+	case "import":
+		id, err := cmd.parse(args.Args, false)
+		if err != nil {
+			return err
+		}
+		var filter []domain.InterfaceIdentifier
+		if id != "" {
+			filter = append(filter, domain.InterfaceIdentifier(id))
+		}
+		imported, err := wg.ImportNewInterfaces(ctx, filter...)
+		if err != nil {
+			return err
+		}
+		return cmd.print(struct {
+			Imported int `json:"Imported"`
+		}{Imported: imported}, func(w io.Writer) {
+			_, _ = fmt.Fprintf(w, "Imported %d new interfaces.\n", imported)
+		})
+	}
+
+	return fmt.Errorf("unknown interface command %s", args.Action)
+}
+
+// endregion interface-commands
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockAdminUsers struct {
+	users map[domain.UserIdentifier]*domain.User
+}
+
+func (m *mockAdminUsers) GetUser(_ context.Context, id domain.UserIdentifier) (*domain.User, error) {
+	if user, ok := m.users[id]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrNotFound
+}
+func (m *mockAdminUsers) GetAllUsers(_ context.Context) ([]domain.User, error) {
+	all := make([]domain.User, 0, len(m.users))
+	for _, user := range m.users {
+		all = append(all, *user)
+	}
+	return all, nil
+}
+func (m *mockAdminUsers) CreateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	if _, ok := m.users[user.Identifier]; ok {
+		return nil, domain.ErrDuplicateEntry
+	}
+	m.users[user.Identifier] = user
+	return user, nil
+}
+func (m *mockAdminUsers) UpdateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	m.users[user.Identifier] = user
+	return user, nil
+}
+func (m *mockAdminUsers) ActivateApi(_ context.Context, id domain.UserIdentifier) (*domain.User, error) {
+	m.users[id].ApiToken = "token"
+	return m.users[id], nil
+}
+func (m *mockAdminUsers) DeactivateApi(_ context.Context, id domain.UserIdentifier) (*domain.User, error) {
+	m.users[id].ApiToken = ""
+	return m.users[id], nil
+}
+
+type mockAdminWireGuard struct {
+	AdminWireGuardManager // methods that are not used by the tests panic
+
+	peers    map[domain.PeerIdentifier]*domain.Peer
+	restored []domain.InterfaceIdentifier
+}
+
+func (m *mockAdminWireGuard) GetPeer(_ context.Context, id domain.PeerIdentifier) (*domain.Peer, error) {
+	if peer, ok := m.peers[id]; ok {
+		return peer, nil
+	}
+	return nil, domain.ErrNotFound
+}
+func (m *mockAdminWireGuard) UpdatePeer(_ context.Context, peer *domain.Peer) (*domain.Peer, error) {
+	m.peers[peer.Identifier] = peer
+	return peer, nil
+}
+func (m *mockAdminWireGuard) RestoreInterfaceState(
+	_ context.Context,
+	_ bool,
+	filter ...domain.InterfaceIdentifier,
+) error {
+	m.restored = filter
+	return nil
+}
+
+type mockAdminConfigFiles struct{}
+
+func (m mockAdminConfigFiles) GetPeerConfig(_ context.Context, id domain.PeerIdentifier, style string) (
+	io.Reader,
+	error,
+) {
+	return strings.NewReader("# " + string(id) + " " + style + "\n"), nil
+}
+func (m mockAdminConfigFiles) GetPeerConfigQrCode(_ context.Context, _ domain.PeerIdentifier, _ string) (
+	io.Reader,
+	error,
+) {
+	return strings.NewReader("png"), nil
+}
+
+func newTestAdminManagers() (AdminManagers, *mockAdminUsers, *mockAdminWireGuard) {
+	users := &mockAdminUsers{users: map[domain.UserIdentifier]*domain.User{
+		"alice": {Identifier: "alice", Email: "alice@example.com", Source: domain.UserSourceDatabase},
+	}}
+	wg := &mockAdminWireGuard{peers: map[domain.PeerIdentifier]*domain.Peer{
+		"peer1": {Identifier: "peer1", InterfaceIdentifier: "wg0", UserIdentifier: "alice"},
+	}}
+	return AdminManagers{Users: users, WireGuard: wg, ConfigFiles: mockAdminConfigFiles{}}, users, wg
+}
+
+func runTestAdminCommand(t *testing.T, managers AdminManagers, arguments ...string) (string, error) {
+	t.Helper()
+	args, err := parseAdminArgs(arguments)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = RunAdminCommand(context.Background(), &out, managers, args)
+	return out.String(), err
+}
+
+func TestParseAdminArgs(t *testing.T) {
+	args, err := parseAdminArgs([]string{"user", "create", "-admin", "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, &AdminArgs{Resource: "user", Action: "create", Args: []string{"-admin", "bob"}}, args)
+
+	_, err = parseAdminArgs([]string{"peer"})
+	assert.ErrorContains(t, err, "missing peer command")
+
+	_, err = parseAdminArgs([]string{"interface", "delete"})
+	assert.ErrorContains(t, err, "unknown interface command")
+}
+
+func TestRunAdminCommand_Users(t *testing.T) {
+	managers, users, _ := newTestAdminManagers()
+
+	out, err := runTestAdminCommand(t, managers, "user", "create", "-json", "-admin", "-email", "bob@example.com",
+		"bob")
+	require.NoError(t, err)
+	var created cliUser
+	require.NoError(t, json.Unmarshal([]byte(out), &created))
+	assert.True(t, created.IsAdmin)
+	assert.NotEmpty(t, created.Password, "a password must be generated")
+	assert.Equal(t, created.Password, string(users.users["bob"].Password))
+
+	_, err = runTestAdminCommand(t, managers, "user", "disable", "alice")
+	require.NoError(t, err)
+	assert.True(t, users.users["alice"].IsDisabled())
+	assert.Equal(t, domain.DisabledReasonAdmin, users.users["alice"].DisabledReason)
+
+	out, err = runTestAdminCommand(t, managers, "user", "list")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "alice")
+	assert.Contains(t, lines[1], "disabled")
+
+	out, err = runTestAdminCommand(t, managers, "user", "api-token", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "API token of alice: token\n", out)
+
+	_, err = runTestAdminCommand(t, managers, "user", "disable", "alice", "-reason", "left")
+	assert.ErrorContains(t, err, "flags must be given before the identifier")
+
+	_, err = runTestAdminCommand(t, managers, "user", "reset-password")
+	assert.ErrorContains(t, err, "missing identifier")
+}
+
+func TestRunAdminCommand_Peers(t *testing.T) {
+	managers, _, wg := newTestAdminManagers()
+
+	_, err := runTestAdminCommand(t, managers, "peer", "disable", "-reason", "lost device", "peer1")
+	require.NoError(t, err)
+	assert.True(t, wg.peers["peer1"].IsDisabled())
+	assert.Equal(t, "lost device", wg.peers["peer1"].DisabledReason)
+
+	_, err = runTestAdminCommand(t, managers, "peer", "enable", "peer1")
+	require.NoError(t, err)
+	assert.False(t, wg.peers["peer1"].IsDisabled())
+
+	out, err := runTestAdminCommand(t, managers, "peer", "config", "-style", domain.ConfigStyleRaw, "peer1")
+	require.NoError(t, err)
+	assert.Equal(t, "# peer1 raw\n", out)
+
+	_, err = runTestAdminCommand(t, managers, "peer", "qr", "peer1")
+	assert.ErrorContains(t, err, "missing output file")
+
+	_, err = runTestAdminCommand(t, managers, "peer", "disable", "missing")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRunAdminCommand_InterfaceApply(t *testing.T) {
+	managers, _, wg := newTestAdminManagers()
+
+	out, err := runTestAdminCommand(t, managers, "interface", "apply", "-json", "wg0")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Applied": ["wg0"]}`, out)
+	assert.Equal(t, []domain.InterfaceIdentifier{"wg0"}, wg.restored)
+}
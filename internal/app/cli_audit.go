@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// AuditArgs contains the arguments of the audit subcommand.
+type AuditArgs struct {
+	Json bool // print the result as JSON
+}
+
+// AuditVerifier verifies the hash chain of the audit log.
+type AuditVerifier interface {
+	VerifyChain(ctx context.Context) (*domain.AuditChainReport, error)
+}
+
+// parseAuditArgs parses the arguments of the audit subcommand, currently only verify is supported.
+func parseAuditArgs(arguments []string) (*AuditArgs, error) {
+	if len(arguments) == 0 || arguments[0] != "verify" {
+		return nil, fmt.Errorf("usage: wg-portal audit verify [-json]")
+	}
+
+	flags := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(arguments[1:]); err != nil {
+		return nil, err
+	}
+
+	return &AuditArgs{Json: *jsonOutput}, nil
+}
+
+// RunAuditCommand verifies the hash chain of the audit log and prints the report. An error is returned if the chain
+// is broken.
+func RunAuditCommand(ctx context.Context, w io.Writer, verifier AuditVerifier, args *AuditArgs) error {
+	ctx = domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+
+	report, err := verifier.VerifyChain(ctx)
+	if err != nil {
+		return err
+	}
+
+	if args.Json {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printAuditChainReport(w, report)
+	}
+
+	if !report.Valid {
+		return fmt.Errorf("audit log hash chain is broken")
+	}
+	return nil
+}
+
+func printAuditChainReport(w io.Writer, report *domain.AuditChainReport) {
+	_, _ = fmt.Fprintf(w, "Verified entries: %d, unchained entries: %d, verified checkpoints: %d\n",
+		report.Entries, report.UnchainedEntries, report.Checkpoints)
+
+	broken := report.FirstBroken
+	switch {
+	case broken == nil:
+		_, _ = fmt.Fprintln(w, "The audit log hash chain is valid.")
+	case broken.CheckpointId != 0:
+		_, _ = fmt.Fprintf(w, "Broken at checkpoint %d (%s): %s\n", broken.CheckpointId,
+			broken.CreatedAt.Format(time.RFC3339), broken.Reason)
+	default:
+		_, _ = fmt.Fprintf(w, "Broken at entry %d (%s): %s\n", broken.EntryId,
+			broken.CreatedAt.Format(time.RFC3339), broken.Reason)
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockAuditVerifier struct {
+	report *domain.AuditChainReport
+}
+
+func (m mockAuditVerifier) VerifyChain(ctx context.Context) (*domain.AuditChainReport, error) {
+	if !domain.GetUserInfo(ctx).IsAdmin {
+		return nil, domain.ErrNoPermission
+	}
+	return m.report, nil
+}
+
+func TestParseAuditArgs(t *testing.T) {
+	args, err := parseAuditArgs([]string{"verify", "-json"})
+	require.NoError(t, err)
+	assert.True(t, args.Json)
+
+	_, err = parseAuditArgs([]string{"repair"})
+	assert.Error(t, err)
+	_, err = parseAuditArgs(nil)
+	assert.Error(t, err)
+}
+
+func TestRunAuditCommand(t *testing.T) {
+	var out bytes.Buffer
+	verifier := mockAuditVerifier{report: &domain.AuditChainReport{Valid: true, Entries: 3, Checkpoints: 1}}
+	require.NoError(t, RunAuditCommand(context.Background(), &out, verifier, &AuditArgs{}))
+	assert.Contains(t, out.String(), "hash chain is valid")
+
+	out.Reset()
+	verifier.report = &domain.AuditChainReport{Entries: 1, FirstBroken: &domain.AuditChainBreak{EntryId: 2,
+		Reason: "the entry has been altered"}}
+	require.Error(t, RunAuditCommand(context.Background(), &out, verifier, &AuditArgs{Json: true}))
+
+	var report domain.AuditChainReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.False(t, report.Valid)
+	assert.Equal(t, uint64(2), report.FirstBroken.EntryId)
+}
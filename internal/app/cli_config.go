@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// ConnectivityChecker tests the connectivity to the external services of a configuration.
+type ConnectivityChecker interface {
+	Check(ctx context.Context, cfg *config.Config) []domain.ConnectivityCheck
+}
+
+// configValidationReport is the JSON output of the config validate subcommand.
+type configValidationReport struct {
+	Valid        bool                       `json:"valid"`
+	Errors       []config.ValidationError   `json:"errors"`
+	Connectivity []domain.ConnectivityCheck `json:"connectivity,omitempty"`
+}
+
+// IsConfigCommand returns true if the program arguments start with the config subcommand. It must be handled before
+// the configuration is loaded, as the configuration may be invalid.
+func IsConfigCommand(arguments []string) bool {
+	return len(arguments) > 0 && arguments[0] == "config"
+}
+
+// RunConfigCommand runs the config validate or config dump subcommand. The arguments start after "config".
+// Validation errors are printed to w, an error is returned if the configuration is invalid.
+func RunConfigCommand(ctx context.Context, w io.Writer, checker ConnectivityChecker, arguments []string) error {
+	if len(arguments) == 0 || (arguments[0] != "validate" && arguments[0] != "dump") {
+		return fmt.Errorf("usage: wg-portal config validate [-connectivity] [-json] | config dump")
+	}
+
+	flags := flag.NewFlagSet("config "+arguments[0], flag.ContinueOnError)
+	flags.SetOutput(w)
+	var connectivity, jsonOutput *bool
+	if arguments[0] == "validate" {
+		connectivity = flags.Bool("connectivity", false,
+			"also connect to the LDAP, SMTP, OIDC and backend servers of the configuration")
+		jsonOutput = flags.Bool("json", false, "print the result as JSON")
+	}
+	if err := flags.Parse(arguments[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if arguments[0] == "dump" {
+		return cfg.Dump(w)
+	}
+
+	report := configValidationReport{Errors: cfg.Validate()}
+	if report.Errors == nil {
+		report.Errors = []config.ValidationError{}
+	}
+	if *connectivity {
+		report.Connectivity = checker.Check(ctx, cfg)
+	}
+	report.Valid = len(report.Errors) == 0
+	failedChecks := 0
+	for _, check := range report.Connectivity {
+		if !check.OK() {
+			failedChecks++
+		}
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printConfigValidationReport(w, &report)
+	}
+
+	switch {
+	case !report.Valid:
+		return fmt.Errorf("configuration has %d errors", len(report.Errors))
+	case failedChecks > 0:
+		return fmt.Errorf("%d connectivity checks failed", failedChecks)
+	}
+	return nil
+}
+
+func printConfigValidationReport(w io.Writer, report *configValidationReport) {
+	for _, validationErr := range report.Errors {
+		_, _ = fmt.Fprintf(w, "error: %s\n", validationErr.Error())
+	}
+	for _, check := range report.Connectivity {
+		if check.OK() {
+			_, _ = fmt.Fprintf(w, "ok: %s (%s)\n", check.Setting, check.Target)
+		} else {
+			_, _ = fmt.Fprintf(w, "unreachable: %s (%s): %s\n", check.Setting, check.Target, check.Error)
+		}
+	}
+	if report.Valid {
+		_, _ = fmt.Fprintln(w, "Configuration is valid.")
+	}
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockConnectivityChecker struct{}
+
+func (m mockConnectivityChecker) Check(_ context.Context, cfg *config.Config) []domain.ConnectivityCheck {
+	return []domain.ConnectivityCheck{{Setting: "mail", Target: cfg.Mail.Host, Error: "connection refused"}}
+}
+
+func writeConfigCommandConfig(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	t.Setenv("WG_PORTAL_CONFIG", path)
+}
+
+func TestRunConfigCommand_Validate(t *testing.T) {
+	writeConfigCommandConfig(t, "mail:\n  host: smtp.example.com\n  encryption: ssl\n")
+
+	var out bytes.Buffer
+	err := RunConfigCommand(context.Background(), &out, mockConnectivityChecker{},
+		[]string{"validate", "-connectivity", "-json"})
+	require.Error(t, err)
+
+	var report configValidationReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "mail.encryption", report.Errors[0].Setting)
+	require.Len(t, report.Connectivity, 1)
+	assert.Equal(t, "smtp.example.com", report.Connectivity[0].Target)
+}
+
+func TestRunConfigCommand_ConnectivityFailure(t *testing.T) {
+	writeConfigCommandConfig(t, "mail:\n  host: smtp.example.com\n")
+
+	var out bytes.Buffer
+	err := RunConfigCommand(context.Background(), &out, mockConnectivityChecker{},
+		[]string{"validate", "-connectivity"})
+	assert.EqualError(t, err, "1 connectivity checks failed")
+	assert.Contains(t, out.String(), "unreachable: mail (smtp.example.com): connection refused")
+	assert.Contains(t, out.String(), "Configuration is valid.")
+}
+
+func TestRunConfigCommand_Usage(t *testing.T) {
+	var out bytes.Buffer
+	assert.Error(t, RunConfigCommand(context.Background(), &out, mockConnectivityChecker{}, nil))
+	assert.Error(t, RunConfigCommand(context.Background(), &out, mockConnectivityChecker{}, []string{"check"}))
+}
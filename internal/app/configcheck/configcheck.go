@@ -0,0 +1,101 @@
+package configcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+
+	"github.com/biezax/wg-portal/internal"
+	"github.com/biezax/wg-portal/internal/adapters"
+	"github.com/biezax/wg-portal/internal/adapters/wgcontroller"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// checkTimeout limits the duration of a single connectivity check.
+const checkTimeout = 15 * time.Second
+
+// Checker tests the connectivity to all external services of a configuration.
+type Checker struct{}
+
+// NewChecker creates a new Checker.
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Check connects to all configured LDAP servers, the SMTP server, all Mikrotik and pfSense backends and discovers
+// all OpenID Connect providers. It returns one result per service, failures do not abort the remaining checks.
+func (c *Checker) Check(ctx context.Context, cfg *config.Config) []domain.ConnectivityCheck {
+	var results []domain.ConnectivityCheck
+
+	for i := range cfg.Auth.Ldap {
+		provider := &cfg.Auth.Ldap[i]
+		results = append(results, c.run(ctx, fmt.Sprintf("auth.ldap[%d]", i), provider.URL,
+			func(_ context.Context) error {
+				conn, err := internal.LdapConnect(provider)
+				if err != nil {
+					return err
+				}
+				internal.LdapDisconnect(conn)
+				return nil
+			}))
+	}
+
+	for i, provider := range cfg.Auth.OpenIDConnect {
+		results = append(results, c.run(ctx, fmt.Sprintf("auth.oidc[%d]", i), provider.BaseUrl,
+			func(ctx context.Context) error {
+				_, err := oidc.NewProvider(ctx, provider.BaseUrl)
+				return err
+			}))
+	}
+
+	if cfg.Mail.Host != "" {
+		target := fmt.Sprintf("%s:%d", cfg.Mail.Host, cfg.Mail.Port)
+		results = append(results, c.run(ctx, "mail", target, adapters.NewSmtpMailRepo(cfg.Mail).Ping))
+	}
+
+	for i := range cfg.Backend.Mikrotik {
+		backend := &cfg.Backend.Mikrotik[i]
+		results = append(results, c.run(ctx, fmt.Sprintf("backend.mikrotik[%d]", i), backend.ApiUrl,
+			func(ctx context.Context) error {
+				controller, err := wgcontroller.NewMikrotikController(cfg, backend)
+				if err != nil {
+					return err
+				}
+				_, err = controller.GetInterfaces(ctx)
+				return err
+			}))
+	}
+
+	for i := range cfg.Backend.Pfsense {
+		backend := &cfg.Backend.Pfsense[i]
+		results = append(results, c.run(ctx, fmt.Sprintf("backend.pfsense[%d]", i), backend.ApiUrl,
+			func(ctx context.Context) error {
+				controller, err := wgcontroller.NewPfsenseController(cfg, backend)
+				if err != nil {
+					return err
+				}
+				_, err = controller.GetInterfaces(ctx)
+				return err
+			}))
+	}
+
+	return results
+}
+
+func (c *Checker) run(
+	ctx context.Context,
+	setting, target string,
+	check func(ctx context.Context) error,
+) domain.ConnectivityCheck {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	result := domain.ConnectivityCheck{Setting: setting, Target: target}
+	if err := check(ctx); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
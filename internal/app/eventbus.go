@@ -15,6 +15,8 @@ const TopicUserDeleted = "user:deleted"
 const TopicUserUpdated = "user:updated"
 const TopicUserApiEnabled = "user:api:enabled"
 const TopicUserApiDisabled = "user:api:disabled"
+const TopicUserApiTokenCreated = "user:api:token:created"
+const TopicUserApiTokenRevoked = "user:api:token:revoked"
 const TopicUserRegistered = "user:registered"
 const TopicUserDisabled = "user:disabled"
 const TopicUserEnabled = "user:enabled"
@@ -37,6 +39,7 @@ const TopicPeerUpdated = "peer:updated"
 const TopicPeerInterfaceUpdated = "peer:interface:updated"
 const TopicPeerIdentifierUpdated = "peer:identifier:updated"
 const TopicPeerStateChanged = "peer:state:changed"
+const TopicPeerExpiring = "peer:expiring"
 
 // endregion peer-events
 
@@ -47,5 +50,11 @@ const TopicAuditLoginFailed = "audit:login:failed"
 
 const TopicAuditInterfaceChanged = "audit:interface:changed"
 const TopicAuditPeerChanged = "audit:peer:changed"
+const TopicAuditUserChanged = "audit:user:changed"
+const TopicAuditApiTokenChanged = "audit:api_token:changed"
+const TopicAuditWebAuthnChanged = "audit:webauthn:changed"
+const TopicAuditConfigChanged = "audit:config:changed"
+
+const TopicAuditEntryCreated = "audit:entry:created"
 
 // endregion audit-events
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// MessageBus is the message bus that is wrapped by the TrackingEventBus.
+type MessageBus interface {
+	// Publish publishes the arguments to all subscribers of the topic.
+	Publish(topic string, args ...any)
+	// Subscribe subscribes the handler function to the topic.
+	Subscribe(topic string, fn any) error
+}
+
+// TrackingEventBus counts the published events that have not been handled yet. The handlers of the message bus run
+// asynchronously, so one-shot commands use Wait to make sure that all follow-up work of their changes, like audit
+// entries or disabling the peers of a disabled user, is done before the process exits.
+type TrackingEventBus struct {
+	bus MessageBus
+
+	mu          sync.Mutex
+	idle        *sync.Cond
+	subscribers map[string]int // number of handlers per topic
+	pending     int            // number of handler calls that have not finished yet
+}
+
+// NewTrackingEventBus wraps the given message bus.
+func NewTrackingEventBus(bus MessageBus) *TrackingEventBus {
+	b := &TrackingEventBus{
+		bus:         bus,
+		subscribers: make(map[string]int),
+	}
+	b.idle = sync.NewCond(&b.mu)
+
+	return b
+}
+
+// Publish publishes the arguments to all subscribers of the topic.
+func (b *TrackingEventBus) Publish(topic string, args ...any) {
+	b.mu.Lock()
+	b.pending += b.subscribers[topic]
+	b.mu.Unlock()
+
+	b.bus.Publish(topic, args...)
+}
+
+// Subscribe subscribes the handler function to the topic.
+func (b *TrackingEventBus) Subscribe(topic string, fn any) error {
+	callback := reflect.ValueOf(fn)
+	if callback.Kind() != reflect.Func {
+		return b.bus.Subscribe(topic, fn) // let the message bus report the invalid handler
+	}
+
+	wrapped := reflect.MakeFunc(callback.Type(), func(args []reflect.Value) []reflect.Value {
+		defer b.done()
+		if callback.Type().IsVariadic() {
+			return callback.CallSlice(args)
+		}
+		return callback.Call(args)
+	})
+	if err := b.bus.Subscribe(topic, wrapped.Interface()); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.subscribers[topic]++
+	b.mu.Unlock()
+
+	return nil
+}
+
+// Wait blocks until all published events have been handled, including the events that have been published by the
+// handlers themselves, or until the context is cancelled.
+func (b *TrackingEventBus) Wait(ctx context.Context) error {
+	idle := make(chan struct{})
+	go func() {
+		b.mu.Lock()
+		for b.pending > 0 {
+			b.idle.Wait()
+		}
+		b.mu.Unlock()
+		close(idle)
+	}()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *TrackingEventBus) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending--
+	if b.pending == 0 {
+		b.idle.Broadcast()
+	}
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	evbus "github.com/vardius/message-bus"
+)
+
+func TestTrackingEventBus_Wait(t *testing.T) {
+	bus := NewTrackingEventBus(evbus.New(10))
+
+	var handled atomic.Int32
+	require.NoError(t, bus.Subscribe(TopicUserDisabled, func(id string) {
+		time.Sleep(50 * time.Millisecond)
+		bus.Publish(TopicPeerUpdated, id) // follow-up events of handlers are awaited as well
+	}))
+	require.NoError(t, bus.Subscribe(TopicPeerUpdated, func(_ string) {
+		time.Sleep(50 * time.Millisecond)
+		handled.Add(1)
+	}))
+
+	bus.Publish(TopicUserDisabled, "bob")
+	bus.Publish(TopicUserDeleted, "bob") // no subscribers
+
+	require.NoError(t, bus.Wait(context.Background()))
+	assert.Equal(t, int32(1), handled.Load())
+}
+
+func TestTrackingEventBus_WaitCancelled(t *testing.T) {
+	bus := NewTrackingEventBus(evbus.New(10))
+	block := make(chan struct{})
+	defer close(block)
+	require.NoError(t, bus.Subscribe(TopicUserDisabled, func(_ string) { <-block }))
+
+	bus.Publish(TopicUserDisabled, "bob")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bus.Wait(ctx), context.DeadlineExceeded)
+}
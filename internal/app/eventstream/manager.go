@@ -0,0 +1,201 @@
+package eventstream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// region dependencies
+
+type EventBus interface {
+	// Subscribe subscribes to a topic
+	Subscribe(topic string, fn interface{}) error
+}
+
+// endregion dependencies
+
+const (
+	// bufferSize is the number of events that are kept in memory to resume interrupted streams.
+	bufferSize = 1024
+	// subscriberQueueSize is the number of events that can be queued for a subscriber. Subscribers that do not keep
+	// up are disconnected, they can resume the stream with the last received event id.
+	subscriberQueueSize = 128
+)
+
+type subscriber struct {
+	user   *domain.ContextUserInfo
+	filter domain.StreamEventFilter
+	events chan domain.StreamEvent
+}
+
+// Manager relays peer, interface and audit events of the message bus to the subscribers of the real-time
+// event stream.
+type Manager struct {
+	bus EventBus
+
+	// mu protects the ring buffer and the subscribers.
+	mu          sync.Mutex
+	firstId     uint64                         // id of the first event, ids of later events are consecutive
+	lastId      uint64                         // id of the latest event
+	buffer      [bufferSize]domain.StreamEvent // event with id n is stored at index (n-firstId) % bufferSize
+	subscribers map[*subscriber]struct{}
+}
+
+// NewManager creates a new event stream manager instance.
+func NewManager(bus EventBus) (*Manager, error) {
+	// event ids start at the current time, so that ids of a previous process are recognized as outdated
+	firstId := uint64(time.Now().UnixMicro())
+	m := &Manager{
+		bus:         bus,
+		firstId:     firstId,
+		lastId:      firstId - 1,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+
+	err := m.connectToMessageBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup message bus: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *Manager) connectToMessageBus() error {
+	subscriptions := map[string]any{
+		app.TopicPeerCreated:       m.handlePeerEvent(domain.StreamEventPeerCreated),
+		app.TopicPeerUpdated:       m.handlePeerEvent(domain.StreamEventPeerUpdated),
+		app.TopicPeerDeleted:       m.handlePeerEvent(domain.StreamEventPeerDeleted),
+		app.TopicPeerStateChanged:  m.handlePeerStateChangeEvent,
+		app.TopicInterfaceCreated:  m.handleInterfaceEvent(domain.StreamEventInterfaceCreated),
+		app.TopicInterfaceUpdated:  m.handleInterfaceEvent(domain.StreamEventInterfaceUpdated),
+		app.TopicInterfaceDeleted:  m.handleInterfaceEvent(domain.StreamEventInterfaceDeleted),
+		app.TopicAuditEntryCreated: m.handleAuditEntryEvent,
+	}
+	for topic, fn := range subscriptions {
+		if err := m.bus.Subscribe(topic, fn); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) handlePeerEvent(eventType domain.StreamEventType) func(peer domain.Peer) {
+	return func(peer domain.Peer) {
+		m.publish(eventType, peer.UserIdentifier, peer)
+	}
+}
+
+func (m *Manager) handlePeerStateChangeEvent(peerStatus domain.PeerStatus, peer domain.Peer) {
+	if peerStatus.IsConnected {
+		m.publish(domain.StreamEventPeerConnected, peer.UserIdentifier, peerStatus)
+	} else {
+		m.publish(domain.StreamEventPeerDisconnected, peer.UserIdentifier, peerStatus)
+	}
+}
+
+func (m *Manager) handleInterfaceEvent(eventType domain.StreamEventType) func(iface domain.Interface) {
+	return func(iface domain.Interface) {
+		m.publish(eventType, "", iface)
+	}
+}
+
+func (m *Manager) handleAuditEntryEvent(entry domain.AuditEntry) {
+	m.publish(domain.StreamEventAuditEntry, "", entry)
+}
+
+// publish stores the event in the ring buffer and sends it to all subscribers that can see it.
+func (m *Manager) publish(eventType domain.StreamEventType, owner domain.UserIdentifier, payload any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastId++
+	event := domain.StreamEvent{
+		Id:             m.lastId,
+		Type:           eventType,
+		CreatedAt:      time.Now(),
+		UserIdentifier: owner,
+		Payload:        payload,
+	}
+	m.buffer[(event.Id-m.firstId)%bufferSize] = event
+
+	for s := range m.subscribers {
+		if !s.wants(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			// the subscriber is too slow, disconnect it
+			delete(m.subscribers, s)
+			close(s.events)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber for the user of the context. If resumeAfter is set, the buffered events after
+// this event id are returned for replay. If these events are no longer buffered, the replay starts with a reset event.
+// The events channel is closed if the subscriber cannot keep up, the returned function ends the subscription.
+func (m *Manager) Subscribe(
+	ctx context.Context,
+	filter domain.StreamEventFilter,
+	resumeAfter *uint64,
+) (replay []domain.StreamEvent, events <-chan domain.StreamEvent, unsubscribe func()) {
+	s := &subscriber{
+		user:   domain.GetUserInfo(ctx),
+		filter: filter,
+		events: make(chan domain.StreamEvent, subscriberQueueSize),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if resumeAfter != nil {
+		replay = m.replay(s, *resumeAfter)
+	}
+	m.subscribers[s] = struct{}{}
+
+	unsubscribe = func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if _, ok := m.subscribers[s]; ok {
+			delete(m.subscribers, s)
+			close(s.events)
+		}
+	}
+
+	return replay, s.events, unsubscribe
+}
+
+// replay returns the buffered events after the given id. The caller must hold the lock.
+func (m *Manager) replay(s *subscriber, after uint64) []domain.StreamEvent {
+	oldestId := m.firstId
+	if m.lastId-m.firstId >= bufferSize {
+		oldestId = m.lastId - bufferSize + 1
+	}
+
+	if after > m.lastId || after+1 < oldestId {
+		// the id is unknown (for example of a previous process) or the events have been overwritten already
+		return []domain.StreamEvent{{Id: m.lastId, Type: domain.StreamEventReset, CreatedAt: time.Now()}}
+	}
+
+	var events []domain.StreamEvent
+	for id := after + 1; id <= m.lastId; id++ {
+		event := m.buffer[(id-m.firstId)%bufferSize]
+		if s.wants(event) {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func (s *subscriber) wants(event domain.StreamEvent) bool {
+	return event.VisibleTo(s.user) && s.filter.Matches(event)
+}
@@ -0,0 +1,116 @@
+package eventstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockBus struct{}
+
+func (f *mockBus) Subscribe(topic string, fn interface{}) error { return nil }
+
+func userContext(id domain.UserIdentifier, isAdmin bool) context.Context {
+	return domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: id, IsAdmin: isAdmin})
+}
+
+func eventTypes(events []domain.StreamEvent) []domain.StreamEventType {
+	types := make([]domain.StreamEventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestManager_Subscribe(t *testing.T) {
+	m, err := NewManager(&mockBus{})
+	require.NoError(t, err)
+
+	_, adminEvents, unsubscribeAdmin := m.Subscribe(userContext("admin", true), domain.StreamEventFilter{}, nil)
+	defer unsubscribeAdmin()
+	_, aliceEvents, unsubscribeAlice := m.Subscribe(userContext("alice", false), domain.StreamEventFilter{}, nil)
+	defer unsubscribeAlice()
+	_, auditEvents, unsubscribeAudit := m.Subscribe(userContext("admin", true),
+		domain.StreamEventFilter{Types: []string{"audit:"}}, nil)
+	defer unsubscribeAudit()
+
+	m.handlePeerEvent(domain.StreamEventPeerCreated)(domain.Peer{Identifier: "peer-a", UserIdentifier: "alice"})
+	m.handlePeerEvent(domain.StreamEventPeerCreated)(domain.Peer{Identifier: "peer-b", UserIdentifier: "bob"})
+	m.handlePeerStateChangeEvent(domain.PeerStatus{PeerId: "peer-a", IsConnected: true},
+		domain.Peer{Identifier: "peer-a", UserIdentifier: "alice"})
+	m.handleInterfaceEvent(domain.StreamEventInterfaceUpdated)(domain.Interface{Identifier: "wg0"})
+	m.handleAuditEntryEvent(domain.AuditEntry{Message: "peer-a updated"})
+
+	assert.Len(t, adminEvents, 5)
+	require.Len(t, aliceEvents, 2)
+	assert.Equal(t, domain.StreamEventPeerCreated, (<-aliceEvents).Type)
+	assert.Equal(t, domain.StreamEventPeerConnected, (<-aliceEvents).Type)
+	require.Len(t, auditEvents, 1)
+	assert.Equal(t, domain.StreamEventAuditEntry, (<-auditEvents).Type)
+
+	unsubscribeAlice()
+	_, ok := <-aliceEvents
+	assert.False(t, ok, "unsubscribe closes the channel")
+}
+
+func TestManager_SubscribeResume(t *testing.T) {
+	m, err := NewManager(&mockBus{})
+	require.NoError(t, err)
+	ctx := userContext("alice", false)
+
+	for _, user := range []domain.UserIdentifier{"alice", "bob", "alice"} {
+		m.handlePeerEvent(domain.StreamEventPeerUpdated)(domain.Peer{UserIdentifier: user})
+	}
+	first := m.firstId
+
+	replay, _, unsubscribe := m.Subscribe(ctx, domain.StreamEventFilter{}, &first)
+	unsubscribe()
+	require.Len(t, replay, 1, "only the own events after the given id")
+	assert.Equal(t, first+2, replay[0].Id)
+
+	last := m.lastId
+	replay, _, unsubscribe = m.Subscribe(ctx, domain.StreamEventFilter{}, &last)
+	unsubscribe()
+	assert.Empty(t, replay)
+
+	unknown := uint64(42)
+	replay, _, unsubscribe = m.Subscribe(ctx, domain.StreamEventFilter{}, &unknown)
+	unsubscribe()
+	assert.Equal(t, []domain.StreamEventType{domain.StreamEventReset}, eventTypes(replay))
+	assert.Equal(t, m.lastId, replay[0].Id)
+
+	for i := 0; i < bufferSize; i++ {
+		m.handlePeerEvent(domain.StreamEventPeerUpdated)(domain.Peer{UserIdentifier: "alice"})
+	}
+	replay, _, unsubscribe = m.Subscribe(ctx, domain.StreamEventFilter{}, &first)
+	unsubscribe()
+	assert.Equal(t, []domain.StreamEventType{domain.StreamEventReset}, eventTypes(replay), "events overwritten")
+
+	oldest := m.lastId - bufferSize
+	replay, _, unsubscribe = m.Subscribe(ctx, domain.StreamEventFilter{}, &oldest)
+	unsubscribe()
+	assert.Len(t, replay, bufferSize)
+}
+
+func TestManager_SlowSubscriber(t *testing.T) {
+	m, err := NewManager(&mockBus{})
+	require.NoError(t, err)
+
+	_, events, unsubscribe := m.Subscribe(userContext("admin", true), domain.StreamEventFilter{}, nil)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberQueueSize; i++ {
+		m.handleInterfaceEvent(domain.StreamEventInterfaceUpdated)(domain.Interface{Identifier: "wg0"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, subscriberQueueSize, received, "the channel is closed once the queue is full")
+	assert.Empty(t, m.subscribers)
+}
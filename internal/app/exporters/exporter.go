@@ -0,0 +1,158 @@
+package exporters
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/config"
+)
+
+const (
+	// defaultBufferSize is the number of queued events of an exporter, if no buffer size is configured.
+	defaultBufferSize = 1000
+	// publishTimeout is the maximum time to wait for the broker to accept a message.
+	publishTimeout = 10 * time.Second
+	// maxConnectInterval is the maximum delay between two attempts to connect to the broker.
+	maxConnectInterval = 1 * time.Minute
+)
+
+// publisher sends events to a message broker.
+type publisher interface {
+	// Connect connects to the broker. Once connected, the client re-establishes lost connections by itself.
+	Connect() error
+	// Publish sends the event to the broker.
+	Publish(event Event) error
+	// Close disconnects from the broker.
+	Close()
+}
+
+// exporter publishes the events in the background. Events are dropped while its queue is full, so that an
+// unavailable broker never blocks the message bus.
+type exporter struct {
+	name      string
+	events    []string // exported event types or type prefixes, all events are exported if empty
+	publisher publisher
+	queue     chan Event
+	dropped   atomic.Uint64
+}
+
+func newExporter(cfg config.EventExporter) (*exporter, error) {
+	var p publisher
+	var err error
+	switch cfg.Type {
+	case config.EventExporterMqtt:
+		p, err = newMqttPublisher(cfg)
+	case config.EventExporterNats:
+		p, err = newNatsPublisher(cfg)
+	default:
+		err = fmt.Errorf("unknown exporter type %s", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	return &exporter{
+		name:      cfg.Name,
+		events:    cfg.Events,
+		publisher: p,
+		queue:     make(chan Event, bufferSize),
+	}, nil
+}
+
+// wants returns true if the event type is exported.
+func (e *exporter) wants(event Event) bool {
+	if len(e.events) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(e.events, func(t string) bool {
+		return strings.HasPrefix(event.Type, t)
+	})
+}
+
+// enqueue queues the event for the exporter, it never blocks.
+func (e *exporter) enqueue(event Event) {
+	select {
+	case e.queue <- event:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// run connects to the broker and publishes the queued events until the context is cancelled.
+func (e *exporter) run(ctx context.Context) {
+	if !e.connect(ctx) {
+		return
+	}
+	defer e.publisher.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-e.queue:
+			if dropped := e.dropped.Swap(0); dropped > 0 {
+				slog.Warn("exporter queue was full, events have been dropped", "exporter", e.name, "count", dropped)
+			}
+			if err := e.publisher.Publish(event); err != nil {
+				slog.Warn("failed to export event", "exporter", e.name, "event", event.Type, "error", err)
+			}
+		}
+	}
+}
+
+// connect tries to connect to the broker until it succeeds or the context is cancelled. The delay between two
+// attempts doubles, up to one minute.
+func (e *exporter) connect(ctx context.Context) bool {
+	interval := time.Second
+	for {
+		err := e.publisher.Connect()
+		if err == nil {
+			slog.Debug("exporter connected", "exporter", e.name)
+			return true
+		}
+		slog.Warn("failed to connect exporter", "exporter", e.name, "retry", interval, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+		interval = min(2*interval, maxConnectInterval)
+	}
+}
+
+// newTlsConfig returns the TLS configuration of the broker connection, or nil if the defaults should be used.
+func newTlsConfig(cfg config.EventExporter) (*tls.Config, error) {
+	if cfg.TlsCaFile == "" && !cfg.TlsSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.TlsSkipVerify}
+	if cfg.TlsCaFile != "" {
+		caCert, err := os.ReadFile(cfg.TlsCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("CA certificate file contains no PEM certificate")
+		}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,179 @@
+package exporters
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/app/webhooks/models"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// region dependencies
+
+type EventBus interface {
+	// Subscribe subscribes to a topic
+	Subscribe(topic string, fn interface{}) error
+}
+
+// endregion dependencies
+
+const (
+	EventUserCreated       = "user:created"
+	EventUserUpdated       = "user:updated"
+	EventUserDeleted       = "user:deleted"
+	EventPeerCreated       = "peer:created"
+	EventPeerUpdated       = "peer:updated"
+	EventPeerDeleted       = "peer:deleted"
+	EventPeerConnected     = "peer:connected"
+	EventPeerDisconnected  = "peer:disconnected"
+	EventPeerExpiring      = "peer:expiring"
+	EventInterfaceCreated  = "interface:created"
+	EventInterfaceUpdated  = "interface:updated"
+	EventInterfaceDeleted  = "interface:deleted"
+	EventAuditEntryCreated = "audit:entry"
+)
+
+// Event is the JSON message of an exported event. The payload uses the models of the webhooks, without key material.
+type Event struct {
+	// Type is the event type, for example peer:connected.
+	Type string `json:"type"`
+	// Entity is the entity type: user, peer, interface or audit.
+	Entity string `json:"entity"`
+	// Identifier is the identifier of the entity.
+	Identifier string    `json:"identifier"`
+	Timestamp  time.Time `json:"timestamp"`
+	Payload    any       `json:"payload"`
+}
+
+// Manager publishes the events of the message bus to the configured MQTT and NATS exporters.
+type Manager struct {
+	bus       EventBus
+	exporters []*exporter
+}
+
+// NewManager creates a new exporter manager instance. The manager only subscribes to the message bus if at least one
+// exporter is configured.
+func NewManager(cfg *config.Config, bus EventBus) (*Manager, error) {
+	m := &Manager{bus: bus}
+
+	for _, exporterCfg := range cfg.Exporters {
+		e, err := newExporter(exporterCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup exporter %s: %w", exporterCfg.Name, err)
+		}
+		m.exporters = append(m.exporters, e)
+	}
+	if len(m.exporters) == 0 {
+		return m, nil // noting to do
+	}
+
+	if err := m.connectToMessageBus(); err != nil {
+		return nil, fmt.Errorf("failed to setup message bus: %w", err)
+	}
+
+	return m, nil
+}
+
+// StartBackgroundJobs connects the exporters to their brokers, each exporter publishes its events in its own
+// goroutine.
+// This method is non-blocking and returns immediately.
+func (m *Manager) StartBackgroundJobs(ctx context.Context) {
+	for _, e := range m.exporters {
+		go e.run(ctx)
+	}
+}
+
+func (m *Manager) connectToMessageBus() error {
+	subscriptions := map[string]any{
+		app.TopicUserCreated:       m.handleUserEvent(EventUserCreated),
+		app.TopicUserUpdated:       m.handleUserEvent(EventUserUpdated),
+		app.TopicUserDeleted:       m.handleUserEvent(EventUserDeleted),
+		app.TopicPeerCreated:       m.handlePeerEvent(EventPeerCreated),
+		app.TopicPeerUpdated:       m.handlePeerEvent(EventPeerUpdated),
+		app.TopicPeerDeleted:       m.handlePeerEvent(EventPeerDeleted),
+		app.TopicPeerStateChanged:  m.handlePeerStateChangeEvent,
+		app.TopicPeerExpiring:      m.handlePeerExpiringEvent,
+		app.TopicInterfaceCreated:  m.handleInterfaceEvent(EventInterfaceCreated),
+		app.TopicInterfaceUpdated:  m.handleInterfaceEvent(EventInterfaceUpdated),
+		app.TopicInterfaceDeleted:  m.handleInterfaceEvent(EventInterfaceDeleted),
+		app.TopicAuditEntryCreated: m.handleAuditEntryEvent,
+	}
+	for topic, fn := range subscriptions {
+		if err := m.bus.Subscribe(topic, fn); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) handleUserEvent(eventType string) func(user domain.User) {
+	return func(user domain.User) {
+		m.publish(eventType, "user", string(user.Identifier), models.NewUser(user))
+	}
+}
+
+func (m *Manager) handlePeerEvent(eventType string) func(peer domain.Peer) {
+	return func(peer domain.Peer) {
+		m.publish(eventType, "peer", string(peer.Identifier), newPeer(peer))
+	}
+}
+
+// handlePeerExpiringEvent publishes the expiry reminder of a peer, the self-service extension link is not exported.
+func (m *Manager) handlePeerExpiringEvent(peer domain.Peer, _ string) {
+	m.publish(EventPeerExpiring, "peer", string(peer.Identifier), newPeer(peer))
+}
+
+func (m *Manager) handlePeerStateChangeEvent(peerStatus domain.PeerStatus, peer domain.Peer) {
+	eventType := EventPeerDisconnected
+	if peerStatus.IsConnected {
+		eventType = EventPeerConnected
+	}
+
+	metrics := models.NewPeerMetrics(peerStatus, peer)
+	metrics.Peer = newPeer(peer)
+	m.publish(eventType, "peer", string(peer.Identifier), metrics)
+}
+
+func (m *Manager) handleInterfaceEvent(eventType string) func(iface domain.Interface) {
+	return func(iface domain.Interface) {
+		model := models.NewInterface(iface)
+		model.PrivateKey = ""
+		m.publish(eventType, "interface", string(iface.Identifier), model)
+	}
+}
+
+func (m *Manager) handleAuditEntryEvent(entry domain.AuditEntry) {
+	m.publish(EventAuditEntryCreated, "audit", strconv.FormatUint(entry.UniqueId, 10), entry)
+}
+
+// newPeer returns the webhook model of the peer without its private and preshared key, brokers are often shared
+// with other applications.
+func newPeer(peer domain.Peer) models.Peer {
+	model := models.NewPeer(peer)
+	model.PrivateKey = ""
+	model.PresharedKey = ""
+
+	return model
+}
+
+// publish queues the event for all exporters that export its type.
+func (m *Manager) publish(eventType, entity, identifier string, payload any) {
+	event := Event{
+		Type:       eventType,
+		Entity:     entity,
+		Identifier: identifier,
+		Timestamp:  time.Now(),
+		Payload:    payload,
+	}
+
+	for _, e := range m.exporters {
+		if e.wants(event) {
+			e.enqueue(event)
+		}
+	}
+}
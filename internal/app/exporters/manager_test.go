@@ -0,0 +1,143 @@
+package exporters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockBus struct{}
+
+func (f *mockBus) Subscribe(string, interface{}) error { return nil }
+
+// startMqttBroker starts an embedded MQTT broker and returns its address.
+func startMqttBroker(t *testing.T) (*mochi.Server, string) {
+	t.Helper()
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	require.NoError(t, server.AddHook(new(auth.AllowHook), nil))
+	listener := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	require.NoError(t, server.AddListener(listener))
+	require.NoError(t, server.Serve())
+	t.Cleanup(func() { _ = server.Close() })
+
+	return server, listener.Address()
+}
+
+func receive[T any](t *testing.T, messages <-chan T) T {
+	t.Helper()
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+	panic("unreachable")
+}
+
+func TestManager_Mqtt(t *testing.T) {
+	server, address := startMqttBroker(t)
+	messages := make(chan packets.Packet, 10)
+	require.NoError(t, server.Subscribe("wg-portal/peer/+", 1, func(_ *mochi.Client, _ packets.Subscription,
+		pk packets.Packet) {
+		messages <- pk
+	}))
+
+	cfg := &config.Config{Exporters: []config.EventExporter{{Name: "home", Type: config.EventExporterMqtt,
+		Url: "tcp://" + address, Qos: 1}}}
+	m, err := NewManager(cfg, &mockBus{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.StartBackgroundJobs(ctx)
+
+	peer := domain.Peer{Identifier: "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg+/=", UserIdentifier: "alice",
+		PresharedKey: "preshared-secret"}
+	peer.Interface.KeyPair.PrivateKey = "private-secret"
+	m.handlePeerStateChangeEvent(domain.PeerStatus{PeerId: peer.Identifier, IsConnected: true}, peer)
+
+	message := receive(t, messages)
+	assert.Equal(t, "wg-portal/peer/connected", message.TopicName)
+	assert.NotContains(t, string(message.Payload), "secret", "key material must not be exported")
+	var event Event
+	require.NoError(t, json.Unmarshal(message.Payload, &event))
+	assert.Equal(t, EventPeerConnected, event.Type)
+	assert.Equal(t, string(peer.Identifier), event.Identifier)
+
+	presenceTopic := "wg-portal/peer/xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg-_=/presence"
+	assert.Eventually(t, func() bool {
+		retained := server.Topics.Retained.GetAll()
+		return string(retained[presenceTopic].Payload) == "online" &&
+			string(retained["wg-portal/status"].Payload) == "online"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// deleting the peer removes its retained presence
+	m.handlePeerEvent(EventPeerDeleted)(peer)
+	assert.Equal(t, "wg-portal/peer/deleted", receive(t, messages).TopicName)
+	assert.Eventually(t, func() bool {
+		_, ok := server.Topics.Retained.GetAll()[presenceTopic]
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_Nats(t *testing.T) {
+	server := natsserver.RunRandClientPortServer()
+	defer server.Shutdown()
+
+	conn, err := nats.Connect(server.ClientURL())
+	require.NoError(t, err)
+	defer conn.Close()
+	messages := make(chan *nats.Msg, 10)
+	_, err = conn.ChanSubscribe("events.>", messages)
+	require.NoError(t, err)
+	require.NoError(t, conn.Flush())
+
+	cfg := &config.Config{Exporters: []config.EventExporter{{Name: "backbone", Type: config.EventExporterNats,
+		Url: server.ClientURL(), Prefix: "events", Events: []string{"audit:", "interface:deleted"}}}}
+	m, err := NewManager(cfg, &mockBus{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.StartBackgroundJobs(ctx)
+
+	m.handleInterfaceEvent(EventInterfaceUpdated)(domain.Interface{Identifier: "wg0"})
+	m.handleInterfaceEvent(EventInterfaceDeleted)(domain.Interface{Identifier: "wg0"})
+	m.handleAuditEntryEvent(domain.AuditEntry{UniqueId: 42, Message: "wg0 deleted"})
+
+	message := receive(t, messages)
+	assert.Equal(t, "events.interface.deleted", message.Subject, "filtered events are not exported")
+	message = receive(t, messages)
+	assert.Equal(t, "events.audit.entry", message.Subject)
+	var event Event
+	require.NoError(t, json.Unmarshal(message.Data, &event))
+	assert.Equal(t, "audit", event.Entity)
+	assert.Equal(t, "42", event.Identifier)
+}
+
+func TestExporter_DropsEventsWhenFull(t *testing.T) {
+	e := &exporter{name: "test", queue: make(chan Event, 2)}
+
+	// the exporter is not running, so the queue is never drained and enqueue must not block
+	for i := 0; i < 5; i++ {
+		e.enqueue(Event{Type: EventPeerUpdated})
+	}
+	assert.Len(t, e.queue, 2)
+	assert.Equal(t, uint64(3), e.dropped.Load())
+}
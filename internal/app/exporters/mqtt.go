@@ -0,0 +1,112 @@
+package exporters
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/biezax/wg-portal/internal/config"
+)
+
+// mqttTopicEscaper replaces the characters of peer identifiers that are not allowed in a topic level.
+var mqttTopicEscaper = strings.NewReplacer("/", "_", "+", "-", "#", "_")
+
+// mqttPublisher publishes events to <prefix>/<entity>/<event>, for example wg-portal/peer/connected. The presence of
+// each peer is published as retained message online or offline to <prefix>/peer/<identifier>/presence, and the
+// availability of WireGuard Portal itself to <prefix>/status.
+type mqttPublisher struct {
+	client mqtt.Client
+	prefix string
+	qos    byte
+}
+
+func newMqttPublisher(cfg config.EventExporter) (*mqttPublisher, error) {
+	tlsConfig, err := newTlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &mqttPublisher{
+		prefix: strings.TrimSuffix(cfg.Prefix, "/"),
+		qos:    byte(cfg.Qos),
+	}
+	if p.prefix == "" {
+		p.prefix = "wg-portal"
+	}
+	clientId := cfg.ClientId
+	if clientId == "" {
+		clientId = "wg-portal"
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Url).
+		SetClientID(clientId).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetConnectTimeout(publishTimeout).
+		SetAutoReconnect(true).
+		SetWill(p.statusTopic(), "offline", p.qos, true).
+		SetOnConnectHandler(func(client mqtt.Client) {
+			client.Publish(p.statusTopic(), p.qos, true, "online")
+		})
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+	p.client = mqtt.NewClient(opts)
+
+	return p, nil
+}
+
+func (p *mqttPublisher) Connect() error {
+	return waitForToken(p.client.Connect())
+}
+
+func (p *mqttPublisher) Publish(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := p.publish(p.prefix+"/"+strings.ReplaceAll(event.Type, ":", "/"), false, payload); err != nil {
+		return err
+	}
+
+	presenceTopic := p.prefix + "/peer/" + mqttTopicEscaper.Replace(event.Identifier) + "/presence"
+	switch event.Type {
+	case EventPeerConnected:
+		return p.publish(presenceTopic, true, []byte("online"))
+	case EventPeerDisconnected:
+		return p.publish(presenceTopic, true, []byte("offline"))
+	case EventPeerDeleted:
+		return p.publish(presenceTopic, true, nil) // an empty retained message removes the retained presence
+	}
+
+	return nil
+}
+
+func (p *mqttPublisher) publish(topic string, retained bool, payload []byte) error {
+	return waitForToken(p.client.Publish(topic, p.qos, retained, payload))
+}
+
+// Close marks WireGuard Portal as offline and disconnects from the broker.
+func (p *mqttPublisher) Close() {
+	if !p.client.IsConnected() {
+		return
+	}
+
+	_ = p.publish(p.statusTopic(), true, []byte("offline"))
+	p.client.Disconnect(1000) // wait up to one second for pending messages
+}
+
+func (p *mqttPublisher) statusTopic() string {
+	return p.prefix + "/status"
+}
+
+// waitForToken waits until the MQTT operation of the token has been completed.
+func waitForToken(token mqtt.Token) error {
+	if !token.WaitTimeout(publishTimeout) {
+		return errors.New("timeout while waiting for the MQTT broker")
+	}
+	return token.Error()
+}
@@ -0,0 +1,79 @@
+package exporters
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/biezax/wg-portal/internal/config"
+)
+
+// natsPublisher publishes events to the subject <prefix>.<entity>.<event>, for example wgportal.peer.connected.
+type natsPublisher struct {
+	url    string
+	opts   []nats.Option
+	prefix string
+
+	conn *nats.Conn
+}
+
+func newNatsPublisher(cfg config.EventExporter) (*natsPublisher, error) {
+	tlsConfig, err := newTlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &natsPublisher{
+		url:    cfg.Url,
+		prefix: strings.TrimSuffix(cfg.Prefix, "."),
+		opts: []nats.Option{
+			nats.Name("wg-portal"),
+			nats.Timeout(publishTimeout),
+			nats.MaxReconnects(-1), // reconnect forever
+		},
+	}
+	if p.prefix == "" {
+		p.prefix = "wgportal"
+	}
+	if cfg.Username != "" {
+		p.opts = append(p.opts, nats.UserInfo(cfg.Username, cfg.Password))
+	}
+	if cfg.CredentialsFile != "" {
+		p.opts = append(p.opts, nats.UserCredentials(cfg.CredentialsFile))
+	}
+	if tlsConfig != nil {
+		p.opts = append(p.opts, nats.Secure(tlsConfig))
+	}
+
+	return p, nil
+}
+
+func (p *natsPublisher) Connect() error {
+	conn, err := nats.Connect(p.url, p.opts...)
+	if err != nil {
+		return err
+	}
+	p.conn = conn
+
+	return nil
+}
+
+func (p *natsPublisher) Publish(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.conn.Publish(p.prefix+"."+strings.ReplaceAll(event.Type, ":", "."), payload)
+}
+
+// Close sends the buffered messages and disconnects from the server.
+func (p *natsPublisher) Close() {
+	if p.conn == nil {
+		return
+	}
+
+	_ = p.conn.FlushTimeout(publishTimeout)
+	p.conn.Close()
+}
@@ -6,13 +6,20 @@ import (
 	"io"
 	"log/slog"
 	"net/mail"
+	"sync/atomic"
 
+	"github.com/biezax/wg-portal/internal/app"
 	"github.com/biezax/wg-portal/internal/config"
 	"github.com/biezax/wg-portal/internal/domain"
 )
 
 // region dependencies
 
+type EventBus interface {
+	// Subscribe subscribes to the given topic.
+	Subscribe(topic string, fn any) error
+}
+
 type Mailer interface {
 	// Send sends an email with the given subject and body to the given recipients.
 	Send(ctx context.Context, subject, body string, to []string, options *domain.MailOptions) error
@@ -50,12 +57,21 @@ type TemplateRenderer interface {
 		io.Reader,
 		error,
 	)
+	// GetExpiryReminderMail returns the text and html template for the mail that reminds the user of an expiring peer.
+	GetExpiryReminderMail(user *domain.User, peer *domain.Peer, extensionLink string, extensionDays int) (
+		io.Reader,
+		io.Reader,
+		error,
+	)
 }
 
 // endregion dependencies
 
 type Manager struct {
 	cfg *config.Config
+	bus EventBus
+
+	allowPeerEmail *atomic.Bool // can be changed by reloading the configuration
 
 	tplHandler  TemplateRenderer
 	mailer      Mailer
@@ -67,6 +83,7 @@ type Manager struct {
 // NewMailManager creates a new mail manager.
 func NewMailManager(
 	cfg *config.Config,
+	bus EventBus,
 	mailer Mailer,
 	configFiles ConfigFileManager,
 	users UserDatabaseRepo,
@@ -78,17 +95,41 @@ func NewMailManager(
 	}
 
 	m := &Manager{
-		cfg:         cfg,
-		tplHandler:  tplHandler,
-		mailer:      mailer,
-		configFiles: configFiles,
-		users:       users,
-		wg:          wg,
+		cfg:            cfg,
+		bus:            bus,
+		allowPeerEmail: &atomic.Bool{},
+		tplHandler:     tplHandler,
+		mailer:         mailer,
+		configFiles:    configFiles,
+		users:          users,
+		wg:             wg,
 	}
 
+	m.allowPeerEmail.Store(cfg.Mail.AllowPeerEmail)
+
+	m.connectToMessageBus()
+
 	return m, nil
 }
 
+func (m Manager) connectToMessageBus() {
+	_ = m.bus.Subscribe(app.TopicPeerExpiring, m.handlePeerExpiringEvent)
+}
+
+func (m Manager) handlePeerExpiringEvent(peer domain.Peer, extensionLink string) {
+	ctx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+
+	if err := m.sendExpiryReminder(ctx, &peer, extensionLink); err != nil {
+		slog.Error("failed to send expiry reminder mail", "peer", peer.Identifier, "error", err)
+	}
+}
+
+// ReloadConfig applies the allow_peer_email setting of the given configuration.
+func (m Manager) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	m.allowPeerEmail.Store(cfg.Mail.AllowPeerEmail)
+	return nil
+}
+
 // SendPeerEmail sends an email to the user linked to the given peers.
 func (m Manager) SendPeerEmail(ctx context.Context, linkOnly bool, style string, peers ...domain.PeerIdentifier) error {
 	for _, peerId := range peers {
@@ -182,10 +223,44 @@ func (m Manager) sendPeerEmail(
 	return nil
 }
 
+// sendExpiryReminder sends the expiry reminder of the peer to its user, peers without a valid email address are
+// skipped.
+func (m Manager) sendExpiryReminder(ctx context.Context, peer *domain.Peer, extensionLink string) error {
+	email, user := m.resolveEmail(ctx, peer)
+	if email == "" {
+		return nil
+	}
+
+	var extensionDays int
+	if extensionLink != "" {
+		iface, err := m.wg.GetInterface(ctx, peer.InterfaceIdentifier)
+		if err != nil {
+			return fmt.Errorf("failed to fetch interface %s: %w", peer.InterfaceIdentifier, err)
+		}
+		extensionDays = iface.PeerExtensionDays
+	}
+
+	txtMail, htmlMail, err := m.tplHandler.GetExpiryReminderMail(&user, peer, extensionLink, extensionDays)
+	if err != nil {
+		return fmt.Errorf("failed to get mail body: %w", err)
+	}
+
+	txtMailStr, _ := io.ReadAll(txtMail)
+	htmlMailStr, _ := io.ReadAll(htmlMail)
+
+	err = m.mailer.Send(ctx, "WireGuard VPN Peer Expires Soon", string(txtMailStr), []string{email},
+		&domain.MailOptions{HtmlBody: string(htmlMailStr)})
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
 func (m Manager) resolveEmail(ctx context.Context, peer *domain.Peer) (string, domain.User) {
 	user, err := m.users.GetUser(ctx, peer.UserIdentifier)
 	if err != nil {
-		if m.cfg.Mail.AllowPeerEmail {
+		if m.allowPeerEmail.Load() {
 			_, err := mail.ParseAddress(string(peer.UserIdentifier)) // test if the user identifier is a valid email address
 			if err == nil {
 				slog.Debug("peer email: using user-identifier as email",
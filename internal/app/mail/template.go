@@ -7,6 +7,7 @@ import (
 	htmlTemplate "html/template"
 	"io"
 	"text/template"
+	"time"
 
 	"github.com/biezax/wg-portal/internal/domain"
 )
@@ -102,3 +103,41 @@ func (c TemplateHandler) GetConfigMailWithAttachment(user *domain.User, cfgName,
 
 	return &tplBuff, &htmlTplBuff, nil
 }
+
+// GetExpiryReminderMail returns the text and html template for the mail that reminds the user of an expiring peer.
+// The extension link is optional, extensionDays is the number of days the link extends the peer by.
+func (c TemplateHandler) GetExpiryReminderMail(
+	user *domain.User,
+	peer *domain.Peer,
+	extensionLink string,
+	extensionDays int,
+) (io.Reader, io.Reader, error) {
+	var tplBuff bytes.Buffer
+	var htmlTplBuff bytes.Buffer
+
+	var expiresAt string
+	if peer.ExpiresAt != nil {
+		expiresAt = peer.ExpiresAt.Format(time.DateOnly)
+	}
+	data := map[string]any{
+		"User":          user,
+		"Peer":          peer,
+		"ExpiresAt":     expiresAt,
+		"ExtensionLink": extensionLink,
+		"ExtensionDays": extensionDays,
+		"PortalUrl":     c.portalUrl,
+		"PortalName":    c.portalName,
+	}
+
+	err := c.textTemplates.ExecuteTemplate(&tplBuff, "mail_expiry_reminder.gotpl", data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute template mail_expiry_reminder.gotpl: %w", err)
+	}
+
+	err = c.htmlTemplates.ExecuteTemplate(&htmlTplBuff, "mail_expiry_reminder.gohtml", data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute template mail_expiry_reminder.gohtml: %w", err)
+	}
+
+	return &tplBuff, &htmlTplBuff, nil
+}
@@ -0,0 +1,216 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+const firezoneAdminRole = "admin"
+
+// firezoneUser is a record of the users table of Firezone (0.7).
+type firezoneUser struct {
+	Id         string     `gorm:"column:id;primaryKey"`
+	Email      string     `gorm:"column:email"`
+	Role       string     `gorm:"column:role"`
+	DisabledAt *time.Time `gorm:"column:disabled_at"`
+	InsertedAt time.Time  `gorm:"column:inserted_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+}
+
+func (firezoneUser) TableName() string { return "users" }
+
+// firezoneDevice is a record of the devices table of Firezone (0.7). Postgres arrays are read in their text form.
+type firezoneDevice struct {
+	Id                            string    `gorm:"column:id;primaryKey"`
+	Name                          string    `gorm:"column:name"`
+	Description                   *string   `gorm:"column:description"`
+	PublicKey                     string    `gorm:"column:public_key"`
+	PresharedKey                  *string   `gorm:"column:preshared_key"`
+	UseDefaultAllowedIps          bool      `gorm:"column:use_default_allowed_ips"`
+	AllowedIps                    *string   `gorm:"column:allowed_ips"`
+	UseDefaultDns                 bool      `gorm:"column:use_default_dns"`
+	Dns                           *string   `gorm:"column:dns"`
+	UseDefaultEndpoint            bool      `gorm:"column:use_default_endpoint"`
+	Endpoint                      *string   `gorm:"column:endpoint"`
+	UseDefaultMtu                 bool      `gorm:"column:use_default_mtu"`
+	Mtu                           *int      `gorm:"column:mtu"`
+	UseDefaultPersistentKeepalive bool      `gorm:"column:use_default_persistent_keepalive"`
+	PersistentKeepalive           *int      `gorm:"column:persistent_keepalive"`
+	Ipv4                          *string   `gorm:"column:ipv4"`
+	Ipv6                          *string   `gorm:"column:ipv6"`
+	UserId                        string    `gorm:"column:user_id"`
+	InsertedAt                    time.Time `gorm:"column:inserted_at"`
+	UpdatedAt                     time.Time `gorm:"column:updated_at"`
+}
+
+func (firezoneDevice) TableName() string { return "devices" }
+
+// firezoneConfiguration is the single record of the configurations table of Firezone (0.7), it contains the
+// defaults for all devices.
+type firezoneConfiguration struct {
+	DefaultClientAllowedIps          *string `gorm:"column:default_client_allowed_ips"`
+	DefaultClientDns                 *string `gorm:"column:default_client_dns"`
+	DefaultClientEndpoint            *string `gorm:"column:default_client_endpoint"`
+	DefaultClientMtu                 *int    `gorm:"column:default_client_mtu"`
+	DefaultClientPersistentKeepalive *int    `gorm:"column:default_client_persistent_keepalive"`
+}
+
+func (firezoneConfiguration) TableName() string { return "configurations" }
+
+// loadFirezone maps the users and devices of a Firezone database to users and peers. Users are identified by their
+// email address. Firezone does not store the private keys of devices, and the server key is stored outside the
+// database (/var/firezone/private_key).
+func loadFirezone(ctx context.Context, db *gorm.DB) (*sourceData, error) {
+	db = db.WithContext(ctx)
+
+	var users []firezoneUser
+	if err := db.Order("inserted_at, email").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("unable to read Firezone users: %w", err)
+	}
+	var devices []firezoneDevice
+	if err := db.Order("inserted_at, name").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("unable to read Firezone devices: %w", err)
+	}
+
+	data := &sourceData{
+		Interface: sourceInterface{
+			Identifier: "wg-firezone",
+		},
+		Warnings: []string{"Firezone passwords cannot be migrated, imported users have to log in through an " +
+			"external authentication provider or get a new password"},
+	}
+	// the Firezone default networks, WIREGUARD_IPV4_NETWORK and WIREGUARD_IPV6_NETWORK
+	data.Interface.Addresses, _ = domain.ParseWgQuickCidrs("10.3.2.1/24, fd00::3:2:1/120")
+
+	if db.Migrator().HasTable(&firezoneConfiguration{}) {
+		var configuration firezoneConfiguration
+		if err := db.Limit(1).Find(&configuration).Error; err != nil {
+			return nil, fmt.Errorf("unable to read Firezone configuration: %w", err)
+		}
+		data.Interface.PeerDefAllowedIPs = strings.Join(parsePostgresArray(configuration.DefaultClientAllowedIps), ", ")
+		data.Interface.PeerDefDns = strings.Join(parsePostgresArray(configuration.DefaultClientDns), ", ")
+		data.Interface.PeerDefEndpoint = valueOrZero(configuration.DefaultClientEndpoint)
+		data.Interface.PeerDefMtu = valueOrZero(configuration.DefaultClientMtu)
+		data.Interface.PeerDefPersistentKeepalive = valueOrZero(configuration.DefaultClientPersistentKeepalive)
+	} else {
+		data.Warnings = append(data.Warnings, "Firezone configuration not found, using the default client settings")
+	}
+
+	currentUser := domain.GetUserInfo(ctx)
+	userIndex := make(map[string]int, len(users))
+	for _, fzUser := range users {
+		if fzUser.Email == "" {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("user %s has no email address, skipping it", fzUser.Id))
+			continue
+		}
+		user := domain.User{
+			BaseModel: domain.BaseModel{
+				CreatedBy: string(currentUser.Id),
+				UpdatedBy: string(currentUser.Id),
+				CreatedAt: fzUser.InsertedAt,
+				UpdatedAt: fzUser.UpdatedAt,
+			},
+			Identifier: domain.UserIdentifier(fzUser.Email),
+			Email:      fzUser.Email,
+			Source:     domain.UserSourceDatabase,
+			IsAdmin:    fzUser.Role == firezoneAdminRole,
+			Notes:      "imported from Firezone",
+		}
+		if fzUser.DisabledAt != nil {
+			user.Disabled = fzUser.DisabledAt
+			user.DisabledReason = "disabled prior to migration"
+		}
+		userIndex[fzUser.Id] = len(data.Users)
+		data.Users = append(data.Users, user)
+	}
+
+	for _, device := range devices {
+		if device.PublicKey == "" {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("device %s has no public key, skipping it", device.Name))
+			continue
+		}
+		addresses, err := domain.ParseWgQuickCidrs(valueOrZero(device.Ipv4) + "," +
+			valueOrZero(device.Ipv6))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address of device %s: %w", device.Name, domain.ErrInvalidData)
+		}
+
+		peer := sourcePeer{
+			Name:         device.Name,
+			Notes:        valueOrZero(device.Description),
+			PublicKey:    device.PublicKey,
+			PresharedKey: valueOrZero(device.PresharedKey),
+			Addresses:    addresses,
+			CreatedAt:    device.InsertedAt,
+			UpdatedAt:    device.UpdatedAt,
+		}
+		if !device.UseDefaultAllowedIps {
+			peer.AllowedIPs = strings.Join(parsePostgresArray(device.AllowedIps), ", ")
+		}
+		if !device.UseDefaultDns {
+			peer.Dns = strings.Join(parsePostgresArray(device.Dns), ", ")
+		}
+		if !device.UseDefaultEndpoint {
+			peer.Endpoint = valueOrZero(device.Endpoint)
+		}
+		if !device.UseDefaultMtu {
+			peer.Mtu = valueOrZero(device.Mtu)
+		}
+		if !device.UseDefaultPersistentKeepalive {
+			peer.PersistentKeepalive = valueOrZero(device.PersistentKeepalive)
+		}
+
+		idx, ok := userIndex[device.UserId]
+		switch {
+		case !ok:
+			data.Warnings = append(data.Warnings,
+				fmt.Sprintf("owner %s of device %s not found, the peer is not linked to a user", device.UserId, device.Name))
+		case data.Users[idx].IsDisabled():
+			peer.User = data.Users[idx].Identifier
+			peer.Disabled = data.Users[idx].Disabled
+			peer.DisabledReason = domain.DisabledReasonUserDisabled
+		default:
+			peer.User = data.Users[idx].Identifier
+		}
+		if err := peer.validateKeys(); err != nil {
+			data.Conflicts = append(data.Conflicts, fmt.Sprintf("device %s: %v", device.Name, err))
+			continue
+		}
+
+		data.Peers = append(data.Peers, peer)
+	}
+
+	return data, nil
+}
+
+// parsePostgresArray parses the text representation of a Postgres array, for example {1.1.1.1,"example.com"}.
+// Values that are not wrapped in braces are treated as comma separated list.
+func parsePostgresArray(value *string) []string {
+	if value == nil {
+		return nil
+	}
+	raw := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(*value), "{"), "}")
+
+	var values []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.Trim(strings.TrimSpace(entry), `"`)
+		if entry != "" && !strings.EqualFold(entry, "NULL") {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
+
+func valueOrZero[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
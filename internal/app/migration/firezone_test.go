@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// newFirezoneTestDatabase creates a database with the Firezone tables, arrays are stored in the Postgres text format.
+func newFirezoneTestDatabase(t *testing.T, withConfiguration bool) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "firezone.db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&firezoneUser{}, &firezoneDevice{}))
+	if withConfiguration {
+		require.NoError(t, db.AutoMigrate(&firezoneConfiguration{}))
+	}
+	return db
+}
+
+func TestLoadFirezone(t *testing.T) {
+	alice, bob, orphan := newTestKey(t).PublicKey(), newTestKey(t).PublicKey(), newTestKey(t).PublicKey()
+	disabledAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	db := newFirezoneTestDatabase(t, true)
+	require.NoError(t, db.Create(&firezoneConfiguration{
+		DefaultClientAllowedIps: ptr("{0.0.0.0/0,::/0}"),
+		DefaultClientDns:        ptr(`{1.1.1.1,"example.com"}`),
+		DefaultClientEndpoint:   ptr("vpn.example.com"),
+		DefaultClientMtu:        ptr(1280),
+	}).Error)
+	require.NoError(t, db.Create(&[]firezoneUser{
+		{Id: "u1", Email: "admin@example.com", Role: "admin", InsertedAt: time.Now()},
+		{Id: "u2", Email: "bob@example.com", Role: "unprivileged", DisabledAt: &disabledAt, InsertedAt: time.Now()},
+	}).Error)
+	require.NoError(t, db.Create(&[]firezoneDevice{
+		{
+			Id: "d1", Name: "alice-laptop", Description: ptr("work laptop"), PublicKey: alice.String(),
+			UseDefaultAllowedIps: false, AllowedIps: ptr("{10.0.0.0/8}"), UseDefaultDns: true,
+			UseDefaultEndpoint: true, UseDefaultMtu: true, UseDefaultPersistentKeepalive: false,
+			PersistentKeepalive: ptr(25), Ipv4: ptr("10.3.2.2"), Ipv6: ptr("fd00::3:2:2"), UserId: "u1",
+			InsertedAt: time.Now(),
+		},
+		{
+			Id: "d2", Name: "bob-phone", PublicKey: bob.String(), UseDefaultAllowedIps: true, UseDefaultDns: true,
+			UseDefaultEndpoint: true, UseDefaultMtu: true, UseDefaultPersistentKeepalive: true,
+			Ipv4: ptr("10.3.2.3"), UserId: "u2", InsertedAt: time.Now().Add(time.Second),
+		},
+		{
+			Id: "d3", Name: "orphan", PublicKey: orphan.String(), UseDefaultAllowedIps: true, UseDefaultDns: true,
+			UseDefaultEndpoint: true, UseDefaultMtu: true, UseDefaultPersistentKeepalive: true,
+			Ipv4: ptr("10.3.2.4"), UserId: "u3", InsertedAt: time.Now().Add(2 * time.Second),
+		},
+	}).Error)
+
+	data, err := loadFirezone(context.Background(), db)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0/0, ::/0", data.Interface.PeerDefAllowedIPs)
+	assert.Equal(t, "1.1.1.1, example.com", data.Interface.PeerDefDns)
+	assert.Equal(t, "vpn.example.com", data.Interface.PeerDefEndpoint)
+	assert.Equal(t, 1280, data.Interface.PeerDefMtu)
+
+	require.Len(t, data.Users, 2)
+	assert.Equal(t, domain.UserIdentifier("admin@example.com"), data.Users[0].Identifier)
+	assert.True(t, data.Users[0].IsAdmin)
+	assert.False(t, data.Users[1].IsAdmin)
+	assert.True(t, data.Users[1].IsDisabled())
+
+	require.Len(t, data.Peers, 3)
+	assert.Equal(t, "alice-laptop", data.Peers[0].Name)
+	assert.Equal(t, "work laptop", data.Peers[0].Notes)
+	assert.Equal(t, domain.UserIdentifier("admin@example.com"), data.Peers[0].User)
+	assert.Equal(t, "10.3.2.2/32,fd00::3:2:2/128", domain.CidrsToString(data.Peers[0].Addresses))
+	assert.Equal(t, "10.0.0.0/8", data.Peers[0].AllowedIPs)
+	assert.Equal(t, 25, data.Peers[0].PersistentKeepalive)
+	assert.Empty(t, data.Peers[0].PrivateKey)
+	assert.Nil(t, data.Peers[0].Disabled)
+
+	assert.Equal(t, domain.UserIdentifier("bob@example.com"), data.Peers[1].User)
+	require.NotNil(t, data.Peers[1].Disabled)
+	assert.Equal(t, domain.DisabledReasonUserDisabled, data.Peers[1].DisabledReason)
+
+	assert.Empty(t, data.Peers[2].User)
+	assert.Contains(t, data.Warnings, "owner u3 of device orphan not found, the peer is not linked to a user")
+}
+
+func TestLoadFirezone_WithoutConfiguration(t *testing.T) {
+	data, err := loadFirezone(context.Background(), newFirezoneTestDatabase(t, false))
+	require.NoError(t, err)
+	assert.Empty(t, data.Peers)
+	assert.Equal(t, "10.3.2.1/24,fd00::3:2:1/120", domain.CidrsToString(data.Interface.Addresses))
+	assert.Contains(t, data.Warnings, "Firezone configuration not found, using the default client settings")
+}
+
+func TestParsePostgresArray(t *testing.T) {
+	assert.Nil(t, parsePostgresArray(nil))
+	assert.Equal(t, []string{"1.1.1.1", "example.com"}, parsePostgresArray(ptr(`{1.1.1.1, "example.com",NULL}`)))
+	assert.Equal(t, []string{"10.0.0.0/8", "::/0"}, parsePostgresArray(ptr("10.0.0.0/8,::/0")))
+	assert.Nil(t, parsePostgresArray(ptr("{}")))
+}
@@ -0,0 +1,537 @@
+package migration
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Biezax/wgctrl/wgtypes"
+	"gorm.io/gorm"
+
+	"github.com/biezax/wg-portal/internal/adapters"
+	"github.com/biezax/wg-portal/internal/app/wireguard"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+const (
+	defaultListenPort          = 51820
+	defaultPersistentKeepalive = 0
+)
+
+// region dependencies
+
+type UserManager interface {
+	// GetUser returns the user with the given identifier.
+	GetUser(ctx context.Context, id domain.UserIdentifier) (*domain.User, error)
+	// CreateUser creates a new user.
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	// DeleteUser deletes the user with the given identifier.
+	DeleteUser(ctx context.Context, id domain.UserIdentifier) error
+}
+
+type WireGuardManager interface {
+	// CreateInterface creates a new interface with the given configuration.
+	CreateInterface(ctx context.Context, in *domain.Interface) (*domain.Interface, error)
+	// CreatePeer creates a new peer.
+	CreatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
+	// DeleteInterface deletes the given interface and all of its peers.
+	DeleteInterface(ctx context.Context, id domain.InterfaceIdentifier) error
+	// FindImportConflicts returns the reasons why the interface and its peers cannot be imported.
+	FindImportConflicts(ctx context.Context, iface *domain.Interface, peers []domain.Peer) ([]string, error)
+}
+
+// endregion dependencies
+
+// Manager imports users, interfaces and peers from other WireGuard management tools.
+type Manager struct {
+	cfg *config.Config
+
+	users UserManager
+	wg    WireGuardManager
+
+	openDatabase func(cfg config.DatabaseConfig) (*gorm.DB, error) // opens the database of the migration source
+}
+
+// NewManager creates a new migration manager instance.
+func NewManager(cfg *config.Config, users UserManager, wg WireGuardManager) *Manager {
+	return &Manager{
+		cfg:          cfg,
+		users:        users,
+		wg:           wg,
+		openDatabase: adapters.NewDatabase,
+	}
+}
+
+// Import creates users, an interface and its peers from the data of another WireGuard management tool.
+// If dryRun is set, or if conflicts are detected, nothing is stored and only the migration plan is returned.
+// A failed migration is rolled back, so that no partially imported interface or users remain.
+func (m Manager) Import(
+	ctx context.Context,
+	req *domain.MigrationRequest,
+	dryRun bool,
+) (*domain.MigrationPlan, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := req.Source.Validate(); err != nil {
+		return nil, err
+	}
+
+	var data *sourceData
+	var err error
+	switch req.Source {
+	case domain.MigrationSourceWgEasy:
+		data, err = parseWgEasy(req.Data)
+	case domain.MigrationSourceFirezone:
+		data, err = m.loadFirezoneDatabase(ctx, req.Dsn)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s data: %w", req.Source, err)
+	}
+
+	plan, err := m.plan(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun || plan.HasConflicts() {
+		return plan, nil
+	}
+
+	if err := m.commit(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	slog.Info("imported data from other WireGuard management tool", "source", plan.Source,
+		"interface", plan.Interface.Identifier, "users", len(plan.Users), "peers", len(plan.Peers))
+
+	return plan, nil
+}
+
+// region helper-functions
+
+// sourceData is the data of a migration source, mapped to the WireGuard Portal data model as far as possible.
+type sourceData struct {
+	Interface sourceInterface
+	Users     []domain.User
+	Peers     []sourcePeer
+	Conflicts []string
+	Warnings  []string
+}
+
+// sourceInterface contains the server settings of a migration source. Empty values fall back to the defaults.
+type sourceInterface struct {
+	Identifier domain.InterfaceIdentifier
+	PrivateKey string
+	PublicKey  string // the public key stored by the source, only used to validate the private key
+	Addresses  []domain.Cidr
+	ListenPort int
+	Mtu        int
+
+	PeerDefEndpoint            string
+	PeerDefDns                 string
+	PeerDefAllowedIPs          string
+	PeerDefMtu                 int
+	PeerDefPersistentKeepalive int
+}
+
+// sourcePeer is a client or device of a migration source. Empty values fall back to the interface defaults.
+type sourcePeer struct {
+	Name         string
+	Notes        string
+	User         domain.UserIdentifier
+	PublicKey    string
+	PrivateKey   string // might be unknown, for example for Firezone devices
+	PresharedKey string
+	Addresses    []domain.Cidr
+
+	Endpoint            string
+	Dns                 string
+	AllowedIPs          string
+	Mtu                 int
+	PersistentKeepalive int
+
+	Disabled       *time.Time
+	DisabledReason string
+	ExpiresAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// validateKeys returns an error if one of the keys of the peer is not a valid WireGuard key. The private and the
+// preshared key are optional.
+func (p sourcePeer) validateKeys() error {
+	if _, err := wgtypes.ParseKey(p.PublicKey); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	if _, err := wgtypes.ParseKey(p.PrivateKey); p.PrivateKey != "" && err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+	if _, err := wgtypes.ParseKey(p.PresharedKey); p.PresharedKey != "" && err != nil {
+		return fmt.Errorf("invalid preshared key: %w", err)
+	}
+	return nil
+}
+
+func (m Manager) loadFirezoneDatabase(ctx context.Context, dsn string) (*sourceData, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("missing Firezone database DSN: %w", domain.ErrInvalidData)
+	}
+
+	db, err := m.openDatabase(config.DatabaseConfig{Type: config.DatabasePostgres, DSN: dsn})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Firezone database: %w: %w", err, domain.ErrInvalidData)
+	}
+	if sqlDb, err := db.DB(); err == nil {
+		defer sqlDb.Close()
+	}
+
+	return loadFirezone(ctx, db)
+}
+
+func (m Manager) plan(
+	ctx context.Context,
+	req *domain.MigrationRequest,
+	data *sourceData,
+) (*domain.MigrationPlan, error) {
+	plan := &domain.MigrationPlan{
+		Source:    req.Source,
+		Conflicts: data.Conflicts,
+		Warnings:  data.Warnings,
+	}
+
+	iface, warnings, err := m.migrationInterface(ctx, req, &data.Interface)
+	if err != nil {
+		return nil, err
+	}
+	plan.Interface = iface
+	plan.Warnings = append(plan.Warnings, warnings...)
+
+	// users that already exist are kept, their peers are assigned to the existing accounts
+	for _, user := range data.Users {
+		existing, err := m.users.GetUser(ctx, user.Identifier)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("unable to load existing user %s: %w", user.Identifier, err)
+		}
+		if existing != nil {
+			plan.Warnings = append(plan.Warnings,
+				fmt.Sprintf("user %s already exists, its peers will be assigned to the existing user", user.Identifier))
+			continue
+		}
+		plan.Users = append(plan.Users, user)
+	}
+
+	if req.UserIdentifier != "" {
+		existing, err := m.users.GetUser(ctx, req.UserIdentifier)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("unable to load user %s: %w", req.UserIdentifier, err)
+		}
+		if existing == nil {
+			plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("user %s does not exist", req.UserIdentifier))
+		}
+	}
+
+	networks := make([]domain.Cidr, len(iface.Addresses))
+	for i, address := range iface.Addresses {
+		networks[i] = address.NetworkAddr()
+	}
+
+	seenKeys := make(map[string]struct{}, len(data.Peers))
+	seenAddresses := make(map[string]string, len(data.Peers))
+	missingPrivateKeys := 0
+	for _, sourcePeer := range data.Peers {
+		if _, seen := seenKeys[sourcePeer.PublicKey]; seen {
+			plan.Conflicts = append(plan.Conflicts,
+				fmt.Sprintf("peer %s is defined multiple times", sourcePeer.PublicKey))
+			continue
+		}
+		seenKeys[sourcePeer.PublicKey] = struct{}{}
+
+		for _, address := range sourcePeer.Addresses {
+			if other, used := seenAddresses[address.Addr]; used {
+				plan.Conflicts = append(plan.Conflicts, fmt.Sprintf("address %s is used by peer %s and peer %s",
+					address.Addr, other, sourcePeer.Name))
+			}
+			seenAddresses[address.Addr] = sourcePeer.Name
+			if !slices.ContainsFunc(networks, func(n domain.Cidr) bool { return n.Contains(address) }) {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("address %s of peer %s is outside of the networks of %s",
+					address.Addr, sourcePeer.Name, iface.Identifier))
+			}
+		}
+
+		if sourcePeer.PrivateKey == "" {
+			missingPrivateKeys++
+		}
+		if sourcePeer.User == "" {
+			sourcePeer.User = req.UserIdentifier
+		}
+
+		plan.Peers = append(plan.Peers, migrationPeer(ctx, iface, &sourcePeer))
+	}
+	if missingPrivateKeys > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+			"the private keys of %d peers are unknown, their configurations cannot be downloaded", missingPrivateKeys))
+	}
+
+	conflicts, err := m.wg.FindImportConflicts(ctx, plan.Interface, plan.Peers)
+	if err != nil {
+		return nil, err
+	}
+	plan.Conflicts = append(plan.Conflicts, conflicts...)
+
+	return plan, nil
+}
+
+func (m Manager) migrationInterface(
+	ctx context.Context,
+	req *domain.MigrationRequest,
+	src *sourceInterface,
+) (*domain.Interface, []string, error) {
+	var warnings []string
+	now := time.Now()
+	currentUser := domain.GetUserInfo(ctx)
+
+	id := src.Identifier
+	if req.Identifier != "" {
+		id = req.Identifier
+	}
+
+	backend := req.Backend
+	if backend == "" {
+		backend = domain.InterfaceBackend(m.cfg.Backend.Default)
+	}
+
+	addresses := src.Addresses
+	if len(req.Addresses) > 0 {
+		addresses = req.Addresses
+	}
+	if len(addresses) == 0 {
+		return nil, nil, fmt.Errorf("missing interface addresses: %w", domain.ErrInvalidData)
+	}
+	networks := make([]domain.Cidr, len(addresses))
+	for i, address := range addresses {
+		networks[i] = address.NetworkAddr()
+	}
+
+	listenPort := cmp.Or(req.ListenPort, src.ListenPort, defaultListenPort)
+
+	privateKey := cmp.Or(req.PrivateKey, src.PrivateKey)
+	var keyPair domain.KeyPair
+	if privateKey == "" {
+		key, err := wgtypes.GeneratePrivateKey()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate private key: %w", err)
+		}
+		keyPair = domain.KeyPair{PrivateKey: key.String(), PublicKey: key.PublicKey().String()}
+		warnings = append(warnings, fmt.Sprintf("no private key given for interface %s, a new key pair has been "+
+			"generated and all clients have to be updated to the new public key %s", id, keyPair.PublicKey))
+	} else {
+		key, err := wgtypes.ParseKey(privateKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid private key: %w", domain.ErrInvalidData)
+		}
+		keyPair = domain.KeyPair{PrivateKey: key.String(), PublicKey: key.PublicKey().String()}
+		if src.PublicKey != "" && src.PublicKey != keyPair.PublicKey {
+			warnings = append(warnings, fmt.Sprintf("the stored public key %s does not match the private key, "+
+				"using the derived public key %s", src.PublicKey, keyPair.PublicKey))
+		}
+	}
+
+	endpoint := cmp.Or(req.Endpoint, src.PeerDefEndpoint)
+	if endpoint == "" {
+		warnings = append(warnings, "no public endpoint given, the endpoint has to be set before peer "+
+			"configurations can be used")
+	} else if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		endpoint = net.JoinHostPort(strings.Trim(endpoint, "[]"), strconv.Itoa(listenPort))
+	}
+
+	iface := &domain.Interface{
+		BaseModel: domain.BaseModel{
+			CreatedBy: string(currentUser.Id),
+			UpdatedBy: string(currentUser.Id),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Identifier:                 id,
+		KeyPair:                    keyPair,
+		ListenPort:                 listenPort,
+		Addresses:                  addresses,
+		Mtu:                        cmp.Or(src.Mtu, wireguard.DefaultMTU),
+		DisplayName:                string(id),
+		Type:                       domain.InterfaceTypeServer,
+		Backend:                    backend,
+		ClientType:                 wgtypes.NativeClient,
+		PeerDefNetworkStr:          domain.CidrsToString(networks),
+		PeerDefDnsStr:              src.PeerDefDns,
+		PeerDefEndpoint:            endpoint,
+		PeerDefAllowedIPsStr:       cmp.Or(src.PeerDefAllowedIPs, "0.0.0.0/0, ::/0"),
+		PeerDefMtu:                 cmp.Or(src.PeerDefMtu, wireguard.DefaultMTU),
+		PeerDefPersistentKeepalive: cmp.Or(src.PeerDefPersistentKeepalive, defaultPersistentKeepalive),
+	}
+
+	return iface, warnings, nil
+}
+
+func migrationPeer(ctx context.Context, iface *domain.Interface, src *sourcePeer) domain.Peer {
+	currentUser := domain.GetUserInfo(ctx)
+
+	createdAt := src.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	updatedAt := src.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+
+	peer := domain.Peer{
+		BaseModel: domain.BaseModel{
+			CreatedBy: string(currentUser.Id),
+			UpdatedBy: string(currentUser.Id),
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		},
+		Identifier:          domain.PeerIdentifier(src.PublicKey),
+		InterfaceIdentifier: iface.Identifier,
+		UserIdentifier:      src.User,
+		DisplayName:         src.Name,
+		Notes:               src.Notes,
+		PresharedKey:        domain.PreSharedKey(src.PresharedKey),
+		Disabled:            src.Disabled,
+		DisabledReason:      src.DisabledReason,
+		ExpiresAt:           src.ExpiresAt,
+		Interface: domain.PeerInterfaceConfig{
+			KeyPair: domain.KeyPair{
+				PrivateKey: src.PrivateKey,
+				PublicKey:  src.PublicKey,
+			},
+			Type:      domain.InterfaceTypeClient,
+			Addresses: src.Addresses,
+		},
+	}
+	peer.Endpoint = peerOption(src.Endpoint, iface.PeerDefEndpoint)
+	peer.EndpointPublicKey = domain.NewConfigOption(iface.PublicKey, true)
+	peer.AllowedIPsStr = peerOption(src.AllowedIPs, iface.PeerDefAllowedIPsStr)
+	peer.PersistentKeepalive = peerOption(src.PersistentKeepalive, iface.PeerDefPersistentKeepalive)
+	peer.Interface.DnsStr = peerOption(src.Dns, iface.PeerDefDnsStr)
+	peer.Interface.DnsSearchStr = domain.NewConfigOption(iface.PeerDefDnsSearchStr, true)
+	peer.Interface.Mtu = peerOption(src.Mtu, iface.PeerDefMtu)
+	peer.Interface.FirewallMark = domain.NewConfigOption(iface.PeerDefFirewallMark, true)
+	peer.Interface.RoutingTable = domain.NewConfigOption(iface.PeerDefRoutingTable, true)
+	peer.Interface.PreUp = domain.NewConfigOption(iface.PeerDefPreUp, true)
+	peer.Interface.PostUp = domain.NewConfigOption(iface.PeerDefPostUp, true)
+	peer.Interface.PreDown = domain.NewConfigOption(iface.PeerDefPreDown, true)
+	peer.Interface.PostDown = domain.NewConfigOption(iface.PeerDefPostDown, true)
+
+	if peer.DisplayName == "" {
+		peer.DisplayName = "Imported Peer (" + src.PublicKey[0:8] + ")"
+	}
+
+	return peer
+}
+
+// commit stores the users, the interface and the peers of the plan. On failure, all changes are rolled back.
+func (m Manager) commit(ctx context.Context, plan *domain.MigrationPlan) error {
+	var createdUsers []domain.UserIdentifier
+	for i := range plan.Users {
+		user := &plan.Users[i]
+		// passwords cannot be migrated, database users get a random password that has to be reset by an admin
+		if user.Source == domain.UserSourceDatabase && user.Password == "" {
+			password, err := randomPassword()
+			if err != nil {
+				return m.rollback(ctx, plan, createdUsers, false, err)
+			}
+			user.Password = domain.PrivateString(password)
+		}
+		created, err := m.users.CreateUser(ctx, user)
+		if err != nil {
+			return m.rollback(ctx, plan, createdUsers, false,
+				fmt.Errorf("creation of user %s failed: %w", user.Identifier, err))
+		}
+		plan.Users[i] = *created
+		createdUsers = append(createdUsers, user.Identifier)
+	}
+
+	iface, err := m.wg.CreateInterface(ctx, plan.Interface)
+	if err != nil {
+		return m.rollback(ctx, plan, createdUsers, false,
+			fmt.Errorf("creation of interface %s failed: %w", plan.Interface.Identifier, err))
+	}
+	plan.Interface = iface
+
+	for i := range plan.Peers {
+		peer, err := m.wg.CreatePeer(ctx, &plan.Peers[i])
+		if err != nil {
+			return m.rollback(ctx, plan, createdUsers, true,
+				fmt.Errorf("creation of peers failed after %d of %d peers: %w", i, len(plan.Peers), err))
+		}
+		plan.Peers[i] = *peer
+	}
+
+	plan.Committed = true
+
+	return nil
+}
+
+// rollback removes the imported interface, its peers and the imported users.
+func (m Manager) rollback(
+	ctx context.Context,
+	plan *domain.MigrationPlan,
+	createdUsers []domain.UserIdentifier,
+	interfaceCreated bool,
+	cause error,
+) error {
+	var rollbackErrs []error
+	if interfaceCreated {
+		if err := m.wg.DeleteInterface(ctx, plan.Interface.Identifier); err != nil && !errors.Is(err,
+			domain.ErrNotFound) {
+			rollbackErrs = append(rollbackErrs, err)
+		}
+	}
+	for _, userId := range createdUsers {
+		if err := m.users.DeleteUser(ctx, userId); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			rollbackErrs = append(rollbackErrs, err)
+		}
+	}
+
+	if len(rollbackErrs) > 0 {
+		return fmt.Errorf("migration from %s failed: %w; rollback failed: %v", plan.Source, cause,
+			errors.Join(rollbackErrs...))
+	}
+
+	slog.Warn("rolled back failed migration", "source", plan.Source, "error", cause)
+
+	return fmt.Errorf("migration from %s failed, changes have been rolled back: %w", plan.Source, cause)
+}
+
+// peerOption returns an overridable option if the value is empty or matches the interface default,
+// otherwise a fixed option.
+func peerOption[T comparable](value, defaultValue T) domain.ConfigOption[T] {
+	var zero T
+	if value == zero || value == defaultValue {
+		return domain.NewConfigOption(defaultValue, true)
+	}
+	return domain.NewConfigOption(value, false)
+}
+
+// randomPassword returns a random password that nobody knows.
+func randomPassword() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// endregion helper-functions
@@ -0,0 +1,226 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockDB struct {
+	interfaces   []domain.Interface
+	peers        map[domain.PeerIdentifier]*domain.Peer
+	users        map[domain.UserIdentifier]*domain.User
+	deletedUsers []domain.UserIdentifier
+}
+
+func (f *mockDB) GetUser(_ context.Context, id domain.UserIdentifier) (*domain.User, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, domain.ErrNotFound
+}
+func (f *mockDB) CreateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	f.users[user.Identifier] = user
+	return user, nil
+}
+func (f *mockDB) DeleteUser(_ context.Context, id domain.UserIdentifier) error {
+	delete(f.users, id)
+	f.deletedUsers = append(f.deletedUsers, id)
+	return nil
+}
+
+type mockWireGuard struct {
+	db                *mockDB
+	failPeer          domain.PeerIdentifier // creating this peer fails
+	deletedInterfaces []domain.InterfaceIdentifier
+}
+
+func (f *mockWireGuard) CreateInterface(_ context.Context, in *domain.Interface) (*domain.Interface, error) {
+	f.db.interfaces = append(f.db.interfaces, *in)
+	return in, nil
+}
+func (f *mockWireGuard) CreatePeer(_ context.Context, peer *domain.Peer) (*domain.Peer, error) {
+	if peer.Identifier == f.failPeer {
+		return nil, errors.New("backend unavailable")
+	}
+	f.db.peers[peer.Identifier] = peer
+	return peer, nil
+}
+func (f *mockWireGuard) DeleteInterface(_ context.Context, id domain.InterfaceIdentifier) error {
+	f.deletedInterfaces = append(f.deletedInterfaces, id)
+	return nil
+}
+
+func (f *mockWireGuard) FindImportConflicts(
+	_ context.Context,
+	iface *domain.Interface,
+	peers []domain.Peer,
+) ([]string, error) {
+	var conflicts []string
+	for _, existing := range f.db.interfaces {
+		if existing.Identifier == iface.Identifier {
+			conflicts = append(conflicts, fmt.Sprintf("interface %s already exists", existing.Identifier))
+		}
+	}
+	for _, peer := range peers {
+		if _, exists := f.db.peers[peer.Identifier]; exists {
+			conflicts = append(conflicts, fmt.Sprintf("peer %s already exists", peer.Identifier))
+		}
+	}
+	return conflicts, nil
+}
+
+func newTestManager() (*Manager, *mockDB, *mockWireGuard) {
+	db := &mockDB{
+		peers: make(map[domain.PeerIdentifier]*domain.Peer),
+		users: make(map[domain.UserIdentifier]*domain.User),
+	}
+	wg := &mockWireGuard{db: db}
+	cfg := &config.Config{}
+	cfg.Backend.Default = config.LocalBackendName
+	return NewManager(cfg, db, wg), db, wg
+}
+
+func adminContext() context.Context {
+	return domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+}
+
+func TestManager_Import_WgEasy(t *testing.T) {
+	server, alice, bob := newTestKey(t), newTestKey(t), newTestKey(t)
+	m, db, _ := newTestManager()
+	db.users["owner"] = &domain.User{Identifier: "owner"}
+
+	req := &domain.MigrationRequest{
+		Source:         domain.MigrationSourceWgEasy,
+		Data:           wgEasyTestDatabase(server, alice, bob),
+		Endpoint:       "vpn.example.com",
+		UserIdentifier: "owner",
+	}
+
+	plan, err := m.Import(adminContext(), req, true)
+	require.NoError(t, err)
+	assert.False(t, plan.Committed)
+	assert.Empty(t, plan.Conflicts)
+	assert.Empty(t, db.interfaces, "dry run must not store anything")
+	assert.Equal(t, "vpn.example.com:51820", plan.Interface.PeerDefEndpoint)
+	assert.Equal(t, server.PublicKey().String(), plan.Interface.PublicKey)
+
+	plan, err = m.Import(adminContext(), req, false)
+	require.NoError(t, err)
+	assert.True(t, plan.Committed)
+	require.Len(t, db.interfaces, 1)
+	require.Len(t, db.peers, 2)
+
+	bobPeer := db.peers[domain.PeerIdentifier(bob.PublicKey().String())]
+	assert.Equal(t, domain.UserIdentifier("owner"), bobPeer.UserIdentifier)
+	assert.Equal(t, bob.String(), bobPeer.Interface.PrivateKey)
+	assert.True(t, bobPeer.IsDisabled())
+	assert.NotNil(t, bobPeer.ExpiresAt)
+	assert.Equal(t, "1.1.1.1", bobPeer.Interface.DnsStr.GetValue())
+	assert.True(t, bobPeer.Interface.DnsStr.Overridable)
+
+	// importing the same data again conflicts with the existing interface and peers
+	plan, err = m.Import(adminContext(), req, false)
+	require.NoError(t, err)
+	assert.False(t, plan.Committed)
+	assert.Contains(t, plan.Conflicts, "interface wg0 already exists")
+	assert.Len(t, plan.Conflicts, 3) // interface and two peers
+}
+
+func TestManager_Import_UnknownOwner(t *testing.T) {
+	m, _, _ := newTestManager()
+	plan, err := m.Import(adminContext(), &domain.MigrationRequest{
+		Source:         domain.MigrationSourceWgEasy,
+		Data:           wgEasyTestDatabase(newTestKey(t), newTestKey(t), newTestKey(t)),
+		UserIdentifier: "missing",
+	}, false)
+	require.NoError(t, err)
+	assert.False(t, plan.Committed)
+	assert.Equal(t, []string{"user missing does not exist"}, plan.Conflicts)
+}
+
+func TestManager_Import_InvalidKey(t *testing.T) {
+	server := newTestKey(t)
+	m, db, _ := newTestManager()
+	data := []byte(`{
+  "server": {"privateKey": "` + server.String() + `", "address": "10.8.0.1"},
+  "clients": {"1": {"id": "1", "address": "10.8.0.2", "publicKey": "abc", "enabled": true}}
+}`)
+
+	plan, err := m.Import(adminContext(), &domain.MigrationRequest{Source: domain.MigrationSourceWgEasy, Data: data},
+		false)
+	require.NoError(t, err)
+	assert.False(t, plan.Committed)
+	require.Len(t, plan.Conflicts, 1)
+	assert.Contains(t, plan.Conflicts[0], "invalid public key")
+	assert.Empty(t, plan.Peers)
+	assert.Empty(t, db.interfaces)
+}
+
+func TestManager_Import_Firezone(t *testing.T) {
+	m, db, _ := newTestManager()
+	source := newFirezoneTestDatabase(t, false)
+	m.openDatabase = func(_ config.DatabaseConfig) (*gorm.DB, error) { return source, nil }
+
+	peerKey := newTestKey(t).PublicKey()
+	require.NoError(t, source.Create(&firezoneUser{Id: "u1", Email: "alice@example.com", Role: "admin"}).Error)
+	require.NoError(t, source.Create(&firezoneDevice{Id: "d1", Name: "laptop", PublicKey: peerKey.String(),
+		Ipv4: ptr("10.3.2.2"), UseDefaultAllowedIps: true, UserId: "u1"}).Error)
+
+	plan, err := m.Import(adminContext(), &domain.MigrationRequest{
+		Source: domain.MigrationSourceFirezone,
+		Dsn:    "postgres://firezone",
+	}, false)
+	require.NoError(t, err)
+	assert.True(t, plan.Committed)
+	require.Contains(t, db.users, domain.UserIdentifier("alice@example.com"))
+	assert.True(t, db.users["alice@example.com"].IsAdmin)
+	assert.NotEmpty(t, db.users["alice@example.com"].Password, "database users need a password")
+	assert.Equal(t, domain.UserIdentifier("alice@example.com"),
+		db.peers[domain.PeerIdentifier(peerKey.String())].UserIdentifier)
+	assert.NotEmpty(t, plan.Interface.PrivateKey, "a new interface key has to be generated")
+}
+
+func TestManager_Import_RollbackOnFailure(t *testing.T) {
+	m, db, wg := newTestManager()
+	source := newFirezoneTestDatabase(t, false)
+	m.openDatabase = func(_ config.DatabaseConfig) (*gorm.DB, error) { return source, nil }
+
+	first, second := newTestKey(t).PublicKey(), newTestKey(t).PublicKey()
+	require.NoError(t, source.Create(&firezoneUser{Id: "u1", Email: "alice@example.com"}).Error)
+	require.NoError(t, source.Create(&[]firezoneDevice{
+		{Id: "d1", Name: "first", PublicKey: first.String(), Ipv4: ptr("10.3.2.2"), UserId: "u1"},
+		{Id: "d2", Name: "second", PublicKey: second.String(), Ipv4: ptr("10.3.2.3"), UserId: "u1"},
+	}).Error)
+	wg.failPeer = domain.PeerIdentifier(second.String())
+
+	_, err := m.Import(adminContext(), &domain.MigrationRequest{
+		Source: domain.MigrationSourceFirezone,
+		Dsn:    "postgres://firezone",
+	}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "changes have been rolled back")
+	assert.Contains(t, err.Error(), "after 1 of 2 peers")
+	assert.Equal(t, []domain.InterfaceIdentifier{"wg-firezone"}, wg.deletedInterfaces)
+	assert.Equal(t, []domain.UserIdentifier{"alice@example.com"}, db.deletedUsers)
+	assert.Empty(t, db.users)
+}
+
+func TestManager_Import_RequiresAdmin(t *testing.T) {
+	m, _, _ := newTestManager()
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "user", IsAdmin: false})
+
+	_, err := m.Import(ctx, &domain.MigrationRequest{Source: domain.MigrationSourceWgEasy}, true)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+
+	_, err = m.Import(adminContext(), &domain.MigrationRequest{Source: "unknown"}, true)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+}
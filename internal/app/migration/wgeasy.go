@@ -0,0 +1,114 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// wgEasyDatabase is the content of the wg0.json file of wg-easy (up to version 14).
+type wgEasyDatabase struct {
+	Server struct {
+		PrivateKey string `json:"privateKey"`
+		PublicKey  string `json:"publicKey"`
+		Address    string `json:"address"`
+	} `json:"server"`
+	Clients map[string]wgEasyClient `json:"clients"`
+}
+
+type wgEasyClient struct {
+	Id           string     `json:"id"`
+	Name         string     `json:"name"`
+	Address      string     `json:"address"`
+	PrivateKey   string     `json:"privateKey"`
+	PublicKey    string     `json:"publicKey"`
+	PreSharedKey string     `json:"preSharedKey"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ExpiredAt    *time.Time `json:"expiredAt"`
+	Enabled      bool       `json:"enabled"`
+}
+
+// parseWgEasy maps the clients of a wg-easy database to peers. wg-easy has no user accounts, and settings like the
+// listen port, DNS servers or allowed IPs are only stored in environment variables, so the wg-easy defaults are used.
+func parseWgEasy(raw []byte) (*sourceData, error) {
+	var db wgEasyDatabase
+	if err := json.Unmarshal(raw, &db); err != nil {
+		return nil, fmt.Errorf("invalid wg-easy database: %w: %w", err, domain.ErrInvalidData)
+	}
+	if db.Server.PrivateKey == "" || db.Server.Address == "" {
+		return nil, fmt.Errorf("invalid wg-easy database, missing server settings: %w", domain.ErrInvalidData)
+	}
+
+	// wg-easy always uses a /24 network (WG_DEFAULT_ADDRESS)
+	addresses, err := domain.ParseWgQuickCidrs(strings.TrimSpace(db.Server.Address) + "/24")
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address %s: %w", db.Server.Address, domain.ErrInvalidData)
+	}
+
+	data := &sourceData{
+		Interface: sourceInterface{
+			Identifier:        "wg0",
+			PrivateKey:        db.Server.PrivateKey,
+			PublicKey:         db.Server.PublicKey,
+			Addresses:         addresses,
+			PeerDefDns:        "1.1.1.1",
+			PeerDefAllowedIPs: "0.0.0.0/0, ::/0",
+		},
+	}
+
+	clients := make([]wgEasyClient, 0, len(db.Clients))
+	for _, client := range db.Clients {
+		clients = append(clients, client)
+	}
+	slices.SortFunc(clients, func(a, b wgEasyClient) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	for _, client := range clients {
+		if client.PublicKey == "" {
+			data.Warnings = append(data.Warnings, fmt.Sprintf("client %s has no public key, skipping it", client.Name))
+			continue
+		}
+		clientAddresses, err := domain.ParseWgQuickCidrs(client.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s of client %s: %w", client.Address, client.Name,
+				domain.ErrInvalidData)
+		}
+
+		peer := sourcePeer{
+			Name:         client.Name,
+			Notes:        "imported from wg-easy, client id " + client.Id,
+			PublicKey:    client.PublicKey,
+			PrivateKey:   client.PrivateKey,
+			PresharedKey: client.PreSharedKey,
+			Addresses:    clientAddresses,
+			ExpiresAt:    client.ExpiredAt,
+			CreatedAt:    client.CreatedAt,
+			UpdatedAt:    client.UpdatedAt,
+		}
+		if !client.Enabled {
+			disabled := client.UpdatedAt
+			if disabled.IsZero() {
+				disabled = time.Now()
+			}
+			peer.Disabled = &disabled
+			peer.DisabledReason = domain.DisabledReasonAdmin
+		}
+		if err := peer.validateKeys(); err != nil {
+			data.Conflicts = append(data.Conflicts, fmt.Sprintf("client %s: %v", client.Name, err))
+			continue
+		}
+
+		data.Peers = append(data.Peers, peer)
+	}
+
+	return data, nil
+}
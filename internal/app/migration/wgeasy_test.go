@@ -0,0 +1,88 @@
+package migration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Biezax/wgctrl/wgtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func newTestKey(t *testing.T) wgtypes.Key {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	return key
+}
+
+func wgEasyTestDatabase(server, alice, bob wgtypes.Key) []byte {
+	return []byte(`{
+  "server": {
+    "privateKey": "` + server.String() + `",
+    "publicKey": "` + server.PublicKey().String() + `",
+    "address": "10.8.0.1"
+  },
+  "clients": {
+    "b2c7a9d0-0000-4000-8000-000000000002": {
+      "id": "b2c7a9d0-0000-4000-8000-000000000002",
+      "name": "bob",
+      "address": "10.8.0.3",
+      "privateKey": "` + bob.String() + `",
+      "publicKey": "` + bob.PublicKey().String() + `",
+      "preSharedKey": "` + alice.PublicKey().String() + `",
+      "createdAt": "2024-02-01T10:00:00.000Z",
+      "updatedAt": "2024-03-01T10:00:00.000Z",
+      "expiredAt": "2030-01-01T00:00:00.000Z",
+      "enabled": false
+    },
+    "b2c7a9d0-0000-4000-8000-000000000001": {
+      "id": "b2c7a9d0-0000-4000-8000-000000000001",
+      "name": "alice",
+      "address": "10.8.0.2",
+      "privateKey": "` + alice.String() + `",
+      "publicKey": "` + alice.PublicKey().String() + `",
+      "preSharedKey": "",
+      "createdAt": "2024-01-01T10:00:00.000Z",
+      "updatedAt": "2024-01-01T10:00:00.000Z",
+      "expiredAt": null,
+      "enabled": true
+    }
+  }
+}`)
+}
+
+func TestParseWgEasy(t *testing.T) {
+	server, alice, bob := newTestKey(t), newTestKey(t), newTestKey(t)
+
+	data, err := parseWgEasy(wgEasyTestDatabase(server, alice, bob))
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.InterfaceIdentifier("wg0"), data.Interface.Identifier)
+	assert.Equal(t, server.String(), data.Interface.PrivateKey)
+	assert.Equal(t, "10.8.0.1/24", domain.CidrsToString(data.Interface.Addresses))
+
+	require.Len(t, data.Peers, 2)
+	assert.Equal(t, "alice", data.Peers[0].Name, "clients should be ordered by creation date")
+	assert.Equal(t, alice.String(), data.Peers[0].PrivateKey)
+	assert.Equal(t, "10.8.0.2/32", domain.CidrsToString(data.Peers[0].Addresses))
+	assert.Nil(t, data.Peers[0].Disabled)
+	assert.Nil(t, data.Peers[0].ExpiresAt)
+
+	assert.Equal(t, "bob", data.Peers[1].Name)
+	assert.Equal(t, bob.PublicKey().String(), data.Peers[1].PublicKey)
+	assert.Equal(t, alice.PublicKey().String(), data.Peers[1].PresharedKey)
+	require.NotNil(t, data.Peers[1].Disabled)
+	assert.Equal(t, domain.DisabledReasonAdmin, data.Peers[1].DisabledReason)
+	require.NotNil(t, data.Peers[1].ExpiresAt)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), data.Peers[1].ExpiresAt.UTC())
+}
+
+func TestParseWgEasy_Invalid(t *testing.T) {
+	_, err := parseWgEasy([]byte(`{"clients": {}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+
+	_, err = parseWgEasy([]byte(`{"clients": {}}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+}
@@ -0,0 +1,493 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// region dependencies
+
+type UserManager interface {
+	// GetAllUsers returns all users.
+	GetAllUsers(ctx context.Context) ([]domain.User, error)
+	// CreateUser creates a new user.
+	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	// UpdateUser updates the user with the given identifier.
+	UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error)
+	// DeleteUser deletes the user with the given identifier.
+	DeleteUser(ctx context.Context, id domain.UserIdentifier) error
+}
+
+type WireGuardManager interface {
+	// GetAllInterfacesAndPeers returns all interfaces and their peers.
+	GetAllInterfacesAndPeers(ctx context.Context) ([]domain.Interface, [][]domain.Peer, error)
+	// PreparePeer prepares a new peer with fresh keys and addresses for the given interface.
+	PreparePeer(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Peer, error)
+	// CreatePeer creates a new peer.
+	CreatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
+	// UpdatePeer updates the given peer.
+	UpdatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error)
+	// DeletePeer deletes the peer with the given identifier.
+	DeletePeer(ctx context.Context, id domain.PeerIdentifier) error
+}
+
+// endregion dependencies
+
+// Manager reconciles the users and peers of the provisioning configuration with the database.
+// Objects created by the provisioning are owned by the provisioner, only those are deleted when pruning.
+type Manager struct {
+	users UserManager
+	wg    WireGuardManager
+
+	mu                sync.Mutex
+	cfg               config.ProvisioningConfig
+	minPasswordLength int
+}
+
+// NewManager creates a new provisioning manager.
+func NewManager(cfg *config.Config, users UserManager, wg WireGuardManager) *Manager {
+	return &Manager{
+		users:             users,
+		wg:                wg,
+		cfg:               cfg.Provisioning,
+		minPasswordLength: cfg.Auth.MinPasswordLength,
+	}
+}
+
+// Plan returns the changes that are required to reconcile the database with the configuration.
+// Nothing is changed.
+func (m *Manager) Plan(ctx context.Context) (*domain.ProvisioningPlan, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, err := m.plan(ctx, m.cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.report, nil
+}
+
+// Apply reconciles the database with the configuration and returns the applied plan. If the plan contains invalid
+// entries, nothing is changed. If a change fails, the remaining changes are skipped and an error is returned.
+func (m *Manager) Apply(ctx context.Context) (*domain.ProvisioningPlan, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.apply(ctx, m.cfg)
+}
+
+// ReloadConfig reconciles the database with the provisioning settings of the given configuration.
+// The settings are only kept if they are valid.
+func (m *Manager) ReloadConfig(ctx context.Context, cfg *config.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report, err := m.apply(ctx, cfg.Provisioning)
+	if report != nil && !report.HasErrors() {
+		m.cfg = cfg.Provisioning
+		m.minPasswordLength = cfg.Auth.MinPasswordLength
+	}
+	return err
+}
+
+func (m *Manager) apply(ctx context.Context, cfg config.ProvisioningConfig) (*domain.ProvisioningPlan, error) {
+	// changes are stored with the provisioner as author, so that provisioned objects can be pruned later
+	ctx = domain.SetUserInfo(ctx, domain.ProvisioningContextUserInfo())
+
+	p, err := m.plan(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if p.report.HasErrors() {
+		return p.report, fmt.Errorf("invalid provisioning configuration: %v: %w", p.report.Errors,
+			domain.ErrInvalidData)
+	}
+
+	for _, s := range p.steps {
+		if err := s.apply(ctx); err != nil {
+			return p.report, fmt.Errorf("failed to %s %s %s: %w", s.change.Action, s.change.Kind,
+				s.change.Identifier, err)
+		}
+	}
+	p.report.Applied = true
+
+	if len(p.steps) > 0 {
+		slog.Info("provisioning applied", "changes", len(p.steps))
+	}
+
+	return p.report, nil
+}
+
+// step is a single change of a plan.
+type step struct {
+	change domain.ProvisioningChange
+	apply  func(ctx context.Context) error
+}
+
+type plan struct {
+	report *domain.ProvisioningPlan
+	steps  []step
+}
+
+func (p *plan) add(change domain.ProvisioningChange, apply func(ctx context.Context) error) {
+	p.report.Changes = append(p.report.Changes, change)
+	p.steps = append(p.steps, step{change: change, apply: apply})
+}
+
+func (p *plan) invalid(format string, args ...any) {
+	p.report.Errors = append(p.report.Errors, fmt.Sprintf(format, args...))
+}
+
+func (m *Manager) plan(ctx context.Context, cfg config.ProvisioningConfig) (*plan, error) {
+	users, err := m.users.GetAllUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load users: %w", err)
+	}
+	interfaces, interfacePeers, err := m.wg.GetAllInterfacesAndPeers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load interfaces: %w", err)
+	}
+
+	p := &plan{report: &domain.ProvisioningPlan{}}
+
+	userMap := make(map[domain.UserIdentifier]*domain.User, len(users))
+	for i := range users {
+		userMap[users[i].Identifier] = &users[i]
+	}
+	interfaceMap := make(map[domain.InterfaceIdentifier]*domain.Interface, len(interfaces))
+	peerMap := make(map[domain.PeerIdentifier]*domain.Peer)
+	var peers []domain.Peer
+	for i := range interfaces {
+		interfaceMap[interfaces[i].Identifier] = &interfaces[i]
+		peers = append(peers, interfacePeers[i]...)
+	}
+	for i := range peers {
+		peerMap[peers[i].Identifier] = &peers[i]
+	}
+
+	configuredUsers := make(map[domain.UserIdentifier]struct{}, len(cfg.Users))
+	for _, in := range cfg.Users {
+		configuredUsers[domain.UserIdentifier(in.Identifier)] = struct{}{}
+		m.planUser(p, in, userMap[domain.UserIdentifier(in.Identifier)])
+	}
+
+	configuredPeers := make(map[domain.PeerIdentifier]struct{}, len(cfg.Peers))
+	for _, in := range cfg.Peers {
+		id, keys, err := peerKeys(in)
+		if err != nil {
+			p.invalid("peer on interface %s: %v", in.Interface, err)
+			continue
+		}
+		if _, ok := configuredPeers[id]; ok {
+			p.invalid("peer %s is not unique", id)
+			continue
+		}
+		configuredPeers[id] = struct{}{}
+
+		if in.User != "" {
+			_, inConfig := configuredUsers[domain.UserIdentifier(in.User)]
+			if _, exists := userMap[domain.UserIdentifier(in.User)]; !exists && !inConfig {
+				p.invalid("peer %s: user %s not found", id, in.User)
+				continue
+			}
+		}
+
+		iface, ok := interfaceMap[domain.InterfaceIdentifier(in.Interface)]
+		if !ok {
+			p.invalid("peer %s: interface %s not found", id, in.Interface)
+			continue
+		}
+
+		m.planPeer(p, in, id, keys, iface, peerMap[id])
+	}
+
+	if !cfg.Prune {
+		return p, nil
+	}
+
+	// delete peers before users, so that no peer is left without its user
+	for _, peer := range peers {
+		if _, ok := configuredPeers[peer.Identifier]; ok || peer.CreatedBy != domain.CtxSystemProvisioner {
+			continue
+		}
+		p.add(domain.ProvisioningChange{
+			Kind:       domain.ProvisioningKindPeer,
+			Identifier: string(peer.Identifier),
+			Action:     domain.ProvisioningActionDelete,
+		}, func(ctx context.Context) error {
+			return m.wg.DeletePeer(ctx, peer.Identifier)
+		})
+	}
+	for _, user := range users {
+		if _, ok := configuredUsers[user.Identifier]; ok || user.CreatedBy != domain.CtxSystemProvisioner {
+			continue
+		}
+		p.add(domain.ProvisioningChange{
+			Kind:       domain.ProvisioningKindUser,
+			Identifier: string(user.Identifier),
+			Action:     domain.ProvisioningActionDelete,
+		}, func(ctx context.Context) error {
+			return m.users.DeleteUser(ctx, user.Identifier)
+		})
+	}
+
+	return p, nil
+}
+
+// region users
+
+func (m *Manager) planUser(p *plan, in config.ProvisioningUser, existing *domain.User) {
+	source := domain.UserSource(in.Source)
+	change := domain.ProvisioningChange{
+		Kind:       domain.ProvisioningKindUser,
+		Identifier: in.Identifier,
+	}
+
+	if existing == nil {
+		if source == domain.UserSourceDatabase && in.Password == "" {
+			p.invalid("user %s: missing password", in.Identifier)
+			return
+		}
+		if len(in.Password) > 0 && len(in.Password) < m.minPasswordLength {
+			p.invalid("user %s: password is too short, minimum length is %d", in.Identifier, m.minPasswordLength)
+			return
+		}
+
+		user := &domain.User{
+			Identifier: domain.UserIdentifier(in.Identifier),
+			Source:     source,
+			Password:   domain.PrivateString(in.Password),
+		}
+		applyProvisioningUser(user, in, true)
+
+		change.Action = domain.ProvisioningActionCreate
+		p.add(change, func(ctx context.Context) error {
+			_, err := m.users.CreateUser(ctx, user)
+			return err
+		})
+		return
+	}
+
+	if existing.Source != source {
+		p.invalid("user %s: cannot change the source from %s to %s", in.Identifier, existing.Source, source)
+		return
+	}
+
+	// the profile of LDAP and OAuth users is managed by the authentication provider
+	updateProfile := source == domain.UserSourceDatabase
+
+	user := &domain.User{}
+	*user = *existing
+	user.Password = "" // keep the existing password
+	applyProvisioningUser(user, in, updateProfile)
+
+	change.Action = domain.ProvisioningActionUpdate
+	change.Fields = changedUserFields(existing, user)
+	if len(change.Fields) == 0 {
+		return
+	}
+	p.add(change, func(ctx context.Context) error {
+		_, err := m.users.UpdateUser(ctx, user)
+		return err
+	})
+}
+
+// applyProvisioningUser copies the configured values to the user. The profile fields are only copied if
+// updateProfile is true. Provisioning only enables users it could have disabled itself, users that have been disabled
+// by the system, for example by the LDAP synchronization, stay disabled.
+func applyProvisioningUser(user *domain.User, in config.ProvisioningUser, updateProfile bool) {
+	if updateProfile {
+		user.Email = in.Email
+		user.Firstname = in.Firstname
+		user.Lastname = in.Lastname
+		user.Phone = in.Phone
+		user.Department = in.Department
+		user.IsAdmin = in.IsAdmin
+	}
+	user.Notes = in.Notes
+	switch {
+	case in.Disabled && !user.IsDisabled():
+		now := time.Now()
+		user.Disabled = &now
+		user.DisabledReason = domain.DisabledReasonAdmin
+	case !in.Disabled && user.DisabledReason == domain.DisabledReasonAdmin:
+		user.Disabled = nil
+		user.DisabledReason = ""
+	}
+}
+
+func changedUserFields(old, new *domain.User) []string {
+	var fields []string
+	addIf := func(changed bool, field string) {
+		if changed {
+			fields = append(fields, field)
+		}
+	}
+	addIf(old.Email != new.Email, "email")
+	addIf(old.Firstname != new.Firstname, "firstname")
+	addIf(old.Lastname != new.Lastname, "lastname")
+	addIf(old.Phone != new.Phone, "phone")
+	addIf(old.Department != new.Department, "department")
+	addIf(old.Notes != new.Notes, "notes")
+	addIf(old.IsAdmin != new.IsAdmin, "is_admin")
+	addIf(old.IsDisabled() != new.IsDisabled(), "disabled")
+	return fields
+}
+
+// endregion users
+
+// region peers
+
+// peerKeys returns the identifier and the key pair of the configured peer. The private key is empty if only the
+// public key is configured.
+func peerKeys(in config.ProvisioningPeer) (domain.PeerIdentifier, domain.KeyPair, error) {
+	keys := domain.KeyPair{PrivateKey: in.PrivateKey, PublicKey: in.PublicKey}
+	if in.PrivateKey != "" {
+		publicKey := domain.PublicKeyFromPrivateKey(in.PrivateKey)
+		if publicKey == "" {
+			return "", keys, fmt.Errorf("invalid private_key")
+		}
+		if in.PublicKey != "" && in.PublicKey != publicKey {
+			return "", keys, fmt.Errorf("public_key does not match private_key")
+		}
+		keys.PublicKey = publicKey
+	}
+
+	id := domain.PeerIdentifier(keys.PublicKey)
+	if !id.IsPublicKey() {
+		return "", keys, fmt.Errorf("invalid public_key")
+	}
+	return id, keys, nil
+}
+
+func (m *Manager) planPeer(
+	p *plan,
+	in config.ProvisioningPeer,
+	id domain.PeerIdentifier,
+	keys domain.KeyPair,
+	iface *domain.Interface,
+	existing *domain.Peer,
+) {
+	change := domain.ProvisioningChange{
+		Kind:       domain.ProvisioningKindPeer,
+		Identifier: string(id),
+	}
+
+	var addresses []domain.Cidr
+	if len(in.Addresses) > 0 {
+		var err error
+		if addresses, err = domain.CidrsFromArray(in.Addresses); err != nil {
+			p.invalid("peer %s: invalid addresses: %v", id, err)
+			return
+		}
+	}
+
+	if existing == nil {
+		change.Action = domain.ProvisioningActionCreate
+		p.add(change, func(ctx context.Context) error {
+			peer, err := m.wg.PreparePeer(ctx, iface.Identifier)
+			if err != nil {
+				return err
+			}
+			peer.Identifier = id
+			peer.Interface.KeyPair = keys
+			peer.UserIdentifier = domain.UserIdentifier(in.User)
+			peer.GenerateDisplayName("")
+			if addresses != nil {
+				peer.Interface.Addresses = addresses
+			}
+			applyProvisioningPeer(peer, in)
+
+			_, err = m.wg.CreatePeer(ctx, peer)
+			return err
+		})
+		return
+	}
+
+	if existing.InterfaceIdentifier != iface.Identifier {
+		p.invalid("peer %s: cannot move peer from interface %s to %s", id, existing.InterfaceIdentifier,
+			iface.Identifier)
+		return
+	}
+
+	peer := &domain.Peer{}
+	*peer = *existing
+	peer.UserIdentifier = domain.UserIdentifier(in.User)
+	if keys.PrivateKey != "" {
+		peer.Interface.PrivateKey = keys.PrivateKey
+	}
+	if addresses != nil {
+		peer.Interface.Addresses = addresses
+	}
+	applyProvisioningPeer(peer, in)
+
+	change.Action = domain.ProvisioningActionUpdate
+	change.Fields = changedPeerFields(existing, peer)
+	if len(change.Fields) == 0 {
+		return
+	}
+	p.add(change, func(ctx context.Context) error {
+		_, err := m.wg.UpdatePeer(ctx, peer)
+		return err
+	})
+}
+
+// applyProvisioningPeer copies the configured values to the peer. An empty display name keeps the current name.
+// Like users, peers that have been disabled by the system, for example because they expired, stay disabled.
+func applyProvisioningPeer(peer *domain.Peer, in config.ProvisioningPeer) {
+	if in.DisplayName != "" {
+		peer.DisplayName = in.DisplayName
+	}
+	peer.Notes = in.Notes
+	peer.ExpiresAt = in.ExpiresAt
+	switch {
+	case in.Disabled && !peer.IsDisabled():
+		now := time.Now()
+		peer.Disabled = &now
+		peer.DisabledReason = domain.DisabledReasonAdmin
+	case !in.Disabled && peer.DisabledReason == domain.DisabledReasonAdmin:
+		peer.Disabled = nil
+		peer.DisabledReason = ""
+	}
+}
+
+func changedPeerFields(old, new *domain.Peer) []string {
+	var fields []string
+	addIf := func(changed bool, field string) {
+		if changed {
+			fields = append(fields, field)
+		}
+	}
+	addIf(old.UserIdentifier != new.UserIdentifier, "user")
+	addIf(old.DisplayName != new.DisplayName, "display_name")
+	addIf(old.Interface.PrivateKey != new.Interface.PrivateKey, "private_key")
+	addIf(domain.CidrsToString(old.Interface.Addresses) != domain.CidrsToString(new.Interface.Addresses),
+		"addresses")
+	addIf(!equalTime(old.ExpiresAt, new.ExpiresAt), "expires_at")
+	addIf(old.Notes != new.Notes, "notes")
+	addIf(old.IsDisabled() != new.IsDisabled(), "disabled")
+	return fields
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// endregion peers
@@ -0,0 +1,225 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// mockBackend implements the user and WireGuard manager on top of in-memory maps. Like the database, it stores the
+// user of the context as author of new records.
+type mockBackend struct {
+	users      map[domain.UserIdentifier]*domain.User
+	interfaces []domain.Interface
+	peers      map[domain.PeerIdentifier]*domain.Peer
+}
+
+func newMockBackend() *mockBackend {
+	return &mockBackend{
+		users: map[domain.UserIdentifier]*domain.User{
+			"admin": {Identifier: "admin", Source: domain.UserSourceDatabase, IsAdmin: true},
+		},
+		interfaces: []domain.Interface{{Identifier: "wg0", Type: domain.InterfaceTypeServer}},
+		peers:      map[domain.PeerIdentifier]*domain.Peer{},
+	}
+}
+
+func (f *mockBackend) GetAllUsers(_ context.Context) ([]domain.User, error) {
+	users := make([]domain.User, 0, len(f.users))
+	for _, user := range f.users {
+		users = append(users, *user)
+	}
+	return users, nil
+}
+func (f *mockBackend) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	stored := *user
+	stored.CreatedBy = domain.GetUserInfo(ctx).UserId()
+	f.users[user.Identifier] = &stored
+	return user, nil
+}
+func (f *mockBackend) UpdateUser(_ context.Context, user *domain.User) (*domain.User, error) {
+	stored := *user
+	f.users[user.Identifier] = &stored
+	return user, nil
+}
+func (f *mockBackend) DeleteUser(_ context.Context, id domain.UserIdentifier) error {
+	delete(f.users, id)
+	return nil
+}
+func (f *mockBackend) GetAllInterfacesAndPeers(_ context.Context) ([]domain.Interface, [][]domain.Peer, error) {
+	peers := make([][]domain.Peer, len(f.interfaces))
+	for i, iface := range f.interfaces {
+		for _, peer := range f.peers {
+			if peer.InterfaceIdentifier == iface.Identifier {
+				peers[i] = append(peers[i], *peer)
+			}
+		}
+	}
+	return f.interfaces, peers, nil
+}
+func (f *mockBackend) PreparePeer(_ context.Context, id domain.InterfaceIdentifier) (*domain.Peer, error) {
+	addr, _ := domain.CidrFromString("10.0.0.2/32")
+	return &domain.Peer{
+		InterfaceIdentifier: id,
+		Interface:           domain.PeerInterfaceConfig{Addresses: []domain.Cidr{addr}},
+	}, nil
+}
+func (f *mockBackend) CreatePeer(ctx context.Context, peer *domain.Peer) (*domain.Peer, error) {
+	stored := *peer
+	stored.CreatedBy = domain.GetUserInfo(ctx).UserId()
+	f.peers[peer.Identifier] = &stored
+	return peer, nil
+}
+func (f *mockBackend) UpdatePeer(_ context.Context, peer *domain.Peer) (*domain.Peer, error) {
+	stored := *peer
+	f.peers[peer.Identifier] = &stored
+	return peer, nil
+}
+func (f *mockBackend) DeletePeer(_ context.Context, id domain.PeerIdentifier) error {
+	delete(f.peers, id)
+	return nil
+}
+
+func adminContext() context.Context {
+	return domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+}
+
+func newTestConfig(t *testing.T) (*config.Config, domain.KeyPair) {
+	t.Helper()
+
+	keys, err := domain.NewFreshKeypair()
+	require.NoError(t, err)
+
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{}
+	cfg.Auth.MinPasswordLength = 8
+	cfg.Provisioning = config.ProvisioningConfig{
+		Users: []config.ProvisioningUser{
+			{Identifier: "alice", Source: "db", Email: "alice@example.com", Password: "secret-password"},
+		},
+		Peers: []config.ProvisioningPeer{
+			{Interface: "wg0", User: "alice", DisplayName: "alice laptop", PrivateKey: keys.PrivateKey,
+				ExpiresAt: &expiry},
+		},
+	}
+	return cfg, keys
+}
+
+func TestManager_PlanAndApply(t *testing.T) {
+	backend := newMockBackend()
+	cfg, keys := newTestConfig(t)
+	m := NewManager(cfg, backend, backend)
+
+	plan, err := m.Plan(adminContext())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ProvisioningChange{
+		{Kind: domain.ProvisioningKindUser, Identifier: "alice", Action: domain.ProvisioningActionCreate},
+		{Kind: domain.ProvisioningKindPeer, Identifier: keys.PublicKey, Action: domain.ProvisioningActionCreate},
+	}, plan.Changes)
+	assert.False(t, plan.Applied)
+	assert.NotContains(t, backend.users, domain.UserIdentifier("alice"), "planning must not change anything")
+
+	plan, err = m.Apply(adminContext())
+	require.NoError(t, err)
+	assert.True(t, plan.Applied)
+	require.Contains(t, backend.users, domain.UserIdentifier("alice"))
+	assert.Equal(t, domain.CtxSystemProvisioner, backend.users["alice"].CreatedBy)
+	peer := backend.peers[domain.PeerIdentifier(keys.PublicKey)]
+	require.NotNil(t, peer)
+	assert.Equal(t, domain.UserIdentifier("alice"), peer.UserIdentifier)
+	assert.Equal(t, "alice laptop", peer.DisplayName)
+	assert.Equal(t, keys, peer.Interface.KeyPair)
+	assert.Equal(t, "10.0.0.2/32", domain.CidrsToString(peer.Interface.Addresses))
+
+	// applying the same configuration again is a no-op
+	plan, err = m.Apply(adminContext())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+}
+
+func TestManager_ReloadConfig_UpdateAndPrune(t *testing.T) {
+	backend := newMockBackend()
+	cfg, keys := newTestConfig(t)
+	m := NewManager(cfg, backend, backend)
+	_, err := m.Apply(adminContext())
+	require.NoError(t, err)
+
+	updated, _ := newTestConfig(t)
+	updated.Provisioning.Users[0].Email = "alice@example.org"
+	updated.Provisioning.Users[0].Disabled = true
+	updated.Provisioning.Peers = nil
+	require.NoError(t, m.ReloadConfig(adminContext(), updated))
+	assert.Equal(t, "alice@example.org", backend.users["alice"].Email)
+	assert.True(t, backend.users["alice"].IsDisabled())
+	assert.Contains(t, backend.peers, domain.PeerIdentifier(keys.PublicKey), "peers are only deleted when pruning")
+
+	updated.Provisioning.Prune = true
+	updated.Provisioning.Users = nil
+	plan, err := m.Plan(adminContext())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes, "the plan uses the last applied configuration")
+
+	require.NoError(t, m.ReloadConfig(adminContext(), updated))
+	assert.Empty(t, backend.peers)
+	assert.NotContains(t, backend.users, domain.UserIdentifier("alice"))
+	assert.Contains(t, backend.users, domain.UserIdentifier("admin"), "users that were not provisioned are kept")
+}
+
+func TestManager_Apply_InvalidEntries(t *testing.T) {
+	backend := newMockBackend()
+	cfg, _ := newTestConfig(t)
+	cfg.Provisioning.Users[0].Password = ""
+	cfg.Provisioning.Peers[0].Interface = "wg9"
+	m := NewManager(cfg, backend, backend)
+
+	plan, err := m.Apply(adminContext())
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+	require.NotNil(t, plan)
+	assert.Len(t, plan.Errors, 2)
+	assert.False(t, plan.Applied)
+	assert.NotContains(t, backend.users, domain.UserIdentifier("alice"), "nothing is applied if entries are invalid")
+}
+
+func TestManager_Plan_RequiresAdmin(t *testing.T) {
+	backend := newMockBackend()
+	cfg, _ := newTestConfig(t)
+	m := NewManager(cfg, backend, backend)
+
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "alice"})
+	_, err := m.Plan(ctx)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+}
+
+func TestManager_Apply_KeepsExpiredPeerDisabled(t *testing.T) {
+	backend := newMockBackend()
+	cfg, keys := newTestConfig(t)
+	expired := time.Now().Add(-time.Hour)
+	cfg.Provisioning.Peers[0].ExpiresAt = &expired
+	m := NewManager(cfg, backend, backend)
+	_, err := m.Apply(adminContext())
+	require.NoError(t, err)
+
+	// the expired peers check disables the peer
+	peer := backend.peers[domain.PeerIdentifier(keys.PublicKey)]
+	disabled := time.Now()
+	peer.Disabled = &disabled
+	peer.DisabledReason = domain.DisabledReasonExpired
+
+	plan, err := m.Apply(adminContext())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+	assert.True(t, backend.peers[domain.PeerIdentifier(keys.PublicKey)].IsDisabled())
+
+	// peers disabled through provisioning are enabled again
+	cfg.Provisioning.Peers[0].ExpiresAt = nil
+	peer.DisabledReason = domain.DisabledReasonAdmin
+	_, err = m.Apply(adminContext())
+	require.NoError(t, err)
+	assert.False(t, backend.peers[domain.PeerIdentifier(keys.PublicKey)].IsDisabled())
+}
@@ -0,0 +1,215 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/app/audit"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// region dependencies
+
+// Reloadable is a component that can apply changed settings without a restart.
+type Reloadable interface {
+	// ReloadConfig applies the reloadable settings of the given configuration.
+	// If an error is returned, the component must keep its previous settings.
+	ReloadConfig(ctx context.Context, cfg *config.Config) error
+}
+
+type EventBus interface {
+	// Publish sends a message to the message bus.
+	Publish(topic string, args ...any)
+}
+
+// endregion dependencies
+
+// ReloadFunc is a function that implements the Reloadable interface.
+type ReloadFunc func(ctx context.Context, cfg *config.Config) error
+
+// ReloadConfig calls f(ctx, cfg).
+func (f ReloadFunc) ReloadConfig(ctx context.Context, cfg *config.Config) error {
+	return f(ctx, cfg)
+}
+
+type component struct {
+	name     string
+	settings []string // the settings handled by the component, a setting also covers all nested settings
+	target   Reloadable
+}
+
+// handles returns the given settings that are handled by the component.
+func (c component) handles(settings []string) []string {
+	var handled []string
+	for _, setting := range settings {
+		if c.handlesSetting(setting) {
+			handled = append(handled, setting)
+		}
+	}
+	return handled
+}
+
+func (c component) handlesSetting(setting string) bool {
+	for _, prefix := range c.settings {
+		if setting == prefix || strings.HasPrefix(setting, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Manager re-reads the configuration and applies the changed settings to the registered components.
+type Manager struct {
+	bus  EventBus
+	load func() (*config.Config, error)
+
+	mu         sync.Mutex
+	startup    *config.Config      // the configuration the application was started with
+	current    *config.Config      // the last loaded configuration
+	failed     map[string]struct{} // components that failed to apply the current configuration
+	components []component
+}
+
+// NewManager creates a new reload manager. The load function reads and validates the configuration,
+// usually it is config.GetConfig.
+func NewManager(cfg *config.Config, bus EventBus, load func() (*config.Config, error)) *Manager {
+	return &Manager{
+		bus:     bus,
+		load:    load,
+		startup: cfg,
+		current: cfg,
+		failed:  make(map[string]struct{}),
+	}
+}
+
+// Register registers a component that applies the given settings, for example "auth.ldap" or "webhook".
+// Components are reloaded in the order of registration. Changed settings that are not handled by any component
+// are reported as requiring a restart.
+func (m *Manager) Register(name string, target Reloadable, settings ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.components = append(m.components, component{name: name, settings: settings, target: target})
+}
+
+// StartBackgroundJobs reloads the configuration whenever the process receives SIGHUP.
+// This method is non-blocking and returns immediately.
+func (m *Manager) StartBackgroundJobs(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				slog.Info("received SIGHUP, reloading configuration")
+				reloadCtx := domain.SetUserInfo(ctx, domain.SystemAdminContextUserInfo())
+				if _, err := m.Reload(reloadCtx); err != nil {
+					slog.Error("failed to reload configuration", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Reload re-reads the configuration and applies the changed settings. If the configuration is invalid,
+// nothing is changed and an error is returned. Changed settings that cannot be reloaded are listed in the report.
+func (m *Manager) Reload(ctx context.Context) (*domain.ConfigReloadReport, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.load()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("invalid configuration, nothing has been changed: %w", err),
+			domain.ErrInvalidData)
+	}
+
+	report := &domain.ConfigReloadReport{
+		ReloadedAt: time.Now(),
+	}
+
+	changed := config.ChangedSettings(m.current, cfg)
+	for _, c := range m.components {
+		settings := c.handles(changed)
+		_, failedBefore := m.failed[c.name]
+		if len(settings) == 0 && !failedBefore {
+			continue
+		}
+
+		if err := c.target.ReloadConfig(ctx, cfg); err != nil {
+			m.failed[c.name] = struct{}{}
+			report.Failed = append(report.Failed, domain.ConfigReloadError{
+				Component: c.name,
+				Settings:  settings,
+				Error:     err.Error(),
+			})
+			slog.Error("failed to apply configuration changes", "component", c.name, "settings", settings,
+				"error", err)
+			continue
+		}
+
+		delete(m.failed, c.name)
+		report.Applied = append(report.Applied, settings...)
+	}
+	m.current = cfg
+
+	// compare with the startup configuration, so that settings are reported until the application is restarted
+	for _, setting := range config.ChangedSettings(m.startup, cfg) {
+		if !m.isReloadable(setting) {
+			report.RestartRequired = append(report.RestartRequired, setting)
+		}
+	}
+
+	slices.Sort(report.Applied)
+	report.Applied = slices.Compact(report.Applied)
+
+	if len(changed) > 0 {
+		failed := make([]string, 0, len(report.Failed))
+		for _, f := range report.Failed {
+			failed = append(failed, f.Component)
+		}
+		m.bus.Publish(app.TopicAuditConfigChanged, domain.AuditEventWrapper[audit.ConfigEvent]{
+			Ctx: ctx,
+			Event: audit.ConfigEvent{
+				Settings: changed,
+				Failed:   failed,
+				Action:   "reload",
+			},
+		})
+	}
+
+	slog.Info("configuration reloaded", "applied", report.Applied, "failed", len(report.Failed))
+	if len(report.RestartRequired) > 0 {
+		slog.Warn("changed settings require a restart to take effect", "settings", report.RestartRequired)
+	}
+
+	return report, nil
+}
+
+func (m *Manager) isReloadable(setting string) bool {
+	for _, c := range m.components {
+		if c.handlesSetting(setting) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type recorder struct {
+	calls []*config.Config
+	err   error
+}
+
+func (r *recorder) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	r.calls = append(r.calls, cfg)
+	return r.err
+}
+
+type recordingBus struct {
+	published map[string][]any
+}
+
+func (b *recordingBus) Publish(topic string, args ...any) {
+	if b.published == nil {
+		b.published = make(map[string][]any)
+	}
+	b.published[topic] = args
+}
+
+func adminContext() context.Context {
+	return domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+}
+
+func TestManager_Reload(t *testing.T) {
+	startup := &config.Config{}
+	next := &config.Config{}
+	var loadErr error
+	bus := &recordingBus{}
+	m := NewManager(startup, bus, func() (*config.Config, error) { return next, loadErr })
+
+	webhook, mail := &recorder{}, &recorder{}
+	m.Register("webhook", webhook, "webhook")
+	m.Register("mail", mail, "mail.host", "mail.port")
+
+	next.Webhook.Url = "https://example.com/hook"
+	next.Mail.LinkOnly = true
+	next.Core.AdminUser = "root"
+	report, err := m.Reload(adminContext())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"webhook.url"}, report.Applied)
+	assert.Equal(t, []string{"core.admin_user", "mail.link_only"}, report.RestartRequired)
+	assert.Empty(t, report.Failed)
+	require.Len(t, webhook.calls, 1)
+	assert.Same(t, next, webhook.calls[0])
+	assert.Empty(t, mail.calls, "unchanged components must not be reloaded")
+
+	// a failed component keeps being reloaded until it succeeds
+	next = &config.Config{Webhook: next.Webhook, Mail: next.Mail, Core: next.Core}
+	next.Mail.Host = "smtp.example.com"
+	mail.err = errors.New("connection refused")
+	report, err = m.Reload(adminContext())
+	require.NoError(t, err)
+	assert.Empty(t, report.Applied)
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, domain.ConfigReloadError{Component: "mail", Settings: []string{"mail.host"},
+		Error: "connection refused"}, report.Failed[0])
+	assert.Len(t, webhook.calls, 1)
+
+	mail.err = nil
+	report, err = m.Reload(adminContext())
+	require.NoError(t, err)
+	assert.Empty(t, report.Failed)
+	assert.Len(t, mail.calls, 2)
+	assert.Equal(t, []string{"core.admin_user", "mail.link_only"}, report.RestartRequired,
+		"settings must be reported until the application is restarted")
+
+	// an invalid configuration changes nothing
+	loadErr = errors.New("yaml error")
+	_, err = m.Reload(adminContext())
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+	assert.Len(t, mail.calls, 2)
+}
+
+func TestManager_Reload_RequiresAdmin(t *testing.T) {
+	m := NewManager(&config.Config{}, &recordingBus{}, func() (*config.Config, error) { return &config.Config{}, nil })
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "user", IsAdmin: false})
+
+	_, err := m.Reload(ctx)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+}
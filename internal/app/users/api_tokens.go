@@ -0,0 +1,140 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/app/audit"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// apiTokenLastUsedInterval limits how often the last usage time of a named API token is written to the database.
+const apiTokenLastUsedInterval = time.Minute
+
+// GetApiTokens returns the named API tokens of the given user.
+func (m Manager) GetApiTokens(ctx context.Context, id domain.UserIdentifier) ([]domain.ApiToken, error) {
+	if err := domain.ValidateUserAccessRights(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.users.GetUser(ctx, id); err != nil {
+		return nil, fmt.Errorf("unable to find user %s: %w", id, err)
+	}
+
+	return m.users.GetUserApiTokens(ctx, id)
+}
+
+// GetAllApiTokens returns the named API tokens of all users.
+func (m Manager) GetAllApiTokens(ctx context.Context) ([]domain.ApiToken, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.users.GetAllApiTokens(ctx)
+}
+
+// CreateApiToken creates a new named API token with the name, scopes, source CIDRs and expiry of the given token.
+// The created token and its secret are returned, the secret cannot be retrieved again later.
+func (m Manager) CreateApiToken(ctx context.Context, token *domain.ApiToken) (*domain.ApiToken, string, error) {
+	if err := domain.ValidateUserAccessRights(ctx, token.UserIdentifier); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := m.users.GetUser(ctx, token.UserIdentifier); err != nil {
+		return nil, "", fmt.Errorf("unable to find user %s: %w", token.UserIdentifier, err)
+	}
+
+	newToken, secret, err := domain.NewApiToken(token.UserIdentifier, token.Name, token.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	newToken.SourceCidrs = token.SourceCidrs
+	newToken.ExpiresAt = token.ExpiresAt
+	newToken.CreatedBy = domain.GetUserInfo(ctx).UserId()
+
+	if err := newToken.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	if err := m.users.SaveApiToken(ctx, newToken); err != nil {
+		return nil, "", fmt.Errorf("creation failure: %w", err)
+	}
+
+	m.bus.Publish(app.TopicUserApiTokenCreated, *newToken)
+	m.bus.Publish(app.TopicAuditApiTokenChanged, domain.AuditEventWrapper[audit.ApiTokenEvent]{
+		Ctx:   ctx,
+		Event: audit.ApiTokenEvent{Token: *newToken, Action: "create"},
+	})
+
+	return newToken, secret, nil
+}
+
+// DeleteApiToken revokes the named API token with the given identifier. Users can revoke their own tokens, admins
+// can revoke the tokens of all users.
+func (m Manager) DeleteApiToken(ctx context.Context, id domain.ApiTokenIdentifier) error {
+	token, err := m.users.GetApiToken(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to find api token %s: %w", id, err)
+	}
+
+	if err := domain.ValidateUserAccessRights(ctx, token.UserIdentifier); err != nil {
+		return err
+	}
+
+	if err := m.users.DeleteApiToken(ctx, id); err != nil {
+		return fmt.Errorf("deletion failure: %w", err)
+	}
+
+	m.bus.Publish(app.TopicUserApiTokenRevoked, *token)
+	m.bus.Publish(app.TopicAuditApiTokenChanged, domain.AuditEventWrapper[audit.ApiTokenEvent]{
+		Ctx:   ctx,
+		Event: audit.ApiTokenEvent{Token: *token, Action: "revoke"},
+	})
+
+	return nil
+}
+
+// AuthenticateApiToken returns the user and the named API token for the given token secret. The token must not be
+// expired and must be used from one of its source networks, the user must neither be disabled nor locked.
+func (m Manager) AuthenticateApiToken(ctx context.Context, secret, clientIp string) (
+	*domain.User,
+	*domain.ApiToken,
+	error,
+) {
+	token, err := m.users.GetApiTokenByHash(ctx, domain.HashApiTokenSecret(secret))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, errors.New("unknown api token")
+		}
+		return nil, nil, fmt.Errorf("unable to load api token: %w", err)
+	}
+
+	if token.IsExpired() {
+		return nil, nil, fmt.Errorf("api token %s expired", token.Identifier)
+	}
+	if !token.AllowsSource(clientIp) {
+		return nil, nil, fmt.Errorf("api token %s is not allowed from %s", token.Identifier, clientIp)
+	}
+
+	user, err := m.users.GetUser(ctx, token.UserIdentifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find user %s: %w", token.UserIdentifier, err)
+	}
+	if user.IsDisabled() || user.IsLocked() {
+		return nil, nil, fmt.Errorf("user %s is disabled or locked", user.Identifier)
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= apiTokenLastUsedInterval {
+		if err := m.users.UpdateApiTokenLastUsed(ctx, token.Identifier, now); err != nil {
+			slog.Warn("failed to update api token usage", "token", token.Identifier, "error", err)
+		}
+		token.LastUsedAt = &now
+	}
+
+	return user, token, nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/biezax/wg-portal/internal"
 	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/app/audit"
 	"github.com/biezax/wg-portal/internal/config"
 	"github.com/biezax/wg-portal/internal/domain"
 )
@@ -35,11 +36,29 @@ type UserDatabaseRepo interface {
 	SaveUser(ctx context.Context, id domain.UserIdentifier, updateFunc func(u *domain.User) (*domain.User, error)) error
 	// DeleteUser deletes the user with the given identifier.
 	DeleteUser(ctx context.Context, id domain.UserIdentifier) error
+	// QueryUsers returns one page of the users matching the filter and the total number of matching users.
+	QueryUsers(ctx context.Context, filter domain.UserFilter, opts domain.ListOptions) ([]domain.User, int, error)
+	// GetUserApiTokens returns the named API tokens of the given user.
+	GetUserApiTokens(ctx context.Context, id domain.UserIdentifier) ([]domain.ApiToken, error)
+	// GetAllApiTokens returns the named API tokens of all users.
+	GetAllApiTokens(ctx context.Context) ([]domain.ApiToken, error)
+	// GetApiToken returns the named API token with the given identifier.
+	GetApiToken(ctx context.Context, id domain.ApiTokenIdentifier) (*domain.ApiToken, error)
+	// GetApiTokenByHash returns the named API token with the given secret hash.
+	GetApiTokenByHash(ctx context.Context, hash string) (*domain.ApiToken, error)
+	// SaveApiToken creates or updates the given named API token.
+	SaveApiToken(ctx context.Context, token *domain.ApiToken) error
+	// UpdateApiTokenLastUsed sets the last usage time of the given named API token.
+	UpdateApiTokenLastUsed(ctx context.Context, id domain.ApiTokenIdentifier, lastUsed time.Time) error
+	// DeleteApiToken deletes the named API token with the given identifier.
+	DeleteApiToken(ctx context.Context, id domain.ApiTokenIdentifier) error
 }
 
 type PeerDatabaseRepo interface {
 	// GetUserPeers returns all peers linked to the given user.
 	GetUserPeers(ctx context.Context, id domain.UserIdentifier) ([]domain.Peer, error)
+	// GetUserPeerCounts returns the number of peers of the given users.
+	GetUserPeerCounts(ctx context.Context, ids ...domain.UserIdentifier) (map[domain.UserIdentifier]int, error)
 }
 
 type EventBus interface {
@@ -47,28 +66,50 @@ type EventBus interface {
 	Publish(topic string, args ...any)
 }
 
+type AdmissionController interface {
+	// AdmitUser sends the proposed user to the admission webhooks, old is nil for new users. Mutating webhooks may
+	// change the proposed user.
+	AdmitUser(ctx context.Context, old, new *domain.User) error
+}
+
 // endregion dependencies
 
 // Manager is the user manager.
 type Manager struct {
 	cfg *config.Config
 
-	bus   EventBus
-	users UserDatabaseRepo
-	peers PeerDatabaseRepo
+	bus       EventBus
+	users     UserDatabaseRepo
+	peers     PeerDatabaseRepo
+	admission AdmissionController // optional, nil if no admission webhooks are used
+
+	ldapSync *ldapSynchronization
+}
+
+// ldapSynchronization keeps track of the running LDAP synchronization, it is restarted on configuration reloads.
+type ldapSynchronization struct {
+	mu     sync.Mutex
+	jobCtx context.Context
+	stop   context.CancelFunc
 }
 
 // NewUserManager creates a new user manager instance.
-func NewUserManager(cfg *config.Config, bus EventBus, users UserDatabaseRepo, peers PeerDatabaseRepo) (
-	*Manager,
-	error,
-) {
+func NewUserManager(
+	cfg *config.Config,
+	bus EventBus,
+	users UserDatabaseRepo,
+	peers PeerDatabaseRepo,
+	admission AdmissionController,
+) (*Manager, error) {
 	m := &Manager{
 		cfg: cfg,
 		bus: bus,
 
-		users: users,
-		peers: peers,
+		users:     users,
+		peers:     peers,
+		admission: admission,
+
+		ldapSync: &ldapSynchronization{},
 	}
 	return m, nil
 }
@@ -92,7 +133,33 @@ func (m Manager) RegisterUser(ctx context.Context, user *domain.User) error {
 // StartBackgroundJobs starts the background jobs.
 // This method is non-blocking and returns immediately.
 func (m Manager) StartBackgroundJobs(ctx context.Context) {
-	go m.runLdapSynchronizationService(ctx)
+	m.ldapSync.mu.Lock()
+	defer m.ldapSync.mu.Unlock()
+
+	m.ldapSync.jobCtx = ctx
+	m.startLdapSynchronization(m.cfg.Auth.Ldap)
+}
+
+// ReloadConfig restarts the LDAP synchronization with the LDAP providers of the given configuration.
+func (m Manager) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	m.ldapSync.mu.Lock()
+	defer m.ldapSync.mu.Unlock()
+
+	if m.ldapSync.jobCtx == nil {
+		return nil // background jobs not started yet
+	}
+	m.ldapSync.stop()
+	m.startLdapSynchronization(cfg.Auth.Ldap)
+
+	return nil
+}
+
+// startLdapSynchronization starts the synchronization of the given LDAP providers. The caller must hold the lock.
+func (m Manager) startLdapSynchronization(providers []config.LdapProvider) {
+	ctx, cancel := context.WithCancel(m.ldapSync.jobCtx)
+	m.ldapSync.stop = cancel
+
+	go m.runLdapSynchronizationService(ctx, providers)
 }
 
 // GetUser returns the user with the given identifier.
@@ -183,6 +250,39 @@ func (m Manager) GetAllUsers(ctx context.Context) ([]domain.User, error) {
 	return users, nil
 }
 
+// QueryUsers returns one page of the users that match the given filter and the total number of matching users.
+func (m Manager) QueryUsers(ctx context.Context, filter domain.UserFilter, opts domain.ListOptions) (
+	[]domain.User,
+	int,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := opts.Validate(domain.UserSortFields); err != nil {
+		return nil, 0, err
+	}
+
+	users, total, err := m.users.QueryUsers(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to load users: %w", err)
+	}
+
+	ids := make([]domain.UserIdentifier, len(users))
+	for i := range users {
+		ids[i] = users[i].Identifier
+	}
+	counts, err := m.peers.GetUserPeerCounts(ctx, ids...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to count peers: %w", err)
+	}
+	for i := range users {
+		users[i].LinkedPeerCount = counts[users[i].Identifier]
+	}
+
+	return users, total, nil
+}
+
 // UpdateUser updates the user with the given identifier.
 func (m Manager) UpdateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
 	if err := domain.ValidateUserAccessRights(ctx, user.Identifier); err != nil {
@@ -216,6 +316,7 @@ func (m Manager) UpdateUser(ctx context.Context, user *domain.User) (*domain.Use
 	}
 
 	m.bus.Publish(app.TopicUserUpdated, *user)
+	m.publishAuditEvent(ctx, "update", user, existingUser)
 
 	switch {
 	case !existingUser.IsDisabled() && user.IsDisabled():
@@ -263,6 +364,7 @@ func (m Manager) CreateUser(ctx context.Context, user *domain.User) (*domain.Use
 	}
 
 	m.bus.Publish(app.TopicUserCreated, *user)
+	m.publishAuditEvent(ctx, "create", user, nil)
 
 	return user, nil
 }
@@ -288,6 +390,7 @@ func (m Manager) DeleteUser(ctx context.Context, id domain.UserIdentifier) error
 	}
 
 	m.bus.Publish(app.TopicUserDeleted, *existingUser)
+	m.publishAuditEvent(ctx, "delete", existingUser, nil)
 
 	return nil
 }
@@ -303,6 +406,7 @@ func (m Manager) ActivateApi(ctx context.Context, id domain.UserIdentifier) (*do
 		return nil, err
 	}
 
+	previous := *user
 	now := time.Now()
 	user.ApiToken = uuid.New().String()
 	user.ApiTokenCreated = &now
@@ -317,6 +421,7 @@ func (m Manager) ActivateApi(ctx context.Context, id domain.UserIdentifier) (*do
 
 	m.bus.Publish(app.TopicUserUpdated, *user)
 	m.bus.Publish(app.TopicUserApiEnabled, *user)
+	m.publishAuditEvent(ctx, "api-enable", user, &previous)
 
 	return user, nil
 }
@@ -332,6 +437,7 @@ func (m Manager) DeactivateApi(ctx context.Context, id domain.UserIdentifier) (*
 		return nil, err
 	}
 
+	previous := *user
 	user.ApiToken = ""
 	user.ApiTokenCreated = nil
 
@@ -345,6 +451,7 @@ func (m Manager) DeactivateApi(ctx context.Context, id domain.UserIdentifier) (*
 
 	m.bus.Publish(app.TopicUserUpdated, *user)
 	m.bus.Publish(app.TopicUserApiDisabled, *user)
+	m.publishAuditEvent(ctx, "api-disable", user, &previous)
 
 	return user, nil
 }
@@ -384,6 +491,13 @@ func (m Manager) validateModifications(ctx context.Context, old, new *domain.Use
 		return fmt.Errorf("cannot change user source: %w", domain.ErrInvalidData)
 	}
 
+	// changes made by the system itself, like disabling expired peers, are not subject to admission webhooks
+	if m.admission != nil && currentUser.Id != domain.SystemAdminContextUserInfo().Id {
+		if err := m.admission.AdmitUser(ctx, old, new); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -429,6 +543,12 @@ func (m Manager) validateCreation(ctx context.Context, new *domain.User) error {
 		return errors.Join(fmt.Errorf("password too weak: %w", err), domain.ErrInvalidData)
 	}
 
+	if m.admission != nil && currentUser.Id != domain.SystemAdminContextUserInfo().Id {
+		if err := m.admission.AdmitUser(ctx, nil, new); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -460,10 +580,10 @@ func (m Manager) validateApiChange(ctx context.Context, user *domain.User) error
 	return nil
 }
 
-func (m Manager) runLdapSynchronizationService(ctx context.Context) {
+func (m Manager) runLdapSynchronizationService(ctx context.Context, providers []config.LdapProvider) {
 	ctx = domain.SetUserInfo(ctx, domain.LdapSyncContextUserInfo()) // switch to service context for LDAP sync
 
-	for _, ldapCfg := range m.cfg.Auth.Ldap { // LDAP Auth providers
+	for _, ldapCfg := range providers { // LDAP Auth providers
 		go func(cfg config.LdapProvider) {
 			syncInterval := cfg.SyncInterval
 			if syncInterval == 0 {
@@ -563,7 +683,7 @@ func (m Manager) updateLdapUsers(
 		}
 
 		tctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		tctx = domain.SetUserInfo(tctx, domain.SystemAdminContextUserInfo())
+		tctx = domain.SetUserInfo(tctx, domain.LdapSyncContextUserInfo())
 
 		if existingUser == nil {
 			// create new user
@@ -584,6 +704,7 @@ func (m Manager) updateLdapUsers(
 				user.DisabledReason = existingUser.DisabledReason
 			}
 			if existingUser.Source == domain.UserSourceLdap && userChangedInLdap(existingUser, user) {
+				var updatedUser domain.User
 				err := m.users.SaveUser(tctx, user.Identifier, func(u *domain.User) (*domain.User, error) {
 					u.UpdatedAt = time.Now()
 					u.UpdatedBy = domain.CtxSystemLdapSyncer
@@ -598,6 +719,7 @@ func (m Manager) updateLdapUsers(
 					u.Disabled = nil
 					u.DisabledReason = ""
 
+					updatedUser = *u
 					return u, nil
 				})
 				if err != nil {
@@ -605,6 +727,8 @@ func (m Manager) updateLdapUsers(
 					return fmt.Errorf("update error for user id %s: %w", user.Identifier, err)
 				}
 
+				m.publishAuditEvent(tctx, "ldap-sync", &updatedUser, existingUser)
+
 				if existingUser.IsDisabled() && !user.IsDisabled() {
 					m.bus.Publish(app.TopicUserEnabled, *user)
 				}
@@ -653,6 +777,7 @@ func (m Manager) disableMissingLdapUsers(
 
 		slog.Debug("user is missing in ldap provider, disabling", "user", user.Identifier, "provider", providerName)
 
+		previous := user
 		now := time.Now()
 		user.Disabled = &now
 		user.DisabledReason = domain.DisabledReasonLdapMissing
@@ -667,7 +792,20 @@ func (m Manager) disableMissingLdapUsers(
 		}
 
 		m.bus.Publish(app.TopicUserDisabled, user)
+		m.publishAuditEvent(ctx, "ldap-disable", &user, &previous)
 	}
 
 	return nil
 }
+
+// publishAuditEvent publishes the change of a user for the audit log, previous is nil for new users.
+func (m Manager) publishAuditEvent(ctx context.Context, action string, user, previous *domain.User) {
+	m.bus.Publish(app.TopicAuditUserChanged, domain.AuditEventWrapper[audit.UserEvent]{
+		Ctx: ctx,
+		Event: audit.UserEvent{
+			User:     *user,
+			Previous: previous,
+			Action:   action,
+		},
+	})
+}
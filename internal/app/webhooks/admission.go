@@ -0,0 +1,362 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/biezax/wg-portal/internal/app/webhooks/models"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+const (
+	AdmissionOperationCreate = "create"
+	AdmissionOperationUpdate = "update"
+
+	maxAdmissionResponseSize = 1024 * 1024
+)
+
+// AdmissionRequest is the request body of admission webhooks.
+type AdmissionRequest struct {
+	// Uid is the unique identifier of the request, it is also sent in the X-Wg-Portal-Delivery header.
+	Uid string `json:"uid"`
+	// Type is the type of the webhook: validating or mutating.
+	Type string `json:"type" example:"validating"`
+	// Operation is the requested operation: create or update.
+	Operation string `json:"operation" example:"create"`
+	// Entity is the entity type: peer or user.
+	Entity WebhookEntity `json:"entity" example:"peer"`
+	// Identifier is the identifier of the entity.
+	Identifier string `json:"identifier"`
+	// User is the identifier of the user that requested the change.
+	User string `json:"user"`
+	// Object is the proposed peer or user, see models.Peer and models.User.
+	Object any `json:"object"`
+	// OldObject is the stored peer or user, it is only set for updates.
+	OldObject any `json:"old_object,omitempty"`
+}
+
+// AdmissionResponse is the expected response body of admission webhooks.
+type AdmissionResponse struct {
+	// Allowed decides whether the change is stored.
+	Allowed bool `json:"allowed"`
+	// Message is the reason of a denial, it is shown to the user.
+	Message string `json:"message,omitempty"`
+	// Patch is a JSON patch (RFC 6902) of the proposed object, it is only applied for mutating webhooks.
+	Patch []PatchOperation `json:"patch,omitempty"`
+}
+
+// AdmissionController calls the admission webhooks before peers and users are created or updated.
+type AdmissionController struct {
+	client *http.Client
+
+	// mu protects the webhooks, they are replaced on configuration reloads.
+	mu      sync.RWMutex
+	hooks   []config.AdmissionWebhook
+	timeout time.Duration
+}
+
+// NewAdmissionController creates a new admission controller for the admission webhooks of the configuration.
+func NewAdmissionController(cfg *config.Config) *AdmissionController {
+	c := &AdmissionController{
+		client: &http.Client{},
+	}
+	c.setWebhooks(cfg.Webhook)
+
+	return c
+}
+
+// ReloadConfig replaces the admission webhooks with the ones of the given configuration.
+func (c *AdmissionController) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	c.setWebhooks(cfg.Webhook)
+	return nil
+}
+
+func (c *AdmissionController) setWebhooks(cfg config.WebhookConfig) {
+	// mutating webhooks are called first, so that the validating webhooks see the final object
+	hooks := make([]config.AdmissionWebhook, 0, len(cfg.Admission))
+	for _, hook := range cfg.Admission {
+		if hook.Type == config.AdmissionWebhookMutating {
+			hooks = append(hooks, hook)
+		}
+	}
+	for _, hook := range cfg.Admission {
+		if hook.Type != config.AdmissionWebhookMutating {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = hooks
+	c.timeout = cfg.Timeout
+}
+
+// AdmitPeer sends the proposed peer to the matching admission webhooks, old is nil for new peers. Mutating webhooks
+// may change the proposed peer. An error is returned if a webhook denies the change.
+func (c *AdmissionController) AdmitPeer(ctx context.Context, old, new *domain.Peer) error {
+	var oldObject any
+	if old != nil {
+		oldObject = models.NewPeer(*old)
+	}
+
+	return c.admit(ctx, WebhookEntityPeer, string(new.Identifier), oldObject,
+		func() any { return models.NewPeer(*new) },
+		func(patched []byte) error { return patchPeer(new, patched) })
+}
+
+// AdmitUser sends the proposed user to the matching admission webhooks, old is nil for new users. Mutating webhooks
+// may change the proposed user. An error is returned if a webhook denies the change.
+func (c *AdmissionController) AdmitUser(ctx context.Context, old, new *domain.User) error {
+	var oldObject any
+	if old != nil {
+		oldObject = models.NewUser(*old)
+	}
+
+	return c.admit(ctx, WebhookEntityUser, string(new.Identifier), oldObject,
+		func() any { return models.NewUser(*new) },
+		func(patched []byte) error { return patchUser(new, patched) })
+}
+
+// admit calls the matching webhooks in order. The object function returns the current webhook model of the proposed
+// entity, the patch function applies the patched model to the proposed entity.
+func (c *AdmissionController) admit(
+	ctx context.Context,
+	entity WebhookEntity,
+	identifier string,
+	oldObject any,
+	object func() any,
+	patch func(patched []byte) error,
+) error {
+	c.mu.RLock()
+	hooks, timeout := c.hooks, c.timeout
+	c.mu.RUnlock()
+
+	operation := AdmissionOperationCreate
+	if oldObject != nil {
+		operation = AdmissionOperationUpdate
+	}
+
+	for _, hook := range hooks {
+		if len(hook.Entities) > 0 && !slices.Contains(hook.Entities, entity) ||
+			len(hook.Operations) > 0 && !slices.Contains(hook.Operations, operation) {
+			continue
+		}
+
+		request := AdmissionRequest{
+			Uid:        uuid.New().String(),
+			Type:       hook.Type,
+			Operation:  operation,
+			Entity:     entity,
+			Identifier: identifier,
+			User:       string(domain.GetUserInfo(ctx).Id),
+			Object:     object(),
+			OldObject:  oldObject,
+		}
+
+		response, err := c.review(ctx, hook, timeout, &request, patch)
+		switch {
+		case err != nil && hook.FailurePolicy == config.AdmissionFailurePolicyIgnore:
+			slog.Warn("[WEBHOOK] admission webhook failed, ignoring it", "webhook", hook.Name, "entity", entity,
+				"identifier", identifier, "error", err)
+		case err != nil:
+			return fmt.Errorf("admission webhook %s failed: %w", hook.Name, err)
+		case !response.Allowed:
+			message := response.Message
+			if message == "" {
+				message = "no reason given"
+			}
+			return errors.Join(fmt.Errorf("denied by admission webhook %s: %s", hook.Name, message),
+				domain.ErrInvalidData)
+		}
+	}
+
+	return nil
+}
+
+// review sends the request to the webhook and applies the returned patch of mutating webhooks.
+func (c *AdmissionController) review(
+	ctx context.Context,
+	hook config.AdmissionWebhook,
+	timeout time.Duration,
+	request *AdmissionRequest,
+	patch func(patched []byte) error,
+) (*AdmissionResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize request: %w", err)
+	}
+
+	if hook.Timeout > 0 {
+		timeout = hook.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryId, request.Uid)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if hook.Authentication != "" {
+		req.Header.Set("Authorization", hook.Authentication)
+	}
+	if hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	var response AdmissionResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxAdmissionResponseSize)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	if !response.Allowed || len(response.Patch) == 0 {
+		return &response, nil
+	}
+	if hook.Type != config.AdmissionWebhookMutating {
+		slog.Warn("[WEBHOOK] ignoring patch of validating admission webhook", "webhook", hook.Name)
+		return &response, nil
+	}
+
+	object, err := json.Marshal(request.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize object: %w", err)
+	}
+	patched, err := applyPatch(object, response.Patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	if err := patch(patched); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+
+	return &response, nil
+}
+
+// patchPeer applies the patched webhook model to the peer. Only the fields that do not affect the identity, the keys
+// or the interface hooks of the peer may be changed.
+func patchPeer(peer *domain.Peer, patched []byte) error {
+	var model models.Peer
+	if err := json.Unmarshal(patched, &model); err != nil {
+		return err
+	}
+
+	updated := *peer
+	updated.DisplayName = model.DisplayName
+	updated.Notes = model.Notes
+	updated.UserIdentifier = domain.UserIdentifier(model.UserIdentifier)
+	updated.Disabled = model.Disabled
+	updated.DisabledReason = model.DisabledReason
+	updated.ExpiresAt = model.ExpiresAt
+	updated.Endpoint.SetValue(model.Endpoint)
+	updated.AllowedIPsStr.SetValue(model.AllowedIPsStr)
+	updated.ExtraAllowedIPsStr = model.ExtraAllowedIPsStr
+	updated.PersistentKeepalive.SetValue(model.PersistentKeepalive)
+	updated.Interface.DnsStr.SetValue(model.DnsStr)
+	updated.Interface.DnsSearchStr.SetValue(model.DnsSearchStr)
+	updated.Interface.Mtu.SetValue(model.Mtu)
+	if !slices.Equal(model.Addresses, domain.CidrsToStringSlice(peer.Interface.Addresses)) {
+		addresses, err := domain.CidrsFromArray(model.Addresses)
+		if err != nil {
+			return fmt.Errorf("invalid addresses: %w", err)
+		}
+		updated.Interface.Addresses = addresses
+	}
+
+	if err := checkReadOnlyFields(models.NewPeer(updated), model); err != nil {
+		return err
+	}
+	if isSystemDisabled(peer.Disabled, peer.DisabledReason) &&
+		(updated.Disabled == nil || updated.DisabledReason != peer.DisabledReason) {
+		return fmt.Errorf("the patch enables a peer that has been disabled by the system: %s", peer.DisabledReason)
+	}
+
+	*peer = updated
+	return nil
+}
+
+// patchUser applies the patched webhook model to the user. The identifier, the source and the admin flag cannot be
+// changed.
+func patchUser(user *domain.User, patched []byte) error {
+	var model models.User
+	if err := json.Unmarshal(patched, &model); err != nil {
+		return err
+	}
+
+	updated := *user
+	updated.Email = model.Email
+	updated.Firstname = model.Firstname
+	updated.Lastname = model.Lastname
+	updated.Phone = model.Phone
+	updated.Department = model.Department
+	updated.Notes = model.Notes
+	updated.Disabled = model.Disabled
+	updated.DisabledReason = model.DisabledReason
+	updated.Locked = model.Locked
+	updated.LockedReason = model.LockedReason
+
+	if err := checkReadOnlyFields(models.NewUser(updated), model); err != nil {
+		return err
+	}
+	if isSystemDisabled(user.Disabled, user.DisabledReason) &&
+		(updated.Disabled == nil || updated.DisabledReason != user.DisabledReason) {
+		return fmt.Errorf("the patch enables a user that has been disabled by the system: %s", user.DisabledReason)
+	}
+
+	*user = updated
+	return nil
+}
+
+// isSystemDisabled returns true if the entity has been disabled by WireGuard Portal itself, for example because it
+// expired or its user has been deleted. Admission webhooks cannot enable such entities.
+func isSystemDisabled(disabled *time.Time, reason string) bool {
+	return disabled != nil && reason != "" && reason != domain.DisabledReasonAdmin && reason != domain.DisabledReasonApi
+}
+
+// checkReadOnlyFields returns an error if the patched model differs from the model of the updated entity, which
+// means that the patch changed a field that cannot be changed by admission webhooks.
+func checkReadOnlyFields(updated, patched any) error {
+	want, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	got, err := json.Marshal(patched)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(want, got) {
+		return errors.New("the patch changes fields that cannot be changed by admission webhooks")
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// newAdmissionServer returns a webhook server that answers every admission request with the given response.
+func newAdmissionServer(t *testing.T, requests *[]AdmissionRequest, response string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request AdmissionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		*requests = append(*requests, request)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestAdmissionController(hooks ...config.AdmissionWebhook) *AdmissionController {
+	cfg := &config.Config{}
+	cfg.Webhook.Timeout = time.Second
+	cfg.Webhook.Admission = hooks
+	return NewAdmissionController(cfg)
+}
+
+func newTestPeer() *domain.Peer {
+	peer := &domain.Peer{
+		Identifier:          "peer-key",
+		DisplayName:         "laptop",
+		InterfaceIdentifier: "wg0",
+	}
+	peer.Interface.PublicKey = "peer-key"
+	address, _ := domain.CidrFromString("10.0.0.2/32")
+	peer.Interface.Addresses = []domain.Cidr{address}
+	return peer
+}
+
+func TestAdmissionController_Validating(t *testing.T) {
+	var requests []AdmissionRequest
+	srv := newAdmissionServer(t, &requests, `{"allowed": false, "message": "asset tag missing"}`)
+	c := newTestAdmissionController(config.AdmissionWebhook{
+		Name: "cmdb", Url: srv.URL, Type: config.AdmissionWebhookValidating, Entities: []string{"peer"},
+	})
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "admin", IsAdmin: true})
+
+	err := c.AdmitPeer(ctx, nil, newTestPeer())
+	require.ErrorIs(t, err, domain.ErrInvalidData)
+	assert.Contains(t, err.Error(), "asset tag missing")
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, AdmissionOperationCreate, requests[0].Operation)
+	assert.Equal(t, WebhookEntityPeer, requests[0].Entity)
+	assert.Equal(t, "peer-key", requests[0].Identifier)
+	assert.Equal(t, "admin", requests[0].User)
+	assert.Nil(t, requests[0].OldObject)
+
+	// users do not match the entity filter
+	require.NoError(t, c.AdmitUser(ctx, nil, &domain.User{Identifier: "alice"}))
+	assert.Len(t, requests, 1)
+}
+
+func TestAdmissionController_Mutating(t *testing.T) {
+	var requests []AdmissionRequest
+	mutating := newAdmissionServer(t, &requests, `{"allowed": true, "patch": [
+		{"op": "replace", "path": "/DisplayName", "value": "LAP-0042"},
+		{"op": "add", "path": "/Notes", "value": "registered in CMDB"}
+	]}`)
+	validating := newAdmissionServer(t, &requests, `{"allowed": true}`)
+	c := newTestAdmissionController(
+		config.AdmissionWebhook{Name: "check", Url: validating.URL, Type: config.AdmissionWebhookValidating},
+		config.AdmissionWebhook{Name: "naming", Url: mutating.URL, Type: config.AdmissionWebhookMutating},
+	)
+
+	old := newTestPeer()
+	peer := newTestPeer()
+	require.NoError(t, c.AdmitPeer(context.Background(), old, peer))
+	assert.Equal(t, "LAP-0042", peer.DisplayName)
+	assert.Equal(t, "registered in CMDB", peer.Notes)
+	assert.Equal(t, old.Interface.Addresses, peer.Interface.Addresses)
+
+	// the mutating webhook is called first, the validating webhook receives the patched peer
+	require.Len(t, requests, 2)
+	assert.Equal(t, config.AdmissionWebhookMutating, requests[0].Type)
+	assert.Equal(t, AdmissionOperationUpdate, requests[0].Operation)
+	assert.NotNil(t, requests[0].OldObject)
+	assert.Equal(t, "LAP-0042", requests[1].Object.(map[string]any)["DisplayName"])
+}
+
+func TestAdmissionController_ReadOnlyFields(t *testing.T) {
+	var requests []AdmissionRequest
+	srv := newAdmissionServer(t, &requests, `{"allowed": true, "patch": [
+		{"op": "replace", "path": "/PublicKey", "value": "other-key"}
+	]}`)
+	c := newTestAdmissionController(config.AdmissionWebhook{
+		Name: "naming", Url: srv.URL, Type: config.AdmissionWebhookMutating,
+	})
+
+	peer := newTestPeer()
+	err := c.AdmitPeer(context.Background(), nil, peer)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be changed")
+	assert.Equal(t, "peer-key", peer.Interface.PublicKey)
+
+	srv = newAdmissionServer(t, &requests, `{"allowed": true, "patch": [
+		{"op": "replace", "path": "/IsAdmin", "value": true}
+	]}`)
+	c = newTestAdmissionController(config.AdmissionWebhook{
+		Name: "roles", Url: srv.URL, Type: config.AdmissionWebhookMutating,
+	})
+
+	user := &domain.User{Identifier: "alice", Email: "alice@example.com"}
+	require.Error(t, c.AdmitUser(context.Background(), nil, user))
+	assert.False(t, user.IsAdmin)
+}
+
+func TestAdmissionController_FailurePolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"allowed": false}`))
+	}))
+	defer srv.Close()
+
+	hook := config.AdmissionWebhook{
+		Name: "slow", Url: srv.URL, Type: config.AdmissionWebhookValidating, Timeout: 10 * time.Millisecond,
+	}
+	user := &domain.User{Identifier: "alice"}
+
+	err := newTestAdmissionController(hook).AdmitUser(context.Background(), nil, user)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrInvalidData)
+
+	hook.FailurePolicy = config.AdmissionFailurePolicyIgnore
+	require.NoError(t, newTestAdmissionController(hook).AdmitUser(context.Background(), nil, user))
+}
+
+func TestApplyPatch(t *testing.T) {
+	doc := `{"a": 1, "list": ["x", "y"], "obj": {"b~c": true, "d/e": null}}`
+
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{"add member", `[{"op": "add", "path": "/n", "value": "v"}]`,
+			`{"a": 1, "list": ["x", "y"], "n": "v", "obj": {"b~c": true, "d/e": null}}`},
+		{"insert and append",
+			`[{"op": "add", "path": "/list/0", "value": "w"}, {"op": "add", "path": "/list/-", "value": "z"}]`,
+			`{"a": 1, "list": ["w", "x", "y", "z"], "obj": {"b~c": true, "d/e": null}}`},
+		{"remove escaped", `[{"op": "remove", "path": "/obj/b~0c"}, {"op": "remove", "path": "/obj/d~1e"}]`,
+			`{"a": 1, "list": ["x", "y"], "obj": {}}`},
+		{"replace array element", `[{"op": "replace", "path": "/list/1", "value": 2}]`,
+			`{"a": 1, "list": ["x", 2], "obj": {"b~c": true, "d/e": null}}`},
+		{"move", `[{"op": "move", "from": "/a", "path": "/obj/a"}]`,
+			`{"list": ["x", "y"], "obj": {"a": 1, "b~c": true, "d/e": null}}`},
+		{"copy", `[{"op": "copy", "from": "/list", "path": "/copy"}]`,
+			`{"a": 1, "copy": ["x", "y"], "list": ["x", "y"], "obj": {"b~c": true, "d/e": null}}`},
+		{"test", `[{"op": "test", "path": "/obj/d~1e", "value": null}]`, doc},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.patch), &patch))
+			got, err := applyPatch([]byte(doc), patch)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+
+	for _, patch := range []string{
+		`[{"op": "replace", "path": "/missing", "value": 1}]`,
+		`[{"op": "remove", "path": "/list/2"}]`,
+		`[{"op": "add", "path": "/list/01", "value": 1}]`,
+		`[{"op": "test", "path": "/a", "value": 2}]`,
+		`[{"op": "move", "from": "/obj", "path": "/obj/child"}]`,
+		`[{"op": "add", "path": "/n"}]`,
+		`[{"op": "merge", "path": "/a", "value": 1}]`,
+	} {
+		var ops []PatchOperation
+		require.NoError(t, json.Unmarshal([]byte(patch), &ops))
+		_, err := applyPatch([]byte(doc), ops)
+		assert.Error(t, err, patch)
+	}
+}
+
+func TestAdmissionController_SystemDisabled(t *testing.T) {
+	var requests []AdmissionRequest
+	srv := newAdmissionServer(t, &requests, `{"allowed": true, "patch": [
+		{"op": "replace", "path": "/Disabled", "value": null},
+		{"op": "replace", "path": "/DisabledReason", "value": ""}
+	]}`)
+	c := newTestAdmissionController(config.AdmissionWebhook{
+		Name: "enabler", Url: srv.URL, Type: config.AdmissionWebhookMutating,
+	})
+
+	disabled := time.Now().Truncate(time.Second)
+	peer := newTestPeer()
+	peer.Disabled = &disabled
+	peer.DisabledReason = domain.DisabledReasonExpired
+	err := c.AdmitPeer(context.Background(), nil, peer)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disabled by the system")
+	assert.True(t, peer.IsDisabled())
+
+	user := &domain.User{Identifier: "alice", Disabled: &disabled, DisabledReason: domain.DisabledReasonLdapMissing}
+	require.Error(t, c.AdmitUser(context.Background(), nil, user))
+	assert.True(t, user.IsDisabled())
+
+	// peers disabled by an admin may be enabled by mutating webhooks
+	peer.DisabledReason = domain.DisabledReasonAdmin
+	require.NoError(t, c.AdmitPeer(context.Background(), nil, peer))
+	assert.False(t, peer.IsDisabled())
+}
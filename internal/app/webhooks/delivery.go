@@ -0,0 +1,273 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+const (
+	// HeaderDeliveryId contains the identifier of the delivery, it is the same for all attempts of a delivery.
+	HeaderDeliveryId = "X-Wg-Portal-Delivery"
+	// HeaderTimestamp contains the unix time of the attempt, it is part of the signed data.
+	HeaderTimestamp = "X-Wg-Portal-Timestamp"
+	// HeaderSignature contains the HMAC-SHA256 signature of the request, prefixed with "sha256=".
+	HeaderSignature = "X-Wg-Portal-Signature"
+
+	deliveryPollInterval    = 5 * time.Second
+	deliveryCleanupInterval = time.Hour
+	deliveryBatchSize       = 50
+	maxRetryInterval        = time.Hour
+	maxResponseBodySize     = 4 * 1024
+)
+
+// Sign returns the signature of a webhook request body, as sent in the HeaderSignature header.
+// The signed data is the timestamp header value, a dot and the request body.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetDeliveries returns a page of the webhook deliveries and their total number.
+func (m *Manager) GetDeliveries(
+	ctx context.Context,
+	filter domain.WebhookDeliveryFilter,
+	opts domain.ListOptions,
+) ([]domain.WebhookDelivery, int, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	if err := opts.Validate(domain.WebhookDeliverySortFields); err != nil {
+		return nil, 0, err
+	}
+
+	return m.repo.QueryWebhookDeliveries(ctx, filter, opts)
+}
+
+// GetDelivery returns the webhook delivery with the given identifier.
+func (m *Manager) GetDelivery(ctx context.Context, id domain.WebhookDeliveryIdentifier) (
+	*domain.WebhookDelivery,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.repo.GetWebhookDelivery(ctx, id)
+}
+
+// Redeliver queues the webhook delivery with the given identifier again, it is sent with the full number of attempts.
+func (m *Manager) Redeliver(ctx context.Context, id domain.WebhookDeliveryIdentifier) (
+	*domain.WebhookDelivery,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	delivery, err := m.repo.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Requeue()
+	if err := m.repo.SaveWebhookDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to requeue delivery %s: %w", id, err)
+	}
+
+	m.wakeDeliveryWorker()
+
+	return delivery, nil
+}
+
+// wakeDeliveryWorker triggers the delivery worker without waiting for the next poll.
+func (m *Manager) wakeDeliveryWorker() {
+	select {
+	case m.wakeup <- struct{}{}:
+	default: // the worker is already triggered
+	}
+}
+
+func (m *Manager) runDeliveryWorker(ctx context.Context) {
+	pollTicker := time.NewTicker(deliveryPollInterval)
+	defer pollTicker.Stop()
+	cleanupTicker := time.NewTicker(deliveryCleanupInterval)
+	defer cleanupTicker.Stop()
+
+	slog.Debug("[WEBHOOK] delivery worker started")
+
+	m.deliverDue(ctx) // deliveries that were pending on shutdown
+	m.cleanupDeliveries(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Debug("[WEBHOOK] delivery worker stopped")
+			return
+		case <-m.wakeup:
+			m.deliverDue(ctx)
+		case <-pollTicker.C:
+			m.deliverDue(ctx)
+		case <-cleanupTicker.C:
+			m.cleanupDeliveries(ctx)
+		}
+	}
+}
+
+// deliverDue sends all pending deliveries that are due, in the order they were created.
+func (m *Manager) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := m.repo.GetDueWebhookDeliveries(ctx, time.Now(), deliveryBatchSize)
+		if err != nil {
+			slog.Error("[WEBHOOK] failed to load due deliveries", "error", err)
+			return
+		}
+
+		for i := range deliveries {
+			m.attemptDelivery(ctx, &deliveries[i])
+		}
+
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
+
+// attemptDelivery sends the delivery once and stores the result. Failed deliveries are scheduled for a retry with
+// exponential backoff, or marked as failed if the maximum number of attempts is reached.
+func (m *Manager) attemptDelivery(ctx context.Context, delivery *domain.WebhookDelivery) {
+	webhook, client := m.getWebhook()
+
+	var responseStatus int
+	var responseBody string
+	var err error
+	retryable := true
+	subscriptionId := delivery.Subscription
+	if subscriptionId == "" {
+		subscriptionId = domain.DefaultWebhookSubscription // deliveries created before subscriptions were added
+	}
+	if subscription, ok := m.getSubscription(subscriptionId); ok {
+		responseStatus, responseBody, err = m.send(ctx, client, subscription.Authentication, subscription.Secret,
+			delivery)
+	} else {
+		err = fmt.Errorf("webhook subscription %s has been removed", subscriptionId)
+		retryable = false
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.UpdatedAt = now
+	delivery.ResponseStatus = responseStatus
+	delivery.ResponseBody = responseBody
+	delivery.LastError = ""
+
+	switch {
+	case err == nil:
+		delivery.Status = domain.WebhookDeliveryStatusDelivered
+		delivery.NextAttemptAt = nil
+		slog.Debug("[WEBHOOK] delivered webhook", "delivery", delivery.Identifier, "event", delivery.Event,
+			"attempts", delivery.Attempts)
+	case !retryable || delivery.Attempts >= webhook.MaxAttempts:
+		delivery.Status = domain.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+		slog.Error("[WEBHOOK] giving up webhook delivery", "delivery", delivery.Identifier,
+			"event", delivery.Event, "attempts", delivery.Attempts, "error", err)
+	default:
+		nextAttempt := now.Add(retryDelay(webhook.RetryInterval, delivery.Attempts))
+		delivery.NextAttemptAt = &nextAttempt
+		delivery.LastError = err.Error()
+		slog.Warn("[WEBHOOK] webhook delivery failed, retrying later", "delivery", delivery.Identifier,
+			"event", delivery.Event, "attempts", delivery.Attempts, "next", nextAttempt, "error", err)
+	}
+
+	// the result must be stored even if the worker is stopped, otherwise the delivery would be sent twice
+	if err := m.repo.SaveWebhookDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		slog.Error("[WEBHOOK] failed to store delivery result", "delivery", delivery.Identifier, "error", err)
+	}
+}
+
+// send performs a single delivery attempt. It returns the status code and the truncated body of the response,
+// a non-2xx status code is returned as error.
+func (m *Manager) send(
+	ctx context.Context,
+	client *http.Client,
+	authentication, secret string,
+	delivery *domain.WebhookDelivery,
+) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	contentType := delivery.ContentType
+	if contentType == "" {
+		contentType = "application/json" // deliveries created before payload templates were added
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(HeaderDeliveryId, string(delivery.Identifier))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if authentication != "" {
+		req.Header.Set("Authorization", authentication)
+	}
+	if secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, timestamp, delivery.Payload))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(body), fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+func (m *Manager) cleanupDeliveries(ctx context.Context) {
+	webhook, _ := m.getWebhook()
+
+	deleted, err := m.repo.DeleteWebhookDeliveriesBefore(ctx, time.Now().Add(-webhook.DeliveryRetention))
+	if err != nil {
+		slog.Error("[WEBHOOK] failed to delete old deliveries", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.Debug("[WEBHOOK] deleted old deliveries", "count", deleted)
+	}
+}
+
+// retryDelay returns the delay before the next attempt, it doubles with every attempt up to maxRetryInterval.
+func retryDelay(interval time.Duration, attempts int) time.Duration {
+	delay := interval
+	for i := 1; i < attempts && delay < maxRetryInterval; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryInterval)
+}
@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/app/webhooks/models"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type mockBus struct{}
+
+func (m mockBus) Publish(string, ...any) {}
+
+func (m mockBus) Subscribe(string, interface{}) error { return nil }
+
+type mockRepo struct {
+	mu            sync.Mutex
+	subscriptions []domain.WebhookSubscription
+	deliveries    map[domain.WebhookDeliveryIdentifier]domain.WebhookDelivery
+}
+
+func (r *mockRepo) GetWebhookSubscriptions(context.Context) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.subscriptions), nil
+}
+
+func (r *mockRepo) SaveWebhookSubscription(_ context.Context, subscription *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s domain.WebhookSubscription) bool {
+		return s.Identifier == subscription.Identifier
+	})
+	r.subscriptions = append(r.subscriptions, *subscription)
+	return nil
+}
+
+func (r *mockRepo) DeleteWebhookSubscription(_ context.Context, id domain.WebhookSubscriptionIdentifier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(s domain.WebhookSubscription) bool {
+		return s.Identifier == id
+	})
+	return nil
+}
+
+func (r *mockRepo) SaveWebhookDelivery(_ context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.Identifier] = *delivery
+	return nil
+}
+
+func (r *mockRepo) GetWebhookDelivery(_ context.Context, id domain.WebhookDeliveryIdentifier) (
+	*domain.WebhookDelivery,
+	error,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (r *mockRepo) GetDueWebhookDeliveries(_ context.Context, now time.Time, limit int) (
+	[]domain.WebhookDelivery,
+	error,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []domain.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == domain.WebhookDeliveryStatusPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Identifier < due[j].Identifier })
+	return due[:min(limit, len(due))], nil
+}
+
+func (r *mockRepo) QueryWebhookDeliveries(
+	context.Context,
+	domain.WebhookDeliveryFilter,
+	domain.ListOptions,
+) ([]domain.WebhookDelivery, int, error) {
+	return nil, 0, nil
+}
+
+func (r *mockRepo) DeleteWebhookDeliveriesBefore(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func newTestManager(t *testing.T, url string) (*Manager, *mockRepo) {
+	repo := &mockRepo{deliveries: map[domain.WebhookDeliveryIdentifier]domain.WebhookDelivery{}}
+	m, err := NewManager(&config.Config{Webhook: config.WebhookConfig{
+		Url:               url,
+		Authentication:    "Bearer test",
+		Timeout:           time.Second,
+		Secret:            "secret",
+		MaxAttempts:       2,
+		RetryInterval:     time.Minute,
+		DeliveryRetention: time.Hour,
+	}}, mockBus{}, repo)
+	require.NoError(t, err)
+	return m, repo
+}
+
+func TestManager_DeliverySigned(t *testing.T) {
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	m, repo := newTestManager(t, srv.URL)
+	m.handleGenericEvent(WebhookEventCreate, WebhookData{}) // unsupported payloads are not stored
+	assert.Empty(t, repo.deliveries)
+
+	m.handleGenericEvent(WebhookEventCreate, models.User{Identifier: "alice"})
+	require.Len(t, repo.deliveries, 1)
+	m.deliverDue(context.Background())
+
+	for _, delivery := range repo.deliveries {
+		assert.Equal(t, domain.WebhookDeliveryStatusDelivered, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, http.StatusOK, delivery.ResponseStatus)
+		assert.Equal(t, "ok", delivery.ResponseBody)
+		assert.Equal(t, string(delivery.Identifier), header.Get(HeaderDeliveryId))
+		assert.Equal(t, delivery.Payload, body)
+	}
+	assert.Equal(t, "Bearer test", header.Get("Authorization"))
+	assert.Equal(t, Sign("secret", header.Get(HeaderTimestamp), body), header.Get(HeaderSignature))
+	assert.Contains(t, string(body), `"identifier":"alice"`)
+}
+
+func TestManager_DeliveryRetries(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	m, repo := newTestManager(t, srv.URL)
+	m.handleGenericEvent(WebhookEventDelete, models.Peer{Identifier: "peer"})
+	var id domain.WebhookDeliveryIdentifier
+	for id = range repo.deliveries {
+		break // there is only one delivery
+	}
+
+	m.deliverDue(context.Background())
+	delivery, _ := repo.GetWebhookDelivery(context.Background(), id)
+	assert.Equal(t, domain.WebhookDeliveryStatusPending, delivery.Status, "failed attempts are retried")
+	assert.Equal(t, http.StatusBadGateway, delivery.ResponseStatus)
+	assert.NotEmpty(t, delivery.LastError)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *delivery.NextAttemptAt, 5*time.Second)
+
+	m.deliverDue(context.Background())
+	assert.Equal(t, 1, requests, "retries wait for the next attempt time")
+
+	now := time.Now()
+	delivery.NextAttemptAt = &now
+	_ = repo.SaveWebhookDelivery(context.Background(), delivery)
+	m.deliverDue(context.Background())
+	delivery, _ = repo.GetWebhookDelivery(context.Background(), id)
+	assert.Equal(t, domain.WebhookDeliveryStatusFailed, delivery.Status, "max attempts reached")
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	_, err := m.Redeliver(context.Background(), id)
+	assert.ErrorIs(t, err, domain.ErrNoPermission)
+
+	adminCtx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+	delivery, err = m.Redeliver(adminCtx, id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, 0, delivery.Attempts)
+
+	m.deliverDue(context.Background())
+	assert.Equal(t, 3, requests)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(30*time.Second, 1))
+	assert.Equal(t, 2*time.Minute, retryDelay(30*time.Second, 3))
+	assert.Equal(t, maxRetryInterval, retryDelay(30*time.Second, 20))
+}
+
+func TestManager_Subscriptions(t *testing.T) {
+	m, repo := newTestManager(t, "http://localhost/all")
+	m.webhook.Subscriptions = []config.WebhookSubscription{
+		{Name: "hr", Url: "http://localhost/hr", Entities: []string{"user"}},
+	}
+	adminCtx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+
+	noc, err := m.CreateSubscription(adminCtx, &domain.WebhookSubscription{
+		Name:       "noc",
+		Url:        "http://localhost/noc",
+		Events:     []string{WebhookEventConnect, WebhookEventDisconnect},
+		Interfaces: []domain.InterfaceIdentifier{"wg0"},
+	})
+	require.NoError(t, err)
+
+	subscriptions, err := m.GetSubscriptions(adminCtx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 3)
+	assert.True(t, subscriptions[1].IsReadOnly())
+	assert.False(t, subscriptions[2].IsReadOnly())
+
+	_, err = m.UpdateSubscription(adminCtx, "hr", &domain.WebhookSubscription{Name: "hr", Url: "http://localhost"})
+	assert.ErrorIs(t, err, domain.ErrInvalidData, "subscriptions of the configuration file are read-only")
+
+	received := func() map[domain.WebhookSubscriptionIdentifier]int {
+		counts := make(map[domain.WebhookSubscriptionIdentifier]int)
+		for id, delivery := range repo.deliveries {
+			counts[delivery.Subscription]++
+			delete(repo.deliveries, id)
+		}
+		return counts
+	}
+
+	m.handleGenericEvent(WebhookEventCreate, models.User{Identifier: "alice"})
+	assert.Equal(t, map[domain.WebhookSubscriptionIdentifier]int{"default": 1, "hr": 1}, received())
+
+	m.handleGenericEvent(WebhookEventConnect, models.PeerMetrics{Peer: models.Peer{InterfaceIdentifier: "wg0"}})
+	assert.Equal(t, map[domain.WebhookSubscriptionIdentifier]int{"default": 1, noc.Identifier: 1}, received())
+
+	m.handleGenericEvent(WebhookEventConnect, models.PeerMetrics{Peer: models.Peer{InterfaceIdentifier: "wg1"}})
+	assert.Equal(t, map[domain.WebhookSubscriptionIdentifier]int{"default": 1}, received())
+
+	require.NoError(t, m.DeleteSubscription(adminCtx, noc.Identifier))
+	subscriptions, _ = m.GetSubscriptions(adminCtx)
+	assert.Len(t, subscriptions, 2)
+}
@@ -0,0 +1,274 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation is a single operation of a JSON patch (RFC 6902).
+type PatchOperation struct {
+	// Op is the operation: add, remove, replace, move, copy or test.
+	Op string `json:"op" example:"replace"`
+	// Path is the JSON pointer (RFC 6901) of the target location, for example /DisplayName.
+	Path string `json:"path" example:"/DisplayName"`
+	// From is the JSON pointer of the source location of move and copy operations.
+	From string `json:"from,omitempty"`
+	// Value is the value of add, replace and test operations.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// applyPatch applies the JSON patch operations to the JSON document. The operations are applied in order, if one of
+// them fails, an error is returned.
+func applyPatch(doc []byte, patch []PatchOperation) ([]byte, error) {
+	var root any
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	for i, op := range patch {
+		var err error
+		root, err = op.apply(root)
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(root)
+}
+
+func (op PatchOperation) apply(root any) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return addValue(root, path, value)
+	case "remove":
+		return removeValue(root, path)
+	case "replace":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if root, err = removeValue(root, path); err != nil {
+			return nil, err
+		}
+		return addValue(root, path, value)
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := getValue(root, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, errors.New("cannot move a value into one of its children")
+			}
+			if root, err = removeValue(root, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+		return addValue(root, path, value)
+	case "test":
+		expected, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		actual, err := getValue(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return nil, errors.New("test failed, the value differs")
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+func (op PatchOperation) value() (any, error) {
+	if op.Value == nil {
+		return nil, errors.New("missing value")
+	}
+
+	var value any
+	if err := json.Unmarshal(op.Value, &value); err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+
+	return value, nil
+}
+
+// parsePointer splits a JSON pointer into its unescaped reference tokens. The empty pointer refers to the whole
+// document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	return len(prefix) <= len(path) && slices.Equal(prefix, path[:len(prefix)])
+}
+
+func getValue(node any, path []string) (any, error) {
+	for _, token := range path {
+		switch container := node.(type) {
+		case map[string]any:
+			value, ok := container[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			node = value
+		case []any:
+			idx, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = container[idx]
+		default:
+			return nil, fmt.Errorf("cannot resolve %q in a scalar value", token)
+		}
+	}
+
+	return node, nil
+}
+
+// modifyParent calls fn with the container of the last token of the path and replaces the container with the result.
+// It returns the modified document.
+func modifyParent(node any, path []string, fn func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	child, err := getValue(node, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = modifyParent(child, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	// getValue succeeded, so the node is either an object or an array with a valid index
+	if container, ok := node.([]any); ok {
+		idx, _ := arrayIndex(path[0], len(container)-1)
+		container[idx] = child
+		return container, nil
+	}
+	node.(map[string]any)[path[0]] = child
+
+	return node, nil
+}
+
+func addValue(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return modifyParent(root, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = value
+			return c, nil
+		case []any:
+			if token == "-" {
+				return append(c, value), nil
+			}
+			idx, err := arrayIndex(token, len(c))
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(c, idx, value), nil
+		default:
+			return nil, fmt.Errorf("cannot add %q to a scalar value", token)
+		}
+	})
+}
+
+func removeValue(root any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+
+	return modifyParent(root, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[token]; !ok {
+				return nil, fmt.Errorf("member %q does not exist", token)
+			}
+			delete(c, token)
+			return c, nil
+		case []any:
+			idx, err := arrayIndex(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(c, idx, idx+1), nil
+		default:
+			return nil, fmt.Errorf("cannot remove %q from a scalar value", token)
+		}
+	})
+}
+
+// arrayIndex parses an array index token, it must be between 0 and maxIdx.
+func arrayIndex(token string, maxIdx int) (int, error) {
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if idx > maxIdx {
+		return 0, fmt.Errorf("array index %d out of bounds", idx)
+	}
+
+	return idx, nil
+}
+
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for key, child := range v {
+			c[key] = deepCopy(child)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, child := range v {
+			c[i] = deepCopy(child)
+		}
+		return c
+	default:
+		return value
+	}
+}
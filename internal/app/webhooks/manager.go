@@ -3,9 +3,10 @@ package webhooks
 import (
 	"context"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/biezax/wg-portal/internal/app"
 	"github.com/biezax/wg-portal/internal/app/webhooks/models"
@@ -22,42 +23,115 @@ type EventBus interface {
 	Subscribe(topic string, fn interface{}) error
 }
 
+type Repository interface {
+	// GetWebhookSubscriptions returns the webhook subscriptions that have been created through the API.
+	GetWebhookSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// SaveWebhookSubscription creates or updates the given webhook subscription.
+	SaveWebhookSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	// DeleteWebhookSubscription deletes the webhook subscription with the given identifier.
+	DeleteWebhookSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) error
+
+	// SaveWebhookDelivery creates or updates the given webhook delivery.
+	SaveWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	// GetWebhookDelivery returns the webhook delivery with the given identifier.
+	GetWebhookDelivery(ctx context.Context, id domain.WebhookDeliveryIdentifier) (*domain.WebhookDelivery, error)
+	// GetDueWebhookDeliveries returns at most limit pending webhook deliveries that are due at the given time.
+	GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// QueryWebhookDeliveries returns a page of the matching webhook deliveries and their total number.
+	QueryWebhookDeliveries(
+		ctx context.Context,
+		filter domain.WebhookDeliveryFilter,
+		opts domain.ListOptions,
+	) ([]domain.WebhookDelivery, int, error)
+	// DeleteWebhookDeliveriesBefore deletes the completed webhook deliveries created before the given time.
+	DeleteWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int, error)
+}
+
 // endregion dependencies
 
 type Manager struct {
-	cfg *config.Config
-	bus EventBus
-
-	client *http.Client
+	bus       EventBus
+	repo      Repository
+	templates *TemplateHandler
+
+	// mu protects the webhook configuration, the client and the subscriptions. The configuration and the client are
+	// replaced on configuration reloads, the stored subscriptions when they are changed through the API.
+	mu                  sync.RWMutex
+	webhook             config.WebhookConfig
+	client              *http.Client
+	storedSubscriptions []domain.WebhookSubscription
+
+	// wakeup triggers the delivery worker, for example after a new delivery has been stored.
+	wakeup chan struct{}
 }
 
 // NewManager creates a new webhook manager instance.
-func NewManager(cfg *config.Config, bus EventBus) (*Manager, error) {
+func NewManager(cfg *config.Config, bus EventBus, repo Repository) (*Manager, error) {
+	templates, err := newTemplateHandler(cfg.Web.SiteTitle, cfg.Web.ExternalUrl)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateConfigTemplates(cfg.Webhook); err != nil {
+		return nil, err
+	}
+
 	m := &Manager{
-		cfg: cfg,
-		bus: bus,
+		bus:       bus,
+		repo:      repo,
+		templates: templates,
+		webhook:   cfg.Webhook,
 		client: &http.Client{
 			Timeout: cfg.Webhook.Timeout,
 		},
+		wakeup: make(chan struct{}, 1),
 	}
 
+	if err := m.loadSubscriptions(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+	if len(m.getSubscriptions()) == 0 {
+		slog.Info("[WEBHOOK] no webhook configured")
+	}
 	m.connectToMessageBus()
 
 	return m, nil
 }
 
-// StartBackgroundJobs starts background jobs for the webhook manager.
-// This method is non-blocking and returns immediately.
-func (m Manager) StartBackgroundJobs(_ context.Context) {
-	// this is a no-op for now
-}
+// ReloadConfig applies the webhook configuration of the given configuration.
+// Webhooks that are already being sent use the previous configuration.
+func (m *Manager) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	if err := validateConfigTemplates(cfg.Webhook); err != nil {
+		return err
+	}
 
-func (m Manager) connectToMessageBus() {
-	if m.cfg.Webhook.Url == "" {
-		slog.Info("[WEBHOOK] no webhook configured, skipping event-bus subscription")
-		return
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.webhook = cfg.Webhook
+	m.client = &http.Client{
+		Timeout: cfg.Webhook.Timeout,
 	}
 
+	return nil
+}
+
+// getWebhook returns the current webhook configuration and the client to use.
+func (m *Manager) getWebhook() (config.WebhookConfig, *http.Client) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.webhook, m.client
+}
+
+// StartBackgroundJobs starts the delivery worker, it sends the pending deliveries of the outbox.
+// This method is non-blocking and returns immediately.
+func (m *Manager) StartBackgroundJobs(ctx context.Context) {
+	go m.runDeliveryWorker(ctx)
+}
+
+// connectToMessageBus subscribes to all events, even if no webhook is configured, so that a webhook can be added by
+// reloading the configuration.
+func (m *Manager) connectToMessageBus() {
 	_ = m.bus.Subscribe(app.TopicUserCreated, m.handleUserCreateEvent)
 	_ = m.bus.Subscribe(app.TopicUserUpdated, m.handleUserUpdateEvent)
 	_ = m.bus.Subscribe(app.TopicUserDeleted, m.handleUserDeleteEvent)
@@ -66,78 +140,55 @@ func (m Manager) connectToMessageBus() {
 	_ = m.bus.Subscribe(app.TopicPeerUpdated, m.handlePeerUpdateEvent)
 	_ = m.bus.Subscribe(app.TopicPeerDeleted, m.handlePeerDeleteEvent)
 	_ = m.bus.Subscribe(app.TopicPeerStateChanged, m.handlePeerStateChangeEvent)
+	_ = m.bus.Subscribe(app.TopicPeerExpiring, m.handlePeerExpiringEvent)
 
 	_ = m.bus.Subscribe(app.TopicInterfaceCreated, m.handleInterfaceCreateEvent)
 	_ = m.bus.Subscribe(app.TopicInterfaceUpdated, m.handleInterfaceUpdateEvent)
 	_ = m.bus.Subscribe(app.TopicInterfaceDeleted, m.handleInterfaceDeleteEvent)
 }
 
-func (m Manager) sendWebhook(ctx context.Context, data io.Reader) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.cfg.Webhook.Url, data)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if m.cfg.Webhook.Authentication != "" {
-		req.Header.Set("Authorization", m.cfg.Webhook.Authentication)
-	}
-
-	resp, err := m.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			slog.Error("[WEBHOOK] failed to close response body", "error", err)
-		}
-	}(resp.Body)
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusAccepted {
-		return fmt.Errorf("webhook request failed with status: %s", resp.Status)
-	}
-
-	return nil
-}
-
-func (m Manager) handleUserCreateEvent(user domain.User) {
+func (m *Manager) handleUserCreateEvent(user domain.User) {
 	m.handleGenericEvent(WebhookEventCreate, models.NewUser(user))
 }
 
-func (m Manager) handleUserUpdateEvent(user domain.User) {
+func (m *Manager) handleUserUpdateEvent(user domain.User) {
 	m.handleGenericEvent(WebhookEventUpdate, models.NewUser(user))
 }
 
-func (m Manager) handleUserDeleteEvent(user domain.User) {
+func (m *Manager) handleUserDeleteEvent(user domain.User) {
 	m.handleGenericEvent(WebhookEventDelete, models.NewUser(user))
 }
 
-func (m Manager) handlePeerCreateEvent(peer domain.Peer) {
+func (m *Manager) handlePeerCreateEvent(peer domain.Peer) {
 	m.handleGenericEvent(WebhookEventCreate, models.NewPeer(peer))
 }
 
-func (m Manager) handlePeerUpdateEvent(peer domain.Peer) {
+func (m *Manager) handlePeerUpdateEvent(peer domain.Peer) {
 	m.handleGenericEvent(WebhookEventUpdate, models.NewPeer(peer))
 }
 
-func (m Manager) handlePeerDeleteEvent(peer domain.Peer) {
+func (m *Manager) handlePeerDeleteEvent(peer domain.Peer) {
 	m.handleGenericEvent(WebhookEventDelete, models.NewPeer(peer))
 }
 
-func (m Manager) handleInterfaceCreateEvent(iface domain.Interface) {
+// handlePeerExpiringEvent sends the expiry reminder of a peer, the self-service extension link is not sent.
+func (m *Manager) handlePeerExpiringEvent(peer domain.Peer, _ string) {
+	m.handleGenericEvent(WebhookEventExpiring, models.NewPeer(peer))
+}
+
+func (m *Manager) handleInterfaceCreateEvent(iface domain.Interface) {
 	m.handleGenericEvent(WebhookEventCreate, models.NewInterface(iface))
 }
 
-func (m Manager) handleInterfaceUpdateEvent(iface domain.Interface) {
+func (m *Manager) handleInterfaceUpdateEvent(iface domain.Interface) {
 	m.handleGenericEvent(WebhookEventUpdate, models.NewInterface(iface))
 }
 
-func (m Manager) handleInterfaceDeleteEvent(iface domain.Interface) {
+func (m *Manager) handleInterfaceDeleteEvent(iface domain.Interface) {
 	m.handleGenericEvent(WebhookEventDelete, models.NewInterface(iface))
 }
 
-func (m Manager) handlePeerStateChangeEvent(peerStatus domain.PeerStatus, peer domain.Peer) {
+func (m *Manager) handlePeerStateChangeEvent(peerStatus domain.PeerStatus, peer domain.Peer) {
 	if peerStatus.IsConnected {
 		m.handleGenericEvent(WebhookEventConnect, models.NewPeerMetrics(peerStatus, peer))
 	} else {
@@ -145,7 +196,9 @@ func (m Manager) handlePeerStateChangeEvent(peerStatus domain.PeerStatus, peer d
 	}
 }
 
-func (m Manager) handleGenericEvent(action WebhookEvent, payload any) {
+// handleGenericEvent stores a delivery of the event for each matching subscription in the outbox, the delivery
+// worker sends them.
+func (m *Manager) handleGenericEvent(action WebhookEvent, payload any) {
 	eventData, err := m.createWebhookData(action, payload)
 	if err != nil {
 		slog.Error("[WEBHOOK] failed to create webhook data", "error", err, "action", action,
@@ -153,25 +206,37 @@ func (m Manager) handleGenericEvent(action WebhookEvent, payload any) {
 		return
 	}
 
-	eventJson, err := eventData.Serialize()
-	if err != nil {
-		slog.Error("[WEBHOOK] failed to serialize event data", "error", err, "action", action,
-			"payload", fmt.Sprintf("%T", payload), "identifier", eventData.Identifier)
-		return
+	interfaceId, userId := payloadOwners(payload)
+	var subscriptions []domain.WebhookSubscription
+	for _, subscription := range m.getSubscriptions() {
+		if subscription.Matches(action, eventData.Entity, interfaceId, userId) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	if len(subscriptions) == 0 {
+		return // no matching webhook configured
 	}
 
-	err = m.sendWebhook(context.Background(), eventJson)
-	if err != nil {
-		slog.Error("[WEBHOOK] failed to execute webhook", "error", err, "action", action,
-			"payload", fmt.Sprintf("%T", payload), "identifier", eventData.Identifier)
-		return
+	for _, subscription := range subscriptions {
+		body, contentType, err := m.templates.Render(&subscription, eventData)
+		if err != nil {
+			slog.Error("[WEBHOOK] failed to render webhook payload", "error", err, "action", action,
+				"subscription", subscription.Identifier, "identifier", eventData.Identifier)
+			continue
+		}
+
+		delivery := domain.NewWebhookDelivery(subscription.Identifier, action, eventData.Entity,
+			eventData.Identifier, subscription.Url, body, contentType)
+		if err := m.repo.SaveWebhookDelivery(context.Background(), delivery); err != nil {
+			slog.Error("[WEBHOOK] failed to store webhook delivery", "error", err, "action", action,
+				"subscription", subscription.Identifier, "identifier", eventData.Identifier)
+		}
 	}
 
-	slog.Info("[WEBHOOK] executed webhook", "action", action, "payload", fmt.Sprintf("%T", payload),
-		"identifier", eventData.Identifier)
+	m.wakeDeliveryWorker()
 }
 
-func (m Manager) createWebhookData(action WebhookEvent, payload any) (*WebhookData, error) {
+func (m *Manager) createWebhookData(action WebhookEvent, payload any) (*WebhookData, error) {
 	d := &WebhookData{
 		Event:   action,
 		Payload: payload,
@@ -196,3 +261,19 @@ func (m Manager) createWebhookData(action WebhookEvent, payload any) (*WebhookDa
 
 	return d, nil
 }
+
+// payloadOwners returns the interface and the user the payload belongs to, they are used by the subscription filters.
+func payloadOwners(payload any) (domain.InterfaceIdentifier, domain.UserIdentifier) {
+	switch v := payload.(type) {
+	case models.User:
+		return "", domain.UserIdentifier(v.Identifier)
+	case models.Peer:
+		return domain.InterfaceIdentifier(v.InterfaceIdentifier), domain.UserIdentifier(v.UserIdentifier)
+	case models.Interface:
+		return domain.InterfaceIdentifier(v.Identifier), ""
+	case models.PeerMetrics:
+		return domain.InterfaceIdentifier(v.Peer.InterfaceIdentifier), domain.UserIdentifier(v.Peer.UserIdentifier)
+	default:
+		return "", ""
+	}
+}
@@ -1,11 +1,5 @@
 package webhooks
 
-import (
-	"bytes"
-	"encoding/json"
-	"io"
-)
-
 // WebhookData is the data structure for the webhook payload.
 type WebhookData struct {
 	// Event is the event type (e.g. create, update, delete)
@@ -21,16 +15,6 @@ type WebhookData struct {
 	Payload any `json:"payload"`
 }
 
-// Serialize serializes the WebhookData to JSON and returns it as an io.Reader.
-func (d *WebhookData) Serialize() (io.Reader, error) {
-	data, err := json.Marshal(d)
-	if err != nil {
-		return nil, err
-	}
-
-	return bytes.NewReader(data), nil
-}
-
 type WebhookEntity = string
 
 const (
@@ -48,4 +32,5 @@ const (
 	WebhookEventDelete     WebhookEvent = "delete"
 	WebhookEventConnect    WebhookEvent = "connect"
 	WebhookEventDisconnect WebhookEvent = "disconnect"
+	WebhookEventExpiring   WebhookEvent = "expiring"
 )
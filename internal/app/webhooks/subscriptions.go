@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// GetSubscriptions returns all webhook subscriptions, the subscriptions of the configuration file first.
+func (m *Manager) GetSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.getSubscriptions(), nil
+}
+
+// GetSubscription returns the webhook subscription with the given identifier.
+func (m *Manager) GetSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) (
+	*domain.WebhookSubscription,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	subscription, ok := m.getSubscription(id)
+	if !ok {
+		return nil, errors.Join(fmt.Errorf("webhook subscription %s not found", id), domain.ErrNotFound)
+	}
+
+	return subscription, nil
+}
+
+// CreateSubscription stores a new webhook subscription, it receives the matching events immediately.
+func (m *Manager) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) (
+	*domain.WebhookSubscription,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription.Identifier = domain.NewWebhookSubscriptionIdentifier()
+	subscription.Source = domain.WebhookSubscriptionSourceDatabase
+	subscription.CreatedAt = now
+	subscription.CreatedBy = string(domain.GetUserInfo(ctx).Id)
+	subscription.UpdatedAt = now
+	subscription.UpdatedBy = subscription.CreatedBy
+
+	if err := m.repo.SaveWebhookSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	if err := m.loadSubscriptions(ctx); err != nil {
+		return nil, fmt.Errorf("failed to reload webhook subscriptions: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// UpdateSubscription replaces the settings of a webhook subscription. Subscriptions of the configuration file cannot
+// be changed. Pending deliveries are sent with the new authentication and secret, but to the previous URL.
+func (m *Manager) UpdateSubscription(
+	ctx context.Context,
+	id domain.WebhookSubscriptionIdentifier,
+	subscription *domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	existing, err := m.getWritableSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	subscription.Identifier = existing.Identifier
+	subscription.Source = existing.Source
+	subscription.CreatedAt = existing.CreatedAt
+	subscription.CreatedBy = existing.CreatedBy
+	subscription.UpdatedAt = time.Now()
+	subscription.UpdatedBy = string(domain.GetUserInfo(ctx).Id)
+
+	if err := m.repo.SaveWebhookSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription %s: %w", id, err)
+	}
+
+	if err := m.loadSubscriptions(ctx); err != nil {
+		return nil, fmt.Errorf("failed to reload webhook subscriptions: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a webhook subscription. Subscriptions of the configuration file cannot be deleted.
+// Pending deliveries of the subscription are marked as failed by the delivery worker.
+func (m *Manager) DeleteSubscription(ctx context.Context, id domain.WebhookSubscriptionIdentifier) error {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return err
+	}
+
+	if _, err := m.getWritableSubscription(id); err != nil {
+		return err
+	}
+
+	if err := m.repo.DeleteWebhookSubscription(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription %s: %w", id, err)
+	}
+
+	if err := m.loadSubscriptions(ctx); err != nil {
+		return fmt.Errorf("failed to reload webhook subscriptions: %w", err)
+	}
+
+	return nil
+}
+
+// validateSubscription checks the settings and the custom template of the subscription.
+func validateSubscription(subscription *domain.WebhookSubscription) error {
+	if err := subscription.Validate(); err != nil {
+		return err
+	}
+	if subscription.Template != "" {
+		if _, err := ParseTemplate(subscription.Template); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateConfigTemplates checks the custom templates of the subscriptions of the configuration file.
+func validateConfigTemplates(cfg config.WebhookConfig) error {
+	for _, subscription := range cfg.Subscriptions {
+		if subscription.Template == "" {
+			continue
+		}
+		if _, err := ParseTemplate(subscription.Template); err != nil {
+			return fmt.Errorf("webhook subscription %s: %w", subscription.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// loadSubscriptions reloads the subscriptions of the database.
+func (m *Manager) loadSubscriptions(ctx context.Context) error {
+	subscriptions, err := m.repo.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.storedSubscriptions = subscriptions
+
+	return nil
+}
+
+// getSubscriptions returns the subscriptions of the configuration file and the database.
+func (m *Manager) getSubscriptions() []domain.WebhookSubscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subscriptions := configSubscriptions(m.webhook)
+	return append(subscriptions, m.storedSubscriptions...)
+}
+
+func (m *Manager) getSubscription(id domain.WebhookSubscriptionIdentifier) (*domain.WebhookSubscription, bool) {
+	subscriptions := m.getSubscriptions()
+	idx := slices.IndexFunc(subscriptions, func(s domain.WebhookSubscription) bool {
+		return s.Identifier == id
+	})
+	if idx < 0 {
+		return nil, false
+	}
+
+	return &subscriptions[idx], true
+}
+
+func (m *Manager) getWritableSubscription(id domain.WebhookSubscriptionIdentifier) (
+	*domain.WebhookSubscription,
+	error,
+) {
+	subscription, ok := m.getSubscription(id)
+	switch {
+	case !ok:
+		return nil, errors.Join(fmt.Errorf("webhook subscription %s not found", id), domain.ErrNotFound)
+	case subscription.IsReadOnly():
+		return nil, errors.Join(fmt.Errorf("webhook subscription %s is defined in the configuration file", id),
+			domain.ErrInvalidData)
+	}
+
+	return subscription, nil
+}
+
+// configSubscriptions returns the subscriptions of the configuration file. The webhook url option is the default
+// subscription, it receives all events.
+func configSubscriptions(cfg config.WebhookConfig) []domain.WebhookSubscription {
+	subscriptions := make([]domain.WebhookSubscription, 0, len(cfg.Subscriptions)+1)
+	if cfg.Url != "" {
+		subscriptions = append(subscriptions, domain.WebhookSubscription{
+			Identifier:     domain.DefaultWebhookSubscription,
+			Source:         domain.WebhookSubscriptionSourceConfig,
+			Name:           string(domain.DefaultWebhookSubscription),
+			Url:            cfg.Url,
+			Authentication: cfg.Authentication,
+			Secret:         cfg.Secret,
+		})
+	}
+
+	for _, sub := range cfg.Subscriptions {
+		subscription := domain.WebhookSubscription{
+			Identifier:     domain.WebhookSubscriptionIdentifier(sub.Name),
+			Source:         domain.WebhookSubscriptionSourceConfig,
+			Name:           sub.Name,
+			Url:            sub.Url,
+			Authentication: sub.Authentication,
+			Secret:         sub.Secret,
+			Preset:         sub.Preset,
+			Template:       sub.Template,
+			ContentType:    sub.ContentType,
+			Entities:       sub.Entities,
+			Events:         sub.Events,
+		}
+		for _, iface := range sub.Interfaces {
+			subscription.Interfaces = append(subscription.Interfaces, domain.InterfaceIdentifier(iface))
+		}
+		for _, user := range sub.Users {
+			subscription.Users = append(subscription.Users, domain.UserIdentifier(user))
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions
+}
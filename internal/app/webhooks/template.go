@@ -0,0 +1,164 @@
+package webhooks
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/app/webhooks/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+//go:embed tpl_files/*
+var TemplateFiles embed.FS
+
+// TemplateData is the data of payload templates. Payload is one of models.User, models.Peer, models.Interface or
+// models.PeerMetrics, depending on the entity.
+type TemplateData struct {
+	WebhookData
+
+	PortalName string // the site title
+	PortalUrl  string // the external URL of the portal, it may be empty
+}
+
+// templateFuncs are the functions that are available in payload templates.
+var templateFuncs = template.FuncMap{
+	"json":    toJson,
+	"summary": summary,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"join":    strings.Join,
+}
+
+// TemplateHandler renders the request bodies of webhook subscriptions.
+type TemplateHandler struct {
+	portalName string
+	portalUrl  string
+	presets    *template.Template
+}
+
+func newTemplateHandler(portalName, portalUrl string) (*TemplateHandler, error) {
+	presets, err := template.New("Presets").Funcs(templateFuncs).ParseFS(TemplateFiles, "tpl_files/*.gotpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse preset template files: %w", err)
+	}
+
+	return &TemplateHandler{
+		portalName: portalName,
+		portalUrl:  portalUrl,
+		presets:    presets,
+	}, nil
+}
+
+// ParseTemplate checks the syntax of a custom payload template.
+func ParseTemplate(text string) (*template.Template, error) {
+	tpl, err := template.New("Custom").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("invalid webhook template: %w", err), domain.ErrInvalidData)
+	}
+
+	return tpl, nil
+}
+
+// Render returns the request body and its content type for the given subscription. Subscriptions without preset or
+// template receive the JSON encoded event data.
+func (h TemplateHandler) Render(subscription *domain.WebhookSubscription, data *WebhookData) ([]byte, string, error) {
+	var buf bytes.Buffer
+	tplData := TemplateData{WebhookData: *data, PortalName: h.portalName, PortalUrl: h.portalUrl}
+
+	switch {
+	case subscription.Preset != "":
+		if err := h.presets.ExecuteTemplate(&buf, subscription.Preset+".gotpl", tplData); err != nil {
+			return nil, "", fmt.Errorf("failed to execute preset %s: %w", subscription.Preset, err)
+		}
+		return buf.Bytes(), "application/json", nil
+	case subscription.Template != "":
+		tpl, err := ParseTemplate(subscription.Template)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := tpl.Execute(&buf, tplData); err != nil {
+			return nil, "", fmt.Errorf("failed to execute template: %w", err)
+		}
+		contentType := subscription.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		return buf.Bytes(), contentType, nil
+	default:
+		body, err := json.Marshal(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to serialize event data: %w", err)
+		}
+		return body, "application/json", nil
+	}
+}
+
+// toJson encodes the value as JSON, strings are quoted and escaped. It allows to embed values in JSON templates.
+func toJson(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// summary returns a short, human-readable description of the event, for example "Peer laptop on wg0 connected".
+func summary(data TemplateData) string {
+	action := map[WebhookEvent]string{
+		WebhookEventCreate:     "was created",
+		WebhookEventUpdate:     "was updated",
+		WebhookEventDelete:     "was deleted",
+		WebhookEventConnect:    "connected",
+		WebhookEventDisconnect: "disconnected",
+		WebhookEventExpiring:   "expires soon",
+	}[data.Event]
+	if action == "" {
+		action = data.Event
+	}
+
+	switch v := data.Payload.(type) {
+	case models.User:
+		name := strings.TrimSpace(v.Firstname + " " + v.Lastname)
+		if name == "" {
+			name = v.Email
+		}
+		if name == "" {
+			return fmt.Sprintf("User %s %s", v.Identifier, action)
+		}
+		return fmt.Sprintf("User %s (%s) %s", name, v.Identifier, action)
+	case models.Peer:
+		if data.Event == WebhookEventExpiring && v.ExpiresAt != nil {
+			return fmt.Sprintf("Peer %s on %s expires on %s", peerName(v), v.InterfaceIdentifier,
+				v.ExpiresAt.Format(time.DateOnly))
+		}
+		return fmt.Sprintf("Peer %s on %s %s", peerName(v), v.InterfaceIdentifier, action)
+	case models.PeerMetrics:
+		if v.Status.Endpoint != "" && data.Event == WebhookEventConnect {
+			return fmt.Sprintf("Peer %s on %s %s from %s", peerName(v.Peer), v.Peer.InterfaceIdentifier, action,
+				v.Status.Endpoint)
+		}
+		return fmt.Sprintf("Peer %s on %s %s", peerName(v.Peer), v.Peer.InterfaceIdentifier, action)
+	case models.Interface:
+		if v.DisplayName != "" && v.DisplayName != v.Identifier {
+			return fmt.Sprintf("Interface %s (%s) %s", v.Identifier, v.DisplayName, action)
+		}
+		return fmt.Sprintf("Interface %s %s", v.Identifier, action)
+	default:
+		return fmt.Sprintf("%s %s %s", data.Entity, data.Identifier, action)
+	}
+}
+
+func peerName(peer models.Peer) string {
+	if peer.DisplayName != "" {
+		return peer.DisplayName
+	}
+
+	return peer.Identifier
+}
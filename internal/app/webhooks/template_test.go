@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/app/webhooks/models"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+func TestTemplateHandler_Presets(t *testing.T) {
+	handler, err := newTemplateHandler(`WG "Portal"`, "https://wg.example.com")
+	require.NoError(t, err)
+
+	data := &WebhookData{
+		Event:      WebhookEventConnect,
+		Entity:     WebhookEntityPeerMetric,
+		Identifier: "peer-key",
+		Payload: models.PeerMetrics{
+			Status: models.PeerStatus{Endpoint: "10.0.0.1:51820"},
+			Peer:   models.Peer{Identifier: "peer-key", DisplayName: `laptop "work"`, InterfaceIdentifier: "wg0"},
+		},
+	}
+
+	for _, preset := range domain.WebhookPresets {
+		body, contentType, err := handler.Render(&domain.WebhookSubscription{Preset: preset}, data)
+		require.NoError(t, err, preset)
+		assert.Equal(t, "application/json", contentType)
+		assert.True(t, json.Valid(body), "%s renders invalid JSON: %s", preset, body)
+		assert.Contains(t, string(body), `Peer laptop \"work\" on wg0 connected from 10.0.0.1:51820`, preset)
+	}
+}
+
+func TestTemplateHandler_CustomTemplate(t *testing.T) {
+	handler, err := newTemplateHandler("WG Portal", "")
+	require.NoError(t, err)
+
+	subscription := &domain.WebhookSubscription{
+		Template:    `{{ upper .Event }} {{ .Payload.Email }}: {{ summary . }}`,
+		ContentType: "text/plain",
+	}
+	data := &WebhookData{Event: WebhookEventCreate, Entity: WebhookEntityUser, Identifier: "alice",
+		Payload: models.User{Identifier: "alice", Email: "alice@example.com"}}
+
+	body, contentType, err := handler.Render(subscription, data)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", contentType)
+	assert.Equal(t, "CREATE alice@example.com: User alice@example.com (alice) was created", string(body))
+
+	body, contentType, err = handler.Render(&domain.WebhookSubscription{}, data)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(body, &raw), "the event data is sent without template")
+	assert.Equal(t, "alice", raw["identifier"])
+	assert.Equal(t, "alice@example.com", raw["payload"].(map[string]any)["Email"])
+
+	_, err = ParseTemplate(`{{ .Event `)
+	assert.ErrorIs(t, err, domain.ErrInvalidData)
+}
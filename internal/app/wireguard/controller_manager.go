@@ -1,10 +1,12 @@
 package wireguard
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"maps"
 	"slices"
+	"sync"
 
 	"github.com/biezax/wg-portal/internal/adapters/wgcontroller"
 	"github.com/biezax/wg-portal/internal/config"
@@ -17,7 +19,10 @@ type backendInstance struct {
 }
 
 type ControllerManager struct {
-	cfg         *config.Config
+	cfg *config.Config
+
+	// mu protects the controllers, the map is replaced if the backends are reloaded.
+	mu          sync.RWMutex
 	controllers map[domain.InterfaceBackend]backendInstance
 }
 
@@ -40,13 +45,38 @@ func (c *ControllerManager) init() error {
 		return err
 	}
 
-	if err := c.registerMikrotikControllers(); err != nil {
+	if err := c.registerMikrotikControllers(c.controllers, c.cfg.Backend.Mikrotik); err != nil {
 		return err
 	}
 
-	if err := c.registerPfsenseControllers(); err != nil {
+	if err := c.registerPfsenseControllers(c.controllers, c.cfg.Backend.Pfsense); err != nil {
+		return err
+	}
+
+	c.logRegisteredControllers()
+
+	return nil
+}
+
+// ReloadConfig replaces the Mikrotik and pfSense controllers with the backends of the given configuration.
+// The local controller is kept. If a controller cannot be created, the current controllers stay active.
+func (c *ControllerManager) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	controllers := make(map[domain.InterfaceBackend]backendInstance)
+
+	c.mu.RLock()
+	controllers[config.LocalBackendName] = c.controllers[config.LocalBackendName]
+	c.mu.RUnlock()
+
+	if err := c.registerMikrotikControllers(controllers, cfg.Backend.Mikrotik); err != nil {
 		return err
 	}
+	if err := c.registerPfsenseControllers(controllers, cfg.Backend.Pfsense); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.controllers = controllers
+	c.mu.Unlock()
 
 	c.logRegisteredControllers()
 
@@ -70,8 +100,11 @@ func (c *ControllerManager) registerLocalController() error {
 	return nil
 }
 
-func (c *ControllerManager) registerMikrotikControllers() error {
-	for _, backendConfig := range c.cfg.Backend.Mikrotik {
+func (c *ControllerManager) registerMikrotikControllers(
+	controllers map[domain.InterfaceBackend]backendInstance,
+	backends []config.BackendMikrotik,
+) error {
+	for _, backendConfig := range backends {
 		if backendConfig.Id == config.LocalBackendName {
 			slog.Warn("skipping registration of Mikrotik controller with reserved ID", "id", config.LocalBackendName)
 			continue
@@ -82,7 +115,7 @@ func (c *ControllerManager) registerMikrotikControllers() error {
 			return fmt.Errorf("failed to create Mikrotik controller for backend %s: %w", backendConfig.Id, err)
 		}
 
-		c.controllers[domain.InterfaceBackend(backendConfig.Id)] = backendInstance{
+		controllers[domain.InterfaceBackend(backendConfig.Id)] = backendInstance{
 			Config:         backendConfig.BackendBase,
 			Implementation: controller,
 		}
@@ -90,8 +123,11 @@ func (c *ControllerManager) registerMikrotikControllers() error {
 	return nil
 }
 
-func (c *ControllerManager) registerPfsenseControllers() error {
-	for _, backendConfig := range c.cfg.Backend.Pfsense {
+func (c *ControllerManager) registerPfsenseControllers(
+	controllers map[domain.InterfaceBackend]backendInstance,
+	backends []config.BackendPfsense,
+) error {
+	for _, backendConfig := range backends {
 		if backendConfig.Id == config.LocalBackendName {
 			slog.Warn("skipping registration of pfSense controller with reserved ID", "id", config.LocalBackendName)
 			continue
@@ -102,7 +138,7 @@ func (c *ControllerManager) registerPfsenseControllers() error {
 			return fmt.Errorf("failed to create pfSense controller for backend %s: %w", backendConfig.Id, err)
 		}
 
-		c.controllers[domain.InterfaceBackend(backendConfig.Id)] = backendInstance{
+		controllers[domain.InterfaceBackend(backendConfig.Id)] = backendInstance{
 			Config:         backendConfig.BackendBase,
 			Implementation: controller,
 		}
@@ -111,6 +147,9 @@ func (c *ControllerManager) registerPfsenseControllers() error {
 }
 
 func (c *ControllerManager) logRegisteredControllers() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	for backend, controller := range c.controllers {
 		slog.Debug("backend controller registered",
 			"backend", backend, "type", fmt.Sprintf("%T", controller.Implementation))
@@ -135,6 +174,9 @@ func (c *ControllerManager) getController(
 		backend = config.LocalBackendName
 	}
 
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	controller, exists := c.controllers[backend]
 	if !exists {
 		controller, exists = c.controllers[config.LocalBackendName] // Fallback to local controller
@@ -149,6 +191,9 @@ func (c *ControllerManager) getController(
 }
 
 func (c *ControllerManager) GetAllControllers() []backendInstance {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	var backendInstances = make([]backendInstance, 0, len(c.controllers))
 	for instance := range maps.Values(c.controllers) {
 		backendInstances = append(backendInstances, instance)
@@ -157,6 +202,9 @@ func (c *ControllerManager) GetAllControllers() []backendInstance {
 }
 
 func (c *ControllerManager) GetControllerNames() []config.BackendBase {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	var names []config.BackendBase
 	for _, id := range slices.Sorted(maps.Keys(c.controllers)) {
 		names = append(names, c.controllers[id].Config)
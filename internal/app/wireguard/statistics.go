@@ -2,6 +2,7 @@ package wireguard
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
@@ -57,6 +58,16 @@ type StatisticsCollector struct {
 	ms StatisticsMetricsServer
 
 	peerChangeEvent chan domain.PeerIdentifier
+
+	// the intervals can be changed by reloading the configuration, intervalChanged is closed on changes
+	intervalMu      sync.Mutex
+	intervals       statisticsIntervals
+	intervalChanged chan struct{}
+}
+
+type statisticsIntervals struct {
+	DataCollection time.Duration
+	PingCheck      time.Duration
 }
 
 // NewStatisticsCollector creates a new statistics collector.
@@ -74,6 +85,12 @@ func NewStatisticsCollector(
 		db: db,
 		wg: wg,
 		ms: ms,
+
+		intervals: statisticsIntervals{
+			DataCollection: cfg.Statistics.DataCollectionInterval,
+			PingCheck:      cfg.Statistics.PingCheckInterval,
+		},
+		intervalChanged: make(chan struct{}),
 	}
 
 	c.connectToMessageBus()
@@ -93,6 +110,34 @@ func (c *StatisticsCollector) StartBackgroundJobs(ctx context.Context) {
 	c.startPeerDataFetcher(ctx)
 }
 
+// ReloadConfig applies the data collection and ping check intervals of the given configuration.
+// Enabling or disabling collectors requires a restart.
+func (c *StatisticsCollector) ReloadConfig(_ context.Context, cfg *config.Config) error {
+	if cfg.Statistics.DataCollectionInterval <= 0 || cfg.Statistics.PingCheckInterval <= 0 {
+		return fmt.Errorf("statistics intervals must be positive: %w", domain.ErrInvalidData)
+	}
+
+	c.intervalMu.Lock()
+	defer c.intervalMu.Unlock()
+
+	c.intervals = statisticsIntervals{
+		DataCollection: cfg.Statistics.DataCollectionInterval,
+		PingCheck:      cfg.Statistics.PingCheckInterval,
+	}
+	close(c.intervalChanged)
+	c.intervalChanged = make(chan struct{})
+
+	return nil
+}
+
+// getIntervals returns the current intervals and a channel that is closed once they change.
+func (c *StatisticsCollector) getIntervals() (statisticsIntervals, <-chan struct{}) {
+	c.intervalMu.Lock()
+	defer c.intervalMu.Unlock()
+
+	return c.intervals, c.intervalChanged
+}
+
 func (c *StatisticsCollector) startInterfaceDataFetcher(ctx context.Context) {
 	if !c.cfg.Statistics.CollectInterfaceData {
 		return
@@ -105,12 +150,16 @@ func (c *StatisticsCollector) startInterfaceDataFetcher(ctx context.Context) {
 
 func (c *StatisticsCollector) collectInterfaceData(ctx context.Context) {
 	// Start ticker
-	ticker := time.NewTicker(c.cfg.Statistics.DataCollectionInterval)
+	intervals, intervalChanged := c.getIntervals()
+	ticker := time.NewTicker(intervals.DataCollection)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return // program stopped
+		case <-intervalChanged:
+			intervals, intervalChanged = c.getIntervals()
+			ticker.Reset(intervals.DataCollection)
 		case <-ticker.C:
 			interfaces, err := c.db.GetAllInterfaces(ctx)
 			if err != nil {
@@ -157,12 +206,16 @@ func (c *StatisticsCollector) startPeerDataFetcher(ctx context.Context) {
 
 func (c *StatisticsCollector) collectPeerData(ctx context.Context) {
 	// Start ticker
-	ticker := time.NewTicker(c.cfg.Statistics.DataCollectionInterval)
+	intervals, intervalChanged := c.getIntervals()
+	ticker := time.NewTicker(intervals.DataCollection)
 	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return // program stopped
+		case <-intervalChanged:
+			intervals, intervalChanged = c.getIntervals()
+			ticker.Reset(intervals.DataCollection)
 		case <-ticker.C:
 			interfaces, err := c.db.GetAllInterfaces(ctx)
 			if err != nil {
@@ -294,7 +347,8 @@ func (c *StatisticsCollector) startPingWorkers(ctx context.Context) {
 
 func (c *StatisticsCollector) enqueuePingChecks(ctx context.Context) {
 	// Start ticker
-	ticker := time.NewTicker(c.cfg.Statistics.PingCheckInterval)
+	intervals, intervalChanged := c.getIntervals()
+	ticker := time.NewTicker(intervals.PingCheck)
 	defer ticker.Stop()
 	defer close(c.pingJobs)
 
@@ -302,6 +356,9 @@ func (c *StatisticsCollector) enqueuePingChecks(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return // program stopped
+		case <-intervalChanged:
+			intervals, intervalChanged = c.getIntervals()
+			ticker.Reset(intervals.PingCheck)
 		case <-ticker.C:
 			interfaces, err := c.db.GetAllInterfaces(ctx)
 			if err != nil {
@@ -35,6 +35,10 @@ type InterfaceAndPeerDatabaseRepo interface {
 	DeletePeer(ctx context.Context, id domain.PeerIdentifier) error
 	GetPeer(ctx context.Context, id domain.PeerIdentifier) (*domain.Peer, error)
 	GetUsedIpsPerSubnet(ctx context.Context, subnets []domain.Cidr) (map[domain.Cidr][]domain.Cidr, error)
+	QueryInterfaces(ctx context.Context, filter domain.InterfaceFilter, opts domain.ListOptions) (
+		[]domain.Interface, int, error)
+	GetInterfacePeerCounts(ctx context.Context) (map[domain.InterfaceIdentifier]domain.PeerCounts, error)
+	QueryPeers(ctx context.Context, filter domain.PeerFilter, opts domain.ListOptions) ([]domain.Peer, int, error)
 }
 
 type WgQuickController interface {
@@ -50,13 +54,20 @@ type EventBus interface {
 	Subscribe(topic string, fn interface{}) error
 }
 
+type AdmissionController interface {
+	// AdmitPeer sends the proposed peer to the admission webhooks, old is nil for new peers. Mutating webhooks may
+	// change the proposed peer.
+	AdmitPeer(ctx context.Context, old, new *domain.Peer) error
+}
+
 // endregion dependencies
 
 type Manager struct {
-	cfg *config.Config
-	bus EventBus
-	db  InterfaceAndPeerDatabaseRepo
-	wg  *ControllerManager
+	cfg       *config.Config
+	bus       EventBus
+	db        InterfaceAndPeerDatabaseRepo
+	wg        *ControllerManager
+	admission AdmissionController // optional, nil if no admission webhooks are used
 
 	userLockMap *sync.Map
 }
@@ -66,12 +77,14 @@ func NewWireGuardManager(
 	bus EventBus,
 	wg *ControllerManager,
 	db InterfaceAndPeerDatabaseRepo,
+	admission AdmissionController,
 ) (*Manager, error) {
 	m := &Manager{
 		cfg:         cfg,
 		bus:         bus,
 		wg:          wg,
 		db:          db,
+		admission:   admission,
 		userLockMap: &sync.Map{},
 	}
 
@@ -297,6 +310,7 @@ func (m Manager) runExpiredPeersCheck(ctx context.Context) {
 			}
 
 			m.checkExpiredPeers(ctx, peers)
+			m.checkExpiringPeers(ctx, &iface, peers)
 		}
 	}
 }
@@ -0,0 +1,203 @@
+package wireguard
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// checkExpiringPeers sends the due expiry reminders of the peers of the given interface.
+func (m Manager) checkExpiringPeers(ctx context.Context, iface *domain.Interface, peers []domain.Peer) {
+	if len(m.cfg.Core.PeerExpiryReminders) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, peer := range peers {
+		if !peer.ExpiryReminderDue(m.cfg.Core.PeerExpiryReminders, now) {
+			continue
+		}
+
+		if err := m.sendExpiryReminder(ctx, iface, &peer, now); err != nil {
+			slog.Error("failed to send expiry reminder", "peer", peer.Identifier, "error", err)
+		}
+	}
+}
+
+// sendExpiryReminder marks the reminder as sent and publishes it. If the interface allows self-service extensions,
+// a new extension link is created, it replaces the link of the previous reminder.
+func (m Manager) sendExpiryReminder(
+	ctx context.Context,
+	iface *domain.Interface,
+	peer *domain.Peer,
+	now time.Time,
+) error {
+	var extensionLink, tokenHash string
+	if iface.PeerExtensionDays > 0 {
+		token, hash, err := newPeerExtensionToken(peer.Identifier)
+		if err != nil {
+			return err
+		}
+		extensionLink = m.cfg.Web.ExternalUrl + "/api/v0/peer/extend?token=" + url.QueryEscape(token)
+		tokenHash = hash
+	}
+
+	err := m.db.SavePeer(ctx, peer.Identifier, func(p *domain.Peer) (*domain.Peer, error) {
+		p.ExpiryReminderSentAt = &now
+		p.ExtensionTokenHash = tokenHash
+		return p, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store reminder state: %w", err)
+	}
+	peer.ExpiryReminderSentAt = &now
+
+	slog.Debug("peer expires soon, sending reminder", "peer", peer.Identifier, "expires", peer.ExpiresAt)
+	m.bus.Publish(app.TopicPeerExpiring, *peer, extensionLink)
+
+	return nil
+}
+
+// GetPeerExtension returns the peer of a self-service extension token and its expiry date after the extension.
+// The token is the authorization, so no user needs to be logged in.
+func (m Manager) GetPeerExtension(ctx context.Context, token string) (*domain.Peer, time.Time, error) {
+	peer, expiresAt, err := m.resolvePeerExtension(ctx, token)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return peer, expiresAt, nil
+}
+
+// ExtendPeer extends the expiry date of the peer of a self-service extension token, a peer that has been disabled
+// because it expired is enabled again. The token can only be used once.
+func (m Manager) ExtendPeer(ctx context.Context, token string) (*domain.Peer, error) {
+	peer, expiresAt, err := m.resolvePeerExtension(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// the change is made on behalf of the owner of the peer
+	ctx = domain.SetUserInfo(ctx, &domain.ContextUserInfo{Id: peer.UserIdentifier})
+
+	existingPeer := *peer
+	peer.ExpiresAt = &expiresAt
+	peer.ExtensionTokenHash = ""
+	if peer.IsDisabled() && peer.DisabledReason == domain.DisabledReasonExpired {
+		peer.Disabled = nil
+		peer.DisabledReason = ""
+	}
+
+	if m.admission != nil {
+		if err := m.admission.AdmitPeer(ctx, &existingPeer, peer); err != nil {
+			return nil, fmt.Errorf("extension not allowed: %w", err)
+		}
+	}
+
+	// the token is consumed before the peer is saved, as saving keeps the stored token of the peer
+	err = m.db.SavePeer(ctx, peer.Identifier, func(p *domain.Peer) (*domain.Peer, error) {
+		p.ExtensionTokenHash = ""
+		return p, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to invalidate extension link: %w", err)
+	}
+	if err := m.savePeers(ctx, peer); err != nil {
+		return nil, fmt.Errorf("extension failure: %w", err)
+	}
+
+	m.bus.Publish(app.TopicPeerUpdated, *peer)
+
+	slog.Info("peer extended by its user", "peer", peer.Identifier, "user", peer.UserIdentifier,
+		"expires", expiresAt)
+
+	return peer, nil
+}
+
+// resolvePeerExtension validates the extension token and returns the peer and its new expiry date.
+func (m Manager) resolvePeerExtension(ctx context.Context, token string) (*domain.Peer, time.Time, error) {
+	invalidToken := errors.Join(errors.New("invalid or already used extension link"), domain.ErrInvalidData)
+
+	peerId, secret, ok := parsePeerExtensionToken(token)
+	if !ok {
+		return nil, time.Time{}, invalidToken
+	}
+
+	peer, err := m.db.GetPeer(ctx, peerId)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, time.Time{}, invalidToken
+		}
+		return nil, time.Time{}, fmt.Errorf("unable to load peer %s: %w", peerId, err)
+	}
+
+	if peer.ExtensionTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(peer.ExtensionTokenHash), []byte(hashPeerExtensionSecret(secret))) != 1 {
+		return nil, time.Time{}, invalidToken
+	}
+	if peer.ExpiresAt == nil {
+		return nil, time.Time{}, errors.Join(errors.New("peer does not expire"), domain.ErrInvalidData)
+	}
+	if peer.IsDisabled() && peer.DisabledReason != domain.DisabledReasonExpired {
+		return nil, time.Time{}, errors.Join(errors.New("peer has been disabled"), domain.ErrInvalidData)
+	}
+
+	iface, err := m.db.GetInterface(ctx, peer.InterfaceIdentifier)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("unable to load interface %s: %w", peer.InterfaceIdentifier, err)
+	}
+	if iface.PeerExtensionDays <= 0 {
+		return nil, time.Time{}, errors.Join(errors.New("self-service extensions are disabled"),
+			domain.ErrInvalidData)
+	}
+
+	// extensions start now and do not add up, so that the link cannot be used to extend a peer indefinitely ahead
+	expiresAt := time.Now().Add(time.Duration(iface.PeerExtensionDays) * 24 * time.Hour)
+	if peer.ExpiresAt.After(expiresAt) {
+		expiresAt = *peer.ExpiresAt
+	}
+
+	return peer, expiresAt, nil
+}
+
+// newPeerExtensionToken returns a new extension token of the peer and the hash of its secret. Only the hash is stored.
+func newPeerExtensionToken(id domain.PeerIdentifier) (string, string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate extension token: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	token := base64.RawURLEncoding.EncodeToString([]byte(id)) + "." + secret
+	return token, hashPeerExtensionSecret(secret), nil
+}
+
+func parsePeerExtensionToken(token string) (domain.PeerIdentifier, string, bool) {
+	encodedId, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return "", "", false
+	}
+	id, err := base64.RawURLEncoding.DecodeString(encodedId)
+	if err != nil || len(id) == 0 {
+		return "", "", false
+	}
+
+	return domain.PeerIdentifier(id), secret, true
+}
+
+func hashPeerExtensionSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,98 @@
+package wireguard
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+type recordingBus struct {
+	mockBus
+	published map[string][]any
+}
+
+func (b *recordingBus) Publish(topic string, args ...any) {
+	if b.published == nil {
+		b.published = make(map[string][]any)
+	}
+	b.published[topic] = args
+}
+
+func TestManager_PeerExpiryReminderAndExtension(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Core.PeerExpiryReminders = []time.Duration{14 * 24 * time.Hour, 24 * time.Hour}
+	cfg.Web.ExternalUrl = "https://vpn.example.com"
+
+	iface := &domain.Interface{Identifier: "wg0", Type: domain.InterfaceTypeServer, PeerExtensionDays: 30}
+	expiresAt := time.Now().Add(10 * 24 * time.Hour)
+	peer := domain.Peer{
+		Identifier:          "peer-key",
+		UserIdentifier:      "alice",
+		InterfaceIdentifier: "wg0",
+		ExpiresAt:           &expiresAt,
+		Interface:           domain.PeerInterfaceConfig{KeyPair: domain.KeyPair{PublicKey: "peer-key"}},
+	}
+	db := &mockDB{iface: iface, savedPeers: map[domain.PeerIdentifier]*domain.Peer{peer.Identifier: &peer}}
+	bus := &recordingBus{}
+	m := Manager{cfg: cfg, db: db, bus: bus}
+
+	m.checkExpiringPeers(context.Background(), iface, []domain.Peer{peer})
+
+	require.Contains(t, bus.published, app.TopicPeerExpiring)
+	link := bus.published[app.TopicPeerExpiring][1].(string)
+	require.True(t, strings.HasPrefix(link, "https://vpn.example.com/api/v0/peer/extend?token="))
+	stored := db.savedPeers[peer.Identifier]
+	require.NotNil(t, stored.ExpiryReminderSentAt)
+	require.NotEmpty(t, stored.ExtensionTokenHash)
+
+	// the reminder is only sent once
+	delete(bus.published, app.TopicPeerExpiring)
+	m.checkExpiringPeers(context.Background(), iface, []domain.Peer{*stored})
+	assert.NotContains(t, bus.published, app.TopicPeerExpiring)
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	token := parsed.Query().Get("token")
+
+	// admin changes keep the outstanding extension link, the API models do not contain the token
+	adminCtx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "admin", IsAdmin: true})
+	edited := *stored
+	edited.ExtensionTokenHash = ""
+	edited.Notes = "edited by admin"
+	_, err = m.UpdatePeer(adminCtx, &edited)
+	require.NoError(t, err)
+	stored = db.savedPeers[peer.Identifier]
+	assert.Equal(t, "edited by admin", stored.Notes)
+	require.NotEmpty(t, stored.ExtensionTokenHash)
+
+	_, _, err = m.GetPeerExtension(context.Background(), token+"x")
+	require.ErrorIs(t, err, domain.ErrInvalidData)
+
+	_, newExpiry, err := m.GetPeerExtension(context.Background(), token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), newExpiry, time.Minute)
+
+	// the extension enables peers that have been disabled because they expired
+	now := time.Now()
+	stored.Disabled = &now
+	stored.DisabledReason = domain.DisabledReasonExpired
+
+	extended, err := m.ExtendPeer(context.Background(), token)
+	require.NoError(t, err)
+	assert.False(t, extended.IsDisabled())
+	assert.WithinDuration(t, newExpiry, *db.savedPeers[peer.Identifier].ExpiresAt, time.Minute)
+	assert.Empty(t, db.savedPeers[peer.Identifier].ExtensionTokenHash)
+
+	// the link can only be used once
+	_, err = m.ExtendPeer(context.Background(), token)
+	require.ErrorIs(t, err, domain.ErrInvalidData)
+}
@@ -36,6 +36,15 @@ func (m Manager) GetInterfaceAndPeers(ctx context.Context, id domain.InterfaceId
 	return m.db.GetInterfaceAndPeers(ctx, id)
 }
 
+// GetInterface returns the interface for the given interface identifier.
+func (m Manager) GetInterface(ctx context.Context, id domain.InterfaceIdentifier) (*domain.Interface, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	return m.db.GetInterface(ctx, id)
+}
+
 // GetAllInterfaces returns all interfaces that are available in the database.
 func (m Manager) GetAllInterfaces(ctx context.Context) ([]domain.Interface, error) {
 	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
@@ -68,6 +77,33 @@ func (m Manager) GetAllInterfacesAndPeers(ctx context.Context) ([]domain.Interfa
 	return interfaces, allPeers, nil
 }
 
+// QueryInterfaces returns one page of the interfaces that match the given filter, the number of peers of each
+// returned interface and the total number of matching interfaces.
+func (m Manager) QueryInterfaces(ctx context.Context, filter domain.InterfaceFilter, opts domain.ListOptions) (
+	[]domain.Interface,
+	map[domain.InterfaceIdentifier]domain.PeerCounts,
+	int,
+	error,
+) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, nil, 0, err
+	}
+	if err := opts.Validate(domain.InterfaceSortFields); err != nil {
+		return nil, nil, 0, err
+	}
+
+	interfaces, total, err := m.db.QueryInterfaces(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("unable to load interfaces: %w", err)
+	}
+	counts, err := m.db.GetInterfacePeerCounts(ctx)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("unable to count peers: %w", err)
+	}
+
+	return interfaces, counts, total, nil
+}
+
 // GetUserInterfaces is deprecated. Self-provisioning was removed.
 func (m Manager) GetUserInterfaces(ctx context.Context, _ domain.UserIdentifier) ([]domain.Interface, error) {
 	return []domain.Interface{}, nil
@@ -775,6 +811,13 @@ func (m Manager) DeleteInterface(ctx context.Context, id domain.InterfaceIdentif
 	}
 
 	m.bus.Publish(app.TopicInterfaceDeleted, *existingInterface)
+	m.bus.Publish(app.TopicAuditInterfaceChanged, domain.AuditEventWrapper[audit.InterfaceEvent]{
+		Ctx: ctx,
+		Event: audit.InterfaceEvent{
+			Interface: *existingInterface,
+			Action:    "delete",
+		},
+	})
 
 	return nil
 }
@@ -792,8 +835,10 @@ func (m Manager) saveInterface(ctx context.Context, iface *domain.Interface) (
 	applyToHost := m.cfg.Core.WireGuardHostManagement
 
 	oldEnabled, newEnabled, routeTableChanged := false, !iface.IsDisabled(), false // if the interface did not exist, we assume it was not enabled
+	var previous *domain.Interface
 	oldInterface, err := m.db.GetInterface(ctx, iface.Identifier)
 	if err == nil {
+		previous = oldInterface
 		oldEnabled, newEnabled, routeTableChanged = m.getInterfaceStateHistory(oldInterface, iface)
 		if iface.ClientType == 0 {
 			iface.ClientType = oldInterface.ClientType
@@ -931,6 +976,7 @@ func (m Manager) saveInterface(ctx context.Context, iface *domain.Interface) (
 		Ctx: ctx,
 		Event: audit.InterfaceEvent{
 			Interface: *iface,
+			Previous:  previous,
 			Action:    "save",
 		},
 	})
@@ -79,6 +79,27 @@ func (m Manager) GetUserPeers(ctx context.Context, id domain.UserIdentifier) ([]
 	return m.db.GetUserPeers(ctx, id)
 }
 
+// QueryPeers returns one page of the peers that match the given filter and the total number of matching peers.
+// Admins can query all peers, other users only their own peers.
+func (m Manager) QueryPeers(ctx context.Context, filter domain.PeerFilter, opts domain.ListOptions) (
+	[]domain.Peer,
+	int,
+	error,
+) {
+	if filter.UserIdentifier != "" {
+		if err := domain.ValidateUserAccessRights(ctx, filter.UserIdentifier); err != nil {
+			return nil, 0, err
+		}
+	} else if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := opts.Validate(domain.PeerSortFields); err != nil {
+		return nil, 0, err
+	}
+
+	return m.db.QueryPeers(ctx, filter, opts)
+}
+
 // CreateUserPeerOnInterface creates a new peer for the given user on the given interface.
 // Peer settings are derived from the interface defaults. The user is not allowed to override any settings.
 func (m Manager) CreateUserPeerOnInterface(
@@ -448,6 +469,13 @@ func (m Manager) DeletePeer(ctx context.Context, id domain.PeerIdentifier) error
 	}
 
 	m.bus.Publish(app.TopicPeerDeleted, *peer)
+	m.bus.Publish(app.TopicAuditPeerChanged, domain.AuditEventWrapper[audit.PeerEvent]{
+		Ctx: ctx,
+		Event: audit.PeerEvent{
+			Action: "delete",
+			Peer:   *peer,
+		},
+	})
 	if applyToHost {
 		// Update routes after peers have changed
 		m.bus.Publish(app.TopicRouteUpdate, domain.RoutingTableInfo{
@@ -520,6 +548,16 @@ func (m Manager) savePeers(ctx context.Context, peers ...*domain.Peer) error {
 
 		iface := interfaces[peer.InterfaceIdentifier]
 
+		// the stored peer is loaded completely, so that the audit log only contains the actual changes
+		var previous *domain.Peer
+		if m.cfg.Statistics.CollectAuditData {
+			existing, err := m.db.GetPeer(ctx, peer.Identifier)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("unable to load peer %s: %w", peer.Identifier, err)
+			}
+			previous = existing
+		}
+
 		err := m.db.SavePeer(ctx, peer.Identifier, func(p *domain.Peer) (*domain.Peer, error) {
 			peer.CopyCalculatedAttributes(p)
 			peer.Interface.AdvancedSecurity = iface.AdvancedSecurity
@@ -546,8 +584,9 @@ func (m Manager) savePeers(ctx context.Context, peers ...*domain.Peer) error {
 		m.bus.Publish(app.TopicAuditPeerChanged, domain.AuditEventWrapper[audit.PeerEvent]{
 			Ctx: ctx,
 			Event: audit.PeerEvent{
-				Action: "save",
-				Peer:   *peer,
+				Action:   "save",
+				Peer:     *peer,
+				Previous: previous,
 			},
 		})
 	}
@@ -623,13 +662,20 @@ func (m Manager) getFreshPeerIpConfig(ctx context.Context, iface *domain.Interfa
 	return
 }
 
-func (m Manager) validatePeerModifications(ctx context.Context, _, _ *domain.Peer) error {
+func (m Manager) validatePeerModifications(ctx context.Context, old, new *domain.Peer) error {
 	currentUser := domain.GetUserInfo(ctx)
 
 	if !currentUser.IsAdmin {
 		return domain.ErrNoPermission
 	}
 
+	// changes made by the system itself, like disabling expired peers, are not subject to admission webhooks
+	if m.admission != nil && currentUser.Id != domain.SystemAdminContextUserInfo().Id {
+		if err := m.admission.AdmitPeer(ctx, old, new); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -649,6 +695,12 @@ func (m Manager) validatePeerCreation(ctx context.Context, _, new *domain.Peer)
 		return fmt.Errorf("invalid interface: %w", domain.ErrInvalidData)
 	}
 
+	if m.admission != nil && currentUser.Id != domain.SystemAdminContextUserInfo().Id {
+		if err := m.admission.AdmitPeer(ctx, nil, new); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -3,6 +3,7 @@ package wireguard
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/biezax/wg-portal/internal/config"
 	"github.com/biezax/wg-portal/internal/domain"
@@ -127,6 +128,10 @@ func (f *mockDB) SavePeer(
 }
 func (f *mockDB) DeletePeer(ctx context.Context, id domain.PeerIdentifier) error { return nil }
 func (f *mockDB) GetPeer(ctx context.Context, id domain.PeerIdentifier) (*domain.Peer, error) {
+	if peer, ok := f.savedPeers[id]; ok {
+		p := *peer
+		return &p, nil
+	}
 	return nil, domain.ErrNotFound
 }
 func (f *mockDB) GetUsedIpsPerSubnet(ctx context.Context, subnets []domain.Cidr) (
@@ -135,6 +140,23 @@ func (f *mockDB) GetUsedIpsPerSubnet(ctx context.Context, subnets []domain.Cidr)
 ) {
 	return map[domain.Cidr][]domain.Cidr{}, nil
 }
+func (f *mockDB) QueryInterfaces(ctx context.Context, filter domain.InterfaceFilter, opts domain.ListOptions) (
+	[]domain.Interface,
+	int,
+	error,
+) {
+	return nil, 0, nil
+}
+func (f *mockDB) GetInterfacePeerCounts(ctx context.Context) (map[domain.InterfaceIdentifier]domain.PeerCounts, error) {
+	return map[domain.InterfaceIdentifier]domain.PeerCounts{}, nil
+}
+func (f *mockDB) QueryPeers(ctx context.Context, filter domain.PeerFilter, opts domain.ListOptions) (
+	[]domain.Peer,
+	int,
+	error,
+) {
+	return nil, 0, nil
+}
 
 // --- Test ---
 
@@ -193,3 +215,37 @@ func TestCreatePeer_SetsIdentifier_FromPublicKey(t *testing.T) {
 		t.Fatalf("expected peer with identifier %q to be saved in DB", expectedId)
 	}
 }
+
+type mockAdmission struct {
+	calls int
+}
+
+func (f *mockAdmission) AdmitPeer(_ context.Context, _, _ *domain.Peer) error {
+	f.calls++
+	return domain.ErrInvalidData
+}
+
+func TestValidatePeerModifications_SkipsAdmissionForSystemChanges(t *testing.T) {
+	admission := &mockAdmission{}
+	m := Manager{cfg: &config.Config{}, admission: admission}
+	old := &domain.Peer{Identifier: "peer", InterfaceIdentifier: "wg0"}
+	expired := *old
+	expired.Disabled = &time.Time{}
+	expired.DisabledReason = domain.DisabledReasonExpired
+
+	systemCtx := domain.SetUserInfo(context.Background(), domain.SystemAdminContextUserInfo())
+	if err := m.validatePeerModifications(systemCtx, old, &expired); err != nil {
+		t.Fatalf("expected system change to bypass admission, got: %v", err)
+	}
+	if admission.calls != 0 {
+		t.Fatalf("expected no admission calls for system changes, got %d", admission.calls)
+	}
+
+	adminCtx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "admin", IsAdmin: true})
+	if err := m.validatePeerModifications(adminCtx, old, &expired); err == nil {
+		t.Fatal("expected admission webhooks to be called for admin changes")
+	}
+	if admission.calls != 1 {
+		t.Fatalf("expected one admission call, got %d", admission.calls)
+	}
+}
@@ -0,0 +1,465 @@
+package wireguard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Biezax/wgctrl/wgtypes"
+
+	"github.com/biezax/wg-portal/internal/app"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// ImportWgQuickInterface creates a new interface and its peers from wg-quick configuration files.
+// Client configurations are matched to the [Peer] sections of the server configuration by their public key, so that
+// imported peers keep their private keys and client settings.
+// If dryRun is set, or if conflicts are detected, nothing is stored and only the import plan is returned.
+func (m Manager) ImportWgQuickInterface(
+	ctx context.Context,
+	req *domain.WgQuickImportRequest,
+	dryRun bool,
+) (*domain.WgQuickImportPlan, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	plan, err := m.planWgQuickImport(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun || plan.HasConflicts() {
+		return plan, nil
+	}
+
+	if err := m.validateInterfaceCreation(ctx, nil, plan.Interface); err != nil {
+		return nil, fmt.Errorf("creation not allowed: %w", err)
+	}
+
+	// From here on, a failure rolls back the whole import so that no partially imported interface remains.
+	iface, err := m.saveInterface(ctx, plan.Interface)
+	if err != nil {
+		return nil, m.rollbackWgQuickImport(ctx, plan.Interface.Identifier,
+			fmt.Errorf("creation of interface %s failed: %w", plan.Interface.Identifier, err))
+	}
+	plan.Interface = iface
+
+	// peers can only be validated once the interface exists
+	peers := make([]*domain.Peer, len(plan.Peers))
+	for i := range plan.Peers {
+		peers[i] = &plan.Peers[i]
+		if err := m.validatePeerCreation(ctx, nil, peers[i]); err != nil {
+			return nil, m.rollbackWgQuickImport(ctx, iface.Identifier,
+				fmt.Errorf("creation of peer %s not allowed: %w", peers[i].Identifier, err))
+		}
+	}
+	if err := m.savePeers(ctx, peers...); err != nil {
+		savedPeers, _ := m.db.GetInterfacePeers(ctx, iface.Identifier)
+		return nil, m.rollbackWgQuickImport(ctx, iface.Identifier,
+			fmt.Errorf("creation of peers failed after %d of %d peers: %w", len(savedPeers), len(peers), err))
+	}
+
+	m.bus.Publish(app.TopicInterfaceCreated, *iface)
+	for _, peer := range peers {
+		m.bus.Publish(app.TopicPeerCreated, *peer)
+	}
+
+	plan.Committed = true
+
+	slog.Info("imported wg-quick interface", "interface", iface.Identifier, "peers", len(peers))
+
+	return plan, nil
+}
+
+// region helper-functions
+
+// rollbackWgQuickImport removes the partially imported interface and its peers from the database and the host.
+func (m Manager) rollbackWgQuickImport(ctx context.Context, id domain.InterfaceIdentifier, cause error) error {
+	if err := m.DeleteInterface(ctx, id); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("import of %s failed: %w; rollback failed: %v", id, cause, err)
+	}
+
+	slog.Warn("rolled back failed wg-quick import", "interface", id, "error", cause)
+
+	return fmt.Errorf("import of %s failed, changes have been rolled back: %w", id, cause)
+}
+
+func (m Manager) planWgQuickImport(
+	ctx context.Context,
+	req *domain.WgQuickImportRequest,
+) (*domain.WgQuickImportPlan, error) {
+	serverCfg, err := domain.ParseWgQuickConfig(bytes.NewReader(req.Server.Content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", req.Server.Name, err)
+	}
+
+	id := req.Identifier
+	if id == "" {
+		id = domain.InterfaceIdentifier(domain.WgQuickNameFromFileName(req.Server.Name))
+	}
+	if id == "" || id == "." {
+		return nil, fmt.Errorf("missing interface identifier: %w", domain.ErrInvalidData)
+	}
+
+	plan := &domain.WgQuickImportPlan{}
+
+	// index client configurations by their public key
+	clients := make(map[string]*domain.WgQuickConfig, len(req.Clients))
+	clientNames := make(map[string]string, len(req.Clients))
+	var clientOrder []string
+	for _, file := range req.Clients {
+		clientCfg, err := domain.ParseWgQuickConfig(bytes.NewReader(file.Content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Name, err)
+		}
+		publicKey := clientCfg.PublicKey()
+		if _, exists := clients[publicKey]; exists {
+			plan.Warnings = append(plan.Warnings,
+				fmt.Sprintf("client config %s duplicates the keys of %s, ignoring it", file.Name, clientNames[publicKey]))
+			continue
+		}
+		clients[publicKey] = clientCfg
+		clientNames[publicKey] = file.Name
+		clientOrder = append(clientOrder, publicKey)
+	}
+
+	plan.Interface = m.wgQuickInterface(ctx, id, req.Backend, serverCfg, clients)
+
+	seenPeers := make(map[string]struct{}, len(serverCfg.Peers))
+	for _, serverPeer := range serverCfg.Peers {
+		if _, seen := seenPeers[serverPeer.PublicKey]; seen {
+			plan.Conflicts = append(plan.Conflicts,
+				fmt.Sprintf("peer %s is defined multiple times in %s", serverPeer.PublicKey, req.Server.Name))
+			continue
+		}
+		seenPeers[serverPeer.PublicKey] = struct{}{}
+
+		clientCfg := clients[serverPeer.PublicKey]
+		clientName := clientNames[serverPeer.PublicKey]
+		switch {
+		case clientCfg == nil:
+			plan.Warnings = append(plan.Warnings,
+				fmt.Sprintf("no client config found for peer %s, its private key is unknown", serverPeer.PublicKey))
+		case len(clientCfg.Peers) == 0 || clientCfg.Peers[0].PublicKey != plan.Interface.PublicKey:
+			plan.Warnings = append(plan.Warnings,
+				fmt.Sprintf("client config %s does not reference the public key of interface %s", clientName, id))
+		}
+
+		plan.Peers = append(plan.Peers, wgQuickPeer(ctx, plan.Interface, &serverPeer, clientCfg, clientName))
+	}
+
+	for _, publicKey := range clientOrder {
+		if _, matched := seenPeers[publicKey]; !matched {
+			plan.Warnings = append(plan.Warnings,
+				fmt.Sprintf("client config %s does not match any peer of %s", clientNames[publicKey], req.Server.Name))
+		}
+	}
+
+	conflicts, err := m.FindImportConflicts(ctx, plan.Interface, plan.Peers)
+	if err != nil {
+		return nil, err
+	}
+	plan.Conflicts = append(plan.Conflicts, conflicts...)
+
+	return plan, nil
+}
+
+func (m Manager) wgQuickInterface(
+	ctx context.Context,
+	id domain.InterfaceIdentifier,
+	backend domain.InterfaceBackend,
+	serverCfg *domain.WgQuickConfig,
+	clients map[string]*domain.WgQuickConfig,
+) *domain.Interface {
+	now := time.Now()
+	currentUser := domain.GetUserInfo(ctx)
+
+	if backend == "" {
+		backend = domain.InterfaceBackend(m.cfg.Backend.Default)
+	}
+
+	networks := make([]domain.Cidr, len(serverCfg.Interface.Addresses))
+	for i, address := range serverCfg.Interface.Addresses {
+		networks[i] = address.NetworkAddr()
+	}
+
+	// derive the peer defaults from the values most of the client configurations agree on
+	var endpoints, dns, dnsSearch, allowedIPs []string
+	var mtus, keepalives []int
+	for _, peer := range serverCfg.Peers {
+		clientCfg := clients[peer.PublicKey]
+		if clientCfg == nil {
+			continue
+		}
+		dns = append(dns, strings.Join(clientCfg.Interface.Dns, ","))
+		dnsSearch = append(dnsSearch, strings.Join(clientCfg.Interface.DnsSearch, ","))
+		mtus = append(mtus, clientCfg.Interface.Mtu)
+		if len(clientCfg.Peers) > 0 {
+			endpoints = append(endpoints, clientCfg.Peers[0].Endpoint)
+			allowedIPs = append(allowedIPs, domain.CidrsToString(clientCfg.Peers[0].AllowedIPs))
+			keepalives = append(keepalives, clientCfg.Peers[0].PersistentKeepalive)
+		}
+	}
+
+	iface := &domain.Interface{
+		BaseModel: domain.BaseModel{
+			CreatedBy: string(currentUser.Id),
+			UpdatedBy: string(currentUser.Id),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Identifier: id,
+		KeyPair: domain.KeyPair{
+			PrivateKey: serverCfg.Interface.PrivateKey,
+			PublicKey:  serverCfg.PublicKey(),
+		},
+		ListenPort:                 serverCfg.Interface.ListenPort,
+		Addresses:                  serverCfg.Interface.Addresses,
+		DnsStr:                     strings.Join(serverCfg.Interface.Dns, ","),
+		DnsSearchStr:               strings.Join(serverCfg.Interface.DnsSearch, ","),
+		Mtu:                        serverCfg.Interface.Mtu,
+		FirewallMark:               serverCfg.Interface.FirewallMark,
+		RoutingTable:               serverCfg.Interface.RoutingTable,
+		PreUp:                      serverCfg.Interface.PreUp,
+		PostUp:                     serverCfg.Interface.PostUp,
+		PreDown:                    serverCfg.Interface.PreDown,
+		PostDown:                   serverCfg.Interface.PostDown,
+		SaveConfig:                 serverCfg.Interface.SaveConfig,
+		DisplayName:                string(id),
+		Backend:                    backend,
+		PeerDefNetworkStr:          domain.CidrsToString(networks),
+		PeerDefDnsStr:              mostCommonValue(dns, ""),
+		PeerDefDnsSearchStr:        mostCommonValue(dnsSearch, ""),
+		PeerDefEndpoint:            mostCommonValue(endpoints, ""),
+		PeerDefAllowedIPsStr:       mostCommonValue(allowedIPs, domain.CidrsToString(networks)),
+		PeerDefMtu:                 mostCommonValue(mtus, DefaultMTU),
+		PeerDefPersistentKeepalive: mostCommonValue(keepalives, DefaultPersistentKeepalive),
+		AdvancedSecurity:           serverCfg.Interface.AdvancedSecurity,
+		ClientType:                 wgtypes.NativeClient,
+	}
+	if iface.Mtu == 0 {
+		iface.Mtu = DefaultMTU
+	}
+	if iface.AdvancedSecurity != nil {
+		iface.ClientType = wgtypes.AmneziaClient
+	}
+
+	// predict the interface type: a single peer with an endpoint and no listen port is a typical client setup
+	switch {
+	case len(serverCfg.Peers) == 1 && serverCfg.Peers[0].Endpoint != "" && serverCfg.Interface.ListenPort == 0:
+		iface.Type = domain.InterfaceTypeClient
+	case serverCfg.Interface.ListenPort != 0 || len(serverCfg.Peers) > 1:
+		iface.Type = domain.InterfaceTypeServer
+	default:
+		iface.Type = domain.InterfaceTypeAny
+	}
+
+	return iface
+}
+
+func wgQuickPeer(
+	ctx context.Context,
+	iface *domain.Interface,
+	serverPeer *domain.WgQuickPeer,
+	clientCfg *domain.WgQuickConfig,
+	clientFileName string,
+) domain.Peer {
+	now := time.Now()
+	currentUser := domain.GetUserInfo(ctx)
+
+	peer := domain.Peer{
+		BaseModel: domain.BaseModel{
+			CreatedBy: string(currentUser.Id),
+			UpdatedBy: string(currentUser.Id),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Identifier:          domain.PeerIdentifier(serverPeer.PublicKey),
+		InterfaceIdentifier: iface.Identifier,
+		DisplayName:         serverPeer.Name,
+		PresharedKey:        domain.PreSharedKey(serverPeer.PresharedKey),
+		Interface: domain.PeerInterfaceConfig{
+			KeyPair: domain.KeyPair{PublicKey: serverPeer.PublicKey},
+		},
+	}
+	peer.Endpoint = domain.NewConfigOption(iface.PeerDefEndpoint, true)
+	peer.EndpointPublicKey = domain.NewConfigOption(iface.PublicKey, true)
+	peer.AllowedIPsStr = domain.NewConfigOption(iface.PeerDefAllowedIPsStr, true)
+	peer.PersistentKeepalive = wgQuickOption(serverPeer.PersistentKeepalive, iface.PeerDefPersistentKeepalive)
+	peer.Interface.DnsStr = domain.NewConfigOption(iface.PeerDefDnsStr, true)
+	peer.Interface.DnsSearchStr = domain.NewConfigOption(iface.PeerDefDnsSearchStr, true)
+	peer.Interface.Mtu = domain.NewConfigOption(iface.PeerDefMtu, true)
+	peer.Interface.FirewallMark = domain.NewConfigOption(iface.PeerDefFirewallMark, true)
+	peer.Interface.RoutingTable = domain.NewConfigOption(iface.PeerDefRoutingTable, true)
+	peer.Interface.PreUp = domain.NewConfigOption(iface.PeerDefPreUp, true)
+	peer.Interface.PostUp = domain.NewConfigOption(iface.PeerDefPostUp, true)
+	peer.Interface.PreDown = domain.NewConfigOption(iface.PeerDefPreDown, true)
+	peer.Interface.PostDown = domain.NewConfigOption(iface.PeerDefPostDown, true)
+	peer.Interface.AdvancedSecurity = iface.AdvancedSecurity
+
+	switch iface.Type {
+	case domain.InterfaceTypeServer:
+		peer.Interface.Type = domain.InterfaceTypeClient
+	case domain.InterfaceTypeClient:
+		peer.Interface.Type = domain.InterfaceTypeServer
+		// the remote side of a client interface is not a peer we provision; allowed ips and endpoint are taken as-is
+		peer.AllowedIPsStr = domain.NewConfigOption(domain.CidrsToString(serverPeer.AllowedIPs), false)
+		peer.Endpoint = domain.NewConfigOption(serverPeer.Endpoint, false)
+	default:
+		peer.Interface.Type = domain.InterfaceTypeAny
+		peer.Endpoint = domain.NewConfigOption(serverPeer.Endpoint, false)
+	}
+
+	if clientCfg != nil {
+		peer.Interface.PrivateKey = clientCfg.Interface.PrivateKey
+		peer.Interface.Addresses = clientCfg.Interface.Addresses
+		peer.Interface.DnsStr = wgQuickOption(strings.Join(clientCfg.Interface.Dns, ","), iface.PeerDefDnsStr)
+		peer.Interface.DnsSearchStr = wgQuickOption(strings.Join(clientCfg.Interface.DnsSearch, ","),
+			iface.PeerDefDnsSearchStr)
+		peer.Interface.Mtu = wgQuickOption(clientCfg.Interface.Mtu, iface.PeerDefMtu)
+		if len(clientCfg.Peers) > 0 {
+			clientPeer := clientCfg.Peers[0]
+			peer.Endpoint = wgQuickOption(clientPeer.Endpoint, iface.PeerDefEndpoint)
+			peer.AllowedIPsStr = wgQuickOption(domain.CidrsToString(clientPeer.AllowedIPs), iface.PeerDefAllowedIPsStr)
+			peer.PersistentKeepalive = wgQuickOption(clientPeer.PersistentKeepalive, iface.PeerDefPersistentKeepalive)
+		}
+		if peer.DisplayName == "" {
+			peer.DisplayName = domain.WgQuickNameFromFileName(clientFileName)
+		}
+	} else {
+		// without a client config, the host routes within the interface networks are the peer addresses
+		networks, _ := domain.CidrsFromString(iface.PeerDefNetworkStr)
+		for _, allowedIP := range serverPeer.AllowedIPs {
+			isHost := allowedIP.NetLength == allowedIP.Prefix().Addr().BitLen()
+			if isHost && slices.ContainsFunc(networks, func(n domain.Cidr) bool { return n.Contains(allowedIP) }) {
+				peer.Interface.Addresses = append(peer.Interface.Addresses, allowedIP)
+			}
+		}
+	}
+
+	// allowed ips on the server side that are not covered by the peer addresses, for example routed subnets
+	if peer.Interface.Type != domain.InterfaceTypeServer {
+		var extraAllowedIPs []domain.Cidr
+		for _, allowedIP := range serverPeer.AllowedIPs {
+			if !slices.ContainsFunc(peer.Interface.Addresses, func(a domain.Cidr) bool {
+				return a.HostAddr().EqualPrefix(allowedIP)
+			}) {
+				extraAllowedIPs = append(extraAllowedIPs, allowedIP)
+			}
+		}
+		peer.ExtraAllowedIPsStr = domain.CidrsToString(extraAllowedIPs)
+	}
+
+	if peer.DisplayName == "" {
+		peer.DisplayName = "Imported Peer (" + serverPeer.PublicKey[0:8] + ")"
+	}
+
+	return peer
+}
+
+// FindImportConflicts returns the reasons why the imported interface and its peers cannot be stored next to the
+// existing interfaces and peers, for example duplicate identifiers, listen ports or overlapping addresses.
+func (m Manager) FindImportConflicts(
+	ctx context.Context,
+	iface *domain.Interface,
+	peers []domain.Peer,
+) ([]string, error) {
+	if err := domain.ValidateAdminAccessRights(ctx); err != nil {
+		return nil, err
+	}
+
+	var conflicts []string
+
+	existingInterfaces, err := m.db.GetAllInterfaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load existing interfaces: %w", err)
+	}
+	for _, existing := range existingInterfaces {
+		if existing.Identifier == iface.Identifier {
+			conflicts = append(conflicts, fmt.Sprintf("interface %s already exists", existing.Identifier))
+		}
+		if iface.ListenPort != 0 && existing.ListenPort == iface.ListenPort {
+			conflicts = append(conflicts, fmt.Sprintf("listen port %d is already used by interface %s",
+				existing.ListenPort, existing.Identifier))
+		}
+	}
+
+	// interfaces that are up on the host but not yet managed by WireGuard Portal would silently be taken over
+	if m.cfg.Core.WireGuardHostManagement {
+		physicalInterfaces, err := m.wg.GetController(*iface).GetInterfaces(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load interfaces of backend %s: %w", iface.Backend, err)
+		}
+		for _, physical := range physicalInterfaces {
+			if physical.Identifier == iface.Identifier {
+				conflicts = append(conflicts, fmt.Sprintf("interface %s already exists on backend %s",
+					physical.Identifier, iface.Backend))
+			}
+			if iface.ListenPort != 0 && physical.ListenPort == iface.ListenPort {
+				conflicts = append(conflicts, fmt.Sprintf("listen port %d is already used by interface %s on backend %s",
+					physical.ListenPort, physical.Identifier, iface.Backend))
+			}
+		}
+	}
+
+	existingIps, err := m.db.GetInterfaceIps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load existing interface addresses: %w", err)
+	}
+	for existingId, cidrs := range existingIps {
+		for _, cidr := range cidrs {
+			for _, address := range iface.Addresses {
+				if cidr.Prefix().Masked().Overlaps(address.Prefix().Masked()) {
+					conflicts = append(conflicts, fmt.Sprintf("address %s overlaps with %s of interface %s",
+						address, cidr, existingId))
+				}
+			}
+		}
+	}
+
+	for _, peer := range peers {
+		existingPeer, err := m.db.GetPeer(ctx, peer.Identifier)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("unable to load existing peer %s: %w", peer.Identifier, err)
+		}
+		if existingPeer != nil {
+			conflicts = append(conflicts, fmt.Sprintf("peer %s already exists on interface %s",
+				peer.Identifier, existingPeer.InterfaceIdentifier))
+		}
+	}
+
+	slices.Sort(conflicts) // map iteration above is random, keep the report stable
+
+	return conflicts, nil
+}
+
+// wgQuickOption returns an overridable option if the value matches the interface default, otherwise a fixed option.
+func wgQuickOption[T comparable](value, defaultValue T) domain.ConfigOption[T] {
+	return domain.NewConfigOption(value, value == defaultValue)
+}
+
+// mostCommonValue returns the most common non-zero value, or the fallback if there is none.
+// Ties are resolved in favour of the value that was seen first.
+func mostCommonValue[T comparable](values []T, fallback T) T {
+	var zero T
+	counts := make(map[T]int, len(values))
+	result, maxCount := fallback, 0
+	for _, value := range values {
+		if value == zero {
+			continue
+		}
+		counts[value]++
+		if counts[value] > maxCount {
+			result, maxCount = value, counts[value]
+		}
+	}
+	return result
+}
+
+// endregion helper-functions
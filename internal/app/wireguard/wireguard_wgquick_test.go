@@ -0,0 +1,278 @@
+package wireguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Biezax/wgctrl/wgtypes"
+
+	"github.com/biezax/wg-portal/internal/config"
+	"github.com/biezax/wg-portal/internal/domain"
+)
+
+// wgQuickMockDB behaves like the real repository for unknown interfaces and keeps track of deletions.
+type wgQuickMockDB struct {
+	*mockDB
+	failPeer domain.PeerIdentifier // saving this peer fails
+}
+
+func (f *wgQuickMockDB) GetInterface(_ context.Context, id domain.InterfaceIdentifier) (*domain.Interface, error) {
+	if f.iface == nil || f.iface.Identifier != id {
+		return nil, domain.ErrNotFound
+	}
+	return f.iface, nil
+}
+func (f *wgQuickMockDB) GetInterfaceAndPeers(ctx context.Context, id domain.InterfaceIdentifier) (
+	*domain.Interface,
+	[]domain.Peer,
+	error,
+) {
+	iface, err := f.GetInterface(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	peers, _ := f.GetInterfacePeers(ctx, id)
+	return iface, peers, nil
+}
+func (f *wgQuickMockDB) GetInterfacePeers(_ context.Context, id domain.InterfaceIdentifier) ([]domain.Peer, error) {
+	var peers []domain.Peer
+	for _, peer := range f.savedPeers {
+		if peer.InterfaceIdentifier == id {
+			peers = append(peers, *peer)
+		}
+	}
+	return peers, nil
+}
+func (f *wgQuickMockDB) SavePeer(
+	ctx context.Context,
+	id domain.PeerIdentifier,
+	updateFunc func(in *domain.Peer) (*domain.Peer, error),
+) error {
+	if id == f.failPeer {
+		return errors.New("database unavailable")
+	}
+	return f.mockDB.SavePeer(ctx, id, updateFunc)
+}
+func (f *wgQuickMockDB) DeleteInterface(_ context.Context, _ domain.InterfaceIdentifier) error {
+	f.iface = nil
+	return nil
+}
+func (f *wgQuickMockDB) DeletePeer(_ context.Context, id domain.PeerIdentifier) error {
+	delete(f.savedPeers, id)
+	return nil
+}
+
+// wgQuickMockController reports the given interfaces as existing on the host.
+type wgQuickMockController struct {
+	mockController
+	interfaces []domain.PhysicalInterface
+}
+
+func (f *wgQuickMockController) GetInterfaces(_ context.Context) ([]domain.PhysicalInterface, error) {
+	return f.interfaces, nil
+}
+
+type wgQuickTestKeys struct {
+	server, alice, bob wgtypes.Key
+}
+
+func newWgQuickTestKeys(t *testing.T) wgQuickTestKeys {
+	var keys wgQuickTestKeys
+	for _, k := range []*wgtypes.Key{&keys.server, &keys.alice, &keys.bob} {
+		key, err := wgtypes.GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		*k = key
+	}
+	return keys
+}
+
+func newWgQuickTestRequest(keys wgQuickTestKeys) *domain.WgQuickImportRequest {
+	server := fmt.Sprintf(`[Interface]
+PrivateKey = %s
+Address = 10.11.0.1/24
+ListenPort = 51820
+PostUp = iptables -A FORWARD -i %%i -j ACCEPT
+
+### begin alice ###
+[Peer]
+PublicKey = %s
+AllowedIPs = 10.11.0.2/32
+### end alice ###
+
+[Peer]
+PublicKey = %s
+AllowedIPs = 10.11.0.3/32, 192.168.50.0/24
+`, keys.server, keys.alice.PublicKey(), keys.bob.PublicKey())
+
+	alice := fmt.Sprintf(`[Interface]
+PrivateKey = %s
+Address = 10.11.0.2/24
+DNS = 10.11.0.1
+
+[Peer]
+PublicKey = %s
+Endpoint = vpn.example.com:51820
+AllowedIPs = 0.0.0.0/0
+`, keys.alice, keys.server.PublicKey())
+
+	return &domain.WgQuickImportRequest{
+		Server:  domain.WgQuickFile{Name: "/etc/wireguard/wg7.conf", Content: []byte(server)},
+		Clients: []domain.WgQuickFile{{Name: "alice-phone.conf", Content: []byte(alice)}},
+	}
+}
+
+func TestImportWgQuickInterface_Commit(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Backend.Default = config.LocalBackendName
+
+	keys := newWgQuickTestKeys(t)
+	db := &wgQuickMockDB{mockDB: &mockDB{}}
+	m := Manager{cfg: cfg, db: db, bus: &mockBus{}}
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "admin", IsAdmin: true})
+
+	plan, err := m.ImportWgQuickInterface(ctx, newWgQuickTestRequest(keys), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Committed {
+		t.Fatalf("expected import to be committed, conflicts: %v", plan.Conflicts)
+	}
+	if len(plan.Warnings) != 1 {
+		t.Fatalf("expected a single warning for the peer without client config, got %v", plan.Warnings)
+	}
+
+	if db.iface == nil || db.iface.Identifier != "wg7" {
+		t.Fatalf("expected interface wg7 to be saved, got %+v", db.iface)
+	}
+	if db.iface.Type != domain.InterfaceTypeServer {
+		t.Fatalf("expected server interface, got %q", db.iface.Type)
+	}
+	if db.iface.PeerDefEndpoint != "vpn.example.com:51820" {
+		t.Fatalf("expected default endpoint from client config, got %q", db.iface.PeerDefEndpoint)
+	}
+
+	alice := db.savedPeers[domain.PeerIdentifier(keys.alice.PublicKey().String())]
+	if alice == nil {
+		t.Fatal("expected alice to be saved")
+	}
+	if alice.Interface.PrivateKey != keys.alice.String() {
+		t.Fatal("expected alice to keep the private key from the client config")
+	}
+	if alice.DisplayName != "alice" {
+		t.Fatalf("expected display name from PiVPN comment, got %q", alice.DisplayName)
+	}
+	if alice.Interface.DnsStr.GetValue() != "10.11.0.1" {
+		t.Fatalf("expected dns from client config, got %q", alice.Interface.DnsStr.GetValue())
+	}
+
+	bob := db.savedPeers[domain.PeerIdentifier(keys.bob.PublicKey().String())]
+	if bob == nil {
+		t.Fatal("expected bob to be saved")
+	}
+	if bob.Interface.PrivateKey != "" {
+		t.Fatal("expected bob to have no private key")
+	}
+	if domain.CidrsToString(bob.Interface.Addresses) != "10.11.0.3/32" {
+		t.Fatalf("unexpected addresses for bob: %v", bob.Interface.Addresses)
+	}
+	if bob.ExtraAllowedIPsStr != "192.168.50.0/24" {
+		t.Fatalf("expected routed subnet as extra allowed ip, got %q", bob.ExtraAllowedIPsStr)
+	}
+}
+
+func TestImportWgQuickInterface_ConflictsBlockCommit(t *testing.T) {
+	cfg := &config.Config{}
+
+	keys := newWgQuickTestKeys(t)
+	db := &mockDB{existingInterfaces: []domain.Interface{{Identifier: "wg7", ListenPort: 51820}}}
+	m := Manager{cfg: cfg, db: db, bus: &mockBus{}}
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{IsAdmin: true})
+
+	plan, err := m.ImportWgQuickInterface(ctx, newWgQuickTestRequest(keys), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Committed {
+		t.Fatal("expected import not to be committed")
+	}
+	if len(plan.Conflicts) != 2 {
+		t.Fatalf("expected identifier and port conflicts, got %v", plan.Conflicts)
+	}
+	if len(plan.Peers) != 2 {
+		t.Fatalf("expected two planned peers, got %d", len(plan.Peers))
+	}
+	if db.iface != nil || len(db.savedPeers) != 0 {
+		t.Fatal("expected nothing to be saved")
+	}
+}
+
+func TestImportWgQuickInterface_RollbackOnFailure(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Backend.Default = config.LocalBackendName
+
+	keys := newWgQuickTestKeys(t)
+	db := &wgQuickMockDB{
+		mockDB:   &mockDB{},
+		failPeer: domain.PeerIdentifier(keys.bob.PublicKey().String()),
+	}
+	m := Manager{cfg: cfg, db: db, bus: &mockBus{}}
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{Id: "admin", IsAdmin: true})
+
+	_, err := m.ImportWgQuickInterface(ctx, newWgQuickTestRequest(keys), false)
+	if err == nil {
+		t.Fatal("expected import to fail")
+	}
+	if !strings.Contains(err.Error(), "rolled back") || !strings.Contains(err.Error(), "after 1 of 2 peers") {
+		t.Fatalf("expected error to describe the rollback, got %v", err)
+	}
+	if db.iface != nil {
+		t.Fatal("expected interface to be removed")
+	}
+	if len(db.savedPeers) != 0 {
+		t.Fatalf("expected saved peers to be removed, got %d", len(db.savedPeers))
+	}
+}
+
+func TestImportWgQuickInterface_ConflictWithHostInterface(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Core.WireGuardHostManagement = true
+	cfg.Backend.Default = config.LocalBackendName
+
+	ctrlMgr := &ControllerManager{
+		controllers: map[domain.InterfaceBackend]backendInstance{
+			config.LocalBackendName: {Implementation: &wgQuickMockController{
+				interfaces: []domain.PhysicalInterface{{Identifier: "wg7"}},
+			}},
+		},
+	}
+
+	db := &wgQuickMockDB{mockDB: &mockDB{}}
+	m := Manager{cfg: cfg, db: db, bus: &mockBus{}, wg: ctrlMgr}
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{IsAdmin: true})
+
+	plan, err := m.ImportWgQuickInterface(ctx, newWgQuickTestRequest(newWgQuickTestKeys(t)), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Committed || len(plan.Conflicts) != 1 {
+		t.Fatalf("expected a single conflict for the existing host interface, got %v", plan.Conflicts)
+	}
+	if db.iface != nil {
+		t.Fatal("expected nothing to be saved")
+	}
+}
+
+func TestImportWgQuickInterface_RequiresAdmin(t *testing.T) {
+	m := Manager{cfg: &config.Config{}, db: &mockDB{}, bus: &mockBus{}}
+	ctx := domain.SetUserInfo(context.Background(), &domain.ContextUserInfo{IsAdmin: false})
+
+	_, err := m.ImportWgQuickInterface(ctx, newWgQuickTestRequest(newWgQuickTestKeys(t)), true)
+	if err == nil {
+		t.Fatal("expected permission error")
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+const (
+	AuditSinkSyslog = "syslog"
+	AuditSinkFile   = "file"
+)
+
+// auditSyslogFacilities are the RFC 5424 facility codes by name.
+var auditSyslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7, "uucp": 8,
+	"cron": 9, "authpriv": 10, "ftp": 11, "local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20,
+	"local5": 21, "local6": 22, "local7": 23,
+}
+
+// AuditSink forwards all audit entries to an external system, in addition to the database.
+type AuditSink struct {
+	// Name is the unique name of the sink, it is shown in log messages.
+	Name string `yaml:"name"`
+	// Type is either syslog or file.
+	Type string `yaml:"type"`
+	// BufferSize is the number of entries that are queued for the sink, further entries are dropped while the
+	// queue is full. 1000 if zero.
+	BufferSize int `yaml:"buffer_size"`
+
+	// Network is the syslog transport: udp, tcp or tls.
+	Network string `yaml:"network"`
+	// Address is the host and port of the syslog server, for example siem.example.com:6514.
+	Address string `yaml:"address"`
+	// Facility is the syslog facility of the messages, auth if empty.
+	Facility string `yaml:"facility"`
+	// AppName is the syslog application name, wg-portal if empty.
+	AppName string `yaml:"app_name"`
+	// TlsCaFile is the path to a PEM encoded CA certificate that verifies the syslog server. The system
+	// certificates are used if it is empty.
+	TlsCaFile string `yaml:"tls_ca_file"`
+	// TlsSkipVerify disables the verification of the syslog server certificate.
+	TlsSkipVerify bool `yaml:"tls_skip_verify"`
+
+	// Path is the path of the JSON lines file.
+	Path string `yaml:"path"`
+	// MaxSize is the size in megabytes after which the file is rotated. 0 disables the rotation.
+	MaxSize int `yaml:"max_size"`
+	// MaxBackups is the number of rotated files that are kept, at least one.
+	MaxBackups int `yaml:"max_backups"`
+}
+
+// SyslogFacility returns the numeric syslog facility of the sink.
+func (s AuditSink) SyslogFacility() int {
+	if facility, ok := auditSyslogFacilities[s.Facility]; ok {
+		return facility
+	}
+	return auditSyslogFacilities["auth"]
+}
@@ -187,6 +187,8 @@ type LdapProvider struct {
 	BindUser string `yaml:"bind_user"`
 	// BindPass is the bind password for LDAP
 	BindPass string `yaml:"bind_pass"`
+	// BindPassFile is a file containing the bind password, it overrides BindPass
+	BindPassFile string `yaml:"bind_pass_file"`
 
 	// FieldMap is used to map the names of the LDAP fields to wg-portal fields
 	FieldMap LdapFields `yaml:"field_map"`
@@ -248,6 +250,9 @@ type OpenIDConnectProvider struct {
 	// ClientSecret is the application's secret.
 	ClientSecret string `yaml:"client_secret"`
 
+	// ClientSecretFile is a file containing the application's secret, it overrides ClientSecret.
+	ClientSecretFile string `yaml:"client_secret_file"`
+
 	// ExtraScopes specifies optional requested permissions.
 	ExtraScopes []string `yaml:"extra_scopes"`
 
@@ -270,6 +275,60 @@ type OpenIDConnectProvider struct {
 	// If LogSensitiveInfo is set to true, sensitive information retrieved from the OIDC provider will be logged in trace level.
 	// This also includes OAuth tokens! Keep this disabled in production!
 	LogSensitiveInfo bool `yaml:"log_sensitive_info"`
+
+	// ApiAccess configures the REST API access with JWT bearer tokens issued by the provider.
+	ApiAccess OidcApiAccess `yaml:"api_access"`
+}
+
+// OidcApiAccess contains the settings for JWT bearer tokens of an OpenID Connect provider, for example access tokens
+// of the client credentials flow. The signing keys are discovered from the provider's JWKS endpoint.
+type OidcApiAccess struct {
+	// Enabled allows JWT bearer tokens of the provider for the REST API.
+	Enabled bool `yaml:"enabled"`
+
+	// Audience is the expected audience (aud claim) of the tokens. If it is empty, the client_id is expected.
+	Audience string `yaml:"audience"`
+
+	// UserClaim is the claim that contains the wg-portal user identifier. Defaults to "sub".
+	UserClaim string `yaml:"user_claim"`
+
+	// ScopeClaim is the claim that contains the token scopes, either as space separated string or as list.
+	// Defaults to "scope".
+	ScopeClaim string `yaml:"scope_claim"`
+
+	// ScopeMapping maps provider scopes to wg-portal API token scopes (full, read-only, peers:write, provisioning).
+	// Provider scopes that are not mapped are ignored. If it is empty, the provider scopes must be wg-portal scopes.
+	ScopeMapping map[string]string `yaml:"scope_mapping"`
+
+	// ServiceIdentities are identities that are not backed by a wg-portal user, for example other services that
+	// authenticate with their client credentials. Tokens of other subjects must belong to an existing user.
+	ServiceIdentities []OidcServiceIdentity `yaml:"service_identities"`
+}
+
+// OidcServiceIdentity maps the user claim of JWT bearer tokens to a service identity.
+type OidcServiceIdentity struct {
+	// Subject is the value of the user claim, for example the client id of the service.
+	Subject string `yaml:"subject"`
+	// Name is the identity name that is used as user identifier, for example in the audit log.
+	Name string `yaml:"name"`
+	// Admin grants admin permissions to the service identity.
+	Admin bool `yaml:"admin"`
+}
+
+// GetUserClaim returns the configured user claim or its default value.
+func (a OidcApiAccess) GetUserClaim() string {
+	if a.UserClaim == "" {
+		return "sub"
+	}
+	return a.UserClaim
+}
+
+// GetScopeClaim returns the configured scope claim or its default value.
+func (a OidcApiAccess) GetScopeClaim() string {
+	if a.ScopeClaim == "" {
+		return "scope"
+	}
+	return a.ScopeClaim
 }
 
 // OAuthProvider contains the configuration for the OAuth provider.
@@ -286,6 +345,9 @@ type OAuthProvider struct {
 	// ClientSecret is the application's secret.
 	ClientSecret string `yaml:"client_secret"`
 
+	// ClientSecretFile is a file containing the application's secret, it overrides ClientSecret.
+	ClientSecretFile string `yaml:"client_secret_file"`
+
 	// AuthURL is the URL to request OAuth user authorization.
 	AuthURL string `yaml:"auth_url"`
 	// TokenURL is the URL to request a token.
@@ -75,11 +75,12 @@ func (b BackendBase) GetDisplayName() string {
 type BackendMikrotik struct {
 	BackendBase `yaml:",inline"` // Embed the base fields
 
-	ApiUrl       string        `yaml:"api_url"` // The base URL of the Mikrotik API (e.g., "https://10.10.10.10:8729/rest")
-	ApiUser      string        `yaml:"api_user"`
-	ApiPassword  string        `yaml:"api_password"`
-	ApiVerifyTls bool          `yaml:"api_verify_tls"` // Whether to verify the TLS certificate of the Mikrotik API
-	ApiTimeout   time.Duration `yaml:"api_timeout"`    // Timeout for API requests (default: 30 seconds)
+	ApiUrl          string        `yaml:"api_url"` // The base URL of the Mikrotik API (e.g., "https://10.10.10.10:8729/rest")
+	ApiUser         string        `yaml:"api_user"`
+	ApiPassword     string        `yaml:"api_password"`
+	ApiPasswordFile string        `yaml:"api_password_file"` // A file containing the API password, it overrides ApiPassword
+	ApiVerifyTls    bool          `yaml:"api_verify_tls"`    // Whether to verify the TLS certificate of the Mikrotik API
+	ApiTimeout      time.Duration `yaml:"api_timeout"`       // Timeout for API requests (default: 30 seconds)
 
 	// Concurrency controls the maximum number of concurrent API requests that this backend will issue
 	// when enumerating interfaces and their details. If 0 or negative, a default of 5 is used.
@@ -115,8 +116,9 @@ func (b *BackendMikrotik) GetApiTimeout() time.Duration {
 type BackendPfsense struct {
 	BackendBase `yaml:",inline"` // Embed the base fields
 
-	ApiUrl       string        `yaml:"api_url"` // The base URL of the pfSense REST API (e.g., "https://pfsense.example.com/api/v2")
-	ApiKey       string        `yaml:"api_key"` // API key for authentication (generated in pfSense under 'System' -> 'REST API' -> 'Keys')
+	ApiUrl       string        `yaml:"api_url"`        // The base URL of the pfSense REST API (e.g., "https://pfsense.example.com/api/v2")
+	ApiKey       string        `yaml:"api_key"`        // API key for authentication (generated in pfSense under 'System' -> 'REST API' -> 'Keys')
+	ApiKeyFile   string        `yaml:"api_key_file"`   // A file containing the API key, it overrides ApiKey
 	ApiVerifyTls bool          `yaml:"api_verify_tls"` // Whether to verify the TLS certificate of the pfSense API
 	ApiTimeout   time.Duration `yaml:"api_timeout"`    // Timeout for API requests (default: 30 seconds)
 
@@ -1,18 +1,15 @@
 package config
 
 import (
-	"bytes"
 	"fmt"
-	"io"
 	"log/slog"
+	"maps"
 	"net/netip"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/a8m/envsubst"
-	"gopkg.in/yaml.v3"
 )
 
 // maxAwgStringLen matches wgctrl ioctl buffer limit for special junk packets
@@ -25,7 +22,9 @@ type Config struct {
 		AdminUserDisabled       bool   `yaml:"disable_admin_user"`
 		AdminUser               string `yaml:"admin_user"`
 		AdminPassword           string `yaml:"admin_password"`
-		AdminApiToken           string `yaml:"admin_api_token"` // if set, the API access is enabled automatically
+		AdminPasswordFile       string `yaml:"admin_password_file"`  // if set, the admin password is read from this file
+		AdminApiToken           string `yaml:"admin_api_token"`      // if set, the API access is enabled automatically
+		AdminApiTokenFile       string `yaml:"admin_api_token_file"` // if set, the admin API token is read from this file
 		WireGuardHostManagement bool   `yaml:"wireguard_host_management"`
 
 		EditableKeys                bool `yaml:"editable_keys"`
@@ -36,6 +35,10 @@ type Config struct {
 		DeletePeerAfterUserDeleted  bool `yaml:"delete_peer_after_user_deleted"`
 		ImportExisting              bool `yaml:"import_existing"`
 		RestoreState                bool `yaml:"restore_state"`
+
+		// PeerExpiryReminders are the periods before the expiry date of a peer in which its user is reminded,
+		// for example 336h, 72h and 24h. Each reminder is sent once.
+		PeerExpiryReminders []time.Duration `yaml:"peer_expiry_reminders"`
 	} `yaml:"core"`
 
 	Advanced struct {
@@ -47,6 +50,7 @@ type Config struct {
 		StartCidrV6         string        `yaml:"start_cidr_v6"`
 		UseIpV6             bool          `yaml:"use_ip_v6"`
 		ConfigStoragePath   string        `yaml:"config_storage_path"` // keep empty to disable config export to file
+		ImportConfigPath    string        `yaml:"import_config_path"`  // keep empty to disable config import from local files
 		ExpiryCheckInterval time.Duration `yaml:"expiry_check_interval"`
 		RulePrioOffset      int           `yaml:"rule_prio_offset"`
 		RouteTableOffset    int           `yaml:"route_table_offset"`
@@ -64,7 +68,17 @@ type Config struct {
 		CollectInterfaceData   bool          `yaml:"collect_interface_data"`
 		CollectPeerData        bool          `yaml:"collect_peer_data"`
 		CollectAuditData       bool          `yaml:"collect_audit_data"`
+		AuditRetention         time.Duration `yaml:"audit_retention"`   // 0 keeps audit entries forever
+		AuditMaxEntries        int           `yaml:"audit_max_entries"` // 0 does not limit the number of entries
 		ListeningAddress       string        `yaml:"listening_address"`
+
+		// AuditHashChain links each audit entry to the previous one with a hash, so that changes can be detected.
+		AuditHashChain bool `yaml:"audit_hash_chain"`
+		// AuditSigningKey is the path of a PEM encoded Ed25519 private key, used to sign audit checkpoints.
+		AuditSigningKey         string        `yaml:"audit_signing_key"`
+		AuditCheckpointInterval time.Duration `yaml:"audit_checkpoint_interval"`
+		// AuditSinks forward all audit entries to syslog servers or files.
+		AuditSinks []AuditSink `yaml:"audit_sinks"`
 	} `yaml:"statistics"`
 
 	Mail MailConfig `yaml:"mail"`
@@ -77,11 +91,56 @@ type Config struct {
 
 	Webhook WebhookConfig `yaml:"webhook"`
 
+	// Exporters publish events to MQTT or NATS brokers.
+	Exporters []EventExporter `yaml:"exporters"`
+
 	Provisioning ProvisioningConfig `yaml:"provisioning"`
+
+	files   []string          // the loaded config files, in the order they have been merged
+	sources map[string]string // the file each setting has been read from
 }
 
 type ProvisioningConfig struct {
 	Interfaces []ProvisioningInterface `yaml:"interfaces"`
+	Users      []ProvisioningUser      `yaml:"users"`
+	Peers      []ProvisioningPeer      `yaml:"peers"`
+
+	// Prune deletes users and peers that have been created by the provisioning but are no longer configured.
+	Prune bool `yaml:"prune"`
+}
+
+// ProvisioningUser is a user that is created or updated on startup and on configuration reloads.
+type ProvisioningUser struct {
+	Identifier string `yaml:"identifier"`
+	Source     string `yaml:"source"` // db, ldap or oauth, default: db
+
+	Email      string `yaml:"email"`
+	Firstname  string `yaml:"firstname"`
+	Lastname   string `yaml:"lastname"`
+	Phone      string `yaml:"phone"`
+	Department string `yaml:"department"`
+	Notes      string `yaml:"notes"`
+	IsAdmin    bool   `yaml:"is_admin"`
+	Disabled   bool   `yaml:"disabled"`
+
+	Password     string `yaml:"password"`      // only used to create database users, existing passwords are kept
+	PasswordFile string `yaml:"password_file"` // if set, the password is read from this file
+}
+
+// ProvisioningPeer is a peer that is created or updated on startup and on configuration reloads.
+// The peer is identified by its public key, which is derived from the private key if only the private key is set.
+type ProvisioningPeer struct {
+	Interface   string `yaml:"interface"`
+	User        string `yaml:"user"`
+	DisplayName string `yaml:"display_name"`
+
+	PublicKey  string `yaml:"public_key"`
+	PrivateKey string `yaml:"private_key"`
+
+	Addresses []string   `yaml:"addresses"` // if empty, free addresses of the interface are allocated
+	ExpiresAt *time.Time `yaml:"expires_at"`
+	Notes     string     `yaml:"notes"`
+	Disabled  bool       `yaml:"disabled"`
 }
 
 type ProvisioningInterface struct {
@@ -153,6 +212,12 @@ func (c *Config) Sanitize() error {
 	if err := sanitizeProvisioningInterfaces(c); err != nil {
 		return err
 	}
+	if err := sanitizeProvisioningUsers(c); err != nil {
+		return err
+	}
+	if err := sanitizeProvisioningPeers(c); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -234,6 +299,65 @@ func sanitizeProvisioningInterfaces(c *Config) error {
 	return nil
 }
 
+func sanitizeProvisioningUsers(c *Config) error {
+	seen := make(map[string]struct{}, len(c.Provisioning.Users))
+	for idx := range c.Provisioning.Users {
+		user := &c.Provisioning.Users[idx]
+
+		id := strings.TrimSpace(user.Identifier)
+		if id == "" {
+			return fmt.Errorf("provisioning.users[%d].identifier must not be empty", idx)
+		}
+		user.Identifier = id
+
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("provisioning.users.identifier %q is not unique", id)
+		}
+		seen[id] = struct{}{}
+
+		source := strings.ToLower(strings.TrimSpace(user.Source))
+		switch source {
+		case "":
+			user.Source = "db"
+		case "db", "ldap", "oauth":
+			user.Source = source
+		default:
+			return fmt.Errorf("provisioning.users[%s].source must be one of: db, ldap, oauth", id)
+		}
+
+		if user.Password != "" && user.Source != "db" {
+			return fmt.Errorf("provisioning.users[%s].password is only supported for the db source", id)
+		}
+	}
+
+	return nil
+}
+
+func sanitizeProvisioningPeers(c *Config) error {
+	for idx := range c.Provisioning.Peers {
+		peer := &c.Provisioning.Peers[idx]
+
+		peer.Interface = strings.TrimSpace(peer.Interface)
+		if peer.Interface == "" {
+			return fmt.Errorf("provisioning.peers[%d].interface must not be empty", idx)
+		}
+
+		peer.PublicKey = strings.TrimSpace(peer.PublicKey)
+		peer.PrivateKey = strings.TrimSpace(peer.PrivateKey)
+		if peer.PublicKey == "" && peer.PrivateKey == "" {
+			return fmt.Errorf("provisioning.peers[%d] requires a public_key or private_key", idx)
+		}
+
+		if len(peer.Addresses) > 0 {
+			if err := validateCidrArray(fmt.Sprintf("provisioning.peers[%d].addresses", idx), peer.Addresses); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func validateCidrArray(field string, cidrs []string) error {
 	for i, raw := range cidrs {
 		val := strings.TrimSpace(raw)
@@ -322,7 +446,13 @@ func validateAwgStringPtr(field string, raw **string) error {
 
 // LogStartupValues logs the startup values of the configuration in debug level
 func (c *Config) LogStartupValues() {
-	slog.Info("Configuration loaded!", "logLevel", c.Advanced.LogLevel)
+	slog.Info("Configuration loaded!", "logLevel", c.Advanced.LogLevel, "files", c.files)
+
+	// only the origin of the settings is logged, the values might contain secrets
+	settings := slices.Sorted(maps.Keys(c.sources))
+	for _, setting := range settings {
+		slog.Debug("Config Source", "setting", setting, "file", c.sources[setting])
+	}
 
 	slog.Debug("Config Features",
 		"wireguardHostManagement", c.Core.WireGuardHostManagement,
@@ -340,6 +470,7 @@ func (c *Config) LogStartupValues() {
 
 	slog.Debug("Config Settings",
 		"configStoragePath", c.Advanced.ConfigStoragePath,
+		"importConfigPath", c.Advanced.ImportConfigPath,
 		"externalUrl", c.Web.ExternalUrl,
 	)
 
@@ -405,6 +536,9 @@ func defaultConfig() *Config {
 		SiteCompanyName:   getEnvStr("WG_PORTAL_WEB_SITE_COMPANY_NAME", "WireGuard Portal"),
 		CertFile:          getEnvStr("WG_PORTAL_WEB_CERT_FILE", ""),
 		KeyFile:           getEnvStr("WG_PORTAL_WEB_KEY_FILE", ""),
+		TrustedProxies:    getEnvStrSlice("WG_PORTAL_WEB_TRUSTED_PROXIES", nil),
+
+		GrpcListeningAddress: getEnvStr("WG_PORTAL_WEB_GRPC_LISTENING_ADDRESS", ""),
 	}
 
 	cfg.Advanced.LogLevel = getEnvStr("WG_PORTAL_ADVANCED_LOG_LEVEL", "info")
@@ -415,6 +549,7 @@ func defaultConfig() *Config {
 	cfg.Advanced.StartCidrV6 = getEnvStr("WG_PORTAL_ADVANCED_START_CIDR_V6", "fdfd:d3ad:c0de:1234::0/64")
 	cfg.Advanced.UseIpV6 = getEnvBool("WG_PORTAL_ADVANCED_USE_IP_V6", true)
 	cfg.Advanced.ConfigStoragePath = getEnvStr("WG_PORTAL_ADVANCED_CONFIG_STORAGE_PATH", "")
+	cfg.Advanced.ImportConfigPath = getEnvStr("WG_PORTAL_ADVANCED_IMPORT_CONFIG_PATH", "")
 	cfg.Advanced.ExpiryCheckInterval = getEnvDuration("WG_PORTAL_ADVANCED_EXPIRY_CHECK_INTERVAL", 15*time.Minute)
 	cfg.Advanced.RulePrioOffset = getEnvInt("WG_PORTAL_ADVANCED_RULE_PRIO_OFFSET", 20000)
 	cfg.Advanced.RouteTableOffset = getEnvInt("WG_PORTAL_ADVANCED_ROUTE_TABLE_OFFSET", 20000)
@@ -429,6 +564,12 @@ func defaultConfig() *Config {
 	cfg.Statistics.CollectInterfaceData = getEnvBool("WG_PORTAL_STATISTICS_COLLECT_INTERFACE_DATA", true)
 	cfg.Statistics.CollectPeerData = getEnvBool("WG_PORTAL_STATISTICS_COLLECT_PEER_DATA", true)
 	cfg.Statistics.CollectAuditData = getEnvBool("WG_PORTAL_STATISTICS_COLLECT_AUDIT_DATA", true)
+	cfg.Statistics.AuditRetention = getEnvDuration("WG_PORTAL_STATISTICS_AUDIT_RETENTION", 0)
+	cfg.Statistics.AuditMaxEntries = getEnvInt("WG_PORTAL_STATISTICS_AUDIT_MAX_ENTRIES", 0)
+	cfg.Statistics.AuditHashChain = getEnvBool("WG_PORTAL_STATISTICS_AUDIT_HASH_CHAIN", false)
+	cfg.Statistics.AuditSigningKey = getEnvStr("WG_PORTAL_STATISTICS_AUDIT_SIGNING_KEY", "")
+	cfg.Statistics.AuditCheckpointInterval = getEnvDuration("WG_PORTAL_STATISTICS_AUDIT_CHECKPOINT_INTERVAL",
+		24*time.Hour)
 	cfg.Statistics.ListeningAddress = getEnvStr("WG_PORTAL_STATISTICS_LISTENING_ADDRESS", ":8787")
 
 	cfg.Mail = MailConfig{
@@ -447,6 +588,10 @@ func defaultConfig() *Config {
 	cfg.Webhook.Url = getEnvStr("WG_PORTAL_WEBHOOK_URL", "") // no webhook by default
 	cfg.Webhook.Authentication = getEnvStr("WG_PORTAL_WEBHOOK_AUTHENTICATION", "")
 	cfg.Webhook.Timeout = getEnvDuration("WG_PORTAL_WEBHOOK_TIMEOUT", 10*time.Second)
+	cfg.Webhook.Secret = getEnvStr("WG_PORTAL_WEBHOOK_SECRET", "")
+	cfg.Webhook.MaxAttempts = getEnvInt("WG_PORTAL_WEBHOOK_MAX_ATTEMPTS", 10)
+	cfg.Webhook.RetryInterval = getEnvDuration("WG_PORTAL_WEBHOOK_RETRY_INTERVAL", 30*time.Second)
+	cfg.Webhook.DeliveryRetention = getEnvDuration("WG_PORTAL_WEBHOOK_DELIVERY_RETENTION", 7*24*time.Hour)
 
 	cfg.Auth.WebAuthn.Enabled = getEnvBool("WG_PORTAL_AUTH_WEBAUTHN_ENABLED", true)
 	cfg.Auth.MinPasswordLength = getEnvInt("WG_PORTAL_AUTH_MIN_PASSWORD_LENGTH", 16)
@@ -455,9 +600,34 @@ func defaultConfig() *Config {
 	return cfg
 }
 
-// GetConfig returns the configuration from the config file.
+// GetConfig returns the configuration from the config file and the fragments of the config directory.
 // Environment variable substitution is supported.
 func GetConfig() (*Config, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Sanitize(); err != nil {
+		return nil, err
+	}
+	cfg.Web.Sanitize()
+	err = cfg.Backend.Validate()
+	if err != nil {
+		return nil, err
+	}
+	for i := range cfg.Auth.Ldap {
+		if err := cfg.Auth.Ldap[i].Sanitize(); err != nil {
+			return nil, fmt.Errorf("sanitizing of ldap config for %s failed: %w", cfg.Auth.Ldap[i].ProviderName, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig reads the config files and secret files like GetConfig, but does not validate the configuration.
+// Use Validate to get all problems of the loaded configuration.
+func LoadConfig() (*Config, error) {
 	cfg := defaultConfig()
 
 	// override config values from YAML file
@@ -474,22 +644,17 @@ func GetConfig() (*Config, error) {
 		cfgFileName = cfgFileNameFallback
 	}
 
-	if err := loadConfigFile(cfg, cfgFileName); err != nil {
-		return nil, fmt.Errorf("failed to load config from yaml: %w", err)
-	}
-
-	if err := cfg.Sanitize(); err != nil {
+	files, err := getConfigFiles(cfgFileName)
+	if err != nil {
 		return nil, err
 	}
-	cfg.Web.Sanitize()
-	err := cfg.Backend.Validate()
+	cfg.sources, err = loadConfigFiles(cfg, files...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to load config from yaml: %w", err)
 	}
-	for i := range cfg.Auth.Ldap {
-		if err := cfg.Auth.Ldap[i].Sanitize(); err != nil {
-			return nil, fmt.Errorf("sanitizing of ldap config for %s failed: %w", cfg.Auth.Ldap[i].ProviderName, err)
-		}
+	cfg.files = files
+	if err := cfg.loadSecretFiles(); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
@@ -497,30 +662,8 @@ func GetConfig() (*Config, error) {
 
 // loadConfigFile loads the configuration from a YAML file into the given cfg struct.
 func loadConfigFile(cfg any, filename string) error {
-	data, err := envsubst.ReadFile(filename)
-	if err != nil {
-		if os.IsNotExist(err) {
-			slog.Warn("Config file not found, using default values", "filename", filename)
-			return nil
-		}
-		return fmt.Errorf("envsubst error: %v", err)
-	}
-
-	dec := yaml.NewDecoder(bytes.NewReader(data))
-	dec.KnownFields(true)
-	if err := dec.Decode(cfg); err != nil {
-		return fmt.Errorf("yaml error: %v", err)
-	}
-	// Ensure there are no trailing YAML documents.
-	var extra any
-	if err := dec.Decode(&extra); err != io.EOF {
-		if err == nil {
-			return fmt.Errorf("yaml error: unexpected extra document")
-		}
-		return fmt.Errorf("yaml error: %v", err)
-	}
-
-	return nil
+	_, err := loadConfigFiles(cfg, filename)
+	return err
 }
 
 func getEnvStr(name, fallback string) string {
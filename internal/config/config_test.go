@@ -164,3 +164,177 @@ extra: document
 		t.Fatalf("expected extra document error, got: %v", err)
 	}
 }
+
+func TestChangedSettings(t *testing.T) {
+	current := defaultConfig()
+	updated := defaultConfig()
+
+	if changed := ChangedSettings(current, updated); len(changed) != 0 {
+		t.Fatalf("expected no changes, got: %v", changed)
+	}
+
+	updated.Advanced.LogLevel = "debug"
+	updated.Auth.Ldap = []LdapProvider{{ProviderName: "ldap"}}
+	updated.Mail.Host = "smtp.example.com"
+	updated.Backend.Mikrotik = []BackendMikrotik{{BackendBase: BackendBase{Id: "mikrotik"}}}
+
+	got := strings.Join(ChangedSettings(current, updated), ",")
+	want := "advanced.log_level,backend.mikrotik,mail.host,auth.ldap"
+	if got != want {
+		t.Fatalf("expected %s, got: %s", want, got)
+	}
+}
+
+func TestLoadConfigFiles_MergesFragments(t *testing.T) {
+	cfg := defaultConfig()
+
+	mainFile := writeTempConfig(t, `
+core:
+  admin_user: admin@example.com
+auth:
+  ldap:
+    - provider_name: main
+web:
+  external_url: https://vpn.example.com
+  site_title: Main
+`)
+	fragmentDir := filepath.Join(filepath.Dir(mainFile), configFragmentDir)
+	if err := os.Mkdir(fragmentDir, 0o700); err != nil {
+		t.Fatalf("create fragment dir: %v", err)
+	}
+	fragments := map[string]string{
+		"20-web.yaml":  "web:\n  site_title: Fragment\n",
+		"10-auth.yml":  "auth:\n  ldap:\n    - provider_name: fragment\n",
+		"ignored.conf": "web:\n  site_title: Ignored\n",
+	}
+	for name, contents := range fragments {
+		if err := os.WriteFile(filepath.Join(fragmentDir, name), []byte(contents), 0o600); err != nil {
+			t.Fatalf("write fragment: %v", err)
+		}
+	}
+
+	files, err := getConfigFiles(mainFile)
+	if err != nil {
+		t.Fatalf("getConfigFiles: %v", err)
+	}
+	expectedFiles := []string{mainFile, filepath.Join(fragmentDir, "10-auth.yml"),
+		filepath.Join(fragmentDir, "20-web.yaml")}
+	if strings.Join(files, ",") != strings.Join(expectedFiles, ",") {
+		t.Fatalf("unexpected files: %v", files)
+	}
+
+	sources, err := loadConfigFiles(cfg, files...)
+	if err != nil {
+		t.Fatalf("loadConfigFiles: %v", err)
+	}
+	if cfg.Core.AdminUser != "admin@example.com" || cfg.Web.ExternalUrl != "https://vpn.example.com" {
+		t.Fatalf("values of the main file are missing: %s, %s", cfg.Core.AdminUser, cfg.Web.ExternalUrl)
+	}
+	if cfg.Web.SiteTitle != "Fragment" {
+		t.Fatalf("expected site title of fragment, got: %s", cfg.Web.SiteTitle)
+	}
+	if len(cfg.Auth.Ldap) != 1 || cfg.Auth.Ldap[0].ProviderName != "fragment" {
+		t.Fatalf("expected lists to be replaced, got: %+v", cfg.Auth.Ldap)
+	}
+	if !cfg.Auth.WebAuthn.Enabled {
+		t.Fatalf("default values must be kept")
+	}
+
+	expectedSources := map[string]string{
+		"core.admin_user":  mainFile,
+		"auth.ldap":        filepath.Join(fragmentDir, "10-auth.yml"),
+		"web.external_url": mainFile,
+		"web.site_title":   filepath.Join(fragmentDir, "20-web.yaml"),
+	}
+	for setting, file := range expectedSources {
+		if sources[setting] != file {
+			t.Fatalf("expected %s to be read from %s, got: %s", setting, file, sources[setting])
+		}
+	}
+}
+
+func TestLoadConfigFiles_FragmentErrorNamesFile(t *testing.T) {
+	cfg := defaultConfig()
+
+	mainFile := writeTempConfig(t, "core: {}\n")
+	fragment := filepath.Join(filepath.Dir(mainFile), "fragment.yaml")
+	if err := os.WriteFile(fragment, []byte("web:\n  unknown_key: 1\n"), 0o600); err != nil {
+		t.Fatalf("write fragment: %v", err)
+	}
+
+	_, err := loadConfigFiles(cfg, mainFile, fragment)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), fragment) || !strings.Contains(err.Error(), "unknown_key") {
+		t.Fatalf("expected error mentioning the fragment and unknown_key, got: %v", err)
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	cfg := defaultConfig()
+
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "bind_pass")
+	if err := os.WriteFile(secretFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatalf("write secret: %v", err)
+	}
+	cfg.Auth.Ldap = []LdapProvider{{ProviderName: "ldap", BindPass: "inline", BindPassFile: secretFile}}
+
+	if err := cfg.loadSecretFiles(); err != nil {
+		t.Fatalf("loadSecretFiles: %v", err)
+	}
+	if cfg.Auth.Ldap[0].BindPass != "s3cret" {
+		t.Fatalf("expected secret from file, got: %q", cfg.Auth.Ldap[0].BindPass)
+	}
+	if cfg.sources["auth.ldap[0].bind_pass"] != secretFile {
+		t.Fatalf("expected source of secret to be recorded, got: %v", cfg.sources)
+	}
+
+	cfg.Web.SessionSecretFile = secretFile
+	cfg.Webhook.Subscriptions = []WebhookSubscription{{Name: "chat", SecretFile: secretFile}}
+	if err := cfg.loadSecretFiles(); err != nil {
+		t.Fatalf("loadSecretFiles: %v", err)
+	}
+	if cfg.Web.SessionSecret != "s3cret" || cfg.Webhook.Subscriptions[0].Secret != "s3cret" {
+		t.Fatalf("expected secrets from file, got: %q, %q", cfg.Web.SessionSecret, cfg.Webhook.Subscriptions[0].Secret)
+	}
+
+	cfg.Mail.PasswordFile = filepath.Join(dir, "missing")
+	err := cfg.loadSecretFiles()
+	if err == nil || !strings.Contains(err.Error(), "mail.password_file") {
+		t.Fatalf("expected error mentioning mail.password_file, got: %v", err)
+	}
+}
+
+func TestSanitizeProvisioningUsersAndPeers(t *testing.T) {
+	cfg := defaultConfig()
+
+	path := writeTempConfig(t, `
+provisioning:
+  users:
+    - identifier: " alice "
+  peers:
+    - interface: wg0
+      public_key: xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=
+      expires_at: 2030-01-01T00:00:00Z
+`)
+
+	if err := loadConfigFile(cfg, path); err != nil {
+		t.Fatalf("loadConfigFile: %v", err)
+	}
+	if err := cfg.Sanitize(); err != nil {
+		t.Fatalf("Sanitize: %v", err)
+	}
+	if cfg.Provisioning.Users[0].Identifier != "alice" || cfg.Provisioning.Users[0].Source != "db" {
+		t.Fatalf("unexpected user: %+v", cfg.Provisioning.Users[0])
+	}
+	if cfg.Provisioning.Peers[0].ExpiresAt == nil || cfg.Provisioning.Peers[0].ExpiresAt.Year() != 2030 {
+		t.Fatalf("unexpected expiry: %v", cfg.Provisioning.Peers[0].ExpiresAt)
+	}
+
+	cfg.Provisioning.Peers[0].PublicKey = ""
+	if err := cfg.Sanitize(); err == nil || !strings.Contains(err.Error(), "public_key or private_key") {
+		t.Fatalf("expected missing key error, got: %v", err)
+	}
+}
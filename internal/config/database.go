@@ -28,4 +28,6 @@ type DatabaseConfig struct {
 	// EncryptionPassphrase is the passphrase used to encrypt sensitive data (WireGuard keys) in the database.
 	// If no passphrase is provided, no encryption will be used.
 	EncryptionPassphrase string `yaml:"encryption_passphrase"`
+	// EncryptionPassphraseFile is a file containing the encryption passphrase, it overrides EncryptionPassphrase.
+	EncryptionPassphraseFile string `yaml:"encryption_passphrase_file"`
 }
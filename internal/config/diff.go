@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ChangedSettings returns the paths of all settings that differ between the two configurations, for example
+// "auth.ldap" or "advanced.log_level". The paths are built from the YAML keys, lists are compared as a whole.
+func ChangedSettings(current, updated *Config) []string {
+	return changedFields("", reflect.ValueOf(*current), reflect.ValueOf(*updated))
+}
+
+func changedFields(prefix string, current, updated reflect.Value) []string {
+	var changed []string
+
+	configPkg := reflect.TypeOf(Config{}).PkgPath()
+	for i := range current.NumField() {
+		field := current.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		path := prefix
+		switch {
+		case name == "-":
+			continue
+		case options == "inline":
+			// inlined fields use the path of the parent
+		case name == "":
+			path = joinSettingPath(prefix, strings.ToLower(field.Name))
+		default:
+			path = joinSettingPath(prefix, name)
+		}
+
+		// descend into the configuration sections, other values are compared as a whole
+		isSection := field.Type.PkgPath() == "" || field.Type.PkgPath() == configPkg
+		if field.Type.Kind() == reflect.Struct && isSection {
+			changed = append(changed, changedFields(path, current.Field(i), updated.Field(i))...)
+			continue
+		}
+
+		if !reflect.DeepEqual(current.Field(i).Interface(), updated.Field(i).Interface()) {
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
+
+func joinSettingPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
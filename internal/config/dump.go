@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces the value of secret settings in the output of Dump.
+const redactedValue = "<redacted>"
+
+// secretSettingKeys are the YAML keys whose values are redacted by Dump.
+var secretSettingKeys = map[string]struct{}{
+	"admin_password":        {},
+	"admin_api_token":       {},
+	"api_key":               {},
+	"api_password":          {},
+	"authentication":        {},
+	"bind_pass":             {},
+	"client_secret":         {},
+	"csrf_secret":           {},
+	"encryption_passphrase": {},
+	"password":              {},
+	"private_key":           {},
+	"secret":                {},
+	"session_secret":        {},
+}
+
+// Dump writes the effective configuration as YAML to w. Secrets are redacted, so the output can be shared, for
+// example in support tickets. Settings that have been taken from a config file are annotated with the file name.
+func (c *Config) Dump(w io.Writer) error {
+	var root yaml.Node
+	if err := root.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	redactConfigNode(&root, "", c.Database.Type != DatabaseSQLite, c.sources)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return enc.Close()
+}
+
+// redactConfigNode redacts all secret values below the given node and adds the source file of settings as comment.
+// The DSN is only redacted if it may contain credentials, a SQLite DSN is just a file name.
+func redactConfigNode(node *yaml.Node, path string, redactDsn bool, sources map[string]string) {
+	if source, ok := sources[path]; ok && path != "" {
+		node.LineComment = "from " + source
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			_, secret := secretSettingKeys[key.Value]
+			if key.Value == "dsn" {
+				secret = redactDsn
+			}
+			if secret && value.Kind == yaml.ScalarNode && value.Value != "" {
+				value.Value = redactedValue
+				value.Tag = "!!str"
+				value.Style = 0
+			}
+			redactConfigNode(value, joinSettingPath(path, key.Value), redactDsn, sources)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			redactConfigNode(item, fmt.Sprintf("%s[%d]", path, i), redactDsn, sources)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+const (
+	EventExporterMqtt = "mqtt"
+	EventExporterNats = "nats"
+)
+
+// EventExporter publishes the events of WireGuard Portal as JSON messages to a message broker.
+type EventExporter struct {
+	// Name is the unique name of the exporter, it is shown in log messages.
+	Name string `yaml:"name"`
+	// Type is either mqtt or nats.
+	Type string `yaml:"type"`
+	// Url is the URL of the broker, for example tcp://broker:1883 or ssl://broker:8883 for MQTT and
+	// nats://nats:4222 for NATS.
+	Url string `yaml:"url"`
+	// Username is the optional username for the broker.
+	Username string `yaml:"username"`
+	// Password is the optional password for the broker.
+	Password string `yaml:"password"`
+	// PasswordFile is a file containing the password, it overrides Password.
+	PasswordFile string `yaml:"password_file"`
+	// CredentialsFile is a NATS credentials file with a user JWT and NKey seed.
+	CredentialsFile string `yaml:"credentials_file"`
+	// TlsCaFile is the path to a PEM encoded CA certificate that verifies the broker. The system certificates are
+	// used if it is empty.
+	TlsCaFile string `yaml:"tls_ca_file"`
+	// TlsSkipVerify disables the verification of the broker certificate.
+	TlsSkipVerify bool `yaml:"tls_skip_verify"`
+
+	// Prefix is the topic prefix of MQTT messages (wg-portal if empty) or the subject prefix of NATS messages
+	// (wgportal if empty).
+	Prefix string `yaml:"prefix"`
+	// ClientId is the MQTT client identifier, wg-portal if empty.
+	ClientId string `yaml:"client_id"`
+	// Qos is the MQTT quality of service level: 0, 1 or 2.
+	Qos int `yaml:"qos"`
+	// Events restricts the exporter to the given event types or type prefixes like peer:, empty exports all events.
+	Events []string `yaml:"events"`
+	// BufferSize is the number of events that are queued for the exporter, further events are dropped while the
+	// queue is full. 1000 if zero.
+	BufferSize int `yaml:"buffer_size"`
+}
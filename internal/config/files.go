@@ -0,0 +1,254 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/a8m/envsubst"
+	"gopkg.in/yaml.v3"
+)
+
+// configFragmentDir is the directory next to the main config file that contains additional config fragments.
+const configFragmentDir = "config.d"
+
+// getConfigFiles returns the main config file followed by all fragments of the config directory in lexical order.
+// The directory defaults to config.d next to the main config file, it can be changed with WG_PORTAL_CONFIG_DIR.
+func getConfigFiles(mainFile string) ([]string, error) {
+	dir := filepath.Join(filepath.Dir(mainFile), configFragmentDir)
+	if envDir := os.Getenv("WG_PORTAL_CONFIG_DIR"); envDir != "" {
+		dir = envDir
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	files := []string{mainFile}
+	for _, entry := range entries { // entries are sorted by file name
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+
+	return files, nil
+}
+
+// loadConfigFiles deep-merges the given YAML files in order and decodes the result into cfg.
+// Mappings are merged, all other values (including lists) of later files replace the values of earlier files.
+// Missing files are skipped. The returned map contains the file each setting has been taken from.
+func loadConfigFiles(cfg any, filenames ...string) (map[string]string, error) {
+	sources := make(map[string]string)
+
+	var merged *yaml.Node
+	for _, filename := range filenames {
+		doc, err := readConfigDocument(reflect.TypeOf(cfg).Elem(), filename)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+
+		if merged == nil {
+			merged = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		mergeConfigNode(merged, doc, "", filename, sources)
+	}
+
+	if merged == nil {
+		return sources, nil
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("yaml error: %v", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("yaml error: %v", err)
+	}
+
+	return sources, nil
+}
+
+// readConfigDocument reads a single YAML file and validates it against the given config type, so that errors refer to
+// the lines of the file. It returns nil if the file does not exist or is empty.
+func readConfigDocument(cfgType reflect.Type, filename string) (*yaml.Node, error) {
+	data, err := envsubst.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			slog.Warn("Config file not found, using default values", "filename", filename)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("envsubst error: %v", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(reflect.New(cfgType).Interface()); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil // empty file
+		}
+		return nil, fmt.Errorf("yaml error in %s: %v", filename, err)
+	}
+	// Ensure there are no trailing YAML documents.
+	var extra any
+	if err := dec.Decode(&extra); err != io.EOF {
+		if err == nil {
+			return nil, fmt.Errorf("yaml error in %s: unexpected extra document", filename)
+		}
+		return nil, fmt.Errorf("yaml error in %s: %v", filename, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("yaml error in %s: %v", filename, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil // only comments or null
+	}
+
+	return doc.Content[0], nil
+}
+
+// mergeConfigNode merges the mapping src into the mapping dst and records the source file of all merged values.
+func mergeConfigNode(dst, src *yaml.Node, prefix, filename string, sources map[string]string) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		path := joinSettingPath(prefix, key.Value)
+
+		idx := mappingKeyIndex(dst, key.Value)
+		switch {
+		case idx >= 0 && value.Kind == yaml.MappingNode && dst.Content[idx+1].Kind == yaml.MappingNode:
+			mergeConfigNode(dst.Content[idx+1], value, path, filename, sources)
+		case value.Kind == yaml.MappingNode:
+			if idx >= 0 {
+				dst.Content[idx+1] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			} else {
+				dst.Content = append(dst.Content, key, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+				idx = len(dst.Content) - 2
+			}
+			mergeConfigNode(dst.Content[idx+1], value, path, filename, sources)
+		default:
+			// lists and scalars replace the previous value
+			if idx >= 0 {
+				dst.Content[idx+1] = value
+			} else {
+				dst.Content = append(dst.Content, key, value)
+			}
+			for setting := range sources {
+				if strings.HasPrefix(setting, path+".") {
+					delete(sources, setting)
+				}
+			}
+			sources[path] = filename
+		}
+	}
+}
+
+// mappingKeyIndex returns the index of the given key in the content of a mapping node, or -1 if it does not exist.
+func mappingKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// secretFile is a setting that can be read from a file.
+type secretFile struct {
+	setting string
+	file    string
+	value   *string
+}
+
+// secretFiles returns all secret settings that support a *_file variant.
+func (c *Config) secretFiles() []secretFile {
+	secrets := []secretFile{
+		{"core.admin_password", c.Core.AdminPasswordFile, &c.Core.AdminPassword},
+		{"core.admin_api_token", c.Core.AdminApiTokenFile, &c.Core.AdminApiToken},
+		{"database.encryption_passphrase", c.Database.EncryptionPassphraseFile, &c.Database.EncryptionPassphrase},
+		{"mail.password", c.Mail.PasswordFile, &c.Mail.Password},
+		{"web.session_secret", c.Web.SessionSecretFile, &c.Web.SessionSecret},
+		{"web.csrf_secret", c.Web.CsrfSecretFile, &c.Web.CsrfSecret},
+		{"webhook.authentication", c.Webhook.AuthenticationFile, &c.Webhook.Authentication},
+		{"webhook.secret", c.Webhook.SecretFile, &c.Webhook.Secret},
+	}
+	for i := range c.Auth.Ldap {
+		secrets = append(secrets, secretFile{fmt.Sprintf("auth.ldap[%d].bind_pass", i),
+			c.Auth.Ldap[i].BindPassFile, &c.Auth.Ldap[i].BindPass})
+	}
+	for i := range c.Auth.OpenIDConnect {
+		secrets = append(secrets, secretFile{fmt.Sprintf("auth.oidc[%d].client_secret", i),
+			c.Auth.OpenIDConnect[i].ClientSecretFile, &c.Auth.OpenIDConnect[i].ClientSecret})
+	}
+	for i := range c.Auth.OAuth {
+		secrets = append(secrets, secretFile{fmt.Sprintf("auth.oauth[%d].client_secret", i),
+			c.Auth.OAuth[i].ClientSecretFile, &c.Auth.OAuth[i].ClientSecret})
+	}
+	for i := range c.Backend.Mikrotik {
+		secrets = append(secrets, secretFile{fmt.Sprintf("backend.mikrotik[%d].api_password", i),
+			c.Backend.Mikrotik[i].ApiPasswordFile, &c.Backend.Mikrotik[i].ApiPassword})
+	}
+	for i := range c.Webhook.Subscriptions {
+		secrets = append(secrets,
+			secretFile{fmt.Sprintf("webhook.subscriptions[%d].authentication", i),
+				c.Webhook.Subscriptions[i].AuthenticationFile, &c.Webhook.Subscriptions[i].Authentication},
+			secretFile{fmt.Sprintf("webhook.subscriptions[%d].secret", i),
+				c.Webhook.Subscriptions[i].SecretFile, &c.Webhook.Subscriptions[i].Secret})
+	}
+	for i := range c.Webhook.Admission {
+		secrets = append(secrets,
+			secretFile{fmt.Sprintf("webhook.admission[%d].authentication", i),
+				c.Webhook.Admission[i].AuthenticationFile, &c.Webhook.Admission[i].Authentication},
+			secretFile{fmt.Sprintf("webhook.admission[%d].secret", i),
+				c.Webhook.Admission[i].SecretFile, &c.Webhook.Admission[i].Secret})
+	}
+	for i := range c.Exporters {
+		secrets = append(secrets, secretFile{fmt.Sprintf("exporters[%d].password", i),
+			c.Exporters[i].PasswordFile, &c.Exporters[i].Password})
+	}
+	for i := range c.Provisioning.Users {
+		secrets = append(secrets, secretFile{fmt.Sprintf("provisioning.users[%d].password", i),
+			c.Provisioning.Users[i].PasswordFile, &c.Provisioning.Users[i].Password})
+	}
+	for i := range c.Backend.Pfsense {
+		secrets = append(secrets, secretFile{fmt.Sprintf("backend.pfsense[%d].api_key", i),
+			c.Backend.Pfsense[i].ApiKeyFile, &c.Backend.Pfsense[i].ApiKey})
+	}
+
+	return secrets
+}
+
+// loadSecretFiles reads all secrets that are configured with a *_file setting. Trailing line breaks are removed.
+func (c *Config) loadSecretFiles() error {
+	for _, secret := range c.secretFiles() {
+		if secret.file == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(secret.file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_file: %w", secret.setting, err)
+		}
+		*secret.value = strings.TrimRight(string(data), "\r\n")
+		if c.sources == nil {
+			c.sources = make(map[string]string)
+		}
+		c.sources[secret.setting] = secret.file
+	}
+
+	return nil
+}
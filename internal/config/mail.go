@@ -34,6 +34,8 @@ type MailConfig struct {
 	Username string `yaml:"username"`
 	// Password is the optional SMTP password for authentication
 	Password string `yaml:"password"`
+	// PasswordFile is a file containing the SMTP password, it overrides Password
+	PasswordFile string `yaml:"password_file"`
 	// AuthType is the SMTP authentication type
 	AuthType MailAuthType `yaml:"auth_type"`
 
@@ -0,0 +1,407 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ValidationError is a problem of a single setting, found by Config.Validate.
+type ValidationError struct {
+	Setting string `json:"setting"` // the path of the setting, for example auth.ldap[0].login_filter
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	return e.Setting + ": " + e.Message
+}
+
+// validationErrors collects the problems of a configuration.
+type validationErrors []ValidationError
+
+func (v *validationErrors) add(setting string, format string, args ...any) {
+	*v = append(*v, ValidationError{Setting: setting, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validationErrors) addErr(setting string, err error) {
+	if err != nil {
+		v.add(setting, "%v", err)
+	}
+}
+
+var (
+	webhookEntities = []string{"user", "peer", "peer_metric", "interface"}
+	webhookEvents   = []string{"create", "update", "delete", "connect", "disconnect", "expiring"}
+	webhookPresets  = []string{"slack", "teams", "discord", "mattermost"}
+
+	admissionEntities   = []string{"peer", "user"}
+	admissionOperations = []string{"create", "update"}
+)
+
+// Validate checks the whole configuration and returns all problems, unlike GetConfig, which stops at the first
+// problem. Like GetConfig, it also applies the default values. No connection to external services is made.
+func (c *Config) Validate() []ValidationError {
+	var errs validationErrors
+
+	errs.addErr("provisioning.interfaces", sanitizeProvisioningInterfaces(c))
+	errs.addErr("provisioning.users", sanitizeProvisioningUsers(c))
+	errs.addErr("provisioning.peers", sanitizeProvisioningPeers(c))
+	errs.addErr("backend", c.Backend.Validate())
+
+	c.Web.Sanitize()
+	if c.Web.ExternalUrl != "" {
+		validateUrl(&errs, "web.external_url", c.Web.ExternalUrl)
+	}
+	for i, proxy := range c.Web.TrustedProxies {
+		if _, err := netip.ParseAddr(proxy); err != nil && proxy != "PRIVATE" {
+			errs.add(fmt.Sprintf("web.trusted_proxies[%d]", i), "must be an IP address or PRIVATE")
+		}
+	}
+
+	providerNames := make(map[string]string)
+	uniqueProvider := func(setting, name string) {
+		if name == "" {
+			errs.add(setting+".provider_name", "must not be empty")
+			return
+		}
+		if other, ok := providerNames[name]; ok {
+			errs.add(setting+".provider_name", "%q is already used by %s", name, other)
+			return
+		}
+		providerNames[name] = setting
+	}
+
+	for i := range c.Auth.Ldap {
+		provider := &c.Auth.Ldap[i]
+		setting := fmt.Sprintf("auth.ldap[%d]", i)
+		uniqueProvider(setting, provider.ProviderName)
+		if u, err := url.Parse(provider.URL); err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") {
+			errs.add(setting+".url", "must be an ldap:// or ldaps:// URL")
+		}
+		if _, err := ldap.ParseDN(provider.BaseDN); err != nil {
+			errs.add(setting+".base_dn", "invalid DN: %v", err)
+		}
+		errs.addErr(setting+".admin_group", provider.Sanitize())
+		if provider.LoginFilter == "" {
+			errs.add(setting+".login_filter", "must not be empty")
+		} else if !strings.Contains(provider.LoginFilter, "{{login_identifier}}") {
+			errs.add(setting+".login_filter", "must contain the {{login_identifier}} placeholder")
+		}
+		loginFilter := strings.ReplaceAll(provider.LoginFilter, "{{login_identifier}}", "user")
+		validateLdapFilter(&errs, setting+".login_filter", loginFilter)
+		validateLdapFilter(&errs, setting+".sync_filter", provider.SyncFilter)
+		if provider.SyncInterval < 0 {
+			errs.add(setting+".sync_interval", "must not be negative")
+		}
+	}
+
+	for i, provider := range c.Auth.OpenIDConnect {
+		setting := fmt.Sprintf("auth.oidc[%d]", i)
+		uniqueProvider(setting, provider.ProviderName)
+		validateUrl(&errs, setting+".base_url", provider.BaseUrl)
+		if provider.ClientID == "" {
+			errs.add(setting+".client_id", "must not be empty")
+		}
+		validateAdminMapping(&errs, setting+".admin_mapping", provider.AdminMapping)
+		validateOidcApiAccess(&errs, setting+".api_access", provider.ApiAccess)
+	}
+
+	for i, provider := range c.Auth.OAuth {
+		setting := fmt.Sprintf("auth.oauth[%d]", i)
+		uniqueProvider(setting, provider.ProviderName)
+		validateUrl(&errs, setting+".auth_url", provider.AuthURL)
+		validateUrl(&errs, setting+".token_url", provider.TokenURL)
+		validateUrl(&errs, setting+".user_info_url", provider.UserInfoURL)
+		if provider.ClientID == "" {
+			errs.add(setting+".client_id", "must not be empty")
+		}
+		validateAdminMapping(&errs, setting+".admin_mapping", provider.AdminMapping)
+	}
+
+	for i, backend := range c.Backend.Mikrotik {
+		validateUrl(&errs, fmt.Sprintf("backend.mikrotik[%d].api_url", i), backend.ApiUrl)
+	}
+	for i, backend := range c.Backend.Pfsense {
+		validateUrl(&errs, fmt.Sprintf("backend.pfsense[%d].api_url", i), backend.ApiUrl)
+	}
+
+	switch c.Database.Type {
+	case DatabaseMySQL, DatabaseMsSQL, DatabasePostgres, DatabaseSQLite:
+	default:
+		errs.add("database.type", "must be one of: mysql, mssql, postgres, sqlite")
+	}
+
+	if c.Mail.Host != "" {
+		if c.Mail.Port < 1 || c.Mail.Port > 65535 {
+			errs.add("mail.port", "must be between 1 and 65535")
+		}
+		switch c.Mail.Encryption {
+		case MailEncryptionNone, MailEncryptionTLS, MailEncryptionStartTLS:
+		default:
+			errs.add("mail.encryption", "must be one of: none, tls, starttls")
+		}
+		switch c.Mail.AuthType {
+		case MailAuthPlain, MailAuthLogin, MailAuthCramMD5:
+		default:
+			errs.add("mail.auth_type", "must be one of: plain, login, crammd5")
+		}
+	}
+
+	if c.Webhook.Url != "" {
+		validateUrl(&errs, "webhook.url", c.Webhook.Url)
+	}
+	webhookNames := make(map[string]struct{}, len(c.Webhook.Subscriptions))
+	for i, subscription := range c.Webhook.Subscriptions {
+		setting := fmt.Sprintf("webhook.subscriptions[%d]", i)
+		if _, ok := webhookNames[subscription.Name]; ok || subscription.Name == "default" {
+			errs.add(setting+".name", "must be unique and must not be default")
+		}
+		if subscription.Name == "" {
+			errs.add(setting+".name", "must not be empty")
+		}
+		webhookNames[subscription.Name] = struct{}{}
+		validateUrl(&errs, setting+".url", subscription.Url)
+		if subscription.Preset != "" && !slices.Contains(webhookPresets, subscription.Preset) {
+			errs.add(setting+".preset", "unknown preset %s, must be one of %s", subscription.Preset,
+				strings.Join(webhookPresets, ", "))
+		}
+		if subscription.Preset != "" && subscription.Template != "" {
+			errs.add(setting+".template", "cannot be combined with a preset")
+		}
+		if subscription.ContentType != "" && subscription.Template == "" {
+			errs.add(setting+".content_type", "can only be set for templates")
+		}
+		for _, entity := range subscription.Entities {
+			if !slices.Contains(webhookEntities, entity) {
+				errs.add(setting+".entities", "unknown entity %s, must be one of %s", entity,
+					strings.Join(webhookEntities, ", "))
+			}
+		}
+		for _, event := range subscription.Events {
+			if !slices.Contains(webhookEvents, event) {
+				errs.add(setting+".events", "unknown event %s, must be one of %s", event,
+					strings.Join(webhookEvents, ", "))
+			}
+		}
+	}
+	admissionNames := make(map[string]struct{}, len(c.Webhook.Admission))
+	for i, hook := range c.Webhook.Admission {
+		setting := fmt.Sprintf("webhook.admission[%d]", i)
+		if _, ok := admissionNames[hook.Name]; ok || hook.Name == "" {
+			errs.add(setting+".name", "must be unique and must not be empty")
+		}
+		admissionNames[hook.Name] = struct{}{}
+		validateUrl(&errs, setting+".url", hook.Url)
+		switch hook.Type {
+		case AdmissionWebhookValidating, AdmissionWebhookMutating:
+		default:
+			errs.add(setting+".type", "must be one of: validating, mutating")
+		}
+		for _, entity := range hook.Entities {
+			if !slices.Contains(admissionEntities, entity) {
+				errs.add(setting+".entities", "unknown entity %s, must be one of %s", entity,
+					strings.Join(admissionEntities, ", "))
+			}
+		}
+		for _, operation := range hook.Operations {
+			if !slices.Contains(admissionOperations, operation) {
+				errs.add(setting+".operations", "unknown operation %s, must be one of %s", operation,
+					strings.Join(admissionOperations, ", "))
+			}
+		}
+		if hook.Timeout < 0 {
+			errs.add(setting+".timeout", "must not be negative")
+		}
+		switch hook.FailurePolicy {
+		case "", AdmissionFailurePolicyFail, AdmissionFailurePolicyIgnore:
+		default:
+			errs.add(setting+".failure_policy", "must be one of: fail, ignore")
+		}
+	}
+	if c.Webhook.MaxAttempts < 1 {
+		errs.add("webhook.max_attempts", "must be at least 1")
+	}
+	if c.Webhook.RetryInterval <= 0 {
+		errs.add("webhook.retry_interval", "must be positive")
+	}
+	if c.Webhook.DeliveryRetention <= 0 {
+		errs.add("webhook.delivery_retention", "must be positive")
+	}
+
+	for i, reminder := range c.Core.PeerExpiryReminders {
+		if reminder <= 0 {
+			errs.add(fmt.Sprintf("core.peer_expiry_reminders[%d]", i), "must be positive")
+		}
+	}
+
+	if c.Statistics.DataCollectionInterval <= 0 {
+		errs.add("statistics.data_collection_interval", "must be positive")
+	}
+	if c.Statistics.PingCheckInterval <= 0 {
+		errs.add("statistics.ping_check_interval", "must be positive")
+	}
+	if c.Statistics.AuditRetention < 0 {
+		errs.add("statistics.audit_retention", "must not be negative")
+	}
+	if c.Statistics.AuditMaxEntries < 0 {
+		errs.add("statistics.audit_max_entries", "must not be negative")
+	}
+	if c.Statistics.AuditSigningKey != "" {
+		if !c.Statistics.AuditHashChain {
+			errs.add("statistics.audit_signing_key", "requires audit_hash_chain to be enabled")
+		}
+		if c.Statistics.AuditCheckpointInterval <= 0 {
+			errs.add("statistics.audit_checkpoint_interval", "must be positive")
+		}
+	}
+	validateAuditSinks(&errs, c)
+	validateEventExporters(&errs, c)
+
+	return errs
+}
+
+func validateAuditSinks(errs *validationErrors, c *Config) {
+	sinkNames := make(map[string]struct{}, len(c.Statistics.AuditSinks))
+	for i, sink := range c.Statistics.AuditSinks {
+		setting := fmt.Sprintf("statistics.audit_sinks[%d]", i)
+		if _, ok := sinkNames[sink.Name]; ok || sink.Name == "" {
+			errs.add(setting+".name", "must be unique and must not be empty")
+		}
+		sinkNames[sink.Name] = struct{}{}
+		if !c.Statistics.CollectAuditData {
+			errs.add(setting, "requires collect_audit_data to be enabled")
+		}
+		if sink.BufferSize < 0 {
+			errs.add(setting+".buffer_size", "must not be negative")
+		}
+
+		switch sink.Type {
+		case AuditSinkSyslog:
+			switch sink.Network {
+			case "udp", "tcp", "tls":
+			default:
+				errs.add(setting+".network", "must be one of: udp, tcp, tls")
+			}
+			if _, _, err := net.SplitHostPort(sink.Address); err != nil {
+				errs.add(setting+".address", "must be host:port: %v", err)
+			}
+			if _, ok := auditSyslogFacilities[sink.Facility]; sink.Facility != "" && !ok {
+				errs.add(setting+".facility", "unknown facility %s", sink.Facility)
+			}
+			if (sink.TlsCaFile != "" || sink.TlsSkipVerify) && sink.Network != "tls" {
+				errs.add(setting+".network", "must be tls if TLS settings are configured")
+			}
+		case AuditSinkFile:
+			if sink.Path == "" {
+				errs.add(setting+".path", "must not be empty")
+			}
+			if sink.MaxSize < 0 {
+				errs.add(setting+".max_size", "must not be negative")
+			}
+			if sink.MaxBackups < 0 {
+				errs.add(setting+".max_backups", "must not be negative")
+			}
+		default:
+			errs.add(setting+".type", "must be one of: syslog, file")
+		}
+	}
+}
+
+func validateEventExporters(errs *validationErrors, c *Config) {
+	exporterNames := make(map[string]struct{}, len(c.Exporters))
+	for i, exporter := range c.Exporters {
+		setting := fmt.Sprintf("exporters[%d]", i)
+		if _, ok := exporterNames[exporter.Name]; ok || exporter.Name == "" {
+			errs.add(setting+".name", "must be unique and must not be empty")
+		}
+		exporterNames[exporter.Name] = struct{}{}
+		validateUrl(errs, setting+".url", exporter.Url)
+		if exporter.BufferSize < 0 {
+			errs.add(setting+".buffer_size", "must not be negative")
+		}
+
+		switch exporter.Type {
+		case EventExporterMqtt:
+			if exporter.Qos < 0 || exporter.Qos > 2 {
+				errs.add(setting+".qos", "must be 0, 1 or 2")
+			}
+			if strings.ContainsAny(exporter.Prefix, "+#") {
+				errs.add(setting+".prefix", "must not contain the wildcards + and #")
+			}
+			if exporter.CredentialsFile != "" {
+				errs.add(setting+".credentials_file", "is only supported by NATS")
+			}
+		case EventExporterNats:
+			if strings.ContainsAny(exporter.Prefix, "*> \t") {
+				errs.add(setting+".prefix", "must not contain the wildcards * and > or whitespace")
+			}
+			if exporter.Qos != 0 || exporter.ClientId != "" {
+				errs.add(setting+".type", "qos and client_id are only supported by MQTT")
+			}
+		default:
+			errs.add(setting+".type", "must be one of: mqtt, nats")
+		}
+	}
+}
+
+func validateUrl(errs *validationErrors, setting, raw string) {
+	u, err := url.Parse(raw)
+	switch {
+	case raw == "":
+		errs.add(setting, "must not be empty")
+	case err != nil:
+		errs.add(setting, "invalid URL: %v", err)
+	case u.Scheme == "" || u.Host == "":
+		errs.add(setting, "must be an absolute URL, for example https://example.com")
+	}
+}
+
+func validateLdapFilter(errs *validationErrors, setting, filter string) {
+	if filter == "" {
+		return
+	}
+	if _, err := ldap.CompileFilter(filter); err != nil {
+		errs.add(setting, "invalid LDAP filter: %v", err)
+	}
+}
+
+func validateOidcApiAccess(errs *validationErrors, setting string, access OidcApiAccess) {
+	if !access.Enabled {
+		return
+	}
+	for providerScope, scope := range access.ScopeMapping {
+		switch scope {
+		case "full", "read-only", "peers:write", "provisioning":
+		default:
+			errs.add(setting+".scope_mapping."+providerScope, "must be one of: full, read-only, peers:write, provisioning")
+		}
+	}
+	names := make(map[string]struct{}, len(access.ServiceIdentities))
+	for i, identity := range access.ServiceIdentities {
+		identitySetting := fmt.Sprintf("%s.service_identities[%d]", setting, i)
+		if identity.Subject == "" {
+			errs.add(identitySetting+".subject", "must not be empty")
+		}
+		if identity.Name == "" {
+			errs.add(identitySetting+".name", "must not be empty")
+		} else if _, ok := names[identity.Name]; ok {
+			errs.add(identitySetting+".name", "%q is already used", identity.Name)
+		}
+		names[identity.Name] = struct{}{}
+	}
+}
+
+func validateAdminMapping(errs *validationErrors, setting string, mapping OauthAdminMapping) {
+	if _, err := regexp.Compile(mapping.AdminValueRegex); err != nil {
+		errs.add(setting+".admin_value_regex", "invalid regular expression: %v", err)
+	}
+	if _, err := regexp.Compile(mapping.AdminGroupRegex); err != nil {
+		errs.add(setting+".admin_group_regex", "invalid regular expression: %v", err)
+	}
+}
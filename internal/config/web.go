@@ -17,8 +17,12 @@ type WebConfig struct {
 	SessionIdentifier string `yaml:"session_identifier"`
 	// SessionSecret is the session secret for the web frontend.
 	SessionSecret string `yaml:"session_secret"`
+	// SessionSecretFile is a file containing the session secret, it overrides SessionSecret.
+	SessionSecretFile string `yaml:"session_secret_file"`
 	// CsrfSecret is the CSRF secret.
 	CsrfSecret string `yaml:"csrf_secret"`
+	// CsrfSecretFile is a file containing the CSRF secret, it overrides CsrfSecret.
+	CsrfSecretFile string `yaml:"csrf_secret_file"`
 	// SiteTitle is the title that is shown in the web frontend.
 	SiteTitle string `yaml:"site_title"`
 	// SiteCompanyName is the company name that is shown at the bottom of the web frontend.
@@ -27,6 +31,12 @@ type WebConfig struct {
 	CertFile string `yaml:"cert_file"`
 	// KeyFile is the path to the TLS certificate key file.
 	KeyFile string `yaml:"key_file"`
+	// TrustedProxies are the IP addresses of reverse proxies. For requests from these addresses, the client IP is
+	// taken from the X-Real-Ip or X-Forwarded-For header. The special value PRIVATE trusts all private addresses.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// GrpcListeningAddress is the address and port for the gRPC management API. If empty, the gRPC API is disabled.
+	// The gRPC server uses the same TLS certificate as the web server.
+	GrpcListeningAddress string `yaml:"grpc_listening_address"`
 }
 
 func (c *WebConfig) Sanitize() {
@@ -4,11 +4,97 @@ import "time"
 
 // WebhookConfig contains the configuration for webhooks.
 type WebhookConfig struct {
-	// Url is the URL to send the webhook to. If empty, no webhook will be sent.
+	// Url is the URL to send the webhook to. It receives all events, additional receivers with event filters can be
+	// configured in Subscriptions. If empty, only the subscriptions are used.
 	Url string `yaml:"url"`
 	// Authentication is the authorization header for the webhook request.
 	// It can either be a Bearer token or a Basic auth string.
 	Authentication string `yaml:"authentication"`
+	// AuthenticationFile is a file containing the authorization header, it overrides Authentication.
+	AuthenticationFile string `yaml:"authentication_file"`
 	// Timeout is the timeout for the webhook request.
 	Timeout time.Duration `yaml:"timeout"`
+	// Secret is the key of the HMAC-SHA256 request signature. If empty, requests are not signed.
+	Secret string `yaml:"secret"`
+	// SecretFile is a file containing the signature key, it overrides Secret.
+	SecretFile string `yaml:"secret_file"`
+	// Subscriptions are additional webhook receivers, each of them receives the events that match its filters.
+	Subscriptions []WebhookSubscription `yaml:"subscriptions"`
+	// Admission are synchronous webhooks that approve or change new and modified peers and users before they are
+	// stored.
+	Admission []AdmissionWebhook `yaml:"admission"`
+	// MaxAttempts is the number of delivery attempts, failed deliveries are retried until it is reached.
+	MaxAttempts int `yaml:"max_attempts"`
+	// RetryInterval is the delay before the first retry, it doubles with every further retry.
+	RetryInterval time.Duration `yaml:"retry_interval"`
+	// DeliveryRetention is the time delivered and failed deliveries are kept in the delivery log.
+	DeliveryRetention time.Duration `yaml:"delivery_retention"`
+}
+
+// WebhookSubscription is a webhook receiver of the configuration file. Empty filters match all events.
+type WebhookSubscription struct {
+	// Name is the unique name of the subscription, it is used as identifier.
+	Name string `yaml:"name"`
+	// Url is the URL to send the webhook to.
+	Url string `yaml:"url"`
+	// Authentication is the authorization header for the webhook request.
+	Authentication string `yaml:"authentication"`
+	// AuthenticationFile is a file containing the authorization header, it overrides Authentication.
+	AuthenticationFile string `yaml:"authentication_file"`
+	// Secret is the key of the HMAC-SHA256 request signature. If empty, requests are not signed.
+	Secret string `yaml:"secret"`
+	// SecretFile is a file containing the signature key, it overrides Secret.
+	SecretFile string `yaml:"secret_file"`
+	// Preset is a built-in payload template: slack, teams, discord or mattermost.
+	Preset string `yaml:"preset"`
+	// Template is a custom text/template of the request body. It cannot be combined with a preset.
+	Template string `yaml:"template"`
+	// ContentType is the content type of the custom template, application/json if empty.
+	ContentType string `yaml:"content_type"`
+	// Entities restricts the subscription to the given entities: user, peer, peer_metric or interface.
+	Entities []string `yaml:"entities"`
+	// Events restricts the subscription to the given events: create, update, delete, connect, disconnect or
+	// expiring.
+	Events []string `yaml:"events"`
+	// Interfaces restricts the subscription to events of peers and interfaces of the given interfaces.
+	Interfaces []string `yaml:"interfaces"`
+	// Users restricts the subscription to events of the given users and their peers.
+	Users []string `yaml:"users"`
+}
+
+const (
+	AdmissionWebhookValidating = "validating"
+	AdmissionWebhookMutating   = "mutating"
+
+	AdmissionFailurePolicyFail   = "fail"
+	AdmissionFailurePolicyIgnore = "ignore"
+)
+
+// AdmissionWebhook is a webhook that is called before a peer or user is created or updated. The change is only
+// stored if the webhook allows it. Empty filters match all entities and operations.
+type AdmissionWebhook struct {
+	// Name is the unique name of the webhook, it is shown in error messages.
+	Name string `yaml:"name"`
+	// Url is the URL to send the admission request to.
+	Url string `yaml:"url"`
+	// Authentication is the authorization header for the webhook request.
+	Authentication string `yaml:"authentication"`
+	// AuthenticationFile is a file containing the authorization header, it overrides Authentication.
+	AuthenticationFile string `yaml:"authentication_file"`
+	// Secret is the key of the HMAC-SHA256 request signature. If empty, requests are not signed.
+	Secret string `yaml:"secret"`
+	// SecretFile is a file containing the signature key, it overrides Secret.
+	SecretFile string `yaml:"secret_file"`
+	// Type is either validating or mutating. Mutating webhooks may return a JSON patch, they are called before the
+	// validating webhooks.
+	Type string `yaml:"type"`
+	// Entities restricts the webhook to the given entities: peer or user.
+	Entities []string `yaml:"entities"`
+	// Operations restricts the webhook to the given operations: create or update.
+	Operations []string `yaml:"operations"`
+	// Timeout is the timeout for the webhook request, the webhook timeout is used if it is zero.
+	Timeout time.Duration `yaml:"timeout"`
+	// FailurePolicy decides what happens if the webhook cannot be reached or returns an invalid response: fail
+	// rejects the change (default), ignore allows it.
+	FailurePolicy string `yaml:"failure_policy"`
 }
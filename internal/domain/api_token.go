@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiTokenPrefix is the prefix of all named API token secrets. It allows to recognize leaked tokens, for example
+// by secret scanners.
+const ApiTokenPrefix = "wgp_"
+
+type ApiTokenIdentifier string
+
+type ApiTokenScope string
+
+const (
+	ApiTokenScopeFull         ApiTokenScope = "full"         // all permissions of the user
+	ApiTokenScopeReadOnly     ApiTokenScope = "read-only"    // all read requests
+	ApiTokenScopePeersWrite   ApiTokenScope = "peers:write"  // all requests of the peer endpoints
+	ApiTokenScopeProvisioning ApiTokenScope = "provisioning" // all requests of the provisioning endpoints
+)
+
+// ApiTokenScopes contains all valid API token scopes.
+var ApiTokenScopes = []ApiTokenScope{ApiTokenScopeFull, ApiTokenScopeReadOnly, ApiTokenScopePeersWrite,
+	ApiTokenScopeProvisioning}
+
+// ApiToken is a named token for the REST API. A user can have multiple tokens, each of them is restricted to a set
+// of scopes. Only the SHA-256 hash of the token secret is stored, the secret is shown once when the token is created.
+type ApiToken struct {
+	Identifier     ApiTokenIdentifier `gorm:"primaryKey;column:identifier"`
+	UserIdentifier UserIdentifier     `gorm:"index;column:user_identifier"`
+	Name           string             `gorm:"column:name"`
+	TokenHash      string             `gorm:"uniqueIndex;column:token_hash"` // hex encoded SHA-256 hash of the secret
+	Scopes         []ApiTokenScope    `gorm:"serializer:json;column:scopes"`
+	// SourceCidrs restricts the token to requests from the given networks, all networks are allowed if it is empty.
+	SourceCidrs []string   `gorm:"serializer:json;column:source_cidrs"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at"`
+	LastUsedAt  *time.Time `gorm:"column:last_used_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	CreatedBy   string     `gorm:"column:created_by"`
+}
+
+// NewApiToken creates a new API token for the given user and returns it together with its secret.
+func NewApiToken(userId UserIdentifier, name string, scopes []ApiTokenScope) (*ApiToken, string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate token secret: %w", err)
+	}
+	secret := ApiTokenPrefix + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	token := &ApiToken{
+		Identifier:     ApiTokenIdentifier(uuid.New().String()),
+		UserIdentifier: userId,
+		Name:           name,
+		TokenHash:      HashApiTokenSecret(secret),
+		Scopes:         scopes,
+		CreatedAt:      time.Now(),
+	}
+
+	return token, secret, nil
+}
+
+// HashApiTokenSecret returns the hash of an API token secret, as it is stored in the database. Token secrets are
+// random, so a fast hash function is sufficient.
+func HashApiTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// IsApiTokenSecret returns true if the given value looks like the secret of a named API token.
+func IsApiTokenSecret(value string) bool {
+	return strings.HasPrefix(value, ApiTokenPrefix)
+}
+
+// Validate checks the name, scopes and source CIDRs of the token.
+func (t *ApiToken) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.Join(errors.New("token name must not be empty"), ErrInvalidData)
+	}
+	if len(t.Scopes) == 0 {
+		return errors.Join(errors.New("token needs at least one scope"), ErrInvalidData)
+	}
+	for _, scope := range t.Scopes {
+		if !slices.Contains(ApiTokenScopes, scope) {
+			return errors.Join(fmt.Errorf("unknown token scope %s", scope), ErrInvalidData)
+		}
+	}
+	for _, cidr := range t.SourceCidrs {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return errors.Join(fmt.Errorf("invalid source CIDR %s: %w", cidr, err), ErrInvalidData)
+		}
+	}
+	if t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now()) {
+		return errors.Join(errors.New("token expiry must be in the future"), ErrInvalidData)
+	}
+
+	return nil
+}
+
+// IsExpired returns true if the token has an expiry date that has passed.
+func (t *ApiToken) IsExpired() bool {
+	return t.ExpiresAt != nil && t.ExpiresAt.Before(time.Now())
+}
+
+// AllowsSource returns true if the token can be used from the given client IP address.
+func (t *ApiToken) AllowsSource(clientIp string) bool {
+	if len(t.SourceCidrs) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(clientIp)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, cidr := range t.SourceCidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows returns true if one of the token scopes permits a request. The resource is the first path segment of the
+// REST API endpoint, for example "peer" or "provisioning". Write requests are all requests that are not GET or HEAD.
+func (t *ApiToken) Allows(resource string, write bool) bool {
+	for _, scope := range t.Scopes {
+		switch scope {
+		case ApiTokenScopeFull:
+			return true
+		case ApiTokenScopeReadOnly:
+			if !write {
+				return true
+			}
+		case ApiTokenScopePeersWrite:
+			if resource == "peer" {
+				return true
+			}
+		case ApiTokenScopeProvisioning:
+			if resource == "provisioning" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewApiToken(t *testing.T) {
+	token, secret, err := NewApiToken("user", "ci", []ApiTokenScope{ApiTokenScopeReadOnly})
+	require.NoError(t, err)
+
+	assert.True(t, IsApiTokenSecret(secret))
+	assert.Equal(t, HashApiTokenSecret(secret), token.TokenHash)
+	assert.NotContains(t, token.TokenHash, secret)
+	assert.NotEmpty(t, token.Identifier)
+	assert.NoError(t, token.Validate())
+
+	_, otherSecret, err := NewApiToken("user", "ci", []ApiTokenScope{ApiTokenScopeReadOnly})
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, otherSecret)
+}
+
+func TestApiToken_Validate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name  string
+		token ApiToken
+	}{
+		{"missing name", ApiToken{Scopes: []ApiTokenScope{ApiTokenScopeFull}}},
+		{"missing scopes", ApiToken{Name: "ci"}},
+		{"unknown scope", ApiToken{Name: "ci", Scopes: []ApiTokenScope{"admin"}}},
+		{"invalid cidr", ApiToken{Name: "ci", Scopes: []ApiTokenScope{ApiTokenScopeFull},
+			SourceCidrs: []string{"10.0.0.1"}}},
+		{"expired", ApiToken{Name: "ci", Scopes: []ApiTokenScope{ApiTokenScopeFull}, ExpiresAt: &past}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.token.Validate(), ErrInvalidData)
+		})
+	}
+}
+
+func TestApiToken_Allows(t *testing.T) {
+	readOnly := ApiToken{Scopes: []ApiTokenScope{ApiTokenScopeReadOnly}}
+	assert.True(t, readOnly.Allows("user", false))
+	assert.False(t, readOnly.Allows("peer", true))
+
+	peers := ApiToken{Scopes: []ApiTokenScope{ApiTokenScopePeersWrite}}
+	assert.True(t, peers.Allows("peer", true))
+	assert.True(t, peers.Allows("peer", false))
+	assert.False(t, peers.Allows("interface", false))
+	assert.False(t, peers.Allows("provisioning", true))
+
+	provisioning := ApiToken{Scopes: []ApiTokenScope{ApiTokenScopeProvisioning, ApiTokenScopeReadOnly}}
+	assert.True(t, provisioning.Allows("provisioning", true))
+	assert.True(t, provisioning.Allows("interface", false))
+	assert.False(t, provisioning.Allows("interface", true))
+
+	full := ApiToken{Scopes: []ApiTokenScope{ApiTokenScopeFull}}
+	assert.True(t, full.Allows("backup", true))
+}
+
+func TestApiToken_AllowsSource(t *testing.T) {
+	token := ApiToken{SourceCidrs: []string{"10.0.0.0/24", "fd00::/64"}}
+	assert.True(t, token.AllowsSource("10.0.0.7"))
+	assert.True(t, token.AllowsSource("::ffff:10.0.0.7"))
+	assert.True(t, token.AllowsSource("fd00::1"))
+	assert.False(t, token.AllowsSource("10.0.1.7"))
+	assert.False(t, token.AllowsSource(""))
+
+	assert.True(t, (&ApiToken{}).AllowsSource("192.0.2.1"))
+}
+
+func TestApiToken_IsExpired(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	assert.True(t, (&ApiToken{ExpiresAt: &past}).IsExpired())
+	assert.False(t, (&ApiToken{ExpiresAt: &future}).IsExpired())
+	assert.False(t, (&ApiToken{}).IsExpired())
+}
@@ -2,6 +2,11 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
 	"time"
 )
 
@@ -10,17 +15,104 @@ type AuditSeverityLevel string
 const AuditSeverityLevelLow AuditSeverityLevel = "low"
 const AuditSeverityLevelHigh AuditSeverityLevel = "high"
 
+const (
+	AuditEntityUser               = "user"
+	AuditEntityApiToken           = "api_token"
+	AuditEntityWebAuthnCredential = "webauthn_credential"
+	AuditEntityInterface          = "interface"
+	AuditEntityPeer               = "peer"
+	AuditEntityConfig             = "config"
+	AuditEntityAuth               = "auth"
+)
+
+// auditRedacted replaces the values of secret fields in audit changes.
+const auditRedacted = "[redacted]"
+
+// auditSecretFields are the fields whose values are never written to the audit log, only the fact that they changed.
+var auditSecretFields = []string{
+	"Password", "ApiToken", "PrivateKey", "PresharedKey", "TokenHash", "SerializedCredential",
+}
+
+// auditIgnoredFields are bookkeeping fields that are not recorded as changes.
+// WebAuthn credentials are audited separately.
+var auditIgnoredFields = []string{
+	"CreatedAt", "CreatedBy", "UpdatedAt", "UpdatedBy", "LinkedPeerCount", "WebAuthnCredentialList",
+}
+
 type AuditEntry struct {
 	UniqueId  uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
 	CreatedAt time.Time `gorm:"column:created_at;index:idx_au_created"`
 
-	ContextUser string `gorm:"column:context_user;index:idx_au_context_user"`
+	ContextUser string `gorm:"column:context_user;index:idx_au_context_user"` // the actor that made the change
+	SourceIp    string `gorm:"column:source_ip"`                              // the client IP of the actor, if known
 
 	Severity AuditSeverityLevel `gorm:"column:severity;index:idx_au_severity"`
 
 	Origin string `gorm:"column:origin"` // origin: for example user auth, stats, ...
 
+	EntityType string `gorm:"column:entity_type;index:idx_au_entity"` // the type of the changed entity, for example user
+	EntityId   string `gorm:"column:entity_id;index:idx_au_entity"`   // the identifier of the changed entity
+	Action     string `gorm:"column:action"`                          // the action, for example create, update or delete
+
 	Message string `gorm:"column:message"`
+
+	Changes []AuditChange `gorm:"column:changes;serializer:json"` // the changed fields of the entity
+
+	PrevHash string `gorm:"column:prev_hash"` // the hash of the previous entry, if the hash chain is enabled
+	Hash     string `gorm:"column:hash"`      // the hash of this entry, see ComputeHash
+}
+
+// AuditChange is the change of a single field. Nested fields are separated by dots, for example Interface.Mtu.
+type AuditChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+}
+
+// AuditEntryFilter restricts the entries of an audit log query. Empty fields are ignored.
+type AuditEntryFilter struct {
+	From        *time.Time         // only entries created at or after this time
+	To          *time.Time         // only entries created before this time
+	Severity    AuditSeverityLevel // only entries with this severity
+	ContextUser string             // only entries of this actor
+	Origin      string             // only entries whose origin starts with this value, for example "peer"
+	Search      string             // only entries whose message, origin or entity contains this text
+}
+
+// Validate checks the severity and the time range of the filter.
+func (f AuditEntryFilter) Validate() error {
+	switch f.Severity {
+	case "", AuditSeverityLevelLow, AuditSeverityLevelHigh:
+	default:
+		return errors.Join(fmt.Errorf("unsupported severity %s, use low or high", f.Severity), ErrInvalidData)
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return errors.Join(errors.New("the start of the time range must be before its end"), ErrInvalidData)
+	}
+	return nil
+}
+
+// AuditEntryCursor is the position in the audit log after which a paginated query continues. Entries are returned
+// newest first, so the cursor is the identifier of the last entry of the previous page. Zero starts at the newest
+// entry.
+type AuditEntryCursor uint64
+
+const (
+	AuditExportFormatCsv       AuditExportFormat = "csv"
+	AuditExportFormatJsonLines AuditExportFormat = "jsonl"
+)
+
+// AuditExportFormat is the file format of an audit log export.
+type AuditExportFormat string
+
+// Validate returns an error if the format is not supported.
+func (f AuditExportFormat) Validate() error {
+	switch f {
+	case AuditExportFormatCsv, AuditExportFormatJsonLines:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q, use csv or jsonl: %w", f, ErrInvalidData)
+	}
 }
 
 type AuditEventWrapper[T any] struct {
@@ -28,3 +120,105 @@ type AuditEventWrapper[T any] struct {
 	Source string
 	Event  T
 }
+
+// AuditChanges returns the changed fields between the old and the new state of an entity. Both states are compared
+// in their JSON representation, old is nil for created and new is nil for deleted entities. The values of secret
+// fields are redacted.
+func AuditChanges(old, new any) []AuditChange {
+	oldFields := flattenAuditObject(old)
+	newFields := flattenAuditObject(new)
+
+	fields := make([]string, 0, len(oldFields)+len(newFields))
+	for field := range oldFields {
+		fields = append(fields, field)
+	}
+	for field := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	changes := make([]AuditChange, 0)
+	for _, field := range fields {
+		oldValue, newValue := oldFields[field], newFields[field]
+		if reflect.DeepEqual(oldValue, newValue) || isEmptyAuditValue(oldValue) && isEmptyAuditValue(newValue) {
+			continue
+		}
+
+		if slices.Contains(auditSecretFields, lastAuditFieldName(field)) {
+			oldValue, newValue = redactAuditValue(oldValue), redactAuditValue(newValue)
+		}
+		changes = append(changes, AuditChange{Field: field, Old: oldValue, New: newValue})
+	}
+
+	return changes
+}
+
+func flattenAuditObject(obj any) map[string]any {
+	fields := make(map[string]any)
+	value := reflect.ValueOf(obj)
+	if !value.IsValid() || value.Kind() == reflect.Pointer && value.IsNil() {
+		return fields
+	}
+
+	// a copy of the value is marshaled, so that secrets that hide themselves behind pointer receivers, like the
+	// password, are still detected as changes
+	raw, err := json.Marshal(reflect.Indirect(value).Interface())
+	if err != nil {
+		return fields
+	}
+	var values map[string]any
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return fields
+	}
+
+	flattenAuditFields(fields, "", values)
+	return fields
+}
+
+func flattenAuditFields(fields map[string]any, prefix string, values map[string]any) {
+	for key, value := range values {
+		if slices.Contains(auditIgnoredFields, key) {
+			continue
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenAuditFields(fields, prefix+key+".", nested)
+			continue
+		}
+		fields[prefix+key] = value
+	}
+}
+
+func lastAuditFieldName(field string) string {
+	for i := len(field) - 1; i >= 0; i-- {
+		if field[i] == '.' {
+			return field[i+1:]
+		}
+	}
+	return field
+}
+
+func isEmptyAuditValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func redactAuditValue(value any) any {
+	if isEmptyAuditValue(value) {
+		return nil
+	}
+	return auditRedacted
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// auditHashContent is the content of an audit entry that is covered by its hash. The identifier is not part of the
+// hash, as it changes when a backup is restored.
+type auditHashContent struct {
+	PrevHash    string             `json:"prev_hash"`
+	CreatedAt   int64              `json:"created_at"` // milliseconds, the precision that all databases store
+	ContextUser string             `json:"context_user"`
+	SourceIp    string             `json:"source_ip"`
+	Severity    AuditSeverityLevel `json:"severity"`
+	Origin      string             `json:"origin"`
+	EntityType  string             `json:"entity_type"`
+	EntityId    string             `json:"entity_id"`
+	Action      string             `json:"action"`
+	Message     string             `json:"message"`
+	Changes     []AuditChange      `json:"changes"`
+}
+
+// ComputeHash returns the hex encoded SHA-256 hash of the content of the entry and the hash of the previous entry.
+func (e *AuditEntry) ComputeHash() string {
+	changes := e.Changes
+	if len(changes) == 0 {
+		changes = nil // an empty list and no list are stored the same way
+	}
+
+	raw, _ := json.Marshal(auditHashContent{
+		PrevHash:    e.PrevHash,
+		CreatedAt:   e.CreatedAt.UnixMilli(),
+		ContextUser: e.ContextUser,
+		SourceIp:    e.SourceIp,
+		Severity:    e.Severity,
+		Origin:      e.Origin,
+		EntityType:  e.EntityType,
+		EntityId:    e.EntityId,
+		Action:      e.Action,
+		Message:     e.Message,
+		Changes:     changes,
+	})
+	hash := sha256.Sum256(raw)
+	return hex.EncodeToString(hash[:])
+}
+
+// IsChained returns true if the entry is part of the hash chain. Entries recorded while the chain was disabled have
+// no hash.
+func (e *AuditEntry) IsChained() bool {
+	return e.Hash != ""
+}
+
+// AuditCheckpoint is a signed statement that the audit log contained the entry with the given hash at the time of
+// the checkpoint. As each hash covers all previous entries, a checkpoint proves the state of the whole chain up to
+// that entry.
+type AuditCheckpoint struct {
+	Id        uint64    `gorm:"primaryKey;autoIncrement:true;column:id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+
+	EntryHash      string    `gorm:"column:entry_hash"`       // the hash of the last entry at the checkpoint time
+	EntryCreatedAt time.Time `gorm:"column:entry_created_at"` // the creation time of that entry
+	Signature      string    `gorm:"column:signature"`        // the base64 encoded Ed25519 signature
+}
+
+// SigningPayload returns the data that is signed by the checkpoint signature.
+func (c *AuditCheckpoint) SigningPayload() []byte {
+	return []byte("wg-portal-audit-checkpoint\n" + strconv.FormatInt(c.CreatedAt.UnixMilli(), 10) + "\n" +
+		c.EntryHash + "\n" + strconv.FormatInt(c.EntryCreatedAt.UnixMilli(), 10))
+}
+
+// AuditChainReport is the result of the verification of the audit hash chain.
+type AuditChainReport struct {
+	Valid bool
+
+	Entries          int // the number of verified entries
+	UnchainedEntries int // the number of entries recorded before the chain has been enabled
+	Checkpoints      int // the number of verified checkpoints
+
+	FirstBroken *AuditChainBreak // the first broken link, nil if the chain is valid
+}
+
+// AuditChainBreak describes the first broken link of the audit hash chain.
+type AuditChainBreak struct {
+	EntryId      uint64 // the entry at which the chain is broken, zero if the break has been found by a checkpoint
+	CheckpointId uint64 // the checkpoint that revealed the break, zero if the break has been found in the entries
+	CreatedAt    time.Time
+	Reason       string
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditChanges_User(t *testing.T) {
+	old := &User{
+		Identifier: "alice",
+		Firstname:  "Alice",
+		Password:   "secret-1",
+		BaseModel:  BaseModel{UpdatedAt: time.Now().Add(-time.Hour)},
+	}
+	updated := *old
+	updated.IsAdmin = true
+	updated.Firstname = "Alicia"
+	updated.Password = "secret-2"
+	updated.UpdatedAt = time.Now()
+
+	changes := AuditChanges(old, &updated)
+
+	assert.Equal(t, []AuditChange{
+		{Field: "Firstname", Old: "Alice", New: "Alicia"},
+		{Field: "IsAdmin", Old: false, New: true},
+		{Field: "Password", Old: auditRedacted, New: auditRedacted},
+	}, changes)
+}
+
+func TestAuditChanges_CreateAndDelete(t *testing.T) {
+	user := &User{Identifier: "bob", Email: "bob@example.com", Password: "secret"}
+
+	created := AuditChanges(nil, user)
+	assert.Contains(t, created, AuditChange{Field: "Identifier", New: "bob"})
+	assert.Contains(t, created, AuditChange{Field: "Password", New: auditRedacted})
+	assert.NotContains(t, created, AuditChange{Field: "IsAdmin"}, "empty values are not recorded")
+
+	deleted := AuditChanges(user, nil)
+	assert.Contains(t, deleted, AuditChange{Field: "Email", Old: "bob@example.com"})
+
+	assert.Empty(t, AuditChanges(user, user))
+}
+
+func TestAuditChanges_NestedFields(t *testing.T) {
+	old := &Peer{Identifier: "peer", Interface: PeerInterfaceConfig{KeyPair: KeyPair{PrivateKey: "a"}}}
+	old.Interface.Mtu = NewConfigOption(1420, false)
+	updated := *old
+	updated.Interface.Mtu = NewConfigOption(1380, false)
+	updated.Interface.PrivateKey = "b"
+
+	changes := AuditChanges(old, &updated)
+
+	assert.Contains(t, changes, AuditChange{Field: "Interface.Mtu.Value", Old: float64(1420), New: float64(1380)})
+	assert.Contains(t, changes, AuditChange{Field: "Interface.PrivateKey", Old: auditRedacted, New: auditRedacted})
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// BackupData contains all entities of the portal database, it is the content of a backup archive.
+type BackupData struct {
+	Users               []User // without their WebAuthn credentials, those are stored in WebAuthnCredentials
+	WebAuthnCredentials []UserWebauthnCredential
+	ApiTokens           []ApiToken
+	// WebhookSubscriptions contains the subscriptions created through the API, the delivery log is not backed up.
+	WebhookSubscriptions []WebhookSubscription
+	Interfaces           []Interface
+	Peers                []Peer
+	InterfaceStatuses    []InterfaceStatus
+	PeerStatuses         []PeerStatus
+	AuditEntries         []AuditEntry
+	AuditCheckpoints     []AuditCheckpoint
+}
+
+// BackupInfo describes a backup archive.
+type BackupInfo struct {
+	FormatVersion int       // the version of the archive format
+	SchemaVersion uint64    // the database schema version of the backed up portal
+	AppVersion    string    // the portal version that created the backup
+	DatabaseType  string    // the database type of the backed up portal, for information only
+	CreatedAt     time.Time // the time the backup has been created
+	CreatedBy     string    // the user that created the backup
+
+	Users                int
+	WebAuthnCredentials  int
+	ApiTokens            int
+	WebhookSubscriptions int
+	Interfaces           int
+	Peers                int
+	InterfaceStatuses    int
+	PeerStatuses         int
+	AuditEntries         int
+	AuditCheckpoints     int
+}
+
+// SetCounts sets the number of entities of the given backup data.
+func (i *BackupInfo) SetCounts(data *BackupData) {
+	i.Users = len(data.Users)
+	i.WebAuthnCredentials = len(data.WebAuthnCredentials)
+	i.ApiTokens = len(data.ApiTokens)
+	i.WebhookSubscriptions = len(data.WebhookSubscriptions)
+	i.Interfaces = len(data.Interfaces)
+	i.Peers = len(data.Peers)
+	i.InterfaceStatuses = len(data.InterfaceStatuses)
+	i.PeerStatuses = len(data.PeerStatuses)
+	i.AuditEntries = len(data.AuditEntries)
+	i.AuditCheckpoints = len(data.AuditCheckpoints)
+}
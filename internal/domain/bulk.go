@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	BulkFormatCsv  BulkFormat = "csv"
+	BulkFormatJson BulkFormat = "json"
+)
+
+// BulkFormat is the file format of a bulk import or export.
+type BulkFormat string
+
+// Validate returns an error if the format is not supported.
+func (f BulkFormat) Validate() error {
+	switch f {
+	case BulkFormatCsv, BulkFormatJson:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q, use csv or json: %w", f, ErrInvalidData)
+	}
+}
+
+const (
+	BulkImportModeAtomic     BulkImportMode = "atomic"      // either all rows are applied, or none
+	BulkImportModeBestEffort BulkImportMode = "best-effort" // valid rows are applied, failed rows are reported
+)
+
+// BulkImportMode defines how a bulk import handles invalid or failing rows.
+type BulkImportMode string
+
+// Validate returns an error if the mode is not supported.
+func (m BulkImportMode) Validate() error {
+	switch m {
+	case BulkImportModeAtomic, BulkImportModeBestEffort:
+		return nil
+	default:
+		return fmt.Errorf("unsupported import mode %q, use atomic or best-effort: %w", m, ErrInvalidData)
+	}
+}
+
+// BulkUser is a user record of a bulk import or export.
+type BulkUser struct {
+	Identifier UserIdentifier `json:"Identifier"`
+	Email      string         `json:"Email"`
+	Source     UserSource     `json:"Source"` // defaults to the database source on import
+	Firstname  string         `json:"Firstname"`
+	Lastname   string         `json:"Lastname"`
+	Phone      string         `json:"Phone"`
+	Department string         `json:"Department"`
+	Notes      string         `json:"Notes"`
+	IsAdmin    bool           `json:"IsAdmin"`
+	Disabled   bool           `json:"Disabled"`
+
+	// Password is only used for imports, it is never exported. An empty password keeps the existing password.
+	Password string `json:"Password,omitempty"`
+	// Interfaces are the interfaces the user has peers on. On import, a new peer is created on each of these
+	// interfaces if the user has no peer there yet.
+	Interfaces []InterfaceIdentifier `json:"Interfaces"`
+}
+
+// BulkPeer is a peer record of a bulk import or export.
+type BulkPeer struct {
+	// Identifier is the public key of the peer. If it is empty on import, a new peer is created for the user.
+	Identifier          PeerIdentifier      `json:"Identifier"`
+	InterfaceIdentifier InterfaceIdentifier `json:"InterfaceIdentifier"`
+	UserIdentifier      UserIdentifier      `json:"UserIdentifier"`
+	DisplayName         string              `json:"DisplayName"`
+	Notes               string              `json:"Notes"`
+	Addresses           string              `json:"Addresses"` // only exported, addresses of new peers are allocated
+	Disabled            bool                `json:"Disabled"`
+	ExpiresAt           *time.Time          `json:"ExpiresAt"`
+}
+
+const (
+	BulkRowValid      BulkRowStatus = "valid"       // dry run: the row would be applied
+	BulkRowInvalid    BulkRowStatus = "invalid"     // the row failed validation and has not been applied
+	BulkRowCreated    BulkRowStatus = "created"     // a new record has been created
+	BulkRowUpdated    BulkRowStatus = "updated"     // an existing record has been updated
+	BulkRowFailed     BulkRowStatus = "failed"      // applying the row failed, its changes have been reverted
+	BulkRowSkipped    BulkRowStatus = "skipped"     // atomic import: the row has not been applied because of other rows
+	BulkRowRolledBack BulkRowStatus = "rolled back" // atomic import: the row has been applied and reverted afterwards
+)
+
+// BulkRowStatus is the result of a single row of a bulk import.
+type BulkRowStatus string
+
+// BulkImportRow is the report of a single row of a bulk import.
+type BulkImportRow struct {
+	Row        int // the row number, starting at 1 for the first record
+	Identifier string
+	Status     BulkRowStatus
+	Errors     []string
+	Peers      []PeerIdentifier // peers that have been created for the row
+}
+
+// BulkImportReport is the per-row report of a bulk import.
+type BulkImportReport struct {
+	Mode      BulkImportMode
+	DryRun    bool
+	Committed bool // true if at least one row has been applied and kept
+	Rows      []BulkImportRow
+}
+
+// HasErrors returns true if any row is invalid or failed.
+func (r BulkImportReport) HasErrors() bool {
+	for _, row := range r.Rows {
+		if row.Status == BulkRowInvalid || row.Status == BulkRowFailed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package domain
+
+// ConnectivityCheck is the outcome of a connectivity test to an external service of the configuration.
+type ConnectivityCheck struct {
+	Setting string `json:"setting"` // the checked section of the configuration, for example auth.ldap[0]
+	Target  string `json:"target"`  // the checked service, for example the URL of an LDAP server
+	Error   string `json:"error,omitempty"`
+}
+
+// OK returns true if the service is reachable.
+func (c ConnectivityCheck) OK() bool {
+	return c.Error == ""
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// ConfigReloadReport describes the result of a configuration reload.
+type ConfigReloadReport struct {
+	ReloadedAt      time.Time
+	Applied         []string            // changed settings that have been applied, for example auth.ldap
+	Failed          []ConfigReloadError // components that could not apply the changed settings
+	RestartRequired []string            // changed settings that only take effect after a restart
+}
+
+// ConfigReloadError describes a component that could not apply the changed settings.
+// The component keeps its previous settings, it is reloaded again on the next reload.
+type ConfigReloadError struct {
+	Component string
+	Settings  []string
+	Error     string
+}
@@ -7,13 +7,15 @@ import (
 )
 
 const CtxUserInfo = "userInfo"
+const CtxSourceIp = "sourceIp"
 
 const (
-	CtxSystemAdminId    = "_WG_SYS_ADMIN_"
-	CtxUnknownUserId    = "_WG_SYS_UNKNOWN_"
-	CtxSystemLdapSyncer = "_WG_SYS_LDAP_SYNCER_"
-	CtxSystemWgImporter = "_WG_SYS_WG_IMPORTER_"
-	CtxSystemV1Migrator = "_WG_SYS_V1_MIGRATOR_"
+	CtxSystemAdminId     = "_WG_SYS_ADMIN_"
+	CtxUnknownUserId     = "_WG_SYS_UNKNOWN_"
+	CtxSystemLdapSyncer  = "_WG_SYS_LDAP_SYNCER_"
+	CtxSystemWgImporter  = "_WG_SYS_WG_IMPORTER_"
+	CtxSystemV1Migrator  = "_WG_SYS_V1_MIGRATOR_"
+	CtxSystemProvisioner = "_WG_SYS_PROVISIONER_"
 )
 
 type ContextUserInfo struct {
@@ -53,6 +55,14 @@ func LdapSyncContextUserInfo() *ContextUserInfo {
 	}
 }
 
+// ProvisioningContextUserInfo returns a context user info for the declarative provisioning.
+func ProvisioningContextUserInfo() *ContextUserInfo {
+	return &ContextUserInfo{
+		Id:      CtxSystemProvisioner,
+		IsAdmin: true,
+	}
+}
+
 // SetUserInfo sets the user info in the context.
 func SetUserInfo(ctx context.Context, info *ContextUserInfo) context.Context {
 	ctx = context.WithValue(ctx, CtxUserInfo, info)
@@ -73,6 +83,17 @@ func GetUserInfo(ctx context.Context) *ContextUserInfo {
 	return DefaultContextUserInfo()
 }
 
+// SetSourceIp sets the client IP of the request in the context, it is recorded in the audit log.
+func SetSourceIp(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, CtxSourceIp, ip)
+}
+
+// GetSourceIp returns the client IP of the request from the context, or an empty string if it is unknown.
+func GetSourceIp(ctx context.Context) string {
+	ip, _ := ctx.Value(CtxSourceIp).(string)
+	return ip
+}
+
 // ValidateUserAccessRights checks if the current user has access rights to the requested user.
 // If the user is an admin, access is granted.
 func ValidateUserAccessRights(ctx context.Context, requiredUser UserIdentifier) error {
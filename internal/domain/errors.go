@@ -11,6 +11,7 @@ var ErrNoPermission = errors.New("no permission")
 var ErrDuplicateEntry = errors.New("duplicate entry")
 var ErrInvalidData = errors.New("invalid data")
 var ErrPeerLimitReached = errors.New("peer limit reached")
+var ErrPreconditionFailed = errors.New("precondition failed")
 
 // GetStackTrace returns a stack trace of the current goroutine. The stack trace has at most 1024 bytes.
 func GetStackTrace() string {
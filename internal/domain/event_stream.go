@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+type StreamEventType string
+
+const (
+	StreamEventPeerCreated      StreamEventType = "peer:created"
+	StreamEventPeerUpdated      StreamEventType = "peer:updated"
+	StreamEventPeerDeleted      StreamEventType = "peer:deleted"
+	StreamEventPeerConnected    StreamEventType = "peer:connected"
+	StreamEventPeerDisconnected StreamEventType = "peer:disconnected"
+	StreamEventInterfaceCreated StreamEventType = "interface:created"
+	StreamEventInterfaceUpdated StreamEventType = "interface:updated"
+	StreamEventInterfaceDeleted StreamEventType = "interface:deleted"
+	StreamEventAuditEntry       StreamEventType = "audit:entry"
+	// StreamEventReset tells the client that events have been missed, the client should reload its data.
+	StreamEventReset StreamEventType = "stream:reset"
+)
+
+// StreamEvent is an event of the real-time event stream. The payload is a Peer, PeerStatus, Interface or AuditEntry,
+// depending on the event type.
+type StreamEvent struct {
+	Id        uint64
+	Type      StreamEventType
+	CreatedAt time.Time
+	// UserIdentifier is the owner of the event entity. Events without owner are only visible to admins.
+	UserIdentifier UserIdentifier
+	Payload        any
+}
+
+// VisibleTo returns true if the event can be sent to the given user. Admins see all events, other users only see the
+// events of their own entities.
+func (e StreamEvent) VisibleTo(user *ContextUserInfo) bool {
+	if user.IsAdmin {
+		return true
+	}
+
+	return e.UserIdentifier != "" && e.UserIdentifier == user.Id
+}
+
+// StreamEventFilter restricts the events of a stream subscription.
+type StreamEventFilter struct {
+	// Types contains the requested event types or type prefixes like "peer:", all events are sent if it is empty.
+	Types []string
+}
+
+// Matches returns true if the event type is requested by the filter.
+func (f StreamEventFilter) Matches(e StreamEvent) bool {
+	if len(f.Types) == 0 || e.Type == StreamEventReset {
+		return true
+	}
+
+	return slices.ContainsFunc(f.Types, func(t string) bool {
+		return strings.HasPrefix(string(e.Type), t)
+	})
+}
@@ -83,6 +83,8 @@ type Interface struct {
 	Disabled       *time.Time       `gorm:"index"` // flag that specifies if the interface is enabled (up) or not (down)
 	DisabledReason string           // the reason why the interface has been disabled
 
+	PeerExtensionDays int // the number of days users can extend their expiring peers by, 0 disables it
+
 	// Default settings for the peer, used for new peers, those settings will be published to ConfigOption options of
 	// the peer config
 
@@ -137,6 +139,10 @@ func (i *Interface) Validate() error {
 		i.PeerDefEndpoint = net.JoinHostPort(host, port)
 	}
 
+	if i.PeerExtensionDays < 0 {
+		return fmt.Errorf("invalid peer extension days: %d", i.PeerExtensionDays)
+	}
+
 	return nil
 }
 
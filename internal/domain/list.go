@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxListLimit is the maximum number of records that can be requested with a single list query.
+const MaxListLimit = 1000
+
+// Sortable fields of list queries. The field names match the JSON field names of the REST API.
+var (
+	PeerSortFields = []string{"Identifier", "DisplayName", "UserIdentifier", "InterfaceIdentifier", "CreatedAt",
+		"UpdatedAt", "ExpiresAt", "Disabled"}
+	UserSortFields      = []string{"Identifier", "Email", "Firstname", "Lastname", "CreatedAt", "UpdatedAt", "Disabled"}
+	InterfaceSortFields = []string{"Identifier", "DisplayName", "Mode", "CreatedAt", "UpdatedAt", "Disabled"}
+)
+
+// ListOptions controls the pagination and sorting of list queries.
+type ListOptions struct {
+	Offset   int    // the number of records to skip
+	Limit    int    // the maximum number of records, 0 returns all records
+	SortBy   string // one of the sortable fields, records are sorted by their identifier by default
+	SortDesc bool
+}
+
+// Validate checks the pagination values and that SortBy is one of the given sortable fields.
+func (o ListOptions) Validate(sortFields []string) error {
+	if o.Offset < 0 {
+		return errors.Join(errors.New("offset must not be negative"), ErrInvalidData)
+	}
+	if o.Limit < 0 || o.Limit > MaxListLimit {
+		return errors.Join(fmt.Errorf("limit must be between 0 and %d", MaxListLimit), ErrInvalidData)
+	}
+	if o.SortBy != "" && !slices.Contains(sortFields, o.SortBy) {
+		return errors.Join(fmt.Errorf("unsupported sort field %s, supported fields: %s", o.SortBy,
+			strings.Join(sortFields, ", ")), ErrInvalidData)
+	}
+	return nil
+}
+
+// PeerFilter restricts the peers of a list query. Empty fields are ignored.
+type PeerFilter struct {
+	InterfaceIdentifier InterfaceIdentifier
+	UserIdentifier      UserIdentifier
+	Disabled            *bool
+	Expired             *bool
+	Connected           *bool
+	CreatedAfter        *time.Time
+}
+
+// UserFilter restricts the users of a list query. Empty fields are ignored.
+type UserFilter struct {
+	Disabled     *bool
+	Admin        *bool
+	CreatedAfter *time.Time
+}
+
+// InterfaceFilter restricts the interfaces of a list query. Empty fields are ignored.
+type InterfaceFilter struct {
+	Disabled     *bool
+	CreatedAfter *time.Time
+}
+
+// PeerCounts contains the number of peers of an interface.
+type PeerCounts struct {
+	Total   int
+	Enabled int
+}
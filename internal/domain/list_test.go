@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOptions_Validate(t *testing.T) {
+	assert.NoError(t, ListOptions{}.Validate(PeerSortFields))
+	assert.NoError(t, ListOptions{Offset: 10, Limit: MaxListLimit, SortBy: "ExpiresAt"}.Validate(PeerSortFields))
+	assert.ErrorIs(t, ListOptions{Offset: -1}.Validate(PeerSortFields), ErrInvalidData)
+	assert.ErrorIs(t, ListOptions{Limit: MaxListLimit + 1}.Validate(PeerSortFields), ErrInvalidData)
+	assert.ErrorIs(t, ListOptions{SortBy: "ExpiresAt"}.Validate(UserSortFields), ErrInvalidData)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	MigrationSourceWgEasy   MigrationSource = "wg-easy"  // the wg0.json file of wg-easy
+	MigrationSourceFirezone MigrationSource = "firezone" // the Postgres database of Firezone (0.7)
+)
+
+// MigrationSource is the WireGuard management tool to import data from.
+type MigrationSource string
+
+// MigrationSources returns all supported migration sources.
+func MigrationSources() []MigrationSource {
+	return []MigrationSource{MigrationSourceWgEasy, MigrationSourceFirezone}
+}
+
+// Validate returns an error if the migration source is not supported.
+func (s MigrationSource) Validate() error {
+	if !slices.Contains(MigrationSources(), s) {
+		return fmt.Errorf("unsupported migration source %q: %w", s, ErrInvalidData)
+	}
+	return nil
+}
+
+// MigrationRequest describes an import of users, an interface and its peers from another WireGuard management tool.
+// All fields except the source and the source data are optional and override values that cannot be derived from the
+// source data.
+type MigrationRequest struct {
+	Source MigrationSource
+	Data   []byte // wg-easy: the content of the wg0.json file
+	Dsn    string // Firezone: the DSN of the Postgres database
+
+	Identifier     InterfaceIdentifier // the interface to create, defaults to wg0 (wg-easy) or wg-firezone (Firezone)
+	Backend        InterfaceBackend    // defaults to the default backend
+	PrivateKey     string              // Firezone stores the server key outside the database, a new key is generated if empty
+	Addresses      []Cidr              // the interface addresses, defaults to the default addresses of the source
+	ListenPort     int                 // defaults to 51820
+	Endpoint       string              // the public endpoint of the server, wg-easy: WG_HOST
+	UserIdentifier UserIdentifier      // wg-easy: the user that owns the imported peers, wg-easy has no user accounts
+}
+
+// MigrationPlan is the result of a migration. It lists the users, interface and peers that will be (or have been)
+// created, conflicts that block the migration and warnings that do not.
+type MigrationPlan struct {
+	Source    MigrationSource
+	Interface *Interface
+	Users     []User // only new users, existing users are kept as they are
+	Peers     []Peer
+	Conflicts []string
+	Warnings  []string
+	Committed bool // true if the users, interface and peers have been stored
+}
+
+// HasConflicts returns true if the migration cannot be committed.
+func (p MigrationPlan) HasConflicts() bool {
+	return len(p.Conflicts) > 0
+}
@@ -53,6 +53,9 @@ type Peer struct {
 	Notes                string              `form:"notes" binding:"omitempty"` // a note field for peers
 	AutomaticallyCreated bool                `gorm:"column:auto_created"`       // specifies if the peer was automatically created
 
+	ExpiryReminderSentAt *time.Time `gorm:"column:expiry_reminder_sent_at"` // the time the last expiry reminder was sent
+	ExtensionTokenHash   string     `json:"-"`                              // hash of the self-service extension token
+
 	// Interface settings for the peer, used to generate the [interface] section in the peer config file
 	Interface PeerInterfaceConfig `gorm:"embedded"`
 }
@@ -71,6 +74,26 @@ func (p *Peer) IsExpired() bool {
 	return false
 }
 
+// ExpiryReminderDue returns true if the peer expires within one of the given reminder periods and no reminder has
+// been sent since the start of that period. Disabled and expired peers do not receive reminders.
+func (p *Peer) ExpiryReminderDue(reminders []time.Duration, now time.Time) bool {
+	if p.ExpiresAt == nil || p.IsDisabled() || !now.Before(*p.ExpiresAt) {
+		return false
+	}
+
+	for _, reminder := range reminders {
+		periodStart := p.ExpiresAt.Add(-reminder)
+		if now.Before(periodStart) {
+			continue
+		}
+		if p.ExpiryReminderSentAt == nil || p.ExpiryReminderSentAt.Before(periodStart) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (p *Peer) CheckAliveAddress() string {
 	if p.Interface.CheckAliveAddress != "" {
 		return p.Interface.CheckAliveAddress
@@ -85,6 +108,8 @@ func (p *Peer) CheckAliveAddress() string {
 
 func (p *Peer) CopyCalculatedAttributes(src *Peer) {
 	p.BaseModel = src.BaseModel
+	p.ExpiryReminderSentAt = src.ExpiryReminderSentAt
+	p.ExtensionTokenHash = src.ExtensionTokenHash
 }
 
 func (p *Peer) GetConfigFileName() string {
@@ -29,6 +29,32 @@ func TestPeer_IsExpired(t *testing.T) {
 	assert.False(t, peer.IsExpired())
 }
 
+func TestPeer_ExpiryReminderDue(t *testing.T) {
+	day := 24 * time.Hour
+	reminders := []time.Duration{14 * day, 3 * day, day}
+	now := time.Now()
+	peer := &Peer{}
+	assert.False(t, peer.ExpiryReminderDue(reminders, now))
+
+	expiresAt := now.Add(10 * day)
+	peer.ExpiresAt = &expiresAt
+	assert.True(t, peer.ExpiryReminderDue(reminders, now))
+
+	peer.ExpiryReminderSentAt = &now
+	assert.False(t, peer.ExpiryReminderDue(reminders, now.Add(day)))
+	assert.True(t, peer.ExpiryReminderDue(reminders, now.Add(7*day+time.Minute)))
+
+	// after an extension, the reminders start again
+	extended := now.Add(60 * day)
+	peer.ExpiresAt = &extended
+	assert.False(t, peer.ExpiryReminderDue(reminders, now.Add(day)))
+	assert.True(t, peer.ExpiryReminderDue(reminders, now.Add(46*day+time.Minute)))
+
+	assert.False(t, peer.ExpiryReminderDue(reminders, now.Add(61*day)), "expired peers get no reminder")
+	peer.Disabled = &now
+	assert.False(t, peer.ExpiryReminderDue(reminders, now.Add(59*day)), "disabled peers get no reminder")
+}
+
 func TestPeer_CheckAliveAddress(t *testing.T) {
 	peer := &Peer{}
 	assert.Equal(t, "", peer.CheckAliveAddress())
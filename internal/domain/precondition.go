@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const CtxIfMatchPrecondition = "ifMatchPrecondition"
+
+// ifMatchPrecondition is the If-Match precondition of a request for a single record.
+type ifMatchPrecondition struct {
+	target  any // the identifier of the record, for example a PeerIdentifier
+	ifMatch string
+	checked atomic.Bool
+}
+
+// ETag returns the entity tag of a record, derived from its last update time.
+func (b BaseModel) ETag() string {
+	return `"` + strconv.FormatInt(b.UpdatedAt.UnixNano(), 16) + `"`
+}
+
+// SetIfMatchPrecondition stores the If-Match header value for the record with the given identifier in the context.
+// The precondition is verified by the repository when the record is saved or deleted. An empty value is ignored.
+func SetIfMatchPrecondition(ctx context.Context, target any, ifMatch string) context.Context {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, CtxIfMatchPrecondition, &ifMatchPrecondition{target: target, ifMatch: ifMatch})
+}
+
+// HasIfMatchPrecondition returns true if the context contains an unchecked precondition for the given record.
+func HasIfMatchPrecondition(ctx context.Context, target any) bool {
+	precondition, ok := ctx.Value(CtxIfMatchPrecondition).(*ifMatchPrecondition)
+	return ok && precondition.target == target && !precondition.checked.Load()
+}
+
+// CheckIfMatchPrecondition verifies the If-Match precondition of the given record against its current state.
+// The precondition is only checked once, further changes to the same record within the request are not affected.
+// ErrPreconditionFailed is returned if the entity tag does not match.
+func CheckIfMatchPrecondition(ctx context.Context, target any, exists bool, current BaseModel) error {
+	precondition, ok := ctx.Value(CtxIfMatchPrecondition).(*ifMatchPrecondition)
+	if !ok || precondition.target != target || !precondition.checked.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	return precondition.match(exists, current)
+}
+
+// PeekIfMatchPrecondition verifies the If-Match precondition like CheckIfMatchPrecondition, but does not mark it as
+// checked. It allows to fail early, before changes are applied outside the database.
+func PeekIfMatchPrecondition(ctx context.Context, target any, exists bool, current BaseModel) error {
+	if !HasIfMatchPrecondition(ctx, target) {
+		return nil
+	}
+
+	return ctx.Value(CtxIfMatchPrecondition).(*ifMatchPrecondition).match(exists, current)
+}
+
+func (p *ifMatchPrecondition) match(exists bool, current BaseModel) error {
+	if !exists {
+		return ErrPreconditionFailed
+	}
+
+	etag := current.ETag()
+	for _, tag := range strings.Split(p.ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckIfMatchPrecondition(t *testing.T) {
+	current := BaseModel{UpdatedAt: time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)}
+	other := BaseModel{UpdatedAt: current.UpdatedAt.Add(time.Millisecond)}
+	peerId := PeerIdentifier("peer")
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		target  any
+		exists  bool
+		wantErr error
+	}{
+		{"no precondition", "", peerId, true, nil},
+		{"matching tag", current.ETag(), peerId, true, nil},
+		{"tag list", other.ETag() + ", " + current.ETag(), peerId, true, nil},
+		{"wildcard", "*", peerId, true, nil},
+		{"stale tag", other.ETag(), peerId, true, ErrPreconditionFailed},
+		{"weak tag", "W/" + current.ETag(), peerId, true, ErrPreconditionFailed},
+		{"missing record", "*", peerId, false, ErrPreconditionFailed},
+		{"other record", other.ETag(), PeerIdentifier("other"), true, nil},
+		{"other record type", other.ETag(), UserIdentifier("peer"), true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := SetIfMatchPrecondition(context.Background(), peerId, tt.ifMatch)
+			err := CheckIfMatchPrecondition(ctx, tt.target, tt.exists, current)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckIfMatchPrecondition_OnlyOnce(t *testing.T) {
+	current := BaseModel{UpdatedAt: time.Now()}
+	peerId := PeerIdentifier("peer")
+	ctx := SetIfMatchPrecondition(context.Background(), peerId, `"stale"`)
+
+	assert.True(t, HasIfMatchPrecondition(ctx, peerId))
+	assert.ErrorIs(t, PeekIfMatchPrecondition(ctx, peerId, true, current), ErrPreconditionFailed)
+	assert.True(t, HasIfMatchPrecondition(ctx, peerId))
+	assert.ErrorIs(t, CheckIfMatchPrecondition(ctx, peerId, true, current), ErrPreconditionFailed)
+	assert.False(t, HasIfMatchPrecondition(ctx, peerId))
+	assert.NoError(t, CheckIfMatchPrecondition(ctx, peerId, true, current))
+}
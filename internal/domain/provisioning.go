@@ -0,0 +1,39 @@
+package domain
+
+const (
+	ProvisioningKindUser ProvisioningKind = "user"
+	ProvisioningKindPeer ProvisioningKind = "peer"
+)
+
+// ProvisioningKind is the kind of object that is managed by the declarative provisioning.
+type ProvisioningKind string
+
+const (
+	ProvisioningActionCreate ProvisioningAction = "create"
+	ProvisioningActionUpdate ProvisioningAction = "update"
+	ProvisioningActionDelete ProvisioningAction = "delete" // only planned if pruning is enabled
+)
+
+// ProvisioningAction is the change that is applied to a provisioned object.
+type ProvisioningAction string
+
+// ProvisioningChange is a single change of a provisioning plan.
+type ProvisioningChange struct {
+	Kind       ProvisioningKind
+	Identifier string
+	Action     ProvisioningAction
+	Fields     []string // the changed settings of an update, for example email or addresses
+}
+
+// ProvisioningPlan lists the changes that are required to reconcile the provisioned users and peers with the
+// configuration.
+type ProvisioningPlan struct {
+	Changes []ProvisioningChange
+	Errors  []string // invalid provisioning entries, no change is applied if there are errors
+	Applied bool     // true if all changes have been applied
+}
+
+// HasErrors returns true if the plan contains invalid provisioning entries.
+func (p ProvisioningPlan) HasErrors() bool {
+	return len(p.Errors) > 0
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscriptionIdentifier string
+
+// DefaultWebhookSubscription is the identifier of the subscription of the webhook.url configuration option.
+const DefaultWebhookSubscription WebhookSubscriptionIdentifier = "default"
+
+type WebhookSubscriptionSource string
+
+const (
+	WebhookSubscriptionSourceConfig   WebhookSubscriptionSource = "config"   // defined in the configuration file
+	WebhookSubscriptionSourceDatabase WebhookSubscriptionSource = "database" // created through the API
+)
+
+// WebhookEntities contains the entity types that webhooks are sent for.
+var WebhookEntities = []string{"user", "peer", "peer_metric", "interface"}
+
+// WebhookEvents contains the event types that webhooks are sent for.
+var WebhookEvents = []string{"create", "update", "delete", "connect", "disconnect", "expiring"}
+
+// WebhookPresets contains the built-in payload templates for chat services.
+var WebhookPresets = []string{"slack", "teams", "discord", "mattermost"}
+
+// WebhookSubscription is a webhook receiver. It receives the events that match its filters, empty filters match
+// all events. Subscriptions are either defined in the configuration file or created through the API, only the latter
+// are stored in the database.
+type WebhookSubscription struct {
+	Identifier WebhookSubscriptionIdentifier `gorm:"primaryKey;column:identifier"`
+	CreatedAt  time.Time                     `gorm:"column:created_at"`
+	UpdatedAt  time.Time                     `gorm:"column:updated_at"`
+	CreatedBy  string                        `gorm:"column:created_by"`
+	UpdatedBy  string                        `gorm:"column:updated_by"`
+
+	Source WebhookSubscriptionSource `gorm:"-"`
+
+	Name           string `gorm:"column:name"`
+	Url            string `gorm:"column:url"`
+	Authentication string `gorm:"column:authentication"` // the Authorization header of the requests
+	Secret         string `gorm:"column:secret"`         // the key of the request signature
+	Disabled       bool   `gorm:"column:disabled"`
+
+	// Preset is the name of a built-in payload template, Template is a custom text/template of the request body.
+	// If both are empty, the WebhookData JSON is sent. ContentType is only used for custom templates.
+	Preset      string `gorm:"column:preset"`
+	Template    string `gorm:"column:template"`
+	ContentType string `gorm:"column:content_type"`
+
+	Entities []string `gorm:"serializer:json;column:entities"`
+	Events   []string `gorm:"serializer:json;column:events"`
+	// Interfaces and Users restrict the subscription to events of the given interfaces or users. Events of entities
+	// that do not belong to an interface or a user, for example user events, do not match these filters.
+	Interfaces []InterfaceIdentifier `gorm:"serializer:json;column:interfaces"`
+	Users      []UserIdentifier      `gorm:"serializer:json;column:users"`
+}
+
+// NewWebhookSubscriptionIdentifier returns a new random subscription identifier.
+func NewWebhookSubscriptionIdentifier() WebhookSubscriptionIdentifier {
+	return WebhookSubscriptionIdentifier(uuid.New().String())
+}
+
+// Validate checks the URL and the filters of the subscription.
+func (s *WebhookSubscription) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.Join(errors.New("subscription name must not be empty"), ErrInvalidData)
+	}
+	u, err := url.Parse(s.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Join(fmt.Errorf("invalid subscription url %q", s.Url), ErrInvalidData)
+	}
+	if s.Preset != "" && !slices.Contains(WebhookPresets, s.Preset) {
+		return errors.Join(fmt.Errorf("unknown webhook preset %s", s.Preset), ErrInvalidData)
+	}
+	if s.Preset != "" && s.Template != "" {
+		return errors.Join(errors.New("a subscription can either use a preset or a template"), ErrInvalidData)
+	}
+	if s.ContentType != "" && s.Template == "" {
+		return errors.Join(errors.New("the content type can only be set for templates"), ErrInvalidData)
+	}
+	for _, entity := range s.Entities {
+		if !slices.Contains(WebhookEntities, entity) {
+			return errors.Join(fmt.Errorf("unknown webhook entity %s", entity), ErrInvalidData)
+		}
+	}
+	for _, event := range s.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return errors.Join(fmt.Errorf("unknown webhook event %s", event), ErrInvalidData)
+		}
+	}
+
+	return nil
+}
+
+// IsReadOnly returns true if the subscription is defined in the configuration file, it cannot be changed through
+// the API.
+func (s *WebhookSubscription) IsReadOnly() bool {
+	return s.Source == WebhookSubscriptionSourceConfig
+}
+
+// Matches returns true if the subscription receives the given event. The interface and user are the owners of the
+// entity, they are empty if the entity does not belong to an interface or a user.
+func (s *WebhookSubscription) Matches(
+	event, entity string,
+	interfaceId InterfaceIdentifier,
+	userId UserIdentifier,
+) bool {
+	switch {
+	case s.Disabled:
+		return false
+	case len(s.Entities) > 0 && !slices.Contains(s.Entities, entity):
+		return false
+	case len(s.Events) > 0 && !slices.Contains(s.Events, event):
+		return false
+	case len(s.Interfaces) > 0 && (interfaceId == "" || !slices.Contains(s.Interfaces, interfaceId)):
+		return false
+	case len(s.Users) > 0 && (userId == "" || !slices.Contains(s.Users, userId)):
+		return false
+	}
+
+	return true
+}
+
+type WebhookDeliveryIdentifier string
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"   // waiting for the first or the next attempt
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered" // the receiver accepted the webhook
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"    // all attempts failed, it is not retried anymore
+)
+
+// WebhookDeliverySortFields contains the sortable fields of the webhook delivery list.
+var WebhookDeliverySortFields = []string{"Identifier", "CreatedAt", "UpdatedAt", "Status", "Attempts"}
+
+// WebhookDelivery is a webhook request of the outbox. Deliveries are stored when the event occurs and sent by a
+// background worker, failed attempts are retried until the maximum number of attempts is reached.
+type WebhookDelivery struct {
+	// Identifier is a time ordered UUID, it is sent to the receiver to detect duplicate deliveries.
+	Identifier WebhookDeliveryIdentifier `gorm:"primaryKey;column:identifier"`
+	CreatedAt  time.Time                 `gorm:"index;column:created_at"`
+	UpdatedAt  time.Time                 `gorm:"column:updated_at"`
+
+	Subscription     WebhookSubscriptionIdentifier `gorm:"index;column:subscription"`
+	Event            string                        `gorm:"column:event"`
+	Entity           string                        `gorm:"column:entity"`
+	EntityIdentifier string                        `gorm:"column:entity_identifier"`
+	Url              string                        `gorm:"column:url"`
+	Payload          []byte                        `gorm:"column:payload"` // the request body
+	ContentType      string                        `gorm:"column:content_type"`
+
+	Status        WebhookDeliveryStatus `gorm:"index;column:status"`
+	Attempts      int                   `gorm:"column:attempts"`
+	NextAttemptAt *time.Time            `gorm:"index;column:next_attempt_at"`
+	LastAttemptAt *time.Time            `gorm:"column:last_attempt_at"`
+
+	// ResponseStatus and ResponseBody contain the response of the last attempt, the body is truncated.
+	ResponseStatus int    `gorm:"column:response_status"`
+	ResponseBody   string `gorm:"column:response_body"`
+	// LastError is the error of the last attempt, it is empty if the last attempt succeeded.
+	LastError string `gorm:"column:last_error"`
+}
+
+// NewWebhookDelivery creates a new pending delivery that is due immediately.
+func NewWebhookDelivery(
+	subscription WebhookSubscriptionIdentifier,
+	event, entity, entityIdentifier, url string,
+	payload []byte,
+	contentType string,
+) *WebhookDelivery {
+	now := time.Now()
+
+	return &WebhookDelivery{
+		Identifier:       WebhookDeliveryIdentifier(uuid.Must(uuid.NewV7()).String()),
+		Subscription:     subscription,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Event:            event,
+		Entity:           entity,
+		EntityIdentifier: entityIdentifier,
+		Url:              url,
+		Payload:          payload,
+		ContentType:      contentType,
+		Status:           WebhookDeliveryStatusPending,
+		NextAttemptAt:    &now,
+	}
+}
+
+// Requeue resets the delivery, so that it is sent again with the full number of attempts.
+func (d *WebhookDelivery) Requeue() {
+	now := time.Now()
+
+	d.Status = WebhookDeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = &now
+	d.UpdatedAt = now
+}
+
+// WebhookDeliveryFilter restricts the webhook deliveries of a list query. Empty fields are ignored.
+type WebhookDeliveryFilter struct {
+	Subscription WebhookSubscriptionIdentifier
+	Status       WebhookDeliveryStatus
+}
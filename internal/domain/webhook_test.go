@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSubscription_Matches(t *testing.T) {
+	all := WebhookSubscription{}
+	assert.True(t, all.Matches("create", "user", "", "alice"))
+
+	filtered := WebhookSubscription{
+		Entities:   []string{"peer", "peer_metric"},
+		Events:     []string{"connect"},
+		Interfaces: []InterfaceIdentifier{"wg0"},
+	}
+	assert.True(t, filtered.Matches("connect", "peer_metric", "wg0", "alice"))
+	assert.False(t, filtered.Matches("disconnect", "peer_metric", "wg0", "alice"))
+	assert.False(t, filtered.Matches("connect", "peer_metric", "wg1", "alice"))
+	assert.False(t, filtered.Matches("connect", "user", "", "alice"))
+
+	users := WebhookSubscription{Users: []UserIdentifier{"alice"}}
+	assert.True(t, users.Matches("update", "peer", "wg0", "alice"))
+	assert.False(t, users.Matches("update", "interface", "wg0", ""), "interfaces do not belong to a user")
+
+	disabled := WebhookSubscription{Disabled: true}
+	assert.False(t, disabled.Matches("create", "user", "", "alice"))
+}
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	assert.NoError(t, (&WebhookSubscription{Name: "hr", Url: "https://example.com/hook"}).Validate())
+	assert.ErrorIs(t, (&WebhookSubscription{Url: "https://example.com/hook"}).Validate(), ErrInvalidData)
+	assert.ErrorIs(t, (&WebhookSubscription{Name: "hr", Url: "example.com"}).Validate(), ErrInvalidData)
+	assert.ErrorIs(t, (&WebhookSubscription{Name: "hr", Url: "https://example.com", Entities: []string{"group"}}).
+		Validate(), ErrInvalidData)
+	assert.ErrorIs(t, (&WebhookSubscription{Name: "hr", Url: "https://example.com", Events: []string{"connected"}}).
+		Validate(), ErrInvalidData)
+}
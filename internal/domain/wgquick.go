@@ -0,0 +1,432 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pivpnClientCommentRegex matches the "### begin <name> ###" markers that PiVPN writes in front of each peer.
+var pivpnClientCommentRegex = regexp.MustCompile(`^###\s*begin\s+(.+?)\s*###$`)
+
+// WgQuickConfig is the parsed representation of a wg-quick configuration file.
+type WgQuickConfig struct {
+	Interface WgQuickInterface
+	Peers     []WgQuickPeer
+}
+
+// WgQuickInterface contains the values of the [Interface] section of a wg-quick configuration file.
+type WgQuickInterface struct {
+	PrivateKey   string
+	Addresses    []Cidr
+	ListenPort   int
+	Mtu          int
+	Dns          []string // dns server addresses
+	DnsSearch    []string // dns search domains (non-ip values of the DNS key)
+	FirewallMark uint32
+	RoutingTable string // the routing table number, "auto" or "off"
+	SaveConfig   bool
+
+	PreUp    string
+	PostUp   string
+	PreDown  string
+	PostDown string
+
+	AdvancedSecurity *AdvancedSecurity // only set if AmneziaWG keys are present
+}
+
+// WgQuickPeer contains the values of a [Peer] section of a wg-quick configuration file.
+type WgQuickPeer struct {
+	Name                string // optional name, extracted from well-known comments (friendly_name, PiVPN, WG Portal)
+	PublicKey           string
+	PresharedKey        string
+	AllowedIPs          []Cidr
+	Endpoint            string
+	PersistentKeepalive int
+}
+
+// WgQuickFile is a raw wg-quick configuration file.
+type WgQuickFile struct {
+	Name    string // the file name, for example wg0.conf, used to derive identifiers and display names
+	Content []byte
+}
+
+// WgQuickImportRequest describes a wg-quick server configuration and the client configurations that belong to it.
+type WgQuickImportRequest struct {
+	Identifier InterfaceIdentifier // optional, defaults to the name of the server configuration file
+	Backend    InterfaceBackend    // optional, defaults to the default backend
+	Server     WgQuickFile
+	Clients    []WgQuickFile
+}
+
+// WgQuickImportPlan is the result of a wg-quick import. It lists the interface and peers that will be (or have been)
+// created, conflicts that block the import and warnings that do not.
+type WgQuickImportPlan struct {
+	Interface *Interface
+	Peers     []Peer
+	Conflicts []string
+	Warnings  []string
+	Committed bool // true if the interface and peers have been stored
+}
+
+// HasConflicts returns true if the import cannot be committed.
+func (p WgQuickImportPlan) HasConflicts() bool {
+	return len(p.Conflicts) > 0
+}
+
+// PublicKey returns the public key that belongs to the private key of the interface section.
+func (c WgQuickConfig) PublicKey() string {
+	return PublicKeyFromPrivateKey(c.Interface.PrivateKey)
+}
+
+// ParseWgQuickConfig parses a configuration file in wg-quick format.
+// Keys are matched case-insensitively, comments and blank lines are ignored.
+// Keys that may occur multiple times (Address, DNS, AllowedIPs, hooks) are merged.
+func ParseWgQuickConfig(r io.Reader) (*WgQuickConfig, error) {
+	cfg := &WgQuickConfig{}
+
+	const (
+		sectionNone = iota
+		sectionInterface
+		sectionPeer
+	)
+
+	section := sectionNone
+	interfaceSeen := false
+	pendingName := ""
+	peerNamedInSection := false
+	var peer *WgQuickPeer
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // AmneziaWG I1-I5 values can be rather long
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "#") {
+			name, isPeerMarker := wgQuickCommentName(line)
+			switch {
+			case name == "":
+			case peer != nil && peer.PublicKey == "":
+				// comment inside the peer section, before the public key, takes priority over preceding comments
+				if !peerNamedInSection {
+					peer.Name = name
+					peerNamedInSection = true
+				}
+			case section == sectionPeer || isPeerMarker:
+				// comment in front of the next peer section; other comments within the interface section
+				// (like the display name written by WireGuard Portal) describe the interface
+				pendingName = name
+			}
+			continue
+		}
+		line = stripWgQuickComment(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			switch strings.ToLower(strings.TrimSpace(line[1 : len(line)-1])) {
+			case "interface":
+				if interfaceSeen {
+					return nil, fmt.Errorf("line %d: duplicate [Interface] section: %w", lineNo, ErrInvalidData)
+				}
+				interfaceSeen = true
+				section = sectionInterface
+				peer = nil
+				pendingName = ""
+			case "peer":
+				section = sectionPeer
+				cfg.Peers = append(cfg.Peers, WgQuickPeer{Name: pendingName})
+				peer = &cfg.Peers[len(cfg.Peers)-1]
+				peerNamedInSection = false
+				pendingName = ""
+			default:
+				return nil, fmt.Errorf("line %d: unknown section %s: %w", lineNo, line, ErrInvalidData)
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value: %w", lineNo, ErrInvalidData)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var err error
+		switch section {
+		case sectionInterface:
+			err = cfg.Interface.parseKey(key, value)
+		case sectionPeer:
+			err = peer.parseKey(key, value)
+		default:
+			err = fmt.Errorf("key %s outside of a section: %w", key, ErrInvalidData)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if !interfaceSeen {
+		return nil, fmt.Errorf("missing [Interface] section: %w", ErrInvalidData)
+	}
+	if cfg.Interface.PrivateKey == "" {
+		return nil, fmt.Errorf("missing PrivateKey in [Interface] section: %w", ErrInvalidData)
+	}
+	for i, p := range cfg.Peers {
+		if p.PublicKey == "" {
+			return nil, fmt.Errorf("missing PublicKey in [Peer] section %d: %w", i+1, ErrInvalidData)
+		}
+	}
+
+	return cfg, nil
+}
+
+// WgQuickNameFromFileName returns the interface or peer name for a given configuration file name,
+// for example /etc/wireguard/wg0.conf returns wg0.
+func WgQuickNameFromFileName(fileName string) string {
+	name := filepath.Base(fileName)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+func (i *WgQuickInterface) parseKey(key, value string) error {
+	var err error
+
+	switch key {
+	case "privatekey":
+		if !isValidWgKey(value) {
+			return fmt.Errorf("invalid private key: %w", ErrInvalidData)
+		}
+		i.PrivateKey = value
+	case "address":
+		addresses, err := ParseWgQuickCidrs(value)
+		if err != nil {
+			return fmt.Errorf("invalid address %q: %w", value, ErrInvalidData)
+		}
+		i.Addresses = append(i.Addresses, addresses...)
+	case "listenport":
+		i.ListenPort, err = parseWgQuickInt(key, value, 0, 65535)
+	case "mtu":
+		i.Mtu, err = parseWgQuickInt(key, value, 0, 9000)
+	case "dns":
+		for _, entry := range splitWgQuickList(value) {
+			if _, parseErr := netip.ParseAddr(entry); parseErr == nil {
+				i.Dns = append(i.Dns, entry)
+			} else {
+				i.DnsSearch = append(i.DnsSearch, entry)
+			}
+		}
+	case "fwmark":
+		if strings.ToLower(value) == "off" {
+			i.FirewallMark = 0
+			break
+		}
+		mark, parseErr := strconv.ParseUint(value, 0, 32)
+		if parseErr != nil {
+			return fmt.Errorf("invalid FwMark %q: %w", value, ErrInvalidData)
+		}
+		i.FirewallMark = uint32(mark)
+	case "table":
+		if strings.ToLower(value) == "auto" {
+			value = ""
+		}
+		i.RoutingTable = value
+	case "saveconfig":
+		i.SaveConfig, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid SaveConfig %q: %w", value, ErrInvalidData)
+		}
+	case "preup":
+		i.PreUp = joinWgQuickHook(i.PreUp, value)
+	case "postup":
+		i.PostUp = joinWgQuickHook(i.PostUp, value)
+	case "predown":
+		i.PreDown = joinWgQuickHook(i.PreDown, value)
+	case "postdown":
+		i.PostDown = joinWgQuickHook(i.PostDown, value)
+	case "jc", "jmin", "jmax", "s1", "s2", "s3", "s4", "h1", "h2", "h3", "h4", "i1", "i2", "i3", "i4", "i5":
+		if i.AdvancedSecurity == nil {
+			i.AdvancedSecurity = &AdvancedSecurity{}
+		}
+		err = i.AdvancedSecurity.parseKey(key, value)
+	default:
+		return fmt.Errorf("unknown key %s in [Interface] section: %w", key, ErrInvalidData)
+	}
+
+	return err
+}
+
+func (p *WgQuickPeer) parseKey(key, value string) error {
+	var err error
+
+	switch key {
+	case "publickey":
+		if !isValidWgKey(value) {
+			return fmt.Errorf("invalid public key: %w", ErrInvalidData)
+		}
+		p.PublicKey = value
+	case "presharedkey":
+		if !isValidWgKey(value) {
+			return fmt.Errorf("invalid preshared key: %w", ErrInvalidData)
+		}
+		p.PresharedKey = value
+	case "allowedips":
+		allowedIPs, err := ParseWgQuickCidrs(value)
+		if err != nil {
+			return fmt.Errorf("invalid allowed ips %q: %w", value, ErrInvalidData)
+		}
+		p.AllowedIPs = append(p.AllowedIPs, allowedIPs...)
+	case "endpoint":
+		p.Endpoint = value
+	case "persistentkeepalive":
+		if strings.ToLower(value) == "off" {
+			p.PersistentKeepalive = 0
+			break
+		}
+		p.PersistentKeepalive, err = parseWgQuickInt(key, value, 0, 65535)
+	default:
+		return fmt.Errorf("unknown key %s in [Peer] section: %w", key, ErrInvalidData)
+	}
+
+	return err
+}
+
+func (s *AdvancedSecurity) parseKey(key, value string) error {
+	if strings.HasPrefix(key, "h") || strings.HasPrefix(key, "i") {
+		if value == "" {
+			return fmt.Errorf("empty value for %s: %w", key, ErrInvalidData)
+		}
+	}
+
+	var err error
+	parseUint16 := func() uint16 {
+		var v int
+		v, err = parseWgQuickInt(key, value, 0, 65535)
+		return uint16(v)
+	}
+
+	switch key {
+	case "jc":
+		s.JunkPacketCount = parseUint16()
+	case "jmin":
+		s.JunkPacketMinSize = parseUint16()
+	case "jmax":
+		s.JunkPacketMaxSize = parseUint16()
+	case "s1":
+		s.InitPacketJunkSize = parseUint16()
+	case "s2":
+		s.ResponsePacketJunkSize = parseUint16()
+	case "s3":
+		s.CookieReplyPacketJunkSize = parseUint16()
+	case "s4":
+		s.TransportPacketJunkSize = parseUint16()
+	case "h1":
+		s.InitPacketMagicHeader = value
+	case "h2":
+		s.ResponsePacketMagicHeader = value
+	case "h3":
+		s.UnderloadPacketMagicHeader = value
+	case "h4":
+		s.TransportPacketMagicHeader = value
+	case "i1":
+		s.FirstSpecialJunkPacket = &value
+	case "i2":
+		s.SecondSpecialJunkPacket = &value
+	case "i3":
+		s.ThirdSpecialJunkPacket = &value
+	case "i4":
+		s.FourthSpecialJunkPacket = &value
+	case "i5":
+		s.FifthSpecialJunkPacket = &value
+	}
+
+	return err
+}
+
+// wgQuickCommentName extracts a name from well-known comment formats. The returned flag is set if the comment is
+// a marker that always refers to a peer (PiVPN), other comments may also describe the interface.
+func wgQuickCommentName(line string) (name string, isPeerMarker bool) {
+	if m := pivpnClientCommentRegex.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+
+	comment := strings.TrimSpace(strings.TrimLeft(line, "#"))
+	if key, value, found := strings.Cut(comment, "="); found && strings.TrimSpace(key) == "friendly_name" {
+		return strings.TrimSpace(value), false
+	}
+	if name, found := strings.CutPrefix(comment, "-WGP- Display name:"); found {
+		return strings.TrimSpace(name), false
+	}
+
+	return "", false
+}
+
+// stripWgQuickComment removes trailing comments from a line.
+func stripWgQuickComment(line string) string {
+	if idx := strings.Index(line, "#"); idx >= 0 {
+		line = line[:idx]
+	}
+	return strings.TrimSpace(line)
+}
+
+func splitWgQuickList(value string) []string {
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// ParseWgQuickCidrs parses a comma separated list of addresses. Like wg-quick, plain ip addresses without a
+// prefix length are accepted and treated as host addresses.
+func ParseWgQuickCidrs(value string) ([]Cidr, error) {
+	entries := splitWgQuickList(value)
+	cidrs := make([]Cidr, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, err
+			}
+			entry = netip.PrefixFrom(addr, addr.BitLen()).String()
+		}
+		cidr, err := CidrFromString(entry)
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
+}
+
+func joinWgQuickHook(existing, value string) string {
+	if existing == "" {
+		return value
+	}
+	return existing + "; " + value
+}
+
+func parseWgQuickInt(key, value string, minValue, maxValue int) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < minValue || v > maxValue {
+		return 0, fmt.Errorf("invalid value %q for %s: %w", value, key, ErrInvalidData)
+	}
+	return v, nil
+}
+
+func isValidWgKey(key string) bool {
+	return PublicKeyFromPrivateKey(key) != ""
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Biezax/wgctrl/wgtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWgKey(t *testing.T) wgtypes.Key {
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+	return key
+}
+
+func TestParseWgQuickConfig_ServerConfig(t *testing.T) {
+	serverKey := newTestWgKey(t)
+	peer1 := newTestWgKey(t).PublicKey()
+	peer2 := newTestWgKey(t).PublicKey()
+	psk := newTestWgKey(t)
+
+	raw := `# generated by hand
+[Interface]
+PrivateKey = ` + serverKey.String() + `
+Address = 10.0.0.1/24, fd00::1/64
+ListenPort = 51820
+MTU = 1380
+DNS = 1.1.1.1, example.com
+Table = auto
+FwMark = 0xca6c
+PostUp = iptables -A FORWARD -i %i -j ACCEPT
+PostUp = iptables -t nat -A POSTROUTING -o eth0 -j MASQUERADE
+Jc = 4
+H1 = 1234567
+
+### begin alice ###
+[Peer]
+PublicKey = ` + peer1.String() + `
+PresharedKey = ` + psk.String() + `
+AllowedIPs = 10.0.0.2/32, fd00::2
+### end alice ###
+
+[Peer]
+# friendly_name = bob
+publickey = ` + peer2.String() + ` # trailing comment
+allowedips = 10.0.0.3
+PersistentKeepalive = off
+`
+
+	cfg, err := ParseWgQuickConfig(strings.NewReader(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, serverKey.PublicKey().String(), cfg.PublicKey())
+	assert.Equal(t, "10.0.0.1/24,fd00::1/64", CidrsToString(cfg.Interface.Addresses))
+	assert.Equal(t, 51820, cfg.Interface.ListenPort)
+	assert.Equal(t, 1380, cfg.Interface.Mtu)
+	assert.Equal(t, []string{"1.1.1.1"}, cfg.Interface.Dns)
+	assert.Equal(t, []string{"example.com"}, cfg.Interface.DnsSearch)
+	assert.Equal(t, "", cfg.Interface.RoutingTable)
+	assert.Equal(t, uint32(0xca6c), cfg.Interface.FirewallMark)
+	assert.Equal(t, "iptables -A FORWARD -i %i -j ACCEPT; iptables -t nat -A POSTROUTING -o eth0 -j MASQUERADE",
+		cfg.Interface.PostUp)
+	require.NotNil(t, cfg.Interface.AdvancedSecurity)
+	assert.Equal(t, uint16(4), cfg.Interface.AdvancedSecurity.JunkPacketCount)
+	assert.Equal(t, "1234567", cfg.Interface.AdvancedSecurity.InitPacketMagicHeader)
+
+	require.Len(t, cfg.Peers, 2)
+	assert.Equal(t, "alice", cfg.Peers[0].Name)
+	assert.Equal(t, peer1.String(), cfg.Peers[0].PublicKey)
+	assert.Equal(t, psk.String(), cfg.Peers[0].PresharedKey)
+	assert.Equal(t, "10.0.0.2/32,fd00::2/128", CidrsToString(cfg.Peers[0].AllowedIPs))
+	assert.Equal(t, "bob", cfg.Peers[1].Name)
+	assert.Equal(t, peer2.String(), cfg.Peers[1].PublicKey)
+	assert.Equal(t, "10.0.0.3/32", CidrsToString(cfg.Peers[1].AllowedIPs))
+	assert.Equal(t, 0, cfg.Peers[1].PersistentKeepalive)
+}
+
+func TestParseWgQuickConfig_PeerNames(t *testing.T) {
+	serverKey := newTestWgKey(t)
+	alice := newTestWgKey(t).PublicKey()
+	bob := newTestWgKey(t).PublicKey()
+
+	// layout of the configuration files written by WireGuard Portal, with an additional header comment
+	raw := `# -WGP- Display name: My Server
+[Interface]
+# -WGP- Interface: wg0
+# -WGP- Display name: My Server
+PrivateKey = ` + serverKey.String() + `
+
+# friendly_name = not bob
+[Peer]
+# friendly_name = alice
+# -WGP- Display name: alice
+PublicKey = ` + alice.String() + `
+
+# friendly_name = bob
+[Peer]
+PublicKey = ` + bob.String() + `
+`
+
+	cfg, err := ParseWgQuickConfig(strings.NewReader(raw))
+	require.NoError(t, err)
+	require.Len(t, cfg.Peers, 2)
+	assert.Equal(t, "alice", cfg.Peers[0].Name)
+	assert.Equal(t, "bob", cfg.Peers[1].Name)
+}
+
+func TestParseWgQuickConfig_ClientConfig(t *testing.T) {
+	clientKey := newTestWgKey(t)
+	serverKey := newTestWgKey(t).PublicKey()
+
+	raw := `[Interface]
+PrivateKey = ` + clientKey.String() + `
+Address = 10.0.0.2/32
+
+[Peer]
+PublicKey = ` + serverKey.String() + `
+Endpoint = vpn.example.com:51820
+AllowedIPs = 0.0.0.0/0, ::/0
+PersistentKeepalive = 25
+`
+
+	cfg, err := ParseWgQuickConfig(strings.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, clientKey.PublicKey().String(), cfg.PublicKey())
+	require.Len(t, cfg.Peers, 1)
+	assert.Equal(t, "vpn.example.com:51820", cfg.Peers[0].Endpoint)
+	assert.Equal(t, 25, cfg.Peers[0].PersistentKeepalive)
+	assert.True(t, ContainsDefaultRoute(cfg.Peers[0].AllowedIPs))
+}
+
+func TestParseWgQuickConfig_Errors(t *testing.T) {
+	key := newTestWgKey(t).String()
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "empty", raw: ""},
+		{name: "missing private key", raw: "[Interface]\nAddress = 10.0.0.1/24\n"},
+		{name: "invalid private key", raw: "[Interface]\nPrivateKey = abc\n"},
+		{name: "unknown section", raw: "[Interface]\nPrivateKey = " + key + "\n[Foo]\n"},
+		{name: "unknown key", raw: "[Interface]\nPrivateKey = " + key + "\nFoo = bar\n"},
+		{name: "key outside section", raw: "PrivateKey = " + key + "\n"},
+		{name: "duplicate interface", raw: "[Interface]\nPrivateKey = " + key + "\n[Interface]\n"},
+		{name: "invalid port", raw: "[Interface]\nPrivateKey = " + key + "\nListenPort = 70000\n"},
+		{name: "peer without key", raw: "[Interface]\nPrivateKey = " + key + "\n[Peer]\nAllowedIPs = 10.0.0.2/32\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWgQuickConfig(strings.NewReader(tt.raw))
+			assert.ErrorIs(t, err, ErrInvalidData)
+		})
+	}
+}
+
+func TestWgQuickNameFromFileName(t *testing.T) {
+	assert.Equal(t, "wg0", WgQuickNameFromFileName("/etc/wireguard/wg0.conf"))
+	assert.Equal(t, "alice", WgQuickNameFromFileName("alice.conf"))
+}